@@ -0,0 +1,5839 @@
+// Code generated by cmd/tsclient; DO NOT EDIT.
+//
+// The typed client of the OpenSourceDUTH API. Regenerate it with
+// go generate ./cmd/tsclient after changing routes, handlers or their types.
+
+/** A query parameter's value; array values repeat the parameter */
+export type QueryValue = string | number | boolean;
+
+/** A path parameter's value, e.g. an ID */
+export type PathParam = string | number;
+
+/** Metadata of every response */
+export interface Metadata {
+  timestamp: string;
+  /** The API version, set by the /api/v0 routes */
+  version?: string;
+  requestId: string;
+  /** Set on paginated lists that have another page; pass it back as the cursor query parameter */
+  nextCursor?: string;
+}
+
+/** APIResponse is the envelope every JSON response comes in */
+export interface APIResponse<T> {
+  data: T;
+  errors: ErrorDetail[];
+  metadata: Metadata;
+}
+
+/** Where a server left on the default HOST and PORT is served, e.g. during development */
+export const DEFAULT_BASE_URL = "http://localhost:9237";
+
+export interface ClientOptions {
+  /**
+   * Where the API is served, e.g. https://api.example.org or DEFAULT_BASE_URL. The host
+   * picks the tenant.
+   */
+  baseUrl: string;
+  /** The API token sent as a bearer token, or a function returning it */
+  token?: string | (() => string | undefined | Promise<string | undefined>);
+  /**
+   * Whether to send the session cookie the session and admin routes need;
+   * "include" when the frontend is served from another origin
+   */
+  credentials?: RequestCredentials;
+  /** Headers sent with every request, e.g. Accept-Language */
+  headers?: Record<string, string>;
+  /** The fetch implementation, by default the global one */
+  fetch?: typeof fetch;
+}
+
+export interface RequestOptions {
+  /** Sent as Idempotency-Key, so that a retried write is applied once */
+  idempotencyKey?: string;
+  headers?: Record<string, string>;
+  signal?: AbortSignal;
+}
+
+/** ApiError is thrown for responses with an error status */
+export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly errors: ErrorDetail[],
+    readonly requestId: string | undefined,
+    readonly body: unknown,
+  ) {
+    super(errors[0]?.message ?? `request failed with status ${status}`);
+    this.name = "ApiError";
+  }
+
+  /** The first error's code, e.g. not_found */
+  get code(): string | undefined {
+    return this.errors[0]?.code;
+  }
+
+  static async from(response: Response): Promise<ApiError> {
+    let body: unknown;
+    try {
+      body = await response.json();
+    } catch {
+      body = undefined;
+    }
+    // Token and session checks answer with { code, error, requestId } rather than an APIResponse
+    const b = (body ?? {}) as Partial<APIResponse<unknown>> & { code?: string; error?: string; requestId?: string };
+    const errors: ErrorDetail[] = Array.isArray(b.errors)
+      ? b.errors
+      : b.code
+        ? [{ code: b.code, message: b.error ?? "", docsUrl: "" }]
+        : [];
+    const requestId = b.metadata?.requestId ?? b.requestId ?? response.headers.get("X-Request-ID") ?? undefined;
+    return new ApiError(response.status, errors, requestId, body);
+  }
+}
+
+/** AccessRequest is a user's request to use a feature that requires approval */
+export interface AccessRequest {
+  id: number;
+  userId: number;
+  email: string;
+  featureId: number;
+  featureSlug: string;
+  status: string;
+  reason?: string;
+  decidedBy?: number;
+  decidedAt?: string;
+  decisionNote?: string;
+  createdAt: string;
+}
+
+/** AccessRequestCreateRequest represents the request body for requesting access to a feature */
+export interface AccessRequestCreateRequest {
+  feature: string;
+  reason?: string | null;
+}
+
+/** AccessRequestDecisionRequest represents the request body for approving, denying or revoking access */
+export interface AccessRequestDecisionRequest {
+  note?: string | null;
+}
+
+/** AdminStats aggregates the numbers shown on the admin dashboard */
+export interface AdminStats {
+  users: UserStats;
+  signupsPerDay: DailyCount[];
+  tokens: TokenStats;
+  activeSessions: number;
+  groups: GroupCount[];
+  topFeatures: FeatureUsage[];
+}
+
+/** AdminWebhook is an admin-registered endpoint that receives auth events */
+export interface AdminWebhook {
+  id: number;
+  url: string;
+  events: string[];
+  format: string;
+  description?: string;
+  active: boolean;
+  createdBy?: number;
+  createdAt: string;
+}
+
+/** Alert is a low toner or paper alert of a station */
+export interface Alert {
+  id: number;
+  station_id: number;
+  station_code: string;
+  station_name: string;
+  /** toner or paper */
+  kind: string;
+  message: string;
+  raised_at: string;
+  resolved_at?: string;
+  acknowledged_by?: number;
+  acknowledged_at?: string;
+}
+
+export interface Announcement {
+  id: number;
+  type: string;
+  content: string;
+  content_en?: string;
+  starting_date: string;
+  ending_date: string;
+  is_current: boolean;
+  /**
+   * StartsAt and EndsAt are HH:MM times of day. An announcement runs from StartsAt on
+   * its starting date to EndsAt on its ending date, or, when RepeatOn lists weekdays,
+   * between those times on each of them, e.g. a "no dinner" notice every Sunday.
+   */
+  starts_at?: string;
+  ends_at?: string;
+  repeat_on?: string[];
+}
+
+export interface AnnouncementInput {
+  id?: number;
+  type: string;
+  content: string;
+  content_en?: string;
+  starting_date: string;
+  ending_date?: string;
+  is_current?: boolean;
+  /**
+   * StartsAt and EndsAt are HH:MM times of day. An announcement runs from StartsAt on
+   * its starting date to EndsAt on its ending date, or, when RepeatOn lists weekdays,
+   * between those times on each of them, e.g. a "no dinner" notice every Sunday.
+   */
+  starts_at?: string;
+  ends_at?: string;
+  repeat_on?: string[];
+}
+
+/**
+ * AnnouncementReceipt marks announcements as delivered to or seen by the calling user.
+ * DeviceID is optional and lets each of a user's devices keep its own state.
+ */
+export interface AnnouncementReceipt {
+  announcement_ids: number[];
+  device_id?: string;
+  status: string;
+}
+
+/**
+ * AnnouncementUpdate changes the fields that are set. An empty EndingDate makes the
+ * announcement open-ended, an empty ContentEn removes the translation, empty times
+ * remove them and an empty RepeatOn makes it a one-off. is_current follows the dates
+ * and times and cannot be set.
+ */
+export interface AnnouncementUpdate {
+  type?: string | null;
+  content?: string | null;
+  content_en?: string | null;
+  starting_date?: string | null;
+  ending_date?: string | null;
+  starts_at?: string | null;
+  ends_at?: string | null;
+  repeat_on?: string[] | null;
+}
+
+/** Booking is a user's booking of a court slot */
+export interface Booking {
+  id: number;
+  court_id: number;
+  court: string;
+  facility_id: number;
+  starts_at: string;
+  ends_at: string;
+  created_at: string;
+  cancelled_at?: string;
+}
+
+/** BookingRequest is the body of a new booking; the slot's length follows from the court */
+export interface BookingRequest {
+  court_id: number;
+  starts_at: string;
+}
+
+/** Category is a category of the feed with the number of its items */
+export interface Category {
+  name: string;
+  items: number;
+}
+
+/** Class is a class of the weekly program, held at a facility on a weekday */
+export interface Class {
+  id: number;
+  facility_id: number;
+  name: string;
+  name_en?: string;
+  instructor?: string;
+  day: string;
+  starts: string;
+  ends: string;
+  capacity?: number;
+}
+
+/** Class is a class of the weekly program, held at a facility on a weekday */
+export interface ClassInput {
+  id?: number;
+  facility_id: number;
+  name: string;
+  name_en?: string;
+  instructor?: string;
+  day: string;
+  starts: string;
+  ends: string;
+  capacity?: number | null;
+}
+
+/**
+ * Closure is a date or range of dates the rotation does not apply to, e.g. a public
+ * holiday. The cafeteria is closed unless a replacement menu is set.
+ */
+export interface Closure {
+  id: number;
+  starting_date: string;
+  /** starting_date when empty */
+  ending_date: string;
+  reason: string;
+  reason_en?: string;
+  /** The replacement menu as food IDs; none means closed */
+  lunch_dish_ids?: number[];
+  dinner_dish_ids?: number[];
+}
+
+/**
+ * Closure is a date or range of dates the rotation does not apply to, e.g. a public
+ * holiday. The cafeteria is closed unless a replacement menu is set.
+ */
+export interface ClosureInput {
+  id?: number;
+  starting_date: string;
+  /** starting_date when empty */
+  ending_date?: string;
+  reason?: string;
+  reason_en?: string;
+  /** The replacement menu as food IDs; none means closed */
+  lunch_dish_ids?: number[];
+  dinner_dish_ids?: number[];
+}
+
+/**
+ * Course is a course of a department's curriculum. Instructors are listed in the order
+ * the catalog gives them.
+ */
+export interface Course {
+  id: number;
+  department_id: number;
+  department: string;
+  code: string;
+  title: string;
+  title_en?: string;
+  semester: number;
+  ects: number;
+  instructors: string[];
+}
+
+/**
+ * Course is a course of a department's curriculum. Instructors are listed in the order
+ * the catalog gives them.
+ */
+export interface CourseInput {
+  id?: number;
+  department_id: number;
+  department?: string;
+  code: string;
+  title: string;
+  title_en?: string;
+  semester: number;
+  ects?: number;
+  instructors: string[];
+}
+
+/** CourseWorkspace groups the time-boxed tokens an instructor issues to a cohort */
+export interface CourseWorkspace {
+  id: number;
+  name: string;
+  instructorId: number;
+  endsAt: string;
+  createdAt: string;
+  features: Feature[];
+  memberCount: number;
+}
+
+/** Department publishes a course catalog */
+export interface CoursesDepartment {
+  id: number;
+  code: string;
+  name: string;
+  name_en?: string;
+}
+
+/** Department publishes a course catalog */
+export interface CoursesDepartmentInput {
+  id?: number;
+  code: string;
+  name: string;
+  name_en?: string;
+}
+
+/** Court is a court users can book in slots of SlotMinutes */
+export interface Court {
+  id: number;
+  facility_id: number;
+  name: string;
+  name_en?: string;
+  sport: string;
+  slot_minutes: number;
+  /** Slots lists the slots of the day asked for, in availability listings */
+  slots?: Slot[];
+}
+
+/** Court is a court users can book in slots of SlotMinutes */
+export interface CourtInput {
+  id?: number;
+  facility_id: number;
+  name: string;
+  name_en?: string;
+  sport: string;
+  slot_minutes: number;
+  /** Slots lists the slots of the day asked for, in availability listings */
+  slots?: SlotInput[];
+}
+
+/** DailyCount is a count bucketed by calendar day (YYYY-MM-DD) */
+export interface DailyCount {
+  date: string;
+  count: number;
+}
+
+export interface DateSchedule {
+  lunch: Food[];
+  dinner: Food[];
+  /**
+   * Closure is set when one covers the date; Lunch and Dinner then hold its
+   * replacement menu, empty when the cafeteria is closed
+   */
+  closure?: Closure;
+  /** Hours are when the restaurants serve the meals on the date; none when closed */
+  hours?: MealHours[];
+}
+
+/** DaySchedule is the menu of one calendar date in a range */
+export interface DaySchedule {
+  date: string;
+  lunch: Food[];
+  dinner: Food[];
+  /**
+   * Closure is set when one covers the date; Lunch and Dinner then hold its
+   * replacement menu, empty when the cafeteria is closed
+   */
+  closure?: Closure;
+  /** Hours are when the restaurants serve the meals on the date; none when closed */
+  hours?: MealHours[];
+}
+
+/**
+ * Deadline is an upcoming activity the account has to act on, e.g. an assignment to
+ * submit or a quiz to take
+ */
+export interface Deadline {
+  id: number;
+  name: string;
+  /** The activity type, e.g. assign or quiz */
+  module: string;
+  course_id: number;
+  course: string;
+  due_at: string;
+  url: string;
+}
+
+/** DecisionRequest is the optional body of an approval or rejection */
+export interface DecisionRequest {
+  note?: string;
+}
+
+/** Definition documents an error code */
+export interface Definition {
+  code: string;
+  status: number;
+  description: string;
+  docsUrl: string;
+}
+
+/** Delivery is an event sent, or still to be sent, to a subscription */
+export interface Delivery {
+  id: number;
+  event: string;
+  data: unknown;
+  /** pending, delivered or failed */
+  status: string;
+  attempts: number;
+  last_error?: string;
+  /** while pending */
+  next_attempt_at?: string;
+  delivered_at?: string;
+  failed_at?: string;
+  created_at: string;
+}
+
+/** DenialDiagnostic explains why a request was answered with 401/403/429 */
+export interface DenialDiagnostic {
+  requestId: string;
+  timestamp: string;
+  status: number;
+  reason: string;
+  method: string;
+  path: string;
+  clientIp: string;
+  featureSlug: string;
+  tokenId?: number;
+  userId?: number;
+  checks: DiagnosticCheck[];
+  quota?: QuotaDiagnostic;
+}
+
+export interface DepartmentImport {
+  code: string;
+  name: string;
+  name_en?: string;
+  staff?: StaffImport[];
+}
+
+/** DeprecatedFeatureToken is an active token still scoped to deprecated features */
+export interface DeprecatedFeatureToken {
+  tokenId: number;
+  label: string;
+  userId: number;
+  email: string;
+  adminCreated: boolean;
+  expiresAt?: string;
+  createdAt: string;
+  /** Only the deprecated ones */
+  features: Feature[];
+}
+
+/** DiagnosticCheck is one step of the token middleware's decision chain */
+export interface DiagnosticCheck {
+  name: string;
+  passed: boolean;
+  detail?: string;
+}
+
+/** Department belongs to a school and has staff */
+export interface DirectoryDepartment {
+  id: number;
+  code: string;
+  name: string;
+  name_en?: string;
+}
+
+/**
+ * DirectoryImport is the whole directory as the university publishes it. Importing it
+ * replaces the stored directory: schools and departments are matched by code and staff
+ * by email, or by name when they have none, so their IDs stay the same across imports.
+ */
+export interface DirectoryImport {
+  schools: SchoolImport[];
+}
+
+/** ImportResult reports what an import changed, or would change on a dry run */
+export interface DirectoryImportResult {
+  dry_run: boolean;
+  schools: ImportCounts;
+  departments: ImportCounts;
+  staff: ImportCounts;
+}
+
+/** DishStats is how often a dish was served in a period */
+export interface DishStats {
+  food: Food;
+  /** meals it was served at */
+  appearances: number;
+  /** average appearances per week of the period */
+  per_week: number;
+  last_served: string;
+  /** days from last_served to the end of the period */
+  days_since: number;
+}
+
+/** Document is a file posted to one of the account's courses */
+export interface EclassDocument {
+  id: number;
+  course_id: number;
+  course: string;
+  name: string;
+  /** The document's page on eClass, where the user signs in to download it */
+  url: string;
+  modified_at: string;
+}
+
+/** Employer is who offers a job */
+export interface Employer {
+  name: string;
+  website?: string;
+  description?: string;
+}
+
+/** EmployerRequest is the employer of a JobRequest */
+export interface EmployerRequest {
+  name: string;
+  website?: string;
+  description?: string;
+}
+
+/** EnrolledCourse is an eClass course the linked account is enrolled in */
+export interface EnrolledCourse {
+  id: number;
+  short_name: string;
+  full_name: string;
+  url: string;
+}
+
+/** Error is an error as it appears in APIResponse.errors */
+export interface ErrorDetail {
+  code: string;
+  message: string;
+  /** JSON name of the offending body field */
+  field?: string;
+  docsUrl: string;
+}
+
+/** ErrorEntry is a request that failed on the server's side */
+export interface ErrorEntry {
+  requestId: string;
+  timestamp: string;
+  status: number;
+  method: string;
+  path: string;
+  route: string;
+  clientIp: string;
+  userId?: number;
+  tokenId?: number;
+  latencyMs: number;
+  error: string;
+  /** Only for panics */
+  stack?: string;
+}
+
+/** EventRequest is the body admins and submitters send to add or replace an event */
+export interface EventRequest {
+  title: string;
+  title_en?: string;
+  description?: string;
+  category: string;
+  venue: string;
+  organizer: string;
+  url?: string;
+  starts_at: string;
+  ends_at: string;
+}
+
+/**
+ * EventType is an event token holders can subscribe to and the feature their token
+ * needs to receive it
+ */
+export interface EventType {
+  type: string;
+  feature: string;
+  description: string;
+}
+
+/**
+ * Event is a talk, career day, student event or the like. Submitted events are pending
+ * until an admin approves them; events added by admins are approved right away.
+ */
+export interface EventsEvent {
+  id: number;
+  title: string;
+  title_en?: string;
+  description?: string;
+  category: string;
+  venue: string;
+  organizer: string;
+  url?: string;
+  starts_at: string;
+  ends_at: string;
+  /** Approval details, left out of public listings */
+  status?: string;
+  submitted_by?: number;
+  decided_by?: number;
+  decided_at?: string;
+  decision_note?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+/** Facility is a sports facility with its opening hours */
+export interface Facility {
+  id: number;
+  code: string;
+  name: string;
+  name_en?: string;
+  kind: string;
+  location?: string;
+  hours: OpeningHours[];
+  /** OpenNow is computed for responses */
+  open_now?: boolean;
+}
+
+/** Facility is a sports facility with its opening hours */
+export interface FacilityInput {
+  id?: number;
+  code: string;
+  name: string;
+  name_en?: string;
+  kind: string;
+  location?: string;
+  hours?: OpeningHoursInput[];
+  /** OpenNow is computed for responses */
+  open_now?: boolean | null;
+}
+
+/**
+ * Favorite is a user's favorite food with when it is served next, nil when it is not
+ * within the look-ahead
+ */
+export interface Favorite {
+  food: Food;
+  next: Serving | null;
+}
+
+/** Feature represents an API feature (hierarchical) */
+export interface Feature {
+  id: number;
+  slug: string;
+  name: string;
+  parentId?: number;
+  adminOnly: boolean;
+  /** Users need an approved access request before their tokens can use it */
+  approvalRequired: boolean;
+  /** Highest response format version; tokens pin one with "slug@vN" and use v1 otherwise */
+  latestVersion: number;
+  /**
+   * Identical GET requests from one token within this many milliseconds are
+   * served from a single execution and charged once (0 disables)
+   */
+  dedupWindowMs: number;
+  deprecated: boolean;
+  /** When the feature will be retired */
+  sunsetAt?: string;
+  description?: string;
+  docsUrl?: string;
+  /** e.g. an example request */
+  example?: string;
+  createdAt: string;
+  children?: (Feature | null)[];
+}
+
+/** FeatureCreateRequest represents the request body for creating a feature */
+export interface FeatureCreateRequest {
+  slug: string;
+  name: string;
+  parentId?: number | null;
+  adminOnly?: boolean;
+  approvalRequired?: boolean;
+  description?: string | null;
+  docsUrl?: string | null;
+  example?: string | null;
+}
+
+/** FeaturePermission is a feature the user can reach with the limit that applies to them */
+export interface FeaturePermission {
+  id: number;
+  slug: string;
+  name: string;
+  parentId?: number;
+  adminOnly: boolean;
+  approvalRequired: boolean;
+  /** Can be added to the user's own tokens */
+  assignable: boolean;
+  /** NULL = uncapped */
+  rpmLimit: number | null;
+}
+
+/** FeatureUpdateRequest represents the request body for updating a feature */
+export interface FeatureUpdateRequest {
+  name?: string | null;
+  parentId?: number | null;
+  adminOnly?: boolean | null;
+  approvalRequired?: boolean | null;
+  dedupWindowMs?: number | null;
+  deprecated?: boolean | null;
+  /** Implies deprecated unless it is explicitly false */
+  sunsetAt?: string | null;
+  description?: string | null;
+  docsUrl?: string | null;
+  example?: string | null;
+}
+
+/** FeatureUsage ranks a feature by current traffic and token grants */
+export interface FeatureUsage {
+  featureId: number;
+  slug: string;
+  requestsLastMinute: number;
+  activeTokens: number;
+}
+
+/** FeatureUsageLeaderboard lists the heaviest users and tokens of a feature */
+export interface FeatureUsageLeaderboard {
+  featureId: number;
+  since: string;
+  total: number;
+  topUsers: UserRequestCount[];
+  topTokens: TokenRequestCount[];
+}
+
+/** FetchResult reports what fetching a source found */
+export interface FetchResult {
+  source: string;
+  not_modified?: boolean;
+  /** in the feed */
+  items: number;
+  /** new items */
+  added: number;
+  /** items whose title, summary or link changed */
+  updated: number;
+  error?: string;
+}
+
+export interface Food {
+  id: number;
+  name: string;
+  name_en?: string;
+  nutrition?: Nutrition;
+  allergens: string[];
+  tags: string[];
+  image?: FoodImage;
+  rating?: Rating;
+}
+
+/** FoodImage holds the URLs of a food image's variants */
+export interface FoodImage {
+  thumb: string;
+  medium: string;
+  large: string;
+}
+
+/** FoodImage holds the URLs of a food image's variants */
+export interface FoodImageInput {
+  thumb?: string;
+  medium?: string;
+  large?: string;
+}
+
+export interface FoodInput {
+  id?: number;
+  name: string;
+  name_en?: string;
+  nutrition?: NutritionInput | null;
+  allergens?: string[];
+  tags?: string[];
+  image?: FoodImageInput | null;
+  rating?: RatingInput | null;
+}
+
+/**
+ * FoodUpdate changes the fields that are set. Nutrition, Allergens and Tags replace the
+ * whole previous value; an empty NameEn removes the translation.
+ */
+export interface FoodUpdate {
+  name?: string | null;
+  name_en?: string | null;
+  nutrition?: NutritionInput | null;
+  allergens?: string[] | null;
+  tags?: string[] | null;
+}
+
+/** Group represents a quota tier */
+export interface Group {
+  id: number;
+  name: string;
+  defaultRpm: number;
+  /** 0 means unlimited */
+  maxSessions: number;
+  description: string | null;
+  createdAt: string;
+  /** set while the group is deleted */
+  deletedAt?: string;
+}
+
+/** GroupCount is the number of users in a group */
+export interface GroupCount {
+  groupId: number;
+  name: string;
+  userCount: number;
+}
+
+/** GroupCreateRequest represents the request body for creating a group */
+export interface GroupCreateRequest {
+  name: string;
+  defaultRpm: number;
+  maxSessions?: number;
+  description?: string | null;
+}
+
+/** GroupFeatureQuota defines the default RPM for a group on a feature */
+export interface GroupFeatureQuota {
+  groupId: number;
+  featureId: number;
+  /** NULL = uncapped */
+  rpmLimit: number | null;
+}
+
+/** GroupUpdateRequest represents the request body for updating a group */
+export interface GroupUpdateRequest {
+  name?: string | null;
+  defaultRpm?: number | null;
+  maxSessions?: number | null;
+  description?: string | null;
+}
+
+/** ImportCounts is how many rows of a kind an import created, updated and removed */
+export interface ImportCounts {
+  created: number;
+  updated: number;
+  removed: number;
+}
+
+/** Invitation pre-assigns a group and role to an email before its first login */
+export interface Invitation {
+  id: number;
+  email: string;
+  groupId: number;
+  role: string;
+  invitedBy?: number;
+  expiresAt?: string;
+  acceptedAt?: string;
+  createdAt: string;
+}
+
+/** InvitationCreateRequest represents the request body for inviting an email */
+export interface InvitationCreateRequest {
+  email: string;
+  groupId: number;
+  role?: string;
+  expiresAt?: string | null;
+}
+
+/** IssuedWorkspaceToken is a student token returned once when a student is enrolled */
+export interface IssuedWorkspaceToken {
+  userId: number;
+  email: string;
+  token: string;
+}
+
+/** Item is a news post of one of the sources */
+export interface Item {
+  id: number;
+  /** slug */
+  source: string;
+  title: string;
+  summary?: string;
+  url: string;
+  category?: string;
+  published_at: string;
+}
+
+/**
+ * Job is a part-time or student position. Jobs submitted by users are listed once an
+ * admin approves them; jobs added by admins are approved right away.
+ */
+export interface Job {
+  id: number;
+  title: string;
+  description: string;
+  type: string;
+  location: string;
+  remote: boolean;
+  hours_per_week?: number;
+  pay?: string;
+  employer: Employer;
+  contact_email: string;
+  apply_url?: string;
+  expires_at: string;
+  /** Approval details, left out of public listings */
+  submitted_by?: number;
+  status?: string;
+  decided_by?: number;
+  decided_at?: string;
+  decision_note?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+/**
+ * JobRequest is the body of a job submission, or of a job added or replaced by an
+ * admin. Without expires_at, a job expires DefaultLifetime after it is sent.
+ */
+export interface JobRequest {
+  title: string;
+  description: string;
+  type: string;
+  location: string;
+  remote?: boolean;
+  hours_per_week?: number | null;
+  pay?: string;
+  employer: EmployerRequest;
+  contact_email: string;
+  apply_url?: string;
+  expires_at?: string | null;
+}
+
+/** Library is a library branch with its opening hours and seat occupancy */
+export interface Library {
+  id: number;
+  code: string;
+  name: string;
+  name_en?: string;
+  seats: number;
+  hours: OpeningHours[];
+  /** Occupancy and OpenNow are computed for responses */
+  occupancy?: Occupancy;
+  open_now?: boolean;
+}
+
+/** Library is a library branch with its opening hours and seat occupancy */
+export interface LibraryInput {
+  id?: number;
+  code: string;
+  name: string;
+  name_en?: string;
+  seats?: number;
+  hours?: OpeningHoursInput[];
+  /** Occupancy and OpenNow are computed for responses */
+  occupancy?: OccupancyInput | null;
+  open_now?: boolean | null;
+}
+
+/**
+ * Link is the eClass account a user linked to the API. The web service token eClass
+ * issued for it is only used to call eClass on the user's behalf and never returned.
+ */
+export interface Link {
+  site_user_id: number;
+  username: string;
+  full_name: string;
+  consented_at: string;
+  last_used_at?: string;
+}
+
+/**
+ * LinkRequest is the body of PUT /eclass/link. Either the account's username and
+ * password, which are exchanged for a web service token and not stored, or a key
+ * copied from the eClass security keys page, for accounts that sign in through the
+ * institution. consent must be true: the user agrees to the API reading their eClass
+ * courses, documents and deadlines until they unlink the account.
+ */
+export interface LinkRequest {
+  username?: string;
+  password?: string;
+  key?: string;
+  consent?: boolean;
+}
+
+/** MealHours is when a restaurant serves a meal on a particular date */
+export interface MealHours {
+  restaurant_id: number;
+  restaurant: string;
+  restaurant_en?: string;
+  meal_type: string;
+  opens: string;
+  closes: string;
+}
+
+/**
+ * MenuChange lists the dishes edits added to and removed from a meal of a date.
+ * ChangedAt is when the latest of those edits was made.
+ */
+export interface MenuChange {
+  date: string;
+  meal_type: string;
+  added: Food[];
+  removed: Food[];
+  changed_at: string;
+}
+
+/**
+ * MenuChannel is a chat channel the day's menu and announcements are posted to every
+ * day. BotToken is never returned.
+ */
+export interface MenuChannel {
+  id: number;
+  kind: string;
+  /** Discord webhook URL, or Telegram chat ID or @channel */
+  target: string;
+  bot_token?: string;
+  language: string;
+  post_at: string;
+  /** text/template; empty uses the default */
+  template: string;
+  active: boolean;
+  last_posted_on?: string;
+  last_error?: string;
+  created_at: string;
+}
+
+/**
+ * MenuChannel is a chat channel the day's menu and announcements are posted to every
+ * day. BotToken is never returned.
+ */
+export interface MenuChannelInput {
+  id?: number;
+  kind: string;
+  /** Discord webhook URL, or Telegram chat ID or @channel */
+  target: string;
+  bot_token?: string;
+  language?: string;
+  post_at?: string;
+  /** text/template; empty uses the default */
+  template?: string;
+  active?: boolean;
+  last_posted_on?: string;
+  last_error?: string;
+  created_at?: string;
+}
+
+/**
+ * MenuChannelUpdate changes the fields that are set. An empty Template restores the
+ * default one and an empty BotToken keeps the current one.
+ */
+export interface MenuChannelUpdate {
+  target?: string | null;
+  bot_token?: string | null;
+  language?: string | null;
+  post_at?: string | null;
+  template?: string | null;
+  active?: boolean | null;
+}
+
+/**
+ * MenuDraft is a menu recognized from a scan, for an admin to correct and confirm with
+ * POST /admin/schedule/draft. Lines that could not be placed in a meal slot are listed
+ * in Unassigned.
+ */
+export interface MenuDraft {
+  starting_date: string;
+  ending_date: string;
+  is_current: boolean;
+  items: SpreadsheetItem[];
+  /** English names, keyed by the Greek ones */
+  names_en?: Record<string, string>;
+  unassigned?: string[];
+  /** what the OCR recognized */
+  text?: string;
+}
+
+/**
+ * MenuDraft is a menu recognized from a scan, for an admin to correct and confirm with
+ * POST /admin/schedule/draft. Lines that could not be placed in a meal slot are listed
+ * in Unassigned.
+ */
+export interface MenuDraftInput {
+  starting_date: string;
+  ending_date?: string;
+  is_current?: boolean;
+  items: SpreadsheetItemInput[];
+  /** English names, keyed by the Greek ones */
+  names_en?: Record<string, string>;
+  unassigned?: string[];
+  /** what the OCR recognized */
+  text?: string;
+}
+
+/** MenuStats aggregates the menus served from From to To inclusive */
+export interface MenuStats {
+  from: string;
+  to: string;
+  /** dates with at least one dish */
+  days_served: number;
+  dishes: DishStats[];
+}
+
+/** Nutrition is per serving; unknown values are null */
+export interface Nutrition {
+  /** kcal */
+  calories: number | null;
+  /** grams */
+  protein: number | null;
+  /** grams */
+  carbohydrates: number | null;
+  /** grams */
+  fat: number | null;
+}
+
+/** Nutrition is per serving; unknown values are null */
+export interface NutritionInput {
+  /** kcal */
+  calories?: number | null;
+  /** grams */
+  protein?: number | null;
+  /** grams */
+  carbohydrates?: number | null;
+  /** grams */
+  fat?: number | null;
+}
+
+/** Occupancy is how many seats of a library were taken at the last report */
+export interface Occupancy {
+  occupied: number;
+  free: number;
+  percent: number;
+  updated_at: string;
+}
+
+/** Occupancy is how many seats of a library were taken at the last report */
+export interface OccupancyInput {
+  occupied?: number;
+  free?: number;
+  percent?: number;
+  updated_at?: string;
+}
+
+/** OccupancyReport is what the gate counters send to the ingest endpoint */
+export interface OccupancyReport {
+  occupied: number | null;
+}
+
+/** OpeningHours is when a place is open on a weekday, as HH:MM in Location */
+export interface OpeningHours {
+  day: string;
+  opens: string;
+  closes: string;
+}
+
+/** OpeningHours is when a place is open on a weekday, as HH:MM in Location */
+export interface OpeningHoursInput {
+  day: string;
+  opens: string;
+  closes: string;
+}
+
+/** POI is a point of interest on the campus map. Hours are empty when they are not known. */
+export interface POI {
+  id: number;
+  name: string;
+  name_en?: string;
+  category: string;
+  description?: string;
+  latitude: number;
+  longitude: number;
+  hours: OpeningHours[];
+  /** OpenNow and Distance are computed for responses */
+  open_now?: boolean;
+  /** meters, in "near me" results */
+  distance?: number;
+}
+
+/** POI is a point of interest on the campus map. Hours are empty when they are not known. */
+export interface POIInput {
+  id?: number;
+  name: string;
+  name_en?: string;
+  category: string;
+  description?: string;
+  latitude?: number;
+  longitude?: number;
+  hours?: OpeningHoursInput[];
+  /** OpenNow and Distance are computed for responses */
+  open_now?: boolean | null;
+  /** meters, in "near me" results */
+  distance?: number | null;
+}
+
+/** Period is a span of time a room is reserved for */
+export interface Period {
+  starts_at: string;
+  ends_at: string;
+}
+
+/** Period is a span of time a room is reserved for */
+export interface PeriodInput {
+  starts_at?: string;
+  ends_at?: string;
+}
+
+/** PersonalizedDay is a date's menu checked against the preferences it was personalized for */
+export interface PersonalizedDay {
+  date: string;
+  lunch: PersonalizedFood[];
+  dinner: PersonalizedFood[];
+  closure?: Closure;
+  preferences: Preferences | null;
+}
+
+/**
+ * PersonalizedFood is a food checked against a user's dietary preferences. Suitable is set
+ * when it has none of the allergens they avoid and fits every diet they follow.
+ */
+export interface PersonalizedFood {
+  id: number;
+  name: string;
+  name_en?: string;
+  nutrition?: Nutrition;
+  allergens: string[];
+  tags: string[];
+  image?: FoodImage;
+  rating?: Rating;
+  suitable: boolean;
+  avoided_allergens?: string[];
+  unmet_diets?: string[];
+}
+
+/**
+ * Posting is a thesis topic or an internship opening. Postings are written in the
+ * language their department publishes them in, so they have no translations. A data
+ * editor's postings are listed once an admin approves them; admins' own postings are
+ * approved right away.
+ */
+export interface Posting {
+  id: number;
+  kind: string;
+  title: string;
+  description: string;
+  department: string;
+  supervisor?: string;
+  /** the host of an internship */
+  organization?: string;
+  location?: string;
+  contact_email?: string;
+  url?: string;
+  positions?: number;
+  tags: string[];
+  /** YYYY-MM-DD, the last day to apply */
+  deadline?: string;
+  expires_at: string;
+  /** CreatedBy and the approval details are shown to editors only */
+  created_by?: number;
+  status?: string;
+  decided_by?: number;
+  decided_at?: string;
+  decision_note?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+/**
+ * PostingRequest is the body editors send to publish or replace a posting. Without
+ * expires_at, a posting expires after its deadline, or DefaultLifetime after it is
+ * published when it has none.
+ */
+export interface PostingRequest {
+  kind: string;
+  title: string;
+  description: string;
+  department: string;
+  supervisor?: string;
+  organization?: string;
+  location?: string;
+  contact_email?: string;
+  url?: string;
+  positions?: number | null;
+  tags?: string[];
+  deadline?: string;
+  expires_at?: string | null;
+}
+
+/**
+ * Preferences are a user's dietary preferences, which personalize cafeteria menus. The
+ * values match the food tags and allergens of the schedule module.
+ */
+export interface Preferences {
+  diets: string[];
+  allergens: string[];
+}
+
+/**
+ * Preferences are a user's dietary preferences, which personalize cafeteria menus. The
+ * values match the food tags and allergens of the schedule module.
+ */
+export interface PreferencesInput {
+  diets: string[];
+  allergens: string[];
+}
+
+/** QuotaDiagnostic captures the numbers behind a rate limit decision */
+export interface QuotaDiagnostic {
+  effectiveRpm: number;
+  currentRpm: number;
+  windowSeconds: number;
+}
+
+/** QuotaEntry represents a single quota setting */
+export interface QuotaEntry {
+  featureId: number;
+  /** NULL = uncapped */
+  rpmLimit?: number | null;
+}
+
+/** QuotaSetRequest represents the request body for setting quotas */
+export interface QuotaSetRequest {
+  quotas: QuotaEntry[];
+}
+
+/** Rating is the average of a food's ratings; foods nobody rated have none */
+export interface Rating {
+  average: number;
+  count: number;
+}
+
+/** Rating is the average of a food's ratings; foods nobody rated have none */
+export interface RatingInput {
+  average?: number;
+  count?: number;
+}
+
+/** Reservation is a user's booking of a study room */
+export interface Reservation {
+  id: number;
+  room_id: number;
+  room: string;
+  library_id: number;
+  starts_at: string;
+  ends_at: string;
+  created_at: string;
+  cancelled_at?: string;
+}
+
+/** ReservationRequest is the body of a new reservation */
+export interface ReservationRequest {
+  room_id: number;
+  starts_at: string;
+  ends_at: string;
+}
+
+/** Restaurant is a place the cafeteria serves in, with its serving hours */
+export interface Restaurant {
+  id: number;
+  name: string;
+  name_en?: string;
+  hours: ServiceHours[];
+}
+
+/** Restaurant is a place the cafeteria serves in, with its serving hours */
+export interface RestaurantInput {
+  id?: number;
+  name: string;
+  name_en?: string;
+  hours?: ServiceHoursInput[];
+}
+
+/**
+ * Review is a user's rating of a food, with an optional short text. UserID and Hidden are
+ * only shown to admins.
+ */
+export interface Review {
+  id: number;
+  food_id: number;
+  user_id?: number;
+  rating: number;
+  review: string;
+  hidden?: boolean;
+  created_at: string;
+  updated_at: string;
+}
+
+/**
+ * Review is a user's rating of a food, with an optional short text. UserID and Hidden are
+ * only shown to admins.
+ */
+export interface ReviewInput {
+  id?: number;
+  food_id?: number;
+  user_id?: number;
+  rating: number;
+  review?: string;
+  hidden?: boolean;
+  created_at?: string;
+  updated_at?: string;
+}
+
+/** ReviewModeration hides or shows a review */
+export interface ReviewModeration {
+  hidden: boolean | null;
+}
+
+/** Room is a study room users can reserve */
+export interface Room {
+  id: number;
+  library_id: number;
+  name: string;
+  name_en?: string;
+  capacity: number;
+  /** Booked lists the reserved periods of the day asked for, in availability listings */
+  booked?: Period[];
+}
+
+/** Room is a study room users can reserve */
+export interface RoomInput {
+  id?: number;
+  library_id: number;
+  name: string;
+  name_en?: string;
+  capacity: number;
+  /** Booked lists the reserved periods of the day asked for, in availability listings */
+  booked?: PeriodInput[];
+}
+
+/** SampledRequest is a request recorded by a sampling rule, with its secrets redacted */
+export interface SampledRequest {
+  requestId: string;
+  ruleId: number;
+  timestamp: string;
+  method: string;
+  path: string;
+  query?: string;
+  route: string;
+  feature?: string;
+  userId?: number;
+  tokenId?: number;
+  status: number;
+  latencyMs: number;
+  requestHeaders: Record<string, string>;
+  requestBody?: string;
+  responseHeaders: Record<string, string>;
+  responseBody?: string;
+  /** A body was longer than SamplingMaxBody */
+  truncated: boolean;
+}
+
+/**
+ * SamplingRule records a share of the requests to a feature, of a user, or of a user to a
+ * feature, until it expires
+ */
+export interface SamplingRule {
+  id: number;
+  feature?: string;
+  userId?: number;
+  rate: number;
+  createdBy?: number;
+  createdAt: string;
+  expiresAt: string;
+}
+
+/** SamplingRuleRequest is the body admins send to start sampling */
+export interface SamplingRuleRequest {
+  feature?: string;
+  userId?: number | null;
+  /** Share of the matching requests; defaults to 1 */
+  rate?: number | null;
+  /** Minutes the rule runs for; defaults to 60, at most MaxSamplingRuleDuration */
+  durationMinutes?: number;
+}
+
+/** ScheduleImport is a complete menu (e.g. one parsed from an emailed file) imported as a new version */
+export interface ScheduleImport {
+  starting_date: string;
+  ending_date?: string;
+  is_current?: boolean;
+  items: ScheduleImportItem[];
+}
+
+export interface ScheduleImportItem {
+  week_number?: number;
+  day_number?: number;
+  meal_type: string;
+  dish_ids: number[];
+}
+
+/**
+ * ImportResult references the version an import resolved to. Duplicate is set when the
+ * items matched an already imported version and nothing was created.
+ */
+export interface ScheduleImportResult {
+  version_id: number;
+  duplicate: boolean;
+  content_hash: string;
+}
+
+export interface ScheduleItem {
+  id: number;
+  version_id: number;
+  week_number: number;
+  day_number: number;
+  meal_type: string;
+  dish_ids: number[];
+}
+
+export interface ScheduleItemInput {
+  id?: number;
+  version_id: number;
+  week_number: number;
+  day_number: number;
+  meal_type: string;
+  dish_ids: number[];
+}
+
+/**
+ * ScheduleItemUpdate changes the fields that are set. DishIDs, when set, replaces the whole
+ * dish list.
+ */
+export interface ScheduleItemUpdate {
+  week_number?: number | null;
+  day_number?: number | null;
+  meal_type?: string | null;
+  dish_ids?: number[];
+}
+
+export interface ScheduleVersion {
+  id: number;
+  starting_date: string;
+  ending_date: string;
+  is_current: boolean;
+  /** UpdatedAt is when the version or its items last changed; ignored on create */
+  updated_at?: string;
+}
+
+export interface ScheduleVersionInput {
+  id?: number;
+  starting_date: string;
+  ending_date?: string;
+  is_current?: boolean;
+  /** UpdatedAt is when the version or its items last changed; ignored on create */
+  updated_at?: string | null;
+}
+
+/** School groups departments */
+export interface School {
+  id: number;
+  code: string;
+  name: string;
+  name_en?: string;
+  departments: DirectoryDepartment[];
+}
+
+export interface SchoolImport {
+  code: string;
+  name: string;
+  name_en?: string;
+  departments?: DepartmentImport[];
+}
+
+/**
+ * ServiceHours is when a restaurant serves a meal on weekdays or at weekends, as HH:MM
+ * campus time
+ */
+export interface ServiceHours {
+  meal_type: string;
+  days: string;
+  opens: string;
+  closes: string;
+}
+
+/**
+ * ServiceHours is when a restaurant serves a meal on weekdays or at weekends, as HH:MM
+ * campus time
+ */
+export interface ServiceHoursInput {
+  meal_type: string;
+  days: string;
+  opens: string;
+  closes: string;
+}
+
+/** Serving is a date and meal a food is served at */
+export interface Serving {
+  date: string;
+  meal_type: string;
+}
+
+/** Slot is a span of time a court can be booked for */
+export interface Slot {
+  starts_at: string;
+  ends_at: string;
+  available: boolean;
+}
+
+/** Slot is a span of time a court can be booked for */
+export interface SlotInput {
+  starts_at?: string;
+  ends_at?: string;
+  available?: boolean;
+}
+
+/** Snapshot is one stored copy of a database */
+export interface Snapshot {
+  database: string;
+  key: string;
+  size: number;
+  createdAt: string;
+}
+
+/** Source is an RSS or Atom feed of a department's site */
+export interface Source {
+  id: number;
+  slug: string;
+  name: string;
+  name_en?: string;
+  feed_url: string;
+  site_url?: string;
+  /** for items whose feed sets none */
+  category?: string;
+  enabled?: boolean;
+  /** Fetch state, shown to admins */
+  last_fetched_at?: string;
+  last_error?: string;
+}
+
+/** Source is an RSS or Atom feed of a department's site */
+export interface SourceInput {
+  id?: number;
+  slug: string;
+  name: string;
+  name_en?: string;
+  feed_url: string;
+  site_url?: string;
+  /** for items whose feed sets none */
+  category?: string;
+  enabled?: boolean | null;
+  /** Fetch state, shown to admins */
+  last_fetched_at?: string | null;
+  last_error?: string;
+}
+
+/** SpreadsheetImport is the form sent with an imported spreadsheet */
+export interface SpreadsheetImport {
+  StartingDate: string;
+  EndingDate?: string;
+  IsCurrent?: boolean;
+}
+
+/**
+ * SpreadsheetImportResult reports what a spreadsheet import created, or would create on a
+ * dry run, in which case VersionID is 0
+ */
+export interface SpreadsheetImportResult {
+  version_id: number;
+  duplicate: boolean;
+  content_hash: string;
+  dry_run: boolean;
+  items: number;
+  dishes: number;
+  created_foods: string[];
+}
+
+/**
+ * SpreadsheetItem is one meal slot of an imported spreadsheet or menu draft, with its
+ * foods by name
+ */
+export interface SpreadsheetItem {
+  week_number: number;
+  day_number: number;
+  meal_type: string;
+  foods: string[];
+}
+
+/**
+ * SpreadsheetItem is one meal slot of an imported spreadsheet or menu draft, with its
+ * foods by name
+ */
+export interface SpreadsheetItemInput {
+  week_number?: number;
+  day_number?: number;
+  meal_type: string;
+  foods: string[];
+}
+
+/** Staff is a member of a department's faculty or administration */
+export interface Staff {
+  id: number;
+  /** code */
+  department: string;
+  name: string;
+  name_en?: string;
+  /** e.g. "Professor" */
+  title?: string;
+  office?: string;
+  email?: string;
+  phone?: string;
+  office_hours?: string;
+}
+
+export interface StaffImport {
+  name: string;
+  name_en?: string;
+  title?: string;
+  office?: string;
+  email?: string;
+  phone?: string;
+  office_hours?: string;
+}
+
+/** Station is a campus print station */
+export interface Station {
+  id: number;
+  /** the station's queue on the print server */
+  code: string;
+  name: string;
+  name_en?: string;
+  location: string;
+  location_en?: string;
+  color: boolean;
+  /** Status is the print server's last report, nil until the first one */
+  status?: Status;
+}
+
+/** Station is a campus print station */
+export interface StationInput {
+  id?: number;
+  /** the station's queue on the print server */
+  code: string;
+  name: string;
+  name_en?: string;
+  location: string;
+  location_en?: string;
+  color?: boolean;
+  /** Status is the print server's last report, nil until the first one */
+  status?: StatusInput | null;
+}
+
+/** StationReport is the print server's report on one station, matched by code */
+export interface StationReport {
+  code: string;
+  state: string;
+  message?: string;
+  queue_length: number | null;
+  toner_percent?: number | null;
+  paper?: string;
+}
+
+/** Status is how a station was doing at the print server's last report */
+export interface Status {
+  /** ready, printing, error, offline or unknown */
+  state: string;
+  message?: string;
+  queue_length: number;
+  toner_percent?: number;
+  /** ok, low or empty */
+  paper?: string;
+  updated_at: string;
+}
+
+/** Status is how a station was doing at the print server's last report */
+export interface StatusInput {
+  /** ready, printing, error, offline or unknown */
+  state?: string;
+  message?: string;
+  queue_length?: number;
+  toner_percent?: number | null;
+  /** ok, low or empty */
+  paper?: string;
+  updated_at?: string;
+}
+
+/** StatusReport is what the print server sends to the ingest endpoint */
+export interface StatusReport {
+  stations: StationReport[];
+}
+
+export interface StatusResponse {
+  internal_server_latency: string;
+  uptime: string;
+}
+
+/**
+ * Subscription is a webhook a token holder registered for some events. It belongs to
+ * the token that created it and only receives the events that token is entitled to.
+ */
+export interface Subscription {
+  id: number;
+  token_id: number;
+  url: string;
+  events: string[];
+  description?: string;
+  active: boolean;
+  created_at: string;
+  updated_at: string;
+}
+
+/**
+ * SubscriptionCreateRequest is the body of POST /webhooks. A signing secret is generated
+ * when none is given.
+ */
+export interface SubscriptionCreateRequest {
+  url: string;
+  events: string[];
+  secret?: string;
+  description?: string | null;
+}
+
+/** SubscriptionUpdateRequest is the body of PATCH /webhooks/:id; missing fields are kept */
+export interface SubscriptionUpdateRequest {
+  url?: string | null;
+  events?: string[];
+  description?: string | null;
+  active?: boolean | null;
+}
+
+/**
+ * SurgeWindow is a predefined high-traffic period (exam weeks, registration
+ * days) during which its groups get higher quotas and caches refresh sooner
+ */
+export interface SurgeWindow {
+  id: number;
+  name: string;
+  startsAt: string;
+  endsAt: string;
+  rpmMultiplier: number;
+  /** Applied to cache TTLs, 1 leaves them unchanged */
+  cacheTtlFactor: number;
+  groupIds: number[];
+  active: boolean;
+  createdBy?: number;
+  createdAt: string;
+}
+
+/** SurgeWindowRequest represents the request body for creating or replacing a surge window */
+export interface SurgeWindowRequest {
+  name: string;
+  startsAt: string;
+  endsAt: string;
+  rpmMultiplier: number;
+  /** Defaults to 1 */
+  cacheTtlFactor?: number;
+  groupIds: number[];
+}
+
+/** Tag is a tag of the listed postings with the number of postings it is on */
+export interface Tag {
+  tag: string;
+  postings: number;
+}
+
+/**
+ * ThrottledAddress is an address that sent invalid tokens. IPv6 clients are tracked by
+ * their /64, since one host can use any address in it.
+ */
+export interface ThrottledAddress {
+  address: string;
+  failures: number;
+  lastFailure: string;
+  blockedUntil?: string;
+}
+
+/** Token represents an API token */
+export interface Token {
+  id: number;
+  userId: number;
+  label: string;
+  adminCreated: boolean;
+  /** Adds a Server-Timing header to responses */
+  debugTiming: boolean;
+  expiresAt?: string;
+  revokedAt?: string;
+  createdAt: string;
+  features?: Feature[];
+  /** Versions pinned with "slug@vN" by feature slug; unlisted features use v1 */
+  featureVersions?: Record<string, number>;
+  allowedIps?: string[];
+}
+
+/** TokenAllowance describes how many more tokens the user can create */
+export interface TokenAllowance {
+  active: number;
+  max: number;
+  canCreate: boolean;
+}
+
+/** TokenCreateRequest represents the request body for creating a token */
+export interface TokenCreateRequest {
+  label: string;
+  features: string[];
+  allowedIps?: string[];
+  expiresAt?: string | null;
+}
+
+/** TokenRequestCount ranks a token by requests to a feature */
+export interface TokenRequestCount {
+  tokenId: number;
+  label: string;
+  userId: number;
+  email: string;
+  revoked: boolean;
+  requests: number;
+  lastHour: string;
+}
+
+/** TokenStats counts tokens by state */
+export interface TokenStats {
+  issued: number;
+  issuedRecent: number;
+  revoked: number;
+  active: number;
+}
+
+/** TokenUpdateRequest represents the request body for updating one's own token */
+export interface TokenUpdateRequest {
+  debugTiming: boolean | null;
+}
+
+export interface UnseenAnnouncements {
+  count: number;
+  announcements: Announcement[];
+}
+
+/** User represents an authenticated user */
+export interface User {
+  id: number;
+  email: string;
+  displayName: string;
+  role: string;
+  status: string;
+  groupId: number;
+  maxTokens: number;
+  createdAt: string;
+  /** Suspension context, only set while Status is suspended */
+  suspensionReason?: string;
+  suspendedBy?: number;
+  suspendedAt?: string;
+  /** NULL = until lifted by an admin */
+  suspendedUntil?: string;
+  /** Set while the user is deleted; deleted users cannot sign in or use their tokens */
+  deletedAt?: string;
+  /** Joined fields (not always populated) */
+  group?: Group;
+}
+
+/** UserNote is a free-form admin-only note on a user account */
+export interface UserNote {
+  id: number;
+  userId: number;
+  authorId?: number;
+  body: string;
+  createdAt: string;
+}
+
+/** UserNoteCreateRequest represents the request body for adding a note to a user */
+export interface UserNoteCreateRequest {
+  body: string;
+}
+
+/**
+ * UserPermissions is the evaluated authorization state of a user, returned in
+ * one call so the frontend does not have to probe endpoints for 403s
+ */
+export interface UserPermissions {
+  role: string;
+  permissions: string[];
+  features: FeaturePermission[];
+  tokens: TokenAllowance;
+  /** 0 means unlimited */
+  maxSessions: number;
+}
+
+/** UserQuotaOverride defines a per-user RPM override on a feature */
+export interface UserQuotaOverride {
+  userId: number;
+  featureId: number;
+  /** NULL = uncapped */
+  rpmLimit: number | null;
+}
+
+/** UserRequestCount ranks a user by requests to a feature */
+export interface UserRequestCount {
+  userId: number;
+  email: string;
+  displayName: string;
+  requests: number;
+  /** Start of the last hour with requests */
+  lastHour: string;
+}
+
+/** UserStats counts users by state */
+export interface UserStats {
+  total: number;
+  active: number;
+  suspended: number;
+  admins: number;
+}
+
+/** UserTagsRequest represents the request body for replacing a user's tags */
+export interface UserTagsRequest {
+  tags: string[];
+}
+
+/**
+ * UserUpdateRequest represents the request body for updating a user.
+ * SuspensionReason and SuspendedUntil only apply when Status is suspended.
+ */
+export interface UserUpdateRequest {
+  role?: string | null;
+  status?: string | null;
+  groupId?: number | null;
+  maxTokens?: number | null;
+  suspensionReason?: string | null;
+  suspendedUntil?: string | null;
+}
+
+/** WebhookCreateRequest represents the request body for registering a webhook */
+export interface WebhookCreateRequest {
+  url: string;
+  events: string[];
+  format?: string;
+  /** Generated when empty */
+  secret?: string;
+  description?: string | null;
+}
+
+/** WebhookUpdateRequest represents the request body for updating a webhook */
+export interface WebhookUpdateRequest {
+  url?: string | null;
+  events?: string[];
+  format?: string | null;
+  description?: string | null;
+  active?: boolean | null;
+}
+
+/** WorkspaceCreateRequest represents the request body for creating a course workspace */
+export interface WorkspaceCreateRequest {
+  name: string;
+  features: string[];
+  endsAt: string;
+}
+
+/** WorkspaceMember is a student enrolled in a course workspace with their usage */
+export interface WorkspaceMember {
+  id: number;
+  userId: number;
+  email: string;
+  displayName: string;
+  tokenId?: number;
+  tokenActive: boolean;
+  requestCount: number;
+  lastUsedAt?: string;
+  currentRpm: number;
+  createdAt: string;
+}
+
+/** WorkspaceStudentsRequest represents the request body for enrolling students by email */
+export interface WorkspaceStudentsRequest {
+  emails: string[];
+}
+
+export type AdminListAcademicDomainsResponse = APIResponse<{
+  domains: string[];
+}>;
+
+export type AdminAddAcademicDomainBody = {
+  domain: string;
+};
+
+export type AdminAddAcademicDomainResponse = APIResponse<{
+  message: string;
+  domain: string;
+}>;
+
+export type AdminRemoveAcademicDomainResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AdminImportAcademicDomainsBody = {
+  domains: string[];
+};
+
+export type AdminImportAcademicDomainsResponse = APIResponse<{
+  added: number;
+  existing: number;
+  invalid: string[];
+}>;
+
+export interface AdminListAccessRequestsQuery {
+  status?: QueryValue;
+}
+
+export type AdminListAccessRequestsResponse = APIResponse<{
+  requests: AccessRequest[];
+}>;
+
+export type AdminApproveAccessRequestBody = AccessRequestDecisionRequest;
+
+export type AdminApproveAccessRequestResponse = APIResponse<{
+  request: AccessRequest | null;
+}>;
+
+export type AdminDenyAccessRequestBody = AccessRequestDecisionRequest;
+
+export type AdminDenyAccessRequestResponse = APIResponse<{
+  request: AccessRequest | null;
+}>;
+
+export type AdminRevokeAccessRequestBody = AccessRequestDecisionRequest;
+
+export type AdminRevokeAccessRequestResponse = APIResponse<{
+  request: AccessRequest | null;
+}>;
+
+export type AdminGetRequestDiagnosticResponse = APIResponse<{
+  diagnostic: DenialDiagnostic | null;
+}>;
+
+export interface AdminListErrorsQuery {
+  limit?: QueryValue;
+  status?: QueryValue;
+  route?: QueryValue;
+}
+
+export type AdminListErrorsResponse = APIResponse<{
+  errors: ErrorEntry[];
+  kept: number;
+}>;
+
+export type AdminListFeaturesResponse = APIResponse<{
+  features: Feature[];
+}>;
+
+export type AdminCreateFeatureBody = FeatureCreateRequest;
+
+export type AdminCreateFeatureResponse = APIResponse<{
+  feature: Feature | null;
+}>;
+
+export type AdminGetFeatureResponse = APIResponse<{
+  feature: Feature | null;
+}>;
+
+export type AdminUpdateFeatureBody = FeatureUpdateRequest;
+
+export type AdminUpdateFeatureResponse = APIResponse<{
+  feature: Feature | null;
+}>;
+
+export type AdminDeleteFeatureResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface AdminGetFeatureUsageQuery {
+  days?: QueryValue;
+  limit?: QueryValue;
+}
+
+export type AdminGetFeatureUsageResponse = APIResponse<{
+  feature: Feature | null;
+  days: number;
+  usage: FeatureUsageLeaderboard | null;
+}>;
+
+export type AdminListDeprecatedFeatureTokensResponse = APIResponse<{
+  tokens: DeprecatedFeatureToken[];
+}>;
+
+export type AdminGetFeatureTreeResponse = APIResponse<{
+  features: (Feature | null)[];
+}>;
+
+export interface AdminListGroupsQuery {
+  deleted?: QueryValue;
+}
+
+export type AdminListGroupsResponse = APIResponse<{
+  groups: Group[];
+}>;
+
+export type AdminCreateGroupBody = GroupCreateRequest;
+
+export type AdminCreateGroupResponse = APIResponse<{
+  group: Group | null;
+}>;
+
+export type AdminGetGroupResponse = APIResponse<{
+  group: Group | null;
+}>;
+
+export type AdminUpdateGroupBody = GroupUpdateRequest;
+
+export type AdminUpdateGroupResponse = APIResponse<{
+  group: Group | null;
+}>;
+
+export type AdminDeleteGroupResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AdminGetGroupQuotasResponse = APIResponse<{
+  quotas: GroupFeatureQuota[];
+}>;
+
+export type AdminSetGroupQuotasBody = QuotaSetRequest;
+
+export type AdminSetGroupQuotasResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AdminRestoreGroupResponse = APIResponse<{
+  group: Group | null;
+}>;
+
+export type AdminListInvitationsResponse = APIResponse<{
+  invitations: Invitation[];
+}>;
+
+export type AdminCreateInvitationBody = InvitationCreateRequest;
+
+export type AdminCreateInvitationResponse = APIResponse<{
+  invitation: Invitation | null;
+}>;
+
+export type AdminDeleteInvitationResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface AdminListSampledRequestsQuery {
+  userId?: QueryValue;
+  limit?: QueryValue;
+  feature?: QueryValue;
+}
+
+export type AdminListSampledRequestsResponse = APIResponse<{
+  requests: SampledRequest[];
+}>;
+
+export type AdminGetSampledRequestResponse = APIResponse<{
+  request: SampledRequest | null;
+}>;
+
+export type AdminListSamplingRulesResponse = APIResponse<{
+  rules: SamplingRule[];
+}>;
+
+export type AdminCreateSamplingRuleBody = SamplingRuleRequest;
+
+export type AdminCreateSamplingRuleResponse = APIResponse<{
+  rule: SamplingRule;
+}>;
+
+export type AdminDeleteSamplingRuleResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface AdminGetStatsQuery {
+  days?: QueryValue;
+}
+
+export type AdminGetStatsResponse = APIResponse<{
+  days: number;
+  stats: AdminStats | null;
+}>;
+
+export type AdminListSurgeWindowsResponse = APIResponse<{
+  surges: SurgeWindow[];
+}>;
+
+export type AdminCreateSurgeWindowBody = SurgeWindowRequest;
+
+export type AdminCreateSurgeWindowResponse = APIResponse<{
+  surge: SurgeWindow | null;
+}>;
+
+export type AdminUpdateSurgeWindowBody = SurgeWindowRequest;
+
+export type AdminUpdateSurgeWindowResponse = APIResponse<{
+  surge: SurgeWindow | null;
+}>;
+
+export type AdminDeleteSurgeWindowResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AdminListThrottledAddressesResponse = APIResponse<{
+  addresses: ThrottledAddress[];
+}>;
+
+export type AdminLiftThrottleResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AdminRevokeTokenResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AdminRestoreTokenResponse = APIResponse<{
+  token: Token | null;
+}>;
+
+export interface AdminListUsersQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  q?: QueryValue;
+  role?: QueryValue;
+  status?: QueryValue;
+  groupId?: QueryValue;
+  hasActiveTokens?: QueryValue;
+  deleted?: QueryValue;
+  tag?: QueryValue;
+}
+
+export type AdminListUsersResponse = APIResponse<{
+  users: User[];
+  total: number;
+  limit: number;
+}>;
+
+export type AdminGetUserResponse = APIResponse<{
+  user: User | null;
+  notes: UserNote[];
+  tags: string[];
+}>;
+
+export type AdminUpdateUserBody = UserUpdateRequest;
+
+export type AdminUpdateUserResponse = APIResponse<{
+  user: User | null;
+}>;
+
+export type AdminDeleteUserResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AdminAddUserNoteBody = UserNoteCreateRequest;
+
+export type AdminAddUserNoteResponse = APIResponse<{
+  note: UserNote | null;
+}>;
+
+export type AdminDeleteUserNoteResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AdminGetUserQuotasResponse = APIResponse<{
+  overrides: UserQuotaOverride[];
+}>;
+
+export type AdminSetUserQuotasBody = QuotaSetRequest;
+
+export type AdminSetUserQuotasResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AdminRestoreUserResponse = APIResponse<{
+  user: User | null;
+}>;
+
+export type AdminSetUserTagsBody = UserTagsRequest;
+
+export type AdminSetUserTagsResponse = APIResponse<{
+  tags: string[];
+}>;
+
+export interface AdminListUserTokensQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+}
+
+export type AdminListUserTokensResponse = APIResponse<{
+  tokens: Token[];
+}>;
+
+export type AdminCreateUserTokenBody = TokenCreateRequest;
+
+export type AdminCreateUserTokenResponse = APIResponse<{
+  token: string;
+  details: Token;
+  message: string;
+}>;
+
+export type AdminGetUserUsageResponse = APIResponse<{
+  totalRpm: number;
+  byFeature: Record<string, number>;
+}>;
+
+export type AdminListWebhooksResponse = APIResponse<{
+  webhooks: AdminWebhook[];
+  events: string[];
+}>;
+
+export type AdminCreateWebhookBody = WebhookCreateRequest;
+
+export type AdminCreateWebhookResponse = APIResponse<{
+  webhook: AdminWebhook | null;
+  secret: string;
+}>;
+
+export type AdminUpdateWebhookBody = WebhookUpdateRequest;
+
+export type AdminUpdateWebhookResponse = APIResponse<{
+  webhook: AdminWebhook | null;
+}>;
+
+export type AdminDeleteWebhookResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AdminTestWebhookResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AuthListAccessRequestsResponse = APIResponse<{
+  requests: AccessRequest[];
+}>;
+
+export type AuthRequestAccessBody = AccessRequestCreateRequest;
+
+export type AuthRequestAccessResponse = APIResponse<{
+  request: AccessRequest | null;
+}>;
+
+export interface AuthCallbackQuery {
+  state?: QueryValue;
+  error?: QueryValue;
+  code?: QueryValue;
+}
+
+export type AuthCallbackResponse = APIResponse<{
+  message: string;
+  user: {
+    id: number;
+    email: string;
+    displayName: string;
+    role: string;
+  };
+}>;
+
+export type AuthGetAssignableFeatureTreeResponse = APIResponse<{
+  features: (Feature | null)[];
+}>;
+
+export type AuthLogoutResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AuthMeResponse = APIResponse<{
+  user: {
+    id: number;
+    email: string;
+    displayName: string;
+    role: string;
+    status: string;
+    group: Group | null;
+    maxTokens: number;
+    createdAt: string;
+  };
+}>;
+
+export type AuthMyPermissionsResponse = APIResponse<UserPermissions | null>;
+
+export type AuthGetMyPreferencesResponse = APIResponse<{
+  preferences: Preferences | null;
+}>;
+
+export type AuthSetMyPreferencesBody = PreferencesInput;
+
+export type AuthSetMyPreferencesResponse = APIResponse<{
+  preferences: Preferences | null;
+}>;
+
+export interface AuthListTokensQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+}
+
+export type AuthListTokensResponse = APIResponse<{
+  tokens: Token[];
+}>;
+
+export type AuthCreateTokenBody = TokenCreateRequest;
+
+export type AuthCreateTokenResponse = APIResponse<{
+  token: string;
+  details: Token;
+  message: string;
+}>;
+
+export type AuthUpdateTokenBody = TokenUpdateRequest;
+
+export type AuthUpdateTokenResponse = APIResponse<{
+  token: Token | null;
+}>;
+
+export type AuthRevokeTokenResponse = APIResponse<{
+  message: string;
+}>;
+
+export type AuthListAssignableFeaturesResponse = APIResponse<{
+  features: Feature[];
+}>;
+
+export type AuthFeatureCatalogResponse = APIResponse<{
+  features: Feature[];
+}>;
+
+export type BackupListBackupsResponse = APIResponse<{
+  backups: Snapshot[];
+}>;
+
+export type BackupCreateBackupResponse = APIResponse<{
+  backups: Snapshot[];
+}>;
+
+export interface CaldavGetQuery {
+  lang?: QueryValue;
+}
+
+export type CommonErrorCatalogResponse = APIResponse<Definition[]>;
+
+export type CommonStatusResponse = APIResponse<StatusResponse>;
+
+export type CoursesPostCourseBody = CourseInput;
+
+export type CoursesPostCourseResponse = APIResponse<{
+  id: number;
+}>;
+
+export type CoursesReplaceCourseBody = CourseInput;
+
+export type CoursesReplaceCourseResponse = APIResponse<{
+  course: Course | null;
+}>;
+
+export type CoursesDeleteCourseResponse = APIResponse<{
+  message: string;
+}>;
+
+export type CoursesListDepartmentsResponse = APIResponse<{
+  departments: CoursesDepartment[];
+}>;
+
+export type CoursesPostDepartmentBody = CoursesDepartmentInput;
+
+export type CoursesPostDepartmentResponse = APIResponse<{
+  id: number;
+}>;
+
+export type CoursesReplaceDepartmentBody = CoursesDepartmentInput;
+
+export type CoursesReplaceDepartmentResponse = APIResponse<{
+  department: CoursesDepartment;
+}>;
+
+export type CoursesDeleteDepartmentResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface CoursesListCoursesQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  lang?: QueryValue;
+  department?: QueryValue;
+  instructor?: QueryValue;
+  q?: QueryValue;
+  semester?: QueryValue;
+}
+
+export type CoursesListCoursesResponse = APIResponse<{
+  courses: Course[];
+  total: number;
+  limit: number;
+}>;
+
+export interface CoursesGetCourseQuery {
+  lang?: QueryValue;
+}
+
+export type CoursesGetCourseResponse = APIResponse<{
+  course: Course;
+}>;
+
+export interface CoursesGetDepartmentsQuery {
+  lang?: QueryValue;
+}
+
+export type CoursesGetDepartmentsResponse = APIResponse<{
+  departments: CoursesDepartment[];
+}>;
+
+export type DirectoryPostImportBody = DirectoryImport;
+
+export interface DirectoryPostImportQuery {
+  dry_run?: QueryValue;
+}
+
+export type DirectoryPostImportResponse = APIResponse<DirectoryImportResult | null>;
+
+export interface DirectoryGetDepartmentStaffQuery {
+  lang?: QueryValue;
+}
+
+export type DirectoryGetDepartmentStaffResponse = APIResponse<{
+  staff: Staff[];
+}>;
+
+export interface DirectoryGetSchoolsQuery {
+  lang?: QueryValue;
+}
+
+export type DirectoryGetSchoolsResponse = APIResponse<{
+  schools: School[];
+}>;
+
+export interface DirectorySearchStaffQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  lang?: QueryValue;
+  department?: QueryValue;
+  q?: QueryValue;
+}
+
+export type DirectorySearchStaffResponse = APIResponse<{
+  staff: Staff[];
+  total: number;
+  limit: number;
+}>;
+
+export interface DirectoryGetStaffQuery {
+  lang?: QueryValue;
+}
+
+export type DirectoryGetStaffResponse = APIResponse<{
+  staff: Staff;
+}>;
+
+export type EclassGetCoursesResponse = APIResponse<{
+  courses: EnrolledCourse[];
+}>;
+
+export interface EclassGetDeadlinesQuery {
+  days?: QueryValue;
+}
+
+export type EclassGetDeadlinesResponse = APIResponse<{
+  deadlines: Deadline[];
+  days: number;
+}>;
+
+export interface EclassGetDocumentsQuery {
+  days?: QueryValue;
+}
+
+export type EclassGetDocumentsResponse = APIResponse<{
+  documents: EclassDocument[];
+  days: number;
+}>;
+
+export type EclassGetLinkResponse = APIResponse<{
+  link: Link | null;
+}>;
+
+export type EclassPutLinkBody = LinkRequest;
+
+export type EclassPutLinkResponse = APIResponse<{
+  link: Link | null;
+}>;
+
+export type EclassDeleteLinkResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface EventsAdminListEventsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  category?: QueryValue;
+  q?: QueryValue;
+  from?: QueryValue;
+  to?: QueryValue;
+  status?: QueryValue;
+}
+
+export type EventsAdminListEventsResponse = APIResponse<{
+  events: EventsEvent[];
+  total: number;
+  limit: number;
+}>;
+
+export type EventsPostEventBody = EventRequest;
+
+export type EventsPostEventResponse = APIResponse<{
+  id: number;
+}>;
+
+export type EventsReplaceEventBody = EventRequest;
+
+export type EventsReplaceEventResponse = APIResponse<{
+  event: EventsEvent | null;
+}>;
+
+export type EventsDeleteEventResponse = APIResponse<{
+  message: string;
+}>;
+
+export type EventsApproveEventBody = DecisionRequest;
+
+export type EventsApproveEventResponse = APIResponse<{
+  event: EventsEvent | null;
+}>;
+
+export type EventsRejectEventBody = DecisionRequest;
+
+export type EventsRejectEventResponse = APIResponse<{
+  event: EventsEvent | null;
+}>;
+
+export interface EventsListEventsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  lang?: QueryValue;
+  category?: QueryValue;
+  q?: QueryValue;
+  from?: QueryValue;
+  to?: QueryValue;
+}
+
+export type EventsListEventsResponse = APIResponse<{
+  events: EventsEvent[];
+  total: number;
+  limit: number;
+}>;
+
+export interface EventsGetEventQuery {
+  lang?: QueryValue;
+}
+
+export type EventsGetEventResponse = APIResponse<{
+  event: EventsEvent;
+}>;
+
+export interface EventsExportCalendarQuery {
+  lang?: QueryValue;
+  category?: QueryValue;
+  q?: QueryValue;
+  from?: QueryValue;
+  to?: QueryValue;
+}
+
+export interface EventsListSubmissionsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+}
+
+export type EventsListSubmissionsResponse = APIResponse<{
+  events: EventsEvent[];
+  total: number;
+  limit: number;
+}>;
+
+export type EventsPostSubmissionBody = EventRequest;
+
+export type EventsPostSubmissionResponse = APIResponse<{
+  event: EventsEvent | null;
+}>;
+
+export interface JobsAdminListJobsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  type?: QueryValue;
+  q?: QueryValue;
+  location?: QueryValue;
+  employer?: QueryValue;
+  remote?: QueryValue;
+  status?: QueryValue;
+}
+
+export type JobsAdminListJobsResponse = APIResponse<{
+  jobs: Job[];
+  total: number;
+  limit: number;
+}>;
+
+export type JobsPostJobBody = JobRequest;
+
+export type JobsPostJobResponse = APIResponse<{
+  id: number;
+}>;
+
+export type JobsReplaceJobBody = JobRequest;
+
+export type JobsReplaceJobResponse = APIResponse<{
+  job: Job | null;
+}>;
+
+export type JobsDeleteJobResponse = APIResponse<{
+  message: string;
+}>;
+
+export type JobsApproveJobResponse = APIResponse<{
+  job: Job | null;
+}>;
+
+export type JobsRejectJobResponse = APIResponse<{
+  job: Job | null;
+}>;
+
+export interface JobsListJobsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  type?: QueryValue;
+  q?: QueryValue;
+  location?: QueryValue;
+  employer?: QueryValue;
+  remote?: QueryValue;
+}
+
+export type JobsListJobsResponse = APIResponse<{
+  jobs: Job[];
+  total: number;
+  limit: number;
+}>;
+
+export type JobsGetJobResponse = APIResponse<{
+  job: Job;
+}>;
+
+export interface JobsListSubmissionsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+}
+
+export type JobsListSubmissionsResponse = APIResponse<{
+  jobs: Job[];
+  total: number;
+  limit: number;
+}>;
+
+export type JobsPostSubmissionBody = JobRequest;
+
+export type JobsPostSubmissionResponse = APIResponse<{
+  job: Job | null;
+}>;
+
+export type JobsReplaceSubmissionBody = JobRequest;
+
+export type JobsReplaceSubmissionResponse = APIResponse<{
+  job: Job | null;
+}>;
+
+export type JobsDeleteSubmissionResponse = APIResponse<{
+  message: string;
+}>;
+
+export type LibraryListLibrariesResponse = APIResponse<{
+  libraries: Library[];
+}>;
+
+export type LibraryPostLibraryBody = LibraryInput;
+
+export type LibraryPostLibraryResponse = APIResponse<{
+  id: number;
+}>;
+
+export type LibraryReplaceLibraryBody = LibraryInput;
+
+export type LibraryReplaceLibraryResponse = APIResponse<{
+  library: Library | null;
+}>;
+
+export type LibraryDeleteLibraryResponse = APIResponse<{
+  message: string;
+}>;
+
+export type LibraryPostRoomBody = RoomInput;
+
+export type LibraryPostRoomResponse = APIResponse<{
+  id: number;
+}>;
+
+export type LibraryReplaceRoomBody = RoomInput;
+
+export type LibraryReplaceRoomResponse = APIResponse<{
+  room: Room;
+}>;
+
+export type LibraryDeleteRoomResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface LibraryGetLibrariesQuery {
+  lang?: QueryValue;
+}
+
+export type LibraryGetLibrariesResponse = APIResponse<{
+  libraries: Library[];
+}>;
+
+export interface LibraryGetLibraryQuery {
+  lang?: QueryValue;
+}
+
+export type LibraryGetLibraryResponse = APIResponse<{
+  library: Library;
+}>;
+
+export type LibraryPutOccupancyBody = OccupancyReport;
+
+export type LibraryPutOccupancyResponse = APIResponse<{
+  occupancy: Occupancy | null;
+}>;
+
+export interface LibraryGetRoomsQuery {
+  lang?: QueryValue;
+  date?: QueryValue;
+}
+
+export type LibraryGetRoomsResponse = APIResponse<{
+  date: string;
+  hours: OpeningHours[];
+  rooms: Room[];
+}>;
+
+export type LibraryListReservationsResponse = APIResponse<{
+  reservations: Reservation[];
+  max_active: number;
+}>;
+
+export type LibraryPostReservationBody = ReservationRequest;
+
+export type LibraryPostReservationResponse = APIResponse<{
+  reservation: Reservation | null;
+}>;
+
+export type LibraryDeleteReservationResponse = APIResponse<{
+  message: string;
+}>;
+
+export type MapsPostPOIBody = POIInput;
+
+export type MapsPostPOIResponse = APIResponse<{
+  id: number;
+}>;
+
+export type MapsReplacePOIBody = POIInput;
+
+export type MapsReplacePOIResponse = APIResponse<{
+  poi: POI | null;
+}>;
+
+export type MapsDeletePOIResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface MapsListPOIsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  lang?: QueryValue;
+  category?: QueryValue;
+  q?: QueryValue;
+  format?: QueryValue;
+}
+
+export interface MapsGetPOIQuery {
+  lang?: QueryValue;
+  format?: QueryValue;
+}
+
+export interface MapsGetNearbyPOIsQuery {
+  lat?: QueryValue;
+  lon?: QueryValue;
+  radius?: QueryValue;
+  limit?: QueryValue;
+  lang?: QueryValue;
+  category?: QueryValue;
+  q?: QueryValue;
+  format?: QueryValue;
+}
+
+export interface MapsGetTileQuery {
+  lang?: QueryValue;
+  category?: QueryValue;
+  q?: QueryValue;
+}
+
+export type NewsListSourcesResponse = APIResponse<{
+  sources: Source[];
+}>;
+
+export type NewsPostSourceBody = SourceInput;
+
+export type NewsPostSourceResponse = APIResponse<{
+  id: number;
+}>;
+
+export type NewsReplaceSourceBody = SourceInput;
+
+export type NewsReplaceSourceResponse = APIResponse<{
+  source: Source | null;
+}>;
+
+export type NewsDeleteSourceResponse = APIResponse<{
+  message: string;
+}>;
+
+export type NewsFetchSourceResponse = APIResponse<{
+  result: FetchResult;
+}>;
+
+export interface NewsGetFeedQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  category?: QueryValue;
+  q?: QueryValue;
+  source?: QueryValue[];
+}
+
+export type NewsGetFeedResponse = APIResponse<{
+  items: Item[];
+  total: number;
+  limit: number;
+}>;
+
+export type NewsGetCategoriesResponse = APIResponse<{
+  categories: Category[];
+}>;
+
+export interface NewsGetSourcesQuery {
+  lang?: QueryValue;
+}
+
+export type NewsGetSourcesResponse = APIResponse<{
+  sources: Source[];
+}>;
+
+export type PostingsApprovePostingResponse = APIResponse<{
+  posting: Posting | null;
+}>;
+
+export type PostingsRejectPostingResponse = APIResponse<{
+  posting: Posting | null;
+}>;
+
+export interface PostingsListEditorPostingsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  kind?: QueryValue;
+  department?: QueryValue;
+  q?: QueryValue;
+  tag?: QueryValue[];
+  open?: QueryValue;
+  status?: QueryValue;
+}
+
+export type PostingsListEditorPostingsResponse = APIResponse<{
+  postings: Posting[];
+  total: number;
+  limit: number;
+}>;
+
+export type PostingsPostPostingBody = PostingRequest;
+
+export type PostingsPostPostingResponse = APIResponse<{
+  posting: Posting | null;
+}>;
+
+export type PostingsReplacePostingBody = PostingRequest;
+
+export type PostingsReplacePostingResponse = APIResponse<{
+  posting: Posting | null;
+}>;
+
+export type PostingsDeletePostingResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface PostingsGetPostingsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  kind?: QueryValue;
+  department?: QueryValue;
+  q?: QueryValue;
+  tag?: QueryValue[];
+  open?: QueryValue;
+}
+
+export type PostingsGetPostingsResponse = APIResponse<{
+  postings: Posting[];
+  total: number;
+  limit: number;
+}>;
+
+export type PostingsGetPostingResponse = APIResponse<{
+  posting: Posting;
+}>;
+
+export interface PostingsGetTagsQuery {
+  kind?: QueryValue;
+  department?: QueryValue;
+  q?: QueryValue;
+  tag?: QueryValue[];
+  open?: QueryValue;
+}
+
+export type PostingsGetTagsResponse = APIResponse<{
+  tags: Tag[];
+}>;
+
+export interface PrintingListAlertsQuery {
+  since?: QueryValue;
+}
+
+export type PrintingListAlertsResponse = APIResponse<{
+  alerts: Alert[];
+}>;
+
+export type PrintingAcknowledgeAlertResponse = APIResponse<{
+  alert: Alert | null;
+}>;
+
+export type PrintingListStationsResponse = APIResponse<{
+  stations: Station[];
+}>;
+
+export type PrintingPostStationBody = StationInput;
+
+export type PrintingPostStationResponse = APIResponse<{
+  id: number;
+}>;
+
+export type PrintingReplaceStationBody = StationInput;
+
+export type PrintingReplaceStationResponse = APIResponse<{
+  station: Station | null;
+}>;
+
+export type PrintingDeleteStationResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface PrintingGetStationsQuery {
+  lang?: QueryValue;
+}
+
+export type PrintingGetStationsResponse = APIResponse<{
+  stations: Station[];
+}>;
+
+export interface PrintingGetStationQuery {
+  lang?: QueryValue;
+}
+
+export type PrintingGetStationResponse = APIResponse<{
+  station: Station;
+}>;
+
+export type PrintingPutStatusBody = StatusReport;
+
+export type PrintingPutStatusResponse = APIResponse<{
+  updated: number;
+  unknown: string[];
+}>;
+
+export type SchedulePostAnnouncementBody = AnnouncementInput;
+
+export type SchedulePostAnnouncementResponse = APIResponse<{
+  id: number;
+}>;
+
+export type ScheduleUpdateAnnouncementBody = AnnouncementUpdate;
+
+export type ScheduleUpdateAnnouncementResponse = APIResponse<{
+  message: string;
+}>;
+
+export type ScheduleDeleteAnnouncementResponse = APIResponse<{
+  message: string;
+}>;
+
+export type ScheduleListChannelsResponse = APIResponse<{
+  channels: MenuChannel[];
+}>;
+
+export type SchedulePostChannelBody = MenuChannelInput;
+
+export type SchedulePostChannelResponse = APIResponse<{
+  id: number;
+}>;
+
+export type ScheduleGetChannelResponse = APIResponse<{
+  channel: MenuChannel;
+}>;
+
+export type ScheduleUpdateChannelBody = MenuChannelUpdate;
+
+export type ScheduleUpdateChannelResponse = APIResponse<{
+  channel: MenuChannel;
+}>;
+
+export type ScheduleDeleteChannelResponse = APIResponse<{
+  message: string;
+}>;
+
+export type ScheduleTestChannelResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface ScheduleListClosuresQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  upcoming?: QueryValue;
+}
+
+export type ScheduleListClosuresResponse = APIResponse<{
+  closures: Closure[];
+  total: number;
+  limit: number;
+}>;
+
+export type SchedulePostClosureBody = ClosureInput;
+
+export type SchedulePostClosureResponse = APIResponse<{
+  id: number;
+}>;
+
+export type ScheduleGetClosureResponse = APIResponse<{
+  closure: Closure | null;
+}>;
+
+export type ScheduleReplaceClosureBody = ClosureInput;
+
+export type ScheduleReplaceClosureResponse = APIResponse<{
+  closure: Closure | null;
+}>;
+
+export type ScheduleDeleteClosureResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface ScheduleListFoodsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  q?: QueryValue;
+}
+
+export type ScheduleListFoodsResponse = APIResponse<{
+  foods: Food[];
+  total: number;
+  limit: number;
+}>;
+
+export type SchedulePostFoodBody = FoodInput;
+
+export type SchedulePostFoodResponse = APIResponse<{
+  id: number;
+}>;
+
+export type ScheduleGetFoodResponse = APIResponse<{
+  food: Food | null;
+}>;
+
+export type ScheduleUpdateFoodBody = FoodUpdate;
+
+export type ScheduleUpdateFoodResponse = APIResponse<{
+  food: Food | null;
+}>;
+
+export type ScheduleDeleteFoodResponse = APIResponse<{
+  message: string;
+}>;
+
+export type SchedulePutFoodImageResponse = APIResponse<{
+  food: Food | null;
+}>;
+
+export type ScheduleDeleteFoodImageResponse = APIResponse<{
+  message: string;
+}>;
+
+export type SchedulePostImportBody = ScheduleImport;
+
+export type SchedulePostImportResponse = APIResponse<ScheduleImportResult | null>;
+
+export type SchedulePostScheduleBody = ScheduleItemInput;
+
+export type SchedulePostScheduleResponse = APIResponse<null>;
+
+export type ScheduleGetScheduleItemResponse = APIResponse<{
+  item: ScheduleItem | null;
+}>;
+
+export type ScheduleReplaceScheduleItemBody = ScheduleImportItem;
+
+export type ScheduleReplaceScheduleItemResponse = APIResponse<{
+  item: ScheduleItem | null;
+}>;
+
+export type ScheduleUpdateScheduleItemBody = ScheduleItemUpdate;
+
+export type ScheduleUpdateScheduleItemResponse = APIResponse<{
+  item: ScheduleItem | null;
+}>;
+
+export type ScheduleDeleteScheduleItemResponse = APIResponse<{
+  message: string;
+}>;
+
+export type ScheduleListRestaurantsResponse = APIResponse<{
+  restaurants: Restaurant[];
+}>;
+
+export type SchedulePostRestaurantBody = RestaurantInput;
+
+export type SchedulePostRestaurantResponse = APIResponse<{
+  id: number;
+}>;
+
+export type ScheduleGetRestaurantResponse = APIResponse<{
+  restaurant: Restaurant | null;
+}>;
+
+export type ScheduleReplaceRestaurantBody = RestaurantInput;
+
+export type ScheduleReplaceRestaurantResponse = APIResponse<{
+  restaurant: Restaurant | null;
+}>;
+
+export type ScheduleDeleteRestaurantResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface ScheduleListReviewsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  food_id?: QueryValue;
+  hidden?: QueryValue;
+}
+
+export type ScheduleListReviewsResponse = APIResponse<{
+  reviews: Review[];
+  total: number;
+  limit: number;
+}>;
+
+export type ScheduleModerateReviewBody = ReviewModeration;
+
+export type ScheduleModerateReviewResponse = APIResponse<{
+  message: string;
+}>;
+
+export type ScheduleDeleteReviewResponse = APIResponse<{
+  message: string;
+}>;
+
+export type SchedulePostMenuDraftBody = MenuDraftInput;
+
+export interface SchedulePostMenuDraftQuery {
+  dry_run?: QueryValue;
+}
+
+export type SchedulePostMenuDraftResponse = APIResponse<SpreadsheetImportResult | null>;
+
+export type SchedulePostSpreadsheetImportBody = SpreadsheetImport;
+
+export interface SchedulePostSpreadsheetImportQuery {
+  dry_run?: QueryValue;
+}
+
+export type SchedulePostSpreadsheetImportResponse = APIResponse<SpreadsheetImportResult | null>;
+
+export type SchedulePostMenuScanBody = SpreadsheetImport;
+
+export type SchedulePostMenuScanResponse = APIResponse<MenuDraft>;
+
+export type SchedulePostVersionBody = ScheduleVersionInput;
+
+export type SchedulePostVersionResponse = APIResponse<{
+  id: number;
+}>;
+
+export interface ScheduleListAnnouncementsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  lang?: QueryValue;
+  type?: QueryValue;
+  active?: QueryValue;
+}
+
+export type ScheduleListAnnouncementsResponse = APIResponse<{
+  announcements: Announcement[];
+  total: number;
+  limit: number;
+}>;
+
+export interface ScheduleGetScheduleQuery {
+  all?: QueryValue;
+  date?: QueryValue;
+  from?: QueryValue;
+  to?: QueryValue;
+  diet?: QueryValue;
+  lang?: QueryValue;
+}
+
+export type ScheduleGetScheduleResponse = APIResponse<DaySchedule[] | DateSchedule | Record<string, Record<string, DateSchedule>>>;
+
+export type SchedulePostAnnouncementReceiptsBody = AnnouncementReceipt;
+
+export type SchedulePostAnnouncementReceiptsResponse = APIResponse<null>;
+
+export interface ScheduleGetUnseenAnnouncementsQuery {
+  device_id?: QueryValue;
+  lang?: QueryValue;
+}
+
+export type ScheduleGetUnseenAnnouncementsResponse = APIResponse<UnseenAnnouncements>;
+
+export interface ScheduleGetChangesQuery {
+  since?: QueryValue;
+  lang?: QueryValue;
+}
+
+export type ScheduleGetChangesResponse = APIResponse<{
+  since: string;
+  changes: MenuChange[];
+}>;
+
+export interface ScheduleListUpcomingClosuresQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+  lang?: QueryValue;
+}
+
+export type ScheduleListUpcomingClosuresResponse = APIResponse<{
+  closures: Closure[];
+  total: number;
+  limit: number;
+}>;
+
+export interface ScheduleListFavoritesQuery {
+  lang?: QueryValue;
+}
+
+export type ScheduleListFavoritesResponse = APIResponse<{
+  favorites: Food[];
+}>;
+
+export interface ScheduleGetNextFavoritesQuery {
+  lang?: QueryValue;
+}
+
+export type ScheduleGetNextFavoritesResponse = APIResponse<{
+  favorites: Favorite[];
+  lookahead_days: number;
+}>;
+
+export type SchedulePutFavoriteResponse = APIResponse<{
+  message: string;
+}>;
+
+export type ScheduleDeleteFavoriteResponse = APIResponse<{
+  message: string;
+}>;
+
+export type ScheduleGetMyReviewResponse = APIResponse<Review | null>;
+
+export type SchedulePutMyReviewBody = ReviewInput;
+
+export type SchedulePutMyReviewResponse = APIResponse<Review | null>;
+
+export type ScheduleDeleteMyReviewResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface ScheduleGetFoodReviewsQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+}
+
+export type ScheduleGetFoodReviewsResponse = APIResponse<{
+  reviews: Review[];
+  total: number;
+  limit: number;
+}>;
+
+export interface ScheduleGetHoursQuery {
+  lang?: QueryValue;
+}
+
+export type ScheduleGetHoursResponse = APIResponse<{
+  restaurants: Restaurant[];
+}>;
+
+export interface ScheduleGetPersonalizedQuery {
+  date?: QueryValue;
+  suitable_only?: QueryValue;
+  lang?: QueryValue;
+}
+
+export type ScheduleGetPersonalizedResponse = APIResponse<PersonalizedDay>;
+
+export interface ScheduleGetStatsQuery {
+  days?: QueryValue;
+  limit?: QueryValue;
+  sort?: QueryValue;
+  lang?: QueryValue;
+}
+
+export type ScheduleGetStatsResponse = APIResponse<MenuStats>;
+
+export interface ScheduleGetTodayQuery {
+  diet?: QueryValue;
+  lang?: QueryValue;
+}
+
+export type ScheduleGetTodayResponse = APIResponse<DaySchedule>;
+
+export interface ScheduleGetTomorrowQuery {
+  diet?: QueryValue;
+  lang?: QueryValue;
+}
+
+export type ScheduleGetTomorrowResponse = APIResponse<DaySchedule>;
+
+export type ScheduleGetVersionResponse = APIResponse<ScheduleVersion | null>;
+
+export interface ScheduleGetWeekQuery {
+  diet?: QueryValue;
+  lang?: QueryValue;
+}
+
+export type ScheduleGetWeekResponse = APIResponse<DaySchedule[]>;
+
+export type SportsPostClassBody = ClassInput;
+
+export type SportsPostClassResponse = APIResponse<{
+  id: number;
+}>;
+
+export type SportsReplaceClassBody = ClassInput;
+
+export type SportsReplaceClassResponse = APIResponse<{
+  class: Class;
+}>;
+
+export type SportsDeleteClassResponse = APIResponse<{
+  message: string;
+}>;
+
+export type SportsPostCourtBody = CourtInput;
+
+export type SportsPostCourtResponse = APIResponse<{
+  id: number;
+}>;
+
+export type SportsReplaceCourtBody = CourtInput;
+
+export type SportsReplaceCourtResponse = APIResponse<{
+  court: Court;
+}>;
+
+export type SportsDeleteCourtResponse = APIResponse<{
+  message: string;
+}>;
+
+export type SportsListFacilitiesResponse = APIResponse<{
+  facilities: Facility[];
+}>;
+
+export type SportsPostFacilityBody = FacilityInput;
+
+export type SportsPostFacilityResponse = APIResponse<{
+  id: number;
+}>;
+
+export type SportsReplaceFacilityBody = FacilityInput;
+
+export type SportsReplaceFacilityResponse = APIResponse<{
+  facility: Facility | null;
+}>;
+
+export type SportsDeleteFacilityResponse = APIResponse<{
+  message: string;
+}>;
+
+export type SportsListBookingsResponse = APIResponse<{
+  bookings: Booking[];
+  max_active: number;
+}>;
+
+export type SportsPostBookingBody = BookingRequest;
+
+export type SportsPostBookingResponse = APIResponse<{
+  booking: Booking | null;
+}>;
+
+export type SportsDeleteBookingResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface SportsGetFacilitiesQuery {
+  lang?: QueryValue;
+}
+
+export type SportsGetFacilitiesResponse = APIResponse<{
+  facilities: Facility[];
+}>;
+
+export interface SportsGetFacilityQuery {
+  lang?: QueryValue;
+}
+
+export type SportsGetFacilityResponse = APIResponse<{
+  facility: Facility;
+}>;
+
+export interface SportsGetCourtsQuery {
+  lang?: QueryValue;
+  date?: QueryValue;
+}
+
+export type SportsGetCourtsResponse = APIResponse<{
+  date: string;
+  hours: OpeningHours[];
+  courts: Court[];
+}>;
+
+export interface SportsGetProgramQuery {
+  facility?: QueryValue;
+  day?: QueryValue;
+  lang?: QueryValue;
+}
+
+export type SportsGetProgramResponse = APIResponse<{
+  classes: Class[];
+}>;
+
+export type StaffListWorkspacesResponse = APIResponse<{
+  workspaces: CourseWorkspace[];
+}>;
+
+export type StaffCreateWorkspaceBody = WorkspaceCreateRequest;
+
+export type StaffCreateWorkspaceResponse = APIResponse<{
+  workspace: CourseWorkspace | null;
+}>;
+
+export type StaffGetWorkspaceResponse = APIResponse<{
+  workspace: CourseWorkspace | null;
+  students: WorkspaceMember[];
+}>;
+
+export type StaffDeleteWorkspaceResponse = APIResponse<{
+  message: string;
+}>;
+
+export type StaffEnrollStudentsBody = WorkspaceStudentsRequest;
+
+export type StaffEnrollStudentsResponse = APIResponse<{
+  message: string;
+  issued: IssuedWorkspaceToken[];
+  missing: string[];
+  alreadyEnrolled: string[];
+}>;
+
+export type StaffRemoveStudentResponse = APIResponse<{
+  message: string;
+}>;
+
+export type StaffGetWorkspaceUsageResponse = APIResponse<{
+  workspaceId: number;
+  endsAt: string;
+  students: number;
+  activeStudents: number;
+  totalRequests: number;
+  currentRpm: number;
+  byStudent: WorkspaceMember[];
+}>;
+
+export interface StreamStreamQuery {
+  lang?: QueryValue;
+  topics?: QueryValue;
+}
+
+export type TenantGetTenantResponse = APIResponse<{
+  id: string;
+  branding: {
+    name: string;
+    university: string;
+    website: string;
+    logoUrl: string;
+  };
+}>;
+
+export type WebhooksListWebhooksResponse = APIResponse<{
+  webhooks: Subscription[];
+  events: EventType[];
+}>;
+
+export type WebhooksPostWebhookBody = SubscriptionCreateRequest;
+
+export type WebhooksPostWebhookResponse = APIResponse<{
+  webhook: Subscription | null;
+  secret: string;
+}>;
+
+export type WebhooksGetWebhookResponse = APIResponse<{
+  webhook: Subscription | null;
+}>;
+
+export type WebhooksPatchWebhookBody = SubscriptionUpdateRequest;
+
+export type WebhooksPatchWebhookResponse = APIResponse<{
+  webhook: Subscription | null;
+}>;
+
+export type WebhooksDeleteWebhookResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface WebhooksListDeliveriesQuery {
+  limit?: QueryValue;
+  cursor?: QueryValue;
+}
+
+export type WebhooksListDeliveriesResponse = APIResponse<{
+  deliveries: Delivery[];
+  limit: number;
+}>;
+
+export type WebhooksTestWebhookResponse = APIResponse<{
+  message: string;
+}>;
+
+export interface WsConnectQuery {
+  lang?: QueryValue;
+}
+
+interface Init extends RequestOptions {
+  query?: object;
+  body?: unknown;
+}
+
+/** Client calls the API with fetch, one property per module */
+export class Client {
+  private readonly options: ClientOptions;
+
+  constructor(options: ClientOptions) {
+    this.options = { ...options, baseUrl: options.baseUrl.replace(/\/+$/, "") };
+  }
+
+  /** url returns the address of path with the query's parameters */
+  url(path: string, query?: object): string {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(query ?? {})) {
+      for (const v of Array.isArray(value) ? value : [value]) {
+        if (v !== undefined && v !== null) {
+          params.append(key, String(v));
+        }
+      }
+    }
+    const search = params.toString();
+    return this.options.baseUrl + path + (search ? "?" + search : "");
+  }
+
+  /** socketUrl returns the WebSocket address of path with the query's parameters */
+  socketUrl(path: string, query?: object): string {
+    return this.url(path, query).replace(/^http/, "ws");
+  }
+
+  /** raw sends a request and returns the response as it is, throwing ApiError for error statuses */
+  async raw(method: string, path: string, init: Init = {}): Promise<Response> {
+    return this.send(method, path, init, undefined);
+  }
+
+  /** request sends a request and returns the decoded APIResponse, throwing ApiError for error statuses */
+  async request<T>(method: string, path: string, init: Init = {}): Promise<T> {
+    const response = await this.send(method, path, init, "application/json");
+    return (await response.json()) as T;
+  }
+
+  private async send(method: string, path: string, init: Init, accept: string | undefined): Promise<Response> {
+    const headers: Record<string, string> = { ...this.options.headers, ...init.headers };
+    if (accept) {
+      headers["Accept"] = accept;
+    }
+    const token = typeof this.options.token === "function" ? await this.options.token() : this.options.token;
+    if (token) {
+      headers["Authorization"] = `Bearer ${token}`;
+    }
+    if (init.idempotencyKey) {
+      headers["Idempotency-Key"] = init.idempotencyKey;
+    }
+    let body: string | undefined;
+    if (init.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(init.body);
+    }
+
+    const send = this.options.fetch ?? fetch;
+    const response = await send(this.url(path, init.query), {
+      method,
+      headers,
+      body,
+      credentials: this.options.credentials,
+      signal: init.signal,
+    });
+    if (!response.ok) {
+      throw await ApiError.from(response);
+    }
+    return response;
+  }
+
+  readonly admin = {
+    /**
+     * ListAcademicDomains returns all academic domains
+     *
+     * `GET /api/admin/academic-domains`
+     */
+    listAcademicDomains: (options?: RequestOptions): Promise<AdminListAcademicDomainsResponse> =>
+      this.request("GET", `/api/admin/academic-domains`, { ...options }),
+    /**
+     * AddAcademicDomain adds an academic domain; a leading "*." matches every subdomain
+     *
+     * `POST /api/admin/academic-domains`
+     */
+    addAcademicDomain: (body: AdminAddAcademicDomainBody, options?: RequestOptions): Promise<AdminAddAcademicDomainResponse> =>
+      this.request("POST", `/api/admin/academic-domains`, { body, ...options }),
+    /**
+     * RemoveAcademicDomain removes an academic domain
+     *
+     * `DELETE /api/admin/academic-domains/:domain`
+     */
+    removeAcademicDomain: (domain: PathParam, options?: RequestOptions): Promise<AdminRemoveAcademicDomainResponse> =>
+      this.request("DELETE", `/api/admin/academic-domains/${encodeURIComponent(String(domain))}`, { ...options }),
+    /**
+     * ImportAcademicDomains adds many academic domains at once, either as JSON
+     * {"domains": [...]} or as a CSV/plain-text body with one domain per line
+     *
+     * `POST /api/admin/academic-domains/import`
+     */
+    importAcademicDomains: (body: AdminImportAcademicDomainsBody, options?: RequestOptions): Promise<AdminImportAcademicDomainsResponse> =>
+      this.request("POST", `/api/admin/academic-domains/import`, { body, ...options }),
+    /**
+     * ListAccessRequests returns feature access requests, optionally filtered by ?status=
+     *
+     * `GET /api/admin/access-requests`
+     */
+    listAccessRequests: (query?: AdminListAccessRequestsQuery, options?: RequestOptions): Promise<AdminListAccessRequestsResponse> =>
+      this.request("GET", `/api/admin/access-requests`, { query, ...options }),
+    /**
+     * ApproveAccessRequest grants the feature and adds it to the user's active tokens
+     *
+     * `POST /api/admin/access-requests/:id/approve`
+     */
+    approveAccessRequest: (id: PathParam, body: AdminApproveAccessRequestBody, options?: RequestOptions): Promise<AdminApproveAccessRequestResponse> =>
+      this.request("POST", `/api/admin/access-requests/${encodeURIComponent(String(id))}/approve`, { body, ...options }),
+    /**
+     * DenyAccessRequest turns down a pending access request
+     *
+     * `POST /api/admin/access-requests/:id/deny`
+     */
+    denyAccessRequest: (id: PathParam, body: AdminDenyAccessRequestBody, options?: RequestOptions): Promise<AdminDenyAccessRequestResponse> =>
+      this.request("POST", `/api/admin/access-requests/${encodeURIComponent(String(id))}/deny`, { body, ...options }),
+    /**
+     * RevokeAccessRequest withdraws an approved access request
+     *
+     * `POST /api/admin/access-requests/:id/revoke`
+     */
+    revokeAccessRequest: (id: PathParam, body: AdminRevokeAccessRequestBody, options?: RequestOptions): Promise<AdminRevokeAccessRequestResponse> =>
+      this.request("POST", `/api/admin/access-requests/${encodeURIComponent(String(id))}/revoke`, { body, ...options }),
+    /**
+     * GetRequestDiagnostic explains why a recent request was denied
+     *
+     * `GET /api/admin/diagnostics/requests/:id`
+     */
+    getRequestDiagnostic: (id: PathParam, options?: RequestOptions): Promise<AdminGetRequestDiagnosticResponse> =>
+      this.request("GET", `/api/admin/diagnostics/requests/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListErrors returns the latest requests that failed on the server's side, newest
+     * first, optionally only those with a status or of a route pattern (e.g.
+     * /api/v0/events/:id)
+     *
+     * `GET /api/admin/errors`
+     */
+    listErrors: (query?: AdminListErrorsQuery, options?: RequestOptions): Promise<AdminListErrorsResponse> =>
+      this.request("GET", `/api/admin/errors`, { query, ...options }),
+    /**
+     * ListFeatures returns all features
+     *
+     * `GET /api/admin/features`
+     */
+    listFeatures: (options?: RequestOptions): Promise<AdminListFeaturesResponse> =>
+      this.request("GET", `/api/admin/features`, { ...options }),
+    /**
+     * CreateFeature creates a new feature
+     *
+     * `POST /api/admin/features`
+     */
+    createFeature: (body: AdminCreateFeatureBody, options?: RequestOptions): Promise<AdminCreateFeatureResponse> =>
+      this.request("POST", `/api/admin/features`, { body, ...options }),
+    /**
+     * GetFeature returns a feature by ID
+     *
+     * `GET /api/admin/features/:id`
+     */
+    getFeature: (id: PathParam, options?: RequestOptions): Promise<AdminGetFeatureResponse> =>
+      this.request("GET", `/api/admin/features/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * UpdateFeature updates a feature
+     *
+     * `PATCH /api/admin/features/:id`
+     */
+    updateFeature: (id: PathParam, body: AdminUpdateFeatureBody, options?: RequestOptions): Promise<AdminUpdateFeatureResponse> =>
+      this.request("PATCH", `/api/admin/features/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteFeature deletes a feature
+     *
+     * `DELETE /api/admin/features/:id`
+     */
+    deleteFeature: (id: PathParam, options?: RequestOptions): Promise<AdminDeleteFeatureResponse> =>
+      this.request("DELETE", `/api/admin/features/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * GetFeatureUsage ranks the users and tokens sending the most requests to a
+     * feature (and its sub-features), so they can be contacted before it changes
+     *
+     * `GET /api/admin/features/:id/usage`
+     */
+    getFeatureUsage: (id: PathParam, query?: AdminGetFeatureUsageQuery, options?: RequestOptions): Promise<AdminGetFeatureUsageResponse> =>
+      this.request("GET", `/api/admin/features/${encodeURIComponent(String(id))}/usage`, { query, ...options }),
+    /**
+     * ListDeprecatedFeatureTokens lists active tokens still scoped to deprecated features
+     *
+     * `GET /api/admin/features/deprecated/tokens`
+     */
+    listDeprecatedFeatureTokens: (options?: RequestOptions): Promise<AdminListDeprecatedFeatureTokensResponse> =>
+      this.request("GET", `/api/admin/features/deprecated/tokens`, { ...options }),
+    /**
+     * GetFeatureTree returns all features nested under their parents
+     *
+     * `GET /api/admin/features/tree`
+     */
+    getFeatureTree: (options?: RequestOptions): Promise<AdminGetFeatureTreeResponse> =>
+      this.request("GET", `/api/admin/features/tree`, { ...options }),
+    /**
+     * ListGroups returns all groups, or the deleted ones with ?deleted=true
+     *
+     * `GET /api/admin/groups`
+     */
+    listGroups: (query?: AdminListGroupsQuery, options?: RequestOptions): Promise<AdminListGroupsResponse> =>
+      this.request("GET", `/api/admin/groups`, { query, ...options }),
+    /**
+     * CreateGroup creates a new group
+     *
+     * `POST /api/admin/groups`
+     */
+    createGroup: (body: AdminCreateGroupBody, options?: RequestOptions): Promise<AdminCreateGroupResponse> =>
+      this.request("POST", `/api/admin/groups`, { body, ...options }),
+    /**
+     * GetGroup returns a group by ID
+     *
+     * `GET /api/admin/groups/:id`
+     */
+    getGroup: (id: PathParam, options?: RequestOptions): Promise<AdminGetGroupResponse> =>
+      this.request("GET", `/api/admin/groups/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * UpdateGroup updates a group
+     *
+     * `PATCH /api/admin/groups/:id`
+     */
+    updateGroup: (id: PathParam, body: AdminUpdateGroupBody, options?: RequestOptions): Promise<AdminUpdateGroupResponse> =>
+      this.request("PATCH", `/api/admin/groups/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteGroup marks a group deleted; it can be restored
+     *
+     * `DELETE /api/admin/groups/:id`
+     */
+    deleteGroup: (id: PathParam, options?: RequestOptions): Promise<AdminDeleteGroupResponse> =>
+      this.request("DELETE", `/api/admin/groups/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * GetGroupQuotas returns quotas for a group
+     *
+     * `GET /api/admin/groups/:id/quotas`
+     */
+    getGroupQuotas: (id: PathParam, options?: RequestOptions): Promise<AdminGetGroupQuotasResponse> =>
+      this.request("GET", `/api/admin/groups/${encodeURIComponent(String(id))}/quotas`, { ...options }),
+    /**
+     * SetGroupQuotas sets quotas for a group
+     *
+     * `PUT /api/admin/groups/:id/quotas`
+     */
+    setGroupQuotas: (id: PathParam, body: AdminSetGroupQuotasBody, options?: RequestOptions): Promise<AdminSetGroupQuotasResponse> =>
+      this.request("PUT", `/api/admin/groups/${encodeURIComponent(String(id))}/quotas`, { body, ...options }),
+    /**
+     * RestoreGroup restores a deleted group
+     *
+     * `POST /api/admin/groups/:id/restore`
+     */
+    restoreGroup: (id: PathParam, options?: RequestOptions): Promise<AdminRestoreGroupResponse> =>
+      this.request("POST", `/api/admin/groups/${encodeURIComponent(String(id))}/restore`, { ...options }),
+    /**
+     * ListInvitations returns all invitations
+     *
+     * `GET /api/admin/invitations`
+     */
+    listInvitations: (options?: RequestOptions): Promise<AdminListInvitationsResponse> =>
+      this.request("GET", `/api/admin/invitations`, { ...options }),
+    /**
+     * CreateInvitation invites an email with a pre-assigned group and role
+     *
+     * `POST /api/admin/invitations`
+     */
+    createInvitation: (body: AdminCreateInvitationBody, options?: RequestOptions): Promise<AdminCreateInvitationResponse> =>
+      this.request("POST", `/api/admin/invitations`, { body, ...options }),
+    /**
+     * DeleteInvitation withdraws an invitation
+     *
+     * `DELETE /api/admin/invitations/:id`
+     */
+    deleteInvitation: (id: PathParam, options?: RequestOptions): Promise<AdminDeleteInvitationResponse> =>
+      this.request("DELETE", `/api/admin/invitations/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListSampledRequests returns the recorded requests, newest first, without their headers
+     * and bodies
+     *
+     * `GET /api/admin/sampling/requests`
+     */
+    listSampledRequests: (query?: AdminListSampledRequestsQuery, options?: RequestOptions): Promise<AdminListSampledRequestsResponse> =>
+      this.request("GET", `/api/admin/sampling/requests`, { query, ...options }),
+    /**
+     * GetSampledRequest returns a recorded request with its redacted headers and bodies
+     *
+     * `GET /api/admin/sampling/requests/:id`
+     */
+    getSampledRequest: (id: PathParam, options?: RequestOptions): Promise<AdminGetSampledRequestResponse> =>
+      this.request("GET", `/api/admin/sampling/requests/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListSamplingRules returns the sampling rules still running
+     *
+     * `GET /api/admin/sampling/rules`
+     */
+    listSamplingRules: (options?: RequestOptions): Promise<AdminListSamplingRulesResponse> =>
+      this.request("GET", `/api/admin/sampling/rules`, { ...options }),
+    /**
+     * CreateSamplingRule starts recording a share of the requests to a feature, of a user, or
+     * both, with their bodies, to reproduce a reported bug
+     *
+     * `POST /api/admin/sampling/rules`
+     */
+    createSamplingRule: (body: AdminCreateSamplingRuleBody, options?: RequestOptions): Promise<AdminCreateSamplingRuleResponse> =>
+      this.request("POST", `/api/admin/sampling/rules`, { body, ...options }),
+    /**
+     * DeleteSamplingRule stops a sampling rule; what it recorded stays until it expires
+     *
+     * `DELETE /api/admin/sampling/rules/:id`
+     */
+    deleteSamplingRule: (id: PathParam, options?: RequestOptions): Promise<AdminDeleteSamplingRuleResponse> =>
+      this.request("DELETE", `/api/admin/sampling/rules/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * GetStats returns aggregate statistics for the admin dashboard
+     *
+     * `GET /api/admin/stats`
+     */
+    getStats: (query?: AdminGetStatsQuery, options?: RequestOptions): Promise<AdminGetStatsResponse> =>
+      this.request("GET", `/api/admin/stats`, { query, ...options }),
+    /**
+     * ListSurgeWindows returns all surge windows, most recent first
+     *
+     * `GET /api/admin/surges`
+     */
+    listSurgeWindows: (options?: RequestOptions): Promise<AdminListSurgeWindowsResponse> =>
+      this.request("GET", `/api/admin/surges`, { ...options }),
+    /**
+     * CreateSurgeWindow schedules a surge window
+     *
+     * `POST /api/admin/surges`
+     */
+    createSurgeWindow: (body: AdminCreateSurgeWindowBody, options?: RequestOptions): Promise<AdminCreateSurgeWindowResponse> =>
+      this.request("POST", `/api/admin/surges`, { body, ...options }),
+    /**
+     * UpdateSurgeWindow replaces a surge window's schedule, multipliers and groups
+     *
+     * `PUT /api/admin/surges/:id`
+     */
+    updateSurgeWindow: (id: PathParam, body: AdminUpdateSurgeWindowBody, options?: RequestOptions): Promise<AdminUpdateSurgeWindowResponse> =>
+      this.request("PUT", `/api/admin/surges/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteSurgeWindow removes a surge window, ending it immediately if it is active
+     *
+     * `DELETE /api/admin/surges/:id`
+     */
+    deleteSurgeWindow: (id: PathParam, options?: RequestOptions): Promise<AdminDeleteSurgeWindowResponse> =>
+      this.request("DELETE", `/api/admin/surges/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListThrottledAddresses returns the client addresses that recently sent invalid tokens,
+     * those still blocked first
+     *
+     * `GET /api/admin/token-throttle`
+     */
+    listThrottledAddresses: (options?: RequestOptions): Promise<AdminListThrottledAddressesResponse> =>
+      this.request("GET", `/api/admin/token-throttle`, { ...options }),
+    /**
+     * LiftThrottle unblocks an address and forgets its failures, e.g. a campus NAT behind
+     * which a misconfigured client kept sending a revoked token. For IPv6, any address in
+     * the blocked /64 will do.
+     *
+     * `DELETE /api/admin/token-throttle/:ip`
+     */
+    liftThrottle: (ip: PathParam, options?: RequestOptions): Promise<AdminLiftThrottleResponse> =>
+      this.request("DELETE", `/api/admin/token-throttle/${encodeURIComponent(String(ip))}`, { ...options }),
+    /**
+     * RevokeToken revokes any token (admin)
+     *
+     * `DELETE /api/admin/tokens/:id`
+     */
+    revokeToken: (id: PathParam, options?: RequestOptions): Promise<AdminRevokeTokenResponse> =>
+      this.request("DELETE", `/api/admin/tokens/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * RestoreToken restores a revoked token that has not expired (admin)
+     *
+     * `POST /api/admin/tokens/:id/restore`
+     */
+    restoreToken: (id: PathParam, options?: RequestOptions): Promise<AdminRestoreTokenResponse> =>
+      this.request("POST", `/api/admin/tokens/${encodeURIComponent(String(id))}/restore`, { ...options }),
+    /**
+     * ListUsers returns users with search, filters and pagination
+     *
+     * `GET /api/admin/users`
+     */
+    listUsers: (query?: AdminListUsersQuery, options?: RequestOptions): Promise<AdminListUsersResponse> =>
+      this.request("GET", `/api/admin/users`, { query, ...options }),
+    /**
+     * GetUser returns a user by ID
+     *
+     * `GET /api/admin/users/:id`
+     */
+    getUser: (id: PathParam, options?: RequestOptions): Promise<AdminGetUserResponse> =>
+      this.request("GET", `/api/admin/users/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * UpdateUser updates a user
+     *
+     * `PATCH /api/admin/users/:id`
+     */
+    updateUser: (id: PathParam, body: AdminUpdateUserBody, options?: RequestOptions): Promise<AdminUpdateUserResponse> =>
+      this.request("PATCH", `/api/admin/users/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteUser marks a user deleted and signs them out; it can be restored
+     *
+     * `DELETE /api/admin/users/:id`
+     */
+    deleteUser: (id: PathParam, options?: RequestOptions): Promise<AdminDeleteUserResponse> =>
+      this.request("DELETE", `/api/admin/users/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * AddUserNote adds an admin-only note to a user
+     *
+     * `POST /api/admin/users/:id/notes`
+     */
+    addUserNote: (id: PathParam, body: AdminAddUserNoteBody, options?: RequestOptions): Promise<AdminAddUserNoteResponse> =>
+      this.request("POST", `/api/admin/users/${encodeURIComponent(String(id))}/notes`, { body, ...options }),
+    /**
+     * DeleteUserNote removes an admin note from a user
+     *
+     * `DELETE /api/admin/users/:id/notes/:noteId`
+     */
+    deleteUserNote: (id: PathParam, noteId: PathParam, options?: RequestOptions): Promise<AdminDeleteUserNoteResponse> =>
+      this.request("DELETE", `/api/admin/users/${encodeURIComponent(String(id))}/notes/${encodeURIComponent(String(noteId))}`, { ...options }),
+    /**
+     * GetUserQuotas returns quota overrides for a user
+     *
+     * `GET /api/admin/users/:id/quotas`
+     */
+    getUserQuotas: (id: PathParam, options?: RequestOptions): Promise<AdminGetUserQuotasResponse> =>
+      this.request("GET", `/api/admin/users/${encodeURIComponent(String(id))}/quotas`, { ...options }),
+    /**
+     * SetUserQuotas sets quota overrides for a user
+     *
+     * `PUT /api/admin/users/:id/quotas`
+     */
+    setUserQuotas: (id: PathParam, body: AdminSetUserQuotasBody, options?: RequestOptions): Promise<AdminSetUserQuotasResponse> =>
+      this.request("PUT", `/api/admin/users/${encodeURIComponent(String(id))}/quotas`, { body, ...options }),
+    /**
+     * RestoreUser restores a deleted user
+     *
+     * `POST /api/admin/users/:id/restore`
+     */
+    restoreUser: (id: PathParam, options?: RequestOptions): Promise<AdminRestoreUserResponse> =>
+      this.request("POST", `/api/admin/users/${encodeURIComponent(String(id))}/restore`, { ...options }),
+    /**
+     * SetUserTags replaces the admin tags on a user
+     *
+     * `PUT /api/admin/users/:id/tags`
+     */
+    setUserTags: (id: PathParam, body: AdminSetUserTagsBody, options?: RequestOptions): Promise<AdminSetUserTagsResponse> =>
+      this.request("PUT", `/api/admin/users/${encodeURIComponent(String(id))}/tags`, { body, ...options }),
+    /**
+     * ListUserTokens returns a page of a user's tokens (admin)
+     *
+     * `GET /api/admin/users/:id/tokens`
+     */
+    listUserTokens: (id: PathParam, query?: AdminListUserTokensQuery, options?: RequestOptions): Promise<AdminListUserTokensResponse> =>
+      this.request("GET", `/api/admin/users/${encodeURIComponent(String(id))}/tokens`, { query, ...options }),
+    /**
+     * CreateUserToken creates a token for a user (admin)
+     *
+     * `POST /api/admin/users/:id/tokens`
+     */
+    createUserToken: (id: PathParam, body: AdminCreateUserTokenBody, options?: RequestOptions): Promise<AdminCreateUserTokenResponse> =>
+      this.request("POST", `/api/admin/users/${encodeURIComponent(String(id))}/tokens`, { body, ...options }),
+    /**
+     * GetUserUsage returns usage statistics for a user
+     *
+     * `GET /api/admin/users/:id/usage`
+     */
+    getUserUsage: (id: PathParam, options?: RequestOptions): Promise<AdminGetUserUsageResponse> =>
+      this.request("GET", `/api/admin/users/${encodeURIComponent(String(id))}/usage`, { ...options }),
+    /**
+     * ListWebhooks returns all webhooks and the events they can subscribe to
+     *
+     * `GET /api/admin/webhooks`
+     */
+    listWebhooks: (options?: RequestOptions): Promise<AdminListWebhooksResponse> =>
+      this.request("GET", `/api/admin/webhooks`, { ...options }),
+    /**
+     * CreateWebhook registers a webhook. The signing secret is only returned here.
+     *
+     * `POST /api/admin/webhooks`
+     */
+    createWebhook: (body: AdminCreateWebhookBody, options?: RequestOptions): Promise<AdminCreateWebhookResponse> =>
+      this.request("POST", `/api/admin/webhooks`, { body, ...options }),
+    /**
+     * UpdateWebhook changes a webhook's URL, events, format or description, or pauses it
+     *
+     * `PATCH /api/admin/webhooks/:id`
+     */
+    updateWebhook: (id: PathParam, body: AdminUpdateWebhookBody, options?: RequestOptions): Promise<AdminUpdateWebhookResponse> =>
+      this.request("PATCH", `/api/admin/webhooks/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteWebhook removes a webhook and drops its undelivered events
+     *
+     * `DELETE /api/admin/webhooks/:id`
+     */
+    deleteWebhook: (id: PathParam, options?: RequestOptions): Promise<AdminDeleteWebhookResponse> =>
+      this.request("DELETE", `/api/admin/webhooks/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * TestWebhook sends a ping event to a webhook and reports whether it was accepted
+     *
+     * `POST /api/admin/webhooks/:id/test`
+     */
+    testWebhook: (id: PathParam, options?: RequestOptions): Promise<AdminTestWebhookResponse> =>
+      this.request("POST", `/api/admin/webhooks/${encodeURIComponent(String(id))}/test`, { ...options }),
+  };
+
+  readonly auth = {
+    /**
+     * ListAccessRequests returns the current user's feature access requests
+     *
+     * `GET /api/auth/access-requests`
+     */
+    listAccessRequests: (options?: RequestOptions): Promise<AuthListAccessRequestsResponse> =>
+      this.request("GET", `/api/auth/access-requests`, { ...options }),
+    /**
+     * RequestAccess asks admins for access to a feature that requires approval
+     *
+     * `POST /api/auth/access-requests`
+     */
+    requestAccess: (body: AuthRequestAccessBody, options?: RequestOptions): Promise<AuthRequestAccessResponse> =>
+      this.request("POST", `/api/auth/access-requests`, { body, ...options }),
+    /**
+     * Callback handles OAuth callback
+     *
+     * `GET /api/auth/callback/:provider`
+     */
+    callback: (provider: PathParam, query?: AuthCallbackQuery, options?: RequestOptions): Promise<AuthCallbackResponse> =>
+      this.request("GET", `/api/auth/callback/${encodeURIComponent(String(provider))}`, { query, ...options }),
+    /**
+     * GetAssignableFeatureTree returns the features users can assign to their tokens, nested
+     *
+     * `GET /api/auth/features/tree`
+     */
+    getAssignableFeatureTree: (options?: RequestOptions): Promise<AuthGetAssignableFeatureTreeResponse> =>
+      this.request("GET", `/api/auth/features/tree`, { ...options }),
+    /**
+     * Login initiates OAuth flow
+     *
+     * Returns the address to send the browser to, as the endpoint redirects.
+     *
+     * `GET /api/auth/login/:provider`
+     */
+    login: (provider: PathParam): string =>
+      this.url(`/api/auth/login/${encodeURIComponent(String(provider))}`),
+    /**
+     * Logout logs out the current user
+     *
+     * `GET /api/auth/logout`
+     */
+    logout: (options?: RequestOptions): Promise<AuthLogoutResponse> =>
+      this.request("GET", `/api/auth/logout`, { ...options }),
+    /**
+     * Me returns the current authenticated user
+     *
+     * `GET /api/auth/me`
+     */
+    me: (options?: RequestOptions): Promise<AuthMeResponse> =>
+      this.request("GET", `/api/auth/me`, { ...options }),
+    /**
+     * MyPermissions returns the evaluated permissions, reachable features with their
+     * limits and token allowance of the current user
+     *
+     * `GET /api/auth/me/permissions`
+     */
+    myPermissions: (options?: RequestOptions): Promise<AuthMyPermissionsResponse> =>
+      this.request("GET", `/api/auth/me/permissions`, { ...options }),
+    /**
+     * GetMyPreferences returns the current user's dietary preferences
+     *
+     * `GET /api/auth/me/preferences`
+     */
+    getMyPreferences: (options?: RequestOptions): Promise<AuthGetMyPreferencesResponse> =>
+      this.request("GET", `/api/auth/me/preferences`, { ...options }),
+    /**
+     * SetMyPreferences replaces the current user's dietary preferences
+     *
+     * `PUT /api/auth/me/preferences`
+     */
+    setMyPreferences: (body: AuthSetMyPreferencesBody, options?: RequestOptions): Promise<AuthSetMyPreferencesResponse> =>
+      this.request("PUT", `/api/auth/me/preferences`, { body, ...options }),
+    /**
+     * ListTokens returns a page of the current user's tokens
+     *
+     * `GET /api/auth/tokens`
+     */
+    listTokens: (query?: AuthListTokensQuery, options?: RequestOptions): Promise<AuthListTokensResponse> =>
+      this.request("GET", `/api/auth/tokens`, { query, ...options }),
+    /**
+     * CreateToken creates a new token for the current user
+     *
+     * `POST /api/auth/tokens`
+     */
+    createToken: (body: AuthCreateTokenBody, options?: RequestOptions): Promise<AuthCreateTokenResponse> =>
+      this.request("POST", `/api/auth/tokens`, { body, ...options }),
+    /**
+     * UpdateToken changes settings of a token owned by the current user. Enabling
+     * debugTiming adds a Server-Timing header to every response the token gets.
+     *
+     * `PATCH /api/auth/tokens/:id`
+     */
+    updateToken: (id: PathParam, body: AuthUpdateTokenBody, options?: RequestOptions): Promise<AuthUpdateTokenResponse> =>
+      this.request("PATCH", `/api/auth/tokens/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * RevokeToken revokes a token owned by the current user
+     *
+     * `DELETE /api/auth/tokens/:id`
+     */
+    revokeToken: (id: PathParam, options?: RequestOptions): Promise<AuthRevokeTokenResponse> =>
+      this.request("DELETE", `/api/auth/tokens/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListAssignableFeatures returns features that users can assign to their tokens
+     *
+     * `GET /api/auth/tokens/features`
+     */
+    listAssignableFeatures: (options?: RequestOptions): Promise<AuthListAssignableFeaturesResponse> =>
+      this.request("GET", `/api/auth/tokens/features`, { ...options }),
+    /**
+     * FeatureCatalog lists the features third-party developers can request on
+     * their tokens, with their documentation
+     *
+     * `GET /api/features`
+     */
+    featureCatalog: (options?: RequestOptions): Promise<AuthFeatureCatalogResponse> =>
+      this.request("GET", `/api/features`, { ...options }),
+  };
+
+  readonly backup = {
+    /**
+     * ListBackups returns the stored snapshots, newest first
+     *
+     * `GET /api/admin/backups`
+     */
+    listBackups: (options?: RequestOptions): Promise<BackupListBackupsResponse> =>
+      this.request("GET", `/api/admin/backups`, { ...options }),
+    /**
+     * CreateBackup snapshots every database now, outside the schedule
+     *
+     * `POST /api/admin/backups`
+     */
+    createBackup: (options?: RequestOptions): Promise<BackupCreateBackupResponse> =>
+      this.request("POST", `/api/admin/backups`, { ...options }),
+  };
+
+  readonly caldav = {
+    /**
+     * Get returns a calendar as an iCalendar feed, or one of its entries as a calendar of
+     * its own
+     *
+     * `GET /api/v0/caldav/*path`
+     */
+    get: (path: string, query?: CaldavGetQuery, options?: RequestOptions): Promise<Response> =>
+      this.raw("GET", `/api/v0/caldav/${encodeURI(path.replace(/^\/+/, ""))}`, { query, ...options }),
+  };
+
+  readonly common = {
+    /**
+     * ErrorCatalog lists every error code responses can carry
+     *
+     * `GET /api/errors`
+     */
+    errorCatalog: (options?: RequestOptions): Promise<CommonErrorCatalogResponse> =>
+      this.request("GET", `/api/errors`, { ...options }),
+    /** `GET /api/status` */
+    status: (options?: RequestOptions): Promise<CommonStatusResponse> =>
+      this.request("GET", `/api/status`, { ...options }),
+  };
+
+  readonly courses = {
+    /**
+     * PostCourse adds a course to a department's catalog
+     *
+     * `POST /api/v0/admin/courses`
+     */
+    postCourse: (body: CoursesPostCourseBody, options?: RequestOptions): Promise<CoursesPostCourseResponse> =>
+      this.request("POST", `/api/v0/admin/courses`, { body, ...options }),
+    /**
+     * ReplaceCourse overwrites a course and its instructors
+     *
+     * `PUT /api/v0/admin/courses/:id`
+     */
+    replaceCourse: (id: PathParam, body: CoursesReplaceCourseBody, options?: RequestOptions): Promise<CoursesReplaceCourseResponse> =>
+      this.request("PUT", `/api/v0/admin/courses/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteCourse deletes a course and its instructors
+     *
+     * `DELETE /api/v0/admin/courses/:id`
+     */
+    deleteCourse: (id: PathParam, options?: RequestOptions): Promise<CoursesDeleteCourseResponse> =>
+      this.request("DELETE", `/api/v0/admin/courses/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListDepartments returns every department with its translations
+     *
+     * `GET /api/v0/admin/courses/departments`
+     */
+    listDepartments: (options?: RequestOptions): Promise<CoursesListDepartmentsResponse> =>
+      this.request("GET", `/api/v0/admin/courses/departments`, { ...options }),
+    /**
+     * PostDepartment adds a department
+     *
+     * `POST /api/v0/admin/courses/departments`
+     */
+    postDepartment: (body: CoursesPostDepartmentBody, options?: RequestOptions): Promise<CoursesPostDepartmentResponse> =>
+      this.request("POST", `/api/v0/admin/courses/departments`, { body, ...options }),
+    /**
+     * ReplaceDepartment overwrites a department's code and names
+     *
+     * `PUT /api/v0/admin/courses/departments/:id`
+     */
+    replaceDepartment: (id: PathParam, body: CoursesReplaceDepartmentBody, options?: RequestOptions): Promise<CoursesReplaceDepartmentResponse> =>
+      this.request("PUT", `/api/v0/admin/courses/departments/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteDepartment deletes a department that has no courses left
+     *
+     * `DELETE /api/v0/admin/courses/departments/:id`
+     */
+    deleteDepartment: (id: PathParam, options?: RequestOptions): Promise<CoursesDeleteDepartmentResponse> =>
+      this.request("DELETE", `/api/v0/admin/courses/departments/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListCourses returns the courses matching the filters, newest first
+     *
+     * `GET /api/v0/courses`
+     */
+    listCourses: (query?: CoursesListCoursesQuery, options?: RequestOptions): Promise<CoursesListCoursesResponse> =>
+      this.request("GET", `/api/v0/courses`, { query, ...options }),
+    /**
+     * GetCourse returns one course with its instructors
+     *
+     * `GET /api/v0/courses/:id`
+     */
+    getCourse: (id: PathParam, query?: CoursesGetCourseQuery, options?: RequestOptions): Promise<CoursesGetCourseResponse> =>
+      this.request("GET", `/api/v0/courses/${encodeURIComponent(String(id))}`, { query, ...options }),
+    /**
+     * GetDepartments returns every department with a course catalog
+     *
+     * `GET /api/v0/courses/departments`
+     */
+    getDepartments: (query?: CoursesGetDepartmentsQuery, options?: RequestOptions): Promise<CoursesGetDepartmentsResponse> =>
+      this.request("GET", `/api/v0/courses/departments`, { query, ...options }),
+  };
+
+  readonly directory = {
+    /**
+     * PostImport replaces the directory with the one in the body, reporting what was created,
+     * updated and removed. ?dry_run=true reports it without changing anything.
+     *
+     * `POST /api/v0/admin/directory/import`
+     */
+    postImport: (body: DirectoryPostImportBody, query?: DirectoryPostImportQuery, options?: RequestOptions): Promise<DirectoryPostImportResponse> =>
+      this.request("POST", `/api/v0/admin/directory/import`, { body, query, ...options }),
+    /**
+     * GetDepartmentStaff returns the staff of a department by name
+     *
+     * `GET /api/v0/directory/departments/:code/staff`
+     */
+    getDepartmentStaff: (code: PathParam, query?: DirectoryGetDepartmentStaffQuery, options?: RequestOptions): Promise<DirectoryGetDepartmentStaffResponse> =>
+      this.request("GET", `/api/v0/directory/departments/${encodeURIComponent(String(code))}/staff`, { query, ...options }),
+    /**
+     * GetSchools returns every school with its departments
+     *
+     * `GET /api/v0/directory/schools`
+     */
+    getSchools: (query?: DirectoryGetSchoolsQuery, options?: RequestOptions): Promise<DirectoryGetSchoolsResponse> =>
+      this.request("GET", `/api/v0/directory/schools`, { query, ...options }),
+    /**
+     * SearchStaff returns the staff matching a search, newest first
+     *
+     * `GET /api/v0/directory/staff`
+     */
+    searchStaff: (query?: DirectorySearchStaffQuery, options?: RequestOptions): Promise<DirectorySearchStaffResponse> =>
+      this.request("GET", `/api/v0/directory/staff`, { query, ...options }),
+    /**
+     * GetStaff returns one staff member
+     *
+     * `GET /api/v0/directory/staff/:id`
+     */
+    getStaff: (id: PathParam, query?: DirectoryGetStaffQuery, options?: RequestOptions): Promise<DirectoryGetStaffResponse> =>
+      this.request("GET", `/api/v0/directory/staff/${encodeURIComponent(String(id))}`, { query, ...options }),
+  };
+
+  readonly eclass = {
+    /**
+     * GetCourses returns the courses the linked eClass account is enrolled in
+     *
+     * `GET /api/v0/eclass/courses`
+     */
+    getCourses: (options?: RequestOptions): Promise<EclassGetCoursesResponse> =>
+      this.request("GET", `/api/v0/eclass/courses`, { ...options }),
+    /**
+     * GetDeadlines returns the linked account's activities due in the next days (default
+     * 30, at most 180), soonest first
+     *
+     * `GET /api/v0/eclass/deadlines`
+     */
+    getDeadlines: (query?: EclassGetDeadlinesQuery, options?: RequestOptions): Promise<EclassGetDeadlinesResponse> =>
+      this.request("GET", `/api/v0/eclass/deadlines`, { query, ...options }),
+    /**
+     * GetDocuments returns the files posted to the linked account's courses in the last
+     * days (default 14, at most 90), newest first
+     *
+     * `GET /api/v0/eclass/documents`
+     */
+    getDocuments: (query?: EclassGetDocumentsQuery, options?: RequestOptions): Promise<EclassGetDocumentsResponse> =>
+      this.request("GET", `/api/v0/eclass/documents`, { query, ...options }),
+    /**
+     * GetLink returns the eClass account the current user linked
+     *
+     * `GET /api/v0/eclass/link`
+     */
+    getLink: (options?: RequestOptions): Promise<EclassGetLinkResponse> =>
+      this.request("GET", `/api/v0/eclass/link`, { ...options }),
+    /**
+     * PutLink links the current user's eClass account with their consent, replacing the
+     * one linked before. A password is only passed on to eClass in exchange for a key.
+     *
+     * `PUT /api/v0/eclass/link`
+     */
+    putLink: (body: EclassPutLinkBody, options?: RequestOptions): Promise<EclassPutLinkResponse> =>
+      this.request("PUT", `/api/v0/eclass/link`, { body, ...options }),
+    /**
+     * DeleteLink unlinks the current user's eClass account, withdrawing their consent. The
+     * key stays valid on eClass until the user resets it from their security keys page.
+     *
+     * `DELETE /api/v0/eclass/link`
+     */
+    deleteLink: (options?: RequestOptions): Promise<EclassDeleteLinkResponse> =>
+      this.request("DELETE", `/api/v0/eclass/link`, { ...options }),
+  };
+
+  readonly events = {
+    /**
+     * AdminListEvents returns the events matching the filters whatever their status, newest
+     * first. ?status=pending lists the submissions waiting for a decision.
+     *
+     * `GET /api/v0/admin/events`
+     */
+    adminListEvents: (query?: EventsAdminListEventsQuery, options?: RequestOptions): Promise<EventsAdminListEventsResponse> =>
+      this.request("GET", `/api/v0/admin/events`, { query, ...options }),
+    /**
+     * PostEvent adds an approved event
+     *
+     * `POST /api/v0/admin/events`
+     */
+    postEvent: (body: EventsPostEventBody, options?: RequestOptions): Promise<EventsPostEventResponse> =>
+      this.request("POST", `/api/v0/admin/events`, { body, ...options }),
+    /**
+     * ReplaceEvent overwrites the details of an event, e.g. to fix a submission before
+     * approving it
+     *
+     * `PUT /api/v0/admin/events/:id`
+     */
+    replaceEvent: (id: PathParam, body: EventsReplaceEventBody, options?: RequestOptions): Promise<EventsReplaceEventResponse> =>
+      this.request("PUT", `/api/v0/admin/events/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteEvent deletes an event
+     *
+     * `DELETE /api/v0/admin/events/:id`
+     */
+    deleteEvent: (id: PathParam, options?: RequestOptions): Promise<EventsDeleteEventResponse> =>
+      this.request("DELETE", `/api/v0/admin/events/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ApproveEvent lists a pending submission
+     *
+     * `POST /api/v0/admin/events/:id/approve`
+     */
+    approveEvent: (id: PathParam, body: EventsApproveEventBody, options?: RequestOptions): Promise<EventsApproveEventResponse> =>
+      this.request("POST", `/api/v0/admin/events/${encodeURIComponent(String(id))}/approve`, { body, ...options }),
+    /**
+     * RejectEvent turns down a pending submission, with an optional note for the submitter
+     *
+     * `POST /api/v0/admin/events/:id/reject`
+     */
+    rejectEvent: (id: PathParam, body: EventsRejectEventBody, options?: RequestOptions): Promise<EventsRejectEventResponse> =>
+      this.request("POST", `/api/v0/admin/events/${encodeURIComponent(String(id))}/reject`, { body, ...options }),
+    /**
+     * ListEvents returns the approved events matching the filters, newest first
+     *
+     * `GET /api/v0/events`
+     */
+    listEvents: (query?: EventsListEventsQuery, options?: RequestOptions): Promise<EventsListEventsResponse> =>
+      this.request("GET", `/api/v0/events`, { query, ...options }),
+    /**
+     * GetEvent returns one approved event
+     *
+     * `GET /api/v0/events/:id`
+     */
+    getEvent: (id: PathParam, query?: EventsGetEventQuery, options?: RequestOptions): Promise<EventsGetEventResponse> =>
+      this.request("GET", `/api/v0/events/${encodeURIComponent(String(id))}`, { query, ...options }),
+    /**
+     * ExportCalendar returns the approved events matching the filters as an iCalendar feed,
+     * from today to calendarDays ahead unless ?from= and ?to= say otherwise
+     *
+     * `GET /api/v0/events/calendar.ics`
+     */
+    exportCalendar: (query?: EventsExportCalendarQuery, options?: RequestOptions): Promise<Response> =>
+      this.raw("GET", `/api/v0/events/calendar.ics`, { query, ...options }),
+    /**
+     * ListSubmissions returns the events the current user submitted, newest first, with
+     * where each stands in the approval flow
+     *
+     * `GET /api/v0/events/submissions`
+     */
+    listSubmissions: (query?: EventsListSubmissionsQuery, options?: RequestOptions): Promise<EventsListSubmissionsResponse> =>
+      this.request("GET", `/api/v0/events/submissions`, { query, ...options }),
+    /**
+     * PostSubmission submits an event, which is listed once an admin approves it
+     *
+     * `POST /api/v0/events/submissions`
+     */
+    postSubmission: (body: EventsPostSubmissionBody, options?: RequestOptions): Promise<EventsPostSubmissionResponse> =>
+      this.request("POST", `/api/v0/events/submissions`, { body, ...options }),
+  };
+
+  readonly jobs = {
+    /**
+     * AdminListJobs returns the jobs matching the filters whatever their status and expiry,
+     * newest first. ?status=pending lists the submissions waiting for a decision.
+     *
+     * `GET /api/v0/admin/jobs`
+     */
+    adminListJobs: (query?: JobsAdminListJobsQuery, options?: RequestOptions): Promise<JobsAdminListJobsResponse> =>
+      this.request("GET", `/api/v0/admin/jobs`, { query, ...options }),
+    /**
+     * PostJob adds an approved job
+     *
+     * `POST /api/v0/admin/jobs`
+     */
+    postJob: (body: JobsPostJobBody, options?: RequestOptions): Promise<JobsPostJobResponse> =>
+      this.request("POST", `/api/v0/admin/jobs`, { body, ...options }),
+    /**
+     * ReplaceJob overwrites the details of a job, keeping its status, e.g. to fix a
+     * submission before approving it
+     *
+     * `PUT /api/v0/admin/jobs/:id`
+     */
+    replaceJob: (id: PathParam, body: JobsReplaceJobBody, options?: RequestOptions): Promise<JobsReplaceJobResponse> =>
+      this.request("PUT", `/api/v0/admin/jobs/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteJob deletes a job
+     *
+     * `DELETE /api/v0/admin/jobs/:id`
+     */
+    deleteJob: (id: PathParam, options?: RequestOptions): Promise<JobsDeleteJobResponse> =>
+      this.request("DELETE", `/api/v0/admin/jobs/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ApproveJob lists a pending submission
+     *
+     * `POST /api/v0/admin/jobs/:id/approve`
+     */
+    approveJob: (id: PathParam, options?: RequestOptions): Promise<JobsApproveJobResponse> =>
+      this.request("POST", `/api/v0/admin/jobs/${encodeURIComponent(String(id))}/approve`, { ...options }),
+    /**
+     * RejectJob turns down a pending submission, with an optional note for the submitter
+     *
+     * `POST /api/v0/admin/jobs/:id/reject`
+     */
+    rejectJob: (id: PathParam, options?: RequestOptions): Promise<JobsRejectJobResponse> =>
+      this.request("POST", `/api/v0/admin/jobs/${encodeURIComponent(String(id))}/reject`, { ...options }),
+    /**
+     * ListJobs returns the approved jobs that have not expired matching the filters, newest
+     * first
+     *
+     * `GET /api/v0/jobs`
+     */
+    listJobs: (query?: JobsListJobsQuery, options?: RequestOptions): Promise<JobsListJobsResponse> =>
+      this.request("GET", `/api/v0/jobs`, { query, ...options }),
+    /**
+     * GetJob returns one approved job that has not expired
+     *
+     * `GET /api/v0/jobs/:id`
+     */
+    getJob: (id: PathParam, options?: RequestOptions): Promise<JobsGetJobResponse> =>
+      this.request("GET", `/api/v0/jobs/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListSubmissions returns the jobs the current user submitted, expired ones included,
+     * newest first, with where each stands in the approval flow
+     *
+     * `GET /api/v0/jobs/submissions`
+     */
+    listSubmissions: (query?: JobsListSubmissionsQuery, options?: RequestOptions): Promise<JobsListSubmissionsResponse> =>
+      this.request("GET", `/api/v0/jobs/submissions`, { query, ...options }),
+    /**
+     * PostSubmission submits a job, which is listed once an admin approves it
+     *
+     * `POST /api/v0/jobs/submissions`
+     */
+    postSubmission: (body: JobsPostSubmissionBody, options?: RequestOptions): Promise<JobsPostSubmissionResponse> =>
+      this.request("POST", `/api/v0/jobs/submissions`, { body, ...options }),
+    /**
+     * ReplaceSubmission overwrites one of the current user's submissions, e.g. to answer a
+     * rejection note, and sends it back for approval
+     *
+     * `PUT /api/v0/jobs/submissions/:id`
+     */
+    replaceSubmission: (id: PathParam, body: JobsReplaceSubmissionBody, options?: RequestOptions): Promise<JobsReplaceSubmissionResponse> =>
+      this.request("PUT", `/api/v0/jobs/submissions/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteSubmission withdraws one of the current user's submissions
+     *
+     * `DELETE /api/v0/jobs/submissions/:id`
+     */
+    deleteSubmission: (id: PathParam, options?: RequestOptions): Promise<JobsDeleteSubmissionResponse> =>
+      this.request("DELETE", `/api/v0/jobs/submissions/${encodeURIComponent(String(id))}`, { ...options }),
+  };
+
+  readonly library = {
+    /**
+     * ListLibraries returns every library with its translations and occupancy
+     *
+     * `GET /api/v0/admin/library/libraries`
+     */
+    listLibraries: (options?: RequestOptions): Promise<LibraryListLibrariesResponse> =>
+      this.request("GET", `/api/v0/admin/library/libraries`, { ...options }),
+    /**
+     * PostLibrary adds a library with its opening hours
+     *
+     * `POST /api/v0/admin/library/libraries`
+     */
+    postLibrary: (body: LibraryPostLibraryBody, options?: RequestOptions): Promise<LibraryPostLibraryResponse> =>
+      this.request("POST", `/api/v0/admin/library/libraries`, { body, ...options }),
+    /**
+     * ReplaceLibrary overwrites a library and its opening hours
+     *
+     * `PUT /api/v0/admin/library/libraries/:id`
+     */
+    replaceLibrary: (id: PathParam, body: LibraryReplaceLibraryBody, options?: RequestOptions): Promise<LibraryReplaceLibraryResponse> =>
+      this.request("PUT", `/api/v0/admin/library/libraries/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteLibrary deletes a library with its rooms and their reservations
+     *
+     * `DELETE /api/v0/admin/library/libraries/:id`
+     */
+    deleteLibrary: (id: PathParam, options?: RequestOptions): Promise<LibraryDeleteLibraryResponse> =>
+      this.request("DELETE", `/api/v0/admin/library/libraries/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * PostRoom adds a study room to a library
+     *
+     * `POST /api/v0/admin/library/rooms`
+     */
+    postRoom: (body: LibraryPostRoomBody, options?: RequestOptions): Promise<LibraryPostRoomResponse> =>
+      this.request("POST", `/api/v0/admin/library/rooms`, { body, ...options }),
+    /**
+     * ReplaceRoom overwrites a study room
+     *
+     * `PUT /api/v0/admin/library/rooms/:id`
+     */
+    replaceRoom: (id: PathParam, body: LibraryReplaceRoomBody, options?: RequestOptions): Promise<LibraryReplaceRoomResponse> =>
+      this.request("PUT", `/api/v0/admin/library/rooms/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteRoom deletes a study room and its reservations
+     *
+     * `DELETE /api/v0/admin/library/rooms/:id`
+     */
+    deleteRoom: (id: PathParam, options?: RequestOptions): Promise<LibraryDeleteRoomResponse> =>
+      this.request("DELETE", `/api/v0/admin/library/rooms/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * GetLibraries returns every library with its opening hours and current occupancy
+     *
+     * `GET /api/v0/library/libraries`
+     */
+    getLibraries: (query?: LibraryGetLibrariesQuery, options?: RequestOptions): Promise<LibraryGetLibrariesResponse> =>
+      this.request("GET", `/api/v0/library/libraries`, { query, ...options }),
+    /**
+     * GetLibrary returns one library with its opening hours and current occupancy
+     *
+     * `GET /api/v0/library/libraries/:id`
+     */
+    getLibrary: (id: PathParam, query?: LibraryGetLibraryQuery, options?: RequestOptions): Promise<LibraryGetLibraryResponse> =>
+      this.request("GET", `/api/v0/library/libraries/${encodeURIComponent(String(id))}`, { query, ...options }),
+    /**
+     * PutOccupancy records the number of taken seats reported by a library's gate counters
+     *
+     * `PUT /api/v0/library/libraries/:id/occupancy`
+     */
+    putOccupancy: (id: PathParam, body: LibraryPutOccupancyBody, options?: RequestOptions): Promise<LibraryPutOccupancyResponse> =>
+      this.request("PUT", `/api/v0/library/libraries/${encodeURIComponent(String(id))}/occupancy`, { body, ...options }),
+    /**
+     * GetRooms returns the study rooms of a library with the periods they are booked on a
+     * day, today by default
+     *
+     * `GET /api/v0/library/libraries/:id/rooms`
+     */
+    getRooms: (id: PathParam, query?: LibraryGetRoomsQuery, options?: RequestOptions): Promise<LibraryGetRoomsResponse> =>
+      this.request("GET", `/api/v0/library/libraries/${encodeURIComponent(String(id))}/rooms`, { query, ...options }),
+    /**
+     * ListReservations returns the calling user's reservations that have not ended yet
+     *
+     * `GET /api/v0/library/reservations`
+     */
+    listReservations: (options?: RequestOptions): Promise<LibraryListReservationsResponse> =>
+      this.request("GET", `/api/v0/library/reservations`, { ...options }),
+    /**
+     * PostReservation books a study room for the calling user, within the library's opening
+     * hours and the BookingLimits
+     *
+     * `POST /api/v0/library/reservations`
+     */
+    postReservation: (body: LibraryPostReservationBody, options?: RequestOptions): Promise<LibraryPostReservationResponse> =>
+      this.request("POST", `/api/v0/library/reservations`, { body, ...options }),
+    /**
+     * DeleteReservation cancels one of the calling user's reservations that has not ended
+     *
+     * `DELETE /api/v0/library/reservations/:id`
+     */
+    deleteReservation: (id: PathParam, options?: RequestOptions): Promise<LibraryDeleteReservationResponse> =>
+      this.request("DELETE", `/api/v0/library/reservations/${encodeURIComponent(String(id))}`, { ...options }),
+  };
+
+  readonly maps = {
+    /**
+     * PostPOI adds a point of interest with its opening hours
+     *
+     * `POST /api/v0/admin/maps/pois`
+     */
+    postPOI: (body: MapsPostPOIBody, options?: RequestOptions): Promise<MapsPostPOIResponse> =>
+      this.request("POST", `/api/v0/admin/maps/pois`, { body, ...options }),
+    /**
+     * ReplacePOI overwrites a point of interest and its opening hours
+     *
+     * `PUT /api/v0/admin/maps/pois/:id`
+     */
+    replacePOI: (id: PathParam, body: MapsReplacePOIBody, options?: RequestOptions): Promise<MapsReplacePOIResponse> =>
+      this.request("PUT", `/api/v0/admin/maps/pois/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeletePOI deletes a point of interest and its opening hours
+     *
+     * `DELETE /api/v0/admin/maps/pois/:id`
+     */
+    deletePOI: (id: PathParam, options?: RequestOptions): Promise<MapsDeletePOIResponse> =>
+      this.request("DELETE", `/api/v0/admin/maps/pois/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListPOIs returns the points of interest matching the filters, newest first, or all of
+     * them as GeoJSON
+     *
+     * `GET /api/v0/maps/pois`
+     */
+    listPOIs: (query?: MapsListPOIsQuery, options?: RequestOptions): Promise<Response> =>
+      this.raw("GET", `/api/v0/maps/pois`, { query, ...options }),
+    /**
+     * GetPOI returns one point of interest with its opening hours
+     *
+     * `GET /api/v0/maps/pois/:id`
+     */
+    getPOI: (id: PathParam, query?: MapsGetPOIQuery, options?: RequestOptions): Promise<Response> =>
+      this.raw("GET", `/api/v0/maps/pois/${encodeURIComponent(String(id))}`, { query, ...options }),
+    /**
+     * GetNearbyPOIs returns the points of interest within a radius of a position, nearest first
+     *
+     * `GET /api/v0/maps/pois/near`
+     */
+    getNearbyPOIs: (query?: MapsGetNearbyPOIsQuery, options?: RequestOptions): Promise<Response> =>
+      this.raw("GET", `/api/v0/maps/pois/near`, { query, ...options }),
+    /**
+     * GetTile returns the points of interest within a Web Mercator tile as GeoJSON, for map
+     * clients that load the map tile by tile
+     *
+     * `GET /api/v0/maps/tiles/:z/:x/:y`
+     */
+    getTile: (z: PathParam, x: PathParam, y: PathParam, query?: MapsGetTileQuery, options?: RequestOptions): Promise<Response> =>
+      this.raw("GET", `/api/v0/maps/tiles/${encodeURIComponent(String(z))}/${encodeURIComponent(String(x))}/${encodeURIComponent(String(y))}`, { query, ...options }),
+  };
+
+  readonly news = {
+    /**
+     * ListSources returns every source with its feed and the outcome of its last fetch
+     *
+     * `GET /api/v0/admin/news/sources`
+     */
+    listSources: (options?: RequestOptions): Promise<NewsListSourcesResponse> =>
+      this.request("GET", `/api/v0/admin/news/sources`, { ...options }),
+    /**
+     * PostSource adds a source, fetched on the next round or through FetchSource
+     *
+     * `POST /api/v0/admin/news/sources`
+     */
+    postSource: (body: NewsPostSourceBody, options?: RequestOptions): Promise<NewsPostSourceResponse> =>
+      this.request("POST", `/api/v0/admin/news/sources`, { body, ...options }),
+    /**
+     * ReplaceSource overwrites a source; its items are kept
+     *
+     * `PUT /api/v0/admin/news/sources/:id`
+     */
+    replaceSource: (id: PathParam, body: NewsReplaceSourceBody, options?: RequestOptions): Promise<NewsReplaceSourceResponse> =>
+      this.request("PUT", `/api/v0/admin/news/sources/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteSource deletes a source and its items
+     *
+     * `DELETE /api/v0/admin/news/sources/:id`
+     */
+    deleteSource: (id: PathParam, options?: RequestOptions): Promise<NewsDeleteSourceResponse> =>
+      this.request("DELETE", `/api/v0/admin/news/sources/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * FetchSource fetches a source right away, e.g. to check a new feed, and reports what it
+     * found. Disabled sources can be fetched too.
+     *
+     * `POST /api/v0/admin/news/sources/:id/fetch`
+     */
+    fetchSource: (id: PathParam, options?: RequestOptions): Promise<NewsFetchSourceResponse> =>
+      this.request("POST", `/api/v0/admin/news/sources/${encodeURIComponent(String(id))}/fetch`, { ...options }),
+    /**
+     * GetFeed returns the items of every enabled source, newest first
+     *
+     * `GET /api/v0/news`
+     */
+    getFeed: (query?: NewsGetFeedQuery, options?: RequestOptions): Promise<NewsGetFeedResponse> =>
+      this.request("GET", `/api/v0/news`, { query, ...options }),
+    /**
+     * GetCategories returns the categories of the feed's items
+     *
+     * `GET /api/v0/news/categories`
+     */
+    getCategories: (options?: RequestOptions): Promise<NewsGetCategoriesResponse> =>
+      this.request("GET", `/api/v0/news/categories`, { ...options }),
+    /**
+     * GetSources returns the enabled sources, whose slugs filter the feed
+     *
+     * `GET /api/v0/news/sources`
+     */
+    getSources: (query?: NewsGetSourcesQuery, options?: RequestOptions): Promise<NewsGetSourcesResponse> =>
+      this.request("GET", `/api/v0/news/sources`, { query, ...options }),
+  };
+
+  readonly postings = {
+    /**
+     * ApprovePosting lists a pending posting
+     *
+     * `POST /api/v0/admin/postings/:id/approve`
+     */
+    approvePosting: (id: PathParam, options?: RequestOptions): Promise<PostingsApprovePostingResponse> =>
+      this.request("POST", `/api/v0/admin/postings/${encodeURIComponent(String(id))}/approve`, { ...options }),
+    /**
+     * RejectPosting turns down a pending posting, with an optional note for its editor
+     *
+     * `POST /api/v0/admin/postings/:id/reject`
+     */
+    rejectPosting: (id: PathParam, options?: RequestOptions): Promise<PostingsRejectPostingResponse> =>
+      this.request("POST", `/api/v0/admin/postings/${encodeURIComponent(String(id))}/reject`, { ...options }),
+    /**
+     * ListEditorPostings returns the calling editor's postings, expired ones included, newest
+     * first, with where each stands in the approval flow. Admins get every editor's postings,
+     * and ?status=pending lists those waiting for a decision.
+     *
+     * `GET /api/v0/editor/postings`
+     */
+    listEditorPostings: (query?: PostingsListEditorPostingsQuery, options?: RequestOptions): Promise<PostingsListEditorPostingsResponse> =>
+      this.request("GET", `/api/v0/editor/postings`, { query, ...options }),
+    /**
+     * PostPosting publishes a posting, which is listed once an admin approves it. Postings
+     * by admins are approved right away.
+     *
+     * `POST /api/v0/editor/postings`
+     */
+    postPosting: (body: PostingsPostPostingBody, options?: RequestOptions): Promise<PostingsPostPostingResponse> =>
+      this.request("POST", `/api/v0/editor/postings`, { body, ...options }),
+    /**
+     * ReplacePosting overwrites one of the calling editor's postings, e.g. to extend its
+     * deadline, and sends it back for approval. Admins can replace any posting, keeping
+     * its status.
+     *
+     * `PUT /api/v0/editor/postings/:id`
+     */
+    replacePosting: (id: PathParam, body: PostingsReplacePostingBody, options?: RequestOptions): Promise<PostingsReplacePostingResponse> =>
+      this.request("PUT", `/api/v0/editor/postings/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeletePosting deletes one of the calling editor's postings. Admins can delete any
+     * posting.
+     *
+     * `DELETE /api/v0/editor/postings/:id`
+     */
+    deletePosting: (id: PathParam, options?: RequestOptions): Promise<PostingsDeletePostingResponse> =>
+      this.request("DELETE", `/api/v0/editor/postings/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * GetPostings returns the postings that have not expired matching the filters, newest
+     * first. ?open=true leaves out those whose deadline has passed.
+     *
+     * `GET /api/v0/postings`
+     */
+    getPostings: (query?: PostingsGetPostingsQuery, options?: RequestOptions): Promise<PostingsGetPostingsResponse> =>
+      this.request("GET", `/api/v0/postings`, { query, ...options }),
+    /**
+     * GetPosting returns one approved posting that has not expired
+     *
+     * `GET /api/v0/postings/:id`
+     */
+    getPosting: (id: PathParam, options?: RequestOptions): Promise<PostingsGetPostingResponse> =>
+      this.request("GET", `/api/v0/postings/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * GetTags returns the tags of the postings that have not expired, most used first,
+     * optionally of one kind or department
+     *
+     * `GET /api/v0/postings/tags`
+     */
+    getTags: (query?: PostingsGetTagsQuery, options?: RequestOptions): Promise<PostingsGetTagsResponse> =>
+      this.request("GET", `/api/v0/postings/tags`, { query, ...options }),
+  };
+
+  readonly printing = {
+    /**
+     * ListAlerts returns the open toner and paper alerts, oldest first. ?since= (RFC 3339)
+     * adds the resolved alerts raised since then.
+     *
+     * `GET /api/v0/admin/printing/alerts`
+     */
+    listAlerts: (query?: PrintingListAlertsQuery, options?: RequestOptions): Promise<PrintingListAlertsResponse> =>
+      this.request("GET", `/api/v0/admin/printing/alerts`, { query, ...options }),
+    /**
+     * AcknowledgeAlert marks an open alert as seen by the calling admin, so others know it
+     * is being seen to. The alert stays open until a report finds the supply refilled.
+     *
+     * `POST /api/v0/admin/printing/alerts/:id/acknowledge`
+     */
+    acknowledgeAlert: (id: PathParam, options?: RequestOptions): Promise<PrintingAcknowledgeAlertResponse> =>
+      this.request("POST", `/api/v0/admin/printing/alerts/${encodeURIComponent(String(id))}/acknowledge`, { ...options }),
+    /**
+     * ListStations returns every station with its translations and last reported status,
+     * however old
+     *
+     * `GET /api/v0/admin/printing/stations`
+     */
+    listStations: (options?: RequestOptions): Promise<PrintingListStationsResponse> =>
+      this.request("GET", `/api/v0/admin/printing/stations`, { ...options }),
+    /**
+     * PostStation adds a print station
+     *
+     * `POST /api/v0/admin/printing/stations`
+     */
+    postStation: (body: PrintingPostStationBody, options?: RequestOptions): Promise<PrintingPostStationResponse> =>
+      this.request("POST", `/api/v0/admin/printing/stations`, { body, ...options }),
+    /**
+     * ReplaceStation overwrites a print station
+     *
+     * `PUT /api/v0/admin/printing/stations/:id`
+     */
+    replaceStation: (id: PathParam, body: PrintingReplaceStationBody, options?: RequestOptions): Promise<PrintingReplaceStationResponse> =>
+      this.request("PUT", `/api/v0/admin/printing/stations/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteStation deletes a print station with its alerts
+     *
+     * `DELETE /api/v0/admin/printing/stations/:id`
+     */
+    deleteStation: (id: PathParam, options?: RequestOptions): Promise<PrintingDeleteStationResponse> =>
+      this.request("DELETE", `/api/v0/admin/printing/stations/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * GetStations returns every print station with its state and queue length
+     *
+     * `GET /api/v0/printing/stations`
+     */
+    getStations: (query?: PrintingGetStationsQuery, options?: RequestOptions): Promise<PrintingGetStationsResponse> =>
+      this.request("GET", `/api/v0/printing/stations`, { query, ...options }),
+    /**
+     * GetStation returns one print station with its state and queue length
+     *
+     * `GET /api/v0/printing/stations/:id`
+     */
+    getStation: (id: PathParam, query?: PrintingGetStationQuery, options?: RequestOptions): Promise<PrintingGetStationResponse> =>
+      this.request("GET", `/api/v0/printing/stations/${encodeURIComponent(String(id))}`, { query, ...options }),
+    /**
+     * PutStatus records the print server's report on its stations, raising and resolving
+     * their toner and paper alerts. Stations are matched by code; reports on unknown codes
+     * are skipped and listed in the response.
+     *
+     * `PUT /api/v0/printing/status`
+     */
+    putStatus: (body: PrintingPutStatusBody, options?: RequestOptions): Promise<PrintingPutStatusResponse> =>
+      this.request("PUT", `/api/v0/printing/status`, { body, ...options }),
+  };
+
+  readonly schedule = {
+    /** `POST /api/v0/admin/announcements` */
+    postAnnouncement: (body: SchedulePostAnnouncementBody, options?: RequestOptions): Promise<SchedulePostAnnouncementResponse> =>
+      this.request("POST", `/api/v0/admin/announcements`, { body, ...options }),
+    /**
+     * UpdateAnnouncement changes an announcement, e.g. to fix a typo or reschedule it
+     *
+     * `PATCH /api/v0/admin/announcements/:id`
+     */
+    updateAnnouncement: (id: PathParam, body: ScheduleUpdateAnnouncementBody, options?: RequestOptions): Promise<ScheduleUpdateAnnouncementResponse> =>
+      this.request("PATCH", `/api/v0/admin/announcements/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteAnnouncement deletes an announcement and its receipts
+     *
+     * `DELETE /api/v0/admin/announcements/:id`
+     */
+    deleteAnnouncement: (id: PathParam, options?: RequestOptions): Promise<ScheduleDeleteAnnouncementResponse> =>
+      this.request("DELETE", `/api/v0/admin/announcements/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListChannels returns the chat channels the daily menu is posted to
+     *
+     * `GET /api/v0/admin/channels`
+     */
+    listChannels: (options?: RequestOptions): Promise<ScheduleListChannelsResponse> =>
+      this.request("GET", `/api/v0/admin/channels`, { ...options }),
+    /**
+     * PostChannel adds a Discord webhook or Telegram chat to post the daily menu to. New
+     * channels are active unless the body says otherwise.
+     *
+     * `POST /api/v0/admin/channels`
+     */
+    postChannel: (body: SchedulePostChannelBody, options?: RequestOptions): Promise<SchedulePostChannelResponse> =>
+      this.request("POST", `/api/v0/admin/channels`, { body, ...options }),
+    /**
+     * GetChannel returns a channel with the outcome of its last post
+     *
+     * `GET /api/v0/admin/channels/:id`
+     */
+    getChannel: (id: PathParam, options?: RequestOptions): Promise<ScheduleGetChannelResponse> =>
+      this.request("GET", `/api/v0/admin/channels/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * UpdateChannel changes a channel's target, language, post time or template, or enables
+     * or disables it
+     *
+     * `PATCH /api/v0/admin/channels/:id`
+     */
+    updateChannel: (id: PathParam, body: ScheduleUpdateChannelBody, options?: RequestOptions): Promise<ScheduleUpdateChannelResponse> =>
+      this.request("PATCH", `/api/v0/admin/channels/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteChannel stops posting to a channel and forgets it
+     *
+     * `DELETE /api/v0/admin/channels/:id`
+     */
+    deleteChannel: (id: PathParam, options?: RequestOptions): Promise<ScheduleDeleteChannelResponse> =>
+      this.request("DELETE", `/api/v0/admin/channels/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * TestChannel posts today's menu to a channel right away, so admins can check the target
+     * and template. It does not count as the day's post.
+     *
+     * `POST /api/v0/admin/channels/:id/test`
+     */
+    testChannel: (id: PathParam, options?: RequestOptions): Promise<ScheduleTestChannelResponse> =>
+      this.request("POST", `/api/v0/admin/channels/${encodeURIComponent(String(id))}/test`, { ...options }),
+    /**
+     * ListClosures returns closures with pagination, only those not over yet with ?upcoming=true
+     *
+     * `GET /api/v0/admin/closures`
+     */
+    listClosures: (query?: ScheduleListClosuresQuery, options?: RequestOptions): Promise<ScheduleListClosuresResponse> =>
+      this.request("GET", `/api/v0/admin/closures`, { query, ...options }),
+    /**
+     * PostClosure closes the cafeteria on a date or range, optionally with a replacement menu
+     *
+     * `POST /api/v0/admin/closures`
+     */
+    postClosure: (body: SchedulePostClosureBody, options?: RequestOptions): Promise<SchedulePostClosureResponse> =>
+      this.request("POST", `/api/v0/admin/closures`, { body, ...options }),
+    /**
+     * GetClosure returns a closure with its replacement menu
+     *
+     * `GET /api/v0/admin/closures/:id`
+     */
+    getClosure: (id: PathParam, options?: RequestOptions): Promise<ScheduleGetClosureResponse> =>
+      this.request("GET", `/api/v0/admin/closures/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ReplaceClosure overwrites a closure's dates, reason and replacement menu
+     *
+     * `PUT /api/v0/admin/closures/:id`
+     */
+    replaceClosure: (id: PathParam, body: ScheduleReplaceClosureBody, options?: RequestOptions): Promise<ScheduleReplaceClosureResponse> =>
+      this.request("PUT", `/api/v0/admin/closures/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteClosure deletes a closure, so the rotation serves its dates again
+     *
+     * `DELETE /api/v0/admin/closures/:id`
+     */
+    deleteClosure: (id: PathParam, options?: RequestOptions): Promise<ScheduleDeleteClosureResponse> =>
+      this.request("DELETE", `/api/v0/admin/closures/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListFoods returns foods with name search and pagination
+     *
+     * `GET /api/v0/admin/foods`
+     */
+    listFoods: (query?: ScheduleListFoodsQuery, options?: RequestOptions): Promise<ScheduleListFoodsResponse> =>
+      this.request("GET", `/api/v0/admin/foods`, { query, ...options }),
+    /** `POST /api/v0/admin/foods` */
+    postFood: (body: SchedulePostFoodBody, options?: RequestOptions): Promise<SchedulePostFoodResponse> =>
+      this.request("POST", `/api/v0/admin/foods`, { body, ...options }),
+    /**
+     * GetFood returns one food
+     *
+     * `GET /api/v0/admin/foods/:id`
+     */
+    getFood: (id: PathParam, options?: RequestOptions): Promise<ScheduleGetFoodResponse> =>
+      this.request("GET", `/api/v0/admin/foods/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * UpdateFood changes a food's names, nutrition, allergens or tags
+     *
+     * `PATCH /api/v0/admin/foods/:id`
+     */
+    updateFood: (id: PathParam, body: ScheduleUpdateFoodBody, options?: RequestOptions): Promise<ScheduleUpdateFoodResponse> =>
+      this.request("PATCH", `/api/v0/admin/foods/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteFood deletes a food that no schedule item or closure serves
+     *
+     * `DELETE /api/v0/admin/foods/:id`
+     */
+    deleteFood: (id: PathParam, options?: RequestOptions): Promise<ScheduleDeleteFoodResponse> =>
+      this.request("DELETE", `/api/v0/admin/foods/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * PutFoodImage replaces a food's image with a multipart upload in the "image" field,
+     * stored as resized variants
+     *
+     * `PUT /api/v0/admin/foods/:id/image`
+     */
+    putFoodImage: (id: PathParam, options?: RequestOptions): Promise<SchedulePutFoodImageResponse> =>
+      this.request("PUT", `/api/v0/admin/foods/${encodeURIComponent(String(id))}/image`, { ...options }),
+    /**
+     * DeleteFoodImage removes a food's image
+     *
+     * `DELETE /api/v0/admin/foods/:id/image`
+     */
+    deleteFoodImage: (id: PathParam, options?: RequestOptions): Promise<ScheduleDeleteFoodImageResponse> =>
+      this.request("DELETE", `/api/v0/admin/foods/${encodeURIComponent(String(id))}/image`, { ...options }),
+    /**
+     * PostImport imports a complete menu as a new version. Importing a menu whose items match an
+     * existing version returns that version with 200 instead of publishing a duplicate.
+     *
+     * `POST /api/v0/admin/imports`
+     */
+    postImport: (body: SchedulePostImportBody, options?: RequestOptions): Promise<SchedulePostImportResponse> =>
+      this.request("POST", `/api/v0/admin/imports`, { body, ...options }),
+    /** `POST /api/v0/admin/items` */
+    postSchedule: (body: SchedulePostScheduleBody, options?: RequestOptions): Promise<SchedulePostScheduleResponse> =>
+      this.request("POST", `/api/v0/admin/items`, { body, ...options }),
+    /**
+     * GetScheduleItem returns a schedule item with its dish list
+     *
+     * `GET /api/v0/admin/items/:id`
+     */
+    getScheduleItem: (id: PathParam, options?: RequestOptions): Promise<ScheduleGetScheduleItemResponse> =>
+      this.request("GET", `/api/v0/admin/items/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ReplaceScheduleItem sets every field of a schedule item
+     *
+     * `PUT /api/v0/admin/items/:id`
+     */
+    replaceScheduleItem: (id: PathParam, body: ScheduleReplaceScheduleItemBody, options?: RequestOptions): Promise<ScheduleReplaceScheduleItemResponse> =>
+      this.request("PUT", `/api/v0/admin/items/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * UpdateScheduleItem changes the fields of a schedule item that are set
+     *
+     * `PATCH /api/v0/admin/items/:id`
+     */
+    updateScheduleItem: (id: PathParam, body: ScheduleUpdateScheduleItemBody, options?: RequestOptions): Promise<ScheduleUpdateScheduleItemResponse> =>
+      this.request("PATCH", `/api/v0/admin/items/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteScheduleItem deletes a schedule item and its dish list
+     *
+     * `DELETE /api/v0/admin/items/:id`
+     */
+    deleteScheduleItem: (id: PathParam, options?: RequestOptions): Promise<ScheduleDeleteScheduleItemResponse> =>
+      this.request("DELETE", `/api/v0/admin/items/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListRestaurants returns every restaurant with its serving hours and translations
+     *
+     * `GET /api/v0/admin/restaurants`
+     */
+    listRestaurants: (options?: RequestOptions): Promise<ScheduleListRestaurantsResponse> =>
+      this.request("GET", `/api/v0/admin/restaurants`, { ...options }),
+    /**
+     * PostRestaurant adds a restaurant with its serving hours
+     *
+     * `POST /api/v0/admin/restaurants`
+     */
+    postRestaurant: (body: SchedulePostRestaurantBody, options?: RequestOptions): Promise<SchedulePostRestaurantResponse> =>
+      this.request("POST", `/api/v0/admin/restaurants`, { body, ...options }),
+    /**
+     * GetRestaurant returns a restaurant with its serving hours
+     *
+     * `GET /api/v0/admin/restaurants/:id`
+     */
+    getRestaurant: (id: PathParam, options?: RequestOptions): Promise<ScheduleGetRestaurantResponse> =>
+      this.request("GET", `/api/v0/admin/restaurants/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ReplaceRestaurant overwrites a restaurant's names and serving hours
+     *
+     * `PUT /api/v0/admin/restaurants/:id`
+     */
+    replaceRestaurant: (id: PathParam, body: ScheduleReplaceRestaurantBody, options?: RequestOptions): Promise<ScheduleReplaceRestaurantResponse> =>
+      this.request("PUT", `/api/v0/admin/restaurants/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteRestaurant deletes a restaurant and its serving hours
+     *
+     * `DELETE /api/v0/admin/restaurants/:id`
+     */
+    deleteRestaurant: (id: PathParam, options?: RequestOptions): Promise<ScheduleDeleteRestaurantResponse> =>
+      this.request("DELETE", `/api/v0/admin/restaurants/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListReviews returns reviews for moderation, optionally of one food or by hidden state
+     *
+     * `GET /api/v0/admin/reviews`
+     */
+    listReviews: (query?: ScheduleListReviewsQuery, options?: RequestOptions): Promise<ScheduleListReviewsResponse> =>
+      this.request("GET", `/api/v0/admin/reviews`, { query, ...options }),
+    /**
+     * ModerateReview hides a review from listings and averages, or shows it again
+     *
+     * `PATCH /api/v0/admin/reviews/:id`
+     */
+    moderateReview: (id: PathParam, body: ScheduleModerateReviewBody, options?: RequestOptions): Promise<ScheduleModerateReviewResponse> =>
+      this.request("PATCH", `/api/v0/admin/reviews/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteReview deletes a review
+     *
+     * `DELETE /api/v0/admin/reviews/:id`
+     */
+    deleteReview: (id: PathParam, options?: RequestOptions): Promise<ScheduleDeleteReviewResponse> =>
+      this.request("DELETE", `/api/v0/admin/reviews/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * PostMenuDraft imports a menu draft returned by PostMenuScan, once the admin has
+     * corrected it, as a new version. It takes ?dry_run=true like PostSpreadsheetImport.
+     *
+     * `POST /api/v0/admin/schedule/draft`
+     */
+    postMenuDraft: (body: SchedulePostMenuDraftBody, query?: SchedulePostMenuDraftQuery, options?: RequestOptions): Promise<SchedulePostMenuDraftResponse> =>
+      this.request("POST", `/api/v0/admin/schedule/draft`, { body, query, ...options }),
+    /**
+     * PostSpreadsheetImport imports a menu spreadsheet, as sent by the catering office, as a new
+     * version. With ?dry_run=true the file is only validated and nothing is created.
+     *
+     * `POST /api/v0/admin/schedule/import`
+     */
+    postSpreadsheetImport: (body: SchedulePostSpreadsheetImportBody, query?: SchedulePostSpreadsheetImportQuery, options?: RequestOptions): Promise<SchedulePostSpreadsheetImportResponse> =>
+      this.request("POST", `/api/v0/admin/schedule/import`, { body, query, ...options }),
+    /**
+     * PostMenuScan recognizes the text of a photographed or scanned menu and returns it as a
+     * draft of meal slots, which the admin corrects and confirms with PostMenuDraft. Nothing
+     * is stored.
+     *
+     * `POST /api/v0/admin/schedule/ocr`
+     */
+    postMenuScan: (body: SchedulePostMenuScanBody, options?: RequestOptions): Promise<SchedulePostMenuScanResponse> =>
+      this.request("POST", `/api/v0/admin/schedule/ocr`, { body, ...options }),
+    /** `POST /api/v0/admin/versions` */
+    postVersion: (body: SchedulePostVersionBody, options?: RequestOptions): Promise<SchedulePostVersionResponse> =>
+      this.request("POST", `/api/v0/admin/versions`, { body, ...options }),
+    /**
+     * ListAnnouncements returns announcements with filters and pagination
+     *
+     * `GET /api/v0/announcements`
+     */
+    listAnnouncements: (query?: ScheduleListAnnouncementsQuery, options?: RequestOptions): Promise<ScheduleListAnnouncementsResponse> =>
+      this.request("GET", `/api/v0/announcements`, { query, ...options }),
+    /**
+     * GetImage serves a stored image variant, or redirects to a signed URL of it when
+     * the images are in a private bucket. Keys are unique per upload, so clients may
+     * cache them indefinitely.
+     *
+     * `GET /api/v0/images/*key`
+     */
+    getImage: (key: string, options?: RequestOptions): Promise<Response> =>
+      this.raw("GET", `/api/v0/images/${encodeURI(key.replace(/^\/+/, ""))}`, { ...options }),
+    /** `GET /api/v0/schedule` */
+    getSchedule: (query?: ScheduleGetScheduleQuery, options?: RequestOptions): Promise<ScheduleGetScheduleResponse> =>
+      this.request("GET", `/api/v0/schedule`, { query, ...options }),
+    /**
+     * PostAnnouncementReceipts marks announcements as delivered or seen for the token's user
+     *
+     * `POST /api/v0/schedule/announcements/receipts`
+     */
+    postAnnouncementReceipts: (body: SchedulePostAnnouncementReceiptsBody, options?: RequestOptions): Promise<SchedulePostAnnouncementReceiptsResponse> =>
+      this.request("POST", `/api/v0/schedule/announcements/receipts`, { body, ...options }),
+    /**
+     * GetUnseenAnnouncements returns the running announcements the token's user (or device, via ?device_id=) has not seen
+     *
+     * `GET /api/v0/schedule/announcements/unseen`
+     */
+    getUnseenAnnouncements: (query?: ScheduleGetUnseenAnnouncementsQuery, options?: RequestOptions): Promise<ScheduleGetUnseenAnnouncementsResponse> =>
+      this.request("GET", `/api/v0/schedule/announcements/unseen`, { query, ...options }),
+    /**
+     * GetChanges lists the dishes added to and removed from the menus of the coming four weeks
+     * by edits made after ?since=, so clients can tell users what changed
+     *
+     * `GET /api/v0/schedule/changes`
+     */
+    getChanges: (query?: ScheduleGetChangesQuery, options?: RequestOptions): Promise<ScheduleGetChangesResponse> =>
+      this.request("GET", `/api/v0/schedule/changes`, { query, ...options }),
+    /**
+     * ListUpcomingClosures returns the closures that are not over yet, so apps can warn ahead
+     *
+     * `GET /api/v0/schedule/closures`
+     */
+    listUpcomingClosures: (query?: ScheduleListUpcomingClosuresQuery, options?: RequestOptions): Promise<ScheduleListUpcomingClosuresResponse> =>
+      this.request("GET", `/api/v0/schedule/closures`, { query, ...options }),
+    /**
+     * ListFavorites returns the foods the token's user marked as favorites
+     *
+     * `GET /api/v0/schedule/favorites`
+     */
+    listFavorites: (query?: ScheduleListFavoritesQuery, options?: RequestOptions): Promise<ScheduleListFavoritesResponse> =>
+      this.request("GET", `/api/v0/schedule/favorites`, { query, ...options }),
+    /**
+     * GetNextFavorites returns the token's user's favorite foods with the next date and meal
+     * each is served at, soonest first
+     *
+     * `GET /api/v0/schedule/favorites/next`
+     */
+    getNextFavorites: (query?: ScheduleGetNextFavoritesQuery, options?: RequestOptions): Promise<ScheduleGetNextFavoritesResponse> =>
+      this.request("GET", `/api/v0/schedule/favorites/next`, { query, ...options }),
+    /**
+     * PutFavorite marks a food as a favorite of the token's user
+     *
+     * `PUT /api/v0/schedule/foods/:id/favorite`
+     */
+    putFavorite: (id: PathParam, options?: RequestOptions): Promise<SchedulePutFavoriteResponse> =>
+      this.request("PUT", `/api/v0/schedule/foods/${encodeURIComponent(String(id))}/favorite`, { ...options }),
+    /**
+     * DeleteFavorite unmarks a favorite food of the token's user
+     *
+     * `DELETE /api/v0/schedule/foods/:id/favorite`
+     */
+    deleteFavorite: (id: PathParam, options?: RequestOptions): Promise<ScheduleDeleteFavoriteResponse> =>
+      this.request("DELETE", `/api/v0/schedule/foods/${encodeURIComponent(String(id))}/favorite`, { ...options }),
+    /**
+     * GetMyReview returns the calling user's rating of a food
+     *
+     * `GET /api/v0/schedule/foods/:id/rating`
+     */
+    getMyReview: (id: PathParam, options?: RequestOptions): Promise<ScheduleGetMyReviewResponse> =>
+      this.request("GET", `/api/v0/schedule/foods/${encodeURIComponent(String(id))}/rating`, { ...options }),
+    /**
+     * PutMyReview sets the calling user's rating of a food from 1 to 5, with an optional review
+     *
+     * `PUT /api/v0/schedule/foods/:id/rating`
+     */
+    putMyReview: (id: PathParam, body: SchedulePutMyReviewBody, options?: RequestOptions): Promise<SchedulePutMyReviewResponse> =>
+      this.request("PUT", `/api/v0/schedule/foods/${encodeURIComponent(String(id))}/rating`, { body, ...options }),
+    /**
+     * DeleteMyReview withdraws the calling user's rating of a food
+     *
+     * `DELETE /api/v0/schedule/foods/:id/rating`
+     */
+    deleteMyReview: (id: PathParam, options?: RequestOptions): Promise<ScheduleDeleteMyReviewResponse> =>
+      this.request("DELETE", `/api/v0/schedule/foods/${encodeURIComponent(String(id))}/rating`, { ...options }),
+    /**
+     * GetFoodReviews returns the visible reviews of a food that have text, newest first
+     *
+     * `GET /api/v0/schedule/foods/:id/reviews`
+     */
+    getFoodReviews: (id: PathParam, query?: ScheduleGetFoodReviewsQuery, options?: RequestOptions): Promise<ScheduleGetFoodReviewsResponse> =>
+      this.request("GET", `/api/v0/schedule/foods/${encodeURIComponent(String(id))}/reviews`, { query, ...options }),
+    /**
+     * GetHours returns every restaurant with the hours it serves each meal, so apps can show
+     * e.g. "dinner starts at 18:30"
+     *
+     * `GET /api/v0/schedule/hours`
+     */
+    getHours: (query?: ScheduleGetHoursQuery, options?: RequestOptions): Promise<ScheduleGetHoursResponse> =>
+      this.request("GET", `/api/v0/schedule/hours`, { query, ...options }),
+    /**
+     * GetPersonalized returns a day's menu checked against the calling user's dietary
+     * preferences (see PUT /auth/me/preferences), today's unless ?date= is given. With
+     * ?suitable_only=true the foods that do not suit them are left out.
+     *
+     * `GET /api/v0/schedule/personalized`
+     */
+    getPersonalized: (query?: ScheduleGetPersonalizedQuery, options?: RequestOptions): Promise<ScheduleGetPersonalizedResponse> =>
+      this.request("GET", `/api/v0/schedule/personalized`, { query, ...options }),
+    /**
+     * GetStats returns how often dishes were served over the last days, up to today: the
+     * most frequent ones, or with ?sort=absent those not served for the longest
+     *
+     * `GET /api/v0/schedule/stats`
+     */
+    getStats: (query?: ScheduleGetStatsQuery, options?: RequestOptions): Promise<ScheduleGetStatsResponse> =>
+      this.request("GET", `/api/v0/schedule/stats`, { query, ...options }),
+    /**
+     * GetToday returns today's menu in the cafeteria's time zone
+     *
+     * `GET /api/v0/schedule/today`
+     */
+    getToday: (query?: ScheduleGetTodayQuery, options?: RequestOptions): Promise<ScheduleGetTodayResponse> =>
+      this.request("GET", `/api/v0/schedule/today`, { query, ...options }),
+    /**
+     * GetTomorrow returns tomorrow's menu in the cafeteria's time zone
+     *
+     * `GET /api/v0/schedule/tomorrow`
+     */
+    getTomorrow: (query?: ScheduleGetTomorrowQuery, options?: RequestOptions): Promise<ScheduleGetTomorrowResponse> =>
+      this.request("GET", `/api/v0/schedule/tomorrow`, { query, ...options }),
+    /**
+     * GetVersion returns the current version's date range and when it last changed, so clients
+     * can tell when to refetch the menu. It answers If-Modified-Since with 304.
+     *
+     * `GET /api/v0/schedule/version`
+     */
+    getVersion: (options?: RequestOptions): Promise<ScheduleGetVersionResponse> =>
+      this.request("GET", `/api/v0/schedule/version`, { ...options }),
+    /**
+     * GetWeek returns the menus of the current week, Monday to Sunday
+     *
+     * `GET /api/v0/schedule/week`
+     */
+    getWeek: (query?: ScheduleGetWeekQuery, options?: RequestOptions): Promise<ScheduleGetWeekResponse> =>
+      this.request("GET", `/api/v0/schedule/week`, { query, ...options }),
+  };
+
+  readonly sports = {
+    /**
+     * PostClass adds a class to the weekly program
+     *
+     * `POST /api/v0/admin/sports/classes`
+     */
+    postClass: (body: SportsPostClassBody, options?: RequestOptions): Promise<SportsPostClassResponse> =>
+      this.request("POST", `/api/v0/admin/sports/classes`, { body, ...options }),
+    /**
+     * ReplaceClass overwrites a class of the weekly program
+     *
+     * `PUT /api/v0/admin/sports/classes/:id`
+     */
+    replaceClass: (id: PathParam, body: SportsReplaceClassBody, options?: RequestOptions): Promise<SportsReplaceClassResponse> =>
+      this.request("PUT", `/api/v0/admin/sports/classes/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteClass removes a class from the weekly program
+     *
+     * `DELETE /api/v0/admin/sports/classes/:id`
+     */
+    deleteClass: (id: PathParam, options?: RequestOptions): Promise<SportsDeleteClassResponse> =>
+      this.request("DELETE", `/api/v0/admin/sports/classes/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * PostCourt adds a court to a facility
+     *
+     * `POST /api/v0/admin/sports/courts`
+     */
+    postCourt: (body: SportsPostCourtBody, options?: RequestOptions): Promise<SportsPostCourtResponse> =>
+      this.request("POST", `/api/v0/admin/sports/courts`, { body, ...options }),
+    /**
+     * ReplaceCourt overwrites a court. Bookings made with another slot length are kept.
+     *
+     * `PUT /api/v0/admin/sports/courts/:id`
+     */
+    replaceCourt: (id: PathParam, body: SportsReplaceCourtBody, options?: RequestOptions): Promise<SportsReplaceCourtResponse> =>
+      this.request("PUT", `/api/v0/admin/sports/courts/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteCourt deletes a court and its bookings
+     *
+     * `DELETE /api/v0/admin/sports/courts/:id`
+     */
+    deleteCourt: (id: PathParam, options?: RequestOptions): Promise<SportsDeleteCourtResponse> =>
+      this.request("DELETE", `/api/v0/admin/sports/courts/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListFacilities returns every facility with its translations
+     *
+     * `GET /api/v0/admin/sports/facilities`
+     */
+    listFacilities: (options?: RequestOptions): Promise<SportsListFacilitiesResponse> =>
+      this.request("GET", `/api/v0/admin/sports/facilities`, { ...options }),
+    /**
+     * PostFacility adds a facility with its opening hours
+     *
+     * `POST /api/v0/admin/sports/facilities`
+     */
+    postFacility: (body: SportsPostFacilityBody, options?: RequestOptions): Promise<SportsPostFacilityResponse> =>
+      this.request("POST", `/api/v0/admin/sports/facilities`, { body, ...options }),
+    /**
+     * ReplaceFacility overwrites a facility and its opening hours
+     *
+     * `PUT /api/v0/admin/sports/facilities/:id`
+     */
+    replaceFacility: (id: PathParam, body: SportsReplaceFacilityBody, options?: RequestOptions): Promise<SportsReplaceFacilityResponse> =>
+      this.request("PUT", `/api/v0/admin/sports/facilities/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteFacility deletes a facility with its classes, courts and their bookings
+     *
+     * `DELETE /api/v0/admin/sports/facilities/:id`
+     */
+    deleteFacility: (id: PathParam, options?: RequestOptions): Promise<SportsDeleteFacilityResponse> =>
+      this.request("DELETE", `/api/v0/admin/sports/facilities/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListBookings returns the calling user's bookings that have not ended yet
+     *
+     * `GET /api/v0/sports/bookings`
+     */
+    listBookings: (options?: RequestOptions): Promise<SportsListBookingsResponse> =>
+      this.request("GET", `/api/v0/sports/bookings`, { ...options }),
+    /**
+     * PostBooking books the court slot starting at starts_at for the calling user, within
+     * the BookingLimits
+     *
+     * `POST /api/v0/sports/bookings`
+     */
+    postBooking: (body: SportsPostBookingBody, options?: RequestOptions): Promise<SportsPostBookingResponse> =>
+      this.request("POST", `/api/v0/sports/bookings`, { body, ...options }),
+    /**
+     * DeleteBooking cancels one of the calling user's bookings that has not ended
+     *
+     * `DELETE /api/v0/sports/bookings/:id`
+     */
+    deleteBooking: (id: PathParam, options?: RequestOptions): Promise<SportsDeleteBookingResponse> =>
+      this.request("DELETE", `/api/v0/sports/bookings/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * GetFacilities returns every facility with its opening hours
+     *
+     * `GET /api/v0/sports/facilities`
+     */
+    getFacilities: (query?: SportsGetFacilitiesQuery, options?: RequestOptions): Promise<SportsGetFacilitiesResponse> =>
+      this.request("GET", `/api/v0/sports/facilities`, { query, ...options }),
+    /**
+     * GetFacility returns one facility with its opening hours
+     *
+     * `GET /api/v0/sports/facilities/:id`
+     */
+    getFacility: (id: PathParam, query?: SportsGetFacilityQuery, options?: RequestOptions): Promise<SportsGetFacilityResponse> =>
+      this.request("GET", `/api/v0/sports/facilities/${encodeURIComponent(String(id))}`, { query, ...options }),
+    /**
+     * GetCourts returns the courts of a facility with their slots on a day, today by
+     * default. Booked slots and those that have started are not available.
+     *
+     * `GET /api/v0/sports/facilities/:id/courts`
+     */
+    getCourts: (id: PathParam, query?: SportsGetCourtsQuery, options?: RequestOptions): Promise<SportsGetCourtsResponse> =>
+      this.request("GET", `/api/v0/sports/facilities/${encodeURIComponent(String(id))}/courts`, { query, ...options }),
+    /**
+     * GetProgram returns the weekly program of classes, Monday first, optionally of one
+     * facility or day
+     *
+     * `GET /api/v0/sports/program`
+     */
+    getProgram: (query?: SportsGetProgramQuery, options?: RequestOptions): Promise<SportsGetProgramResponse> =>
+      this.request("GET", `/api/v0/sports/program`, { query, ...options }),
+  };
+
+  readonly staff = {
+    /**
+     * ListWorkspaces returns the caller's course workspaces
+     *
+     * `GET /api/staff/workspaces`
+     */
+    listWorkspaces: (options?: RequestOptions): Promise<StaffListWorkspacesResponse> =>
+      this.request("GET", `/api/staff/workspaces`, { ...options }),
+    /**
+     * CreateWorkspace creates a course workspace owned by the caller
+     *
+     * `POST /api/staff/workspaces`
+     */
+    createWorkspace: (body: StaffCreateWorkspaceBody, options?: RequestOptions): Promise<StaffCreateWorkspaceResponse> =>
+      this.request("POST", `/api/staff/workspaces`, { body, ...options }),
+    /**
+     * GetWorkspace returns a workspace with its students
+     *
+     * `GET /api/staff/workspaces/:id`
+     */
+    getWorkspace: (id: PathParam, options?: RequestOptions): Promise<StaffGetWorkspaceResponse> =>
+      this.request("GET", `/api/staff/workspaces/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * DeleteWorkspace revokes all student tokens and deletes the workspace
+     *
+     * `DELETE /api/staff/workspaces/:id`
+     */
+    deleteWorkspace: (id: PathParam, options?: RequestOptions): Promise<StaffDeleteWorkspaceResponse> =>
+      this.request("DELETE", `/api/staff/workspaces/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * EnrollStudents issues time-boxed tokens to students by email. The raw
+     * tokens are only returned in this response.
+     *
+     * `POST /api/staff/workspaces/:id/students`
+     */
+    enrollStudents: (id: PathParam, body: StaffEnrollStudentsBody, options?: RequestOptions): Promise<StaffEnrollStudentsResponse> =>
+      this.request("POST", `/api/staff/workspaces/${encodeURIComponent(String(id))}/students`, { body, ...options }),
+    /**
+     * RemoveStudent revokes a student's token and removes them from the workspace
+     *
+     * `DELETE /api/staff/workspaces/:id/students/:userId`
+     */
+    removeStudent: (id: PathParam, userId: PathParam, options?: RequestOptions): Promise<StaffRemoveStudentResponse> =>
+      this.request("DELETE", `/api/staff/workspaces/${encodeURIComponent(String(id))}/students/${encodeURIComponent(String(userId))}`, { ...options }),
+    /**
+     * GetWorkspaceUsage returns the usage dashboard of a workspace's cohort
+     *
+     * `GET /api/staff/workspaces/:id/usage`
+     */
+    getWorkspaceUsage: (id: PathParam, options?: RequestOptions): Promise<StaffGetWorkspaceUsageResponse> =>
+      this.request("GET", `/api/staff/workspaces/${encodeURIComponent(String(id))}/usage`, { ...options }),
+  };
+
+  readonly stream = {
+    /**
+     * Stream sends server-sent events as the followed topics change: the event is the topic
+     * (announcements, schedule) and the data its whole current state as JSON, in the language
+     * of ?lang= or Accept-Language. The current states are sent first, so a client has
+     * everything to show at once and after every reconnect.
+     *
+     * `GET /api/v0/stream`
+     */
+    stream: (query?: StreamStreamQuery, options?: RequestOptions): Promise<Response> =>
+      this.raw("GET", `/api/v0/stream`, { query, ...options }),
+  };
+
+  readonly tenant = {
+    /** `GET /api/tenant` */
+    getTenant: (options?: RequestOptions): Promise<TenantGetTenantResponse> =>
+      this.request("GET", `/api/tenant`, { ...options }),
+  };
+
+  readonly webhooks = {
+    /**
+     * ListWebhooks returns the calling token's webhooks and the events they can subscribe to
+     *
+     * `GET /api/v0/webhooks`
+     */
+    listWebhooks: (options?: RequestOptions): Promise<WebhooksListWebhooksResponse> =>
+      this.request("GET", `/api/v0/webhooks`, { ...options }),
+    /**
+     * PostWebhook registers a webhook for the calling token. The signing secret is only
+     * returned here.
+     *
+     * `POST /api/v0/webhooks`
+     */
+    postWebhook: (body: WebhooksPostWebhookBody, options?: RequestOptions): Promise<WebhooksPostWebhookResponse> =>
+      this.request("POST", `/api/v0/webhooks`, { body, ...options }),
+    /**
+     * GetWebhook returns one of the calling token's webhooks
+     *
+     * `GET /api/v0/webhooks/:id`
+     */
+    getWebhook: (id: PathParam, options?: RequestOptions): Promise<WebhooksGetWebhookResponse> =>
+      this.request("GET", `/api/v0/webhooks/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * PatchWebhook changes one of the calling token's webhooks; active=false pauses it, and
+     * events queued meanwhile are dropped
+     *
+     * `PATCH /api/v0/webhooks/:id`
+     */
+    patchWebhook: (id: PathParam, body: WebhooksPatchWebhookBody, options?: RequestOptions): Promise<WebhooksPatchWebhookResponse> =>
+      this.request("PATCH", `/api/v0/webhooks/${encodeURIComponent(String(id))}`, { body, ...options }),
+    /**
+     * DeleteWebhook deletes one of the calling token's webhooks with its delivery log
+     *
+     * `DELETE /api/v0/webhooks/:id`
+     */
+    deleteWebhook: (id: PathParam, options?: RequestOptions): Promise<WebhooksDeleteWebhookResponse> =>
+      this.request("DELETE", `/api/v0/webhooks/${encodeURIComponent(String(id))}`, { ...options }),
+    /**
+     * ListDeliveries returns the deliveries of one of the calling token's webhooks, newest
+     * first, with their status, attempts and last error. Failed deliveries are retried with
+     * backoff up to events.OutboxMaxAttempts times.
+     *
+     * `GET /api/v0/webhooks/:id/deliveries`
+     */
+    listDeliveries: (id: PathParam, query?: WebhooksListDeliveriesQuery, options?: RequestOptions): Promise<WebhooksListDeliveriesResponse> =>
+      this.request("GET", `/api/v0/webhooks/${encodeURIComponent(String(id))}/deliveries`, { query, ...options }),
+    /**
+     * TestWebhook sends a signed ping event to one of the calling token's webhooks right away
+     *
+     * `POST /api/v0/webhooks/:id/test`
+     */
+    testWebhook: (id: PathParam, options?: RequestOptions): Promise<WebhooksTestWebhookResponse> =>
+      this.request("POST", `/api/v0/webhooks/${encodeURIComponent(String(id))}/test`, { ...options }),
+  };
+
+  readonly ws = {
+    /**
+     * Connect upgrades to a WebSocket whose clients subscribe to channels (announcements,
+     * schedule, library.occupancy) and get their whole state as JSON, in the language of
+     * ?lang= or Accept-Language, on subscribing and whenever it changes. The handshake is
+     * authenticated like any request, with the token in the Authorization header.
+     *
+     * Returns the address to open the WebSocket at.
+     *
+     * `GET /api/v0/ws`
+     */
+    connect: (query?: WsConnectQuery): string =>
+      this.socketUrl(`/api/v0/ws`, query),
+  };
+}
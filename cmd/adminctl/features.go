@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) featuresCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "features",
+		Short: "List registered features",
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List features with their parents",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			features, err := a.features.GetAllFeatures(a.ctx)
+			if err != nil {
+				return err
+			}
+			slugs := make(map[int64]string, len(features))
+			for _, f := range features {
+				slugs[f.ID] = f.Slug
+			}
+
+			w := newTable()
+			fmt.Fprintln(w, "ID\tSLUG\tNAME\tPARENT\tADMIN ONLY")
+			for _, f := range features {
+				parent := "-"
+				if f.ParentID != nil {
+					parent = slugs[*f.ParentID]
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%t\n", f.ID, f.Slug, f.Name, parent, f.AdminOnly)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.AddCommand(list)
+	return cmd
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"API/internal/auth"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) groupsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "groups",
+		Short: "List user groups",
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List groups with their default limits",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			groups, err := a.repo.GetAllGroups(a.ctx)
+			if err != nil {
+				return err
+			}
+
+			w := newTable()
+			fmt.Fprintln(w, "ID\tNAME\tDEFAULT RPM\tMAX SESSIONS\tDESCRIPTION")
+			for _, g := range groups {
+				desc := ""
+				if g.Description != nil {
+					desc = *g.Description
+				}
+				fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%s\n", g.ID, g.Name, g.DefaultRPM, g.MaxSessions, desc)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.AddCommand(list)
+	return cmd
+}
+
+// groupByName looks up a group by name, failing when it does not exist
+func (a *app) groupByName(name string) (*auth.Group, error) {
+	group, err := a.repo.GetGroupByName(a.ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("no group named %s", name)
+	}
+	return group, nil
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"API/internal/auth"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"text/tabwriter"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/cobra"
+)
+
+// adminctl works directly on a tenant's auth database so that users, tokens,
+// quotas, groups and features can be managed when the web admin UI is down.
+// Every command opens the database itself; the API does not need to be running.
+
+// app holds the components shared by all commands, opened by the root command
+type app struct {
+	ctx      context.Context // cancelled on Ctrl-C, so a slow query stops with it
+	db       *sql.DB
+	repo     *auth.Repository
+	features *auth.FeatureRegistry
+	quota    *auth.QuotaEngine
+	tokens   *auth.TokenStore
+}
+
+func main() {
+	a := &app{}
+	var dbFile string
+
+	root := &cobra.Command{
+		Use:           "adminctl",
+		Short:         "Manage OpenSourceDUTH API users, tokens, quotas, groups and features",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stat(dbFile); err != nil {
+				return fmt.Errorf("auth database %s: %w", dbFile, err)
+			}
+			db, err := sql.Open("sqlite3", dbFile)
+			if err != nil {
+				return err
+			}
+			a.ctx = cmd.Context()
+			a.db = db
+			a.repo = auth.NewRepository(db, nil)
+			a.features = auth.NewFeatureRegistry(a.repo)
+			a.quota = auth.NewQuotaEngine(a.repo, a.features, nil)
+			a.tokens = auth.NewTokenStore(a.repo, a.features, "", nil)
+			return nil
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			if a.db != nil {
+				a.db.Close()
+			}
+		},
+	}
+	root.PersistentFlags().StringVar(&dbFile, "db", "internal/databases/auth.db", "auth database file")
+
+	root.AddCommand(
+		a.usersCommand(),
+		a.tokensCommand(),
+		a.quotasCommand(),
+		a.groupsCommand(),
+		a.featuresCommand(),
+		a.seedCommand(),
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := root.ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// userByEmail looks up a user by email, failing when the account does not exist
+func (a *app) userByEmail(email string) (*auth.User, error) {
+	user, err := a.repo.GetUserByEmail(a.ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("no user with email %s", email)
+	}
+	return user, nil
+}
+
+// featureBySlug looks up a feature by slug, failing when it does not exist
+func (a *app) featureBySlug(slug string) (*auth.Feature, error) {
+	feature, err := a.features.GetFeatureBySlug(a.ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if feature == nil {
+		return nil, fmt.Errorf("no feature with slug %s", slug)
+	}
+	return feature, nil
+}
+
+// parseRPM reads an RPM limit argument; "uncapped" means no limit (nil)
+func parseRPM(value string) (*int, error) {
+	if value == "uncapped" {
+		return nil, nil
+	}
+	rpm, err := strconv.Atoi(value)
+	if err != nil || rpm < 0 {
+		return nil, fmt.Errorf("invalid RPM limit %q, use a number or \"uncapped\"", value)
+	}
+	return &rpm, nil
+}
+
+func formatRPM(rpm *int) string {
+	if rpm == nil {
+		return "uncapped"
+	}
+	return strconv.Itoa(*rpm)
+}
+
+func newTable() *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) quotasCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quotas",
+		Short: "Inspect and set per-user and per-group RPM quotas",
+	}
+
+	get := &cobra.Command{
+		Use:   "get <email>",
+		Short: "Show a user's quota overrides",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.userByEmail(args[0])
+			if err != nil {
+				return err
+			}
+			overrides, err := a.quota.GetUserQuotaOverrides(a.ctx, user.ID)
+			if err != nil {
+				return err
+			}
+			slugs, err := a.featureSlugs()
+			if err != nil {
+				return err
+			}
+
+			w := newTable()
+			fmt.Fprintln(w, "FEATURE\tRPM")
+			for _, o := range overrides {
+				fmt.Fprintf(w, "%s\t%s\n", slugs[o.FeatureID], formatRPM(o.RPMLimit))
+			}
+			return w.Flush()
+		},
+	}
+
+	set := &cobra.Command{
+		Use:   "set <email> <feature> <rpm|uncapped>",
+		Short: "Override a user's RPM limit on a feature",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.userByEmail(args[0])
+			if err != nil {
+				return err
+			}
+			feature, err := a.featureBySlug(args[1])
+			if err != nil {
+				return err
+			}
+			rpm, err := parseRPM(args[2])
+			if err != nil {
+				return err
+			}
+			if err := a.quota.SetUserQuotaOverride(a.ctx, user.ID, feature.ID, rpm); err != nil {
+				return err
+			}
+			fmt.Printf("%s on %s: %s\n", user.Email, feature.Slug, formatRPM(rpm))
+			return nil
+		},
+	}
+
+	groupGet := &cobra.Command{
+		Use:   "group-get <group>",
+		Short: "Show a group's feature quotas",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			group, err := a.groupByName(args[0])
+			if err != nil {
+				return err
+			}
+			quotas, err := a.quota.GetGroupFeatureQuotas(a.ctx, group.ID)
+			if err != nil {
+				return err
+			}
+			slugs, err := a.featureSlugs()
+			if err != nil {
+				return err
+			}
+
+			w := newTable()
+			fmt.Fprintln(w, "FEATURE\tRPM")
+			for _, q := range quotas {
+				fmt.Fprintf(w, "%s\t%s\n", slugs[q.FeatureID], formatRPM(q.RPMLimit))
+			}
+			fmt.Fprintf(w, "(default)\t%d\n", group.DefaultRPM)
+			return w.Flush()
+		},
+	}
+
+	groupSet := &cobra.Command{
+		Use:   "group-set <group> <feature> <rpm|uncapped>",
+		Short: "Set a group's RPM limit on a feature",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			group, err := a.groupByName(args[0])
+			if err != nil {
+				return err
+			}
+			feature, err := a.featureBySlug(args[1])
+			if err != nil {
+				return err
+			}
+			rpm, err := parseRPM(args[2])
+			if err != nil {
+				return err
+			}
+			if err := a.quota.SetGroupFeatureQuota(a.ctx, group.ID, feature.ID, rpm); err != nil {
+				return err
+			}
+			fmt.Printf("%s on %s: %s\n", group.Name, feature.Slug, formatRPM(rpm))
+			return nil
+		},
+	}
+
+	cmd.AddCommand(get, set, groupGet, groupSet)
+	return cmd
+}
+
+// featureSlugs maps feature IDs to slugs for display
+func (a *app) featureSlugs() (map[int64]string, error) {
+	features, err := a.features.GetAllFeatures(a.ctx)
+	if err != nil {
+		return nil, err
+	}
+	slugs := make(map[int64]string, len(features))
+	for _, f := range features {
+		slugs[f.ID] = f.Slug
+	}
+	return slugs, nil
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"API/internal/auth"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// seedFile describes the groups, features, group quotas, academic domains and
+// admins to create or update. Seeding is idempotent: existing rows are matched
+// by group name / feature slug / email and updated in place, so the same file
+// can be applied to every tenant.
+type seedFile struct {
+	Groups []struct {
+		Name        string  `json:"name"`
+		DefaultRPM  int     `json:"defaultRpm"`
+		MaxSessions int     `json:"maxSessions"`
+		Description *string `json:"description"`
+	} `json:"groups"`
+	Features []struct {
+		Slug      string `json:"slug"`
+		Name      string `json:"name"`
+		Parent    string `json:"parent"`
+		AdminOnly bool   `json:"adminOnly"`
+		auth.FeatureDocs
+	} `json:"features"`
+	Quotas []struct {
+		Group    string `json:"group"`
+		Feature  string `json:"feature"`
+		RPMLimit *int   `json:"rpmLimit"` // null = uncapped
+	} `json:"quotas"`
+	AcademicDomains []string `json:"academicDomains"`
+	Admins          []struct {
+		Email       string `json:"email"`
+		DisplayName string `json:"displayName"`
+	} `json:"admins"`
+}
+
+// baseline is what the code expects to exist: the "regular" and "academic"
+// groups that sign-up falls back to, the core features and their quotas
+//
+//go:embed baseline.json
+var baseline []byte
+
+func (a *app) seedCommand() *cobra.Command {
+	var admin string
+	cmd := &cobra.Command{
+		Use:   "seed [file.json]",
+		Short: "Create or update groups, features, group quotas, academic domains and admins",
+		Long: `Without a file, create the baseline groups, features, group quotas and
+academic domains that are missing. Rows that already exist are left untouched.
+
+With a file, create or update them from JSON:
+
+  {
+    "groups":   [{"name": "students", "defaultRpm": 60, "maxSessions": 3}],
+    "features": [{"slug": "schedule", "name": "Schedule", "parent": "v0",
+                  "description": "Cafeteria menu", "docsUrl": "https://..."}],
+    "quotas":   [{"group": "students", "feature": "schedule", "rpmLimit": 120}],
+    "academicDomains": ["cs.duth.gr", "*.duth.gr"],
+    "admins":   [{"email": "you@cs.duth.gr", "displayName": "You"}]
+  }
+
+Features are applied in file order, so parents must be listed before their children.
+Admins are created when they have no account yet, so they can log in through OAuth
+on a fresh deployment.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data := baseline
+			if len(args) == 1 {
+				var err error
+				if data, err = os.ReadFile(args[0]); err != nil {
+					return err
+				}
+			}
+			var seed seedFile
+			if err := json.Unmarshal(data, &seed); err != nil {
+				return fmt.Errorf("invalid seed file: %w", err)
+			}
+			if err := a.applySeed(&seed, len(args) == 0); err != nil {
+				return err
+			}
+			if admin != "" {
+				return a.seedAdmin(admin, "")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&admin, "admin", "", "email of an admin to create or promote")
+	return cmd
+}
+
+// applySeed applies a seed file. With createOnly, existing rows are skipped
+// instead of updated, so the baseline never overwrites what admins changed.
+func (a *app) applySeed(seed *seedFile, createOnly bool) error {
+	for _, g := range seed.Groups {
+		existing, err := a.repo.GetGroupByName(a.ctx, g.Name)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			if _, err := a.repo.CreateGroup(a.ctx, g.Name, g.DefaultRPM, g.MaxSessions, g.Description); err != nil {
+				return fmt.Errorf("group %s: %w", g.Name, err)
+			}
+			fmt.Printf("Created group %s\n", g.Name)
+			continue
+		}
+		if createOnly {
+			continue
+		}
+		if err := a.repo.UpdateGroup(a.ctx, existing.ID, nil, &g.DefaultRPM, &g.MaxSessions, g.Description); err != nil {
+			return fmt.Errorf("group %s: %w", g.Name, err)
+		}
+		fmt.Printf("Updated group %s\n", g.Name)
+	}
+
+	for _, f := range seed.Features {
+		if f.Name == "" {
+			f.Name = f.Slug
+		}
+		var parentID *int64
+		if f.Parent != "" {
+			parent, err := a.featureBySlug(f.Parent)
+			if err != nil {
+				return fmt.Errorf("feature %s: %w", f.Slug, err)
+			}
+			parentID = &parent.ID
+		}
+		existing, err := a.features.GetFeatureBySlug(a.ctx, f.Slug)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			created, err := a.features.CreateFeature(a.ctx, f.Slug, f.Name, parentID, f.AdminOnly)
+			if err != nil {
+				return fmt.Errorf("feature %s: %w", f.Slug, err)
+			}
+			if err := a.features.SetFeatureDocs(a.ctx, created.ID, f.FeatureDocs); err != nil {
+				return fmt.Errorf("feature %s: %w", f.Slug, err)
+			}
+			fmt.Printf("Created feature %s\n", f.Slug)
+			continue
+		}
+		if createOnly {
+			continue
+		}
+		if err := a.features.UpdateFeature(a.ctx, existing.ID, &f.Name, parentID, &f.AdminOnly); err != nil {
+			return fmt.Errorf("feature %s: %w", f.Slug, err)
+		}
+		if err := a.features.SetFeatureDocs(a.ctx, existing.ID, f.FeatureDocs); err != nil {
+			return fmt.Errorf("feature %s: %w", f.Slug, err)
+		}
+		fmt.Printf("Updated feature %s\n", f.Slug)
+	}
+
+	for _, q := range seed.Quotas {
+		group, err := a.groupByName(q.Group)
+		if err != nil {
+			return err
+		}
+		feature, err := a.featureBySlug(q.Feature)
+		if err != nil {
+			return err
+		}
+		if createOnly {
+			existing, err := a.quota.GetGroupFeatureQuotas(a.ctx, group.ID)
+			if err != nil {
+				return err
+			}
+			if hasQuota(existing, feature.ID) {
+				continue
+			}
+		}
+		if err := a.quota.SetGroupFeatureQuota(a.ctx, group.ID, feature.ID, q.RPMLimit); err != nil {
+			return fmt.Errorf("quota %s/%s: %w", q.Group, q.Feature, err)
+		}
+		fmt.Printf("Set %s on %s: %s\n", group.Name, feature.Slug, formatRPM(q.RPMLimit))
+	}
+
+	for _, domain := range seed.AcademicDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if err := a.repo.AddAcademicDomain(a.ctx, domain); err != nil {
+			return fmt.Errorf("academic domain %s: %w", domain, err)
+		}
+		fmt.Printf("Added academic domain %s\n", domain)
+	}
+
+	for _, admin := range seed.Admins {
+		if err := a.seedAdmin(admin.Email, admin.DisplayName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedAdmin makes email an admin, creating the account in the group sign-up
+// would pick when it does not exist yet
+func (a *app) seedAdmin(email, displayName string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	user, err := a.repo.GetUserByEmail(a.ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		local, domain, ok := strings.Cut(email, "@")
+		if !ok {
+			return fmt.Errorf("admin %s: invalid email", email)
+		}
+		if displayName == "" {
+			displayName = local
+		}
+		groupName := "regular"
+		if academic, err := a.repo.IsAcademicDomain(a.ctx, domain); err != nil {
+			return err
+		} else if academic {
+			groupName = "academic"
+		}
+		group, err := a.groupByName(groupName)
+		if err != nil {
+			return err
+		}
+		if user, err = a.repo.CreateUser(a.ctx, email, displayName, group.ID); err != nil {
+			return fmt.Errorf("admin %s: %w", email, err)
+		}
+		fmt.Printf("Created user %s\n", email)
+	}
+	if user.Role == auth.RoleAdmin {
+		return nil
+	}
+	role := auth.RoleAdmin
+	if err := a.repo.UpdateUser(a.ctx, user.ID, &role, nil, nil, nil); err != nil {
+		return fmt.Errorf("admin %s: %w", email, err)
+	}
+	fmt.Printf("Promoted %s to admin\n", email)
+	return nil
+}
+
+// hasQuota reports whether a group already has a quota for a feature
+func hasQuota(quotas []auth.GroupFeatureQuota, featureID int64) bool {
+	for _, q := range quotas {
+		if q.FeatureID == featureID {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) tokensCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tokens",
+		Short: "List and revoke API tokens",
+	}
+
+	list := &cobra.Command{
+		Use:   "list <email>",
+		Short: "List a user's tokens",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.userByEmail(args[0])
+			if err != nil {
+				return err
+			}
+			tokens, err := a.tokens.ListUserTokens(a.ctx, user.ID)
+			if err != nil {
+				return err
+			}
+
+			w := newTable()
+			fmt.Fprintln(w, "ID\tLABEL\tSTATE\tADMIN\tEXPIRES\tCREATED")
+			for _, t := range tokens {
+				state := "active"
+				if t.RevokedAt != nil {
+					state = "revoked"
+				} else if t.ExpiresAt != nil && t.ExpiresAt.Before(time.Now()) {
+					state = "expired"
+				}
+				expires := "never"
+				if t.ExpiresAt != nil {
+					expires = t.ExpiresAt.Format(time.DateTime)
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%t\t%s\t%s\n",
+					t.ID, t.Label, state, t.AdminCreated, expires, t.CreatedAt.Format(time.DateOnly))
+			}
+			return w.Flush()
+		},
+	}
+
+	revoke := &cobra.Command{
+		Use:   "revoke <token-id>...",
+		Short: "Revoke tokens by ID",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, arg := range args {
+				id, err := strconv.ParseInt(arg, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid token ID %q", arg)
+				}
+				if err := a.tokens.AdminRevokeToken(a.ctx, id); err != nil {
+					return fmt.Errorf("token %d: %w", id, err)
+				}
+				fmt.Printf("Revoked token %d\n", id)
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(list, revoke)
+	return cmd
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
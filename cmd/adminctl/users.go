@@ -0,0 +1,202 @@
+package main
+
+import (
+	"API/internal/auth"
+	"API/internal/pagination"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) usersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "users",
+		Short: "List and manage users",
+	}
+
+	var filter auth.UserFilter
+	var role, status string
+	var limit int
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List users, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if role != "" {
+				r := auth.Role(role)
+				filter.Role = &r
+			}
+			if status != "" {
+				s := auth.Status(status)
+				filter.Status = &s
+			}
+			page := pagination.Params{Limit: limit}
+			users, err := a.repo.GetAllUsers(a.ctx, filter, page)
+			if err != nil {
+				return err
+			}
+			users, _ = pagination.Next(users, page, func(u auth.User) int64 { return u.ID })
+
+			w := newTable()
+			fmt.Fprintln(w, "ID\tEMAIL\tNAME\tROLE\tSTATUS\tGROUP\tCREATED")
+			for _, u := range users {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					u.ID, u.Email, u.DisplayName, u.Role, u.Status, u.Group.Name, u.CreatedAt.Format(time.DateOnly))
+			}
+			return w.Flush()
+		},
+	}
+	list.Flags().StringVarP(&filter.Query, "query", "q", "", "match email or display name")
+	list.Flags().StringVar(&role, "role", "", "only users with this role")
+	list.Flags().StringVar(&status, "status", "", "only users with this status")
+	list.Flags().StringVar(&filter.Tag, "tag", "", "only users with this admin tag")
+	list.Flags().IntVar(&limit, "limit", 50, "maximum number of users")
+
+	show := &cobra.Command{
+		Use:   "show <email>",
+		Short: "Show a user with their tags and notes",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.userByEmail(args[0])
+			if err != nil {
+				return err
+			}
+			user, err = a.repo.GetUserByID(a.ctx, user.ID)
+			if err != nil {
+				return err
+			}
+			tags, err := a.repo.GetUserTags(a.ctx, user.ID)
+			if err != nil {
+				return err
+			}
+			notes, err := a.repo.GetUserNotes(a.ctx, user.ID)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("ID:      %d\nEmail:   %s\nName:    %s\nRole:    %s\nStatus:  %s\nGroup:   %s\nTokens:  max %d\n",
+				user.ID, user.Email, user.DisplayName, user.Role, user.Status, user.Group.Name, user.MaxTokens)
+			if user.Status == auth.StatusSuspended {
+				fmt.Printf("Reason:  %s\n", user.SuspensionMessage())
+			}
+			if len(tags) > 0 {
+				fmt.Printf("Tags:    %s\n", strings.Join(tags, ", "))
+			}
+			for _, n := range notes {
+				fmt.Printf("Note:    [%s] %s\n", n.CreatedAt.Format(time.DateOnly), n.Body)
+			}
+			return nil
+		},
+	}
+
+	setRole := &cobra.Command{
+		Use:   "set-role <email> <user|staff|data-editor|admin>",
+		Short: "Change a user's role (e.g. promote to admin)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			role := auth.Role(args[1])
+			if role != auth.RoleUser && role != auth.RoleStaff && role != auth.RoleDataEditor && role != auth.RoleAdmin {
+				return fmt.Errorf("invalid role %q", args[1])
+			}
+			user, err := a.userByEmail(args[0])
+			if err != nil {
+				return err
+			}
+			if err := a.repo.UpdateUser(a.ctx, user.ID, &role, nil, nil, nil); err != nil {
+				return err
+			}
+			fmt.Printf("%s is now %s\n", user.Email, role)
+			return nil
+		},
+	}
+
+	setGroup := &cobra.Command{
+		Use:   "set-group <email> <group>",
+		Short: "Move a user to another group",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.userByEmail(args[0])
+			if err != nil {
+				return err
+			}
+			group, err := a.groupByName(args[1])
+			if err != nil {
+				return err
+			}
+			if err := a.repo.UpdateUser(a.ctx, user.ID, nil, nil, &group.ID, nil); err != nil {
+				return err
+			}
+			fmt.Printf("%s moved to %s\n", user.Email, group.Name)
+			return nil
+		},
+	}
+
+	var reason string
+	var duration time.Duration
+	suspend := &cobra.Command{
+		Use:   "suspend <email>",
+		Short: "Suspend a user, optionally for a limited time",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.userByEmail(args[0])
+			if err != nil {
+				return err
+			}
+			var reasonPtr *string
+			if reason != "" {
+				reasonPtr = &reason
+			}
+			var until *time.Time
+			if duration > 0 {
+				t := time.Now().Add(duration)
+				until = &t
+			}
+			if err := a.repo.SuspendUser(a.ctx, user.ID, reasonPtr, nil, until); err != nil {
+				return err
+			}
+			fmt.Printf("%s suspended\n", user.Email)
+			return nil
+		},
+	}
+	suspend.Flags().StringVar(&reason, "reason", "", "reason shown to the user")
+	suspend.Flags().DurationVar(&duration, "for", 0, "lift the suspension automatically after this long (e.g. 72h)")
+
+	activate := &cobra.Command{
+		Use:   "activate <email>",
+		Short: "Lift a user's suspension",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.userByEmail(args[0])
+			if err != nil {
+				return err
+			}
+			if err := a.repo.ReactivateUser(a.ctx, user.ID); err != nil {
+				return err
+			}
+			fmt.Printf("%s reactivated\n", user.Email)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(list, show, setRole, setGroup, suspend, activate)
+	return cmd
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"API/internal/env"
+	"API/internal/storage"
+	"fmt"
+)
+
+// backupStorage returns the snapshot storage selected by the environment, or
+// nil when backups are disabled
+func backupStorage() (storage.Storage, error) {
+	dir := env.GetEnv(env.EnvBackupDir, "")
+	bucket := env.GetEnv(env.EnvBackupS3Bucket, "")
+
+	switch {
+	case dir != "" && bucket != "":
+		return nil, fmt.Errorf("%s cannot be combined with %s", env.EnvBackupDir, env.EnvBackupS3Bucket)
+	case dir != "":
+		return storage.NewDirStorage(dir), nil
+	case bucket != "":
+		region := env.GetEnv(env.EnvBackupS3Region, "us-east-1")
+		accessKey := env.GetSecret(env.EnvBackupS3AccessKey, "")
+		secretKey := env.GetSecret(env.EnvBackupS3SecretKey, "")
+		if accessKey == "" || secretKey == "" {
+			return nil, fmt.Errorf("%s needs %s and %s", env.EnvBackupS3Bucket, env.EnvBackupS3AccessKey, env.EnvBackupS3SecretKey)
+		}
+		endpoint := env.GetEnv(env.EnvBackupS3Endpoint, "https://s3."+region+".amazonaws.com")
+		return storage.NewS3Storage(endpoint, region, bucket, accessKey, secretKey), nil
+	}
+	return nil, nil
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
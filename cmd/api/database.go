@@ -0,0 +1,134 @@
+package main
+
+import (
+	"API/internal/env"
+	"API/internal/slowquery"
+	"API/internal/tenant"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	// DefaultBusyTimeout is how long a write waits for another writer when
+	// SQLITE_BUSY_TIMEOUT is unset, instead of failing with "database is locked"
+	DefaultBusyTimeout = 5 * time.Second
+
+	// DefaultMaxOpenConns bounds the connections per database; SQLite has a
+	// single writer, so more mostly add lock contention
+	DefaultMaxOpenConns = 16
+
+	// DefaultMaxIdleConns keeps enough connections open to skip reconnecting
+	// under steady load
+	DefaultMaxIdleConns = 4
+
+	// DefaultSlowQuery is how long a statement may take before it is logged when
+	// SQLITE_SLOW_QUERY is unset
+	DefaultSlowQuery = 200 * time.Millisecond
+)
+
+// moduleDatabase is a tenant database, named after the module whose migrations it takes;
+// the name also labels its backups and health checks
+type moduleDatabase struct {
+	name string
+	path string
+}
+
+// tenantDatabases lists the databases of a tenant's modules, in the order they are opened
+func tenantDatabases(t tenant.Tenant) []moduleDatabase {
+	return []moduleDatabase{
+		{"schedule", t.Datasets.ScheduleDB},
+		{"auth", t.Datasets.AuthDB},
+		{"courses", t.Datasets.CoursesDB},
+		{"maps", t.Datasets.MapsDB},
+		{"directory", t.Datasets.DirectoryDB},
+		{"events", t.Datasets.EventsDB},
+		{"library", t.Datasets.LibraryDB},
+		{"news", t.Datasets.NewsDB},
+		{"sports", t.Datasets.SportsDB},
+		{"postings", t.Datasets.PostingsDB},
+		{"jobs", t.Datasets.JobsDB},
+		{"printing", t.Datasets.PrintingDB},
+		{"webhooks", t.Datasets.WebhooksDB},
+		{"eclass", t.Datasets.EClassDB},
+	}
+}
+
+// openDatabase opens a SQLite database, creating its directory if needed.
+// The pragmas are set through the DSN so every pooled connection gets them,
+// not just the one a PRAGMA statement happens to run on.
+func openDatabase(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("_journal_mode", env.GetEnv(env.EnvSQLiteJournalMode, "WAL"))
+	params.Set("_synchronous", env.GetEnv(env.EnvSQLiteSynchronous, "NORMAL"))
+	params.Set("_busy_timeout", strconv.FormatInt(env.GetDuration(env.EnvSQLiteBusyTimeout, DefaultBusyTimeout).Milliseconds(), 10))
+	params.Set("_foreign_keys", strconv.FormatBool(env.GetBool(env.EnvSQLiteForeignKeys, true)))
+	// Transactions take the write lock when they begin, so two of them never
+	// deadlock upgrading a read lock; every transaction in the API writes
+	params.Set("_txlock", "immediate")
+
+	db := open(path, path+"?"+params.Encode())
+	db.SetMaxOpenConns(env.GetInt(env.EnvSQLiteMaxOpenConns, DefaultMaxOpenConns))
+	db.SetMaxIdleConns(env.GetInt(env.EnvSQLiteMaxIdleConns, DefaultMaxIdleConns))
+
+	// Connections open lazily; fail at startup on a bad setting instead
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// openReplica opens a read-only replica of a SQLite database, e.g. a LiteFS
+// mount. It is never written to, so only the connection settings apply.
+func openReplica(path string) (*sql.DB, error) {
+	params := url.Values{}
+	params.Set("mode", "ro")
+	params.Set("_query_only", "true")
+	params.Set("_busy_timeout", strconv.FormatInt(env.GetDuration(env.EnvSQLiteBusyTimeout, DefaultBusyTimeout).Milliseconds(), 10))
+
+	db := open(path, "file:"+path+"?"+params.Encode())
+	db.SetMaxOpenConns(env.GetInt(env.EnvSQLiteMaxOpenConns, DefaultMaxOpenConns))
+	db.SetMaxIdleConns(env.GetInt(env.EnvSQLiteMaxIdleConns, DefaultMaxIdleConns))
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open replica %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// open opens a SQLite database through a driver that logs its slow statements, named in
+// the log after the file, unless SQLITE_SLOW_QUERY is 0
+func open(path, dsn string) *sql.DB {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return slowquery.Open(&sqlite3.SQLiteDriver{}, dsn, name, env.GetDuration(env.EnvSQLiteSlowQuery, DefaultSlowQuery))
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"API/internal/env"
+	"API/internal/storage"
+	"API/internal/v0/schedule"
+	"fmt"
+)
+
+// defaultImageDir is where food images are kept when neither IMAGE_DIR nor
+// IMAGE_S3_BUCKET is set
+const defaultImageDir = "./internal/images"
+
+// imageStore returns the food image store of a tenant, in IMAGE_S3_BUCKET or
+// on local disk. Without IMAGE_BASE_URL the API serves the images itself, or
+// redirects to signed URLs of the bucket with IMAGE_SIGNED_URL_EXPIRY.
+func imageStore(tenantID string) (*schedule.ImageStore, error) {
+	dir := env.GetEnv(env.EnvImageDir, "")
+	bucket := env.GetEnv(env.EnvImageS3Bucket, "")
+
+	var store storage.Storage
+	switch {
+	case dir != "" && bucket != "":
+		return nil, fmt.Errorf("%s cannot be combined with %s", env.EnvImageDir, env.EnvImageS3Bucket)
+	case bucket != "":
+		region := env.GetEnv(env.EnvImageS3Region, "us-east-1")
+		accessKey := env.GetSecret(env.EnvImageS3AccessKey, "")
+		secretKey := env.GetSecret(env.EnvImageS3SecretKey, "")
+		if accessKey == "" || secretKey == "" {
+			return nil, fmt.Errorf("%s needs %s and %s", env.EnvImageS3Bucket, env.EnvImageS3AccessKey, env.EnvImageS3SecretKey)
+		}
+		endpoint := env.GetEnv(env.EnvImageS3Endpoint, "https://s3."+region+".amazonaws.com")
+		s3 := storage.NewS3Storage(endpoint, region, bucket, accessKey, secretKey)
+		if public := env.GetEnv(env.EnvImageS3PublicEndpoint, ""); public != "" {
+			s3.SetPublicEndpoint(public)
+		}
+		store = s3
+	default:
+		if dir == "" {
+			dir = defaultImageDir
+		}
+		store = storage.NewDirStorage(dir)
+	}
+
+	baseURL := env.GetEnv(env.EnvImageBaseURL, "")
+	expiry := env.GetDuration(env.EnvImageSignedURLExpiry, 0)
+	switch {
+	case baseURL != "" && expiry > 0:
+		return nil, fmt.Errorf("%s cannot be combined with %s", env.EnvImageBaseURL, env.EnvImageSignedURLExpiry)
+	case baseURL != "":
+		return schedule.NewImageStore(store, tenantID+"/", baseURL, true), nil
+	}
+	images := schedule.NewImageStore(store, tenantID+"/", "/api/v0/images", false)
+	if expiry > 0 {
+		if expiry > storage.MaxSignedURLExpiry {
+			return nil, fmt.Errorf("%s may be at most %s", env.EnvImageSignedURLExpiry, storage.MaxSignedURLExpiry)
+		}
+		if !images.SignURLs(expiry) {
+			return nil, fmt.Errorf("%s needs %s", env.EnvImageSignedURLExpiry, env.EnvImageS3Bucket)
+		}
+	}
+	return images, nil
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
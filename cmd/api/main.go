@@ -2,14 +2,47 @@ package main
 
 import (
 	"API/internal/auth"
+	"API/internal/backup"
 	"API/internal/common"
+	"API/internal/databases/migrations"
 	"API/internal/env"
+	"API/internal/events"
+	"API/internal/health"
+	"API/internal/limits"
+	"API/internal/logging"
+	"API/internal/metrics"
+	"API/internal/realtime"
+	"API/internal/rpc"
+	"API/internal/tenant"
+	"API/internal/v0/caldav"
+	"API/internal/v0/courses"
+	"API/internal/v0/directory"
+	"API/internal/v0/eclass"
+	campusevents "API/internal/v0/events"
+	"API/internal/v0/jobs"
+	"API/internal/v0/library"
+	"API/internal/v0/maps"
+	"API/internal/v0/news"
+	"API/internal/v0/postings"
+	"API/internal/v0/printing"
 	"API/internal/v0/schedule"
+	"API/internal/v0/sports"
+	"API/internal/v0/stream"
+	"API/internal/v0/webhooks"
+	"API/internal/v0/ws"
 	"context"
+	"crypto/tls"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -20,50 +53,384 @@ import (
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
+	envErr := godotenv.Load()
+
+	// Requests and the standard logger both write structured lines
+	slog.SetDefault(logging.NewLogger(env.GetEnv(env.EnvLogFormat, "json")))
+	if envErr != nil {
 		log.Println("No .env file found, using system environment variables")
 	}
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Schedule database
-	scheduleDB, err := sql.Open("sqlite3", "./internal/databases/schedule.db")
+	// Secrets left out of the environment may come from Vault
+	if err := env.LoadVault(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	tenants, err := tenant.Load()
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer scheduleDB.Close()
 
-	// Auth database
-	authDB, err := sql.Open("sqlite3", "./internal/databases/auth.db")
+	// Every tenant gets its own databases, stores and router; requests are dispatched by host
+	hostRouter := tenant.NewHostRouter()
+	rpcRouter := tenant.NewHostRouter()
+	checker := health.NewChecker()
+	registry := metrics.NewRegistry(env.GetSecret(env.EnvMetricsToken, ""))
+	var stops []func()
+	for _, t := range tenants {
+		handler, rpcHandler, stop, err := newTenantServer(ctx, t, checker, registry)
+		if err != nil {
+			log.Fatalf("Failed to start tenant %s: %v", t.ID, err)
+		}
+		hostRouter.Add(t, handler)
+		rpcRouter.Add(t, rpcHandler)
+		stops = append(stops, stop)
+	}
+	defer func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}()
+
+	listener, err := listen()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Small deployments without a reverse proxy terminate TLS themselves
+	tlsConf, redirectHandler, err := tlsConfig(tenants)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if tlsConf != nil {
+		listener = tls.NewListener(listener, tlsConf)
+	}
+	redirectServer := &http.Server{
+		Handler: redirectHandler,
+	}
+	if redirectHandler != nil {
+		redirectListener, err := listenRedirect()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if redirectListener != nil {
+			go func() {
+				log.Printf("Serving ACME challenges and HTTPS redirects on %s", redirectListener.Addr())
+				if err := redirectServer.Serve(redirectListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Fatal(err)
+				}
+			}()
+		}
+	}
+
+	// Probes are answered for any host, since orchestrators call the pod address
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", checker.Liveness)
+	mux.HandleFunc("GET /readyz", checker.Readiness)
+	mux.HandleFunc("GET /healthz/deep", checker.Deep)
+	mux.HandleFunc("GET /metrics", registry.Handler)
+	mux.Handle("/", hostRouter)
+
+	// Clients that trickle headers or idle on keep-alive must not hold connections
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	// gRPC calls arrive as cleartext HTTP/2 and are routed by :authority like
+	// HTTP requests are by Host
+	rpcListener, err := listenGRPC()
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer authDB.Close()
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	rpcServer := &http.Server{
+		Handler:   rpcRouter,
+		Protocols: &protocols,
+	}
+	if rpcListener != nil {
+		go func() {
+			log.Printf("Serving gRPC on %s", rpcListener.Addr())
+			if err := rpcServer.Serve(rpcListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	// Graceful shutdown handling
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		<-sigCh
+		log.Println("Shutting down...")
+		checker.Drain()
+		cancel()
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Warning: Failed to shut down server cleanly: %v", err)
+		}
+		if err := rpcServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Warning: Failed to shut down gRPC server cleanly: %v", err)
+		}
+		if err := redirectServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Warning: Failed to shut down redirect server cleanly: %v", err)
+		}
+	}()
+
+	scheme := listener.Addr().Network()
+	if tlsConf != nil {
+		scheme += "+tls"
+	}
+	log.Printf("Listening on %s://%s", scheme, listener.Addr())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+// listen opens the server listener from HOST/PORT, or from LISTEN_SOCKET for
+// reverse-proxy setups, rejecting invalid values before anything is served.
+func listen() (net.Listener, error) {
+	if socketPath := env.GetEnv(env.EnvListenSocket, ""); socketPath != "" {
+		// A socket left behind by an unclean shutdown would make Listen fail
+		if info, err := os.Stat(socketPath); err == nil {
+			if info.Mode()&os.ModeSocket == 0 {
+				return nil, fmt.Errorf("%s: %s exists and is not a socket", env.EnvListenSocket, socketPath)
+			}
+			if err := os.Remove(socketPath); err != nil {
+				return nil, fmt.Errorf("failed to remove stale socket %s: %w", socketPath, err)
+			}
+		}
+		listener, err := net.Listen("unix", socketPath)
+		if err != nil {
+			return nil, err
+		}
+		// Let the reverse proxy (usually another user in the same group) connect
+		if err := os.Chmod(socketPath, 0660); err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+		}
+		return listener, nil
+	}
+
+	host := env.GetEnv(env.EnvHost, "")
+	portValue := env.GetEnv(env.EnvPort, strconv.Itoa(env.DefaultPort))
+	port, err := strconv.Atoi(portValue)
+	if err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("%s: invalid port %q", env.EnvPort, portValue)
+	}
+	if strings.ContainsAny(host, "/ ") {
+		return nil, fmt.Errorf("%s: invalid host %q", env.EnvHost, host)
+	}
+	return net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+}
+
+// listenGRPC opens the gRPC listener on HOST:GRPC_PORT, or returns nil when
+// GRPC_PORT is unset
+func listenGRPC() (net.Listener, error) {
+	portValue := env.GetEnv(env.EnvGRPCPort, "")
+	if portValue == "" {
+		return nil, nil
+	}
+	port, err := strconv.Atoi(portValue)
+	if err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("%s: invalid port %q", env.EnvGRPCPort, portValue)
+	}
+	host := env.GetEnv(env.EnvHost, "")
+	if strings.ContainsAny(host, "/ ") {
+		return nil, fmt.Errorf("%s: invalid host %q", env.EnvHost, host)
+	}
+	return net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+}
+
+// newTenantServer wires up all components for a single tenant and returns its
+// HTTP and gRPC handlers along with a function that releases its resources.
+// The tenant's databases and background loops are registered with checker, and its
+// request metrics with registry.
+func newTenantServer(ctx context.Context, t tenant.Tenant, checker *health.Checker, registry *metrics.Registry) (http.Handler, http.Handler, func(), error) {
+	backupStore, err := backupStorage()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	images, err := imageStore(t.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	// Enable WAL mode for auth database (better concurrent performance)
-	if _, err := authDB.Exec("PRAGMA journal_mode=WAL"); err != nil {
-		log.Printf("Warning: Failed to enable WAL mode: %v", err)
+	// Every module's database, closed in reverse on a failed setup and by stop
+	var closers []func() error
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
 	}
+	dbs := make(map[string]*sql.DB)
+	for _, d := range tenantDatabases(t) {
+		db, err := openDatabase(d.path)
+		if err != nil {
+			closeAll()
+			return nil, nil, nil, err
+		}
+		closers = append(closers, db.Close)
+		dbs[d.name] = db
+	}
+
+	// Apply pending migrations, so a deployment cannot run against an older
+	// schema. Disable with AUTO_MIGRATE=false to run cmd/migrate by hand.
+	if env.GetBool(env.EnvAutoMigrate, true) {
+		for _, d := range tenantDatabases(t) {
+			if err := migrations.Up(d.name, d.path); err != nil {
+				closeAll()
+				return nil, nil, nil, err
+			}
+		}
+	}
+
+	// Open-data reads can be served by a read-only replica of the schedule
+	var scheduleReplica *sql.DB
+	if t.Datasets.ScheduleReplicaDB != "" {
+		scheduleReplica, err = openReplica(t.Datasets.ScheduleReplicaDB)
+		if err != nil {
+			closeAll()
+			return nil, nil, nil, err
+		}
+		closers = append(closers, scheduleReplica.Close)
+	}
+	scheduleDB, authDB, webhooksDB := dbs["schedule"], dbs["auth"], dbs["webhooks"]
+
+	// Modules publish domain events here instead of calling each other directly
+	bus := events.NewBus()
+
+	// Durable subscribers (webhooks, push, email) consume events through the
+	// per-database outboxes so deliveries survive restarts
+	scheduleOutbox := events.NewOutbox(scheduleDB)
+	authOutbox := events.NewOutbox(authDB)
+
+	// Integrations (e.g. cafeteria signage) are notified of new schedule versions
+	// and announcements
+	schedule.SubscribeWebhooks(scheduleOutbox, t.Webhooks.SchedulePublished, t.Webhooks.AnnouncementPublished, t.Webhooks.Secret)
 
 	// Initialize schedule components
-	schedRepo := schedule.NewRepository(scheduleDB)
-	schedHandler := schedule.NewHandler(schedRepo)
+	schedRepo := schedule.NewRepository(scheduleDB, scheduleOutbox)
+	if scheduleReplica != nil {
+		schedRepo.SetReplica(scheduleReplica)
+	}
+	schedRepo.SetImages(images)
+	schedHandler := schedule.NewHandler(schedRepo, bus)
+
+	// Initialize course catalog components
+	coursesHandler := courses.NewHandler(courses.NewRepository(dbs["courses"]))
+
+	// Initialize campus map components
+	mapsHandler := maps.NewHandler(maps.NewRepository(dbs["maps"]))
+
+	// Initialize staff directory components
+	directoryHandler := directory.NewHandler(directory.NewRepository(dbs["directory"]))
+
+	// Initialize university events components
+	eventsRepo := campusevents.NewRepository(dbs["events"])
+	eventsHandler := campusevents.NewHandler(eventsRepo)
+
+	// Initialize library components
+	libraryRepo := library.NewRepository(dbs["library"])
+	libraryHandler := library.NewHandler(libraryRepo)
+	libraryHandler.SetBookingLimits(library.BookingLimits{
+		MaxActive:   env.GetInt(env.EnvLibraryMaxActiveBookings, library.DefaultBookingLimits.MaxActive),
+		MaxDuration: env.GetDuration(env.EnvLibraryMaxBookingLength, library.DefaultBookingLimits.MaxDuration),
+		Window:      env.GetDuration(env.EnvLibraryBookingWindow, library.DefaultBookingLimits.Window),
+	})
+
+	// Initialize news aggregation components
+	newsRepo := news.NewRepository(dbs["news"])
+	newsAggregator := news.NewAggregator(newsRepo, env.GetDuration(env.EnvNewsFetchInterval, news.DefaultFetchInterval))
+	newsHandler := news.NewHandler(newsRepo, newsAggregator)
+
+	// Initialize sports facilities components
+	sportsRepo := sports.NewRepository(dbs["sports"])
+	sportsHandler := sports.NewHandler(sportsRepo)
+	sportsHandler.SetBookingLimits(sports.BookingLimits{
+		MaxActive: env.GetInt(env.EnvSportsMaxActiveBookings, sports.DefaultBookingLimits.MaxActive),
+		Window:    env.GetDuration(env.EnvSportsBookingWindow, sports.DefaultBookingLimits.Window),
+	})
+
+	// Initialize postings components
+	postingsHandler := postings.NewHandler(postings.NewRepository(dbs["postings"]))
+
+	// Initialize jobs components
+	jobsHandler := jobs.NewHandler(jobs.NewRepository(dbs["jobs"]))
+
+	// Initialize printing components
+	printingHandler := printing.NewHandler(printing.NewRepository(dbs["printing"]))
+	printingHandler.SetThresholds(printing.Thresholds{
+		LowToner:   env.GetInt(env.EnvPrintingLowToner, printing.DefaultThresholds.LowToner),
+		StaleAfter: env.GetDuration(env.EnvPrintingStaleAfter, printing.DefaultThresholds.StaleAfter),
+	})
+
+	// The mobile apps are pushed new menus and announcements through FCM topics
+	if t.Push.FCMCredentialsFile != "" {
+		pusher, err := schedule.NewFCMPusher(t.Push.FCMCredentialsFile)
+		if err != nil {
+			closeAll()
+			return nil, nil, nil, err
+		}
+		schedule.SubscribePush(scheduleOutbox, schedRepo, pusher, t.Push.Topic)
+	}
+
+	// is_current of announcements follows their dates as days pass
+	announcementMaintainer := schedule.NewAnnouncementMaintainer(schedRepo)
+	announcementMaintainer.Start(ctx)
+
+	// The day's menu is posted to the Discord and Telegram channels admins set up
+	menuPoster := schedule.NewMenuPoster(schedRepo)
+	menuPoster.Start(ctx)
+	schedHandler.SetMenuPoster(menuPoster)
+
+	// Live updates (server-sent events and WebSocket) are refreshed when the schedule
+	// module publishes or a library reports its occupancy, and polled for what nothing
+	// announces (other instances, announcements starting)
+	hub := realtime.NewHub(env.GetDuration(env.EnvStreamPollInterval, realtime.DefaultPollInterval), schedule.Languages...)
+	hub.Register(schedule.Topics(schedRepo)...)
+	hub.Register(library.Topics(libraryRepo)...)
+	libraryHandler.SetHub(hub)
+	bus.Subscribe(events.TypeAnnouncementPublished, func(context.Context, events.Event) { hub.Notify() })
+	bus.Subscribe(events.TypeScheduleVersionPublished, func(context.Context, events.Event) { hub.Notify() })
+	hub.Start(ctx)
+
+	// The departments' news feeds are fetched in the background
+	newsAggregator.Start(ctx)
+
+	if command := env.GetEnv(env.EnvOCRCommand, ""); command != "" {
+		schedHandler.SetOCR(schedule.NewCommandOCR(command))
+	}
 
 	// Initialize auth components
-	authRepo := auth.NewRepository(authDB)
+	authRepo := auth.NewRepository(authDB, authOutbox)
+	schedHandler.SetPreferences(authRepo)
+
+	// Make sure the tenant's academic domains are known
+	for _, domain := range t.AcademicDomains {
+		if err := authRepo.AddAcademicDomain(ctx, domain); err != nil {
+			log.Printf("Warning: Failed to add academic domain %s for tenant %s: %v", domain, t.ID, err)
+		}
+	}
 
 	// OAuth configuration
 	oauthConfig := auth.NewOAuthConfig(
 		auth.ProviderConfig{
-			ClientID:     env.GetEnv(env.EnvGoogleClientID, ""),
-			ClientSecret: env.GetEnv(env.EnvGoogleClientSecret, ""),
+			ClientID:     t.OAuth.Google.ClientID,
+			ClientSecret: t.OAuth.Google.ClientSecret,
 		},
 		auth.ProviderConfig{
-			ClientID:     env.GetEnv(env.EnvGitHubClientID, ""),
-			ClientSecret: env.GetEnv(env.EnvGitHubClientSecret, ""),
+			ClientID:     t.OAuth.GitHub.ClientID,
+			ClientSecret: t.OAuth.GitHub.ClientSecret,
 		},
-		env.GetEnv(env.EnvAuthCallbackBaseURL, "http://localhost:9237"),
+		t.OAuth.CallbackBaseURL,
 	)
 
 	// Auth stores
@@ -72,14 +439,141 @@ func main() {
 		authRepo,
 		env.GetDuration(env.EnvSessionDuration, 7*24*time.Hour),
 		env.GetBool(env.EnvSecureCookies, false),
+		auth.SessionLimitPolicy(env.GetEnv(env.EnvSessionLimitPolicy, string(auth.SessionLimitEvict))),
 	)
 	featureRegistry := auth.NewFeatureRegistry(authRepo)
-	tokenStore := auth.NewTokenStore(authRepo, featureRegistry)
-	quotaEngine := auth.NewQuotaEngine(authRepo, featureRegistry)
-	usageTracker := auth.NewUsageTracker(authRepo, stateStore, sessionStore)
+	// Modules declare the features their routes require; missing ones are created
+	for _, features := range [][]auth.FeatureDefinition{auth.Features, schedule.Features, courses.Features, maps.Features, directory.Features, campusevents.Features, library.Features, news.Features, sports.Features, postings.Features, jobs.Features, printing.Features, webhooks.Features, stream.Features, ws.Features, eclass.Features, caldav.Features} {
+		if err := featureRegistry.SyncFeatures(ctx, features); err != nil {
+			log.Printf("Warning: Failed to register features for tenant %s: %v", t.ID, err)
+		}
+	}
+	tokenStore := auth.NewTokenStore(authRepo, featureRegistry, t.TokenPrefix, bus)
+	surgeSchedule := auth.NewSurgeSchedule(authRepo)
+	quotaEngine := auth.NewQuotaEngine(authRepo, featureRegistry, surgeSchedule)
+	idempotencyStore := auth.NewIdempotencyStore(authRepo)
+	usageTracker := auth.NewUsageTracker(authRepo, stateStore, sessionStore, idempotencyStore)
+	hooks := auth.NewHookRegistry()
+	diagnostics := auth.NewDiagnosticsStore(auth.DiagnosticsTTL)
+	errorLog := logging.NewErrorLog(env.GetInt(env.EnvErrorLogSize, logging.DefaultErrorLogSize))
+	sampling := auth.NewSamplingStore()
+	throttle := auth.NewTokenThrottle()
 
 	// Start usage tracker background goroutines
 	usageTracker.Start(ctx)
+	surgeSchedule.Start(ctx)
+
+	// Course workspaces count their students' requests through a post-usage hook
+	workspaceStore := auth.NewWorkspaceStore(authRepo, tokenStore, featureRegistry, usageTracker)
+	hooks.Register(auth.HookPostUsage, workspaceStore.RecordUsage)
+	workspaceStore.Start(ctx)
+
+	// Hourly per-token counts behind the admin feature usage leaderboard
+	featureUsage := auth.NewFeatureUsageStore(authRepo)
+	hooks.Register(auth.HookPostUsage, featureUsage.RecordUsage)
+	featureUsage.Start(ctx)
+
+	// Admin-registered webhooks subscribe to the auth outbox before it starts
+	webhookStore := auth.NewWebhookStore(authRepo, authOutbox)
+	if err := webhookStore.Load(ctx); err != nil {
+		log.Printf("Warning: Failed to load webhooks for tenant %s: %v", t.ID, err)
+	}
+
+	// Token holders' webhooks copy the events their tokens are entitled to from the
+	// schedule and auth outboxes into their own, which keeps their delivery logs
+	webhooksOutbox := events.NewOutbox(webhooksDB)
+	webhooksRepo := webhooks.NewRepository(webhooksDB)
+	webhookDispatcher := webhooks.NewDispatcher(webhooksRepo, webhooksOutbox, tokenStore)
+	if env.GetBool(env.EnvWebhooksAllowPrivateURLs, false) {
+		webhookDispatcher.AllowPrivateNetworks()
+	}
+	webhookDispatcher.Listen(scheduleOutbox, authOutbox)
+	if err := webhookDispatcher.Load(ctx); err != nil {
+		log.Printf("Warning: Failed to load token webhooks for tenant %s: %v", t.ID, err)
+	}
+	webhooksHandler := webhooks.NewHandler(webhooksRepo, webhookDispatcher)
+	streamHandler := stream.NewHandler(hub, tokenStore)
+	wsHandler := ws.NewHandler(hub, tokenStore)
+
+	// The eClass proxy answers 404 without a site or a key for the stored tokens, and
+	// unlinks the accounts of deleted users
+	eclassRepo := eclass.NewRepository(dbs["eclass"], t.EClass.TokenKey)
+	eclass.SubscribeUserDeletions(authOutbox, eclassRepo)
+	var eclassClient *eclass.Client
+	switch {
+	case t.EClass.URL == "":
+	case t.EClass.TokenKey == "":
+		log.Printf("Warning: eClass proxy for tenant %s is disabled until %s is set", t.ID, env.EnvEClassTokenKey)
+	default:
+		if n, err := eclassRepo.EncryptStoredTokens(ctx); err != nil {
+			log.Printf("Warning: Failed to encrypt the stored eClass tokens of tenant %s: %v", t.ID, err)
+		} else if n > 0 {
+			log.Printf("Encrypted %d stored eClass tokens of tenant %s", n, t.ID)
+		}
+		eclassClient = eclass.NewClient(t.EClass.URL, t.EClass.Service)
+	}
+	eclassHandler := eclass.NewHandler(eclassRepo, eclassClient)
+
+	// CalDAV account combining the calendars the modules publish
+	caldavHandler := caldav.NewHandler(tokenStore)
+	caldavHandler.Register(schedule.Calendars(schedRepo)...)
+	caldavHandler.Register(campusevents.Calendars(eventsRepo)...)
+	caldavHandler.Register(library.Calendars(libraryRepo)...)
+	caldavHandler.Register(sports.Calendars(sportsRepo)...)
+
+	// Start outbox dispatchers
+	scheduleOutbox.Start(ctx)
+	authOutbox.Start(ctx)
+	webhooksOutbox.Start(ctx)
+
+	// Snapshots of every database, on a schedule and on demand; tenants
+	// sharing a bucket or directory are kept apart by their ID
+	var backups *backup.Manager
+	backupInterval := env.GetDuration(env.EnvBackupInterval, backup.DefaultInterval)
+	if backupStore != nil {
+		backups = backup.NewManager(backupStore, t.ID+"/", env.GetInt(env.EnvBackupKeep, backup.DefaultKeep), backupInterval)
+		for _, d := range tenantDatabases(t) {
+			backups.AddDatabase(d.name, dbs[d.name])
+		}
+		if backupInterval > 0 {
+			backups.Start(ctx)
+			checker.AddHeartbeat(t.ID+"/backups", backups.Heartbeat())
+		}
+	}
+
+	// Readiness needs every database migrated and answering; both probes
+	// need the background loops alive
+	for _, d := range tenantDatabases(t) {
+		checker.AddCheck(t.ID+"/"+d.name+"-db", health.Database(dbs[d.name]))
+
+		// The migrations are embedded, so this only fails on a broken build
+		latest, err := migrations.Latest(d.name)
+		if err != nil {
+			log.Fatalf("Failed to read %s migrations: %v", d.name, err)
+		}
+		checker.AddCheck(t.ID+"/"+d.name+"-migrations", health.Migrations(dbs[d.name], latest))
+	}
+	if scheduleReplica != nil {
+		checker.AddCheck(t.ID+"/schedule-replica-db", health.Database(scheduleReplica))
+	}
+	checker.AddHeartbeat(t.ID+"/usage-tracker", usageTracker.Heartbeat())
+	checker.AddHeartbeat(t.ID+"/usage-cleanup", usageTracker.CleanupHeartbeat())
+	checker.AddHeartbeat(t.ID+"/surge-schedule", surgeSchedule.Heartbeat())
+	checker.AddHeartbeat(t.ID+"/workspace-usage", workspaceStore.Heartbeat())
+	checker.AddHeartbeat(t.ID+"/feature-usage", featureUsage.Heartbeat())
+	checker.AddHeartbeat(t.ID+"/auth-outbox", authOutbox.Heartbeat())
+	checker.AddHeartbeat(t.ID+"/schedule-outbox", scheduleOutbox.Heartbeat())
+	checker.AddHeartbeat(t.ID+"/webhooks-outbox", webhooksOutbox.Heartbeat())
+	checker.AddHeartbeat(t.ID+"/announcements", announcementMaintainer.Heartbeat())
+	checker.AddHeartbeat(t.ID+"/menu-channels", menuPoster.Heartbeat())
+	checker.AddHeartbeat(t.ID+"/news-feeds", newsAggregator.Heartbeat())
+	checker.AddHeartbeat(t.ID+"/stream", hub.Heartbeat())
+
+	// The deep report also checks that the background subsystems keep up
+	checker.AddDeepCheck(t.ID+"/usage-backlog", usageTracker.Backlog)
+	checker.AddDeepCheck(t.ID+"/auth-outbox-backlog", authOutbox.Backlog)
+	checker.AddDeepCheck(t.ID+"/schedule-outbox-backlog", scheduleOutbox.Backlog)
+	checker.AddDeepCheck(t.ID+"/webhooks-outbox-backlog", webhooksOutbox.Backlog)
 
 	// Auth handlers
 	authHandler := auth.NewHandler(
@@ -89,6 +583,9 @@ func main() {
 		sessionStore,
 		tokenStore,
 		featureRegistry,
+		quotaEngine,
+		bus,
+		env.GetList(env.EnvAdminBootstrapEmails, nil),
 	)
 	adminHandler := auth.NewAdminHandler(
 		authRepo,
@@ -96,47 +593,143 @@ func main() {
 		featureRegistry,
 		quotaEngine,
 		usageTracker,
+		diagnostics,
+		webhookStore,
+		surgeSchedule,
+		featureUsage,
+		errorLog,
+		sampling,
+		throttle,
 	)
+	staffHandler := auth.NewStaffHandler(workspaceStore)
 	authMiddleware := auth.NewMiddleware(
 		tokenStore,
 		sessionStore,
 		featureRegistry,
 		quotaEngine,
 		usageTracker,
+		hooks,
+		diagnostics,
+		auth.NewRequestCoalescer(),
+		idempotencyStore,
+		throttle,
 	)
+	authMiddleware.SetMetrics(registry.Tenant(t.ID, authRepo.GroupNames))
 
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(logging.Middleware(slog.Default().With(slog.String("tenant", t.ID))))
+	router.Use(errorLog.Middleware())
+	router.Use(sampling.Middleware())
+	router.Use(limits.Middleware(limits.Limits{
+		MaxBodyBytes: int64(env.GetInt(env.EnvMaxBodyBytes, limits.DefaultMaxBodyBytes)),
+		Timeout:      env.GetDuration(env.EnvRequestTimeout, limits.DefaultTimeout),
+	}))
 
 	// Global routes
 	global := router.Group("/api")
 	common.RegisterRoutes(global)
+	tenant.RegisterRoutes(global, t)
 
 	// Auth routes (public + session-protected + admin)
-	auth.RegisterRoutes(global, authHandler, adminHandler, authMiddleware)
+	auth.RegisterRoutes(global, authHandler, adminHandler, staffHandler, authMiddleware)
 
 	// v0 API routes
 	v0Group := router.Group("/api/v0")
 	{
 		// Schedule routes (protected by token)
 		schedule.RegisterRoutes(v0Group, schedHandler, authMiddleware)
+
+		// Course catalog routes (protected by token)
+		courses.RegisterRoutes(v0Group, coursesHandler, authMiddleware)
+
+		// Campus map routes (protected by token)
+		maps.RegisterRoutes(v0Group, mapsHandler, authMiddleware)
+
+		// Staff directory routes (protected by token)
+		directory.RegisterRoutes(v0Group, directoryHandler, authMiddleware)
+
+		// University events routes (protected by token, submissions by session)
+		campusevents.RegisterRoutes(v0Group, eventsHandler, authMiddleware)
+
+		// Library routes (protected by token, occupancy by admin-issued tokens)
+		library.RegisterRoutes(v0Group, libraryHandler, authMiddleware)
+
+		// News routes (protected by token)
+		news.RegisterRoutes(v0Group, newsHandler, authMiddleware)
+
+		// Sports routes (protected by token, bookings need the sports.bookings feature)
+		sports.RegisterRoutes(v0Group, sportsHandler, authMiddleware)
+
+		// Thesis and internship postings routes (protected by token, publishing by data editors)
+		postings.RegisterRoutes(v0Group, postingsHandler, authMiddleware)
+
+		// Job board routes (protected by token, submissions approved by admins)
+		jobs.RegisterRoutes(v0Group, jobsHandler, authMiddleware)
+
+		// Print station routes (protected by token, status reported by the print server)
+		printing.RegisterRoutes(v0Group, printingHandler, authMiddleware)
+
+		// Webhook subscription routes (protected by token, scoped to the calling token)
+		webhooks.RegisterRoutes(v0Group, webhooksHandler, authMiddleware)
+
+		// Live updates stream (protected by token, charged once per connection)
+		stream.RegisterRoutes(v0Group, streamHandler, authMiddleware)
+
+		// Realtime WebSocket routes (protected by token, charged once per connection)
+		ws.RegisterRoutes(v0Group, wsHandler, authMiddleware)
+
+		// eClass proxy routes (protected by token, scoped to the token's user)
+		eclass.RegisterRoutes(v0Group, eclassHandler, authMiddleware)
+
+		// CalDAV routes (protected by token, also accepted as a Basic password)
+		caldav.RegisterRoutes(v0Group, caldavHandler, authMiddleware)
 	}
 
-	router.StaticFile("/favicon.ico", "./internal/assets/logo.svg")
+	caldav.RegisterWellKnown(router, v0Group)
 
-	// Graceful shutdown handling
-	go func() {
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-		<-sigCh
-		log.Println("Shutting down...")
-		cancel()
+	if backups != nil {
+		backup.RegisterRoutes(global, backup.NewHandler(backups), authMiddleware)
+	}
+
+	router.StaticFile("/favicon.ico", t.Branding.LogoPath)
+
+	// gRPC services share the repositories and token checks of the routes
+	rpcServer := rpc.NewServer(authMiddleware.UnaryInterceptor())
+	auth.RegisterGRPC(rpcServer, auth.NewTokenService(tokenStore, featureRegistry), authMiddleware)
+	schedule.RegisterGRPC(rpcServer, schedule.NewService(schedRepo), authMiddleware)
+
+	stop := func() {
+		if backups != nil {
+			backups.Stop()
+		}
+		workspaceStore.Stop()
+		featureUsage.Stop()
+		surgeSchedule.Stop()
+		announcementMaintainer.Stop()
+		menuPoster.Stop()
+		newsAggregator.Stop()
+		hub.Stop()
 		usageTracker.Stop()
-	}()
+		authOutbox.Stop()
+		scheduleOutbox.Stop()
+		webhooksOutbox.Stop()
+		closeAll()
+	}
 
-	err = router.Run(":9237")
-	if err != nil {
-		log.Fatal(err)
+	// A route whose feature does not exist would answer every request with a 500
+	if err := authMiddleware.CheckRouteFeatures(ctx); err != nil {
+		stop()
+		return nil, nil, nil, fmt.Errorf("tenant %s: %w", t.ID, err)
+	}
+
+	// gin believes X-Forwarded-For from any peer by default, which would let clients pick
+	// the address the token throttle, IP allowlists and logs see
+	if err := router.SetTrustedProxies(env.GetList(env.EnvTrustedProxies, nil)); err != nil {
+		stop()
+		return nil, nil, nil, fmt.Errorf("%s: %w", env.EnvTrustedProxies, err)
 	}
+	return router, rpcServer, stop, nil
 }
 
 /*
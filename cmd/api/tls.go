@@ -0,0 +1,99 @@
+package main
+
+import (
+	"API/internal/env"
+	"API/internal/tenant"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// DefaultAutocertCache is where Let's Encrypt certificates are kept between
+// restarts when TLS_AUTOCERT_CACHE is unset
+const DefaultAutocertCache = "./internal/databases/autocert"
+
+// tlsConfig returns the TLS configuration selected by the environment, or nil
+// to serve cleartext behind a proxy. With autocert it also returns the handler
+// for the cleartext port, which answers ACME HTTP-01 challenges and redirects
+// everything else to HTTPS.
+func tlsConfig(tenants []tenant.Tenant) (*tls.Config, http.Handler, error) {
+	certFile := env.GetEnv(env.EnvTLSCertFile, "")
+	keyFile := env.GetEnv(env.EnvTLSKeyFile, "")
+	autocertEnabled := env.GetBool(env.EnvTLSAutocert, false)
+
+	switch {
+	case autocertEnabled && (certFile != "" || keyFile != ""):
+		return nil, nil, fmt.Errorf("%s cannot be combined with %s/%s", env.EnvTLSAutocert, env.EnvTLSCertFile, env.EnvTLSKeyFile)
+	case certFile != "" || keyFile != "":
+		if certFile == "" || keyFile == "" {
+			return nil, nil, fmt.Errorf("%s and %s must be set together", env.EnvTLSCertFile, env.EnvTLSKeyFile)
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		return &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+			NextProtos:   []string{"h2", "http/1.1"},
+		}, nil, nil
+	case autocertEnabled:
+		// Certificates are only requested for hosts we serve, so stray SNI
+		// names cannot use up the Let's Encrypt rate limits
+		hosts := env.GetList(env.EnvTLSAutocertHosts, nil)
+		for _, t := range tenants {
+			hosts = append(hosts, t.Hosts...)
+		}
+		if len(hosts) == 0 {
+			return nil, nil, fmt.Errorf("%s needs tenant hosts or %s", env.EnvTLSAutocert, env.EnvTLSAutocertHosts)
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(env.GetEnv(env.EnvTLSAutocertCache, DefaultAutocertCache)),
+			HostPolicy: autocert.HostWhitelist(hosts...),
+			Email:      env.GetEnv(env.EnvTLSAutocertEmail, ""),
+		}
+		config := manager.TLSConfig()
+		config.MinVersion = tls.VersionTLS12
+		config.NextProtos = []string{"h2", "http/1.1", acme.ALPNProto}
+		return config, manager.HTTPHandler(nil), nil
+	}
+	return nil, nil, nil
+}
+
+// listenRedirect opens the cleartext listener for the autocert handler on
+// HOST:TLS_REDIRECT_PORT, or returns nil when TLS_REDIRECT_PORT is unset. The
+// TLS-ALPN challenge on the main port works without it.
+func listenRedirect() (net.Listener, error) {
+	portValue := env.GetEnv(env.EnvTLSRedirectPort, "")
+	if portValue == "" {
+		return nil, nil
+	}
+	port, err := strconv.Atoi(portValue)
+	if err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("%s: invalid port %q", env.EnvTLSRedirectPort, portValue)
+	}
+	return net.Listen("tcp", net.JoinHostPort(env.GetEnv(env.EnvHost, ""), strconv.Itoa(port)))
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
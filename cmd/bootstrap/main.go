@@ -0,0 +1,80 @@
+package main
+
+import (
+	"API/internal/auth"
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Promotes an existing user to admin on a fresh deployment. The user must have
+// logged in once so that their account exists.
+func main() {
+	email := flag.String("email", "", "email of the user to promote")
+	dbFile := flag.String("db", "internal/databases/auth.db", "auth database file")
+	force := flag.Bool("force", false, "promote even if an admin already exists")
+	flag.Parse()
+
+	if *email == "" {
+		log.Fatal("-email is required")
+	}
+
+	db, err := sql.Open("sqlite3", *dbFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	repo := auth.NewRepository(db, nil)
+	ctx := context.Background()
+
+	user, err := repo.GetUserByEmail(ctx, *email)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if user == nil {
+		log.Fatalf("No user with email %s, log in once through OAuth first", *email)
+	}
+	if user.Role == auth.RoleAdmin {
+		log.Printf("%s is already an admin", user.Email)
+		return
+	}
+
+	if *force {
+		role := auth.RoleAdmin
+		if err := repo.UpdateUser(ctx, user.ID, &role, nil, nil, nil); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Promoted %s to admin", user.Email)
+		return
+	}
+
+	promoted, err := repo.BootstrapAdmin(ctx, user.ID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !promoted {
+		log.Fatal("An admin already exists, use -force to promote anyway")
+	}
+	log.Printf("Promoted %s to admin", user.Email)
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
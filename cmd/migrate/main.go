@@ -1,30 +1,123 @@
 package main
 
 import (
+	"API/internal/databases/migrations"
 	"errors"
 	"flag"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
 
 	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
 func main() {
 	path := flag.String("path", "schedule", "path to the database file")
+	db := flag.String("db", "", "database file to migrate (defaults to internal/databases/<path>.db)")
+	all := flag.Bool("all", false, "migrate every database in internal/databases")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), `Usage: migrate [flags] [command]
+
+Commands:
+  up          apply all pending migrations (default)
+  down        revert all migrations
+  goto V      migrate up or down to version V
+  steps N     apply N migrations, or revert them when N is negative
+  version     print the current version
+  force V     set the version without migrating, clearing the dirty flag
+
+Flags:
+`)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	m, err := migrate.New(
-		"file://internal/databases/migrations/"+*path,
-		"sqlite3://internal/databases/"+*path+".db",
-	)
+	if *all && *db != "" {
+		log.Fatal("-all cannot be combined with -db")
+	}
+
+	paths := []string{*path}
+	if *all {
+		paths = migrations.Databases
+	}
+	for _, name := range paths {
+		// Tenants other than the default keep their databases elsewhere
+		dbFile := *db
+		if dbFile == "" {
+			dbFile = "internal/databases/" + name + ".db"
+		}
+		if err := run(name, dbFile, flag.Args()); err != nil {
+			log.Fatalf("%s: %v", name, err)
+		}
+	}
+}
+
+// run applies one command to a database
+func run(name, dbFile string, args []string) error {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	// goto, steps and force take a number
+	var number int
+	switch command {
+	case "goto", "steps", "force":
+		if len(args) != 2 {
+			return fmt.Errorf("%s needs a number", command)
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", command, args[1])
+		}
+		number = n
+	case "up", "down", "version":
+		if len(args) > 1 {
+			return fmt.Errorf("%s takes no arguments", command)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	m, err := migrations.New(name, dbFile)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		log.Fatal(err)
+	defer m.Close()
+
+	switch command {
+	case "up":
+		err = m.Up()
+	case "down":
+		err = m.Down()
+	case "goto":
+		if number < 0 {
+			return fmt.Errorf("goto: invalid version %d", number)
+		}
+		err = m.Migrate(uint(number))
+	case "steps":
+		err = m.Steps(number)
+	case "force":
+		err = m.Force(number)
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	version, dirty, err := m.Version()
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+		log.Printf("%s (%s): no migrations applied", name, dbFile)
+	case err != nil:
+		return err
+	case dirty:
+		log.Printf("%s (%s): version %d (dirty, fix the schema and run force)", name, dbFile, version)
+	default:
+		log.Printf("%s (%s): version %d", name, dbFile, version)
 	}
-	log.Println("Database migration complete for the:", *path, "path")
+	return nil
 }
 
 /*
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Packages whose response helpers wrap the data of every response
+var responsePackages = map[string]bool{
+	"API/internal/common":    true,
+	"API/internal/v0/common": true,
+}
+
+// function is a declared function or method with the type information of its package
+type function struct {
+	decl *ast.FuncDecl
+	info *types.Info
+	pkg  *packages.Package
+}
+
+// handler is what static analysis learns about a route's handler
+type handler struct {
+	doc   string
+	query []queryParam
+	// body is the type bound from the request body, nil when there is none
+	body types.Type
+	// data are the expressions passed as the data of success responses, with the type
+	// information to read them
+	data []dataExpr
+	// raw handlers write something other than an APIResponse, e.g. an image or a calendar
+	raw bool
+	// redirect handlers send the browser elsewhere, e.g. to an OAuth provider
+	redirect bool
+	// websocket handlers upgrade the connection instead of answering
+	websocket bool
+}
+
+type queryParam struct {
+	name  string
+	array bool
+}
+
+type dataExpr struct {
+	expr ast.Expr
+	fn   *function
+}
+
+// analyzer finds handlers by the names gin gives them and reads their bodies
+type analyzer struct {
+	funcs map[string]*function // by gin's name, e.g. API/internal/v0/jobs.(*Handler).ListJobs
+	objs  map[*types.Func]*function
+}
+
+func newAnalyzer(pkgs []*packages.Package) *analyzer {
+	a := &analyzer{funcs: make(map[string]*function), objs: make(map[*types.Func]*function)}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if !strings.HasPrefix(pkg.PkgPath, modulePath+"/") {
+			return
+		}
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				fn := &function{decl: fd, info: pkg.TypesInfo, pkg: pkg}
+				a.funcs[pkg.PkgPath+"."+funcName(fd)] = fn
+				if obj, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+					a.objs[obj] = fn
+				}
+			}
+		}
+	})
+	return a
+}
+
+// funcName names a declaration the way the runtime does, e.g. (*Handler).ListJobs
+func funcName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	recv := fd.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		if ident, ok := star.X.(*ast.Ident); ok {
+			return "(*" + ident.Name + ")." + fd.Name.Name
+		}
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fd.Name.Name
+	}
+	return fd.Name.Name
+}
+
+// handler analyzes the handler gin names name, which is a method value
+// (pkg.(*Handler).Method-fm), a function (pkg.Function) or a closure (pkg.Function.func1)
+func (a *analyzer) handler(name string) (*handler, error) {
+	name = strings.TrimSuffix(name, "-fm")
+	if fn, ok := a.funcs[name]; ok {
+		h := &handler{doc: fn.decl.Doc.Text()}
+		a.walk(h, fn, fn.decl.Body, fn.pkg.PkgPath, map[*ast.BlockStmt]bool{})
+		return h, nil
+	}
+
+	// Closures are numbered in the order they appear in the enclosing function
+	i := strings.LastIndex(name, ".func")
+	if i < 0 {
+		return nil, fmt.Errorf("handler %s not found", name)
+	}
+	fn, ok := a.funcs[name[:i]]
+	if !ok {
+		return nil, fmt.Errorf("handler %s not found", name)
+	}
+	var n int
+	if _, err := fmt.Sscanf(name[i+len(".func"):], "%d", &n); err != nil {
+		return nil, fmt.Errorf("handler %s not found", name)
+	}
+	var lit *ast.FuncLit
+	count := 0
+	ast.Inspect(fn.decl.Body, func(node ast.Node) bool {
+		if l, ok := node.(*ast.FuncLit); ok {
+			if count++; count == n {
+				lit = l
+			}
+			return false
+		}
+		return lit == nil
+	})
+	if lit == nil {
+		return nil, fmt.Errorf("handler %s not found", name)
+	}
+	h := &handler{}
+	a.walk(h, fn, lit.Body, fn.pkg.PkgPath, map[*ast.BlockStmt]bool{})
+	return h, nil
+}
+
+// walk reads the calls in body. Calls to functions of the handler's package are followed
+// for everything; calls into other packages of the module only for the query parameters
+// they read, e.g. ?limit= and ?cursor= in pagination.FromQuery.
+func (a *analyzer) walk(h *handler, fn *function, body *ast.BlockStmt, handlerPkg string, seen map[*ast.BlockStmt]bool) {
+	if seen[body] {
+		return
+	}
+	seen[body] = true
+	local := fn.pkg.PkgPath == handlerPkg
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		callee, ok := typeutil.Callee(fn.info, call).(*types.Func)
+		if !ok || callee.Pkg() == nil {
+			return true
+		}
+
+		if isContextMethod(callee) {
+			switch callee.Name() {
+			case "Query", "DefaultQuery", "GetQuery":
+				h.addQuery(fn.info, call, false)
+			case "QueryArray", "GetQueryArray":
+				h.addQuery(fn.info, call, true)
+			case "ShouldBindJSON", "ShouldBind", "BindJSON", "Bind":
+				if local && h.body == nil && len(call.Args) == 1 {
+					if ptr, ok := fn.info.TypeOf(call.Args[0]).(*types.Pointer); ok {
+						h.body = ptr.Elem()
+					}
+				}
+			case "Data", "DataFromReader", "File", "FileAttachment", "Stream":
+				h.raw = h.raw || local
+			case "Redirect":
+				h.redirect = h.redirect || local
+			case "IsWebsocket":
+				h.websocket = h.websocket || local
+			}
+			return true
+		}
+
+		if responsePackages[callee.Pkg().Path()] {
+			switch callee.Name() {
+			case "CreateSuccessResponse", "CreatePaginatedResponse", "CreateSuccessResponseWithRequestID":
+				if local && len(call.Args) > 0 {
+					h.data = append(h.data, dataExpr{expr: call.Args[0], fn: fn})
+				}
+				return true
+			}
+			// The other helpers write responses, which would add their ?format= to every
+			// route; ParseLanguage reads ?lang=
+			if callee.Name() != "ParseLanguage" {
+				return true
+			}
+		}
+
+		if next, ok := a.objs[callee.Origin()]; ok && takesContext(callee) {
+			a.walk(h, next, next.decl.Body, handlerPkg, seen)
+		}
+		return true
+	})
+}
+
+func (h *handler) addQuery(info *types.Info, call *ast.CallExpr, array bool) {
+	if len(call.Args) == 0 {
+		return
+	}
+	tv, ok := info.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return
+	}
+	name := constant.StringVal(tv.Value)
+	for _, q := range h.query {
+		if q.name == name {
+			return
+		}
+	}
+	h.query = append(h.query, queryParam{name: name, array: array})
+}
+
+// isContextMethod reports whether fn is a method of *gin.Context
+func isContextMethod(fn *types.Func) bool {
+	sig := fn.Type().(*types.Signature)
+	return sig.Recv() != nil && isContext(sig.Recv().Type())
+}
+
+// takesContext reports whether fn has a *gin.Context parameter, which is how helpers
+// that read the request are told apart from the rest
+func takesContext(fn *types.Func) bool {
+	params := fn.Type().(*types.Signature).Params()
+	for i := 0; i < params.Len(); i++ {
+		if isContext(params.At(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+func isContext(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "github.com/gin-gonic/gin" &&
+		named.Obj().Name() == "Context"
+}
+
+// assignments returns the composite literal a gin.H variable was declared with, if any,
+// and the keys later set on it, so that built up responses are typed too
+func assignments(fn *function, ident *ast.Ident) (*ast.CompositeLit, []*ast.AssignStmt) {
+	obj := fn.info.ObjectOf(ident)
+	if obj == nil {
+		return nil, nil
+	}
+	var lit *ast.CompositeLit
+	var sets []*ast.AssignStmt
+	ast.Inspect(fn.decl.Body, func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			switch lhs := lhs.(type) {
+			case *ast.Ident:
+				if fn.info.ObjectOf(lhs) == obj {
+					if l, ok := ast.Unparen(assign.Rhs[i]).(*ast.CompositeLit); ok {
+						lit = l
+					}
+				}
+			case *ast.IndexExpr:
+				if x, ok := lhs.X.(*ast.Ident); ok && fn.info.ObjectOf(x) == obj {
+					sets = append(sets, assign)
+				}
+			}
+		}
+		return true
+	})
+	return lit, sets
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
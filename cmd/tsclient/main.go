@@ -0,0 +1,303 @@
+// Command tsclient generates the typed TypeScript client of the API for the web and
+// mobile frontends. Routes are read from the same RegisterRoutes functions cmd/api
+// calls; request bodies, query parameters and the data of each response from the
+// handlers' source.
+package main
+
+//go:generate go run . -o ../../clients/typescript/src/client.ts
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"API/internal/env"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const modulePath = "API"
+
+func main() {
+	out := flag.String("o", "clients/typescript/src/client.ts", "file to write the client to, - for stdout")
+	check := flag.Bool("check", false, "fail instead of writing when the file is out of date")
+	flag.Parse()
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes |
+			packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+	}, modulePath+"/internal/...")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		os.Exit(1)
+	}
+
+	endpoints, err := collect(pkgs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	source, err := generate(pkgs, endpoints)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch {
+	case *out == "-":
+		_, err = os.Stdout.Write(source)
+	case *check:
+		current, _ := os.ReadFile(*out)
+		if !bytes.Equal(current, source) {
+			log.Fatalf("%s is out of date; run go generate ./cmd/tsclient", *out)
+		}
+	default:
+		err = os.WriteFile(*out, source, 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// endpoint is a route with what its handler expects and answers
+type endpoint struct {
+	method, path string
+	namespace    string // the client's property grouping the module's endpoints
+	name         string // the method's name in its namespace
+	*handler
+}
+
+// collect analyzes the handler of every route
+func collect(pkgs []*packages.Package) ([]endpoint, error) {
+	a := newAnalyzer(pkgs)
+	var endpoints []endpoint
+	for _, route := range routes() {
+		// WebDAV methods and HEAD and OPTIONS are left to the clients speaking them
+		if _, ok := methodOrder[route.Method]; !ok {
+			continue
+		}
+		h, err := a.handler(route.Handler)
+		if err != nil {
+			return nil, err
+		}
+		namespace, name := names(route.Handler, route.Method, route.Path)
+		endpoints = append(endpoints, endpoint{method: route.Method, path: route.Path, namespace: namespace, name: name, handler: h})
+	}
+
+	// gin lists the routes method by method; the client lists them by module and path
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		a, b := endpoints[i], endpoints[j]
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		return methodOrder[a.method] < methodOrder[b.method]
+	})
+	return endpoints, nil
+}
+
+var methodOrder = map[string]int{"GET": 0, "POST": 1, "PUT": 2, "PATCH": 3, "DELETE": 4}
+
+// names picks an endpoint's namespace and method from its handler: the receiver without
+// its Handler suffix or else the package, and the handler's name. Closures are named after
+// their route.
+func names(handler, method, path string) (string, string) {
+	handler = strings.TrimSuffix(handler, "-fm")
+	pkg, fn := handler, ""
+	if i := strings.LastIndex(handler, "/"); i >= 0 {
+		pkg, fn, _ = strings.Cut(handler[i+1:], ".")
+	}
+
+	if recv, m, ok := strings.Cut(fn, ")."); ok {
+		recv = strings.TrimSuffix(strings.TrimPrefix(recv, "(*"), "Handler")
+		if recv == "" {
+			recv = pkg
+		}
+		return lowerFirst(recv), lowerFirst(m)
+	}
+	if !strings.Contains(fn, ".func") {
+		return pkg, lowerFirst(fn)
+	}
+	name := strings.ToLower(method)
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "api" || segment == "v0" || segment[0] == ':' || segment[0] == '*' {
+			continue
+		}
+		name += pascal(segment)
+	}
+	return pkg, name
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// generate writes the client's source
+func generate(pkgs []*packages.Package, endpoints []endpoint) ([]byte, error) {
+	w := newTypeWriter(pkgs)
+	// The prelude's APIResponse refers to the errors as ErrorDetail
+	var apiError string
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if pkg.PkgPath == modulePath+"/internal/apierror" {
+			apiError = w.typ(pkg.Types.Scope().Lookup("Error").Type())
+		}
+	})
+	if apiError == "" {
+		return nil, fmt.Errorf("%s/internal/apierror.Error not found", modulePath)
+	}
+
+	var types, client strings.Builder
+	var namespaces []string
+	byNamespace := map[string][]endpoint{}
+	for _, e := range endpoints {
+		if _, ok := byNamespace[e.namespace]; !ok {
+			namespaces = append(namespaces, e.namespace)
+		}
+		byNamespace[e.namespace] = append(byNamespace[e.namespace], e)
+	}
+
+	for _, ns := range namespaces {
+		fmt.Fprintf(&client, "\n  readonly %s = {\n", ns)
+		for _, e := range byNamespace[ns] {
+			prefix := pascal(ns) + pascal(e.name)
+			var params []string
+			var path strings.Builder
+			for _, segment := range strings.Split(e.path, "/")[1:] {
+				path.WriteString("/")
+				switch {
+				case segment[0] == ':':
+					params = append(params, segment[1:]+": PathParam")
+					fmt.Fprintf(&path, "${encodeURIComponent(String(%s))}", segment[1:])
+				case segment[0] == '*':
+					params = append(params, segment[1:]+": string")
+					fmt.Fprintf(&path, "${encodeURI(%s.replace(/^\\/+/, \"\"))}", segment[1:])
+				default:
+					path.WriteString(segment)
+				}
+			}
+			var init []string
+			if e.body != nil {
+				fmt.Fprintf(&types, "\nexport type %sBody = %s;\n", prefix, w.body(e.body))
+				params = append(params, "body: "+prefix+"Body")
+				init = append(init, "body")
+			}
+			if len(e.query) > 0 {
+				fmt.Fprintf(&types, "\nexport interface %sQuery {\n", prefix)
+				for _, q := range e.query {
+					typ := "QueryValue"
+					if q.array {
+						typ += "[]"
+					}
+					name := q.name
+					if !isIdentifier(name) {
+						name = fmt.Sprintf("%q", name)
+					}
+					fmt.Fprintf(&types, "  %s?: %s;\n", name, typ)
+				}
+				types.WriteString("}\n")
+				params = append(params, "query?: "+prefix+"Query")
+				init = append(init, "query")
+			}
+
+			call := fmt.Sprintf("this.%%s(%q, `%s`, { %s })", e.method, path.String(), strings.Join(append(init, "...options"), ", "))
+			var result string
+			switch {
+			case e.raw:
+				// Also when the handler may redirect instead, e.g. to a signed URL, as fetch follows redirects
+				params = append(params, "options?: RequestOptions")
+				result = "Promise<Response>"
+				call = fmt.Sprintf(call, "raw")
+			case e.redirect:
+				result = "string"
+				if len(e.query) > 0 {
+					call = fmt.Sprintf("this.url(`%s`, query)", path.String())
+				} else {
+					call = fmt.Sprintf("this.url(`%s`)", path.String())
+				}
+			case e.websocket:
+				result = "string"
+				if len(e.query) > 0 {
+					call = fmt.Sprintf("this.socketUrl(`%s`, query)", path.String())
+				} else {
+					call = fmt.Sprintf("this.socketUrl(`%s`)", path.String())
+				}
+			default:
+				params = append(params, "options?: RequestOptions")
+				var data []string
+				for _, d := range e.data {
+					data = append(data, w.expr(d.expr, d.fn, ""))
+				}
+				if len(data) == 0 {
+					data = append(data, "unknown")
+				}
+				fmt.Fprintf(&types, "\nexport type %sResponse = APIResponse<%s>;\n", prefix, union(data))
+				result = "Promise<" + prefix + "Response>"
+				call = fmt.Sprintf(call, "request")
+			}
+
+			doc := routeDoc(e.doc)
+			if e.redirect && !e.raw {
+				doc = strings.TrimSpace(doc + "\n\nReturns the address to send the browser to, as the endpoint redirects.")
+			}
+			if e.websocket {
+				doc = strings.TrimSpace(doc + "\n\nReturns the address to open the WebSocket at.")
+			}
+			writeDoc(&client, "    ", strings.TrimSpace(doc+"\n\n`"+e.method+" "+e.path+"`"))
+			fmt.Fprintf(&client, "    %s: (%s): %s =>\n      %s,\n", e.name, strings.Join(params, ", "), result, call)
+		}
+		client.WriteString("  };\n")
+	}
+
+	replacer := w.resolve()
+	var b strings.Builder
+	b.WriteString(strings.Replace(prelude, "{{defaultBaseUrl}}", fmt.Sprintf("http://localhost:%d", env.DefaultPort), 1))
+	for _, n := range w.order {
+		b.WriteString("\n")
+		writeDoc(&b, "", w.docs[n.obj.Pos()])
+		fmt.Fprintf(&b, "export interface %s %s\n", n.name, n.body)
+	}
+	b.WriteString(types.String())
+	b.WriteString(strings.Replace(clientClass, "{{namespaces}}", client.String(), 1))
+	return []byte(replacer.Replace(b.String())), nil
+}
+
+// routeDoc drops the route lines (GET /jobs/:id) from a handler's doc comment, as the
+// client documents the route itself
+func routeDoc(doc string) string {
+	var lines []string
+	for _, line := range strings.Split(doc, "\n") {
+		method, _, _ := strings.Cut(line, " /")
+		switch method {
+		case "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS":
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"API/internal/auth"
+	"API/internal/backup"
+	"API/internal/common"
+	"API/internal/tenant"
+	"API/internal/v0/caldav"
+	"API/internal/v0/courses"
+	"API/internal/v0/directory"
+	"API/internal/v0/eclass"
+	campusevents "API/internal/v0/events"
+	"API/internal/v0/jobs"
+	"API/internal/v0/library"
+	"API/internal/v0/maps"
+	"API/internal/v0/news"
+	"API/internal/v0/postings"
+	"API/internal/v0/printing"
+	"API/internal/v0/schedule"
+	"API/internal/v0/sports"
+	"API/internal/v0/stream"
+	"API/internal/v0/webhooks"
+	"API/internal/v0/ws"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routes registers the API's routes the way cmd/api does, with no handlers or stores
+// behind them, and returns the route table. Registering only records the handlers, so
+// nothing is called. Modules added to cmd/api must be added here too.
+func routes() gin.RoutesInfo {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	authMiddleware := &auth.Middleware{}
+
+	global := router.Group("/api")
+	common.RegisterRoutes(global)
+	tenant.RegisterRoutes(global, tenant.Tenant{})
+	auth.RegisterRoutes(global, nil, nil, nil, authMiddleware)
+
+	v0Group := router.Group("/api/v0")
+	{
+		schedule.RegisterRoutes(v0Group, nil, authMiddleware)
+		courses.RegisterRoutes(v0Group, nil, authMiddleware)
+		maps.RegisterRoutes(v0Group, nil, authMiddleware)
+		directory.RegisterRoutes(v0Group, nil, authMiddleware)
+		campusevents.RegisterRoutes(v0Group, nil, authMiddleware)
+		library.RegisterRoutes(v0Group, nil, authMiddleware)
+		news.RegisterRoutes(v0Group, nil, authMiddleware)
+		sports.RegisterRoutes(v0Group, nil, authMiddleware)
+		postings.RegisterRoutes(v0Group, nil, authMiddleware)
+		jobs.RegisterRoutes(v0Group, nil, authMiddleware)
+		printing.RegisterRoutes(v0Group, nil, authMiddleware)
+		webhooks.RegisterRoutes(v0Group, nil, authMiddleware)
+		stream.RegisterRoutes(v0Group, nil, authMiddleware)
+		ws.RegisterRoutes(v0Group, nil, authMiddleware)
+		eclass.RegisterRoutes(v0Group, nil, authMiddleware)
+		caldav.RegisterRoutes(v0Group, nil, authMiddleware)
+	}
+
+	backup.RegisterRoutes(global, nil, authMiddleware)
+	return router.Routes()
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
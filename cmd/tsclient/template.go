@@ -0,0 +1,194 @@
+package main
+
+// prelude opens the generated client with the envelope every response comes in. The
+// declared types follow it.
+const prelude = `// Code generated by cmd/tsclient; DO NOT EDIT.
+//
+// The typed client of the OpenSourceDUTH API. Regenerate it with
+// go generate ./cmd/tsclient after changing routes, handlers or their types.
+
+/** A query parameter's value; array values repeat the parameter */
+export type QueryValue = string | number | boolean;
+
+/** A path parameter's value, e.g. an ID */
+export type PathParam = string | number;
+
+/** Metadata of every response */
+export interface Metadata {
+  timestamp: string;
+  /** The API version, set by the /api/v0 routes */
+  version?: string;
+  requestId: string;
+  /** Set on paginated lists that have another page; pass it back as the cursor query parameter */
+  nextCursor?: string;
+}
+
+/** APIResponse is the envelope every JSON response comes in */
+export interface APIResponse<T> {
+  data: T;
+  errors: ErrorDetail[];
+  metadata: Metadata;
+}
+
+/** Where a server left on the default HOST and PORT is served, e.g. during development */
+export const DEFAULT_BASE_URL = "{{defaultBaseUrl}}";
+
+export interface ClientOptions {
+  /**
+   * Where the API is served, e.g. https://api.example.org or DEFAULT_BASE_URL. The host
+   * picks the tenant.
+   */
+  baseUrl: string;
+  /** The API token sent as a bearer token, or a function returning it */
+  token?: string | (() => string | undefined | Promise<string | undefined>);
+  /**
+   * Whether to send the session cookie the session and admin routes need;
+   * "include" when the frontend is served from another origin
+   */
+  credentials?: RequestCredentials;
+  /** Headers sent with every request, e.g. Accept-Language */
+  headers?: Record<string, string>;
+  /** The fetch implementation, by default the global one */
+  fetch?: typeof fetch;
+}
+
+export interface RequestOptions {
+  /** Sent as Idempotency-Key, so that a retried write is applied once */
+  idempotencyKey?: string;
+  headers?: Record<string, string>;
+  signal?: AbortSignal;
+}
+
+/** ApiError is thrown for responses with an error status */
+export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly errors: ErrorDetail[],
+    readonly requestId: string | undefined,
+    readonly body: unknown,
+  ) {
+    super(errors[0]?.message ?? ` + "`request failed with status ${status}`" + `);
+    this.name = "ApiError";
+  }
+
+  /** The first error's code, e.g. not_found */
+  get code(): string | undefined {
+    return this.errors[0]?.code;
+  }
+
+  static async from(response: Response): Promise<ApiError> {
+    let body: unknown;
+    try {
+      body = await response.json();
+    } catch {
+      body = undefined;
+    }
+    // Token and session checks answer with { code, error, requestId } rather than an APIResponse
+    const b = (body ?? {}) as Partial<APIResponse<unknown>> & { code?: string; error?: string; requestId?: string };
+    const errors: ErrorDetail[] = Array.isArray(b.errors)
+      ? b.errors
+      : b.code
+        ? [{ code: b.code, message: b.error ?? "", docsUrl: "" }]
+        : [];
+    const requestId = b.metadata?.requestId ?? b.requestId ?? response.headers.get("X-Request-ID") ?? undefined;
+    return new ApiError(response.status, errors, requestId, body);
+  }
+}
+`
+
+// clientClass closes the generated client with the class sending the requests, with
+// {{namespaces}} replaced by the endpoints grouped by module
+const clientClass = `
+interface Init extends RequestOptions {
+  query?: object;
+  body?: unknown;
+}
+
+/** Client calls the API with fetch, one property per module */
+export class Client {
+  private readonly options: ClientOptions;
+
+  constructor(options: ClientOptions) {
+    this.options = { ...options, baseUrl: options.baseUrl.replace(/\/+$/, "") };
+  }
+
+  /** url returns the address of path with the query's parameters */
+  url(path: string, query?: object): string {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(query ?? {})) {
+      for (const v of Array.isArray(value) ? value : [value]) {
+        if (v !== undefined && v !== null) {
+          params.append(key, String(v));
+        }
+      }
+    }
+    const search = params.toString();
+    return this.options.baseUrl + path + (search ? "?" + search : "");
+  }
+
+  /** socketUrl returns the WebSocket address of path with the query's parameters */
+  socketUrl(path: string, query?: object): string {
+    return this.url(path, query).replace(/^http/, "ws");
+  }
+
+  /** raw sends a request and returns the response as it is, throwing ApiError for error statuses */
+  async raw(method: string, path: string, init: Init = {}): Promise<Response> {
+    return this.send(method, path, init, undefined);
+  }
+
+  /** request sends a request and returns the decoded APIResponse, throwing ApiError for error statuses */
+  async request<T>(method: string, path: string, init: Init = {}): Promise<T> {
+    const response = await this.send(method, path, init, "application/json");
+    return (await response.json()) as T;
+  }
+
+  private async send(method: string, path: string, init: Init, accept: string | undefined): Promise<Response> {
+    const headers: Record<string, string> = { ...this.options.headers, ...init.headers };
+    if (accept) {
+      headers["Accept"] = accept;
+    }
+    const token = typeof this.options.token === "function" ? await this.options.token() : this.options.token;
+    if (token) {
+      headers["Authorization"] = ` + "`Bearer ${token}`" + `;
+    }
+    if (init.idempotencyKey) {
+      headers["Idempotency-Key"] = init.idempotencyKey;
+    }
+    let body: string | undefined;
+    if (init.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(init.body);
+    }
+
+    const send = this.options.fetch ?? fetch;
+    const response = await send(this.url(path, init.query), {
+      method,
+      headers,
+      body,
+      credentials: this.options.credentials,
+      signal: init.signal,
+    });
+    if (!response.ok) {
+      throw await ApiError.from(response);
+    }
+    return response;
+  }
+{{namespaces}}}
+`
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
@@ -0,0 +1,537 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Named types that get a name of their own rather than their package's prefix when
+// theirs would clash
+var renamed = map[string]string{
+	"API/internal/apierror.Error": "ErrorDetail",
+}
+
+// Names taken by the TypeScript and DOM libraries
+var reserved = map[string]bool{
+	"Array": true, "Blob": true, "Boolean": true, "Date": true, "Document": true, "Element": true,
+	"Error": true, "Event": true, "File": true, "Function": true, "Headers": true, "Image": true,
+	"Location": true, "Map": true, "Node": true, "Notification": true, "Number": true, "Object": true,
+	"Promise": true, "Record": true, "Request": true, "Response": true, "Set": true, "Storage": true,
+	"String": true, "Symbol": true, "Text": true, "URL": true,
+}
+
+// typeWriter renders Go types as TypeScript, collecting the named structs it meets so
+// they are declared once as interfaces
+type typeWriter struct {
+	named map[namedKey]*namedType
+	order []*namedType
+	docs  map[token.Pos]string // doc comments of type specs and struct fields by position
+
+	// input renders types as request bodies are bound: fields without binding:"required"
+	// may be left out. Named types used both ways are declared twice.
+	input bool
+}
+
+type namedKey struct {
+	obj   *types.TypeName
+	input bool
+}
+
+type namedType struct {
+	namedKey
+	name string
+	body string
+}
+
+// placeholder marks where a named type is referenced until every name is known and
+// clashes between packages can be resolved
+func placeholder(key namedKey) string {
+	p := "\x00" + key.obj.Pkg().Path() + "." + key.obj.Name()
+	if key.input {
+		p += "\x01"
+	}
+	return p + "\x00"
+}
+
+// body renders the type of a request body
+func (w *typeWriter) body(t types.Type) string {
+	w.input = true
+	defer func() { w.input = false }()
+	return w.typ(t)
+}
+
+func newTypeWriter(pkgs []*packages.Package) *typeWriter {
+	w := &typeWriter{named: make(map[namedKey]*namedType), docs: make(map[token.Pos]string)}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(node ast.Node) bool {
+				switch node := node.(type) {
+				case *ast.GenDecl:
+					for _, spec := range node.Specs {
+						if ts, ok := spec.(*ast.TypeSpec); ok {
+							doc := ts.Doc
+							if doc == nil && len(node.Specs) == 1 {
+								doc = node.Doc
+							}
+							w.docs[ts.Name.Pos()] = doc.Text()
+						}
+					}
+				case *ast.Field:
+					doc := node.Doc.Text()
+					if doc == "" {
+						doc = node.Comment.Text()
+					}
+					pos := node.Type.Pos()
+					if len(node.Names) > 0 {
+						pos = node.Names[0].Pos()
+					}
+					w.docs[pos] = doc
+				}
+				return true
+			})
+		}
+	})
+	return w
+}
+
+// typ renders t
+func (w *typeWriter) typ(t types.Type) string {
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil {
+			switch obj.Pkg().Path() + "." + obj.Name() {
+			case "time.Time":
+				return "string"
+			case "encoding/json.RawMessage":
+				return "unknown"
+			}
+		}
+		if implements(t, "MarshalJSON") {
+			return "unknown"
+		}
+		if implements(t, "MarshalText") {
+			return "string"
+		}
+		if _, ok := named.Underlying().(*types.Struct); ok && obj.Pkg() != nil && named.TypeArgs().Len() == 0 {
+			key := namedKey{obj: obj, input: w.input}
+			w.declare(key, named)
+			return placeholder(key)
+		}
+		return w.typ(named.Underlying())
+	}
+
+	switch t := t.(type) {
+	case *types.Alias:
+		return w.typ(types.Unalias(t))
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return "boolean"
+		case t.Info()&types.IsNumeric != 0:
+			return "number"
+		case t.Info()&types.IsString != 0:
+			return "string"
+		case t.Kind() == types.UntypedNil:
+			return "null"
+		}
+	case *types.Pointer:
+		return w.typ(t.Elem()) + " | null"
+	case *types.Slice:
+		if b, ok := t.Elem().(*types.Basic); ok && b.Kind() == types.Byte {
+			return "string" // base64
+		}
+		return array(w.typ(t.Elem()))
+	case *types.Array:
+		return array(w.typ(t.Elem()))
+	case *types.Map:
+		return "Record<string, " + w.typ(t.Elem()) + ">"
+	case *types.Struct:
+		return w.object(t, "")
+	}
+	return "unknown"
+}
+
+func array(elem string) string {
+	if strings.ContainsAny(elem, " |") {
+		return "(" + elem + ")[]"
+	}
+	return elem + "[]"
+}
+
+func implements(t types.Type, method string) bool {
+	for _, typ := range []types.Type{t, types.NewPointer(t)} {
+		set := types.NewMethodSet(typ)
+		for i := 0; i < set.Len(); i++ {
+			if set.At(i).Obj().Name() == method {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (w *typeWriter) declare(key namedKey, named *types.Named) {
+	if _, ok := w.named[key]; ok {
+		return
+	}
+	n := &namedType{namedKey: key}
+	w.named[key] = n
+	w.order = append(w.order, n)
+	n.body = w.object(named.Underlying().(*types.Struct), "")
+}
+
+// field is a JSON property of a struct
+type field struct {
+	name     string
+	typ      string
+	optional bool
+	doc      string
+}
+
+// object renders a struct as an object type, indenting its properties by one more level
+// than indent
+func (w *typeWriter) object(s *types.Struct, indent string) string {
+	fields := w.fields(s, map[string]bool{})
+	if len(fields) == 0 {
+		return "Record<string, never>"
+	}
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, f := range fields {
+		writeDoc(&b, indent+"  ", f.doc)
+		name := f.name
+		if !isIdentifier(name) {
+			name = fmt.Sprintf("%q", name)
+		}
+		if f.optional {
+			name += "?"
+		}
+		fmt.Fprintf(&b, "%s  %s: %s;\n", indent, name, strings.ReplaceAll(f.typ, "\n", "\n"+indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// fields lists the JSON properties of s the way encoding/json does, with the fields of
+// untagged embedded structs promoted in their place unless a shallower field has the
+// same name
+func (w *typeWriter) fields(s *types.Struct, taken map[string]bool) []field {
+	type jsonField struct {
+		v        *types.Var
+		name     string
+		opts     string
+		embedded *types.Struct
+	}
+	var list []jsonField
+	own := map[string]bool{}
+	for i := 0; i < s.NumFields(); i++ {
+		v := s.Field(i)
+		tag := reflect.StructTag(s.Tag(i))
+		name, opts, _ := strings.Cut(tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if v.Anonymous() && name == "" {
+			t := v.Type()
+			if ptr, ok := t.(*types.Pointer); ok {
+				t = ptr.Elem()
+			}
+			if st, ok := t.Underlying().(*types.Struct); ok {
+				list = append(list, jsonField{v: v, embedded: st})
+				continue
+			}
+		}
+		if !v.Exported() {
+			continue
+		}
+		if name == "" {
+			name = v.Name()
+		}
+		if w.input && !hasOption(tag.Get("binding"), "required") {
+			opts += ",optional"
+		}
+		list = append(list, jsonField{v: v, name: name, opts: opts})
+		own[name] = true
+	}
+
+	var fields []field
+	for _, jf := range list {
+		if jf.embedded != nil {
+			inner := map[string]bool{}
+			for name := range taken {
+				inner[name] = true
+			}
+			for name := range own {
+				inner[name] = true
+			}
+			promoted := w.fields(jf.embedded, inner)
+			for _, f := range promoted {
+				taken[f.name] = true
+			}
+			fields = append(fields, promoted...)
+			continue
+		}
+		if taken[jf.name] {
+			continue
+		}
+		taken[jf.name] = true
+
+		f := field{name: jf.name, doc: w.docs[jf.v.Pos()]}
+		omit := hasOption(jf.opts, "omitempty") || hasOption(jf.opts, "omitzero")
+		if hasOption(jf.opts, "string") {
+			f.typ = "string"
+		} else if ptr, ok := jf.v.Type().(*types.Pointer); ok && omit && !w.input {
+			f.typ = w.typ(ptr.Elem())
+		} else {
+			f.typ = w.typ(jf.v.Type())
+		}
+		f.optional = omit
+		if w.input {
+			f.optional = hasOption(jf.opts, "optional")
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// expr renders the type of a response's data. gin.H literals, and gin.H variables built
+// up from one, are typed key by key; anything else by its Go type.
+func (w *typeWriter) expr(e ast.Expr, fn *function, indent string) string {
+	e = ast.Unparen(e)
+	if ident, ok := e.(*ast.Ident); ok && isMap(fn.info.TypeOf(e)) {
+		if lit, sets := assignments(fn, ident); lit != nil {
+			props := w.props(lit, fn, indent)
+			for _, set := range sets {
+				for i, lhs := range set.Lhs {
+					if key, ok := stringKey(fn.info, lhs.(*ast.IndexExpr).Index); ok {
+						props = append(props, prop{key: key, typ: w.expr(set.Rhs[i], fn, indent+"  "), optional: true})
+					}
+				}
+			}
+			return renderProps(props, indent)
+		}
+	}
+	if lit, ok := e.(*ast.CompositeLit); ok && isMap(fn.info.TypeOf(e)) {
+		return renderProps(w.props(lit, fn, indent), indent)
+	}
+	if tv, ok := fn.info.Types[e]; ok && tv.IsNil() {
+		return "null"
+	}
+	return strings.ReplaceAll(w.typ(fn.info.TypeOf(e)), "\n", "\n"+indent)
+}
+
+type prop struct {
+	key      string
+	typ      string
+	optional bool
+}
+
+func (w *typeWriter) props(lit *ast.CompositeLit, fn *function, indent string) []prop {
+	var props []prop
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := stringKey(fn.info, kv.Key); ok {
+			props = append(props, prop{key: key, typ: w.expr(kv.Value, fn, indent+"  ")})
+		}
+	}
+	return props
+}
+
+func renderProps(props []prop, indent string) string {
+	// A key set in several places takes every type it is given
+	merged := map[string]*prop{}
+	var keys []string
+	for _, p := range props {
+		if m, ok := merged[p.key]; ok {
+			if m.typ != p.typ {
+				m.typ = union([]string{m.typ, p.typ})
+			}
+			continue
+		}
+		p := p
+		merged[p.key] = &p
+		keys = append(keys, p.key)
+	}
+	if len(keys) == 0 {
+		return "Record<string, never>"
+	}
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, key := range keys {
+		p := merged[key]
+		name := key
+		if !isIdentifier(name) {
+			name = fmt.Sprintf("%q", name)
+		}
+		if p.optional {
+			name += "?"
+		}
+		fmt.Fprintf(&b, "%s  %s: %s;\n", indent, name, p.typ)
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// union joins types, dropping duplicates
+func union(typs []string) string {
+	seen := map[string]bool{}
+	var out []string
+	for _, t := range typs {
+		for _, part := range splitUnion(t) {
+			if !seen[part] {
+				seen[part] = true
+				out = append(out, part)
+			}
+		}
+	}
+	return strings.Join(out, " | ")
+}
+
+// splitUnion splits a union at its top level, leaving object types whole
+func splitUnion(t string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(t); i++ {
+		switch t[i] {
+		case '{', '(', '<', '[':
+			depth++
+		case '}', ')', '>', ']':
+			depth--
+		case '|':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(t[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(t[start:]))
+}
+
+func stringKey(info *types.Info, e ast.Expr) (string, bool) {
+	tv, ok := info.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+func isMap(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	_, ok := t.Underlying().(*types.Map)
+	return ok
+}
+
+// resolve names the declared types, prefixing the package's name to the names that
+// clash, and replaces their placeholders in s
+func (w *typeWriter) resolve() *strings.Replacer {
+	count := map[string]int{}
+	for _, n := range w.order {
+		if !n.input || w.named[namedKey{obj: n.obj}] == nil {
+			count[n.obj.Name()]++
+		}
+	}
+	var pairs []string
+	for _, n := range w.order {
+		key := n.obj.Pkg().Path() + "." + n.obj.Name()
+		switch {
+		case renamed[key] != "":
+			n.name = renamed[key]
+		case count[n.obj.Name()] > 1 || reserved[n.obj.Name()]:
+			n.name = pascal(n.obj.Pkg().Name()) + n.obj.Name()
+		default:
+			n.name = n.obj.Name()
+		}
+		if n.input && w.named[namedKey{obj: n.obj}] != nil {
+			n.name += "Input"
+		}
+		pairs = append(pairs, placeholder(n.namedKey), n.name)
+	}
+	sort.SliceStable(w.order, func(i, j int) bool { return w.order[i].name < w.order[j].name })
+	return strings.NewReplacer(pairs...)
+}
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || r == '$' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// pascal turns names like list_jobs, list-jobs or listJobs into ListJobs
+func pascal(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if r == '_' || r == '-' || r == '.' || r == ' ' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// writeDoc writes doc as a JSDoc comment
+func writeDoc(b *strings.Builder, indent, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s%s\n", indent, strings.TrimRight(" * "+line, " "))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
@@ -0,0 +1,183 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// DocsBaseURL is where each error code is documented, as DocsBaseURL#code
+const DocsBaseURL = "https://opensource.cs.duth.gr/docs/errors"
+
+// Validation errors name fields by their JSON names, as clients know them
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// Code identifies a kind of error clients can branch on. Codes are stable;
+// messages are meant for humans and may change.
+type Code string
+
+const (
+	InvalidRequest       Code = "invalid_request"
+	InvalidID            Code = "invalid_id"
+	ValidationFailed     Code = "validation_failed"
+	NotAuthenticated     Code = "not_authenticated"
+	InvalidToken         Code = "invalid_token"
+	AccountInactive      Code = "account_inactive"
+	Forbidden            Code = "forbidden"
+	FeatureNotAllowed    Code = "feature_not_allowed"
+	IPNotAllowed         Code = "ip_not_allowed"
+	NotFound             Code = "not_found"
+	UnknownTenant        Code = "unknown_tenant"
+	Conflict             Code = "conflict"
+	IdempotencyKeyReused Code = "idempotency_key_reused"
+	PayloadTooLarge      Code = "payload_too_large"
+	UnsupportedMedia     Code = "unsupported_media_type"
+	RateLimited          Code = "rate_limited"
+	TooManyFailures      Code = "too_many_failures"
+	Internal             Code = "internal_error"
+	UpstreamFailed       Code = "upstream_failed"
+	Timeout              Code = "request_timeout"
+)
+
+// Definition documents an error code
+type Definition struct {
+	Code        Code   `json:"code"`
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+	DocsURL     string `json:"docsUrl"`
+}
+
+// catalog is the central list of error codes, served at GET /api/errors
+var catalog = map[Code]Definition{
+	InvalidRequest:       {Status: http.StatusBadRequest, Description: "The request or one of its query parameters is malformed"},
+	InvalidID:            {Status: http.StatusBadRequest, Description: "A path parameter is not a valid ID"},
+	ValidationFailed:     {Status: http.StatusBadRequest, Description: "A body field is missing or invalid; field names it"},
+	NotAuthenticated:     {Status: http.StatusUnauthorized, Description: "The request needs a session; log in first"},
+	InvalidToken:         {Status: http.StatusUnauthorized, Description: "The bearer token is missing, malformed, revoked or expired"},
+	AccountInactive:      {Status: http.StatusForbidden, Description: "The account is suspended or banned"},
+	Forbidden:            {Status: http.StatusForbidden, Description: "The account's role does not allow this action"},
+	FeatureNotAllowed:    {Status: http.StatusForbidden, Description: "The token is not scoped to the feature, or the feature is admin-only or awaits approval"},
+	IPNotAllowed:         {Status: http.StatusForbidden, Description: "The token's IP allowlist does not include the client address"},
+	NotFound:             {Status: http.StatusNotFound, Description: "The resource does not exist"},
+	UnknownTenant:        {Status: http.StatusNotFound, Description: "No university is served on the request host"},
+	Conflict:             {Status: http.StatusConflict, Description: "The request conflicts with the resource's current state"},
+	IdempotencyKeyReused: {Status: http.StatusUnprocessableEntity, Description: "The Idempotency-Key was already used for a request with a different method, URL or body"},
+	UnsupportedMedia:     {Status: http.StatusUnsupportedMediaType, Description: "The uploaded file is not in a supported format"},
+	RateLimited:          {Status: http.StatusTooManyRequests, Description: "The token's per-minute quota for the feature is used up; retry after Retry-After seconds"},
+	TooManyFailures:      {Status: http.StatusTooManyRequests, Description: "The client address sent too many invalid tokens and is blocked; retry after Retry-After seconds"},
+	Internal:             {Status: http.StatusInternalServerError, Description: "The server failed; report it with the request ID"},
+	UpstreamFailed:       {Status: http.StatusBadGateway, Description: "A service the request depends on failed"},
+}
+
+// Catalog returns every error code, sorted by code
+func Catalog() []Definition {
+	definitions := make([]Definition, 0, len(catalog))
+	for code, def := range catalog {
+		def.Code = code
+		def.DocsURL = docsURL(code)
+		definitions = append(definitions, def)
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Code < definitions[j].Code })
+	return definitions
+}
+
+// ForStatus returns the generic code for an HTTP status, for errors that
+// only carry a status (such as those returned by auth hooks)
+func ForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return InvalidRequest
+	case http.StatusUnauthorized:
+		return NotAuthenticated
+	case http.StatusForbidden:
+		return Forbidden
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusTooManyRequests:
+		return RateLimited
+	case http.StatusBadGateway:
+		return UpstreamFailed
+	}
+	return Internal
+}
+
+// Error is an error as it appears in APIResponse.errors
+type Error struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"` // JSON name of the offending body field
+	DocsURL string `json:"docsUrl"`
+}
+
+// New creates an error with the given code
+func New(code Code, message string) Error {
+	return Error{Code: code, Message: message, DocsURL: docsURL(code)}
+}
+
+// Newf creates an error with a formatted message
+func Newf(code Code, format string, args ...interface{}) Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// Invalid creates a ValidationFailed error for a body field, for checks that
+// binding tags cannot express (e.g. that a referenced row exists)
+func Invalid(field, message string) Error {
+	e := New(ValidationFailed, message)
+	e.Field = field
+	return e
+}
+
+// FromBinding converts a request binding error into one ValidationFailed
+// error per invalid field. Bodies that are not valid JSON give a single
+// InvalidRequest error.
+func FromBinding(err error) []Error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return []Error{Newf(PayloadTooLarge, "request body exceeds %d bytes", tooLarge.Limit)}
+	}
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return []Error{New(InvalidRequest, err.Error())}
+	}
+	errs := make([]Error, 0, len(verrs))
+	for _, fe := range verrs {
+		e := Newf(ValidationFailed, "%s failed the '%s' check", fieldName(fe), fe.Tag())
+		e.Field = fieldName(fe)
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// fieldName returns the JSON path of a field (e.g. "items[0].meal_type")
+func fieldName(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:] // Drop the struct name
+	}
+	return namespace
+}
+
+func docsURL(code Code) string {
+	return DocsBaseURL + "#" + string(code)
+}
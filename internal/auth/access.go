@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"API/internal/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The checks below are the ones RequireToken and UnaryInterceptor put token-authenticated
+// requests through, so HTTP and gRPC calls are held to the same rules. They record what
+// they checked in the request's diagnostic and set response headers through setHeader.
+
+// denial refuses a request with a status and the JSON body RequireToken answers with;
+// UnaryInterceptor maps the status to a gRPC code. Failures to run a check are answered
+// the same way but are not recorded as denials.
+type denial struct {
+	status int
+	body   gin.H
+	failed bool
+}
+
+func denied(status int, code apierror.Code, message string) *denial {
+	return &denial{status: status, body: gin.H{"code": code, "error": message}}
+}
+
+func checkFailed(message string) *denial {
+	d := denied(http.StatusInternalServerError, apierror.Internal, message)
+	d.failed = true
+	return d
+}
+
+// authenticate validates the bearer token in the Authorization header
+func (m *Middleware) authenticate(ctx context.Context, authorization string, diag *DenialDiagnostic, setHeader func(key, value string)) (*ValidatedToken, *denial) {
+	// 1. Extract Authorization header
+	if authorization == "" {
+		diag.addCheck("authorization-header", false, "header missing")
+		return nil, denied(http.StatusUnauthorized, apierror.InvalidToken, "Missing authorization header")
+	}
+
+	// 2. Parse Bearer token
+	parts := strings.SplitN(authorization, " ", 2)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		diag.addCheck("authorization-header", false, "not a bearer token")
+		return nil, denied(http.StatusUnauthorized, apierror.InvalidToken, "Invalid authorization header format")
+	}
+	diag.addCheck("authorization-header", true, "")
+
+	// Addresses that sent too many invalid tokens are refused before the lookup
+	if wait := m.throttle.Blocked(diag.ClientIP); wait > 0 {
+		m.metrics.ObserveTokenFailure(true)
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		diag.addCheck("throttle", false, fmt.Sprintf("%s sent too many invalid tokens, blocked for %ds", diag.ClientIP, retryAfter))
+		setHeader(HeaderRetryAfter, strconv.Itoa(retryAfter))
+		d := denied(http.StatusTooManyRequests, apierror.TooManyFailures, "Too many invalid tokens from this address")
+		d.body["retryAfter"] = retryAfter
+		return nil, d
+	}
+
+	// 3. Validate token
+	validated, err := m.tokenStore.ValidateToken(ctx, parts[1])
+	var inactive *InactiveAccountError
+	if errors.As(err, &inactive) {
+		diag.UserID = &inactive.User.ID
+		diag.addCheck("account", false, err.Error())
+		d := denied(http.StatusForbidden, apierror.AccountInactive, err.Error())
+		d.body["reason"] = inactive.User.SuspensionReason
+		d.body["suspendedUntil"] = inactive.User.SuspendedUntil
+		return nil, d
+	}
+	if err != nil {
+		diag.addCheck("token", false, err.Error())
+		if isTokenRejection(err) {
+			m.metrics.ObserveTokenFailure(false)
+			m.throttle.Fail(diag.ClientIP)
+		}
+		return nil, denied(http.StatusUnauthorized, apierror.InvalidToken, err.Error())
+	}
+	diag.TokenID = &validated.Token.ID
+	diag.UserID = &validated.User.ID
+	diag.addCheck("token", true, fmt.Sprintf("token %d of user %d is active", validated.Token.ID, validated.User.ID))
+	return validated, nil
+}
+
+// authorize checks that a validated token may use a feature from the client's address
+func (m *Middleware) authorize(ctx context.Context, validated *ValidatedToken, featureSlug string, diag *DenialDiagnostic, setHeader func(key, value string)) (*Feature, *denial) {
+	// 4. Get the feature being accessed
+	feature, err := m.features.GetFeatureBySlug(ctx, featureSlug)
+	if err != nil || feature == nil {
+		return nil, checkFailed("Feature not found")
+	}
+
+	// Deprecated features announce it on every response (RFC 8594)
+	if feature.Deprecated {
+		setHeader(HeaderDeprecation, "true")
+		if feature.SunsetAt != nil {
+			setHeader(HeaderSunset, feature.SunsetAt.UTC().Format(http.TimeFormat))
+		}
+	}
+
+	// 5. Live admin-only check: if feature is admin-only and token is not admin-created, deny
+	adminOnly, err := m.features.IsFeatureAdminOnly(ctx, feature.ID)
+	if err != nil {
+		return nil, checkFailed("Failed to check feature permissions")
+	}
+	if adminOnly && !validated.Token.AdminCreated {
+		diag.addCheck("admin-only", false, "feature is admin-only and the token was not issued by an admin")
+		return nil, denied(http.StatusForbidden, apierror.FeatureNotAllowed, "This feature requires an admin-issued token")
+	}
+	diag.addCheck("admin-only", true, "")
+
+	// 6. Check if token has access to this feature (including parent features)
+	hasAccess, err := m.features.TokenHasFeatureAccess(ctx, validated.FeatureIDs, featureSlug)
+	if err != nil {
+		return nil, checkFailed("Failed to check feature access")
+	}
+	if !hasAccess {
+		diag.addCheck("feature-scope", false, fmt.Sprintf("token features %v do not include %s or its ancestors", validated.FeatureIDs, featureSlug))
+		return nil, denied(http.StatusForbidden, apierror.FeatureNotAllowed, fmt.Sprintf("Token does not have access to feature '%s'", featureSlug))
+	}
+	diag.addCheck("feature-scope", true, "")
+
+	// Features behind the request-access workflow need a live approval
+	if feature.ApprovalRequired && !validated.Token.AdminCreated {
+		approved, err := m.features.HasApprovedAccess(ctx, validated.User.ID, feature.ID)
+		if err != nil {
+			return nil, checkFailed("Failed to check feature access")
+		}
+		if !approved {
+			diag.addCheck("approval", false, fmt.Sprintf("user %d has no approved access request for %s", validated.User.ID, featureSlug))
+			return nil, denied(http.StatusForbidden, apierror.FeatureNotAllowed, fmt.Sprintf("Access to feature '%s' has not been approved", featureSlug))
+		}
+		diag.addCheck("approval", true, "")
+	}
+
+	// 7. Check IP whitelist
+	if len(validated.AllowedIPs) > 0 {
+		canonicalIP, err := CanonicalizeIP(diag.ClientIP)
+		if err != nil {
+			diag.addCheck("ip-allowlist", false, fmt.Sprintf("client IP %q could not be parsed", diag.ClientIP))
+			return nil, denied(http.StatusForbidden, apierror.IPNotAllowed, "Invalid client IP")
+		}
+		if !IsIPAllowed(canonicalIP, validated.AllowedIPs) {
+			diag.addCheck("ip-allowlist", false, fmt.Sprintf("%s is not in %v", canonicalIP, validated.AllowedIPs))
+			return nil, denied(http.StatusForbidden, apierror.IPNotAllowed, "IP address not allowed for this token")
+		}
+	}
+	diag.addCheck("ip-allowlist", true, "")
+	return feature, nil
+}
+
+// checkQuota checks the user's requests per minute on a feature, which HTTP requests
+// and gRPC calls share
+func (m *Middleware) checkQuota(ctx context.Context, validated *ValidatedToken, feature *Feature, diag *DenialDiagnostic, setHeader func(key, value string)) *denial {
+	// 8. Check RPM quota
+	effectiveRPM, err := m.quota.GetEffectiveRPM(ctx, validated.User.ID, feature.ID)
+	if err != nil {
+		return checkFailed("Failed to check quota")
+	}
+
+	// If not unlimited, check usage
+	if effectiveRPM != UnlimitedRPM {
+		currentRPM, err := m.usage.GetFeatureRPM(ctx, validated.User.ID, feature.ID)
+		if err != nil {
+			return checkFailed("Failed to check usage")
+		}
+
+		// Set rate limit headers
+		remaining := effectiveRPM - currentRPM - 1 // -1 for this request
+		if remaining < 0 {
+			remaining = 0
+		}
+		resetTime := time.Now().Add(60 * time.Second).Unix()
+
+		setHeader(HeaderRateLimitLimit, strconv.Itoa(effectiveRPM))
+		setHeader(HeaderRateLimitRemaining, strconv.Itoa(remaining))
+		setHeader(HeaderRateLimitReset, strconv.FormatInt(resetTime, 10))
+
+		diag.Quota = &QuotaDiagnostic{
+			EffectiveRPM:  effectiveRPM,
+			CurrentRPM:    currentRPM,
+			WindowSeconds: int(UsageRetentionPeriod.Seconds()),
+		}
+		if currentRPM >= effectiveRPM {
+			diag.addCheck("quota", false, fmt.Sprintf("%d requests in the last minute, limit is %d", currentRPM, effectiveRPM))
+			m.quota.RecordExceeded(ctx, validated.User.ID, feature, effectiveRPM)
+			setHeader(HeaderRetryAfter, "60")
+			d := denied(http.StatusTooManyRequests, apierror.RateLimited, "Rate limit exceeded")
+			d.body["limit"] = effectiveRPM
+			d.body["retryAfter"] = 60
+			return d
+		}
+	}
+	diag.addCheck("quota", true, "")
+	return nil
+}
+
+// admit runs the post-auth hooks and records the request's usage, returning the feature
+// version to answer with. hc's stage is left at HookPostUsage.
+func (m *Middleware) admit(hc *HookContext, validated *ValidatedToken, feature *Feature, diag *DenialDiagnostic, setHeader func(key, value string)) (int, *denial) {
+	hc.Feature = feature
+	hc.Token = validated.Token
+	hc.User = validated.User
+
+	hc.Stage = HookPostAuth
+	if err := m.hooks.Run(hc); err != nil {
+		diag.addCheck("hook:"+string(HookPostAuth), false, err.Error())
+		status, body := hookDenial(err)
+		return 0, &denial{status: status, body: body}
+	}
+
+	// 9. Record usage (non-blocking)
+	m.usage.RecordRequest(validated.User.ID, feature.ID)
+
+	hc.Stage = HookPostUsage
+	_ = m.hooks.Run(hc)
+
+	// Tokens granted the feature through a parent, or without pinning a version, get
+	// the original v1 format
+	version := validated.FeatureVersions[feature.ID]
+	if version == 0 {
+		version = 1
+	}
+	setHeader(HeaderFeatureVersion, fmt.Sprintf("%s@v%d", feature.Slug, version))
+	return version, nil
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrAccessRequestNotFound is returned when deciding on a request that does not exist
+	ErrAccessRequestNotFound = errors.New("access request not found")
+
+	// ErrAccessRequestState is returned when a request cannot move to the requested status
+	ErrAccessRequestState = errors.New("invalid access request state")
+)
+
+const accessRequestColumns = `ar.id, ar.user_id, u.email, ar.feature_id, f.slug, ar.status, ar.reason,
+	ar.decided_by, ar.decided_at, ar.decision_note, ar.created_at`
+
+func scanAccessRequest(row rowScanner) (*AccessRequest, error) {
+	var ar AccessRequest
+	var reason, note sql.NullString
+	var decidedBy sql.NullInt64
+	var decidedAt sql.NullTime
+	if err := row.Scan(&ar.ID, &ar.UserID, &ar.Email, &ar.FeatureID, &ar.FeatureSlug, &ar.Status, &reason,
+		&decidedBy, &decidedAt, &note, &ar.CreatedAt); err != nil {
+		return nil, err
+	}
+	ar.Reason = ScanNullableString(reason)
+	ar.DecidedBy = ScanNullableInt64(decidedBy)
+	ar.DecidedAt = ScanNullableTime(decidedAt)
+	ar.DecisionNote = ScanNullableString(note)
+	return &ar, nil
+}
+
+// HasApprovedAccess reports whether a user holds an approved access request for a feature
+func (r *FeatureRegistry) HasApprovedAccess(ctx context.Context, userID, featureID int64) (bool, error) {
+	var exists bool
+	err := r.repo.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM feature_access_requests
+			WHERE user_id = ? AND feature_id = ? AND status = 'approved'
+		)
+	`, userID, featureID).Scan(&exists)
+	return exists, err
+}
+
+// RequestAccess opens an access request for a feature that requires approval.
+// A user can only have one pending or approved request per feature.
+func (r *FeatureRegistry) RequestAccess(ctx context.Context, userID int64, slug string, reason *string) (*AccessRequest, error) {
+	feature, err := r.GetFeatureBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if feature == nil || feature.AdminOnly {
+		return nil, fmt.Errorf("Feature '%s' not found", slug)
+	}
+	if !feature.ApprovalRequired {
+		return nil, fmt.Errorf("Feature '%s' does not require approval", slug)
+	}
+
+	var status AccessRequestStatus
+	err = r.repo.db.QueryRowContext(ctx, `
+		SELECT status FROM feature_access_requests
+		WHERE user_id = ? AND feature_id = ? AND status IN ('pending', 'approved')
+	`, userID, feature.ID).Scan(&status)
+	if err == nil {
+		return nil, fmt.Errorf("%w: access to '%s' is already %s", ErrAccessRequestState, slug, status)
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	result, err := r.repo.db.ExecContext(ctx, `
+		INSERT INTO feature_access_requests (user_id, feature_id, reason) VALUES (?, ?, ?)
+	`, userID, feature.ID, reason)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := result.LastInsertId()
+	return r.GetAccessRequest(ctx, id)
+}
+
+// GetAccessRequest returns an access request by ID
+func (r *FeatureRegistry) GetAccessRequest(ctx context.Context, id int64) (*AccessRequest, error) {
+	ar, err := scanAccessRequest(r.repo.db.QueryRowContext(ctx, `
+		SELECT `+accessRequestColumns+`
+		FROM feature_access_requests ar
+		JOIN users u ON u.id = ar.user_id
+		JOIN features f ON f.id = ar.feature_id
+		WHERE ar.id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ar, nil
+}
+
+// ListAccessRequests returns access requests, newest first. Either filter may be nil.
+func (r *FeatureRegistry) ListAccessRequests(ctx context.Context, userID *int64, status *AccessRequestStatus) ([]AccessRequest, error) {
+	rows, err := r.repo.db.QueryContext(ctx, `
+		SELECT `+accessRequestColumns+`
+		FROM feature_access_requests ar
+		JOIN users u ON u.id = ar.user_id
+		JOIN features f ON f.id = ar.feature_id
+		WHERE (? IS NULL OR ar.user_id = ?) AND (? IS NULL OR ar.status = ?)
+		ORDER BY ar.created_at DESC, ar.id DESC
+	`, userID, userID, status, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []AccessRequest{}
+	for rows.Next() {
+		ar, err := scanAccessRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, *ar)
+	}
+	return requests, rows.Err()
+}
+
+// DecideAccessRequest approves or denies a pending request, or revokes an
+// approved one. Approving adds the feature to the user's active tokens so it
+// can be used right away; revoking leaves the tokens alone, since the
+// middleware refuses features without an approved request anyway.
+func (r *FeatureRegistry) DecideAccessRequest(ctx context.Context, id int64, status AccessRequestStatus, adminID *int64, note *string) (*AccessRequest, error) {
+	ar, err := r.GetAccessRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ar == nil {
+		return nil, ErrAccessRequestNotFound
+	}
+
+	from := AccessRequestPending
+	if status == AccessRequestRevoked {
+		from = AccessRequestApproved
+	}
+	if ar.Status != from {
+		return nil, fmt.Errorf("%w: request is %s", ErrAccessRequestState, ar.Status)
+	}
+
+	now := time.Now()
+	err = r.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE feature_access_requests
+			SET status = ?, decided_by = ?, decided_at = ?, decision_note = ?
+			WHERE id = ? AND status = ?
+		`, status, adminID, now, note, id, from)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("%w: request was decided concurrently", ErrAccessRequestState)
+		}
+
+		// Workspace tokens are managed by their course and keep their own features
+		if status == AccessRequestApproved {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT OR IGNORE INTO token_features (token_id, feature_id)
+				SELECT id, ? FROM tokens
+				WHERE user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+				  AND id NOT IN (SELECT token_id FROM course_workspace_members WHERE token_id IS NOT NULL)
+			`, ar.FeatureID, ar.UserID, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetAccessRequest(ctx, id)
+}
@@ -1,21 +1,36 @@
 package auth
 
 import (
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"API/internal/apierror"
 	"API/internal/common"
+	"API/internal/logging"
+	"API/internal/pagination"
 
 	"github.com/gin-gonic/gin"
 )
 
 // AdminHandler handles admin-only endpoints
 type AdminHandler struct {
-	repo       *Repository
-	tokenStore *TokenStore
-	features   *FeatureRegistry
-	quota      *QuotaEngine
-	usage      *UsageTracker
+	repo        *Repository
+	tokenStore  *TokenStore
+	features    *FeatureRegistry
+	quota       *QuotaEngine
+	usage       *UsageTracker
+	diagnostics *DiagnosticsStore
+	webhooks    *WebhookStore
+	surges      *SurgeSchedule
+	usageBoard  *FeatureUsageStore
+	errorLog    *logging.ErrorLog
+	sampling    *SamplingStore
+	throttle    *TokenThrottle
 }
 
 // NewAdminHandler creates a new admin handler
@@ -25,28 +40,48 @@ func NewAdminHandler(
 	features *FeatureRegistry,
 	quota *QuotaEngine,
 	usage *UsageTracker,
+	diagnostics *DiagnosticsStore,
+	webhooks *WebhookStore,
+	surges *SurgeSchedule,
+	usageBoard *FeatureUsageStore,
+	errorLog *logging.ErrorLog,
+	sampling *SamplingStore,
+	throttle *TokenThrottle,
 ) *AdminHandler {
 	return &AdminHandler{
-		repo:       repo,
-		tokenStore: tokenStore,
-		features:   features,
-		quota:      quota,
-		usage:      usage,
+		repo:        repo,
+		tokenStore:  tokenStore,
+		features:    features,
+		quota:       quota,
+		usage:       usage,
+		diagnostics: diagnostics,
+		webhooks:    webhooks,
+		surges:      surges,
+		usageBoard:  usageBoard,
+		errorLog:    errorLog,
+		sampling:    sampling,
+		throttle:    throttle,
 	}
 }
 
 // --- Group Management ---
 
-// ListGroups returns all groups
-// GET /admin/groups
+// ListGroups returns all groups, or the deleted ones with ?deleted=true
+// GET /admin/groups?deleted=
 func (h *AdminHandler) ListGroups(c *gin.Context) {
-	groups, err := h.repo.GetAllGroups()
+	var groups []Group
+	var err error
+	if c.Query("deleted") == "true" {
+		groups, err = h.repo.GetDeletedGroups(c.Request.Context())
+	} else {
+		groups, err = h.repo.GetAllGroups(c.Request.Context())
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to list groups"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list groups")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"groups": groups,
 	}))
 }
@@ -56,21 +91,21 @@ func (h *AdminHandler) ListGroups(c *gin.Context) {
 func (h *AdminHandler) GetGroup(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid group ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid group ID")))
 		return
 	}
 
-	group, err := h.repo.GetGroupByID(id)
+	group, err := h.repo.GetGroupByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to get group"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get group")))
 		return
 	}
 	if group == nil {
-		c.JSON(http.StatusNotFound, common.CreateErrorResponse([]string{"group not found"}))
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "group not found")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"group": group,
 	}))
 }
@@ -80,17 +115,17 @@ func (h *AdminHandler) GetGroup(c *gin.Context) {
 func (h *AdminHandler) CreateGroup(c *gin.Context) {
 	var req GroupCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
 		return
 	}
 
-	group, err := h.repo.CreateGroup(req.Name, req.DefaultRPM, req.Description)
+	group, err := h.repo.CreateGroup(c.Request.Context(), req.Name, req.DefaultRPM, req.MaxSessions, req.Description)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
 		return
 	}
 
-	c.JSON(http.StatusCreated, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
 		"group": group,
 	}))
 }
@@ -100,62 +135,96 @@ func (h *AdminHandler) CreateGroup(c *gin.Context) {
 func (h *AdminHandler) UpdateGroup(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid group ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid group ID")))
 		return
 	}
 
 	var req GroupUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
 		return
 	}
 
-	if err := h.repo.UpdateGroup(id, req.Name, req.DefaultRPM, req.Description); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to update group"}))
+	if err := h.repo.UpdateGroup(c.Request.Context(), id, req.Name, req.DefaultRPM, req.MaxSessions, req.Description); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update group")))
 		return
 	}
 
-	group, _ := h.repo.GetGroupByID(id)
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	group, _ := h.repo.GetGroupByID(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"group": group,
 	}))
 }
 
-// DeleteGroup deletes a group
+// DeleteGroup marks a group deleted; it can be restored
 // DELETE /admin/groups/:id
 func (h *AdminHandler) DeleteGroup(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid group ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid group ID")))
 		return
 	}
 
-	if err := h.repo.DeleteGroup(id); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to delete group"}))
+	deleted, err := h.repo.DeleteGroup(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrGroupInUse) {
+			common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+			return
+		}
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete group")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "group not found")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"message": "group deleted",
 	}))
 }
 
+// RestoreGroup restores a deleted group
+// POST /admin/groups/:id/restore
+func (h *AdminHandler) RestoreGroup(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid group ID")))
+		return
+	}
+
+	restored, err := h.repo.RestoreGroup(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to restore group")))
+		return
+	}
+	if !restored {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "deleted group not found")))
+		return
+	}
+
+	group, _ := h.repo.GetGroupByID(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"group": group,
+	}))
+}
+
 // GetGroupQuotas returns quotas for a group
 // GET /admin/groups/:id/quotas
 func (h *AdminHandler) GetGroupQuotas(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid group ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid group ID")))
 		return
 	}
 
-	quotas, err := h.quota.GetGroupFeatureQuotas(id)
+	quotas, err := h.quota.GetGroupFeatureQuotas(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to get quotas"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get quotas")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"quotas": quotas,
 	}))
 }
@@ -165,22 +234,22 @@ func (h *AdminHandler) GetGroupQuotas(c *gin.Context) {
 func (h *AdminHandler) SetGroupQuotas(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid group ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid group ID")))
 		return
 	}
 
 	var req QuotaSetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
 		return
 	}
 
-	if err := h.quota.BulkSetGroupFeatureQuotas(id, req.Quotas); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to set quotas"}))
+	if err := h.quota.BulkSetGroupFeatureQuotas(c.Request.Context(), id, req.Quotas); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to set quotas")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"message": "quotas updated",
 	}))
 }
@@ -190,37 +259,51 @@ func (h *AdminHandler) SetGroupQuotas(c *gin.Context) {
 // ListFeatures returns all features
 // GET /admin/features
 func (h *AdminHandler) ListFeatures(c *gin.Context) {
-	features, err := h.features.GetAllFeatures()
+	features, err := h.features.GetAllFeatures(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to list features"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list features")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"features": features,
 	}))
 }
 
+// GetFeatureTree returns all features nested under their parents
+// GET /admin/features/tree
+func (h *AdminHandler) GetFeatureTree(c *gin.Context) {
+	tree, err := h.features.GetFeatureTree(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list features")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"features": tree,
+	}))
+}
+
 // GetFeature returns a feature by ID
 // GET /admin/features/:id
 func (h *AdminHandler) GetFeature(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid feature ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid feature ID")))
 		return
 	}
 
-	feature, err := h.features.GetFeatureByID(id)
+	feature, err := h.features.GetFeatureByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to get feature"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get feature")))
 		return
 	}
 	if feature == nil {
-		c.JSON(http.StatusNotFound, common.CreateErrorResponse([]string{"feature not found"}))
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "feature not found")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"feature": feature,
 	}))
 }
@@ -230,17 +313,31 @@ func (h *AdminHandler) GetFeature(c *gin.Context) {
 func (h *AdminHandler) CreateFeature(c *gin.Context) {
 	var req FeatureCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
 		return
 	}
 
-	feature, err := h.features.CreateFeature(req.Slug, req.Name, req.ParentID, req.AdminOnly)
+	feature, err := h.features.CreateFeature(c.Request.Context(), req.Slug, req.Name, req.ParentID, req.AdminOnly)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
 		return
 	}
+	if req.ApprovalRequired {
+		if err := h.features.SetApprovalRequired(c.Request.Context(), feature.ID, true); err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update feature")))
+			return
+		}
+		feature.ApprovalRequired = true
+	}
+	if req.FeatureDocs.isSet() {
+		if err := h.features.SetFeatureDocs(c.Request.Context(), feature.ID, req.FeatureDocs); err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update feature")))
+			return
+		}
+		feature, _ = h.features.GetFeatureByID(c.Request.Context(), feature.ID)
+	}
 
-	c.JSON(http.StatusCreated, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
 		"feature": feature,
 	}))
 }
@@ -250,24 +347,112 @@ func (h *AdminHandler) CreateFeature(c *gin.Context) {
 func (h *AdminHandler) UpdateFeature(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid feature ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid feature ID")))
 		return
 	}
 
 	var req FeatureUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	if err := h.features.UpdateFeature(c.Request.Context(), id, req.Name, req.ParentID, req.AdminOnly); err != nil {
+		if errors.Is(err, ErrInvalidFeatureParent) {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+			return
+		}
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update feature")))
+		return
+	}
+	if req.ApprovalRequired != nil {
+		if err := h.features.SetApprovalRequired(c.Request.Context(), id, *req.ApprovalRequired); err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update feature")))
+			return
+		}
+	}
+	if req.DedupWindowMs != nil {
+		if err := h.features.SetDedupWindow(c.Request.Context(), id, *req.DedupWindowMs); err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update feature")))
+			return
+		}
+	}
+	if req.FeatureDocs.isSet() {
+		if err := h.features.SetFeatureDocs(c.Request.Context(), id, req.FeatureDocs); err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update feature")))
+			return
+		}
+	}
+	if req.Deprecated != nil || req.SunsetAt != nil {
+		deprecated := req.Deprecated == nil || *req.Deprecated
+		if err := h.features.SetDeprecation(c.Request.Context(), id, deprecated, req.SunsetAt); err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update feature")))
+			return
+		}
+	}
+
+	feature, _ := h.features.GetFeatureByID(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"feature": feature,
+	}))
+}
+
+// GetFeatureUsage ranks the users and tokens sending the most requests to a
+// feature (and its sub-features), so they can be contacted before it changes
+// GET /admin/features/:id/usage?days=7&limit=10
+func (h *AdminHandler) GetFeatureUsage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid feature ID")))
+		return
+	}
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "invalid days")))
+		return
+	}
+	if maxDays := int(FeatureUsageRetention.Hours() / 24); days > maxDays {
+		days = maxDays
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	feature, err := h.features.GetFeatureByID(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get feature")))
+		return
+	}
+	if feature == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "feature not found")))
 		return
 	}
 
-	if err := h.features.UpdateFeature(id, req.Name, req.ParentID, req.AdminOnly); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to update feature"}))
+	board, err := h.usageBoard.Leaderboard(c.Request.Context(), id, time.Now().AddDate(0, 0, -days), limit)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get feature usage")))
 		return
 	}
 
-	feature, _ := h.features.GetFeatureByID(id)
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"feature": feature,
+		"days":    days,
+		"usage":   board,
+	}))
+}
+
+// ListDeprecatedFeatureTokens lists active tokens still scoped to deprecated features
+// GET /admin/features/deprecated/tokens
+func (h *AdminHandler) ListDeprecatedFeatureTokens(c *gin.Context) {
+	tokens, err := h.tokenStore.ListDeprecatedFeatureTokens(c.Request.Context())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list tokens")))
+		return
+	}
+
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"tokens": tokens,
 	}))
 }
 
@@ -276,16 +461,20 @@ func (h *AdminHandler) UpdateFeature(c *gin.Context) {
 func (h *AdminHandler) DeleteFeature(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid feature ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid feature ID")))
 		return
 	}
 
-	if err := h.features.DeleteFeature(id); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to delete feature"}))
+	if err := h.features.DeleteFeature(c.Request.Context(), id); err != nil {
+		if errors.Is(err, ErrFeatureInUse) {
+			common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+			return
+		}
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete feature")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"message": "feature deleted",
 	}))
 }
@@ -295,76 +484,307 @@ func (h *AdminHandler) DeleteFeature(c *gin.Context) {
 // ListAcademicDomains returns all academic domains
 // GET /admin/academic-domains
 func (h *AdminHandler) ListAcademicDomains(c *gin.Context) {
-	domains, err := h.repo.GetAllAcademicDomains()
+	domains, err := h.repo.GetAllAcademicDomains(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to list domains"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list domains")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"domains": domains,
 	}))
 }
 
-// AddAcademicDomain adds an academic domain
+// AddAcademicDomain adds an academic domain; a leading "*." matches every subdomain
 // POST /admin/academic-domains
 func (h *AdminHandler) AddAcademicDomain(c *gin.Context) {
 	var req struct {
 		Domain string `json:"domain" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
 		return
 	}
 
-	if err := h.repo.AddAcademicDomain(req.Domain); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to add domain"}))
+	domain, ok := normalizeAcademicDomain(req.Domain)
+	if !ok {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "invalid domain")))
 		return
 	}
 
-	c.JSON(http.StatusCreated, common.CreateSuccessResponse(gin.H{
+	if err := h.repo.AddAcademicDomain(c.Request.Context(), domain); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to add domain")))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
 		"message": "domain added",
+		"domain":  domain,
+	}))
+}
+
+// ImportAcademicDomains adds many academic domains at once, either as JSON
+// {"domains": [...]} or as a CSV/plain-text body with one domain per line
+// POST /admin/academic-domains/import
+func (h *AdminHandler) ImportAcademicDomains(c *gin.Context) {
+	var raw []string
+	if c.ContentType() == "application/json" {
+		var req struct {
+			Domains []string `json:"domains" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+			return
+		}
+		raw = req.Domains
+	} else {
+		reader := csv.NewReader(c.Request.Body)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "invalid CSV body")))
+			return
+		}
+		for _, record := range records {
+			raw = append(raw, record...)
+		}
+	}
+
+	domains := []string{}
+	invalid := []string{}
+	for _, value := range raw {
+		if strings.TrimSpace(value) == "" || strings.EqualFold(strings.TrimSpace(value), "domain") {
+			continue // blank cells and a CSV header
+		}
+		domain, ok := normalizeAcademicDomain(value)
+		if !ok {
+			invalid = append(invalid, value)
+			continue
+		}
+		domains = append(domains, domain)
+	}
+	if len(domains) == 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "no valid domains provided")))
+		return
+	}
+
+	added, err := h.repo.AddAcademicDomains(c.Request.Context(), domains)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to import domains")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"added":    added,
+		"existing": len(domains) - added,
+		"invalid":  invalid,
 	}))
 }
 
+// normalizeAcademicDomain lowercases a domain, strips a leading "@" and checks
+// that it is a hostname with at least two labels, optionally prefixed by "*."
+func normalizeAcademicDomain(value string) (string, bool) {
+	domain := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "@")
+	host := strings.TrimPrefix(domain, "*.")
+	if !strings.Contains(host, ".") || len(host) > 253 {
+		return "", false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", false
+		}
+		for _, ch := range label {
+			if (ch < 'a' || ch > 'z') && (ch < '0' || ch > '9') && ch != '-' {
+				return "", false
+			}
+		}
+	}
+	return domain, true
+}
+
 // RemoveAcademicDomain removes an academic domain
 // DELETE /admin/academic-domains/:domain
 func (h *AdminHandler) RemoveAcademicDomain(c *gin.Context) {
 	domain := c.Param("domain")
 
-	if err := h.repo.RemoveAcademicDomain(domain); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to remove domain"}))
+	if err := h.repo.RemoveAcademicDomain(c.Request.Context(), domain); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to remove domain")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"message": "domain removed",
 	}))
 }
 
+// --- Invitation Management ---
+
+// ListInvitations returns all invitations
+// GET /admin/invitations
+func (h *AdminHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.repo.GetAllInvitations(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list invitations")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"invitations": invitations,
+	}))
+}
+
+// CreateInvitation invites an email with a pre-assigned group and role
+// POST /admin/invitations
+func (h *AdminHandler) CreateInvitation(c *gin.Context) {
+	var req InvitationCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if req.Role == "" {
+		req.Role = RoleUser
+	}
+	if !req.Role.Valid() {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "invalid role")))
+		return
+	}
+
+	group, err := h.repo.GetGroupByID(c.Request.Context(), req.GroupID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get group")))
+		return
+	}
+	if group == nil || group.DeletedAt != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "group not found")))
+		return
+	}
+
+	existing, err := h.repo.GetUserByEmail(c.Request.Context(), req.Email)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to check user")))
+		return
+	}
+	if existing != nil {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, "a user with this email already exists")))
+		return
+	}
+
+	var invitedBy *int64
+	if admin := GetUserFromContext(c); admin != nil {
+		invitedBy = &admin.ID
+	}
+
+	invitation, err := h.repo.CreateInvitation(c.Request.Context(), req.Email, req.GroupID, req.Role, invitedBy, req.ExpiresAt)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create invitation")))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"invitation": invitation,
+	}))
+}
+
+// DeleteInvitation withdraws an invitation
+// DELETE /admin/invitations/:id
+func (h *AdminHandler) DeleteInvitation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid invitation ID")))
+		return
+	}
+
+	if err := h.repo.DeleteInvitation(c.Request.Context(), id); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete invitation")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "invitation deleted",
+	}))
+}
+
 // --- User Management ---
 
-// ListUsers returns all users with pagination
-// GET /admin/users
+// ListUsers returns users with search, filters and pagination
+// GET /admin/users?q=&role=&status=&groupId=&hasActiveTokens=&deleted=&limit=&cursor=
 func (h *AdminHandler) ListUsers(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	filter, err := parseUserFilter(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
 
-	if limit > 100 {
-		limit = 100
+	users, err := h.repo.GetAllUsers(c.Request.Context(), filter, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list users")))
+		return
 	}
 
-	users, err := h.repo.GetAllUsers(limit, offset)
+	total, err := h.repo.CountUsers(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to list users"}))
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count users")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
-		"users":  users,
-		"limit":  limit,
-		"offset": offset,
-	}))
+	users, next := pagination.Next(users, page, func(u User) int64 { return u.ID })
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"users": users,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
+}
+
+// parseUserFilter reads the user search filters from the query string
+func parseUserFilter(c *gin.Context) (UserFilter, error) {
+	filter := UserFilter{Query: c.Query("q")}
+
+	if v := c.Query("role"); v != "" {
+		role := Role(v)
+		if !role.Valid() {
+			return filter, fmt.Errorf("invalid role filter")
+		}
+		filter.Role = &role
+	}
+	if v := c.Query("status"); v != "" {
+		status := Status(v)
+		if status != StatusActive && status != StatusSuspended {
+			return filter, fmt.Errorf("invalid status filter")
+		}
+		filter.Status = &status
+	}
+	if v := c.Query("groupId"); v != "" {
+		groupID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid groupId filter")
+		}
+		filter.GroupID = &groupID
+	}
+	if v := c.Query("hasActiveTokens"); v != "" {
+		hasActiveTokens, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid hasActiveTokens filter")
+		}
+		filter.HasActiveTokens = &hasActiveTokens
+	}
+	if v := c.Query("deleted"); v != "" {
+		deleted, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid deleted filter")
+		}
+		filter.Deleted = deleted
+	}
+	filter.Tag = normalizeUserTag(c.Query("tag"))
+	return filter, nil
 }
 
 // GetUser returns a user by ID
@@ -372,22 +792,35 @@ func (h *AdminHandler) ListUsers(c *gin.Context) {
 func (h *AdminHandler) GetUser(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid user ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
 		return
 	}
 
-	user, err := h.repo.GetUserByID(id)
+	user, err := h.repo.GetUserByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to get user"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get user")))
 		return
 	}
 	if user == nil {
-		c.JSON(http.StatusNotFound, common.CreateErrorResponse([]string{"user not found"}))
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "user not found")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
-		"user": user,
+	notes, err := h.repo.GetUserNotes(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get user notes")))
+		return
+	}
+	tags, err := h.repo.GetUserTags(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get user tags")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"user":  user,
+		"notes": notes,
+		"tags":  tags,
 	}))
 }
 
@@ -396,23 +829,130 @@ func (h *AdminHandler) GetUser(c *gin.Context) {
 func (h *AdminHandler) UpdateUser(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid user ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
 		return
 	}
 
 	var req UserUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	suspending := req.Status != nil && *req.Status == StatusSuspended
+	if req.Status != nil && *req.Status != StatusActive && !suspending {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "invalid status")))
+		return
+	}
+	if !suspending && (req.SuspensionReason != nil || req.SuspendedUntil != nil) {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "suspension details require status \"suspended\"")))
+		return
+	}
+	if req.SuspendedUntil != nil && !req.SuspendedUntil.After(time.Now()) {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "suspendedUntil must be in the future")))
+		return
+	}
+
+	if req.GroupID != nil {
+		group, err := h.repo.GetGroupByID(c.Request.Context(), *req.GroupID)
+		if err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get group")))
+			return
+		}
+		if group == nil || group.DeletedAt != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "group not found")))
+			return
+		}
+	}
+
+	// Status changes go through SuspendUser/ReactivateUser so the suspension context stays in sync
+	if err := h.repo.UpdateUser(c.Request.Context(), id, req.Role, nil, req.GroupID, req.MaxTokens); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update user")))
+		return
+	}
+	if suspending {
+		var suspendedBy *int64
+		if admin := GetUserFromContext(c); admin != nil {
+			suspendedBy = &admin.ID
+		}
+		err = h.repo.SuspendUser(c.Request.Context(), id, req.SuspensionReason, suspendedBy, req.SuspendedUntil)
+	} else if req.Status != nil {
+		err = h.repo.ReactivateUser(c.Request.Context(), id)
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update user status")))
+		return
+	}
+
+	user, _ := h.repo.GetUserByID(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"user": user,
+	}))
+}
+
+// DeleteUser marks a user deleted and signs them out; it can be restored
+// DELETE /admin/users/:id
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
+		return
+	}
+
+	var deletedBy *int64
+	if admin := GetUserFromContext(c); admin != nil {
+		if admin.ID == id {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "you cannot delete your own account")))
+			return
+		}
+		deletedBy = &admin.ID
+	}
+
+	deleted, err := h.repo.DeleteUser(c.Request.Context(), id, deletedBy)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete user")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "user not found")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "user deleted",
+	}))
+}
+
+// RestoreUser restores a deleted user
+// POST /admin/users/:id/restore
+func (h *AdminHandler) RestoreUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
 		return
 	}
 
-	if err := h.repo.UpdateUser(id, req.Role, req.Status, req.GroupID, req.MaxTokens); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to update user"}))
+	var restoredBy *int64
+	if admin := GetUserFromContext(c); admin != nil {
+		restoredBy = &admin.ID
+	}
+
+	restored, err := h.repo.RestoreUser(c.Request.Context(), id, restoredBy)
+	if err != nil {
+		if errors.Is(err, ErrGroupDeleted) {
+			common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+			return
+		}
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to restore user")))
+		return
+	}
+	if !restored {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "deleted user not found")))
 		return
 	}
 
-	user, _ := h.repo.GetUserByID(id)
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	user, _ := h.repo.GetUserByID(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"user": user,
 	}))
 }
@@ -422,17 +962,17 @@ func (h *AdminHandler) UpdateUser(c *gin.Context) {
 func (h *AdminHandler) GetUserQuotas(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid user ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
 		return
 	}
 
-	overrides, err := h.quota.GetUserQuotaOverrides(id)
+	overrides, err := h.quota.GetUserQuotaOverrides(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to get quotas"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get quotas")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"overrides": overrides,
 	}))
 }
@@ -442,22 +982,22 @@ func (h *AdminHandler) GetUserQuotas(c *gin.Context) {
 func (h *AdminHandler) SetUserQuotas(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid user ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
 		return
 	}
 
 	var req QuotaSetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
 		return
 	}
 
-	if err := h.quota.BulkSetUserQuotaOverrides(id, req.Quotas); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to set quotas"}))
+	if err := h.quota.BulkSetUserQuotaOverrides(c.Request.Context(), id, req.Quotas); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to set quotas")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"message": "quotas updated",
 	}))
 }
@@ -467,90 +1007,724 @@ func (h *AdminHandler) SetUserQuotas(c *gin.Context) {
 func (h *AdminHandler) GetUserUsage(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid user ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
 		return
 	}
 
-	stats, err := h.usage.GetUsageStats(id)
+	stats, err := h.usage.GetUsageStats(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to get usage"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get usage")))
 		return
 	}
 
-	totalRPM, _ := h.usage.GetUserTotalRPM(id)
+	totalRPM, _ := h.usage.GetUserTotalRPM(c.Request.Context(), id)
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"totalRpm":  totalRPM,
 		"byFeature": stats,
 	}))
 }
 
-// --- Token Management ---
+// --- User Notes and Tags ---
 
-// CreateUserToken creates a token for a user (admin)
-// POST /admin/users/:id/tokens
-func (h *AdminHandler) CreateUserToken(c *gin.Context) {
+// AddUserNote adds an admin-only note to a user
+// POST /admin/users/:id/notes
+func (h *AdminHandler) AddUserNote(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"Invalid user ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
 		return
 	}
 
-	var req TokenCreateRequest
+	var req UserNoteCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "note body is required")))
 		return
 	}
 
-	// Admin-created tokens can have any features
-	token, err := h.tokenStore.CreateAdminToken(id, req.Label, req.Features, req.AllowedIPs, req.ExpiresAt)
+	user, err := h.repo.GetUserByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get user")))
+		return
+	}
+	if user == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "user not found")))
 		return
 	}
 
-	c.JSON(http.StatusCreated, common.CreateSuccessResponse(gin.H{
-		"token":   token.RawToken,
-		"details": token.Token,
-		"message": "Admin token created. Save this token now - it will not be shown again.",
+	var authorID *int64
+	if admin := GetUserFromContext(c); admin != nil {
+		authorID = &admin.ID
+	}
+
+	note, err := h.repo.CreateUserNote(c.Request.Context(), id, authorID, body)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to add note")))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"note": note,
 	}))
 }
 
-// ListUserTokens returns all tokens for a user (admin)
-// GET /admin/users/:id/tokens
-func (h *AdminHandler) ListUserTokens(c *gin.Context) {
+// DeleteUserNote removes an admin note from a user
+// DELETE /admin/users/:id/notes/:noteId
+func (h *AdminHandler) DeleteUserNote(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid user ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
+		return
+	}
+	noteID, err := strconv.ParseInt(c.Param("noteId"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid note ID")))
 		return
 	}
 
-	tokens, err := h.tokenStore.ListUserTokens(id)
+	deleted, err := h.repo.DeleteUserNote(c.Request.Context(), id, noteID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to list tokens"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete note")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "note not found")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
-		"tokens": tokens,
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "note deleted",
 	}))
 }
 
-// RevokeToken revokes any token (admin)
-// DELETE /admin/tokens/:id
-func (h *AdminHandler) RevokeToken(c *gin.Context) {
+// SetUserTags replaces the admin tags on a user
+// PUT /admin/users/:id/tags
+func (h *AdminHandler) SetUserTags(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid token ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
 		return
 	}
 
-	if err := h.tokenStore.AdminRevokeToken(id); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+	var req UserTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
-		"message": "token revoked",
+	user, err := h.repo.GetUserByID(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get user")))
+		return
+	}
+	if user == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "user not found")))
+		return
+	}
+
+	tags := make([]string, 0, len(req.Tags))
+	for _, tag := range req.Tags {
+		if tag = normalizeUserTag(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	if err := h.repo.SetUserTags(c.Request.Context(), id, tags); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to set tags")))
+		return
+	}
+
+	tags, _ = h.repo.GetUserTags(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"tags": tags,
+	}))
+}
+
+// normalizeUserTag lowercases a tag and joins its words with dashes
+func normalizeUserTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// --- Token Management ---
+
+// CreateUserToken creates a token for a user (admin)
+// POST /admin/users/:id/tokens
+func (h *AdminHandler) CreateUserToken(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "Invalid user ID")))
+		return
+	}
+
+	var req TokenCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	// Admin-created tokens can have any features
+	token, err := h.tokenStore.CreateAdminToken(c.Request.Context(), id, req.Label, req.Features, req.AllowedIPs, req.ExpiresAt)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"token":   token.RawToken,
+		"details": token.Token,
+		"message": "Admin token created. Save this token now - it will not be shown again.",
+	}))
+}
+
+// ListUserTokens returns a page of a user's tokens (admin)
+// GET /admin/users/:id/tokens?limit=&cursor=
+func (h *AdminHandler) ListUserTokens(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
+		return
+	}
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	tokens, err := h.tokenStore.ListUserTokensPage(c.Request.Context(), id, page)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list tokens")))
+		return
+	}
+
+	tokens, next := pagination.Next(tokens, page, func(t Token) int64 { return t.ID })
+	common.JSON(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"tokens": tokens,
+	}, next))
+}
+
+// RevokeToken revokes any token (admin)
+// DELETE /admin/tokens/:id
+func (h *AdminHandler) RevokeToken(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid token ID")))
+		return
+	}
+
+	if err := h.tokenStore.AdminRevokeToken(c.Request.Context(), id); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "token revoked",
+	}))
+}
+
+// RestoreToken restores a revoked token that has not expired (admin)
+// POST /admin/tokens/:id/restore
+func (h *AdminHandler) RestoreToken(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid token ID")))
+		return
+	}
+
+	if err := h.tokenStore.RestoreToken(c.Request.Context(), id); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	token, _ := h.tokenStore.GetTokenByID(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"token": token,
+	}))
+}
+
+// --- Statistics ---
+
+// GetStats returns aggregate statistics for the admin dashboard
+// GET /admin/stats?days=30
+func (h *AdminHandler) GetStats(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "invalid days")))
+		return
+	}
+	if days > 365 {
+		days = 365
+	}
+
+	stats, err := h.repo.GetAdminStats(c.Request.Context(), days, 10)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get stats")))
+		return
+	}
+
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"days":  days,
+		"stats": stats,
+	}))
+}
+
+// --- Webhook Management ---
+
+// ListWebhooks returns all webhooks and the events they can subscribe to
+// GET /admin/webhooks
+func (h *AdminHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhooks.List(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list webhooks")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"webhooks": webhooks,
+		"events":   WebhookEventTypes,
+	}))
+}
+
+// CreateWebhook registers a webhook. The signing secret is only returned here.
+// POST /admin/webhooks
+func (h *AdminHandler) CreateWebhook(c *gin.Context) {
+	var req WebhookCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	var createdBy *int64
+	if admin := GetUserFromContext(c); admin != nil {
+		createdBy = &admin.ID
+	}
+	webhook, err := h.webhooks.Create(c.Request.Context(), req, createdBy)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"webhook": webhook,
+		"secret":  webhook.Secret,
+	}))
+}
+
+// UpdateWebhook changes a webhook's URL, events, format or description, or pauses it
+// PATCH /admin/webhooks/:id
+func (h *AdminHandler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid webhook ID")))
+		return
+	}
+
+	var req WebhookUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	webhook, err := h.webhooks.Update(c.Request.Context(), id, req)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	if webhook == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "webhook not found")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"webhook": webhook,
+	}))
+}
+
+// DeleteWebhook removes a webhook and drops its undelivered events
+// DELETE /admin/webhooks/:id
+func (h *AdminHandler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid webhook ID")))
+		return
+	}
+
+	deleted, err := h.webhooks.Delete(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete webhook")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "webhook not found")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "Webhook deleted",
+	}))
+}
+
+// TestWebhook sends a ping event to a webhook and reports whether it was accepted
+// POST /admin/webhooks/:id/test
+func (h *AdminHandler) TestWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid webhook ID")))
+		return
+	}
+
+	webhook, err := h.webhooks.Get(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get webhook")))
+		return
+	}
+	if webhook == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "webhook not found")))
+		return
+	}
+
+	if err := h.webhooks.Ping(c.Request.Context(), webhook); err != nil {
+		common.JSON(c, http.StatusBadGateway, common.CreateErrorResponse(apierror.New(apierror.UpstreamFailed, err.Error())))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "Webhook accepted the test event",
+	}))
+}
+
+// --- Feature Access Requests ---
+
+// ListAccessRequests returns feature access requests, optionally filtered by ?status=
+// GET /admin/access-requests
+func (h *AdminHandler) ListAccessRequests(c *gin.Context) {
+	var status *AccessRequestStatus
+	if s := c.Query("status"); s != "" {
+		st := AccessRequestStatus(s)
+		status = &st
+	}
+
+	requests, err := h.features.ListAccessRequests(c.Request.Context(), nil, status)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list access requests")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"requests": requests,
+	}))
+}
+
+// ApproveAccessRequest grants the feature and adds it to the user's active tokens
+// POST /admin/access-requests/:id/approve
+func (h *AdminHandler) ApproveAccessRequest(c *gin.Context) {
+	h.decideAccessRequest(c, AccessRequestApproved)
+}
+
+// DenyAccessRequest turns down a pending access request
+// POST /admin/access-requests/:id/deny
+func (h *AdminHandler) DenyAccessRequest(c *gin.Context) {
+	h.decideAccessRequest(c, AccessRequestDenied)
+}
+
+// RevokeAccessRequest withdraws an approved access request
+// POST /admin/access-requests/:id/revoke
+func (h *AdminHandler) RevokeAccessRequest(c *gin.Context) {
+	h.decideAccessRequest(c, AccessRequestRevoked)
+}
+
+func (h *AdminHandler) decideAccessRequest(c *gin.Context, status AccessRequestStatus) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid access request ID")))
+		return
+	}
+
+	var req AccessRequestDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+			return
+		}
+	}
+
+	var adminID *int64
+	if admin := GetUserFromContext(c); admin != nil {
+		adminID = &admin.ID
+	}
+	request, err := h.features.DecideAccessRequest(c.Request.Context(), id, status, adminID, req.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAccessRequestNotFound):
+			common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, err.Error())))
+		case errors.Is(err, ErrAccessRequestState):
+			common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		default:
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update access request")))
+		}
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"request": request,
+	}))
+}
+
+// --- Surge Windows ---
+
+// ListSurgeWindows returns all surge windows, most recent first
+// GET /admin/surges
+func (h *AdminHandler) ListSurgeWindows(c *gin.Context) {
+	windows, err := h.surges.List(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list surge windows")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"surges": windows,
+	}))
+}
+
+// CreateSurgeWindow schedules a surge window
+// POST /admin/surges
+func (h *AdminHandler) CreateSurgeWindow(c *gin.Context) {
+	var req SurgeWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	var createdBy *int64
+	if admin := GetUserFromContext(c); admin != nil {
+		createdBy = &admin.ID
+	}
+	window, err := h.surges.Create(c.Request.Context(), req, createdBy)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"surge": window,
+	}))
+}
+
+// UpdateSurgeWindow replaces a surge window's schedule, multipliers and groups
+// PUT /admin/surges/:id
+func (h *AdminHandler) UpdateSurgeWindow(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid surge window ID")))
+		return
+	}
+
+	var req SurgeWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	window, err := h.surges.Update(c.Request.Context(), id, req)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	if window == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "surge window not found")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"surge": window,
+	}))
+}
+
+// DeleteSurgeWindow removes a surge window, ending it immediately if it is active
+// DELETE /admin/surges/:id
+func (h *AdminHandler) DeleteSurgeWindow(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid surge window ID")))
+		return
+	}
+
+	deleted, err := h.surges.Delete(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete surge window")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "surge window not found")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "Surge window deleted",
+	}))
+}
+
+// --- Diagnostics ---
+
+// GetRequestDiagnostic explains why a recent request was denied
+// GET /admin/diagnostics/requests/:id
+func (h *AdminHandler) GetRequestDiagnostic(c *gin.Context) {
+	diagnostic := h.diagnostics.Get(c.Param("id"))
+	if diagnostic == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "no denial recorded for this request ID (it may have expired)")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"diagnostic": diagnostic,
+	}))
+}
+
+// ListErrors returns the latest requests that failed on the server's side, newest
+// first, optionally only those with a status or of a route pattern (e.g.
+// /api/v0/events/:id)
+// GET /admin/errors?limit=&status=&route=
+func (h *AdminHandler) ListErrors(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+	limit = min(limit, h.errorLog.Size())
+	status := 0
+	if v := c.Query("status"); v != "" {
+		status, err = strconv.Atoi(v)
+		if err != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("status", "status must be a number")))
+			return
+		}
+	}
+	route := c.Query("route")
+
+	entries := h.errorLog.Recent(limit, func(e logging.ErrorEntry) bool {
+		return (status == 0 || e.Status == status) && (route == "" || e.Route == route)
+	})
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"errors": entries,
+		"kept":   h.errorLog.Size(),
+	}))
+}
+
+// ListThrottledAddresses returns the client addresses that recently sent invalid tokens,
+// those still blocked first
+// GET /admin/token-throttle
+func (h *AdminHandler) ListThrottledAddresses(c *gin.Context) {
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"addresses": h.throttle.List(),
+	}))
+}
+
+// LiftThrottle unblocks an address and forgets its failures, e.g. a campus NAT behind
+// which a misconfigured client kept sending a revoked token. For IPv6, any address in
+// the blocked /64 will do.
+// DELETE /admin/token-throttle/:ip
+func (h *AdminHandler) LiftThrottle(c *gin.Context) {
+	if !h.throttle.Lift(c.Param("ip")) {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "no invalid tokens recorded from this address")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "Address unblocked",
+	}))
+}
+
+// --- Request sampling ---
+
+// ListSamplingRules returns the sampling rules still running
+// GET /admin/sampling/rules
+func (h *AdminHandler) ListSamplingRules(c *gin.Context) {
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"rules": h.sampling.Rules(),
+	}))
+}
+
+// CreateSamplingRule starts recording a share of the requests to a feature, of a user, or
+// both, with their bodies, to reproduce a reported bug
+// POST /admin/sampling/rules
+func (h *AdminHandler) CreateSamplingRule(c *gin.Context) {
+	var req SamplingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	var createdBy *int64
+	if admin := GetUserFromContext(c); admin != nil {
+		createdBy = &admin.ID
+	}
+	rule, err := h.sampling.AddRule(req, createdBy)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"rule": rule,
+	}))
+}
+
+// DeleteSamplingRule stops a sampling rule; what it recorded stays until it expires
+// DELETE /admin/sampling/rules/:id
+func (h *AdminHandler) DeleteSamplingRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid sampling rule ID")))
+		return
+	}
+	if !h.sampling.DeleteRule(id) {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "sampling rule not found")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "Sampling rule deleted",
+	}))
+}
+
+// ListSampledRequests returns the recorded requests, newest first, without their headers
+// and bodies
+// GET /admin/sampling/requests?feature=&userId=&limit=
+func (h *AdminHandler) ListSampledRequests(c *gin.Context) {
+	var userID *int64
+	if v := c.Query("userId"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("userId", "userId must be a number")))
+			return
+		}
+		userID = &id
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+	limit = min(limit, SamplingMaxEntries)
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"requests": h.sampling.List(c.Query("feature"), userID, limit),
+	}))
+}
+
+// GetSampledRequest returns a recorded request with its redacted headers and bodies
+// GET /admin/sampling/requests/:id
+func (h *AdminHandler) GetSampledRequest(c *gin.Context) {
+	sampled := h.sampling.Get(c.Param("id"))
+	if sampled == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "no request recorded with this request ID (it may have expired)")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"request": sampled,
 	}))
 }
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"API/internal/databases/migrations"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// testRepository returns a repository on a freshly migrated auth database
+func testRepository(t *testing.T) *Repository {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "auth.db")
+	if err := migrations.Up("auth", file); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewRepository(db, nil)
+}
+
+func TestCreateInvitationRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &AdminHandler{repo: testRepository(t)}
+	router := gin.New()
+	router.POST("/admin/invitations", h.CreateInvitation)
+
+	tests := []struct {
+		name       string
+		role       Role
+		wantStatus int
+	}{
+		{"no role", "", http.StatusCreated},
+		{"user", RoleUser, http.StatusCreated},
+		{"staff", RoleStaff, http.StatusCreated},
+		{"data editor", RoleDataEditor, http.StatusCreated},
+		{"admin", RoleAdmin, http.StatusCreated},
+		{"unknown role", "owner", http.StatusBadRequest},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"email": "invitee%d@cs.duth.gr", "groupId": 1, "role": %q}`, i, tt.role)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/invitations", strings.NewReader(body)))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("inviting a %q = %d %s, want %d", tt.role, w.Code, w.Body.String(), tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			want := tt.role
+			if want == "" {
+				want = RoleUser
+			}
+			if !strings.Contains(w.Body.String(), `"role":"`+string(want)+`"`) {
+				t.Errorf("invitation %s, want role %q", w.Body.String(), want)
+			}
+		})
+	}
+}
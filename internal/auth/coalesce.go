@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderCoalescedWith names the request whose response was replayed
+const HeaderCoalescedWith = "X-Coalesced-With"
+
+// RequestCoalescer serves byte-identical GET requests from one token with a
+// single execution. Mobile clients retry aggressively while connectivity
+// flaps; without this every retry is charged and they run into 429s.
+type RequestCoalescer struct {
+	mu       sync.Mutex
+	inflight map[string]*coalescedRequest
+}
+
+// coalescedRequest is the shared response of a group of identical requests.
+// Its fields are written by the leader and only read after done is closed.
+type coalescedRequest struct {
+	done      chan struct{}
+	requestID string
+	// released is set when the leader has no response to share: it flushed a stream,
+	// e.g. of server-sent events, or failed. The waiting requests then run themselves.
+	released bool
+	status   int
+	header   http.Header
+	body     []byte
+}
+
+// NewRequestCoalescer creates a new request coalescer
+func NewRequestCoalescer() *RequestCoalescer {
+	return &RequestCoalescer{inflight: make(map[string]*coalescedRequest)}
+}
+
+// coalesce serves c with the response of the in-flight request with the same key, if
+// any, and reports whether c must be handled itself. The request then leads the group,
+// and finish must be called once its response is written.
+func (rc *RequestCoalescer) coalesce(c *gin.Context, key, requestID string, window time.Duration) (lead bool, finish func()) {
+	entry, leader := rc.join(key, requestID)
+	if !leader {
+		select {
+		case <-entry.done:
+		case <-c.Request.Context().Done():
+			c.Abort()
+			return false, nil
+		}
+		if entry.released {
+			return true, func() {}
+		}
+		entry.replay(c)
+		return false, nil
+	}
+
+	started := time.Now()
+	writer := &coalescingResponseWriter{ResponseWriter: c.Writer}
+	// Waiting out a stream would take as long as the stream
+	writer.onStream = func() { rc.release(key, entry) }
+	c.Writer = writer
+	// finish is deferred, so it also runs while a panic unwinds. The recovery middleware
+	// answers that with a 500, which like other failures is not shared.
+	return true, func() {
+		r := recover()
+		switch {
+		case writer.streaming:
+		case r != nil || writer.Status() >= http.StatusInternalServerError:
+			rc.release(key, entry)
+		default:
+			rc.finish(key, entry, writer, started, window)
+		}
+		if r != nil {
+			panic(r)
+		}
+	}
+}
+
+// join returns the group for key. The caller leads it when none was in
+// flight and must call finish once its response is written.
+func (rc *RequestCoalescer) join(key, requestID string) (*coalescedRequest, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if entry, ok := rc.inflight[key]; ok {
+		return entry, false
+	}
+	entry := &coalescedRequest{done: make(chan struct{}), requestID: requestID}
+	rc.inflight[key] = entry
+	return entry, true
+}
+
+// finish publishes the leader's response to the waiting requests and keeps
+// it for the rest of the window, so retries arriving just after are covered
+func (rc *RequestCoalescer) finish(key string, entry *coalescedRequest, w *coalescingResponseWriter, started time.Time, window time.Duration) {
+	entry.status = w.Status()
+	entry.header = w.Header().Clone()
+	entry.body = w.body.Bytes()
+	close(entry.done)
+
+	if remaining := time.Until(started.Add(window)); remaining > 0 {
+		time.AfterFunc(remaining, func() { rc.forget(key, entry) })
+	} else {
+		rc.forget(key, entry)
+	}
+}
+
+// release ends the group of entry without a response, so the waiting requests are
+// handled themselves
+func (rc *RequestCoalescer) release(key string, entry *coalescedRequest) {
+	entry.released = true
+	close(entry.done)
+	rc.forget(key, entry)
+}
+
+// forget ends the group of entry, so the next request with its key leads a new one
+func (rc *RequestCoalescer) forget(key string, entry *coalescedRequest) {
+	rc.mu.Lock()
+	if rc.inflight[key] == entry {
+		delete(rc.inflight, key)
+	}
+	rc.mu.Unlock()
+}
+
+// replay writes a shared response to a follower and aborts its chain
+func (entry *coalescedRequest) replay(c *gin.Context) {
+	for name, values := range entry.header {
+		if name == http.CanonicalHeaderKey(HeaderRequestID) {
+			continue
+		}
+		c.Writer.Header()[name] = values
+	}
+	c.Header(HeaderCoalescedWith, entry.requestID)
+	c.Data(entry.status, entry.header.Get("Content-Type"), entry.body)
+	c.Abort()
+}
+
+// coalesceKey identifies a request by token, URL and the headers that select
+// the representation
+func coalesceKey(tokenID int64, c *gin.Context) string {
+	return fmt.Sprintf("%d %s %s\n%s\n%s", tokenID, c.Request.Method, c.Request.URL.RequestURI(),
+		c.GetHeader("Accept"), c.GetHeader("Accept-Language"))
+}
+
+// coalescingResponseWriter keeps a copy of the body so it can be replayed. When onStream
+// is set, flushing or hijacking the connection marks the response as a stream: the copy
+// is dropped and onStream called, since a stream can run for hours.
+type coalescingResponseWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	onStream  func()
+	streaming bool
+}
+
+func (w *coalescingResponseWriter) Write(data []byte) (int, error) {
+	if !w.streaming {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *coalescingResponseWriter) WriteString(s string) (int, error) {
+	if !w.streaming {
+		w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *coalescingResponseWriter) Flush() {
+	w.stream()
+	w.ResponseWriter.Flush()
+}
+
+func (w *coalescingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.stream()
+	return w.ResponseWriter.Hijack()
+}
+
+func (w *coalescingResponseWriter) stream() {
+	if w.onStream == nil || w.streaming {
+		return
+	}
+	w.streaming = true
+	w.body = bytes.Buffer{}
+	w.onStream()
+}
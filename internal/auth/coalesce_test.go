@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// coalescingRouter serves GET /*path through rc like the middleware does, counting the
+// runs of handler
+func coalescingRouter(rc *RequestCoalescer, window time.Duration, handler gin.HandlerFunc) (*gin.Engine, *atomic.Int32) {
+	gin.SetMode(gin.TestMode)
+	var runs atomic.Int32
+	router := gin.New()
+	router.Use(gin.RecoveryWithWriter(io.Discard))
+	router.GET("/*path", func(c *gin.Context) {
+		lead, finish := rc.coalesce(c, coalesceKey(1, c), c.GetHeader(HeaderRequestID), window)
+		if !lead {
+			return
+		}
+		defer finish()
+		runs.Add(1)
+		handler(c)
+	})
+	return router, &runs
+}
+
+// get requests target with the request ID id
+func get(router http.Handler, target, id string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r.Header.Set(HeaderRequestID, id)
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func TestRequestCoalescer(t *testing.T) {
+	respond := func(c *gin.Context) {
+		c.String(http.StatusOK, "menu of %s", c.Param("path"))
+	}
+	stream := func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.String(http.StatusOK, "data: 1\n\n")
+		c.Writer.Flush()
+		c.String(http.StatusOK, "data: 2\n\n")
+	}
+	fail := func(c *gin.Context) {
+		c.String(http.StatusServiceUnavailable, "try again")
+	}
+	crash := func(c *gin.Context) {
+		panic("handler failed")
+	}
+	tests := []struct {
+		name       string
+		window     time.Duration
+		handler    gin.HandlerFunc
+		second     string
+		wantRuns   int32
+		wantReplay bool
+	}{
+		{"a retry within the window is replayed", time.Minute, respond, "/menu", 1, true},
+		{"a retry after the window runs again", 0, respond, "/menu", 2, false},
+		{"another URL runs on its own", time.Minute, respond, "/foods", 2, false},
+		{"a stream is not kept", time.Minute, stream, "/menu", 2, false},
+		{"a failure is not kept", time.Minute, fail, "/menu", 2, false},
+		{"a panic is not kept", time.Minute, crash, "/menu", 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, runs := coalescingRouter(NewRequestCoalescer(), tt.window, tt.handler)
+			first := get(router, "/menu", "a")
+			second := get(router, tt.second, "b")
+
+			if got := runs.Load(); got != tt.wantRuns {
+				t.Errorf("handler ran %d times, want %d", got, tt.wantRuns)
+			}
+			replayed := second.Header().Get(HeaderCoalescedWith) == "a"
+			if replayed != tt.wantReplay {
+				t.Errorf("replayed = %t, want %t", replayed, tt.wantReplay)
+			}
+			if replayed && second.Body.String() != first.Body.String() {
+				t.Errorf("replayed body %q, want %q", second.Body.String(), first.Body.String())
+			}
+		})
+	}
+}
+
+// A follower waiting on a stream would wait as long as the stream stays open
+func TestRequestCoalescerStreamReleasesFollowers(t *testing.T) {
+	flushed, closeStream := make(chan struct{}), make(chan struct{})
+	var streams atomic.Int32
+	router, runs := coalescingRouter(NewRequestCoalescer(), time.Minute, func(c *gin.Context) {
+		c.String(http.StatusOK, "data: 1\n\n")
+		c.Writer.Flush()
+		if streams.Add(1) == 1 {
+			close(flushed)
+			<-closeStream
+		}
+	})
+
+	leader := make(chan *httptest.ResponseRecorder)
+	go func() { leader <- get(router, "/stream", "a") }()
+	<-flushed
+
+	follower := make(chan *httptest.ResponseRecorder)
+	go func() { follower <- get(router, "/stream", "b") }()
+	select {
+	case w := <-follower:
+		if w.Header().Get(HeaderCoalescedWith) != "" {
+			t.Error("the follower was served the leader's stream")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the follower waited for the leader's stream to end")
+	}
+	close(closeStream)
+	<-leader
+
+	if got := runs.Load(); got != 2 {
+		t.Errorf("handler ran %d times, want 2", got)
+	}
+}
+
+func TestCoalescingResponseWriterBuffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		onStream bool
+		writes   []string // "flush" flushes
+		want     string
+	}{
+		{"keeps the body", true, []string{"a", "b"}, "ab"},
+		{"drops a stream's body", true, []string{"a", "flush", "b"}, ""},
+		{"keeps flushed bodies without onStream", false, []string{"a", "flush", "b"}, "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			streamed := 0
+			w := &coalescingResponseWriter{ResponseWriter: c.Writer}
+			if tt.onStream {
+				w.onStream = func() { streamed++ }
+			}
+			for _, write := range tt.writes {
+				if write == "flush" {
+					w.Flush()
+					w.Flush()
+					continue
+				}
+				w.WriteString(write)
+			}
+			if got := w.body.String(); got != tt.want {
+				t.Errorf("buffered %q, want %q", got, tt.want)
+			}
+			if streamed > 1 {
+				t.Errorf("onStream called %d times", streamed)
+			}
+		})
+	}
+}
@@ -1,17 +1,45 @@
 package auth
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"API/internal/events"
+	"API/internal/pagination"
 )
 
 // Repository provides access to auth-related database operations
 type Repository struct {
-	db *sql.DB
+	db     *sql.DB
+	outbox *events.Outbox
+}
+
+// NewRepository creates a new auth repository. Events produced by writes are
+// recorded in the outbox within the same transaction; outbox may be nil.
+func NewRepository(db *sql.DB, outbox *events.Outbox) *Repository {
+	return &Repository{db: db, outbox: outbox}
 }
 
-// NewRepository creates a new auth repository
-func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+// WithTx runs fn in a transaction, committing when it returns nil and rolling
+// back otherwise. Events fn records in the outbox are dispatched once it commits.
+func (r *Repository) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	r.outbox.Notify()
+	return nil
 }
 
 // DB returns the underlying database connection
@@ -19,19 +47,39 @@ func (r *Repository) DB() *sql.DB {
 	return r.db
 }
 
-// EnableWAL enables Write-Ahead Logging mode for better concurrent performance. This slows startup time.
-func (r *Repository) EnableWAL() error {
-	_, err := r.db.Exec("PRAGMA journal_mode=WAL")
-	return err
+// --- Group Operations ---
+
+// groupColumns lists the groups columns read by scanGroup
+const groupColumns = `id, name, default_rpm, max_sessions, description, created_at, deleted_at`
+
+// scanGroup scans groupColumns
+func scanGroup(row rowScanner) (*Group, error) {
+	var g Group
+	var desc sql.NullString
+	var deletedAt sql.NullTime
+	if err := row.Scan(&g.ID, &g.Name, &g.DefaultRPM, &g.MaxSessions, &desc, &g.CreatedAt, &deletedAt); err != nil {
+		return nil, err
+	}
+	g.Description = ScanNullableString(desc)
+	g.DeletedAt = ScanNullableTime(deletedAt)
+	return &g, nil
 }
 
-// --- Group Operations ---
+// GetAllGroups returns all groups that are not deleted
+func (r *Repository) GetAllGroups(ctx context.Context) ([]Group, error) {
+	return r.queryGroups(ctx, "WHERE deleted_at IS NULL")
+}
+
+// GetDeletedGroups returns the deleted groups, which can be restored
+func (r *Repository) GetDeletedGroups(ctx context.Context) ([]Group, error) {
+	return r.queryGroups(ctx, "WHERE deleted_at IS NOT NULL")
+}
 
-// GetAllGroups returns all groups
-func (r *Repository) GetAllGroups() ([]Group, error) {
-	rows, err := r.db.Query(`
-		SELECT id, name, default_rpm, description, created_at 
-		FROM groups 
+func (r *Repository) queryGroups(ctx context.Context, where string) ([]Group, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+groupColumns+`
+		FROM groups
+		`+where+`
 		ORDER BY name
 	`)
 	if err != nil {
@@ -41,96 +89,141 @@ func (r *Repository) GetAllGroups() ([]Group, error) {
 
 	var groups []Group
 	for rows.Next() {
-		var g Group
-		var desc sql.NullString
-		if err := rows.Scan(&g.ID, &g.Name, &g.DefaultRPM, &desc, &g.CreatedAt); err != nil {
+		g, err := scanGroup(rows)
+		if err != nil {
 			return nil, err
 		}
-		g.Description = ScanNullableString(desc)
-		groups = append(groups, g)
+		groups = append(groups, *g)
 	}
 	return groups, rows.Err()
 }
 
-// GetGroupByID returns a group by ID
-func (r *Repository) GetGroupByID(id int64) (*Group, error) {
-	var g Group
-	var desc sql.NullString
-	err := r.db.QueryRow(`
-		SELECT id, name, default_rpm, description, created_at 
+// GroupNames returns the names of every group by ID, deleted or not
+func (r *Repository) GroupNames(ctx context.Context) (map[int64]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name FROM groups`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		names[id] = name
+	}
+	return names, rows.Err()
+}
+
+// GetGroupByID returns a group by ID, deleted or not
+func (r *Repository) GetGroupByID(ctx context.Context, id int64) (*Group, error) {
+	g, err := scanGroup(r.db.QueryRowContext(ctx, `
+		SELECT `+groupColumns+`
 		FROM groups WHERE id = ?
-	`, id).Scan(&g.ID, &g.Name, &g.DefaultRPM, &desc, &g.CreatedAt)
+	`, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	g.Description = ScanNullableString(desc)
-	return &g, nil
+	return g, nil
 }
 
-// GetGroupByName returns a group by name
-func (r *Repository) GetGroupByName(name string) (*Group, error) {
-	var g Group
-	var desc sql.NullString
-	err := r.db.QueryRow(`
-		SELECT id, name, default_rpm, description, created_at 
-		FROM groups WHERE name = ?
-	`, name).Scan(&g.ID, &g.Name, &g.DefaultRPM, &desc, &g.CreatedAt)
+// GetGroupByName returns a group by name, skipping deleted groups
+func (r *Repository) GetGroupByName(ctx context.Context, name string) (*Group, error) {
+	g, err := scanGroup(r.db.QueryRowContext(ctx, `
+		SELECT `+groupColumns+`
+		FROM groups WHERE name = ? AND deleted_at IS NULL
+	`, name))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	g.Description = ScanNullableString(desc)
-	return &g, nil
+	return g, nil
 }
 
 // CreateGroup creates a new group
-func (r *Repository) CreateGroup(name string, defaultRPM int, description *string) (*Group, error) {
-	result, err := r.db.Exec(`
-		INSERT INTO groups (name, default_rpm, description) VALUES (?, ?, ?)
-	`, name, defaultRPM, description)
+func (r *Repository) CreateGroup(ctx context.Context, name string, defaultRPM, maxSessions int, description *string) (*Group, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO groups (name, default_rpm, max_sessions, description) VALUES (?, ?, ?, ?)
+	`, name, defaultRPM, maxSessions, description)
 	if err != nil {
 		return nil, err
 	}
 	id, _ := result.LastInsertId()
-	return r.GetGroupByID(id)
+	return r.GetGroupByID(ctx, id)
 }
 
 // UpdateGroup updates a group
-func (r *Repository) UpdateGroup(id int64, name *string, defaultRPM *int, description *string) error {
+func (r *Repository) UpdateGroup(ctx context.Context, id int64, name *string, defaultRPM, maxSessions *int, description *string) error {
 	if name != nil {
-		if _, err := r.db.Exec("UPDATE groups SET name = ? WHERE id = ?", *name, id); err != nil {
+		if _, err := r.db.ExecContext(ctx, "UPDATE groups SET name = ? WHERE id = ?", *name, id); err != nil {
 			return err
 		}
 	}
 	if defaultRPM != nil {
-		if _, err := r.db.Exec("UPDATE groups SET default_rpm = ? WHERE id = ?", *defaultRPM, id); err != nil {
+		if _, err := r.db.ExecContext(ctx, "UPDATE groups SET default_rpm = ? WHERE id = ?", *defaultRPM, id); err != nil {
+			return err
+		}
+	}
+	if maxSessions != nil {
+		if _, err := r.db.ExecContext(ctx, "UPDATE groups SET max_sessions = ? WHERE id = ?", *maxSessions, id); err != nil {
 			return err
 		}
 	}
 	if description != nil {
-		if _, err := r.db.Exec("UPDATE groups SET description = ? WHERE id = ?", *description, id); err != nil {
+		if _, err := r.db.ExecContext(ctx, "UPDATE groups SET description = ? WHERE id = ?", *description, id); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// DeleteGroup deletes a group by ID
-func (r *Repository) DeleteGroup(id int64) error {
-	_, err := r.db.Exec("DELETE FROM groups WHERE id = ?", id)
-	return err
+// ErrGroupInUse is returned when deleting a group that still has members
+var ErrGroupInUse = errors.New("group still has users, move them to another group first")
+
+// DeleteGroup marks a group deleted. Its quotas are kept, so restoring it
+// brings them back. Groups with users that are not deleted are refused with
+// ErrGroupInUse. Returns false when the group does not exist or is already
+// deleted.
+func (r *Repository) DeleteGroup(ctx context.Context, id int64) (bool, error) {
+	var members int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE group_id = ? AND deleted_at IS NULL", id).Scan(&members); err != nil {
+		return false, err
+	}
+	if members > 0 {
+		return false, ErrGroupInUse
+	}
+	result, err := r.db.ExecContext(ctx, "UPDATE groups SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", time.Now(), id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// RestoreGroup clears the deletion of a group. Returns false when the group
+// does not exist or is not deleted.
+func (r *Repository) RestoreGroup(ctx context.Context, id int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "UPDATE groups SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
 }
 
 // --- Academic Domain Operations ---
 
 // GetAllAcademicDomains returns all academic domains
-func (r *Repository) GetAllAcademicDomains() ([]string, error) {
-	rows, err := r.db.Query("SELECT domain FROM academic_domains ORDER BY domain")
+func (r *Repository) GetAllAcademicDomains(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT domain FROM academic_domains ORDER BY domain")
 	if err != nil {
 		return nil, err
 	}
@@ -147,10 +240,18 @@ func (r *Repository) GetAllAcademicDomains() ([]string, error) {
 	return domains, rows.Err()
 }
 
-// IsAcademicDomain checks if a domain grants academic status
-func (r *Repository) IsAcademicDomain(domain string) (bool, error) {
+// IsAcademicDomain checks if a domain grants academic status, either by an
+// exact entry or by a wildcard entry such as *.duth.gr covering a subdomain
+func (r *Repository) IsAcademicDomain(ctx context.Context, domain string) (bool, error) {
+	candidates := []interface{}{domain}
+	for rest := domain; strings.Contains(rest, "."); {
+		rest = rest[strings.Index(rest, ".")+1:]
+		candidates = append(candidates, "*."+rest)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(candidates)), ",")
 	var count int
-	err := r.db.QueryRow("SELECT COUNT(*) FROM academic_domains WHERE domain = ?", domain).Scan(&count)
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM academic_domains WHERE domain IN ("+placeholders+")", candidates...).Scan(&count)
 	if err != nil {
 		return false, err
 	}
@@ -158,34 +259,52 @@ func (r *Repository) IsAcademicDomain(domain string) (bool, error) {
 }
 
 // AddAcademicDomain adds a new academic domain
-func (r *Repository) AddAcademicDomain(domain string) error {
-	_, err := r.db.Exec("INSERT OR IGNORE INTO academic_domains (domain) VALUES (?)", domain)
+func (r *Repository) AddAcademicDomain(ctx context.Context, domain string) error {
+	_, err := r.db.ExecContext(ctx, "INSERT OR IGNORE INTO academic_domains (domain) VALUES (?)", domain)
 	return err
 }
 
+// AddAcademicDomains adds several academic domains in one transaction and
+// returns how many were not already present
+func (r *Repository) AddAcademicDomains(ctx context.Context, domains []string) (int, error) {
+	added := 0
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		for _, domain := range domains {
+			result, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO academic_domains (domain) VALUES (?)", domain)
+			if err != nil {
+				return err
+			}
+			n, _ := result.RowsAffected()
+			added += int(n)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
 // RemoveAcademicDomain removes an academic domain
-func (r *Repository) RemoveAcademicDomain(domain string) error {
-	_, err := r.db.Exec("DELETE FROM academic_domains WHERE domain = ?", domain)
+func (r *Repository) RemoveAcademicDomain(ctx context.Context, domain string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM academic_domains WHERE domain = ?", domain)
 	return err
 }
 
 // --- User Operations ---
 
 // GetUserByID returns a user by ID with group info
-func (r *Repository) GetUserByID(id int64) (*User, error) {
-	var u User
+func (r *Repository) GetUserByID(ctx context.Context, id int64) (*User, error) {
 	var g Group
 	var groupDesc sql.NullString
-	err := r.db.QueryRow(`
-		SELECT u.id, u.email, u.display_name, u.role, u.status, u.group_id, u.max_tokens, u.created_at,
-		       g.id, g.name, g.default_rpm, g.description, g.created_at
+	var groupDeletedAt sql.NullTime
+	u, err := scanUser(r.db.QueryRowContext(ctx, `
+		SELECT `+userColumns+`,
+		       g.id, g.name, g.default_rpm, g.max_sessions, g.description, g.created_at, g.deleted_at
 		FROM users u
 		JOIN groups g ON u.group_id = g.id
 		WHERE u.id = ?
-	`, id).Scan(
-		&u.ID, &u.Email, &u.DisplayName, &u.Role, &u.Status, &u.GroupID, &u.MaxTokens, &u.CreatedAt,
-		&g.ID, &g.Name, &g.DefaultRPM, &groupDesc, &g.CreatedAt,
-	)
+	`, id), &g.ID, &g.Name, &g.DefaultRPM, &g.MaxSessions, &groupDesc, &g.CreatedAt, &groupDeletedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -193,36 +312,69 @@ func (r *Repository) GetUserByID(id int64) (*User, error) {
 		return nil, err
 	}
 	g.Description = ScanNullableString(groupDesc)
+	g.DeletedAt = ScanNullableTime(groupDeletedAt)
 	u.Group = &g
-	return &u, nil
+	return u, nil
 }
 
 // GetUserByEmail returns a user by email
-func (r *Repository) GetUserByEmail(email string) (*User, error) {
-	var u User
-	err := r.db.QueryRow(`
-		SELECT id, email, display_name, role, status, group_id, max_tokens, created_at
-		FROM users WHERE email = ?
-	`, email).Scan(&u.ID, &u.Email, &u.DisplayName, &u.Role, &u.Status, &u.GroupID, &u.MaxTokens, &u.CreatedAt)
+func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	u, err := scanUser(r.db.QueryRowContext(ctx, `
+		SELECT `+userColumns+`
+		FROM users u WHERE u.email = ?
+	`, email))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
+	return u, nil
+}
+
+// userColumns lists the users columns read by scanUser, aliased as u
+const userColumns = `u.id, u.email, u.display_name, u.role, u.status, u.group_id, u.max_tokens, u.created_at,
+		       u.suspension_reason, u.suspended_by, u.suspended_at, u.suspended_until, u.deleted_at`
+
+// scanUser scans userColumns followed by any extra destinations (e.g. joined group fields)
+func scanUser(row rowScanner, extra ...interface{}) (*User, error) {
+	var u User
+	var reason sql.NullString
+	var suspendedBy sql.NullInt64
+	var suspendedAt, suspendedUntil, deletedAt sql.NullTime
+	dest := []interface{}{
+		&u.ID, &u.Email, &u.DisplayName, &u.Role, &u.Status, &u.GroupID, &u.MaxTokens, &u.CreatedAt,
+		&reason, &suspendedBy, &suspendedAt, &suspendedUntil, &deletedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	u.SuspensionReason = ScanNullableString(reason)
+	u.SuspendedBy = ScanNullableInt64(suspendedBy)
+	u.SuspendedAt = ScanNullableTime(suspendedAt)
+	u.SuspendedUntil = ScanNullableTime(suspendedUntil)
+	u.DeletedAt = ScanNullableTime(deletedAt)
 	return &u, nil
 }
 
-// GetAllUsers returns all users with pagination
-func (r *Repository) GetAllUsers(limit, offset int) ([]User, error) {
-	rows, err := r.db.Query(`
-		SELECT u.id, u.email, u.display_name, u.role, u.status, u.group_id, u.max_tokens, u.created_at,
-		       g.id, g.name, g.default_rpm, g.description, g.created_at
+// GetAllUsers returns a page of users matching the filter, newest first. The
+// page holds one extra user when another page follows (see pagination.Next).
+func (r *Repository) GetAllUsers(ctx context.Context, filter UserFilter, page pagination.Params) ([]User, error) {
+	where, args := buildUserFilter(filter)
+	after, afterArgs := page.Where("u.id")
+	where += " AND " + after
+	args = append(args, afterArgs...)
+	args = append(args, page.FetchLimit())
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+userColumns+`,
+		       g.id, g.name, g.default_rpm, g.max_sessions, g.description, g.created_at, g.deleted_at
 		FROM users u
 		JOIN groups g ON u.group_id = g.id
-		ORDER BY u.created_at DESC
-		LIMIT ? OFFSET ?
-	`, limit, offset)
+		`+where+`
+		ORDER BY u.id DESC
+		LIMIT ?
+	`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -230,76 +382,456 @@ func (r *Repository) GetAllUsers(limit, offset int) ([]User, error) {
 
 	var users []User
 	for rows.Next() {
-		var u User
 		var g Group
 		var groupDesc sql.NullString
-		if err := rows.Scan(
-			&u.ID, &u.Email, &u.DisplayName, &u.Role, &u.Status, &u.GroupID, &u.MaxTokens, &u.CreatedAt,
-			&g.ID, &g.Name, &g.DefaultRPM, &groupDesc, &g.CreatedAt,
-		); err != nil {
+		var groupDeletedAt sql.NullTime
+		u, err := scanUser(rows, &g.ID, &g.Name, &g.DefaultRPM, &g.MaxSessions, &groupDesc, &g.CreatedAt, &groupDeletedAt)
+		if err != nil {
 			return nil, err
 		}
 		g.Description = ScanNullableString(groupDesc)
+		g.DeletedAt = ScanNullableTime(groupDeletedAt)
 		u.Group = &g
-		users = append(users, u)
+		users = append(users, *u)
 	}
 	return users, rows.Err()
 }
 
+// CountUsers returns the number of users matching the filter
+func (r *Repository) CountUsers(ctx context.Context, filter UserFilter) (int, error) {
+	where, args := buildUserFilter(filter)
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users u "+where, args...).Scan(&count)
+	return count, err
+}
+
+// buildUserFilter builds the WHERE clause for a user filter over the "u" alias
+func buildUserFilter(filter UserFilter) (string, []interface{}) {
+	conditions := []string{"u.deleted_at IS NULL"}
+	if filter.Deleted {
+		conditions[0] = "u.deleted_at IS NOT NULL"
+	}
+	var args []interface{}
+
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		pattern := "%" + strings.ToLower(q) + "%"
+		conditions = append(conditions, "(LOWER(u.email) LIKE ? OR LOWER(u.display_name) LIKE ?)")
+		args = append(args, pattern, pattern)
+	}
+	if filter.Role != nil {
+		conditions = append(conditions, "u.role = ?")
+		args = append(args, *filter.Role)
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, "u.status = ?")
+		args = append(args, *filter.Status)
+	}
+	if filter.GroupID != nil {
+		conditions = append(conditions, "u.group_id = ?")
+		args = append(args, *filter.GroupID)
+	}
+	if filter.HasActiveTokens != nil {
+		clause := `EXISTS (
+			SELECT 1 FROM tokens t
+			WHERE t.user_id = u.id AND t.revoked_at IS NULL AND (t.expires_at IS NULL OR t.expires_at > ?)
+		)`
+		if !*filter.HasActiveTokens {
+			clause = "NOT " + clause
+		}
+		conditions = append(conditions, clause)
+		args = append(args, time.Now())
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM user_tags ut WHERE ut.user_id = u.id AND ut.tag = ?)")
+		args = append(args, filter.Tag)
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// enqueueEvent records an event in the outbox on its own, for events that are
+// not the result of a write (e.g. a rejected request)
+func (r *Repository) enqueueEvent(ctx context.Context, event events.Event) error {
+	return r.WithTx(ctx, func(tx *sql.Tx) error {
+		return r.outbox.Enqueue(tx, event)
+	})
+}
+
 // CreateUser creates a new user
-func (r *Repository) CreateUser(email, displayName string, groupID int64) (*User, error) {
-	result, err := r.db.Exec(`
+func (r *Repository) CreateUser(ctx context.Context, email, displayName string, groupID int64) (*User, error) {
+	var id int64
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		id, err = r.insertUser(ctx, tx, email, displayName, groupID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetUserByID(ctx, id)
+}
+
+// CreateOAuthUser creates a user along with the OAuth identity they signed in
+// with. An invitation, when given, sets the user's role and is marked
+// accepted. Either all of it is stored or none, so a failed sign-in never
+// leaves a user without an identity to sign in with.
+func (r *Repository) CreateOAuthUser(ctx context.Context, email, displayName string, groupID int64, invitation *Invitation, provider Provider, providerID, accessToken, refreshToken string) (*User, error) {
+	var id int64
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if id, err = r.insertUser(ctx, tx, email, displayName, groupID); err != nil {
+			return err
+		}
+		if invitation != nil {
+			if invitation.Role != RoleUser {
+				if _, err := tx.ExecContext(ctx, "UPDATE users SET role = ? WHERE id = ?", invitation.Role, id); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.ExecContext(ctx, "UPDATE invitations SET accepted_at = ? WHERE id = ?", time.Now(), invitation.ID); err != nil {
+				return err
+			}
+		}
+		_, err = insertOAuthIdentity(ctx, tx, id, provider, providerID, accessToken, refreshToken)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetUserByID(ctx, id)
+}
+
+// insertUser adds a user and records its user.created event
+func (r *Repository) insertUser(ctx context.Context, tx *sql.Tx, email, displayName string, groupID int64) (int64, error) {
+	result, err := tx.ExecContext(ctx, `
 		INSERT INTO users (email, display_name, group_id) VALUES (?, ?, ?)
 	`, email, displayName, groupID)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 	id, _ := result.LastInsertId()
-	return r.GetUserByID(id)
+
+	err = r.outbox.Enqueue(tx, events.UserCreated{
+		UserID:     id,
+		Email:      email,
+		GroupID:    groupID,
+		OccurredAt: time.Now(),
+	})
+	return id, err
 }
 
 // UpdateUser updates user fields
-func (r *Repository) UpdateUser(id int64, role *Role, status *Status, groupID *int64, maxTokens *int) error {
-	if role != nil {
-		if _, err := r.db.Exec("UPDATE users SET role = ? WHERE id = ?", *role, id); err != nil {
+func (r *Repository) UpdateUser(ctx context.Context, id int64, role *Role, status *Status, groupID *int64, maxTokens *int) error {
+	return r.WithTx(ctx, func(tx *sql.Tx) error {
+		if role != nil {
+			if _, err := tx.ExecContext(ctx, "UPDATE users SET role = ? WHERE id = ?", *role, id); err != nil {
+				return err
+			}
+		}
+		if status != nil {
+			if _, err := tx.ExecContext(ctx, "UPDATE users SET status = ? WHERE id = ?", *status, id); err != nil {
+				return err
+			}
+		}
+		if groupID != nil {
+			if _, err := tx.ExecContext(ctx, "UPDATE users SET group_id = ? WHERE id = ?", *groupID, id); err != nil {
+				return err
+			}
+		}
+		if maxTokens != nil {
+			if _, err := tx.ExecContext(ctx, "UPDATE users SET max_tokens = ? WHERE id = ?", *maxTokens, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SuspendUser suspends a user with an optional reason and expiry
+func (r *Repository) SuspendUser(ctx context.Context, id int64, reason *string, suspendedBy *int64, until *time.Time) error {
+	return r.WithTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now()
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE users
+			SET status = ?, suspension_reason = ?, suspended_by = ?, suspended_at = ?, suspended_until = ?
+			WHERE id = ?
+		`, StatusSuspended, reason, suspendedBy, now, until, id); err != nil {
 			return err
 		}
+
+		return r.outbox.Enqueue(tx, events.UserSuspended{
+			UserID:         id,
+			Reason:         reason,
+			SuspendedBy:    suspendedBy,
+			SuspendedUntil: until,
+			OccurredAt:     now,
+		})
+	})
+}
+
+// ReactivateUser lifts a suspension and clears its context
+func (r *Repository) ReactivateUser(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET status = ?, suspension_reason = NULL, suspended_by = NULL, suspended_at = NULL, suspended_until = NULL
+		WHERE id = ?
+	`, StatusActive, id)
+	return err
+}
+
+// ReactivateExpiredSuspensions lifts all suspensions whose suspended_until has passed
+func (r *Repository) ReactivateExpiredSuspensions(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET status = ?, suspension_reason = NULL, suspended_by = NULL, suspended_at = NULL, suspended_until = NULL
+		WHERE status = ? AND suspended_until IS NOT NULL AND suspended_until <= ?
+	`, StatusActive, StatusSuspended, time.Now())
+	if err != nil {
+		return 0, err
 	}
-	if status != nil {
-		if _, err := r.db.Exec("UPDATE users SET status = ? WHERE id = ?", *status, id); err != nil {
+	return result.RowsAffected()
+}
+
+// ErrGroupDeleted is returned when restoring a user whose group is deleted
+var ErrGroupDeleted = errors.New("the user's group is deleted, restore it or move the user first")
+
+// DeleteUser marks a user deleted and signs them out. Tokens, quotas, notes
+// and usage are kept, so restoring the user brings them back; until then
+// the tokens are refused. Returns false when the user does not exist or is
+// already deleted.
+func (r *Repository) DeleteUser(ctx context.Context, id int64, deletedBy *int64) (bool, error) {
+	deleted := false
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now()
+		result, err := tx.ExecContext(ctx, "UPDATE users SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", now, id)
+		if err != nil {
 			return err
 		}
-	}
-	if groupID != nil {
-		if _, err := r.db.Exec("UPDATE users SET group_id = ? WHERE id = ?", *groupID, id); err != nil {
+		if n, _ := result.RowsAffected(); n == 0 {
+			return nil
+		}
+		deleted = true
+		if _, err := tx.ExecContext(ctx, "DELETE FROM sessions WHERE user_id = ?", id); err != nil {
 			return err
 		}
-	}
-	if maxTokens != nil {
-		if _, err := r.db.Exec("UPDATE users SET max_tokens = ? WHERE id = ?", *maxTokens, id); err != nil {
+		return r.outbox.Enqueue(tx, events.UserDeleted{
+			UserID:     id,
+			DeletedBy:  deletedBy,
+			OccurredAt: now,
+		})
+	})
+	return deleted, err
+}
+
+// RestoreUser clears the deletion of a user. Users of a deleted group are
+// refused with ErrGroupDeleted. Returns false when the user does not exist
+// or is not deleted.
+func (r *Repository) RestoreUser(ctx context.Context, id int64, restoredBy *int64) (bool, error) {
+	restored := false
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		var groupDeleted bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT g.deleted_at IS NOT NULL
+			FROM users u
+			JOIN groups g ON u.group_id = g.id
+			WHERE u.id = ? AND u.deleted_at IS NOT NULL
+		`, id).Scan(&groupDeleted)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
 			return err
 		}
+		if groupDeleted {
+			return ErrGroupDeleted
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET deleted_at = NULL WHERE id = ?", id); err != nil {
+			return err
+		}
+		restored = true
+		return r.outbox.Enqueue(tx, events.UserRestored{
+			UserID:     id,
+			RestoredBy: restoredBy,
+			OccurredAt: time.Now(),
+		})
+	})
+	return restored, err
+}
+
+// CountAdmins returns the number of active admin users
+func (r *Repository) CountAdmins(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM users WHERE role = ? AND status = ? AND deleted_at IS NULL
+	`, RoleAdmin, StatusActive).Scan(&count)
+	return count, err
+}
+
+// BootstrapAdmin promotes a user to admin only if no active admin exists yet.
+// Returns false when an admin already exists and nothing was changed.
+func (r *Repository) BootstrapAdmin(ctx context.Context, userID int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET role = ?
+		WHERE id = ? AND NOT EXISTS (
+			SELECT 1 FROM users WHERE role = ? AND status = ? AND deleted_at IS NULL
+		)
+	`, RoleAdmin, userID, RoleAdmin, StatusActive)
+	if err != nil {
+		return false, err
 	}
-	return nil
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
 }
 
-// GetUserTokenCount returns the number of active tokens for a user
-func (r *Repository) GetUserTokenCount(userID int64) (int, error) {
+// GetUserTokenCount returns the number of active tokens for a user.
+// Tokens issued through course workspaces do not count.
+func (r *Repository) GetUserTokenCount(ctx context.Context, userID int64) (int, error) {
 	var count int
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM tokens 
 		WHERE user_id = ? AND revoked_at IS NULL
+		  AND id NOT IN (SELECT token_id FROM course_workspace_members WHERE token_id IS NOT NULL)
 	`, userID).Scan(&count)
 	return count, err
 }
 
+// --- User Note and Tag Operations ---
+
+// GetUserNotes returns the admin notes on a user, newest first
+func (r *Repository) GetUserNotes(ctx context.Context, userID int64) ([]UserNote, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, author_id, body, created_at
+		FROM user_notes
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []UserNote{}
+	for rows.Next() {
+		var n UserNote
+		var authorID sql.NullInt64
+		if err := rows.Scan(&n.ID, &n.UserID, &authorID, &n.Body, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		n.AuthorID = ScanNullableInt64(authorID)
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
+
+// CreateUserNote adds an admin note to a user
+func (r *Repository) CreateUserNote(ctx context.Context, userID int64, authorID *int64, body string) (*UserNote, error) {
+	var n UserNote
+	var author sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_notes (user_id, author_id, body) VALUES (?, ?, ?)
+		RETURNING id, user_id, author_id, body, created_at
+	`, userID, authorID, body).Scan(&n.ID, &n.UserID, &author, &n.Body, &n.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	n.AuthorID = ScanNullableInt64(author)
+	return &n, nil
+}
+
+// DeleteUserNote removes a note from a user, reporting whether it existed
+func (r *Repository) DeleteUserNote(ctx context.Context, userID, noteID int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM user_notes WHERE id = ? AND user_id = ?", noteID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// GetUserTags returns the admin tags on a user in alphabetical order
+func (r *Repository) GetUserTags(ctx context.Context, userID int64) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT tag FROM user_tags WHERE user_id = ? ORDER BY tag", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// SetUserTags replaces all admin tags on a user
+func (r *Repository) SetUserTags(ctx context.Context, userID int64, tags []string) error {
+	return r.WithTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM user_tags WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO user_tags (user_id, tag) VALUES (?, ?)", userID, tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// --- Preference Operations ---
+
+// GetPreferences returns a user's dietary preferences, empty when none are set
+func (r *Repository) GetPreferences(ctx context.Context, userID int64) (*Preferences, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT kind, value FROM user_preferences WHERE user_id = ? ORDER BY kind, value", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prefs := &Preferences{Diets: []string{}, Allergens: []string{}}
+	for rows.Next() {
+		var kind, value string
+		if err := rows.Scan(&kind, &value); err != nil {
+			return nil, err
+		}
+		if kind == "diet" {
+			prefs.Diets = append(prefs.Diets, value)
+		} else {
+			prefs.Allergens = append(prefs.Allergens, value)
+		}
+	}
+	return prefs, rows.Err()
+}
+
+// SetPreferences replaces a user's dietary preferences
+func (r *Repository) SetPreferences(ctx context.Context, userID int64, prefs Preferences) error {
+	return r.WithTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM user_preferences WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+		for kind, values := range map[string][]string{"diet": prefs.Diets, "allergen": prefs.Allergens} {
+			for _, value := range values {
+				if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO user_preferences (user_id, kind, value) VALUES (?, ?, ?)", userID, kind, value); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
 // --- OAuth Identity Operations ---
 
 // GetOAuthIdentity returns an OAuth identity by provider and provider ID
-func (r *Repository) GetOAuthIdentity(provider Provider, providerID string) (*OAuthIdentity, error) {
+func (r *Repository) GetOAuthIdentity(ctx context.Context, provider Provider, providerID string) (*OAuthIdentity, error) {
 	var o OAuthIdentity
 	var accessToken, refreshToken sql.NullString
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT id, user_id, provider, provider_id, access_token, refresh_token, created_at
 		FROM oauth_identities
 		WHERE provider = ? AND provider_id = ?
@@ -316,19 +848,20 @@ func (r *Repository) GetOAuthIdentity(provider Provider, providerID string) (*OA
 }
 
 // CreateOAuthIdentity creates a new OAuth identity
-func (r *Repository) CreateOAuthIdentity(userID int64, provider Provider, providerID, accessToken, refreshToken string) (*OAuthIdentity, error) {
-	result, err := r.db.Exec(`
-		INSERT INTO oauth_identities (user_id, provider, provider_id, access_token, refresh_token)
-		VALUES (?, ?, ?, ?, ?)
-	`, userID, provider, providerID, accessToken, refreshToken)
+func (r *Repository) CreateOAuthIdentity(ctx context.Context, userID int64, provider Provider, providerID, accessToken, refreshToken string) (*OAuthIdentity, error) {
+	var id int64
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		id, err = insertOAuthIdentity(ctx, tx, userID, provider, providerID, accessToken, refreshToken)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	id, _ := result.LastInsertId()
 
 	var o OAuthIdentity
 	var at, rt sql.NullString
-	err = r.db.QueryRow(`
+	err = r.db.QueryRowContext(ctx, `
 		SELECT id, user_id, provider, provider_id, access_token, refresh_token, created_at
 		FROM oauth_identities WHERE id = ?
 	`, id).Scan(&o.ID, &o.UserID, &o.Provider, &o.ProviderID, &at, &rt, &o.CreatedAt)
@@ -340,10 +873,107 @@ func (r *Repository) CreateOAuthIdentity(userID int64, provider Provider, provid
 	return &o, nil
 }
 
+func insertOAuthIdentity(ctx context.Context, tx *sql.Tx, userID int64, provider Provider, providerID, accessToken, refreshToken string) (int64, error) {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO oauth_identities (user_id, provider, provider_id, access_token, refresh_token)
+		VALUES (?, ?, ?, ?, ?)
+	`, userID, provider, providerID, accessToken, refreshToken)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
 // UpdateOAuthIdentityTokens updates the tokens for an OAuth identity
-func (r *Repository) UpdateOAuthIdentityTokens(id int64, accessToken, refreshToken string) error {
-	_, err := r.db.Exec(`
+func (r *Repository) UpdateOAuthIdentityTokens(ctx context.Context, id int64, accessToken, refreshToken string) error {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE oauth_identities SET access_token = ?, refresh_token = ? WHERE id = ?
 	`, accessToken, refreshToken, id)
 	return err
 }
+
+// --- Invitation Operations ---
+
+// GetAllInvitations returns all invitations, pending first
+func (r *Repository) GetAllInvitations(ctx context.Context) ([]Invitation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, email, group_id, role, invited_by, expires_at, accepted_at, created_at
+		FROM invitations
+		ORDER BY accepted_at IS NOT NULL, created_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []Invitation
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *inv)
+	}
+	return invitations, rows.Err()
+}
+
+// GetPendingInvitationByEmail returns an unaccepted, unexpired invitation for an email
+func (r *Repository) GetPendingInvitationByEmail(ctx context.Context, email string) (*Invitation, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, email, group_id, role, invited_by, expires_at, accepted_at, created_at
+		FROM invitations
+		WHERE email = ? AND accepted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+	`, strings.ToLower(email), time.Now())
+	inv, err := scanInvitation(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// CreateInvitation creates or replaces the invitation for an email
+func (r *Repository) CreateInvitation(ctx context.Context, email string, groupID int64, role Role, invitedBy *int64, expiresAt *time.Time) (*Invitation, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO invitations (email, group_id, role, invited_by, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (email) DO UPDATE SET
+			group_id = excluded.group_id,
+			role = excluded.role,
+			invited_by = excluded.invited_by,
+			expires_at = excluded.expires_at,
+			accepted_at = NULL,
+			created_at = CURRENT_TIMESTAMP
+	`, email, groupID, role, invitedBy, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetPendingInvitationByEmail(ctx, email)
+}
+
+// DeleteInvitation deletes an invitation by ID
+func (r *Repository) DeleteInvitation(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM invitations WHERE id = ?", id)
+	return err
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanInvitation(row rowScanner) (*Invitation, error) {
+	var inv Invitation
+	var invitedBy sql.NullInt64
+	var expiresAt, acceptedAt sql.NullTime
+	if err := row.Scan(&inv.ID, &inv.Email, &inv.GroupID, &inv.Role, &invitedBy, &expiresAt, &acceptedAt, &inv.CreatedAt); err != nil {
+		return nil, err
+	}
+	inv.InvitedBy = ScanNullableInt64(invitedBy)
+	inv.ExpiresAt = ScanNullableTime(expiresAt)
+	inv.AcceptedAt = ScanNullableTime(acceptedAt)
+	return &inv, nil
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DiagnosticsTTL is how long denial decisions are kept for support lookups
+	DiagnosticsTTL = 30 * time.Minute
+
+	// DiagnosticsMaxEntries bounds memory use under a flood of denied requests
+	DiagnosticsMaxEntries = 10000
+)
+
+// DiagnosticCheck is one step of the token middleware's decision chain
+type DiagnosticCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// QuotaDiagnostic captures the numbers behind a rate limit decision
+type QuotaDiagnostic struct {
+	EffectiveRPM  int `json:"effectiveRpm"`
+	CurrentRPM    int `json:"currentRpm"`
+	WindowSeconds int `json:"windowSeconds"`
+}
+
+// DenialDiagnostic explains why a request was answered with 401/403/429
+type DenialDiagnostic struct {
+	RequestID   string            `json:"requestId"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Status      int               `json:"status"`
+	Reason      string            `json:"reason"`
+	Method      string            `json:"method"`
+	Path        string            `json:"path"`
+	ClientIP    string            `json:"clientIp"`
+	FeatureSlug string            `json:"featureSlug"`
+	TokenID     *int64            `json:"tokenId,omitempty"`
+	UserID      *int64            `json:"userId,omitempty"`
+	Checks      []DiagnosticCheck `json:"checks"`
+	Quota       *QuotaDiagnostic  `json:"quota,omitempty"`
+}
+
+// addCheck appends a step to the decision chain
+func (d *DenialDiagnostic) addCheck(name string, passed bool, detail string) {
+	d.Checks = append(d.Checks, DiagnosticCheck{Name: name, Passed: passed, Detail: detail})
+}
+
+type diagnosticsEntry struct {
+	requestID string
+	expiresAt time.Time
+}
+
+// DiagnosticsStore keeps recent denial decisions in memory, keyed by request ID
+type DiagnosticsStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*DenialDiagnostic
+	order   []diagnosticsEntry // insertion order == expiry order
+}
+
+// NewDiagnosticsStore creates a new diagnostics store
+func NewDiagnosticsStore(ttl time.Duration) *DiagnosticsStore {
+	if ttl == 0 {
+		ttl = DiagnosticsTTL
+	}
+	return &DiagnosticsStore{
+		ttl:     ttl,
+		entries: make(map[string]*DenialDiagnostic),
+	}
+}
+
+// Record stores a denial decision
+func (s *DiagnosticsStore) Record(d *DenialDiagnostic) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	for len(s.order) >= DiagnosticsMaxEntries {
+		delete(s.entries, s.order[0].requestID)
+		s.order = s.order[1:]
+	}
+
+	s.entries[d.RequestID] = d
+	s.order = append(s.order, diagnosticsEntry{requestID: d.RequestID, expiresAt: time.Now().Add(s.ttl)})
+}
+
+// Get returns the denial decision for a request ID, or nil if unknown or expired
+func (s *DiagnosticsStore) Get(requestID string) *DenialDiagnostic {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	return s.entries[requestID]
+}
+
+func (s *DiagnosticsStore) pruneLocked(now time.Time) {
+	i := 0
+	for i < len(s.order) && s.order[i].expiresAt.Before(now) {
+		delete(s.entries, s.order[i].requestID)
+		i++
+	}
+	s.order = s.order[i:]
+}
@@ -0,0 +1,251 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"API/internal/health"
+)
+
+const (
+	// FeatureUsageFlushInterval is how often buffered per-token request counts are written
+	FeatureUsageFlushInterval = 10 * time.Second
+
+	// FeatureUsageRetention is how long hourly per-token request counts are kept
+	FeatureUsageRetention = 90 * 24 * time.Hour
+
+	// featureUsageHourFormat is the hour bucket stored in feature_usage_hourly (UTC)
+	featureUsageHourFormat = "2006-01-02 15:00:00"
+)
+
+// FeatureUsageStore keeps hourly request counts per token and feature. The
+// usage log only covers the last minute for rate limiting; these counts tell
+// admins who depends on a feature before they change or limit it.
+type FeatureUsageStore struct {
+	repo *Repository
+
+	// Request counts buffered by RecordUsage until the next flush
+	mu          sync.Mutex
+	pending     map[featureUsageKey]int
+	lastCleanup time.Time
+	stopCh      chan struct{}
+	wg          sync.WaitGroup
+	heartbeat   *health.Heartbeat
+}
+
+type featureUsageKey struct {
+	tokenID   int64
+	userID    int64
+	featureID int64
+	hour      string
+}
+
+// UserRequestCount ranks a user by requests to a feature
+type UserRequestCount struct {
+	UserID      int64     `json:"userId"`
+	Email       string    `json:"email"`
+	DisplayName string    `json:"displayName"`
+	Requests    int       `json:"requests"`
+	LastHour    time.Time `json:"lastHour"` // Start of the last hour with requests
+}
+
+// TokenRequestCount ranks a token by requests to a feature
+type TokenRequestCount struct {
+	TokenID  int64     `json:"tokenId"`
+	Label    string    `json:"label"`
+	UserID   int64     `json:"userId"`
+	Email    string    `json:"email"`
+	Revoked  bool      `json:"revoked"`
+	Requests int       `json:"requests"`
+	LastHour time.Time `json:"lastHour"`
+}
+
+// FeatureUsageLeaderboard lists the heaviest users and tokens of a feature
+type FeatureUsageLeaderboard struct {
+	FeatureID int64               `json:"featureId"`
+	Since     time.Time           `json:"since"`
+	Total     int                 `json:"total"`
+	TopUsers  []UserRequestCount  `json:"topUsers"`
+	TopTokens []TokenRequestCount `json:"topTokens"`
+}
+
+// NewFeatureUsageStore creates a new feature usage store
+func NewFeatureUsageStore(repo *Repository) *FeatureUsageStore {
+	return &FeatureUsageStore{
+		repo:      repo,
+		pending:   make(map[featureUsageKey]int),
+		stopCh:    make(chan struct{}),
+		heartbeat: health.NewHeartbeat(FeatureUsageFlushInterval),
+	}
+}
+
+// RecordUsage is a HookPostUsage hook counting requests per token and feature
+func (s *FeatureUsageStore) RecordUsage(hc *HookContext) error {
+	if hc.Token == nil || hc.Feature == nil {
+		return nil
+	}
+	key := featureUsageKey{
+		tokenID:   hc.Token.ID,
+		userID:    hc.Token.UserID,
+		featureID: hc.Feature.ID,
+		hour:      time.Now().UTC().Format(featureUsageHourFormat),
+	}
+	s.mu.Lock()
+	s.pending[key]++
+	s.mu.Unlock()
+	return nil
+}
+
+// Start begins the background goroutine flushing buffered request counts
+func (s *FeatureUsageStore) Start(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(FeatureUsageFlushInterval)
+		defer ticker.Stop()
+		s.heartbeat.Beat()
+		defer s.heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				s.Flush()
+				return
+			case <-s.stopCh:
+				s.Flush()
+				return
+			case <-ticker.C:
+				s.heartbeat.Beat()
+				s.Flush()
+			}
+		}
+	}()
+}
+
+// Heartbeat reports whether the flush loop is running
+func (s *FeatureUsageStore) Heartbeat() *health.Heartbeat {
+	return s.heartbeat
+}
+
+// Stop gracefully stops the feature usage store
+func (s *FeatureUsageStore) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// Flush writes buffered request counts and, once an hour, drops counts older
+// than FeatureUsageRetention
+func (s *FeatureUsageStore) Flush() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[featureUsageKey]int)
+	cleanup := time.Since(s.lastCleanup) >= time.Hour
+	if cleanup {
+		s.lastCleanup = time.Now()
+	}
+	s.mu.Unlock()
+
+	// Not bound to the loop's context, which is already cancelled for the
+	// final flush
+	ctx := context.Background()
+
+	if cleanup {
+		cutoff := time.Now().Add(-FeatureUsageRetention).UTC().Format(featureUsageHourFormat)
+		s.repo.db.ExecContext(ctx, "DELETE FROM feature_usage_hourly WHERE hour < ?", cutoff)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	// Errors are dropped, like usage flushing
+	s.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		for key, count := range pending {
+			tx.ExecContext(ctx, `
+				INSERT INTO feature_usage_hourly (token_id, user_id, feature_id, hour, request_count)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (token_id, feature_id, hour) DO UPDATE SET request_count = request_count + excluded.request_count
+			`, key.tokenID, key.userID, key.featureID, key.hour, count)
+		}
+		return nil
+	})
+}
+
+// Leaderboard returns the users and tokens with the most requests to a
+// feature and its sub-features since the given time
+func (s *FeatureUsageStore) Leaderboard(ctx context.Context, featureID int64, since time.Time, limit int) (*FeatureUsageLeaderboard, error) {
+	board := &FeatureUsageLeaderboard{
+		FeatureID: featureID,
+		Since:     since,
+		TopUsers:  []UserRequestCount{},
+		TopTokens: []TokenRequestCount{},
+	}
+	const subtree = `
+		WITH RECURSIVE subtree(id) AS (
+			SELECT ?
+			UNION
+			SELECT f.id FROM features f JOIN subtree ON f.parent_id = subtree.id
+		)`
+	hour := since.UTC().Format(featureUsageHourFormat)
+
+	err := s.repo.db.QueryRowContext(ctx, subtree+`
+		SELECT COALESCE(SUM(request_count), 0) FROM feature_usage_hourly
+		WHERE feature_id IN (SELECT id FROM subtree) AND hour >= ?
+	`, featureID, hour).Scan(&board.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.db.QueryContext(ctx, subtree+`
+		SELECT u.id, u.email, u.display_name, SUM(h.request_count) AS requests, MAX(h.hour)
+		FROM feature_usage_hourly h
+		JOIN users u ON u.id = h.user_id
+		WHERE h.feature_id IN (SELECT id FROM subtree) AND h.hour >= ?
+		GROUP BY u.id
+		ORDER BY requests DESC, u.id
+		LIMIT ?
+	`, featureID, hour, limit)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var uc UserRequestCount
+		var lastHour string
+		if err := rows.Scan(&uc.UserID, &uc.Email, &uc.DisplayName, &uc.Requests, &lastHour); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		uc.LastHour, _ = time.Parse(featureUsageHourFormat, lastHour)
+		board.TopUsers = append(board.TopUsers, uc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.repo.db.QueryContext(ctx, subtree+`
+		SELECT t.id, t.label, u.id, u.email, t.revoked_at IS NOT NULL, SUM(h.request_count) AS requests, MAX(h.hour)
+		FROM feature_usage_hourly h
+		JOIN tokens t ON t.id = h.token_id
+		JOIN users u ON u.id = t.user_id
+		WHERE h.feature_id IN (SELECT id FROM subtree) AND h.hour >= ?
+		GROUP BY t.id
+		ORDER BY requests DESC, t.id
+		LIMIT ?
+	`, featureID, hour, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tc TokenRequestCount
+		var lastHour string
+		if err := rows.Scan(&tc.TokenID, &tc.Label, &tc.UserID, &tc.Email, &tc.Revoked, &tc.Requests, &lastHour); err != nil {
+			return nil, err
+		}
+		tc.LastHour, _ = time.Parse(featureUsageHourFormat, lastHour)
+		board.TopTokens = append(board.TopTokens, tc)
+	}
+	return board, rows.Err()
+}
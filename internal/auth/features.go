@@ -1,7 +1,25 @@
 package auth
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxFeatureDepth is the maximum number of levels in the feature hierarchy (a root is level 1)
+const MaxFeatureDepth = 5
+
+var (
+	// ErrInvalidFeatureParent is returned when a parent would create a cycle or exceed MaxFeatureDepth
+	ErrInvalidFeatureParent = errors.New("invalid feature parent")
+
+	// ErrFeatureInUse is returned when deleting a feature that still has children, tokens, quotas or workspaces
+	ErrFeatureInUse = errors.New("feature is in use")
 )
 
 // FeatureRegistry manages API features with live database queries
@@ -14,46 +32,61 @@ func NewFeatureRegistry(repo *Repository) *FeatureRegistry {
 	return &FeatureRegistry{repo: repo}
 }
 
-// GetFeatureBySlug returns a feature by its slug with a live database query
-func (r *FeatureRegistry) GetFeatureBySlug(slug string) (*Feature, error) {
+// featureColumns lists the features columns read by scanFeature, aliased as f
+const featureColumns = `f.id, f.slug, f.name, f.parent_id, f.admin_only, f.approval_required, f.latest_version, f.dedup_window_ms, f.deprecated, f.sunset_at,
+	f.description, f.docs_url, f.example, f.created_at`
+
+func scanFeature(row rowScanner) (*Feature, error) {
 	var f Feature
 	var parentID sql.NullInt64
-	err := r.repo.db.QueryRow(`
-		SELECT id, slug, name, parent_id, admin_only, created_at
-		FROM features WHERE slug = ?
-	`, slug).Scan(&f.ID, &f.Slug, &f.Name, &parentID, &f.AdminOnly, &f.CreatedAt)
+	var sunsetAt sql.NullTime
+	var description, docsURL, example sql.NullString
+	if err := row.Scan(&f.ID, &f.Slug, &f.Name, &parentID, &f.AdminOnly, &f.ApprovalRequired, &f.LatestVersion, &f.DedupWindowMs, &f.Deprecated, &sunsetAt,
+		&description, &docsURL, &example, &f.CreatedAt); err != nil {
+		return nil, err
+	}
+	f.ParentID = ScanNullableInt64(parentID)
+	f.SunsetAt = ScanNullableTime(sunsetAt)
+	f.Description = ScanNullableString(description)
+	f.DocsURL = ScanNullableString(docsURL)
+	f.Example = ScanNullableString(example)
+	return &f, nil
+}
+
+// GetFeatureBySlug returns a feature by its slug with a live database query
+func (r *FeatureRegistry) GetFeatureBySlug(ctx context.Context, slug string) (*Feature, error) {
+	f, err := scanFeature(r.repo.db.QueryRowContext(ctx, `
+		SELECT `+featureColumns+`
+		FROM features f WHERE f.slug = ?
+	`, slug))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	f.ParentID = ScanNullableInt64(parentID)
-	return &f, nil
+	return f, nil
 }
 
 // GetFeatureByID returns a feature by its ID
-func (r *FeatureRegistry) GetFeatureByID(id int64) (*Feature, error) {
-	var f Feature
-	var parentID sql.NullInt64
-	err := r.repo.db.QueryRow(`
-		SELECT id, slug, name, parent_id, admin_only, created_at
-		FROM features WHERE id = ?
-	`, id).Scan(&f.ID, &f.Slug, &f.Name, &parentID, &f.AdminOnly, &f.CreatedAt)
+func (r *FeatureRegistry) GetFeatureByID(ctx context.Context, id int64) (*Feature, error) {
+	f, err := scanFeature(r.repo.db.QueryRowContext(ctx, `
+		SELECT `+featureColumns+`
+		FROM features f WHERE f.id = ?
+	`, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	f.ParentID = ScanNullableInt64(parentID)
-	return &f, nil
+	return f, nil
 }
 
 // IsFeatureAdminOnly checks if a feature is admin-only (live query)
-func (r *FeatureRegistry) IsFeatureAdminOnly(featureID int64) (bool, error) {
+func (r *FeatureRegistry) IsFeatureAdminOnly(ctx context.Context, featureID int64) (bool, error) {
 	var adminOnly bool
-	err := r.repo.db.QueryRow(`
+	err := r.repo.db.QueryRowContext(ctx, `
 		SELECT admin_only FROM features WHERE id = ?
 	`, featureID).Scan(&adminOnly)
 	if err != nil {
@@ -63,9 +96,9 @@ func (r *FeatureRegistry) IsFeatureAdminOnly(featureID int64) (bool, error) {
 }
 
 // IsFeatureSlugAdminOnly checks if a feature slug is admin-only (live query)
-func (r *FeatureRegistry) IsFeatureSlugAdminOnly(slug string) (bool, error) {
+func (r *FeatureRegistry) IsFeatureSlugAdminOnly(ctx context.Context, slug string) (bool, error) {
 	var adminOnly bool
-	err := r.repo.db.QueryRow(`
+	err := r.repo.db.QueryRowContext(ctx, `
 		SELECT admin_only FROM features WHERE slug = ?
 	`, slug).Scan(&adminOnly)
 	if err != nil {
@@ -75,10 +108,10 @@ func (r *FeatureRegistry) IsFeatureSlugAdminOnly(slug string) (bool, error) {
 }
 
 // GetAllFeatures returns all features (for admins)
-func (r *FeatureRegistry) GetAllFeatures() ([]Feature, error) {
-	rows, err := r.repo.db.Query(`
-		SELECT id, slug, name, parent_id, admin_only, created_at
-		FROM features ORDER BY slug
+func (r *FeatureRegistry) GetAllFeatures(ctx context.Context) ([]Feature, error) {
+	rows, err := r.repo.db.QueryContext(ctx, `
+		SELECT `+featureColumns+`
+		FROM features f ORDER BY f.slug
 	`)
 	if err != nil {
 		return nil, err
@@ -87,22 +120,20 @@ func (r *FeatureRegistry) GetAllFeatures() ([]Feature, error) {
 
 	var features []Feature
 	for rows.Next() {
-		var f Feature
-		var parentID sql.NullInt64
-		if err := rows.Scan(&f.ID, &f.Slug, &f.Name, &parentID, &f.AdminOnly, &f.CreatedAt); err != nil {
+		f, err := scanFeature(rows)
+		if err != nil {
 			return nil, err
 		}
-		f.ParentID = ScanNullableInt64(parentID)
-		features = append(features, f)
+		features = append(features, *f)
 	}
 	return features, rows.Err()
 }
 
 // GetUserAssignableFeatures returns features that users can assign to their tokens
-func (r *FeatureRegistry) GetUserAssignableFeatures() ([]Feature, error) {
-	rows, err := r.repo.db.Query(`
-		SELECT id, slug, name, parent_id, admin_only, created_at
-		FROM features WHERE admin_only = 0 ORDER BY slug
+func (r *FeatureRegistry) GetUserAssignableFeatures(ctx context.Context) ([]Feature, error) {
+	rows, err := r.repo.db.QueryContext(ctx, `
+		SELECT `+featureColumns+`
+		FROM features f WHERE f.admin_only = 0 ORDER BY f.slug
 	`)
 	if err != nil {
 		return nil, err
@@ -111,25 +142,66 @@ func (r *FeatureRegistry) GetUserAssignableFeatures() ([]Feature, error) {
 
 	var features []Feature
 	for rows.Next() {
-		var f Feature
-		var parentID sql.NullInt64
-		if err := rows.Scan(&f.ID, &f.Slug, &f.Name, &parentID, &f.AdminOnly, &f.CreatedAt); err != nil {
+		f, err := scanFeature(rows)
+		if err != nil {
 			return nil, err
 		}
-		f.ParentID = ScanNullableInt64(parentID)
-		features = append(features, f)
+		features = append(features, *f)
 	}
 	return features, rows.Err()
 }
 
+// GetFeatureTree returns all features nested under their parents (for admins)
+func (r *FeatureRegistry) GetFeatureTree(ctx context.Context) ([]*Feature, error) {
+	features, err := r.GetAllFeatures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return BuildFeatureTree(features), nil
+}
+
+// GetUserAssignableFeatureTree returns the features users can assign to their
+// tokens nested under their parents
+func (r *FeatureRegistry) GetUserAssignableFeatureTree(ctx context.Context) ([]*Feature, error) {
+	features, err := r.GetUserAssignableFeatures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return BuildFeatureTree(features), nil
+}
+
+// BuildFeatureTree nests a flat feature list by ParentID, keeping the list's
+// order among siblings. Features whose parent is not in the list (e.g. an
+// admin-only parent filtered out) become roots.
+func BuildFeatureTree(features []Feature) []*Feature {
+	nodes := make(map[int64]*Feature, len(features))
+	for i := range features {
+		features[i].Children = nil
+		nodes[features[i].ID] = &features[i]
+	}
+
+	var roots []*Feature
+	for i := range features {
+		f := &features[i]
+		if f.ParentID != nil {
+			if parent, ok := nodes[*f.ParentID]; ok && parent != f {
+				parent.Children = append(parent.Children, f)
+				continue
+			}
+		}
+		roots = append(roots, f)
+	}
+	return roots
+}
+
 // GetFeaturesByIDs returns features by their IDs
-func (r *FeatureRegistry) GetFeaturesByIDs(ids []int64) ([]Feature, error) {
+func (r *FeatureRegistry) GetFeaturesByIDs(ctx context.Context, ids []int64) ([]Feature, error) {
 	if len(ids) == 0 {
 		return []Feature{}, nil
 	}
 
 	// Build query with placeholders
-	query := "SELECT id, slug, name, parent_id, admin_only, created_at FROM features WHERE id IN ("
+	query := "SELECT " + featureColumns + " FROM features f WHERE f.id IN ("
 	args := make([]interface{}, len(ids))
 	for i, id := range ids {
 		if i > 0 {
@@ -138,9 +210,9 @@ func (r *FeatureRegistry) GetFeaturesByIDs(ids []int64) ([]Feature, error) {
 		query += "?"
 		args[i] = id
 	}
-	query += ") ORDER BY slug"
+	query += ") ORDER BY f.slug"
 
-	rows, err := r.repo.db.Query(query, args...)
+	rows, err := r.repo.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -148,24 +220,22 @@ func (r *FeatureRegistry) GetFeaturesByIDs(ids []int64) ([]Feature, error) {
 
 	var features []Feature
 	for rows.Next() {
-		var f Feature
-		var parentID sql.NullInt64
-		if err := rows.Scan(&f.ID, &f.Slug, &f.Name, &parentID, &f.AdminOnly, &f.CreatedAt); err != nil {
+		f, err := scanFeature(rows)
+		if err != nil {
 			return nil, err
 		}
-		f.ParentID = ScanNullableInt64(parentID)
-		features = append(features, f)
+		features = append(features, *f)
 	}
 	return features, rows.Err()
 }
 
 // GetFeaturesBySlugs returns features by their slugs
-func (r *FeatureRegistry) GetFeaturesBySlugs(slugs []string) ([]Feature, error) {
+func (r *FeatureRegistry) GetFeaturesBySlugs(ctx context.Context, slugs []string) ([]Feature, error) {
 	if len(slugs) == 0 {
 		return []Feature{}, nil
 	}
 
-	query := "SELECT id, slug, name, parent_id, admin_only, created_at FROM features WHERE slug IN ("
+	query := "SELECT " + featureColumns + " FROM features f WHERE f.slug IN ("
 	args := make([]interface{}, len(slugs))
 	for i, slug := range slugs {
 		if i > 0 {
@@ -174,9 +244,9 @@ func (r *FeatureRegistry) GetFeaturesBySlugs(slugs []string) ([]Feature, error)
 		query += "?"
 		args[i] = slug
 	}
-	query += ") ORDER BY slug"
+	query += ") ORDER BY f.slug"
 
-	rows, err := r.repo.db.Query(query, args...)
+	rows, err := r.repo.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -184,24 +254,26 @@ func (r *FeatureRegistry) GetFeaturesBySlugs(slugs []string) ([]Feature, error)
 
 	var features []Feature
 	for rows.Next() {
-		var f Feature
-		var parentID sql.NullInt64
-		if err := rows.Scan(&f.ID, &f.Slug, &f.Name, &parentID, &f.AdminOnly, &f.CreatedAt); err != nil {
+		f, err := scanFeature(rows)
+		if err != nil {
 			return nil, err
 		}
-		f.ParentID = ScanNullableInt64(parentID)
-		features = append(features, f)
+		features = append(features, *f)
 	}
 	return features, rows.Err()
 }
 
-// GetFeatureAncestors returns a feature and all its ancestors (for quota inheritance)
-func (r *FeatureRegistry) GetFeatureAncestors(featureID int64) ([]Feature, error) {
+// GetFeatureAncestors returns a feature and all its ancestors (for quota inheritance).
+// The walk stops at MaxFeatureDepth and on repeated IDs, so a cycle that made it
+// into the database cannot hang quota checks.
+func (r *FeatureRegistry) GetFeatureAncestors(ctx context.Context, featureID int64) ([]Feature, error) {
 	var ancestors []Feature
+	seen := make(map[int64]bool)
 
 	currentID := &featureID
-	for currentID != nil {
-		feature, err := r.GetFeatureByID(*currentID)
+	for currentID != nil && !seen[*currentID] && len(ancestors) < MaxFeatureDepth {
+		seen[*currentID] = true
+		feature, err := r.GetFeatureByID(ctx, *currentID)
 		if err != nil {
 			return nil, err
 		}
@@ -215,46 +287,305 @@ func (r *FeatureRegistry) GetFeatureAncestors(featureID int64) ([]Feature, error
 	return ancestors, nil
 }
 
+// getSubtreeHeight returns the number of levels from a feature down to its deepest descendant (1 for a leaf)
+func (r *FeatureRegistry) getSubtreeHeight(ctx context.Context, featureID int64) (int, error) {
+	var height int
+	err := r.repo.db.QueryRowContext(ctx, `
+		WITH RECURSIVE subtree(id, level) AS (
+			SELECT id, 1 FROM features WHERE id = ?
+			UNION ALL
+			SELECT f.id, s.level + 1 FROM features f JOIN subtree s ON f.parent_id = s.id
+			WHERE s.level < ?
+		)
+		SELECT MAX(level) FROM subtree
+	`, featureID, MaxFeatureDepth+1).Scan(&height)
+	return height, err
+}
+
+// validateParent checks that parentID can become the parent of featureID (0 for
+// a new feature): the parent must exist, must not be the feature or one of its
+// descendants, and the resulting tree must not be deeper than MaxFeatureDepth
+func (r *FeatureRegistry) validateParent(ctx context.Context, featureID, parentID int64) error {
+	ancestors, err := r.GetFeatureAncestors(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	if len(ancestors) == 0 {
+		return fmt.Errorf("%w: parent feature %d not found", ErrInvalidFeatureParent, parentID)
+	}
+	for _, a := range ancestors {
+		if a.ID == featureID {
+			return fmt.Errorf("%w: feature %d cannot be nested under itself or its descendant '%s'", ErrInvalidFeatureParent, featureID, ancestors[0].Slug)
+		}
+	}
+
+	height := 1
+	if featureID != 0 {
+		if height, err = r.getSubtreeHeight(ctx, featureID); err != nil {
+			return err
+		}
+	}
+	if depth := len(ancestors) + height; depth > MaxFeatureDepth {
+		return fmt.Errorf("%w: the hierarchy would be %d levels deep, the maximum is %d", ErrInvalidFeatureParent, depth, MaxFeatureDepth)
+	}
+	return nil
+}
+
 // CreateFeature creates a new feature
-func (r *FeatureRegistry) CreateFeature(slug, name string, parentID *int64, adminOnly bool) (*Feature, error) {
-	result, err := r.repo.db.Exec(`
+func (r *FeatureRegistry) CreateFeature(ctx context.Context, slug, name string, parentID *int64, adminOnly bool) (*Feature, error) {
+	if parentID != nil {
+		if err := r.validateParent(ctx, 0, *parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := r.repo.db.ExecContext(ctx, `
 		INSERT INTO features (slug, name, parent_id, admin_only) VALUES (?, ?, ?, ?)
 	`, slug, name, parentID, adminOnly)
 	if err != nil {
 		return nil, err
 	}
 	id, _ := result.LastInsertId()
-	return r.GetFeatureByID(id)
+	return r.GetFeatureByID(ctx, id)
 }
 
 // UpdateFeature updates a feature
-func (r *FeatureRegistry) UpdateFeature(id int64, name *string, parentID *int64, adminOnly *bool) error {
+func (r *FeatureRegistry) UpdateFeature(ctx context.Context, id int64, name *string, parentID *int64, adminOnly *bool) error {
 	if name != nil {
-		if _, err := r.repo.db.Exec("UPDATE features SET name = ? WHERE id = ?", *name, id); err != nil {
+		if _, err := r.repo.db.ExecContext(ctx, "UPDATE features SET name = ? WHERE id = ?", *name, id); err != nil {
 			return err
 		}
 	}
 	if parentID != nil {
-		if _, err := r.repo.db.Exec("UPDATE features SET parent_id = ? WHERE id = ?", *parentID, id); err != nil {
+		if err := r.validateParent(ctx, id, *parentID); err != nil {
+			return err
+		}
+		if _, err := r.repo.db.ExecContext(ctx, "UPDATE features SET parent_id = ? WHERE id = ?", *parentID, id); err != nil {
 			return err
 		}
 	}
 	if adminOnly != nil {
-		if _, err := r.repo.db.Exec("UPDATE features SET admin_only = ? WHERE id = ?", *adminOnly, id); err != nil {
+		if _, err := r.repo.db.ExecContext(ctx, "UPDATE features SET admin_only = ? WHERE id = ?", *adminOnly, id); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// DeleteFeature deletes a feature
-func (r *FeatureRegistry) DeleteFeature(id int64) error {
-	_, err := r.repo.db.Exec("DELETE FROM features WHERE id = ?", id)
+// FeatureDefinition declares a feature a module serves, so its row exists
+// before any route asks for it
+type FeatureDefinition struct {
+	Slug        string
+	Name        string
+	Parent      string // Slug of the parent, declared earlier in the list or already registered
+	AdminOnly   bool
+	Description string
+	Versions    int // Number of response formats served; 0 means 1
+	// RPM is the quota every existing group gets on the feature when it is created, for
+	// features that spend another service's capacity; 0 leaves the groups' default RPM.
+	// Groups created later use their default RPM until an admin sets one.
+	RPM int
+}
+
+// SyncFeatures creates the declared features that do not exist yet, parents
+// first, and raises their latest version to what the module serves. Other
+// settings of existing features, quotas included, are left as admins configured them.
+func (r *FeatureRegistry) SyncFeatures(ctx context.Context, defs []FeatureDefinition) error {
+	for _, def := range defs {
+		existing, err := r.GetFeatureBySlug(ctx, def.Slug)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			if def.Versions > existing.LatestVersion {
+				if err := r.setLatestVersion(ctx, existing.ID, def.Versions); err != nil {
+					return err
+				}
+				log.Printf("Feature %s now serves up to v%d", def.Slug, def.Versions)
+			}
+			continue
+		}
+
+		var parentID *int64
+		if def.Parent != "" {
+			parent, err := r.GetFeatureBySlug(ctx, def.Parent)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				return fmt.Errorf("Feature '%s' declares unknown parent '%s'", def.Slug, def.Parent)
+			}
+			parentID = &parent.ID
+		}
+
+		feature, err := r.CreateFeature(ctx, def.Slug, def.Name, parentID, def.AdminOnly)
+		if err != nil {
+			return fmt.Errorf("Failed to register feature '%s': %w", def.Slug, err)
+		}
+		if def.Description != "" {
+			if err := r.SetFeatureDocs(ctx, feature.ID, FeatureDocs{Description: &def.Description}); err != nil {
+				return err
+			}
+		}
+		if def.Versions > 1 {
+			if err := r.setLatestVersion(ctx, feature.ID, def.Versions); err != nil {
+				return err
+			}
+		}
+		if def.RPM > 0 {
+			if _, err := r.repo.db.ExecContext(ctx, `
+				INSERT INTO group_feature_quotas (group_id, feature_id, rpm_limit)
+				SELECT id, ?, ? FROM groups
+			`, feature.ID, def.RPM); err != nil {
+				return fmt.Errorf("Failed to set the quotas of feature '%s': %w", def.Slug, err)
+			}
+		}
+		log.Printf("Registered feature %s", def.Slug)
+	}
+	return nil
+}
+
+func (r *FeatureRegistry) setLatestVersion(ctx context.Context, id int64, version int) error {
+	_, err := r.repo.db.ExecContext(ctx, "UPDATE features SET latest_version = ? WHERE id = ?", version, id)
+	return err
+}
+
+// ParseFeatureRef splits a feature reference such as "schedule@v2" into its
+// slug and version. Version 0 means the reference does not pin one.
+func ParseFeatureRef(ref string) (slug string, version int, err error) {
+	slug, v, pinned := strings.Cut(ref, "@")
+	if !pinned {
+		return ref, 0, nil
+	}
+	version, err = strconv.Atoi(strings.TrimPrefix(v, "v"))
+	if err != nil || !strings.HasPrefix(v, "v") || version < 1 {
+		return "", 0, fmt.Errorf("Invalid feature version in '%s', expected slug@vN", ref)
+	}
+	return slug, version, nil
+}
+
+// ResolveFeatureRefs looks up the features of references like "schedule" or
+// "schedule@v2", returning them with the versions pinned by feature ID.
+// Unknown slugs are left out so callers can report them.
+func (r *FeatureRegistry) ResolveFeatureRefs(ctx context.Context, refs []string) ([]Feature, map[int64]int, error) {
+	slugs := make([]string, 0, len(refs))
+	pinned := make(map[string]int)
+	for _, ref := range refs {
+		slug, version, err := ParseFeatureRef(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, dup := pinned[slug]; dup {
+			return nil, nil, fmt.Errorf("Feature '%s' is listed more than once", slug)
+		}
+		pinned[slug] = version
+		slugs = append(slugs, slug)
+	}
+
+	features, err := r.GetFeaturesBySlugs(ctx, slugs)
+	if err != nil {
+		return nil, nil, err
+	}
+	versions := make(map[int64]int)
+	for _, f := range features {
+		version := pinned[f.Slug]
+		if version == 0 {
+			continue
+		}
+		if version > f.LatestVersion {
+			return nil, nil, fmt.Errorf("Feature '%s' has no v%d (latest is v%d)", f.Slug, version, f.LatestVersion)
+		}
+		versions[f.ID] = version
+	}
+	return features, versions, nil
+}
+
+// SetApprovalRequired sets whether users need an approved access request
+// before they can use a feature
+func (r *FeatureRegistry) SetApprovalRequired(ctx context.Context, id int64, required bool) error {
+	_, err := r.repo.db.ExecContext(ctx, "UPDATE features SET approval_required = ? WHERE id = ?", required, id)
+	return err
+}
+
+// SetDedupWindow sets how long identical GET requests are coalesced for a feature
+func (r *FeatureRegistry) SetDedupWindow(ctx context.Context, id int64, windowMs int) error {
+	_, err := r.repo.db.ExecContext(ctx, "UPDATE features SET dedup_window_ms = ? WHERE id = ?", windowMs, id)
+	return err
+}
+
+// SetFeatureDocs updates the catalog documentation of a feature. Nil fields
+// are left unchanged and empty strings clear them.
+func (r *FeatureRegistry) SetFeatureDocs(ctx context.Context, id int64, docs FeatureDocs) error {
+	_, err := r.repo.db.ExecContext(ctx, `
+		UPDATE features
+		SET description = COALESCE(NULLIF(?, ''), CASE WHEN ? IS NULL THEN description END),
+		    docs_url = COALESCE(NULLIF(?, ''), CASE WHEN ? IS NULL THEN docs_url END),
+		    example = COALESCE(NULLIF(?, ''), CASE WHEN ? IS NULL THEN example END)
+		WHERE id = ?
+	`, docs.Description, docs.Description, docs.DocsURL, docs.DocsURL, docs.Example, docs.Example, id)
+	return err
+}
+
+// SetDeprecation marks a feature as deprecated, optionally with the date it
+// will be retired. Omitting sunsetAt keeps the current date; un-deprecating a
+// feature clears it.
+func (r *FeatureRegistry) SetDeprecation(ctx context.Context, id int64, deprecated bool, sunsetAt *time.Time) error {
+	if sunsetAt != nil {
+		utc := sunsetAt.UTC()
+		sunsetAt = &utc
+	}
+	_, err := r.repo.db.ExecContext(ctx, `
+		UPDATE features
+		SET deprecated = ?,
+		    sunset_at = CASE WHEN ? THEN COALESCE(?, sunset_at) ELSE NULL END
+		WHERE id = ?
+	`, deprecated, deprecated, sunsetAt, id)
+	return err
+}
+
+// DeleteFeature deletes a feature. Features that still have children, active
+// tokens, quotas or workspaces are refused with ErrFeatureInUse, since the
+// cascade would silently change what those tokens can reach and how they are limited.
+func (r *FeatureRegistry) DeleteFeature(ctx context.Context, id int64) error {
+	var children, tokens, groupQuotas, userQuotas, workspaces int
+	err := r.repo.db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM features WHERE parent_id = ?),
+			(SELECT COUNT(*) FROM token_features tf JOIN tokens t ON t.id = tf.token_id
+			 WHERE tf.feature_id = ? AND t.revoked_at IS NULL AND (t.expires_at IS NULL OR t.expires_at > ?)),
+			(SELECT COUNT(*) FROM group_feature_quotas WHERE feature_id = ?),
+			(SELECT COUNT(*) FROM user_quota_overrides WHERE feature_id = ?),
+			(SELECT COUNT(*) FROM course_workspace_features WHERE feature_id = ?)
+	`, id, id, time.Now(), id, id, id).Scan(&children, &tokens, &groupQuotas, &userQuotas, &workspaces)
+	if err != nil {
+		return err
+	}
+
+	var uses []string
+	for _, use := range []struct {
+		count int
+		what  string
+	}{
+		{children, "child features"},
+		{tokens, "active tokens"},
+		{groupQuotas, "group quotas"},
+		{userQuotas, "user quota overrides"},
+		{workspaces, "course workspaces"},
+	} {
+		if use.count > 0 {
+			uses = append(uses, fmt.Sprintf("%d %s", use.count, use.what))
+		}
+	}
+	if len(uses) > 0 {
+		return fmt.Errorf("%w: %s", ErrFeatureInUse, strings.Join(uses, ", "))
+	}
+
+	_, err = r.repo.db.ExecContext(ctx, "DELETE FROM features WHERE id = ?", id)
 	return err
 }
 
 // HasAdminOnlyFeatures checks if any of the given feature IDs are admin-only
-func (r *FeatureRegistry) HasAdminOnlyFeatures(featureIDs []int64) (bool, error) {
+func (r *FeatureRegistry) HasAdminOnlyFeatures(ctx context.Context, featureIDs []int64) (bool, error) {
 	if len(featureIDs) == 0 {
 		return false, nil
 	}
@@ -271,7 +602,7 @@ func (r *FeatureRegistry) HasAdminOnlyFeatures(featureIDs []int64) (bool, error)
 	query += ")"
 
 	var count int
-	err := r.repo.db.QueryRow(query, args...).Scan(&count)
+	err := r.repo.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return false, err
 	}
@@ -280,9 +611,9 @@ func (r *FeatureRegistry) HasAdminOnlyFeatures(featureIDs []int64) (bool, error)
 
 // TokenHasFeatureAccess checks if a token has access to a feature
 // This includes checking both direct feature assignment and parent features
-func (r *FeatureRegistry) TokenHasFeatureAccess(tokenFeatureIDs []int64, targetFeatureSlug string) (bool, error) {
+func (r *FeatureRegistry) TokenHasFeatureAccess(ctx context.Context, tokenFeatureIDs []int64, targetFeatureSlug string) (bool, error) {
 	// Get the target feature
-	targetFeature, err := r.GetFeatureBySlug(targetFeatureSlug)
+	targetFeature, err := r.GetFeatureBySlug(ctx, targetFeatureSlug)
 	if err != nil || targetFeature == nil {
 		return false, err
 	}
@@ -296,7 +627,7 @@ func (r *FeatureRegistry) TokenHasFeatureAccess(tokenFeatureIDs []int64, targetF
 
 	// Check if the token has access to any ancestor of this feature
 	// (having access to "maps" grants access to "maps.tiles")
-	ancestors, err := r.GetFeatureAncestors(targetFeature.ID)
+	ancestors, err := r.GetFeatureAncestors(ctx, targetFeature.ID)
 	if err != nil {
 		return false, err
 	}
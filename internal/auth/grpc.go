@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type rpcContextKey int
+
+const (
+	rpcContextUser rpcContextKey = iota
+	rpcContextToken
+	rpcContextFeatureVersion
+)
+
+// rpcCodes maps the status codes RequireToken denies with to gRPC codes
+var rpcCodes = map[int]codes.Code{
+	http.StatusUnauthorized:    codes.Unauthenticated,
+	http.StatusForbidden:       codes.PermissionDenied,
+	http.StatusTooManyRequests: codes.ResourceExhausted,
+}
+
+// RequireTokenRPC declares the feature a gRPC method requires, like
+// RequireToken does for a route. Methods without one are refused.
+func (m *Middleware) RequireTokenRPC(fullMethod, featureSlug string) {
+	m.routeFeatures = append(m.routeFeatures, featureSlug)
+	m.rpcFeatures[fullMethod] = featureSlug
+}
+
+// UnaryInterceptor authenticates gRPC calls with the same token, feature,
+// approval, IP and quota checks RequireToken applies to HTTP requests. The
+// bearer token is read from the "authorization" metadata.
+func (m *Middleware) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		featureSlug, ok := m.rpcFeatures[info.FullMethod]
+		if !ok {
+			return nil, status.Errorf(codes.PermissionDenied, "Method %s does not declare a feature", info.FullMethod)
+		}
+		ctx, err := m.authorizeRPC(ctx, info.FullMethod, featureSlug)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (m *Middleware) authorizeRPC(ctx context.Context, fullMethod, featureSlug string) (context.Context, error) {
+	requestID := uuid.New().String()
+	header := metadata.Pairs(strings.ToLower(HeaderRequestID), requestID)
+	defer func() { _ = grpc.SetHeader(ctx, header) }()
+
+	clientIP := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		clientIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(clientIP); err == nil {
+			clientIP = host
+		}
+	}
+	diag := &DenialDiagnostic{
+		RequestID:   requestID,
+		Method:      "GRPC",
+		Path:        fullMethod,
+		ClientIP:    clientIP,
+		FeatureSlug: featureSlug,
+	}
+
+	// Denials are recorded so support can explain them from the request ID
+	fail := func(d *denial) error {
+		reason, _ := d.body["error"].(string)
+		if d.failed {
+			return status.Error(codes.Internal, reason)
+		}
+		diag.Timestamp = time.Now()
+		diag.Status = d.status
+		diag.Reason = reason
+		m.diagnostics.Record(diag)
+		code, ok := rpcCodes[d.status]
+		if !ok {
+			code = codes.PermissionDenied
+		}
+		return status.Error(code, reason)
+	}
+	setHeader := func(key, value string) { header.Set(key, value) }
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization := ""
+	if values := md.Get(HeaderAuthorization); len(values) > 0 {
+		authorization = values[0]
+	}
+	validated, d := m.authenticate(ctx, authorization, diag, setHeader)
+	if d != nil {
+		return nil, fail(d)
+	}
+	feature, d := m.authorize(ctx, validated, featureSlug, diag, setHeader)
+	if d != nil {
+		return nil, fail(d)
+	}
+	if d := m.checkQuota(ctx, validated, feature, diag, setHeader); d != nil {
+		return nil, fail(d)
+	}
+
+	// There is no gin context for gRPC calls
+	hc := &HookContext{FeatureSlug: featureSlug}
+	version, d := m.admit(hc, validated, feature, diag, setHeader)
+	if d != nil {
+		return nil, fail(d)
+	}
+
+	ctx = context.WithValue(ctx, rpcContextUser, validated.User)
+	ctx = context.WithValue(ctx, rpcContextToken, validated.Token)
+	ctx = context.WithValue(ctx, rpcContextFeatureVersion, version)
+	return ctx, nil
+}
+
+// GetUserFromRPCContext retrieves the user authenticated by UnaryInterceptor
+func GetUserFromRPCContext(ctx context.Context) *User {
+	user, _ := ctx.Value(rpcContextUser).(*User)
+	return user
+}
+
+// GetTokenFromRPCContext retrieves the token authenticated by UnaryInterceptor
+func GetTokenFromRPCContext(ctx context.Context) *Token {
+	token, _ := ctx.Value(rpcContextToken).(*Token)
+	return token
+}
+
+// FeatureVersionFromRPCContext returns the feature version resolved for a
+// gRPC call, like FeatureVersionFromContext does for HTTP requests
+func FeatureVersionFromRPCContext(ctx context.Context) int {
+	if version, ok := ctx.Value(rpcContextFeatureVersion).(int); ok && version > 0 {
+		return version
+	}
+	return 1
+}
@@ -2,10 +2,16 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
+	"API/internal/apierror"
 	"API/internal/common"
+	"API/internal/events"
+	"API/internal/pagination"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,6 +28,11 @@ type Handler struct {
 	sessionStore *SessionStore
 	tokenStore   *TokenStore
 	features     *FeatureRegistry
+	quota        *QuotaEngine
+	events       *events.Bus
+
+	// Emails promoted to admin on login while the deployment has no admin
+	bootstrapEmails []string
 }
 
 // NewHandler creates a new auth handler
@@ -32,14 +43,20 @@ func NewHandler(
 	sessionStore *SessionStore,
 	tokenStore *TokenStore,
 	features *FeatureRegistry,
+	quota *QuotaEngine,
+	bus *events.Bus,
+	bootstrapEmails []string,
 ) *Handler {
 	return &Handler{
-		repo:         repo,
-		oauthConfig:  oauthConfig,
-		stateStore:   stateStore,
-		sessionStore: sessionStore,
-		tokenStore:   tokenStore,
-		features:     features,
+		repo:            repo,
+		oauthConfig:     oauthConfig,
+		stateStore:      stateStore,
+		sessionStore:    sessionStore,
+		tokenStore:      tokenStore,
+		features:        features,
+		quota:           quota,
+		events:          bus,
+		bootstrapEmails: bootstrapEmails,
 	}
 }
 
@@ -51,20 +68,20 @@ func (h *Handler) Login(c *gin.Context) {
 
 	// Validate provider
 	if provider != ProviderGoogle && provider != ProviderGitHub {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"unsupported provider"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "unsupported provider")))
 		return
 	}
 
 	// Check if provider is configured
 	if !h.oauthConfig.IsProviderConfigured(provider) {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"provider not configured"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "provider not configured")))
 		return
 	}
 
 	// Generate state for CSRF protection
-	state, err := h.stateStore.CreateState()
+	state, err := h.stateStore.CreateState(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to create auth state"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create auth state")))
 		return
 	}
 
@@ -83,7 +100,7 @@ func (h *Handler) Login(c *gin.Context) {
 	// Get authorization URL
 	authURL, err := h.oauthConfig.GetAuthURL(provider, state)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to create auth URL"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create auth URL")))
 		return
 	}
 
@@ -99,7 +116,7 @@ func (h *Handler) Callback(c *gin.Context) {
 
 	// Validate provider
 	if provider != ProviderGoogle && provider != ProviderGitHub {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"unsupported provider"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "unsupported provider")))
 		return
 	}
 
@@ -107,20 +124,20 @@ func (h *Handler) Callback(c *gin.Context) {
 	queryState := c.Query("state")
 	cookieState, err := c.Cookie(OAuthStateCookieName)
 	if err != nil || cookieState == "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"missing OAuth state cookie"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "missing OAuth state cookie")))
 		return
 	}
 
 	// Verify states match
 	if queryState != cookieState {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"OAuth state mismatch"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "OAuth state mismatch")))
 		return
 	}
 
 	// Validate state against database
-	valid, err := h.stateStore.ValidateState(queryState)
+	valid, err := h.stateStore.ValidateState(c.Request.Context(), queryState)
 	if err != nil || !valid {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"invalid or expired OAuth state"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "invalid or expired OAuth state")))
 		return
 	}
 
@@ -129,14 +146,14 @@ func (h *Handler) Callback(c *gin.Context) {
 
 	// Check for OAuth error
 	if errMsg := c.Query("error"); errMsg != "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"OAuth error: " + errMsg}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "OAuth error: "+errMsg)))
 		return
 	}
 
 	// Get authorization code
 	code := c.Query("code")
 	if code == "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"missing authorization code"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "missing authorization code")))
 		return
 	}
 
@@ -144,34 +161,45 @@ func (h *Handler) Callback(c *gin.Context) {
 	ctx := context.Background()
 	token, err := h.oauthConfig.ExchangeCode(ctx, provider, code)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to exchange code"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to exchange code")))
 		return
 	}
 
 	// Get user info from provider
 	userInfo, err := h.oauthConfig.GetUserInfo(ctx, provider, token)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to get user info"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get user info")))
 		return
 	}
 
 	// Find or create user
-	user, err := h.findOrCreateUser(userInfo, provider, token.AccessToken, token.RefreshToken)
+	user, err := h.findOrCreateUser(ctx, userInfo, provider, token.AccessToken, token.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to create user"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create user")))
 		return
 	}
 
 	// Check user status
-	if user.Status != StatusActive {
-		c.JSON(http.StatusForbidden, common.CreateErrorResponse([]string{"account is " + string(user.Status)}))
+	if !user.IsActive() {
+		common.JSON(c, http.StatusForbidden, common.CreateErrorResponse(apierror.New(apierror.Forbidden, user.SuspensionMessage())))
+		return
+	}
+
+	// Promote a bootstrap email to admin on a fresh deployment
+	user, err = h.bootstrapAdmin(ctx, user)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to bootstrap admin")))
 		return
 	}
 
 	// Create session
-	session, err := h.sessionStore.CreateSession(user.ID)
+	session, err := h.sessionStore.CreateSession(ctx, user.ID)
+	if errors.Is(err, ErrSessionLimitReached) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to create session"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create session")))
 		return
 	}
 
@@ -179,7 +207,7 @@ func (h *Handler) Callback(c *gin.Context) {
 	h.sessionStore.SetSessionCookie(c, session.ID)
 
 	// Return success (or redirect to frontend)
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"message": "authenticated successfully",
 		"user": gin.H{
 			"id":          user.ID,
@@ -190,64 +218,106 @@ func (h *Handler) Callback(c *gin.Context) {
 	}))
 }
 
-func (h *Handler) findOrCreateUser(info *OAuthUserInfo, provider Provider, accessToken, refreshToken string) (*User, error) {
+func (h *Handler) findOrCreateUser(ctx context.Context, info *OAuthUserInfo, provider Provider, accessToken, refreshToken string) (*User, error) {
 	// Check if OAuth identity exists
-	identity, err := h.repo.GetOAuthIdentity(provider, info.ProviderID)
+	identity, err := h.repo.GetOAuthIdentity(ctx, provider, info.ProviderID)
 	if err != nil {
 		return nil, err
 	}
 
 	if identity != nil {
 		// Update tokens
-		err := h.repo.UpdateOAuthIdentityTokens(identity.ID, accessToken, refreshToken)
+		err := h.repo.UpdateOAuthIdentityTokens(ctx, identity.ID, accessToken, refreshToken)
 		if err != nil {
 			return nil, err
 		}
-		return h.repo.GetUserByID(identity.UserID)
+		return h.repo.GetUserByID(ctx, identity.UserID)
 	}
 
 	// Check if user exists by email
-	user, err := h.repo.GetUserByEmail(info.Email)
+	user, err := h.repo.GetUserByEmail(ctx, info.Email)
 	if err != nil {
 		return nil, err
 	}
 
 	if user != nil {
 		// Link new OAuth identity to existing user
-		_, err = h.repo.CreateOAuthIdentity(user.ID, provider, info.ProviderID, accessToken, refreshToken)
+		_, err = h.repo.CreateOAuthIdentity(ctx, user.ID, provider, info.ProviderID, accessToken, refreshToken)
 		if err != nil {
 			return nil, err
 		}
-		return h.repo.GetUserByID(user.ID)
+		return h.repo.GetUserByID(ctx, user.ID)
 	}
 
 	// Create new user
-	// Determine group based on email domain
-	groupID, err := h.determineGroupForEmail(info.Email)
+	// A pending invitation takes precedence over the email domain
+	invitation, err := h.repo.GetPendingInvitationByEmail(ctx, info.Email)
 	if err != nil {
 		return nil, err
 	}
 
-	user, err = h.repo.CreateUser(info.Email, info.DisplayName, groupID)
+	var groupID int64
+	if invitation != nil {
+		groupID = invitation.GroupID
+	} else {
+		// Determine group based on email domain
+		groupID, err = h.determineGroupForEmail(ctx, info.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The user, their identity and the accepted invitation are stored together
+	user, err = h.repo.CreateOAuthUser(ctx, info.Email, info.DisplayName, groupID, invitation, provider, info.ProviderID, accessToken, refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create OAuth identity
-	_, err = h.repo.CreateOAuthIdentity(user.ID, provider, info.ProviderID, accessToken, refreshToken)
+	h.events.Publish(ctx, events.UserCreated{
+		UserID:     user.ID,
+		Email:      user.Email,
+		GroupID:    user.GroupID,
+		OccurredAt: time.Now(),
+	})
+
+	return user, nil
+}
+
+// bootstrapAdmin promotes the user to admin if their email is listed in the
+// bootstrap emails and no active admin exists yet
+func (h *Handler) bootstrapAdmin(ctx context.Context, user *User) (*User, error) {
+	if user.Role == RoleAdmin {
+		return user, nil
+	}
+
+	listed := false
+	for _, email := range h.bootstrapEmails {
+		if strings.EqualFold(email, user.Email) {
+			listed = true
+			break
+		}
+	}
+	if !listed {
+		return user, nil
+	}
+
+	promoted, err := h.repo.BootstrapAdmin(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
-
-	return h.repo.GetUserByID(user.ID)
+	if !promoted {
+		return user, nil
+	}
+	log.Printf("Bootstrapped first admin: %s", user.Email)
+	return h.repo.GetUserByID(ctx, user.ID)
 }
 
-func (h *Handler) determineGroupForEmail(email string) (int64, error) {
+func (h *Handler) determineGroupForEmail(ctx context.Context, email string) (int64, error) {
 	// Extract domain from email
 	parts := strings.Split(email, "@")
 	if len(parts) != 2 {
 		// Default to regular group
-		group, err := h.repo.GetGroupByName("regular")
+		group, err := h.repo.GetGroupByName(ctx, "regular")
 		if err != nil || group == nil {
 			return 1, nil // Fallback to ID 1
 		}
@@ -257,13 +327,13 @@ func (h *Handler) determineGroupForEmail(email string) (int64, error) {
 	domain := strings.ToLower(parts[1])
 
 	// Check if domain is academic
-	isAcademic, err := h.repo.IsAcademicDomain(domain)
+	isAcademic, err := h.repo.IsAcademicDomain(ctx, domain)
 	if err != nil {
 		return 1, nil
 	}
 
 	if isAcademic {
-		group, err := h.repo.GetGroupByName("academic")
+		group, err := h.repo.GetGroupByName(ctx, "academic")
 		if err != nil || group == nil {
 			return 1, nil
 		}
@@ -271,7 +341,7 @@ func (h *Handler) determineGroupForEmail(email string) (int64, error) {
 	}
 
 	// Default to regular group
-	group, err := h.repo.GetGroupByName("regular")
+	group, err := h.repo.GetGroupByName(ctx, "regular")
 	if err != nil || group == nil {
 		return 1, nil
 	}
@@ -283,11 +353,11 @@ func (h *Handler) determineGroupForEmail(email string) (int64, error) {
 func (h *Handler) Me(c *gin.Context) {
 	user := GetUserFromContext(c)
 	if user == nil {
-		c.JSON(http.StatusUnauthorized, common.CreateErrorResponse([]string{"not authenticated"}))
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"user": gin.H{
 			"id":          user.ID,
 			"email":       user.Email,
@@ -301,12 +371,75 @@ func (h *Handler) Me(c *gin.Context) {
 	}))
 }
 
+// GetMyPreferences returns the current user's dietary preferences
+// GET /auth/me/preferences
+func (h *Handler) GetMyPreferences(c *gin.Context) {
+	user := GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+
+	prefs, err := h.repo.GetPreferences(c.Request.Context(), user.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get preferences")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"preferences": prefs}))
+}
+
+// SetMyPreferences replaces the current user's dietary preferences
+// PUT /auth/me/preferences
+func (h *Handler) SetMyPreferences(c *gin.Context) {
+	user := GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+
+	var prefs Preferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	if err := h.repo.SetPreferences(c.Request.Context(), user.ID, prefs); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to save preferences")))
+		return
+	}
+
+	saved, err := h.repo.GetPreferences(c.Request.Context(), user.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get preferences")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"preferences": saved}))
+}
+
+// MyPermissions returns the evaluated permissions, reachable features with their
+// limits and token allowance of the current user
+// GET /auth/me/permissions
+func (h *Handler) MyPermissions(c *gin.Context) {
+	user := GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+
+	permissions, err := EvaluatePermissions(c.Request.Context(), user, h.repo, h.features, h.quota)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to evaluate permissions")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(permissions))
+}
+
 // Logout logs out the current user
 // POST /auth/logout
 func (h *Handler) Logout(c *gin.Context) {
 	sessionID, err := h.sessionStore.GetSessionFromCookie(c)
 	if err == nil && sessionID != "" {
-		err := h.sessionStore.DeleteSession(sessionID)
+		err := h.sessionStore.DeleteSession(c.Request.Context(), sessionID)
 		if err != nil {
 			return
 		}
@@ -314,79 +447,198 @@ func (h *Handler) Logout(c *gin.Context) {
 
 	h.sessionStore.ClearSessionCookie(c)
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"message": "logged out successfully",
 	}))
 }
 
-// ListTokens returns all tokens for the current user
-// GET /auth/tokens
+// ListTokens returns a page of the current user's tokens
+// GET /auth/tokens?limit=&cursor=
 func (h *Handler) ListTokens(c *gin.Context) {
 	user := GetUserFromContext(c)
 	if user == nil {
-		c.JSON(http.StatusUnauthorized, common.CreateErrorResponse([]string{"not authenticated"}))
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
 		return
 	}
 
-	tokens, err := h.tokenStore.ListUserTokens(user.ID)
+	tokens, err := h.tokenStore.ListUserTokensPage(c.Request.Context(), user.ID, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to list tokens"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list tokens")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	tokens, next := pagination.Next(tokens, page, func(t Token) int64 { return t.ID })
+	common.JSON(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
 		"tokens": tokens,
-	}))
+	}, next))
 }
 
 // ListAssignableFeatures returns features that users can assign to their tokens
 // GET /auth/tokens/features
 func (h *Handler) ListAssignableFeatures(c *gin.Context) {
-	features, err := h.features.GetUserAssignableFeatures()
+	features, err := h.features.GetUserAssignableFeatures(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{"failed to list features"}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list features")))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"features": features,
 	}))
 }
 
+// FeatureCatalog lists the features third-party developers can request on
+// their tokens, with their documentation
+// GET /features
+func (h *Handler) FeatureCatalog(c *gin.Context) {
+	features, err := h.features.GetUserAssignableFeatures(c.Request.Context())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list features")))
+		return
+	}
+
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"features": features,
+	}))
+}
+
+// GetAssignableFeatureTree returns the features users can assign to their tokens, nested
+// GET /auth/features/tree
+func (h *Handler) GetAssignableFeatureTree(c *gin.Context) {
+	tree, err := h.features.GetUserAssignableFeatureTree(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list features")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"features": tree,
+	}))
+}
+
+// ListAccessRequests returns the current user's feature access requests
+// GET /auth/access-requests
+func (h *Handler) ListAccessRequests(c *gin.Context) {
+	user := GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+
+	requests, err := h.features.ListAccessRequests(c.Request.Context(), &user.ID, nil)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list access requests")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"requests": requests,
+	}))
+}
+
+// RequestAccess asks admins for access to a feature that requires approval
+// POST /auth/access-requests
+func (h *Handler) RequestAccess(c *gin.Context) {
+	user := GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+
+	var req AccessRequestCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	request, err := h.features.RequestAccess(c.Request.Context(), user.ID, req.Feature, req.Reason)
+	if err != nil {
+		if errors.Is(err, ErrAccessRequestState) {
+			common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+			return
+		}
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"request": request,
+	}))
+}
+
 // CreateToken creates a new token for the current user
 // POST /auth/tokens
 func (h *Handler) CreateToken(c *gin.Context) {
 	user := GetUserFromContext(c)
 	if user == nil {
-		c.JSON(http.StatusUnauthorized, common.CreateErrorResponse([]string{"not authenticated"}))
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
 		return
 	}
 
 	var req TokenCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
 		return
 	}
 
-	token, err := h.tokenStore.CreateUserToken(user.ID, req.Label, req.Features, req.AllowedIPs, req.ExpiresAt)
+	token, err := h.tokenStore.CreateUserToken(c.Request.Context(), user.ID, req.Label, req.Features, req.AllowedIPs, req.ExpiresAt)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
 		return
 	}
 
-	c.JSON(http.StatusCreated, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
 		"token":   token.RawToken,
 		"details": token.Token,
 		"message": "Token created. Save this token now - it will not be shown again.",
 	}))
 }
 
+// UpdateToken changes settings of a token owned by the current user. Enabling
+// debugTiming adds a Server-Timing header to every response the token gets.
+// PATCH /auth/tokens/:id
+func (h *Handler) UpdateToken(c *gin.Context) {
+	user := GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+
+	tokenID, err := parseID(c.Param("id"))
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "Invalid token ID")))
+		return
+	}
+
+	var req TokenUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	if err := h.tokenStore.SetDebugTiming(c.Request.Context(), tokenID, user.ID, *req.DebugTiming); err != nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, err.Error())))
+		return
+	}
+
+	token, _ := h.tokenStore.GetTokenByID(c.Request.Context(), tokenID)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"token": token,
+	}))
+}
+
 // RevokeToken revokes a token owned by the current user
 // DELETE /auth/tokens/:id
 func (h *Handler) RevokeToken(c *gin.Context) {
 	user := GetUserFromContext(c)
 	if user == nil {
-		c.JSON(http.StatusUnauthorized, common.CreateErrorResponse([]string{"Not authenticated"}))
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
 		return
 	}
 
@@ -395,16 +647,16 @@ func (h *Handler) RevokeToken(c *gin.Context) {
 	// Parse token ID
 	tokenID, err := parseID(tokenIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"Invalid token ID"}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "Invalid token ID")))
 		return
 	}
 
-	if err := h.tokenStore.RevokeToken(tokenID, user.ID); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+	if err := h.tokenStore.RevokeToken(c.Request.Context(), tokenID, user.ID); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
 		return
 	}
 
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(gin.H{
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
 		"message": "Token revoked successfully",
 	}))
 }
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"net/http"
+	"sync"
+
+	"API/internal/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HookStage identifies a point in the token-authenticated request lifecycle
+type HookStage string
+
+const (
+	// HookPreAuth runs before the bearer token is validated
+	HookPreAuth HookStage = "pre-auth"
+
+	// HookPostAuth runs after token, feature, IP and quota checks pass
+	HookPostAuth HookStage = "post-auth"
+
+	// HookPreResponse runs right before the response headers are written
+	HookPreResponse HookStage = "pre-response"
+
+	// HookPostUsage runs after the request has been recorded for rate limiting
+	HookPostUsage HookStage = "post-usage"
+)
+
+// HookContext carries what is known about the request at a given stage.
+// Feature, Token and User are nil during HookPreAuth. gRPC calls only run the
+// HookPostAuth and HookPostUsage stages, with a nil Gin.
+type HookContext struct {
+	Gin         *gin.Context
+	Stage       HookStage
+	FeatureSlug string
+	Feature     *Feature
+	Token       *Token
+	User        *User
+}
+
+// HookFunc is a lifecycle extension. Returning an error aborts the request;
+// errors other than *HookError abort with 403 Forbidden. Errors returned
+// from HookPreResponse and HookPostUsage hooks are ignored since the request
+// has already been accepted.
+type HookFunc func(hc *HookContext) error
+
+// HookError lets a hook choose the status code of an aborted request
+type HookError struct {
+	Status  int
+	Message string
+}
+
+func (e *HookError) Error() string {
+	return e.Message
+}
+
+// HookRegistry holds the hooks registered for each lifecycle stage
+type HookRegistry struct {
+	mu    sync.RWMutex
+	hooks map[HookStage][]HookFunc
+}
+
+// NewHookRegistry creates an empty hook registry
+func NewHookRegistry() *HookRegistry {
+	return &HookRegistry{hooks: make(map[HookStage][]HookFunc)}
+}
+
+// Register adds a hook to a stage. Hooks run in registration order.
+func (r *HookRegistry) Register(stage HookStage, hook HookFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks[stage] = append(r.hooks[stage], hook)
+}
+
+// Run executes all hooks for the context's stage, stopping at the first error
+func (r *HookRegistry) Run(hc *HookContext) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	hooks := r.hooks[hc.Stage]
+	r.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(hc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hookDenial returns the status carried by a hook error and the body to deny the
+// request with
+func hookDenial(err error) (int, gin.H) {
+	status := http.StatusForbidden
+	if hookErr, ok := err.(*HookError); ok && hookErr.Status != 0 {
+		status = hookErr.Status
+	}
+	return status, gin.H{
+		"code":  apierror.ForStatus(status),
+		"error": err.Error(),
+	}
+}
+
+// hookResponseWriter runs the pre-response hooks once, before anything is written
+type hookResponseWriter struct {
+	gin.ResponseWriter
+	once sync.Once
+	run  func()
+}
+
+func (w *hookResponseWriter) before() {
+	w.once.Do(w.run)
+}
+
+func (w *hookResponseWriter) WriteHeaderNow() {
+	w.before()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *hookResponseWriter) Write(data []byte) (int, error) {
+	w.before()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *hookResponseWriter) WriteString(s string) (int, error) {
+	w.before()
+	return w.ResponseWriter.WriteString(s)
+}
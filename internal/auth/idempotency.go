@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"API/internal/apierror"
+	"API/internal/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderIdempotencyKey lets clients retry a POST without repeating it
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	// HeaderIdempotentReplayed marks a response replayed from an earlier request
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// IdempotencyKeyTTL is how long a key and its response are kept
+	IdempotencyKeyTTL = 24 * time.Hour
+
+	// MaxIdempotencyKeyLength bounds the keys clients may send
+	MaxIdempotencyKeyLength = 255
+)
+
+// IdempotencyRecord is the stored outcome of a request sent with a key.
+// Status is 0 while the original request is still being handled.
+type IdempotencyRecord struct {
+	Fingerprint string
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyStore keeps the responses of POST requests sent with an
+// Idempotency-Key, so a retry after a timeout replays the original response
+// instead of creating the resource twice
+type IdempotencyStore struct {
+	repo *Repository
+}
+
+// NewIdempotencyStore creates a new idempotency store
+func NewIdempotencyStore(repo *Repository) *IdempotencyStore {
+	return &IdempotencyStore{repo: repo}
+}
+
+// Begin claims a key for a request. It returns nil when the caller claimed
+// it and must Complete or Release it, or the record already stored under it.
+func (s *IdempotencyStore) Begin(ctx context.Context, userID int64, key, fingerprint string) (*IdempotencyRecord, error) {
+	now := time.Now()
+
+	// An expired key may be reused for a new request
+	if _, err := s.repo.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys WHERE user_id = ? AND key = ? AND created_at <= ?
+	`, userID, key, now.Add(-IdempotencyKeyTTL)); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO idempotency_keys (user_id, key, fingerprint, created_at)
+		VALUES (?, ?, ?, ?)
+	`, userID, key, fingerprint, now)
+	if err != nil {
+		return nil, err
+	}
+	if claimed, err := result.RowsAffected(); err != nil || claimed > 0 {
+		return nil, err
+	}
+
+	var record IdempotencyRecord
+	var contentType sql.NullString
+	err = s.repo.db.QueryRowContext(ctx, `
+		SELECT fingerprint, status, content_type, body
+		FROM idempotency_keys WHERE user_id = ? AND key = ?
+	`, userID, key).Scan(&record.Fingerprint, &record.Status, &contentType, &record.Body)
+	if err != nil {
+		return nil, err
+	}
+	record.ContentType = contentType.String
+	return &record, nil
+}
+
+// Complete stores the response of the request that claimed a key
+func (s *IdempotencyStore) Complete(ctx context.Context, userID int64, key string, status int, contentType string, body []byte) error {
+	_, err := s.repo.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET status = ?, content_type = ?, body = ?
+		WHERE user_id = ? AND key = ?
+	`, status, contentType, body, userID, key)
+	return err
+}
+
+// Release frees a key whose request failed, so a retry runs it again
+func (s *IdempotencyStore) Release(ctx context.Context, userID int64, key string) error {
+	_, err := s.repo.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys WHERE user_id = ? AND key = ?
+	`, userID, key)
+	return err
+}
+
+// CleanupExpiredKeys removes keys older than IdempotencyKeyTTL
+func (s *IdempotencyStore) CleanupExpiredKeys(ctx context.Context) error {
+	_, err := s.repo.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys WHERE created_at <= ?
+	`, time.Now().Add(-IdempotencyKeyTTL))
+	return err
+}
+
+// Idempotent returns a middleware that replays the stored response when a
+// POST is retried with the same Idempotency-Key. It must run after the user
+// is authenticated; keys are scoped to the user.
+func (m *Middleware) Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > MaxIdempotencyKeyLength {
+			common.Abort(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "Idempotency-Key is too long")))
+			return
+		}
+
+		user := GetUserFromContext(c)
+		if user == nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			common.Abort(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "failed to read request body")))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		io.WriteString(hash, c.Request.Method+" "+c.Request.URL.RequestURI()+"\n")
+		hash.Write(body)
+		fingerprint := hex.EncodeToString(hash.Sum(nil))
+
+		record, err := m.idempotency.Begin(c.Request.Context(), user.ID, key, fingerprint)
+		if err != nil {
+			common.Abort(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to check idempotency key")))
+			return
+		}
+		if record != nil {
+			switch {
+			case record.Fingerprint != fingerprint:
+				common.Abort(c, http.StatusUnprocessableEntity, common.CreateErrorResponse(apierror.New(apierror.IdempotencyKeyReused, "Idempotency-Key was already used for a different request")))
+			case record.Status == 0:
+				common.Abort(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, "a request with this Idempotency-Key is still in progress")))
+			default:
+				c.Header(HeaderIdempotentReplayed, "true")
+				c.Data(record.Status, record.ContentType, record.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		// The key must be settled even when the request was cancelled or the handler
+		// panicked, otherwise it stays "in progress" until it expires
+		ctx := context.WithoutCancel(c.Request.Context())
+		completed := false
+		defer func() {
+			if !completed {
+				m.idempotency.Release(ctx, user.ID, key)
+			}
+		}()
+
+		writer := &coalescingResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// Server errors are not final; the client should be able to retry them
+		if writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+		completed = m.idempotency.Complete(ctx, user.ID, key, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes()) == nil
+	}
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotent(t *testing.T) {
+	create := func(c *gin.Context) {
+		c.String(http.StatusCreated, "created")
+	}
+	fail := func(c *gin.Context) {
+		c.String(http.StatusServiceUnavailable, "try again")
+	}
+	crash := func(c *gin.Context) {
+		panic("handler failed")
+	}
+	tests := []struct {
+		name        string
+		handler     gin.HandlerFunc
+		wantRuns    int
+		wantReplay  bool
+		retryStatus int
+	}{
+		{"a retry replays the response", create, 1, true, http.StatusCreated},
+		{"a retry after a server error runs again", fail, 2, false, http.StatusServiceUnavailable},
+		{"a retry after a panic runs again", crash, 2, false, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			m := &Middleware{idempotency: NewIdempotencyStore(testRepository(t))}
+			runs := 0
+			router := gin.New()
+			router.Use(gin.RecoveryWithWriter(io.Discard))
+			router.POST("/bookings", func(c *gin.Context) {
+				c.Set(ContextKeyUser, &User{ID: 1})
+			}, m.Idempotent(), func(c *gin.Context) {
+				runs++
+				tt.handler(c)
+			})
+			post := func() *httptest.ResponseRecorder {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/bookings", strings.NewReader(`{"court": 1}`))
+				r.Header.Set(HeaderIdempotencyKey, "booking-1")
+				router.ServeHTTP(w, r)
+				return w
+			}
+
+			post()
+			retry := post()
+			if runs != tt.wantRuns {
+				t.Errorf("handler ran %d times, want %d", runs, tt.wantRuns)
+			}
+			if retry.Code != tt.retryStatus {
+				t.Errorf("retry answered %d %s, want %d", retry.Code, retry.Body.String(), tt.retryStatus)
+			}
+			if replayed := retry.Header().Get(HeaderIdempotentReplayed) == "true"; replayed != tt.wantReplay {
+				t.Errorf("replayed = %t, want %t", replayed, tt.wantReplay)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"API/internal/rpc/authpb"
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IntrospectionFeatureSlug is the feature internal services need to introspect tokens
+const IntrospectionFeatureSlug = "token-introspection"
+
+// Features are the features this module serves over gRPC, registered at startup
+var Features = []FeatureDefinition{
+	{Slug: IntrospectionFeatureSlug, Name: "Token introspection", AdminOnly: true, Description: "Lets internal services validate the tokens their callers present"},
+}
+
+// TokenService answers token introspection calls from internal services
+type TokenService struct {
+	authpb.UnimplementedTokensServer
+	tokenStore *TokenStore
+	features   *FeatureRegistry
+}
+
+// NewTokenService creates a new token introspection service
+func NewTokenService(tokenStore *TokenStore, features *FeatureRegistry) *TokenService {
+	return &TokenService{
+		tokenStore: tokenStore,
+		features:   features,
+	}
+}
+
+// Introspect reports whether a token can currently be used, and by whom
+func (s *TokenService) Introspect(ctx context.Context, req *authpb.IntrospectRequest) (*authpb.IntrospectResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	validated, err := s.tokenStore.ValidateToken(ctx, req.GetToken())
+	if err != nil {
+		var inactive *InactiveAccountError
+		if !errors.As(err, &inactive) && !isTokenRejection(err) {
+			return nil, status.Error(codes.Internal, "Failed to validate token")
+		}
+		return &authpb.IntrospectResponse{InactiveReason: err.Error()}, nil
+	}
+
+	features, err := s.features.GetFeaturesByIDs(ctx, validated.FeatureIDs)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to get token features")
+	}
+
+	resp := &authpb.IntrospectResponse{
+		Active:       true,
+		TokenId:      validated.Token.ID,
+		UserId:       validated.User.ID,
+		Email:        validated.User.Email,
+		Role:         string(validated.User.Role),
+		AdminCreated: validated.Token.AdminCreated,
+		AllowedIps:   validated.AllowedIPs,
+	}
+	for _, f := range features {
+		ref := f.Slug
+		if version := validated.FeatureVersions[f.ID]; version > 0 {
+			ref = fmt.Sprintf("%s@v%d", f.Slug, version)
+		}
+		resp.Features = append(resp.Features, ref)
+	}
+	if validated.Token.ExpiresAt != nil {
+		resp.ExpiresAt = validated.Token.ExpiresAt.Unix()
+	}
+	return resp, nil
+}
+
+// isTokenRejection reports whether ValidateToken refused the token itself,
+// as opposed to failing to look it up
+func isTokenRejection(err error) bool {
+	return errors.Is(err, ErrInvalidTokenFormat) || errors.Is(err, ErrInvalidToken) ||
+		errors.Is(err, ErrTokenRevoked) || errors.Is(err, ErrTokenExpired) || errors.Is(err, ErrTokenUserNotFound)
+}
+
+// RegisterGRPC registers the token introspection service
+func RegisterGRPC(s *grpc.Server, svc *TokenService, authMiddleware *Middleware) {
+	authpb.RegisterTokensServer(s, svc)
+
+	authMiddleware.RequireTokenRPC(authpb.Tokens_Introspect_FullMethodName, IntrospectionFeatureSlug)
+}
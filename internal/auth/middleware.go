@@ -1,19 +1,26 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
+	"API/internal/apierror"
+	"API/internal/logging"
+	"API/internal/metrics"
+
 	"github.com/gin-gonic/gin"
 )
 
 const (
 	// Context keys
-	ContextKeyUser  = "auth_user"
-	ContextKeyToken = "auth_token"
+	ContextKeyUser           = "auth_user"
+	ContextKeyToken          = "auth_token"
+	ContextKeyRequestID      = logging.ContextKeyRequestID
+	ContextKeyFeatureVersion = "auth_feature_version"
+	ContextKeyFeature        = "auth_feature"
 
 	// Headers
 	HeaderAuthorization      = "Authorization"
@@ -21,6 +28,10 @@ const (
 	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
 	HeaderRateLimitReset     = "X-RateLimit-Reset"
 	HeaderRetryAfter         = "Retry-After"
+	HeaderRequestID          = logging.HeaderRequestID
+	HeaderDeprecation        = "Deprecation"
+	HeaderSunset             = "Sunset"
+	HeaderFeatureVersion     = "X-Feature-Version"
 )
 
 // Middleware provides authentication and authorization middleware
@@ -30,6 +41,17 @@ type Middleware struct {
 	features     *FeatureRegistry
 	quota        *QuotaEngine
 	usage        *UsageTracker
+	hooks        *HookRegistry
+	diagnostics  *DiagnosticsStore
+	coalescer    *RequestCoalescer
+	idempotency  *IdempotencyStore
+	throttle     *TokenThrottle
+	metrics      *metrics.Tenant
+
+	// Feature slugs required by registered routes, checked at startup
+	routeFeatures []string
+	// Feature slug required by each registered gRPC method
+	rpcFeatures map[string]string
 }
 
 // NewMiddleware creates a new middleware instance
@@ -39,6 +61,11 @@ func NewMiddleware(
 	features *FeatureRegistry,
 	quota *QuotaEngine,
 	usage *UsageTracker,
+	hooks *HookRegistry,
+	diagnostics *DiagnosticsStore,
+	coalescer *RequestCoalescer,
+	idempotency *IdempotencyStore,
+	throttle *TokenThrottle,
 ) *Middleware {
 	return &Middleware{
 		tokenStore:   tokenStore,
@@ -46,180 +73,211 @@ func NewMiddleware(
 		features:     features,
 		quota:        quota,
 		usage:        usage,
+		hooks:        hooks,
+		diagnostics:  diagnostics,
+		coalescer:    coalescer,
+		idempotency:  idempotency,
+		throttle:     throttle,
+		rpcFeatures:  make(map[string]string),
 	}
 }
 
+// SetMetrics records the latency and status of every token-authenticated request by
+// feature and the user's group, and the invalid tokens
+func (m *Middleware) SetMetrics(tenant *metrics.Tenant) {
+	m.metrics = tenant
+}
+
 // RequireToken returns a middleware that validates bearer tokens and checks quotas
 func (m *Middleware) RequireToken(featureSlug string) gin.HandlerFunc {
+	m.routeFeatures = append(m.routeFeatures, featureSlug)
+
 	return func(c *gin.Context) {
-		// 1. Extract Authorization header
-		authHeader := c.GetHeader(HeaderAuthorization)
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Missing authorization header",
-			})
-			return
+		timing := newServerTiming()
+
+		// Denials count too, under no group until the token is known. A panic is
+		// answered with a 500 by the recovery middleware, after this has run.
+		var groupID int64
+		defer func() {
+			status := c.Writer.Status()
+			r := recover()
+			if r != nil {
+				status = http.StatusInternalServerError
+			}
+			m.metrics.ObserveRequest(featureSlug, groupID, status, time.Since(timing.start))
+			if r != nil {
+				panic(r)
+			}
+		}()
+
+		requestID := RequestIDFromContext(c)
+		hc := &HookContext{Gin: c, FeatureSlug: featureSlug}
+		diag := &DenialDiagnostic{
+			RequestID:   requestID,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			ClientIP:    c.ClientIP(),
+			FeatureSlug: featureSlug,
 		}
 
-		// 2. Parse Bearer token
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid authorization header format",
-			})
-			return
-		}
-		rawToken := parts[1]
+		// Denials are recorded so support can explain them from the request ID
+		deny := func(status int, body gin.H) {
+			diag.Timestamp = time.Now()
+			diag.Status = status
+			diag.Reason, _ = body["error"].(string)
+			m.diagnostics.Record(diag)
 
-		// 3. Validate token
-		validated, err := m.tokenStore.ValidateToken(rawToken)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": err.Error(),
-			})
-			return
+			body["requestId"] = requestID
+			c.AbortWithStatusJSON(status, body)
 		}
 
-		// 4. Get the feature being accessed
-		feature, err := m.features.GetFeatureBySlug(featureSlug)
-		if err != nil || feature == nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": "Feature not found",
-			})
-			return
+		// Pre-response hooks see everything the later stages learned
+		c.Writer = &hookResponseWriter{
+			ResponseWriter: c.Writer,
+			run: func() {
+				hc.Stage = HookPreResponse
+				_ = m.hooks.Run(hc)
+			},
 		}
 
-		// 5. Live admin-only check: if feature is admin-only and token is not admin-created, deny
-		adminOnly, err := m.features.IsFeatureAdminOnly(feature.ID)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to check feature permissions",
-			})
+		hc.Stage = HookPreAuth
+		if err := m.hooks.Run(hc); err != nil {
+			diag.addCheck("hook:"+string(HookPreAuth), false, err.Error())
+			deny(hookDenial(err))
 			return
 		}
-		if adminOnly && !validated.Token.AdminCreated {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"error": "This feature requires an admin-issued token",
-			})
-			return
+
+		fail := func(d *denial) {
+			if d.failed {
+				c.AbortWithStatusJSON(d.status, d.body)
+				return
+			}
+			deny(d.status, d.body)
 		}
 
-		// 6. Check if token has access to this feature (including parent features)
-		hasAccess, err := m.features.TokenHasFeatureAccess(validated.FeatureIDs, featureSlug)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to check feature access",
-			})
+		validated, d := m.authenticate(c.Request.Context(), c.GetHeader(HeaderAuthorization), diag, c.Header)
+		if d != nil {
+			fail(d)
 			return
 		}
-		if !hasAccess {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"error": fmt.Sprintf("Token does not have access to feature '%s'", featureSlug),
-			})
+		groupID = validated.User.GroupID
+		logging.Annotate(c, "userId", validated.User.ID)
+		logging.Annotate(c, "tokenId", validated.Token.ID)
+		logging.Annotate(c, "feature", featureSlug)
+
+		feature, d := m.authorize(c.Request.Context(), validated, featureSlug, diag, c.Header)
+		if d != nil {
+			fail(d)
 			return
 		}
+		timing.lap("auth", "Token and feature checks")
 
-		// 7. Check IP whitelist
-		if len(validated.AllowedIPs) > 0 {
-			clientIP := c.ClientIP()
-			canonicalIP, err := CanonicalizeIP(clientIP)
-			if err != nil {
-				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-					"error": "Invalid client IP",
-				})
-				return
-			}
-
-			if !IsIPAllowed(canonicalIP, validated.AllowedIPs) {
-				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-					"error": "IP address not allowed for this token",
-				})
+		// Identical retries of an in-flight GET share its response and are not charged again
+		if c.Request.Method == http.MethodGet && feature.DedupWindowMs > 0 {
+			window := time.Duration(feature.DedupWindowMs) * time.Millisecond
+			lead, finish := m.coalescer.coalesce(c, coalesceKey(validated.Token.ID, c), requestID, window)
+			if !lead {
 				return
 			}
+			defer finish()
 		}
 
-		// 8. Check RPM quota
-		effectiveRPM, err := m.quota.GetEffectiveRPM(validated.User.ID, feature.ID)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to check quota",
-			})
+		if d := m.checkQuota(c.Request.Context(), validated, feature, diag, c.Header); d != nil {
+			fail(d)
 			return
 		}
+		timing.lap("quota", "Rate limit check")
 
-		// If not unlimited, check usage
-		if effectiveRPM != UnlimitedRPM {
-			currentRPM, err := m.usage.GetFeatureRPM(validated.User.ID, feature.ID)
-			if err != nil {
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-					"error": "Failed to check usage",
-				})
-				return
-			}
-
-			// Set rate limit headers
-			remaining := effectiveRPM - currentRPM - 1 // -1 for this request
-			if remaining < 0 {
-				remaining = 0
-			}
-			resetTime := time.Now().Add(60 * time.Second).Unix()
-
-			c.Header(HeaderRateLimitLimit, strconv.Itoa(effectiveRPM))
-			c.Header(HeaderRateLimitRemaining, strconv.Itoa(remaining))
-			c.Header(HeaderRateLimitReset, strconv.FormatInt(resetTime, 10))
-
-			if currentRPM >= effectiveRPM {
-				c.Header(HeaderRetryAfter, "60")
-				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-					"error":      "Rate limit exceeded",
-					"limit":      effectiveRPM,
-					"retryAfter": 60,
-				})
-				return
-			}
+		version, d := m.admit(hc, validated, feature, diag, c.Header)
+		if d != nil {
+			fail(d)
+			return
 		}
 
-		// 9. Record usage (non-blocking)
-		m.usage.RecordRequest(validated.User.ID, feature.ID)
-
 		// 10. Set context values
 		c.Set(ContextKeyUser, validated.User)
 		c.Set(ContextKeyToken, validated.Token)
+		c.Set(ContextKeyFeature, feature.Slug)
+		c.Set(ContextKeyFeatureVersion, version)
+
+		// 11. Debug tokens get a Server-Timing breakdown of this request
+		if validated.Token.DebugTiming {
+			timing.lap("hooks", "Hooks and usage recording")
+			c.Writer = &timingResponseWriter{ResponseWriter: c.Writer, timing: timing}
+		}
 
 		c.Next()
 	}
 }
 
+// CheckRouteFeatures verifies that every feature required by a registered
+// route exists. Call it once the routes are registered, so a missing feature
+// fails startup instead of every request to the route.
+func (m *Middleware) CheckRouteFeatures(ctx context.Context) error {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, slug := range m.routeFeatures {
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		feature, err := m.features.GetFeatureBySlug(ctx, slug)
+		if err != nil {
+			return err
+		}
+		if feature == nil {
+			missing = append(missing, slug)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Routes require unregistered features: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// RequestIDFromContext returns the request's ID, assigning a new one (and
+// echoing it in the X-Request-ID response header) on first use
+func RequestIDFromContext(c *gin.Context) string {
+	return logging.RequestID(c)
+}
+
 // RequireSession returns a middleware that validates session cookies
 func (m *Middleware) RequireSession() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		sessionID, err := m.sessionStore.GetSessionFromCookie(c)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"code":  apierror.NotAuthenticated,
 				"error": "Not authenticated",
 			})
 			return
 		}
 
-		user, err := m.sessionStore.GetUserFromSession(sessionID)
+		user, err := m.sessionStore.GetUserFromSession(c.Request.Context(), sessionID)
 		if err != nil || user == nil {
 			m.sessionStore.ClearSessionCookie(c)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"code":  apierror.NotAuthenticated,
 				"error": "Session expired or invalid",
 			})
 			return
 		}
 
 		// Check user status
-		if user.Status != StatusActive {
+		if !user.IsActive() {
 			m.sessionStore.ClearSessionCookie(c)
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"error": fmt.Sprintf("Account is %s", user.Status),
+				"code":           apierror.AccountInactive,
+				"error":          user.SuspensionMessage(),
+				"reason":         user.SuspensionReason,
+				"suspendedUntil": user.SuspendedUntil,
 			})
 			return
 		}
 
 		c.Set(ContextKeyUser, user)
+		logging.Annotate(c, "userId", user.ID)
 		c.Next()
 	}
 }
@@ -230,6 +288,7 @@ func (m *Middleware) RequireRole(role Role) gin.HandlerFunc {
 		userVal, exists := c.Get(ContextKeyUser)
 		if !exists {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"code":  apierror.NotAuthenticated,
 				"error": "Not authenticated",
 			})
 			return
@@ -238,6 +297,7 @@ func (m *Middleware) RequireRole(role Role) gin.HandlerFunc {
 		user, ok := userVal.(*User)
 		if !ok {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"code":  apierror.Internal,
 				"error": "Invalid user context",
 			})
 			return
@@ -245,6 +305,7 @@ func (m *Middleware) RequireRole(role Role) gin.HandlerFunc {
 
 		if user.Role != role && user.Role != RoleAdmin {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"code":  apierror.Forbidden,
 				"error": fmt.Sprintf("Requires %s role", role),
 			})
 			return
@@ -263,15 +324,25 @@ func (m *Middleware) OptionalSession() gin.HandlerFunc {
 			return
 		}
 
-		user, err := m.sessionStore.GetUserFromSession(sessionID)
-		if err == nil && user != nil && user.Status == StatusActive {
+		user, err := m.sessionStore.GetUserFromSession(c.Request.Context(), sessionID)
+		if err == nil && user != nil && user.IsActive() {
 			c.Set(ContextKeyUser, user)
+			logging.Annotate(c, "userId", user.ID)
 		}
 
 		c.Next()
 	}
 }
 
+// FeatureVersionFromContext returns the response format version the token
+// pinned for the route's feature, 1 when it did not pin one
+func FeatureVersionFromContext(c *gin.Context) int {
+	if version := c.GetInt(ContextKeyFeatureVersion); version > 0 {
+		return version
+	}
+	return 1
+}
+
 // GetUserFromContext retrieves the authenticated user from the context
 func GetUserFromContext(c *gin.Context) *User {
 	userVal, exists := c.Get(ContextKeyUser)
@@ -1,7 +1,9 @@
 package auth
 
 import (
+	"API/internal/events"
 	"database/sql"
+	"fmt"
 	"time"
 )
 
@@ -9,10 +11,17 @@ import (
 type Role string
 
 const (
-	RoleUser  Role = "user"
-	RoleAdmin Role = "admin"
+	RoleUser       Role = "user"
+	RoleStaff      Role = "staff"       // instructors managing course workspaces
+	RoleDataEditor Role = "data-editor" // department staff publishing thesis topics and internships
+	RoleAdmin      Role = "admin"
 )
 
+// Valid reports whether r is one of the roles above
+func (r Role) Valid() bool {
+	return r == RoleUser || r == RoleStaff || r == RoleDataEditor || r == RoleAdmin
+}
+
 // Status represents user account status
 type Status string
 
@@ -31,11 +40,13 @@ const (
 
 // Group represents a quota tier
 type Group struct {
-	ID          int64     `json:"id"`
-	Name        string    `json:"name"`
-	DefaultRPM  int       `json:"defaultRpm"`
-	Description *string   `json:"description"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	DefaultRPM  int        `json:"defaultRpm"`
+	MaxSessions int        `json:"maxSessions"` // 0 means unlimited
+	Description *string    `json:"description"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"` // set while the group is deleted
 }
 
 // User represents an authenticated user
@@ -49,10 +60,48 @@ type User struct {
 	MaxTokens   int       `json:"maxTokens"`
 	CreatedAt   time.Time `json:"createdAt"`
 
+	// Suspension context, only set while Status is suspended
+	SuspensionReason *string    `json:"suspensionReason,omitempty"`
+	SuspendedBy      *int64     `json:"suspendedBy,omitempty"`
+	SuspendedAt      *time.Time `json:"suspendedAt,omitempty"`
+	SuspendedUntil   *time.Time `json:"suspendedUntil,omitempty"` // NULL = until lifted by an admin
+
+	// Set while the user is deleted; deleted users cannot sign in or use their tokens
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+
 	// Joined fields (not always populated)
 	Group *Group `json:"group,omitempty"`
 }
 
+// IsActive reports whether the account may sign in and use its tokens
+func (u *User) IsActive() bool {
+	return u.Status == StatusActive && u.DeletedAt == nil
+}
+
+// SuspensionMessage describes why the account cannot be used, for 403 responses
+func (u *User) SuspensionMessage() string {
+	if u.DeletedAt != nil {
+		return "Account is deleted"
+	}
+	msg := fmt.Sprintf("Account is %s", u.Status)
+	if u.SuspensionReason != nil && *u.SuspensionReason != "" {
+		msg += ": " + *u.SuspensionReason
+	}
+	if u.SuspendedUntil != nil {
+		msg += " (until " + u.SuspendedUntil.UTC().Format(time.RFC3339) + ")"
+	}
+	return msg
+}
+
+// InactiveAccountError is returned when a valid credential belongs to a suspended account
+type InactiveAccountError struct {
+	User *User
+}
+
+func (e *InactiveAccountError) Error() string {
+	return e.User.SuspensionMessage()
+}
+
 // OAuthIdentity links a user to an OAuth provider
 type OAuthIdentity struct {
 	ID           int64     `json:"id"`
@@ -80,13 +129,25 @@ type OAuthState struct {
 
 // Feature represents an API feature (hierarchical)
 type Feature struct {
-	ID        int64      `json:"id"`
-	Slug      string     `json:"slug"`
-	Name      string     `json:"name"`
-	ParentID  *int64     `json:"parentId,omitempty"`
-	AdminOnly bool       `json:"adminOnly"`
-	CreatedAt time.Time  `json:"createdAt"`
-	Children  []*Feature `json:"children,omitempty"`
+	ID        int64  `json:"id"`
+	Slug      string `json:"slug"`
+	Name      string `json:"name"`
+	ParentID  *int64 `json:"parentId,omitempty"`
+	AdminOnly bool   `json:"adminOnly"`
+	// Users need an approved access request before their tokens can use it
+	ApprovalRequired bool `json:"approvalRequired"`
+	// Highest response format version; tokens pin one with "slug@vN" and use v1 otherwise
+	LatestVersion int `json:"latestVersion"`
+	// Identical GET requests from one token within this many milliseconds are
+	// served from a single execution and charged once (0 disables)
+	DedupWindowMs int        `json:"dedupWindowMs"`
+	Deprecated    bool       `json:"deprecated"`
+	SunsetAt      *time.Time `json:"sunsetAt,omitempty"` // When the feature will be retired
+	Description   *string    `json:"description,omitempty"`
+	DocsURL       *string    `json:"docsUrl,omitempty"`
+	Example       *string    `json:"example,omitempty"` // e.g. an example request
+	CreatedAt     time.Time  `json:"createdAt"`
+	Children      []*Feature `json:"children,omitempty"`
 }
 
 // GroupFeatureQuota defines the default RPM for a group on a feature
@@ -110,11 +171,26 @@ type Token struct {
 	TokenHash    string     `json:"-"` // Never expose
 	Label        string     `json:"label"`
 	AdminCreated bool       `json:"adminCreated"`
+	DebugTiming  bool       `json:"debugTiming"` // Adds a Server-Timing header to responses
 	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
 	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
 	CreatedAt    time.Time  `json:"createdAt"`
 	Features     []Feature  `json:"features,omitempty"`
-	AllowedIPs   []string   `json:"allowedIps,omitempty"`
+	// Versions pinned with "slug@vN" by feature slug; unlisted features use v1
+	FeatureVersions map[string]int `json:"featureVersions,omitempty"`
+	AllowedIPs      []string       `json:"allowedIps,omitempty"`
+}
+
+// DeprecatedFeatureToken is an active token still scoped to deprecated features
+type DeprecatedFeatureToken struct {
+	TokenID      int64      `json:"tokenId"`
+	Label        string     `json:"label"`
+	UserID       int64      `json:"userId"`
+	Email        string     `json:"email"`
+	AdminCreated bool       `json:"adminCreated"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	Features     []Feature  `json:"features"` // Only the deprecated ones
 }
 
 // TokenWithRaw includes the raw token value (only returned on creation)
@@ -123,6 +199,121 @@ type TokenWithRaw struct {
 	RawToken string `json:"token"`
 }
 
+// Invitation pre-assigns a group and role to an email before its first login
+type Invitation struct {
+	ID         int64      `json:"id"`
+	Email      string     `json:"email"`
+	GroupID    int64      `json:"groupId"`
+	Role       Role       `json:"role"`
+	InvitedBy  *int64     `json:"invitedBy,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	AcceptedAt *time.Time `json:"acceptedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// AccessRequestStatus is the state of a feature access request
+type AccessRequestStatus string
+
+const (
+	AccessRequestPending  AccessRequestStatus = "pending"
+	AccessRequestApproved AccessRequestStatus = "approved"
+	AccessRequestDenied   AccessRequestStatus = "denied"
+	AccessRequestRevoked  AccessRequestStatus = "revoked" // Approved, then withdrawn by an admin
+)
+
+// AccessRequest is a user's request to use a feature that requires approval
+type AccessRequest struct {
+	ID           int64               `json:"id"`
+	UserID       int64               `json:"userId"`
+	Email        string              `json:"email"`
+	FeatureID    int64               `json:"featureId"`
+	FeatureSlug  string              `json:"featureSlug"`
+	Status       AccessRequestStatus `json:"status"`
+	Reason       *string             `json:"reason,omitempty"`
+	DecidedBy    *int64              `json:"decidedBy,omitempty"`
+	DecidedAt    *time.Time          `json:"decidedAt,omitempty"`
+	DecisionNote *string             `json:"decisionNote,omitempty"`
+	CreatedAt    time.Time           `json:"createdAt"`
+}
+
+// UserNote is a free-form admin-only note on a user account
+type UserNote struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"userId"`
+	AuthorID  *int64    `json:"authorId,omitempty"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CourseWorkspace groups the time-boxed tokens an instructor issues to a cohort
+type CourseWorkspace struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	InstructorID int64     `json:"instructorId"`
+	EndsAt       time.Time `json:"endsAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Features     []Feature `json:"features"`
+	MemberCount  int       `json:"memberCount"`
+}
+
+// AdminWebhook is an admin-registered endpoint that receives auth events
+type AdminWebhook struct {
+	ID          int64         `json:"id"`
+	URL         string        `json:"url"`
+	Events      []events.Type `json:"events"`
+	Format      WebhookFormat `json:"format"`
+	Secret      string        `json:"-"` // Only returned on creation
+	Description *string       `json:"description,omitempty"`
+	Active      bool          `json:"active"`
+	CreatedBy   *int64        `json:"createdBy,omitempty"`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+// WebhookFormat selects the body sent to a webhook
+type WebhookFormat string
+
+const (
+	WebhookFormatJSON    WebhookFormat = "json"    // Signed event envelope
+	WebhookFormatSlack   WebhookFormat = "slack"   // Slack incoming webhook message
+	WebhookFormatDiscord WebhookFormat = "discord" // Discord webhook message
+)
+
+// SurgeWindow is a predefined high-traffic period (exam weeks, registration
+// days) during which its groups get higher quotas and caches refresh sooner
+type SurgeWindow struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	StartsAt       time.Time `json:"startsAt"`
+	EndsAt         time.Time `json:"endsAt"`
+	RPMMultiplier  float64   `json:"rpmMultiplier"`
+	CacheTTLFactor float64   `json:"cacheTtlFactor"` // Applied to cache TTLs, 1 leaves them unchanged
+	GroupIDs       []int64   `json:"groupIds"`
+	Active         bool      `json:"active"`
+	CreatedBy      *int64    `json:"createdBy,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// WorkspaceMember is a student enrolled in a course workspace with their usage
+type WorkspaceMember struct {
+	ID           int64      `json:"id"`
+	UserID       int64      `json:"userId"`
+	Email        string     `json:"email"`
+	DisplayName  string     `json:"displayName"`
+	TokenID      *int64     `json:"tokenId,omitempty"`
+	TokenActive  bool       `json:"tokenActive"`
+	RequestCount int        `json:"requestCount"`
+	LastUsedAt   *time.Time `json:"lastUsedAt,omitempty"`
+	CurrentRPM   int        `json:"currentRpm"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// IssuedWorkspaceToken is a student token returned once when a student is enrolled
+type IssuedWorkspaceToken struct {
+	UserID int64  `json:"userId"`
+	Email  string `json:"email"`
+	Token  string `json:"token"`
+}
+
 // UsageLogEntry represents a single API request for rate limiting
 type UsageLogEntry struct {
 	ID        int64     `json:"id"`
@@ -144,18 +335,38 @@ type TokenCreateRequest struct {
 	ExpiresAt  *time.Time `json:"expiresAt"`
 }
 
-// UserUpdateRequest represents the request body for updating a user
+// TokenUpdateRequest represents the request body for updating one's own token
+type TokenUpdateRequest struct {
+	DebugTiming *bool `json:"debugTiming" binding:"required"`
+}
+
+// UserUpdateRequest represents the request body for updating a user.
+// SuspensionReason and SuspendedUntil only apply when Status is suspended.
 type UserUpdateRequest struct {
-	Role      *Role   `json:"role"`
-	Status    *Status `json:"status"`
-	GroupID   *int64  `json:"groupId"`
-	MaxTokens *int    `json:"maxTokens"`
+	Role             *Role      `json:"role"`
+	Status           *Status    `json:"status"`
+	GroupID          *int64     `json:"groupId"`
+	MaxTokens        *int       `json:"maxTokens"`
+	SuspensionReason *string    `json:"suspensionReason" binding:"omitempty,max=500"`
+	SuspendedUntil   *time.Time `json:"suspendedUntil"`
+}
+
+// UserFilter narrows down admin user listings. Zero values mean "any".
+type UserFilter struct {
+	Query           string
+	Role            *Role
+	Status          *Status
+	GroupID         *int64
+	HasActiveTokens *bool
+	Tag             string
+	Deleted         bool // list deleted users instead of existing ones
 }
 
 // GroupCreateRequest represents the request body for creating a group
 type GroupCreateRequest struct {
 	Name        string  `json:"name" binding:"required"`
 	DefaultRPM  int     `json:"defaultRpm" binding:"required,min=1"`
+	MaxSessions int     `json:"maxSessions" binding:"min=0"`
 	Description *string `json:"description"`
 }
 
@@ -163,22 +374,41 @@ type GroupCreateRequest struct {
 type GroupUpdateRequest struct {
 	Name        *string `json:"name"`
 	DefaultRPM  *int    `json:"defaultRpm"`
+	MaxSessions *int    `json:"maxSessions" binding:"omitempty,min=0"`
 	Description *string `json:"description"`
 }
 
+// FeatureDocs is the catalog documentation accepted when creating or updating a feature
+type FeatureDocs struct {
+	Description *string `json:"description" binding:"omitempty,max=2000"`
+	DocsURL     *string `json:"docsUrl" binding:"omitempty,url,max=2000"`
+	Example     *string `json:"example" binding:"omitempty,max=2000"`
+}
+
+func (d FeatureDocs) isSet() bool {
+	return d.Description != nil || d.DocsURL != nil || d.Example != nil
+}
+
 // FeatureCreateRequest represents the request body for creating a feature
 type FeatureCreateRequest struct {
-	Slug      string `json:"slug" binding:"required"`
-	Name      string `json:"name" binding:"required"`
-	ParentID  *int64 `json:"parentId"`
-	AdminOnly bool   `json:"adminOnly"`
+	Slug             string `json:"slug" binding:"required"`
+	Name             string `json:"name" binding:"required"`
+	ParentID         *int64 `json:"parentId"`
+	AdminOnly        bool   `json:"adminOnly"`
+	ApprovalRequired bool   `json:"approvalRequired"`
+	FeatureDocs
 }
 
 // FeatureUpdateRequest represents the request body for updating a feature
 type FeatureUpdateRequest struct {
-	Name      *string `json:"name"`
-	ParentID  *int64  `json:"parentId"`
-	AdminOnly *bool   `json:"adminOnly"`
+	Name             *string    `json:"name"`
+	ParentID         *int64     `json:"parentId"`
+	AdminOnly        *bool      `json:"adminOnly"`
+	ApprovalRequired *bool      `json:"approvalRequired"`
+	DedupWindowMs    *int       `json:"dedupWindowMs" binding:"omitempty,min=0,max=10000"`
+	Deprecated       *bool      `json:"deprecated"`
+	SunsetAt         *time.Time `json:"sunsetAt"` // Implies deprecated unless it is explicitly false
+	FeatureDocs
 }
 
 // QuotaSetRequest represents the request body for setting quotas
@@ -192,12 +422,90 @@ type QuotaEntry struct {
 	RPMLimit  *int  `json:"rpmLimit"` // NULL = uncapped
 }
 
+// InvitationCreateRequest represents the request body for inviting an email
+type InvitationCreateRequest struct {
+	Email     string     `json:"email" binding:"required,email"`
+	GroupID   int64      `json:"groupId" binding:"required"`
+	Role      Role       `json:"role"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// UserNoteCreateRequest represents the request body for adding a note to a user
+type UserNoteCreateRequest struct {
+	Body string `json:"body" binding:"required,max=4000"`
+}
+
+// UserTagsRequest represents the request body for replacing a user's tags
+type UserTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,dive,min=1,max=64"`
+}
+
+// Preferences are a user's dietary preferences, which personalize cafeteria menus. The
+// values match the food tags and allergens of the schedule module.
+type Preferences struct {
+	Diets     []string `json:"diets" binding:"required,dive,oneof=vegan vegetarian gluten-free fasting"`
+	Allergens []string `json:"allergens" binding:"required,dive,oneof=gluten crustaceans eggs fish peanuts soybeans milk nuts celery mustard sesame sulphites lupin molluscs"`
+}
+
+// WorkspaceCreateRequest represents the request body for creating a course workspace
+type WorkspaceCreateRequest struct {
+	Name     string    `json:"name" binding:"required,max=200"`
+	Features []string  `json:"features" binding:"required,min=1"`
+	EndsAt   time.Time `json:"endsAt" binding:"required"`
+}
+
+// WorkspaceStudentsRequest represents the request body for enrolling students by email
+type WorkspaceStudentsRequest struct {
+	Emails []string `json:"emails" binding:"required,min=1,max=500,dive,email"`
+}
+
+// WebhookCreateRequest represents the request body for registering a webhook
+type WebhookCreateRequest struct {
+	URL         string        `json:"url" binding:"required,url,max=2000"`
+	Events      []events.Type `json:"events" binding:"required,min=1"`
+	Format      WebhookFormat `json:"format" binding:"omitempty,oneof=json slack discord"`
+	Secret      string        `json:"secret" binding:"omitempty,min=16,max=256"` // Generated when empty
+	Description *string       `json:"description" binding:"omitempty,max=500"`
+}
+
+// WebhookUpdateRequest represents the request body for updating a webhook
+type WebhookUpdateRequest struct {
+	URL         *string        `json:"url" binding:"omitempty,url,max=2000"`
+	Events      []events.Type  `json:"events" binding:"omitempty,min=1"`
+	Format      *WebhookFormat `json:"format" binding:"omitempty,oneof=json slack discord"`
+	Description *string        `json:"description" binding:"omitempty,max=500"`
+	Active      *bool          `json:"active"`
+}
+
+// SurgeWindowRequest represents the request body for creating or replacing a surge window
+type SurgeWindowRequest struct {
+	Name           string    `json:"name" binding:"required,max=200"`
+	StartsAt       time.Time `json:"startsAt" binding:"required"`
+	EndsAt         time.Time `json:"endsAt" binding:"required,gtfield=StartsAt"`
+	RPMMultiplier  float64   `json:"rpmMultiplier" binding:"required,gt=0,lte=100"`
+	CacheTTLFactor float64   `json:"cacheTtlFactor" binding:"omitempty,gt=0,lte=1"` // Defaults to 1
+	GroupIDs       []int64   `json:"groupIds" binding:"required,min=1"`
+}
+
+// AccessRequestCreateRequest represents the request body for requesting access to a feature
+type AccessRequestCreateRequest struct {
+	Feature string  `json:"feature" binding:"required"`
+	Reason  *string `json:"reason" binding:"omitempty,max=2000"`
+}
+
+// AccessRequestDecisionRequest represents the request body for approving, denying or revoking access
+type AccessRequestDecisionRequest struct {
+	Note *string `json:"note" binding:"omitempty,max=2000"`
+}
+
 // ValidatedToken holds the result of token validation
 type ValidatedToken struct {
 	Token      *Token
 	User       *User
 	FeatureIDs []int64
-	AllowedIPs []string
+	// Versions pinned by feature ID; unlisted features use v1
+	FeatureVersions map[int64]int
+	AllowedIPs      []string
 }
 
 // NullableInt64 helper for scanning nullable int64
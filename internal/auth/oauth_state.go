@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"time"
@@ -22,7 +23,7 @@ func NewOAuthStateStore(repo *Repository) *OAuthStateStore {
 }
 
 // CreateState generates a new random state token for CSRF protection
-func (s *OAuthStateStore) CreateState() (string, error) {
+func (s *OAuthStateStore) CreateState(ctx context.Context) (string, error) {
 	// Generate 32 random bytes
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
@@ -34,7 +35,7 @@ func (s *OAuthStateStore) CreateState() (string, error) {
 	expiresAt := time.Now().Add(OAuthStateExpiry)
 
 	// Store in database
-	_, err := s.repo.db.Exec(`
+	_, err := s.repo.db.ExecContext(ctx, `
 		INSERT INTO oauth_states (state, expires_at) VALUES (?, ?)
 	`, state, expiresAt)
 	if err != nil {
@@ -46,9 +47,9 @@ func (s *OAuthStateStore) CreateState() (string, error) {
 
 // ValidateState checks if a state token is valid and not expired.
 // The token is deleted after validation (single-use).
-func (s *OAuthStateStore) ValidateState(state string) (bool, error) {
+func (s *OAuthStateStore) ValidateState(ctx context.Context, state string) (bool, error) {
 	// Try to delete the state and check if it existed and wasn't expired
-	result, err := s.repo.db.Exec(`
+	result, err := s.repo.db.ExecContext(ctx, `
 		DELETE FROM oauth_states 
 		WHERE state = ? AND expires_at > ?
 	`, state, time.Now())
@@ -65,8 +66,8 @@ func (s *OAuthStateStore) ValidateState(state string) (bool, error) {
 }
 
 // CleanupExpiredStates removes all expired state tokens
-func (s *OAuthStateStore) CleanupExpiredStates() error {
-	_, err := s.repo.db.Exec(`
+func (s *OAuthStateStore) CleanupExpiredStates(ctx context.Context) error {
+	_, err := s.repo.db.ExecContext(ctx, `
 		DELETE FROM oauth_states WHERE expires_at <= ?
 	`, time.Now())
 	return err
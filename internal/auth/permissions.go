@@ -0,0 +1,155 @@
+package auth
+
+import "context"
+
+// Permission names a capability the frontend can check before rendering a view
+type Permission string
+
+const (
+	PermissionTokensManage          Permission = "tokens:manage"
+	PermissionWorkspacesManage      Permission = "workspaces:manage"
+	PermissionUsersManage           Permission = "users:manage"
+	PermissionGroupsManage          Permission = "groups:manage"
+	PermissionFeaturesManage        Permission = "features:manage"
+	PermissionQuotasManage          Permission = "quotas:manage"
+	PermissionInvitationsManage     Permission = "invitations:manage"
+	PermissionAcademicDomainsManage Permission = "academic-domains:manage"
+	PermissionWebhooksManage        Permission = "webhooks:manage"
+	PermissionAccessRequestsManage  Permission = "access-requests:manage"
+	PermissionStatsRead             Permission = "stats:read"
+	PermissionDiagnosticsRead       Permission = "diagnostics:read"
+	PermissionBackupsManage         Permission = "backups:manage"
+	PermissionPostingsManage        Permission = "postings:manage"
+)
+
+// rolePermissions mirrors the RequireRole checks on the routes. Every role
+// also gets the permissions of the roles below it; staff and data editors are
+// side by side, both below admins.
+var rolePermissions = map[Role][]Permission{
+	RoleUser: {
+		PermissionTokensManage,
+	},
+	RoleStaff: {
+		PermissionWorkspacesManage,
+	},
+	RoleDataEditor: {
+		PermissionPostingsManage,
+	},
+	RoleAdmin: {
+		PermissionUsersManage,
+		PermissionGroupsManage,
+		PermissionFeaturesManage,
+		PermissionQuotasManage,
+		PermissionInvitationsManage,
+		PermissionAcademicDomainsManage,
+		PermissionWebhooksManage,
+		PermissionAccessRequestsManage,
+		PermissionStatsRead,
+		PermissionDiagnosticsRead,
+		PermissionBackupsManage,
+	},
+}
+
+// PermissionsForRole returns everything a role is allowed to do
+func PermissionsForRole(role Role) []Permission {
+	permissions := append([]Permission{}, rolePermissions[RoleUser]...)
+	switch role {
+	case RoleStaff:
+		permissions = append(permissions, rolePermissions[RoleStaff]...)
+	case RoleDataEditor:
+		permissions = append(permissions, rolePermissions[RoleDataEditor]...)
+	case RoleAdmin:
+		permissions = append(permissions, rolePermissions[RoleStaff]...)
+		permissions = append(permissions, rolePermissions[RoleDataEditor]...)
+		permissions = append(permissions, rolePermissions[RoleAdmin]...)
+	}
+	return permissions
+}
+
+// FeaturePermission is a feature the user can reach with the limit that applies to them
+type FeaturePermission struct {
+	ID               int64  `json:"id"`
+	Slug             string `json:"slug"`
+	Name             string `json:"name"`
+	ParentID         *int64 `json:"parentId,omitempty"`
+	AdminOnly        bool   `json:"adminOnly"`
+	ApprovalRequired bool   `json:"approvalRequired"`
+	Assignable       bool   `json:"assignable"` // Can be added to the user's own tokens
+	RPMLimit         *int   `json:"rpmLimit"`   // NULL = uncapped
+}
+
+// TokenAllowance describes how many more tokens the user can create
+type TokenAllowance struct {
+	Active    int  `json:"active"`
+	Max       int  `json:"max"`
+	CanCreate bool `json:"canCreate"`
+}
+
+// UserPermissions is the evaluated authorization state of a user, returned in
+// one call so the frontend does not have to probe endpoints for 403s
+type UserPermissions struct {
+	Role        Role                `json:"role"`
+	Permissions []Permission        `json:"permissions"`
+	Features    []FeaturePermission `json:"features"`
+	Tokens      TokenAllowance      `json:"tokens"`
+	MaxSessions int                 `json:"maxSessions"` // 0 means unlimited
+}
+
+// EvaluatePermissions computes a user's permissions, reachable features with
+// their effective RPM, and token allowance
+func EvaluatePermissions(ctx context.Context, user *User, repo *Repository, features *FeatureRegistry, quota *QuotaEngine) (*UserPermissions, error) {
+	all, err := features.GetAllFeatures(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reachable := make([]FeaturePermission, 0, len(all))
+	for _, f := range all {
+		if f.AdminOnly && user.Role != RoleAdmin {
+			continue
+		}
+		rpm, err := quota.GetEffectiveRPM(ctx, user.ID, f.ID)
+		if err != nil {
+			return nil, err
+		}
+		assignable := !f.AdminOnly
+		if assignable && f.ApprovalRequired {
+			if assignable, err = features.HasApprovedAccess(ctx, user.ID, f.ID); err != nil {
+				return nil, err
+			}
+		}
+		fp := FeaturePermission{
+			ID:               f.ID,
+			Slug:             f.Slug,
+			Name:             f.Name,
+			ParentID:         f.ParentID,
+			AdminOnly:        f.AdminOnly,
+			ApprovalRequired: f.ApprovalRequired,
+			Assignable:       assignable,
+		}
+		if rpm != UnlimitedRPM {
+			fp.RPMLimit = &rpm
+		}
+		reachable = append(reachable, fp)
+	}
+
+	count, err := repo.GetUserTokenCount(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := &UserPermissions{
+		Role:        user.Role,
+		Permissions: PermissionsForRole(user.Role),
+		Features:    reachable,
+		Tokens: TokenAllowance{
+			Active:    count,
+			Max:       user.MaxTokens,
+			CanCreate: count < user.MaxTokens,
+		},
+	}
+	if user.Group != nil {
+		permissions.MaxSessions = user.Group.MaxSessions
+	}
+	return permissions, nil
+}
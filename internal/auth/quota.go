@@ -1,7 +1,12 @@
 package auth
 
 import (
+	"API/internal/events"
+	"context"
 	"database/sql"
+	"log"
+	"sync"
+	"time"
 )
 
 const (
@@ -10,28 +15,76 @@ const (
 
 	// UnlimitedRPM indicates no rate limit
 	UnlimitedRPM = -1
+
+	// QuotaExceededInterval is the minimum time between quota.exceeded events for the same user and feature
+	QuotaExceededInterval = 5 * time.Minute
 )
 
 // QuotaEngine calculates effective rate limits for users
 type QuotaEngine struct {
 	repo     *Repository
 	features *FeatureRegistry
+	surges   *SurgeSchedule
+
+	// Last quota.exceeded event per user and feature, to throttle them
+	mu           sync.Mutex
+	lastExceeded map[quotaKey]time.Time
 }
 
-// NewQuotaEngine creates a new quota engine
-func NewQuotaEngine(repo *Repository, features *FeatureRegistry) *QuotaEngine {
+type quotaKey struct {
+	userID    int64
+	featureID int64
+}
+
+// NewQuotaEngine creates a new quota engine. surges may be nil, in which case
+// surge windows are not applied.
+func NewQuotaEngine(repo *Repository, features *FeatureRegistry, surges *SurgeSchedule) *QuotaEngine {
 	return &QuotaEngine{
-		repo:     repo,
-		features: features,
+		repo:         repo,
+		features:     features,
+		surges:       surges,
+		lastExceeded: make(map[quotaKey]time.Time),
+	}
+}
+
+// RecordExceeded records a quota.exceeded event when a user is rate limited.
+// Events are throttled to one per QuotaExceededInterval for each user and
+// feature so that a client hammering the API does not flood subscribers.
+func (q *QuotaEngine) RecordExceeded(ctx context.Context, userID int64, feature *Feature, limit int) {
+	now := time.Now()
+	key := quotaKey{userID: userID, featureID: feature.ID}
+
+	q.mu.Lock()
+	if last, ok := q.lastExceeded[key]; ok && now.Sub(last) < QuotaExceededInterval {
+		q.mu.Unlock()
+		return
+	}
+	q.lastExceeded[key] = now
+	for k, last := range q.lastExceeded {
+		if now.Sub(last) >= QuotaExceededInterval {
+			delete(q.lastExceeded, k)
+		}
+	}
+	q.mu.Unlock()
+
+	if err := q.repo.enqueueEvent(ctx, events.QuotaExceeded{
+		UserID:      userID,
+		FeatureID:   feature.ID,
+		FeatureSlug: feature.Slug,
+		LimitRPM:    limit,
+		OccurredAt:  now,
+	}); err != nil {
+		log.Printf("Failed to record quota.exceeded for user %d: %v", userID, err)
 	}
 }
 
 // GetEffectiveRPM returns the effective RPM limit for a user on a feature.
 // Priority: user override > group quota > parent feature quota > system default
+// Group limits are multiplied while a surge window for the group is active.
 // Returns UnlimitedRPM (-1) if the quota is uncapped (NULL in database)
-func (q *QuotaEngine) GetEffectiveRPM(userID int64, featureID int64) (int, error) {
+func (q *QuotaEngine) GetEffectiveRPM(ctx context.Context, userID int64, featureID int64) (int, error) {
 	// 1. Check user override for this feature
-	rpm, found, err := q.getUserOverride(userID, featureID)
+	rpm, found, err := q.getUserOverride(ctx, userID, featureID)
 	if err != nil {
 		return 0, err
 	}
@@ -40,7 +93,7 @@ func (q *QuotaEngine) GetEffectiveRPM(userID int64, featureID int64) (int, error
 	}
 
 	// 2. Get user's group
-	user, err := q.repo.GetUserByID(userID)
+	user, err := q.repo.GetUserByID(ctx, userID)
 	if err != nil {
 		return 0, err
 	}
@@ -49,25 +102,26 @@ func (q *QuotaEngine) GetEffectiveRPM(userID int64, featureID int64) (int, error
 	}
 
 	// 3. Get feature ancestry (including the feature itself)
-	ancestors, err := q.features.GetFeatureAncestors(featureID)
+	ancestors, err := q.features.GetFeatureAncestors(ctx, featureID)
 	if err != nil {
 		return 0, err
 	}
 
 	// 4. Check group quota for each feature in the ancestry (starting from most specific)
+	multiplier := q.surges.RPMMultiplier(user.GroupID)
 	for _, feature := range ancestors {
-		rpm, found, err := q.getGroupQuota(user.GroupID, feature.ID)
+		rpm, found, err := q.getGroupQuota(ctx, user.GroupID, feature.ID)
 		if err != nil {
 			return 0, err
 		}
 		if found {
-			return rpm, nil
+			return applySurge(rpm, multiplier), nil
 		}
 	}
 
 	// 5. Fall back to group's default RPM
 	if user.Group != nil {
-		return user.Group.DefaultRPM, nil
+		return applySurge(user.Group.DefaultRPM, multiplier), nil
 	}
 
 	// 6. Fall back to system default
@@ -75,20 +129,20 @@ func (q *QuotaEngine) GetEffectiveRPM(userID int64, featureID int64) (int, error
 }
 
 // GetEffectiveRPMBySlug is a convenience method that looks up the feature by slug
-func (q *QuotaEngine) GetEffectiveRPMBySlug(userID int64, featureSlug string) (int, error) {
-	feature, err := q.features.GetFeatureBySlug(featureSlug)
+func (q *QuotaEngine) GetEffectiveRPMBySlug(ctx context.Context, userID int64, featureSlug string) (int, error) {
+	feature, err := q.features.GetFeatureBySlug(ctx, featureSlug)
 	if err != nil {
 		return 0, err
 	}
 	if feature == nil {
 		return DefaultSystemRPM, nil
 	}
-	return q.GetEffectiveRPM(userID, feature.ID)
+	return q.GetEffectiveRPM(ctx, userID, feature.ID)
 }
 
-func (q *QuotaEngine) getUserOverride(userID int64, featureID int64) (rpm int, found bool, err error) {
+func (q *QuotaEngine) getUserOverride(ctx context.Context, userID int64, featureID int64) (rpm int, found bool, err error) {
 	var rpmLimit sql.NullInt64
-	err = q.repo.db.QueryRow(`
+	err = q.repo.db.QueryRowContext(ctx, `
 		SELECT rpm_limit FROM user_quota_overrides
 		WHERE user_id = ? AND feature_id = ?
 	`, userID, featureID).Scan(&rpmLimit)
@@ -107,9 +161,9 @@ func (q *QuotaEngine) getUserOverride(userID int64, featureID int64) (rpm int, f
 	return int(rpmLimit.Int64), true, nil
 }
 
-func (q *QuotaEngine) getGroupQuota(groupID int64, featureID int64) (rpm int, found bool, err error) {
+func (q *QuotaEngine) getGroupQuota(ctx context.Context, groupID int64, featureID int64) (rpm int, found bool, err error) {
 	var rpmLimit sql.NullInt64
-	err = q.repo.db.QueryRow(`
+	err = q.repo.db.QueryRowContext(ctx, `
 		SELECT rpm_limit FROM group_feature_quotas
 		WHERE group_id = ? AND feature_id = ?
 	`, groupID, featureID).Scan(&rpmLimit)
@@ -130,8 +184,8 @@ func (q *QuotaEngine) getGroupQuota(groupID int64, featureID int64) (rpm int, fo
 
 // SetUserQuotaOverride sets a quota override for a user on a feature
 // Pass nil for rpmLimit to set uncapped (unlimited)
-func (q *QuotaEngine) SetUserQuotaOverride(userID int64, featureID int64, rpmLimit *int) error {
-	_, err := q.repo.db.Exec(`
+func (q *QuotaEngine) SetUserQuotaOverride(ctx context.Context, userID int64, featureID int64, rpmLimit *int) error {
+	_, err := q.repo.db.ExecContext(ctx, `
 		INSERT INTO user_quota_overrides (user_id, feature_id, rpm_limit)
 		VALUES (?, ?, ?)
 		ON CONFLICT (user_id, feature_id) DO UPDATE SET rpm_limit = ?
@@ -140,16 +194,16 @@ func (q *QuotaEngine) SetUserQuotaOverride(userID int64, featureID int64, rpmLim
 }
 
 // DeleteUserQuotaOverride removes a quota override
-func (q *QuotaEngine) DeleteUserQuotaOverride(userID int64, featureID int64) error {
-	_, err := q.repo.db.Exec(`
+func (q *QuotaEngine) DeleteUserQuotaOverride(ctx context.Context, userID int64, featureID int64) error {
+	_, err := q.repo.db.ExecContext(ctx, `
 		DELETE FROM user_quota_overrides WHERE user_id = ? AND feature_id = ?
 	`, userID, featureID)
 	return err
 }
 
 // GetUserQuotaOverrides returns all quota overrides for a user
-func (q *QuotaEngine) GetUserQuotaOverrides(userID int64) ([]UserQuotaOverride, error) {
-	rows, err := q.repo.db.Query(`
+func (q *QuotaEngine) GetUserQuotaOverrides(ctx context.Context, userID int64) ([]UserQuotaOverride, error) {
+	rows, err := q.repo.db.QueryContext(ctx, `
 		SELECT user_id, feature_id, rpm_limit
 		FROM user_quota_overrides WHERE user_id = ?
 	`, userID)
@@ -172,8 +226,8 @@ func (q *QuotaEngine) GetUserQuotaOverrides(userID int64) ([]UserQuotaOverride,
 }
 
 // SetGroupFeatureQuota sets a quota for a group on a feature
-func (q *QuotaEngine) SetGroupFeatureQuota(groupID int64, featureID int64, rpmLimit *int) error {
-	_, err := q.repo.db.Exec(`
+func (q *QuotaEngine) SetGroupFeatureQuota(ctx context.Context, groupID int64, featureID int64, rpmLimit *int) error {
+	_, err := q.repo.db.ExecContext(ctx, `
 		INSERT INTO group_feature_quotas (group_id, feature_id, rpm_limit)
 		VALUES (?, ?, ?)
 		ON CONFLICT (group_id, feature_id) DO UPDATE SET rpm_limit = ?
@@ -182,16 +236,16 @@ func (q *QuotaEngine) SetGroupFeatureQuota(groupID int64, featureID int64, rpmLi
 }
 
 // DeleteGroupFeatureQuota removes a quota for a group on a feature
-func (q *QuotaEngine) DeleteGroupFeatureQuota(groupID int64, featureID int64) error {
-	_, err := q.repo.db.Exec(`
+func (q *QuotaEngine) DeleteGroupFeatureQuota(ctx context.Context, groupID int64, featureID int64) error {
+	_, err := q.repo.db.ExecContext(ctx, `
 		DELETE FROM group_feature_quotas WHERE group_id = ? AND feature_id = ?
 	`, groupID, featureID)
 	return err
 }
 
 // GetGroupFeatureQuotas returns all quotas for a group
-func (q *QuotaEngine) GetGroupFeatureQuotas(groupID int64) ([]GroupFeatureQuota, error) {
-	rows, err := q.repo.db.Query(`
+func (q *QuotaEngine) GetGroupFeatureQuotas(ctx context.Context, groupID int64) ([]GroupFeatureQuota, error) {
+	rows, err := q.repo.db.QueryContext(ctx, `
 		SELECT group_id, feature_id, rpm_limit
 		FROM group_feature_quotas WHERE group_id = ?
 	`, groupID)
@@ -214,45 +268,35 @@ func (q *QuotaEngine) GetGroupFeatureQuotas(groupID int64) ([]GroupFeatureQuota,
 }
 
 // BulkSetGroupFeatureQuotas sets multiple quotas for a group at once
-func (q *QuotaEngine) BulkSetGroupFeatureQuotas(groupID int64, quotas []QuotaEntry) error {
-	tx, err := q.repo.db.Begin()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	for _, entry := range quotas {
-		_, err := tx.Exec(`
-			INSERT INTO group_feature_quotas (group_id, feature_id, rpm_limit)
-			VALUES (?, ?, ?)
-			ON CONFLICT (group_id, feature_id) DO UPDATE SET rpm_limit = ?
-		`, groupID, entry.FeatureID, entry.RPMLimit, entry.RPMLimit)
-		if err != nil {
-			return err
+func (q *QuotaEngine) BulkSetGroupFeatureQuotas(ctx context.Context, groupID int64, quotas []QuotaEntry) error {
+	return q.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		for _, entry := range quotas {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO group_feature_quotas (group_id, feature_id, rpm_limit)
+				VALUES (?, ?, ?)
+				ON CONFLICT (group_id, feature_id) DO UPDATE SET rpm_limit = ?
+			`, groupID, entry.FeatureID, entry.RPMLimit, entry.RPMLimit)
+			if err != nil {
+				return err
+			}
 		}
-	}
-
-	return tx.Commit()
+		return nil
+	})
 }
 
 // BulkSetUserQuotaOverrides sets multiple quota overrides for a user at once
-func (q *QuotaEngine) BulkSetUserQuotaOverrides(userID int64, quotas []QuotaEntry) error {
-	tx, err := q.repo.db.Begin()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	for _, entry := range quotas {
-		_, err := tx.Exec(`
-			INSERT INTO user_quota_overrides (user_id, feature_id, rpm_limit)
-			VALUES (?, ?, ?)
-			ON CONFLICT (user_id, feature_id) DO UPDATE SET rpm_limit = ?
-		`, userID, entry.FeatureID, entry.RPMLimit, entry.RPMLimit)
-		if err != nil {
-			return err
+func (q *QuotaEngine) BulkSetUserQuotaOverrides(ctx context.Context, userID int64, quotas []QuotaEntry) error {
+	return q.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		for _, entry := range quotas {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO user_quota_overrides (user_id, feature_id, rpm_limit)
+				VALUES (?, ?, ?)
+				ON CONFLICT (user_id, feature_id) DO UPDATE SET rpm_limit = ?
+			`, userID, entry.FeatureID, entry.RPMLimit, entry.RPMLimit)
+			if err != nil {
+				return err
+			}
 		}
-	}
-
-	return tx.Commit()
+		return nil
+	})
 }
@@ -1,41 +1,84 @@
 package auth
 
 import (
+	"net/http"
+
+	"API/internal/limits"
+	"API/internal/negotiate"
+
 	"github.com/gin-gonic/gin"
 )
 
+// JSON:API types of the nested objects auth responses carry
+func init() {
+	negotiate.RegisterResourceType("group", "groups")
+	negotiate.RegisterResourceType("children", "features")
+}
+
 // RegisterRoutes registers all auth-related routes
 func RegisterRoutes(
 	router *gin.RouterGroup,
 	handler *Handler,
 	adminHandler *AdminHandler,
+	staffHandler *StaffHandler,
 	middleware *Middleware,
 ) {
+	// Public feature catalog for third-party developers
+	router.GET("/features", handler.FeatureCatalog)
+
 	auth := router.Group("/auth")
 	{
 		// Public OAuth routes
 		auth.GET("/login/:provider", handler.Login)
 		auth.GET("/callback/:provider", handler.Callback)
 
+		// Public catalog of assignable features
+		auth.GET("/features/tree", handler.GetAssignableFeatureTree)
+
 		// Session-protected routes
 		sessionProtected := auth.Group("")
 		sessionProtected.Use(middleware.RequireSession())
+		sessionProtected.Use(middleware.Idempotent())
 		{
 			sessionProtected.GET("/me", handler.Me)
+			sessionProtected.GET("/me/permissions", handler.MyPermissions)
+			sessionProtected.GET("/me/preferences", handler.GetMyPreferences)
+			sessionProtected.PUT("/me/preferences", handler.SetMyPreferences)
 			sessionProtected.GET("/logout", handler.Logout)
 
 			// Token management
 			sessionProtected.GET("/tokens", handler.ListTokens)
 			sessionProtected.GET("/tokens/features", handler.ListAssignableFeatures)
 			sessionProtected.POST("/tokens", handler.CreateToken)
+			sessionProtected.PATCH("/tokens/:id", handler.UpdateToken)
 			sessionProtected.DELETE("/tokens/:id", handler.RevokeToken)
+
+			// Access to features that require approval
+			sessionProtected.GET("/access-requests", handler.ListAccessRequests)
+			sessionProtected.POST("/access-requests", handler.RequestAccess)
 		}
 	}
 
+	// Staff routes (course workspaces; admins pass RequireRole too)
+	staff := router.Group("/staff")
+	staff.Use(middleware.RequireSession())
+	staff.Use(middleware.RequireRole(RoleStaff))
+	staff.Use(middleware.Idempotent())
+	{
+		staff.GET("/workspaces", staffHandler.ListWorkspaces)
+		staff.POST("/workspaces", staffHandler.CreateWorkspace)
+		staff.GET("/workspaces/:id", staffHandler.GetWorkspace)
+		staff.DELETE("/workspaces/:id", staffHandler.DeleteWorkspace)
+		staff.GET("/workspaces/:id/usage", staffHandler.GetWorkspaceUsage)
+		staff.POST("/workspaces/:id/students", staffHandler.EnrollStudents)
+		staff.DELETE("/workspaces/:id/students/:userId", staffHandler.RemoveStudent)
+	}
+
 	// Admin routes
 	admin := router.Group("/admin")
 	admin.Use(middleware.RequireSession())
 	admin.Use(middleware.RequireRole(RoleAdmin))
+	admin.Use(middleware.Idempotent())
 	{
 		// Group management
 		admin.GET("/groups", adminHandler.ListGroups)
@@ -43,25 +86,53 @@ func RegisterRoutes(
 		admin.GET("/groups/:id", adminHandler.GetGroup)
 		admin.PATCH("/groups/:id", adminHandler.UpdateGroup)
 		admin.DELETE("/groups/:id", adminHandler.DeleteGroup)
+		admin.POST("/groups/:id/restore", adminHandler.RestoreGroup)
 		admin.GET("/groups/:id/quotas", adminHandler.GetGroupQuotas)
 		admin.PUT("/groups/:id/quotas", adminHandler.SetGroupQuotas)
 
 		// Feature management
 		admin.GET("/features", adminHandler.ListFeatures)
+		admin.GET("/features/tree", adminHandler.GetFeatureTree)
+		admin.GET("/features/deprecated/tokens", adminHandler.ListDeprecatedFeatureTokens)
 		admin.POST("/features", adminHandler.CreateFeature)
 		admin.GET("/features/:id", adminHandler.GetFeature)
 		admin.PATCH("/features/:id", adminHandler.UpdateFeature)
 		admin.DELETE("/features/:id", adminHandler.DeleteFeature)
+		admin.GET("/features/:id/usage", adminHandler.GetFeatureUsage)
+
+		// Feature access requests
+		admin.GET("/access-requests", adminHandler.ListAccessRequests)
+		admin.POST("/access-requests/:id/approve", adminHandler.ApproveAccessRequest)
+		admin.POST("/access-requests/:id/deny", adminHandler.DenyAccessRequest)
+		admin.POST("/access-requests/:id/revoke", adminHandler.RevokeAccessRequest)
+
+		// Surge windows (exam weeks, registration days)
+		admin.GET("/surges", adminHandler.ListSurgeWindows)
+		admin.POST("/surges", adminHandler.CreateSurgeWindow)
+		admin.PUT("/surges/:id", adminHandler.UpdateSurgeWindow)
+		admin.DELETE("/surges/:id", adminHandler.DeleteSurgeWindow)
 
 		// Academic domain management
 		admin.GET("/academic-domains", adminHandler.ListAcademicDomains)
 		admin.POST("/academic-domains", adminHandler.AddAcademicDomain)
+		admin.POST("/academic-domains/import", adminHandler.ImportAcademicDomains)
+		limits.SetRoute(http.MethodPost, admin.BasePath()+"/academic-domains/import", limits.Limits{MaxBodyBytes: 10 << 20})
 		admin.DELETE("/academic-domains/:domain", adminHandler.RemoveAcademicDomain)
 
+		// Invitation management
+		admin.GET("/invitations", adminHandler.ListInvitations)
+		admin.POST("/invitations", adminHandler.CreateInvitation)
+		admin.DELETE("/invitations/:id", adminHandler.DeleteInvitation)
+
 		// User management
 		admin.GET("/users", adminHandler.ListUsers)
 		admin.GET("/users/:id", adminHandler.GetUser)
 		admin.PATCH("/users/:id", adminHandler.UpdateUser)
+		admin.DELETE("/users/:id", adminHandler.DeleteUser)
+		admin.POST("/users/:id/restore", adminHandler.RestoreUser)
+		admin.POST("/users/:id/notes", adminHandler.AddUserNote)
+		admin.DELETE("/users/:id/notes/:noteId", adminHandler.DeleteUserNote)
+		admin.PUT("/users/:id/tags", adminHandler.SetUserTags)
 		admin.GET("/users/:id/quotas", adminHandler.GetUserQuotas)
 		admin.PUT("/users/:id/quotas", adminHandler.SetUserQuotas)
 		admin.GET("/users/:id/usage", adminHandler.GetUserUsage)
@@ -70,5 +141,29 @@ func RegisterRoutes(
 
 		// Token management (admin)
 		admin.DELETE("/tokens/:id", adminHandler.RevokeToken)
+		admin.POST("/tokens/:id/restore", adminHandler.RestoreToken)
+
+		// Dashboard statistics
+		admin.GET("/stats", adminHandler.GetStats)
+
+		// Outbound webhooks for auth events
+		admin.GET("/webhooks", adminHandler.ListWebhooks)
+		admin.POST("/webhooks", adminHandler.CreateWebhook)
+		admin.PATCH("/webhooks/:id", adminHandler.UpdateWebhook)
+		admin.DELETE("/webhooks/:id", adminHandler.DeleteWebhook)
+		admin.POST("/webhooks/:id/test", adminHandler.TestWebhook)
+
+		// Diagnostics
+		admin.GET("/diagnostics/requests/:id", adminHandler.GetRequestDiagnostic)
+		admin.GET("/errors", adminHandler.ListErrors)
+		admin.GET("/token-throttle", adminHandler.ListThrottledAddresses)
+		admin.DELETE("/token-throttle/:ip", adminHandler.LiftThrottle)
+
+		// Request sampling, to reproduce client-reported bugs
+		admin.GET("/sampling/rules", adminHandler.ListSamplingRules)
+		admin.POST("/sampling/rules", adminHandler.CreateSamplingRule)
+		admin.DELETE("/sampling/rules/:id", adminHandler.DeleteSamplingRule)
+		admin.GET("/sampling/requests", adminHandler.ListSampledRequests)
+		admin.GET("/sampling/requests/:id", adminHandler.GetSampledRequest)
 	}
 }
@@ -0,0 +1,434 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"API/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// SamplingRetention is how long sampled requests are kept
+	SamplingRetention = time.Hour
+
+	// SamplingMaxEntries bounds the memory sampled requests take
+	SamplingMaxEntries = 1000
+
+	// SamplingMaxBody caps each recorded body; the rest is dropped
+	SamplingMaxBody = 64 << 10
+
+	// MaxSamplingRuleDuration is the longest a sampling rule may run, so one left
+	// behind after debugging stops recording on its own
+	MaxSamplingRuleDuration = 24 * time.Hour
+
+	// redacted replaces the values of sensitive fields and headers
+	redacted = "[REDACTED]"
+)
+
+// sensitiveHeaders are never recorded
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// sensitiveField reports whether a JSON field, form field or query parameter holds a
+// secret, e.g. an eClass password or key, a token or a webhook secret
+func sensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, part := range []string{"password", "secret", "token", "apikey", "api_key"} {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return name == "key"
+}
+
+// SamplingRule records a share of the requests to a feature, of a user, or of a user to a
+// feature, until it expires
+type SamplingRule struct {
+	ID        int64     `json:"id"`
+	Feature   string    `json:"feature,omitempty"`
+	UserID    *int64    `json:"userId,omitempty"`
+	Rate      float64   `json:"rate"`
+	CreatedBy *int64    `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// SamplingRuleRequest is the body admins send to start sampling
+type SamplingRuleRequest struct {
+	Feature string   `json:"feature" binding:"omitempty,max=100"`
+	UserID  *int64   `json:"userId"`
+	Rate    *float64 `json:"rate" binding:"omitempty,gt=0,lte=1"` // Share of the matching requests; defaults to 1
+	// Minutes the rule runs for; defaults to 60, at most MaxSamplingRuleDuration
+	DurationMinutes int `json:"durationMinutes" binding:"omitempty,min=1"`
+}
+
+// SampledRequest is a request recorded by a sampling rule, with its secrets redacted
+type SampledRequest struct {
+	RequestID       string            `json:"requestId"`
+	RuleID          int64             `json:"ruleId"`
+	Timestamp       time.Time         `json:"timestamp"`
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	Query           string            `json:"query,omitempty"`
+	Route           string            `json:"route"`
+	Feature         string            `json:"feature,omitempty"`
+	UserID          *int64            `json:"userId,omitempty"`
+	TokenID         *int64            `json:"tokenId,omitempty"`
+	Status          int               `json:"status"`
+	LatencyMs       float64           `json:"latencyMs"`
+	RequestHeaders  map[string]string `json:"requestHeaders"`
+	RequestBody     string            `json:"requestBody,omitempty"`
+	ResponseHeaders map[string]string `json:"responseHeaders"`
+	ResponseBody    string            `json:"responseBody,omitempty"`
+	Truncated       bool              `json:"truncated"` // A body was longer than SamplingMaxBody
+}
+
+// SamplingStore holds the sampling rules admins set to reproduce client-reported bugs,
+// and the requests they recorded, in memory for SamplingRetention
+type SamplingStore struct {
+	mu      sync.Mutex
+	rules   []SamplingRule
+	nextID  int64
+	entries map[string]*SampledRequest
+	order   []diagnosticsEntry // insertion order == expiry order
+}
+
+// NewSamplingStore creates an empty sampling store
+func NewSamplingStore() *SamplingStore {
+	return &SamplingStore{entries: make(map[string]*SampledRequest)}
+}
+
+// AddRule starts sampling the requests a rule matches
+func (s *SamplingStore) AddRule(req SamplingRuleRequest, createdBy *int64) (SamplingRule, error) {
+	if req.Feature == "" && req.UserID == nil {
+		return SamplingRule{}, errors.New("a feature, a user or both are required")
+	}
+	rate := 1.0
+	if req.Rate != nil {
+		rate = *req.Rate
+	}
+	duration := time.Hour
+	if req.DurationMinutes > 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if duration > MaxSamplingRuleDuration {
+		return SamplingRule{}, errors.New("a sampling rule may run for at most 24 hours")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	now := time.Now().UTC()
+	rule := SamplingRule{
+		ID:        s.nextID,
+		Feature:   req.Feature,
+		UserID:    req.UserID,
+		Rate:      rate,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		ExpiresAt: now.Add(duration),
+	}
+	s.rules = append(s.rules, rule)
+	return rule, nil
+}
+
+// Rules returns the rules still running
+func (s *SamplingStore) Rules() []SamplingRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneRulesLocked(time.Now())
+	return append([]SamplingRule{}, s.rules...)
+}
+
+// DeleteRule stops a rule; the requests it recorded are kept until they expire
+func (s *SamplingStore) DeleteRule(id int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, rule := range s.rules {
+		if rule.ID == id {
+			s.rules = append(s.rules[:i], s.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns a sampled request by request ID, or nil if unknown or expired
+func (s *SamplingStore) Get(requestID string) *SampledRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	return s.entries[requestID]
+}
+
+// List returns the sampled requests matching the filters, newest first, without their
+// headers and bodies
+func (s *SamplingStore) List(feature string, userID *int64, limit int) []SampledRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	list := []SampledRequest{}
+	for i := len(s.order) - 1; i >= 0 && len(list) < limit; i-- {
+		e := s.entries[s.order[i].requestID]
+		if e == nil || feature != "" && e.Feature != feature || userID != nil && (e.UserID == nil || *e.UserID != *userID) {
+			continue
+		}
+		summary := *e
+		summary.RequestHeaders, summary.RequestBody = nil, ""
+		summary.ResponseHeaders, summary.ResponseBody = nil, ""
+		list = append(list, summary)
+	}
+	return list
+}
+
+// active reports whether any rule runs, so requests are only buffered while one does
+func (s *SamplingStore) active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneRulesLocked(time.Now())
+	return len(s.rules) > 0
+}
+
+// match returns the rule sampling a request, after drawing it at the rule's rate
+func (s *SamplingStore) match(feature string, userID *int64) (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rule := range s.rules {
+		if rule.Feature != "" && rule.Feature != feature {
+			continue
+		}
+		if rule.UserID != nil && (userID == nil || *rule.UserID != *userID) {
+			continue
+		}
+		if rand.Float64() < rule.Rate {
+			return rule.ID, true
+		}
+	}
+	return 0, false
+}
+
+func (s *SamplingStore) record(e *SampledRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	for len(s.order) >= SamplingMaxEntries {
+		delete(s.entries, s.order[0].requestID)
+		s.order = s.order[1:]
+	}
+	s.entries[e.RequestID] = e
+	s.order = append(s.order, diagnosticsEntry{requestID: e.RequestID, expiresAt: time.Now().Add(SamplingRetention)})
+}
+
+func (s *SamplingStore) pruneLocked(now time.Time) {
+	i := 0
+	for i < len(s.order) && s.order[i].expiresAt.Before(now) {
+		delete(s.entries, s.order[i].requestID)
+		i++
+	}
+	s.order = s.order[i:]
+}
+
+func (s *SamplingStore) pruneRulesLocked(now time.Time) {
+	rules := s.rules[:0]
+	for _, rule := range s.rules {
+		if rule.ExpiresAt.After(now) {
+			rules = append(rules, rule)
+		}
+	}
+	s.rules = rules
+}
+
+// Middleware records the requests the sampling rules match, with their bodies. While no
+// rule runs it only checks that none does. The feature and user are those the token or
+// session middleware of the route found, so it goes before them.
+func (s *SamplingStore) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.active() || c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		requestBody := &cappedBuffer{}
+		if c.Request.Body != nil {
+			c.Request.Body = &teeReadCloser{Reader: io.TeeReader(c.Request.Body, requestBody), Closer: c.Request.Body}
+		}
+		writer := &samplingResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		feature := c.GetString(ContextKeyFeature)
+		var userID, tokenID *int64
+		if user := GetUserFromContext(c); user != nil {
+			userID = &user.ID
+		}
+		if token := GetTokenFromContext(c); token != nil {
+			tokenID = &token.ID
+		}
+		ruleID, ok := s.match(feature, userID)
+		if !ok {
+			return
+		}
+
+		s.record(&SampledRequest{
+			RequestID:       logging.RequestID(c),
+			RuleID:          ruleID,
+			Timestamp:       start.UTC(),
+			Method:          c.Request.Method,
+			Path:            c.Request.URL.Path,
+			Query:           sanitizeQuery(c.Request.URL.RawQuery),
+			Route:           c.FullPath(),
+			Feature:         feature,
+			UserID:          userID,
+			TokenID:         tokenID,
+			Status:          writer.Status(),
+			LatencyMs:       float64(time.Since(start).Microseconds()) / 1000,
+			RequestHeaders:  sanitizeHeaders(c.Request.Header),
+			RequestBody:     sanitizeBody(c.Request.Header.Get("Content-Type"), requestBody.Bytes()),
+			ResponseHeaders: sanitizeHeaders(writer.Header()),
+			ResponseBody:    sanitizeBody(writer.Header().Get("Content-Type"), writer.body.Bytes()),
+			Truncated:       requestBody.truncated || writer.body.truncated,
+		})
+	}
+}
+
+// cappedBuffer keeps the first SamplingMaxBody bytes written to it
+type cappedBuffer struct {
+	bytes.Buffer
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	room := SamplingMaxBody - b.Len()
+	if len(p) > room {
+		b.truncated = true
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// samplingResponseWriter keeps the start of the response body
+type samplingResponseWriter struct {
+	gin.ResponseWriter
+	body cappedBuffer
+}
+
+func (w *samplingResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *samplingResponseWriter) WriteString(s string) (int, error) {
+	w.body.Write([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func sanitizeHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			headers[name] = redacted
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+func sanitizeQuery(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return redacted
+	}
+	return sanitizeValues(values).Encode()
+}
+
+func sanitizeValues(values url.Values) url.Values {
+	for name := range values {
+		if sensitiveField(name) {
+			values[name] = []string{redacted}
+		}
+	}
+	return values
+}
+
+// sanitizeBody redacts the secrets of JSON and form bodies. Other bodies, e.g. uploaded
+// images, are not recorded.
+func sanitizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			// Cut short or malformed, so its fields cannot be told apart
+			return "[" + strconv.Itoa(len(body)) + " bytes of unparsable JSON omitted]"
+		}
+		sanitized, _ := json.Marshal(redactJSON(value))
+		return string(sanitized)
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "[" + strconv.Itoa(len(body)) + " bytes of unparsable form omitted]"
+		}
+		return sanitizeValues(values).Encode()
+	case strings.HasPrefix(mediaType, "text/") && mediaType != "text/event-stream":
+		return string(body)
+	}
+	return "[" + strconv.Itoa(len(body)) + " bytes of " + mediaType + " omitted]"
+}
+
+// redactJSON replaces the values of sensitive fields, at any depth
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveField(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactJSON(field)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	}
+	return value
+}
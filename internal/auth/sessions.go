@@ -1,6 +1,10 @@
 package auth
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -16,33 +20,87 @@ const (
 	DefaultSessionDuration = 7 * 24 * time.Hour // 7 days
 )
 
+// SessionLimitPolicy decides what happens when a login would exceed the
+// group's concurrent session limit
+type SessionLimitPolicy string
+
+const (
+	// SessionLimitEvict ends the user's oldest sessions to make room
+	SessionLimitEvict SessionLimitPolicy = "evict"
+	// SessionLimitReject refuses the new login until a session is logged out
+	SessionLimitReject SessionLimitPolicy = "reject"
+)
+
+// ErrSessionLimitReached is returned by CreateSession under SessionLimitReject
+var ErrSessionLimitReached = errors.New("concurrent session limit reached")
+
 // SessionStore manages server-side sessions
 type SessionStore struct {
 	repo            *Repository
 	sessionDuration time.Duration
 	secureCookie    bool
+	limitPolicy     SessionLimitPolicy
 }
 
 // NewSessionStore creates a new session store
-func NewSessionStore(repo *Repository, sessionDuration time.Duration, secureCookie bool) *SessionStore {
+func NewSessionStore(repo *Repository, sessionDuration time.Duration, secureCookie bool, limitPolicy SessionLimitPolicy) *SessionStore {
 	if sessionDuration == 0 {
 		sessionDuration = DefaultSessionDuration
 	}
+	if limitPolicy != SessionLimitReject {
+		limitPolicy = SessionLimitEvict
+	}
 	return &SessionStore{
 		repo:            repo,
 		sessionDuration: sessionDuration,
 		secureCookie:    secureCookie,
+		limitPolicy:     limitPolicy,
 	}
 }
 
-// CreateSession creates a new session for a user
-func (s *SessionStore) CreateSession(userID int64) (*Session, error) {
+// CreateSession creates a new session for a user, enforcing the concurrent
+// session limit of the user's group
+func (s *SessionStore) CreateSession(ctx context.Context, userID int64) (*Session, error) {
 	sessionID := uuid.New().String()
-	expiresAt := time.Now().Add(s.sessionDuration)
-
-	_, err := s.repo.db.Exec(`
-		INSERT INTO sessions (id, user_id, expires_at) VALUES (?, ?, ?)
-	`, sessionID, userID, expiresAt)
+	now := time.Now()
+	expiresAt := now.Add(s.sessionDuration)
+
+	err := s.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		var maxSessions, active int
+		err := tx.QueryRowContext(ctx, `
+			SELECT g.max_sessions,
+			       (SELECT COUNT(*) FROM sessions WHERE user_id = u.id AND expires_at > ?)
+			FROM users u
+			JOIN groups g ON u.group_id = g.id
+			WHERE u.id = ?
+		`, now, userID).Scan(&maxSessions, &active)
+		if err != nil {
+			return err
+		}
+
+		if maxSessions > 0 && active >= maxSessions {
+			if s.limitPolicy == SessionLimitReject {
+				return fmt.Errorf("%w: %d active sessions allowed, log out of another device first", ErrSessionLimitReached, maxSessions)
+			}
+			// Keep the newest maxSessions-1 sessions so the new one fits
+			_, err = tx.ExecContext(ctx, `
+				DELETE FROM sessions WHERE id IN (
+					SELECT id FROM sessions
+					WHERE user_id = ? AND expires_at > ?
+					ORDER BY created_at ASC, rowid ASC
+					LIMIT ?
+				)
+			`, userID, now, active-maxSessions+1)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO sessions (id, user_id, expires_at) VALUES (?, ?, ?)
+		`, sessionID, userID, expiresAt)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -51,14 +109,14 @@ func (s *SessionStore) CreateSession(userID int64) (*Session, error) {
 		ID:        sessionID,
 		UserID:    userID,
 		ExpiresAt: expiresAt,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
 	}, nil
 }
 
 // GetSession returns a session if it exists and is not expired
-func (s *SessionStore) GetSession(sessionID string) (*Session, error) {
+func (s *SessionStore) GetSession(ctx context.Context, sessionID string) (*Session, error) {
 	var session Session
-	err := s.repo.db.QueryRow(`
+	err := s.repo.db.QueryRowContext(ctx, `
 		SELECT id, user_id, expires_at, created_at
 		FROM sessions
 		WHERE id = ? AND expires_at > ?
@@ -70,29 +128,29 @@ func (s *SessionStore) GetSession(sessionID string) (*Session, error) {
 }
 
 // GetUserFromSession returns the user associated with a session
-func (s *SessionStore) GetUserFromSession(sessionID string) (*User, error) {
-	session, err := s.GetSession(sessionID)
+func (s *SessionStore) GetUserFromSession(ctx context.Context, sessionID string) (*User, error) {
+	session, err := s.GetSession(ctx, sessionID)
 	if err != nil {
 		return nil, err
 	}
-	return s.repo.GetUserByID(session.UserID)
+	return s.repo.GetUserByID(ctx, session.UserID)
 }
 
 // DeleteSession removes a session
-func (s *SessionStore) DeleteSession(sessionID string) error {
-	_, err := s.repo.db.Exec("DELETE FROM sessions WHERE id = ?", sessionID)
+func (s *SessionStore) DeleteSession(ctx context.Context, sessionID string) error {
+	_, err := s.repo.db.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", sessionID)
 	return err
 }
 
 // DeleteUserSessions removes all sessions for a user
-func (s *SessionStore) DeleteUserSessions(userID int64) error {
-	_, err := s.repo.db.Exec("DELETE FROM sessions WHERE user_id = ?", userID)
+func (s *SessionStore) DeleteUserSessions(ctx context.Context, userID int64) error {
+	_, err := s.repo.db.ExecContext(ctx, "DELETE FROM sessions WHERE user_id = ?", userID)
 	return err
 }
 
 // CleanupExpiredSessions removes all expired sessions
-func (s *SessionStore) CleanupExpiredSessions() error {
-	_, err := s.repo.db.Exec("DELETE FROM sessions WHERE expires_at <= ?", time.Now())
+func (s *SessionStore) CleanupExpiredSessions(ctx context.Context) error {
+	_, err := s.repo.db.ExecContext(ctx, "DELETE FROM sessions WHERE expires_at <= ?", time.Now())
 	return err
 }
 
@@ -131,9 +189,9 @@ func (s *SessionStore) GetSessionFromCookie(c *gin.Context) (string, error) {
 }
 
 // ExtendSession extends the session expiry time
-func (s *SessionStore) ExtendSession(sessionID string) error {
+func (s *SessionStore) ExtendSession(ctx context.Context, sessionID string) error {
 	expiresAt := time.Now().Add(s.sessionDuration)
-	_, err := s.repo.db.Exec(`
+	_, err := s.repo.db.ExecContext(ctx, `
 		UPDATE sessions SET expires_at = ? WHERE id = ?
 	`, expiresAt, sessionID)
 	return err
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	"API/internal/apierror"
+	"API/internal/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StaffHandler handles course workspace endpoints for instructors (staff role).
+// Staff only see their own workspaces; admins see all of them.
+type StaffHandler struct {
+	workspaces *WorkspaceStore
+}
+
+// NewStaffHandler creates a new staff handler
+func NewStaffHandler(workspaces *WorkspaceStore) *StaffHandler {
+	return &StaffHandler{workspaces: workspaces}
+}
+
+// ListWorkspaces returns the caller's course workspaces
+// GET /staff/workspaces
+func (h *StaffHandler) ListWorkspaces(c *gin.Context) {
+	user := GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+
+	var instructorID *int64
+	if user.Role != RoleAdmin {
+		instructorID = &user.ID
+	}
+
+	workspaces, err := h.workspaces.ListWorkspaces(c.Request.Context(), instructorID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list workspaces")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"workspaces": workspaces,
+	}))
+}
+
+// CreateWorkspace creates a course workspace owned by the caller
+// POST /staff/workspaces
+func (h *StaffHandler) CreateWorkspace(c *gin.Context) {
+	user := GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+
+	var req WorkspaceCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	workspace, err := h.workspaces.CreateWorkspace(c.Request.Context(), user.ID, req.Name, req.Features, req.EndsAt)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"workspace": workspace,
+	}))
+}
+
+// GetWorkspace returns a workspace with its students
+// GET /staff/workspaces/:id
+func (h *StaffHandler) GetWorkspace(c *gin.Context) {
+	workspace, ok := h.loadWorkspace(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.workspaces.GetMembers(c.Request.Context(), workspace.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get students")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"workspace": workspace,
+		"students":  members,
+	}))
+}
+
+// GetWorkspaceUsage returns the usage dashboard of a workspace's cohort
+// GET /staff/workspaces/:id/usage
+func (h *StaffHandler) GetWorkspaceUsage(c *gin.Context) {
+	workspace, ok := h.loadWorkspace(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.workspaces.GetMembers(c.Request.Context(), workspace.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get usage")))
+		return
+	}
+
+	var totalRequests, currentRPM, activeStudents int
+	for _, m := range members {
+		totalRequests += m.RequestCount
+		currentRPM += m.CurrentRPM
+		if m.RequestCount > 0 {
+			activeStudents++
+		}
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"workspaceId":    workspace.ID,
+		"endsAt":         workspace.EndsAt,
+		"students":       len(members),
+		"activeStudents": activeStudents,
+		"totalRequests":  totalRequests,
+		"currentRpm":     currentRPM,
+		"byStudent":      members,
+	}))
+}
+
+// EnrollStudents issues time-boxed tokens to students by email. The raw
+// tokens are only returned in this response.
+// POST /staff/workspaces/:id/students
+func (h *StaffHandler) EnrollStudents(c *gin.Context) {
+	workspace, ok := h.loadWorkspace(c)
+	if !ok {
+		return
+	}
+
+	var req WorkspaceStudentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	issued, missing, enrolled, err := h.workspaces.EnrollStudents(c.Request.Context(), workspace, req.Emails)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"message":         "Distribute these tokens to the students now - they will not be shown again.",
+		"issued":          issued,
+		"missing":         missing,
+		"alreadyEnrolled": enrolled,
+	}))
+}
+
+// RemoveStudent revokes a student's token and removes them from the workspace
+// DELETE /staff/workspaces/:id/students/:userId
+func (h *StaffHandler) RemoveStudent(c *gin.Context) {
+	workspace, ok := h.loadWorkspace(c)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid user ID")))
+		return
+	}
+
+	removed, err := h.workspaces.RemoveStudent(c.Request.Context(), workspace.ID, userID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to remove student")))
+		return
+	}
+	if !removed {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "student not enrolled")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "student removed",
+	}))
+}
+
+// DeleteWorkspace revokes all student tokens and deletes the workspace
+// DELETE /staff/workspaces/:id
+func (h *StaffHandler) DeleteWorkspace(c *gin.Context) {
+	workspace, ok := h.loadWorkspace(c)
+	if !ok {
+		return
+	}
+
+	if err := h.workspaces.DeleteWorkspace(c.Request.Context(), workspace.ID); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete workspace")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"message": "workspace deleted",
+	}))
+}
+
+// loadWorkspace resolves :id and checks the caller owns it (or is an admin).
+// It writes the error response and returns false on failure.
+func (h *StaffHandler) loadWorkspace(c *gin.Context) (*CourseWorkspace, bool) {
+	user := GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return nil, false
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid workspace ID")))
+		return nil, false
+	}
+
+	workspace, err := h.workspaces.GetWorkspace(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get workspace")))
+		return nil, false
+	}
+	// Someone else's workspace is reported as missing rather than forbidden
+	if workspace == nil || (user.Role != RoleAdmin && workspace.InstructorID != user.ID) {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "workspace not found")))
+		return nil, false
+	}
+	return workspace, true
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// AdminStats aggregates the numbers shown on the admin dashboard
+type AdminStats struct {
+	Users          UserStats      `json:"users"`
+	SignupsPerDay  []DailyCount   `json:"signupsPerDay"`
+	Tokens         TokenStats     `json:"tokens"`
+	ActiveSessions int            `json:"activeSessions"`
+	Groups         []GroupCount   `json:"groups"`
+	TopFeatures    []FeatureUsage `json:"topFeatures"`
+}
+
+// UserStats counts users by state
+type UserStats struct {
+	Total     int `json:"total"`
+	Active    int `json:"active"`
+	Suspended int `json:"suspended"`
+	Admins    int `json:"admins"`
+}
+
+// DailyCount is a count bucketed by calendar day (YYYY-MM-DD)
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// TokenStats counts tokens by state
+type TokenStats struct {
+	Issued       int `json:"issued"`
+	IssuedRecent int `json:"issuedRecent"`
+	Revoked      int `json:"revoked"`
+	Active       int `json:"active"`
+}
+
+// GroupCount is the number of users in a group
+type GroupCount struct {
+	GroupID   int64  `json:"groupId"`
+	Name      string `json:"name"`
+	UserCount int    `json:"userCount"`
+}
+
+// FeatureUsage ranks a feature by current traffic and token grants
+type FeatureUsage struct {
+	FeatureID          int64  `json:"featureId"`
+	Slug               string `json:"slug"`
+	RequestsLastMinute int    `json:"requestsLastMinute"`
+	ActiveTokens       int    `json:"activeTokens"`
+}
+
+// GetAdminStats computes dashboard statistics; days bounds the signup and
+// recent-token windows and topN the number of features returned
+func (r *Repository) GetAdminStats(ctx context.Context, days, topN int) (*AdminStats, error) {
+	now := time.Now()
+	since := now.AddDate(0, 0, -days)
+	stats := &AdminStats{
+		SignupsPerDay: []DailyCount{},
+		Groups:        []GroupCount{},
+		TopFeatures:   []FeatureUsage{},
+	}
+
+	// Users
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+		       COALESCE(SUM(status = ?), 0),
+		       COALESCE(SUM(status = ?), 0),
+		       COALESCE(SUM(role = ?), 0)
+		FROM users
+		WHERE deleted_at IS NULL
+	`, StatusActive, StatusSuspended, RoleAdmin).Scan(
+		&stats.Users.Total, &stats.Users.Active, &stats.Users.Suspended, &stats.Users.Admins,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Signups per day
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT date(created_at) AS day, COUNT(*)
+		FROM users
+		WHERE created_at >= ?
+		GROUP BY day
+		ORDER BY day
+	`, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var dc DailyCount
+		if err := rows.Scan(&dc.Date, &dc.Count); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stats.SignupsPerDay = append(stats.SignupsPerDay, dc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Tokens
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+		       COALESCE(SUM(created_at >= ?), 0),
+		       COALESCE(SUM(revoked_at IS NOT NULL), 0),
+		       COALESCE(SUM(revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)), 0)
+		FROM tokens
+	`, since.UTC().Format("2006-01-02 15:04:05"), now).Scan(
+		&stats.Tokens.Issued, &stats.Tokens.IssuedRecent, &stats.Tokens.Revoked, &stats.Tokens.Active,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sessions
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sessions WHERE expires_at > ?", now).Scan(&stats.ActiveSessions); err != nil {
+		return nil, err
+	}
+
+	// Per-group user counts
+	rows, err = r.db.QueryContext(ctx, `
+		SELECT g.id, g.name, COUNT(u.id)
+		FROM groups g
+		LEFT JOIN users u ON u.group_id = g.id
+		GROUP BY g.id
+		ORDER BY g.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var gc GroupCount
+		if err := rows.Scan(&gc.GroupID, &gc.Name, &gc.UserCount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stats.Groups = append(stats.Groups, gc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Top features by current traffic, then by active token grants
+	rows, err = r.db.QueryContext(ctx, `
+		SELECT f.id, f.slug,
+		       (SELECT COUNT(*) FROM usage_log ul WHERE ul.feature_id = f.id AND ul.timestamp > ?) AS requests,
+		       (SELECT COUNT(*) FROM token_features tf
+		        JOIN tokens t ON t.id = tf.token_id
+		        WHERE tf.feature_id = f.id AND t.revoked_at IS NULL AND (t.expires_at IS NULL OR t.expires_at > ?)) AS active_tokens
+		FROM features f
+		ORDER BY requests DESC, active_tokens DESC, f.slug
+		LIMIT ?
+	`, now.Add(-UsageRetentionPeriod), now, topN)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var fu FeatureUsage
+		if err := rows.Scan(&fu.FeatureID, &fu.Slug, &fu.RequestsLastMinute, &fu.ActiveTokens); err != nil {
+			return nil, err
+		}
+		stats.TopFeatures = append(stats.TopFeatures, fu)
+	}
+	return stats, rows.Err()
+}
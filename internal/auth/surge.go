@@ -0,0 +1,364 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"API/internal/health"
+)
+
+// SurgeRefreshInterval is how often surge windows are reloaded from the database
+const SurgeRefreshInterval = time.Minute
+
+// SurgeSchedule applies predefined surge windows. Windows that have not ended
+// are kept in memory, so the quota check on every request does not hit the
+// database, and are reloaded periodically and after every change.
+type SurgeSchedule struct {
+	repo *Repository
+
+	mu       sync.RWMutex
+	upcoming []SurgeWindow
+	active   map[int64]bool // Windows active at the last refresh, to log transitions
+
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	heartbeat *health.Heartbeat
+}
+
+// NewSurgeSchedule creates a new surge schedule
+func NewSurgeSchedule(repo *Repository) *SurgeSchedule {
+	return &SurgeSchedule{
+		repo:      repo,
+		active:    make(map[int64]bool),
+		stopCh:    make(chan struct{}),
+		heartbeat: health.NewHeartbeat(SurgeRefreshInterval),
+	}
+}
+
+// Start loads the windows and begins the background goroutine reloading them
+func (s *SurgeSchedule) Start(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("Failed to load surge windows: %v", err)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(SurgeRefreshInterval)
+		defer ticker.Stop()
+		s.heartbeat.Beat()
+		defer s.heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.heartbeat.Beat()
+				if err := s.Refresh(ctx); err != nil {
+					log.Printf("Failed to refresh surge windows: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Heartbeat reports whether the refresh loop is running
+func (s *SurgeSchedule) Heartbeat() *health.Heartbeat {
+	return s.heartbeat
+}
+
+// Stop gracefully stops the surge schedule
+func (s *SurgeSchedule) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// Refresh reloads the windows that have not ended yet
+func (s *SurgeSchedule) Refresh(ctx context.Context) error {
+	windows, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	upcoming := make([]SurgeWindow, 0, len(windows))
+	active := make(map[int64]bool)
+	for _, w := range windows {
+		if !w.EndsAt.After(now) {
+			continue
+		}
+		upcoming = append(upcoming, w)
+		if w.Active {
+			active[w.ID] = true
+		}
+	}
+
+	s.mu.Lock()
+	for id := range active {
+		if !s.active[id] {
+			log.Printf("Surge window %d started", id)
+		}
+	}
+	for id := range s.active {
+		if !active[id] {
+			log.Printf("Surge window %d ended", id)
+		}
+	}
+	s.upcoming = upcoming
+	s.active = active
+	s.mu.Unlock()
+	return nil
+}
+
+// RPMMultiplier returns the quota multiplier for a group right now. Windows
+// are matched against the current time, so they start and end on schedule
+// between refreshes. Overlapping windows do not stack; the largest applies.
+func (s *SurgeSchedule) RPMMultiplier(groupID int64) float64 {
+	if s == nil {
+		return 1
+	}
+	now := time.Now()
+	multiplier := 1.0
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, w := range s.upcoming {
+		if !w.activeAt(now) {
+			continue
+		}
+		for _, id := range w.GroupIDs {
+			if id == groupID && w.RPMMultiplier > multiplier {
+				multiplier = w.RPMMultiplier
+			}
+		}
+	}
+	return multiplier
+}
+
+// CacheTTL shortens a cache TTL while a surge window is active, so that
+// changes published during exam weeks reach clients sooner. Caches are shared
+// between groups, so the smallest factor of any active window applies.
+func (s *SurgeSchedule) CacheTTL(base time.Duration) time.Duration {
+	if s == nil {
+		return base
+	}
+	now := time.Now()
+	factor := 1.0
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, w := range s.upcoming {
+		if w.activeAt(now) && w.CacheTTLFactor < factor {
+			factor = w.CacheTTLFactor
+		}
+	}
+	return time.Duration(float64(base) * factor)
+}
+
+func (w *SurgeWindow) activeAt(t time.Time) bool {
+	return !t.Before(w.StartsAt) && t.Before(w.EndsAt)
+}
+
+// applySurge multiplies an RPM limit, rounding up so a multiplier never lowers it
+func applySurge(rpm int, multiplier float64) int {
+	if rpm == UnlimitedRPM || multiplier == 1 {
+		return rpm
+	}
+	return int(math.Ceil(float64(rpm) * multiplier))
+}
+
+// List returns all surge windows, most recent first
+func (s *SurgeSchedule) List(ctx context.Context) ([]SurgeWindow, error) {
+	rows, err := s.repo.db.QueryContext(ctx, `
+		SELECT s.id, s.name, s.starts_at, s.ends_at, s.rpm_multiplier, s.cache_ttl_factor,
+		       s.created_by, s.created_at, COALESCE(GROUP_CONCAT(g.group_id), '')
+		FROM surge_windows s
+		LEFT JOIN surge_window_groups g ON g.surge_window_id = s.id
+		GROUP BY s.id
+		ORDER BY s.starts_at DESC, s.id DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var windows []SurgeWindow
+	for rows.Next() {
+		w, err := scanSurgeWindow(rows)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, *w)
+	}
+	return windows, rows.Err()
+}
+
+// Get returns a surge window by ID
+func (s *SurgeSchedule) Get(ctx context.Context, id int64) (*SurgeWindow, error) {
+	w, err := scanSurgeWindow(s.repo.db.QueryRowContext(ctx, `
+		SELECT s.id, s.name, s.starts_at, s.ends_at, s.rpm_multiplier, s.cache_ttl_factor,
+		       s.created_by, s.created_at, COALESCE(GROUP_CONCAT(g.group_id), '')
+		FROM surge_windows s
+		LEFT JOIN surge_window_groups g ON g.surge_window_id = s.id
+		WHERE s.id = ?
+		GROUP BY s.id
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func scanSurgeWindow(row rowScanner) (*SurgeWindow, error) {
+	var w SurgeWindow
+	var createdBy sql.NullInt64
+	var groupList string
+	if err := row.Scan(&w.ID, &w.Name, &w.StartsAt, &w.EndsAt, &w.RPMMultiplier, &w.CacheTTLFactor,
+		&createdBy, &w.CreatedAt, &groupList); err != nil {
+		return nil, err
+	}
+	w.CreatedBy = ScanNullableInt64(createdBy)
+	w.GroupIDs = []int64{}
+	for _, part := range strings.Split(groupList, ",") {
+		var id int64
+		if _, err := fmt.Sscan(part, &id); err == nil {
+			w.GroupIDs = append(w.GroupIDs, id)
+		}
+	}
+	w.Active = w.activeAt(time.Now())
+	return &w, nil
+}
+
+// Create schedules a surge window
+func (s *SurgeSchedule) Create(ctx context.Context, req SurgeWindowRequest, createdBy *int64) (*SurgeWindow, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err := s.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO surge_windows (name, starts_at, ends_at, rpm_multiplier, cache_ttl_factor, created_by)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, req.Name, req.StartsAt.UTC(), req.EndsAt.UTC(), req.RPMMultiplier, cacheTTLFactor(req), createdBy)
+		if err != nil {
+			return err
+		}
+		id, _ = result.LastInsertId()
+		return setSurgeWindowGroups(ctx, tx, id, req.GroupIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.afterChange(ctx, id)
+}
+
+// Update replaces a surge window. Returns nil when it does not exist.
+func (s *SurgeSchedule) Update(ctx context.Context, id int64, req SurgeWindowRequest) (*SurgeWindow, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	found := false
+	err := s.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE surge_windows
+			SET name = ?, starts_at = ?, ends_at = ?, rpm_multiplier = ?, cache_ttl_factor = ?
+			WHERE id = ?
+		`, req.Name, req.StartsAt.UTC(), req.EndsAt.UTC(), req.RPMMultiplier, cacheTTLFactor(req), id)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return nil
+		}
+		found = true
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM surge_window_groups WHERE surge_window_id = ?", id); err != nil {
+			return err
+		}
+		return setSurgeWindowGroups(ctx, tx, id, req.GroupIDs)
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return s.afterChange(ctx, id)
+}
+
+// Delete removes a surge window, ending it right away if it is active
+func (s *SurgeSchedule) Delete(ctx context.Context, id int64) (bool, error) {
+	found := false
+	err := s.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM surge_window_groups WHERE surge_window_id = ?", id); err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM surge_windows WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, _ := result.RowsAffected()
+		found = n > 0
+		return nil
+	})
+	if err != nil || !found {
+		return false, err
+	}
+	return true, s.Refresh(ctx)
+}
+
+func (s *SurgeSchedule) validate(ctx context.Context, req SurgeWindowRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("Surge window name is required")
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return fmt.Errorf("Surge window must end after it starts")
+	}
+	for _, groupID := range req.GroupIDs {
+		group, err := s.repo.GetGroupByID(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if group == nil || group.DeletedAt != nil {
+			return fmt.Errorf("Group %d not found", groupID)
+		}
+	}
+	return nil
+}
+
+// afterChange reloads the schedule so a change applies immediately
+func (s *SurgeSchedule) afterChange(ctx context.Context, id int64) (*SurgeWindow, error) {
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, id)
+}
+
+func setSurgeWindowGroups(ctx context.Context, tx *sql.Tx, windowID int64, groupIDs []int64) error {
+	for _, groupID := range groupIDs {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO surge_window_groups (surge_window_id, group_id) VALUES (?, ?)
+		`, windowID, groupID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cacheTTLFactor(req SurgeWindowRequest) float64 {
+	if req.CacheTTLFactor == 0 {
+		return 1
+	}
+	return req.CacheTTLFactor
+}
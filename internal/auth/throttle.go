@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// ThrottleFreeFailures is how many invalid tokens an address may send before it is blocked
+	ThrottleFreeFailures = 5
+
+	// ThrottleBaseBlock is how long the first failure past the free ones blocks the
+	// address; every further failure doubles it
+	ThrottleBaseBlock = time.Second
+
+	// ThrottleMaxBlock caps a block, which at that length amounts to a temporary ban
+	ThrottleMaxBlock = 15 * time.Minute
+
+	// ThrottleWindow is how long an address's failures are remembered after its last one
+	ThrottleWindow = time.Hour
+
+	// ThrottleMaxEntries bounds memory use when invalid tokens come from many addresses
+	ThrottleMaxEntries = 100000
+)
+
+// ThrottledAddress is an address that sent invalid tokens. IPv6 clients are tracked by
+// their /64, since one host can use any address in it.
+type ThrottledAddress struct {
+	Address      string     `json:"address"`
+	Failures     int        `json:"failures"`
+	LastFailure  time.Time  `json:"lastFailure"`
+	BlockedUntil *time.Time `json:"blockedUntil,omitempty"`
+}
+
+// TokenThrottle tracks invalid bearer tokens per client address and blocks the
+// addresses that keep sending them, for exponentially longer, so tokens cannot be
+// guessed and each guess no longer costs a hash and a database lookup
+type TokenThrottle struct {
+	mu        sync.Mutex
+	addresses map[string]*ThrottledAddress
+}
+
+// NewTokenThrottle creates a new token throttle
+func NewTokenThrottle() *TokenThrottle {
+	return &TokenThrottle{addresses: make(map[string]*ThrottledAddress)}
+}
+
+// Blocked returns how long the client IP remains blocked, 0 when it may try a token.
+// It does nothing on a nil throttle or for an unknown IP, e.g. of a Unix socket peer,
+// which would otherwise make every such client share one entry.
+func (t *TokenThrottle) Blocked(ip string) time.Duration {
+	if t == nil || ip == "" {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	a := t.addresses[throttleKey(ip)]
+	if a == nil || a.BlockedUntil == nil {
+		return 0
+	}
+	return max(time.Until(*a.BlockedUntil), 0)
+}
+
+// Fail records an invalid token from the client IP and returns how long the address is
+// now blocked, 0 while it has failures to spare. Like Blocked, it ignores unknown IPs.
+func (t *TokenThrottle) Fail(ip string) time.Duration {
+	if t == nil || ip == "" {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	key := throttleKey(ip)
+	a := t.addresses[key]
+	if a != nil && now.Sub(a.LastFailure) > ThrottleWindow {
+		a = nil
+	}
+	if a == nil {
+		t.pruneLocked(now)
+		a = &ThrottledAddress{Address: key}
+		t.addresses[key] = a
+	}
+	a.Failures++
+	a.LastFailure = now
+
+	if a.Failures <= ThrottleFreeFailures {
+		return 0
+	}
+	// Past 2^20 seconds the block is capped anyway
+	block := ThrottleBaseBlock << min(a.Failures-ThrottleFreeFailures-1, 20)
+	block = min(block, ThrottleMaxBlock)
+	until := now.Add(block)
+	a.BlockedUntil = &until
+	return block
+}
+
+// List returns the addresses with recent failures, those blocked longest first
+func (t *TokenThrottle) List() []ThrottledAddress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.pruneLocked(now)
+	list := make([]ThrottledAddress, 0, len(t.addresses))
+	for _, a := range t.addresses {
+		// Forgotten failures are only dropped once the map is full
+		if now.Sub(a.LastFailure) > ThrottleWindow {
+			continue
+		}
+		entry := *a
+		if entry.BlockedUntil != nil && !entry.BlockedUntil.After(now) {
+			entry.BlockedUntil = nil
+		}
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		bi, bj := list[i].BlockedUntil, list[j].BlockedUntil
+		if (bi == nil) != (bj == nil) {
+			return bi != nil
+		}
+		if bi != nil && !bi.Equal(*bj) {
+			return bi.After(*bj)
+		}
+		return list[i].LastFailure.After(list[j].LastFailure)
+	})
+	return list
+}
+
+// Lift forgets the failures of the address an IP belongs to, unblocking it. It reports
+// whether the address had any.
+func (t *TokenThrottle) Lift(ip string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := throttleKey(ip)
+	if _, ok := t.addresses[key]; !ok {
+		return false
+	}
+	delete(t.addresses, key)
+	return true
+}
+
+// pruneLocked drops the addresses whose failures are forgotten, and when that is not
+// enough to make room for another, any that is not blocked
+func (t *TokenThrottle) pruneLocked(now time.Time) {
+	if len(t.addresses) < ThrottleMaxEntries {
+		return
+	}
+	for key, a := range t.addresses {
+		if now.Sub(a.LastFailure) > ThrottleWindow {
+			delete(t.addresses, key)
+		}
+	}
+	for key, a := range t.addresses {
+		if len(t.addresses) < ThrottleMaxEntries {
+			return
+		}
+		if a.BlockedUntil == nil || !a.BlockedUntil.After(now) {
+			delete(t.addresses, key)
+		}
+	}
+}
+
+// throttleKey is the address an IP is tracked under: itself for IPv4, its /64 for IPv6
+func throttleKey(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if parsed.To4() != nil {
+		return parsed.String()
+	}
+	return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTokenThrottleFail(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{"within the free failures", ThrottleFreeFailures, 0},
+		{"first failure past them", ThrottleFreeFailures + 1, ThrottleBaseBlock},
+		{"doubles with each failure", ThrottleFreeFailures + 3, 4 * ThrottleBaseBlock},
+		{"capped", ThrottleFreeFailures + 40, ThrottleMaxBlock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttle := NewTokenThrottle()
+			var got time.Duration
+			for range tt.failures {
+				got = throttle.Fail("192.0.2.1")
+			}
+			if got != tt.want {
+				t.Errorf("block after %d failures = %s, want %s", tt.failures, got, tt.want)
+			}
+			if blocked := throttle.Blocked("192.0.2.1"); (blocked > 0) != (tt.want > 0) {
+				t.Errorf("Blocked = %s, want blocked %t", blocked, tt.want > 0)
+			}
+		})
+	}
+}
+
+func TestTokenThrottleWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		age        time.Duration // since the last of the earlier failures
+		wantBlock  bool
+		wantListed bool
+	}{
+		{"recent failures count", time.Minute, true, true},
+		{"failures at the window's end count", ThrottleWindow - time.Minute, true, true},
+		{"failures past the window are forgotten", ThrottleWindow + time.Minute, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttle := NewTokenThrottle()
+			for range ThrottleFreeFailures {
+				throttle.Fail("192.0.2.1")
+			}
+			throttle.addresses["192.0.2.1"].LastFailure = time.Now().Add(-tt.age)
+
+			listed := len(throttle.List()) > 0
+			if listed != tt.wantListed {
+				t.Errorf("listed = %t, want %t", listed, tt.wantListed)
+			}
+			if block := throttle.Fail("192.0.2.1"); (block > 0) != tt.wantBlock {
+				t.Errorf("next failure blocks for %s, want blocked %t", block, tt.wantBlock)
+			}
+		})
+	}
+}
+
+func TestTokenThrottleEviction(t *testing.T) {
+	now := time.Now()
+	blockedUntil := now.Add(time.Hour)
+	tests := []struct {
+		name     string
+		entry    ThrottledAddress
+		wantKept bool
+	}{
+		{"forgotten", ThrottledAddress{Failures: 1, LastFailure: now.Add(-2 * ThrottleWindow)}, false},
+		{"not blocked", ThrottledAddress{Failures: 1, LastFailure: now}, false},
+		{"blocked", ThrottledAddress{Failures: 9, LastFailure: now, BlockedUntil: &blockedUntil}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A full map of blocked addresses, but for the entry under test
+			throttle := NewTokenThrottle()
+			for i := 1; i < ThrottleMaxEntries; i++ {
+				until := blockedUntil
+				throttle.addresses[fmt.Sprint(i)] = &ThrottledAddress{Failures: 9, LastFailure: now, BlockedUntil: &until}
+			}
+			entry := tt.entry
+			throttle.addresses["0"] = &entry
+
+			throttle.Fail("192.0.2.1")
+			if _, ok := throttle.addresses["192.0.2.1"]; !ok {
+				t.Fatal("the new address was not recorded")
+			}
+			if _, kept := throttle.addresses["0"]; kept != tt.wantKept {
+				t.Errorf("kept = %t, want %t", kept, tt.wantKept)
+			}
+			// Blocked addresses are never evicted to make room
+			want := ThrottleMaxEntries
+			if tt.wantKept {
+				want++
+			}
+			if len(throttle.addresses) != want {
+				t.Errorf("%d entries, want %d", len(throttle.addresses), want)
+			}
+		})
+	}
+}
+
+func TestThrottleKey(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"192.0.2.1", "192.0.2.1"},
+		{"::ffff:192.0.2.1", "192.0.2.1"},
+		{"2001:db8:1:2:3:4:5:6", "2001:db8:1:2::/64"},
+		{"2001:db8:1:2::ffff", "2001:db8:1:2::/64"},
+		{"not an ip", "not an ip"},
+	}
+	for _, tt := range tests {
+		if got := throttleKey(tt.ip); got != tt.want {
+			t.Errorf("throttleKey(%q) = %q, want %q", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestTokenThrottleUnknownIP(t *testing.T) {
+	throttle := NewTokenThrottle()
+	for range ThrottleFreeFailures + 1 {
+		if block := throttle.Fail(""); block != 0 {
+			t.Fatalf("Fail(\"\") = %s, want 0", block)
+		}
+	}
+	if len(throttle.addresses) != 0 {
+		t.Errorf("recorded %d entries for an unknown IP", len(throttle.addresses))
+	}
+}
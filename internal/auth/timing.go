@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderServerTiming      = "Server-Timing"
+	HeaderTimingAllowOrigin = "Timing-Allow-Origin"
+)
+
+// serverTiming measures consecutive phases of a request for the Server-Timing
+// header, so integrators can tell our processing time from network latency
+type serverTiming struct {
+	start   time.Time
+	last    time.Time
+	metrics []string
+}
+
+func newServerTiming() *serverTiming {
+	now := time.Now()
+	return &serverTiming{start: now, last: now}
+}
+
+// lap records the time since the previous lap as a named phase
+func (t *serverTiming) lap(name, desc string) {
+	now := time.Now()
+	t.metrics = append(t.metrics, formatTimingMetric(name, desc, now.Sub(t.last)))
+	t.last = now
+}
+
+// header closes the last phase and returns the Server-Timing header value
+func (t *serverTiming) header(name, desc string) string {
+	t.lap(name, desc)
+	metrics := append(t.metrics, formatTimingMetric("total", "", time.Since(t.start)))
+	return strings.Join(metrics, ", ")
+}
+
+func formatTimingMetric(name, desc string, d time.Duration) string {
+	metric := fmt.Sprintf("%s;dur=%.2f", name, float64(d.Microseconds())/1000)
+	if desc != "" {
+		metric += fmt.Sprintf(";desc=%q", desc)
+	}
+	return metric
+}
+
+// timingResponseWriter adds the Server-Timing header once the handler starts
+// writing its response, closing the "handler" phase at that point
+type timingResponseWriter struct {
+	gin.ResponseWriter
+	once   sync.Once
+	timing *serverTiming
+}
+
+func (w *timingResponseWriter) before() {
+	w.once.Do(func() {
+		w.Header().Set(HeaderServerTiming, w.timing.header("handler", "Handler"))
+		w.Header().Set(HeaderTimingAllowOrigin, "*")
+	})
+}
+
+func (w *timingResponseWriter) WriteHeaderNow() {
+	w.before()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timingResponseWriter) Write(data []byte) (int, error) {
+	w.before()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timingResponseWriter) WriteString(s string) (int, error) {
+	w.before()
+	return w.ResponseWriter.WriteString(s)
+}
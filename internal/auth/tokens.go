@@ -1,38 +1,59 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"API/internal/events"
+	"API/internal/pagination"
+
 	"github.com/mr-tron/base58"
 )
 
 const (
-	// TokenPrefix is the prefix for all generated tokens
+	// TokenPrefix is the default prefix for generated tokens
 	TokenPrefix = "osduth_"
 )
 
+// Reasons ValidateToken refuses a token; other errors are lookup failures
+var (
+	ErrInvalidTokenFormat = errors.New("invalid token format")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrTokenExpired       = errors.New("token has expired")
+	ErrTokenUserNotFound  = errors.New("user not found")
+)
+
 // TokenStore manages API token operations
 type TokenStore struct {
 	repo     *Repository
 	features *FeatureRegistry
+	prefix   string
+	events   *events.Bus
 }
 
-// NewTokenStore creates a new token store
-func NewTokenStore(repo *Repository, features *FeatureRegistry) *TokenStore {
+// NewTokenStore creates a new token store. An empty prefix falls back to TokenPrefix.
+func NewTokenStore(repo *Repository, features *FeatureRegistry, prefix string, bus *events.Bus) *TokenStore {
+	if prefix == "" {
+		prefix = TokenPrefix
+	}
 	return &TokenStore{
 		repo:     repo,
 		features: features,
+		prefix:   prefix,
+		events:   bus,
 	}
 }
 
-// GenerateToken creates a new random token with the osduth_ prefix
-// Format: osduth_ + Base58(SHA256(random_bytes))
+// GenerateToken creates a new random token with the store's prefix
+// Format: prefix + Base58(SHA256(random_bytes))
 func (s *TokenStore) GenerateToken() (rawToken string, tokenHash string, err error) {
 	// Generate 32 random bytes
 	randomBytes := make([]byte, 32)
@@ -47,7 +68,7 @@ func (s *TokenStore) GenerateToken() (rawToken string, tokenHash string, err err
 	encoded := base58.Encode(hash[:])
 
 	// Create raw token with prefix
-	rawToken = TokenPrefix + encoded
+	rawToken = s.prefix + encoded
 
 	// Hash the raw token for storage
 	tokenHash = hashToken(rawToken)
@@ -63,7 +84,7 @@ func hashToken(token string) string {
 
 // CreateUserToken creates a token for a user with the given parameters
 // This enforces max_tokens limit and rejects admin-only features
-func (s *TokenStore) CreateUserToken(userID int64, label string, featureSlugs []string, allowedIPs []string, expiresAt *time.Time) (*TokenWithRaw, error) {
+func (s *TokenStore) CreateUserToken(ctx context.Context, userID int64, label string, featureSlugs []string, allowedIPs []string, expiresAt *time.Time) (*TokenWithRaw, error) {
 	// Validate label
 	label = strings.TrimSpace(label)
 	if label == "" {
@@ -71,7 +92,7 @@ func (s *TokenStore) CreateUserToken(userID int64, label string, featureSlugs []
 	}
 
 	// Check token limit
-	user, err := s.repo.GetUserByID(userID)
+	user, err := s.repo.GetUserByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +100,7 @@ func (s *TokenStore) CreateUserToken(userID int64, label string, featureSlugs []
 		return nil, fmt.Errorf("User not found")
 	}
 
-	count, err := s.repo.GetUserTokenCount(userID)
+	count, err := s.repo.GetUserTokenCount(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +109,7 @@ func (s *TokenStore) CreateUserToken(userID int64, label string, featureSlugs []
 	}
 
 	// Validate features exist and are not admin-only
-	features, err := s.features.GetFeaturesBySlugs(featureSlugs)
+	features, versions, err := s.features.ResolveFeatureRefs(ctx, featureSlugs)
 	if err != nil {
 		return nil, err
 	}
@@ -99,11 +120,20 @@ func (s *TokenStore) CreateUserToken(userID int64, label string, featureSlugs []
 		return nil, fmt.Errorf("One or more features not found")
 	}
 
-	// Check for admin-only features
+	// Check for admin-only features and features the user was not approved for
 	for _, f := range features {
 		if f.AdminOnly {
 			return nil, fmt.Errorf("Feature '%s' is admin-only and cannot be assigned by users", f.Slug)
 		}
+		if f.ApprovalRequired {
+			approved, err := s.features.HasApprovedAccess(ctx, userID, f.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !approved {
+				return nil, fmt.Errorf("Feature '%s' requires approval; request access first", f.Slug)
+			}
+		}
 	}
 
 	// Canonicalize IPs
@@ -119,11 +149,11 @@ func (s *TokenStore) CreateUserToken(userID int64, label string, featureSlugs []
 	}
 
 	// Create token in database
-	return s.createToken(userID, tokenHash, label, false, expiresAt, features, canonicalIPs, rawToken)
+	return s.createToken(ctx, userID, tokenHash, label, false, expiresAt, features, versions, canonicalIPs, rawToken)
 }
 
 // CreateAdminToken creates a token without restrictions (admin use)
-func (s *TokenStore) CreateAdminToken(userID int64, label string, featureSlugs []string, allowedIPs []string, expiresAt *time.Time) (*TokenWithRaw, error) {
+func (s *TokenStore) CreateAdminToken(ctx context.Context, userID int64, label string, featureSlugs []string, allowedIPs []string, expiresAt *time.Time) (*TokenWithRaw, error) {
 	// Validate label
 	label = strings.TrimSpace(label)
 	if label == "" {
@@ -131,7 +161,7 @@ func (s *TokenStore) CreateAdminToken(userID int64, label string, featureSlugs [
 	}
 
 	// Validate features exist
-	features, err := s.features.GetFeaturesBySlugs(featureSlugs)
+	features, versions, err := s.features.ResolveFeatureRefs(ctx, featureSlugs)
 	if err != nil {
 		return nil, err
 	}
@@ -155,60 +185,88 @@ func (s *TokenStore) CreateAdminToken(userID int64, label string, featureSlugs [
 	}
 
 	// Create token in database
-	return s.createToken(userID, tokenHash, label, true, expiresAt, features, canonicalIPs, rawToken)
+	return s.createToken(ctx, userID, tokenHash, label, true, expiresAt, features, versions, canonicalIPs, rawToken)
 }
 
-func (s *TokenStore) createToken(userID int64, tokenHash, label string, adminCreated bool, expiresAt *time.Time, features []Feature, allowedIPs []string, rawToken string) (*TokenWithRaw, error) {
-	tx, err := s.repo.db.Begin()
-	if err != nil {
-		return nil, err
+// CreateWorkspaceToken issues a course workspace token to a student. It does not
+// count against the student's max_tokens, and the features were already checked
+// to be non-admin-only when the workspace was created.
+func (s *TokenStore) CreateWorkspaceToken(ctx context.Context, userID int64, label string, features []Feature, expiresAt time.Time) (*TokenWithRaw, error) {
+	if len(features) == 0 {
+		return nil, fmt.Errorf("At least one valid feature is required")
 	}
-	defer tx.Rollback()
 
-	// Insert token
-	result, err := tx.Exec(`
-		INSERT INTO tokens (user_id, token_hash, label, admin_created, expires_at)
-		VALUES (?, ?, ?, ?, ?)
-	`, userID, tokenHash, label, adminCreated, expiresAt)
+	rawToken, tokenHash, err := s.GenerateToken()
 	if err != nil {
 		return nil, err
 	}
+	return s.createToken(ctx, userID, tokenHash, label, false, &expiresAt, features, nil, nil, rawToken)
+}
 
-	tokenID, _ := result.LastInsertId()
+func (s *TokenStore) createToken(ctx context.Context, userID int64, tokenHash, label string, adminCreated bool, expiresAt *time.Time, features []Feature, versions map[int64]int, allowedIPs []string, rawToken string) (*TokenWithRaw, error) {
+	var tokenID int64
+	var event events.TokenCreated
+	err := s.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		// Insert token
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO tokens (user_id, token_hash, label, admin_created, expires_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, userID, tokenHash, label, adminCreated, expiresAt)
+		if err != nil {
+			return err
+		}
 
-	// Insert feature associations
-	for _, f := range features {
-		if _, err := tx.Exec(`
-			INSERT INTO token_features (token_id, feature_id) VALUES (?, ?)
-		`, tokenID, f.ID); err != nil {
-			return nil, err
+		tokenID, _ = result.LastInsertId()
+
+		// Insert feature associations with their pinned versions
+		for _, f := range features {
+			var version *int
+			if v, ok := versions[f.ID]; ok {
+				version = &v
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO token_features (token_id, feature_id, version) VALUES (?, ?, ?)
+			`, tokenID, f.ID, version); err != nil {
+				return err
+			}
 		}
-	}
 
-	// Insert allowed IPs
-	for _, ip := range allowedIPs {
-		if _, err := tx.Exec(`
-			INSERT INTO token_allowed_ips (token_id, ip_address) VALUES (?, ?)
-		`, tokenID, ip); err != nil {
-			return nil, err
+		// Insert allowed IPs
+		for _, ip := range allowedIPs {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO token_allowed_ips (token_id, ip_address) VALUES (?, ?)
+			`, tokenID, ip); err != nil {
+				return err
+			}
 		}
-	}
 
-	if err := tx.Commit(); err != nil {
+		event = events.TokenCreated{
+			TokenID:      tokenID,
+			UserID:       userID,
+			Label:        label,
+			AdminCreated: adminCreated,
+			ExpiresAt:    expiresAt,
+			OccurredAt:   time.Now(),
+		}
+		return s.repo.outbox.Enqueue(tx, event)
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.events.Publish(context.Background(), event)
 
 	// Build response
 	token := &TokenWithRaw{
 		Token: Token{
-			ID:           tokenID,
-			UserID:       userID,
-			Label:        label,
-			AdminCreated: adminCreated,
-			ExpiresAt:    expiresAt,
-			CreatedAt:    time.Now(),
-			Features:     features,
-			AllowedIPs:   allowedIPs,
+			ID:              tokenID,
+			UserID:          userID,
+			Label:           label,
+			AdminCreated:    adminCreated,
+			ExpiresAt:       expiresAt,
+			CreatedAt:       time.Now(),
+			Features:        features,
+			FeatureVersions: featureVersionsBySlug(features, versions),
+			AllowedIPs:      allowedIPs,
 		},
 		RawToken: rawToken,
 	}
@@ -217,10 +275,10 @@ func (s *TokenStore) createToken(userID int64, tokenHash, label string, adminCre
 }
 
 // ValidateToken validates a raw token and returns the token with user info
-func (s *TokenStore) ValidateToken(rawToken string) (*ValidatedToken, error) {
+func (s *TokenStore) ValidateToken(ctx context.Context, rawToken string) (*ValidatedToken, error) {
 	// Check prefix
-	if !strings.HasPrefix(rawToken, TokenPrefix) {
-		return nil, fmt.Errorf("invalid token format")
+	if !strings.HasPrefix(rawToken, s.prefix) {
+		return nil, ErrInvalidTokenFormat
 	}
 
 	// Hash the token for lookup
@@ -229,12 +287,12 @@ func (s *TokenStore) ValidateToken(rawToken string) (*ValidatedToken, error) {
 	// Look up token
 	var t Token
 	var expiresAt, revokedAt sql.NullTime
-	err := s.repo.db.QueryRow(`
-		SELECT id, user_id, token_hash, label, admin_created, expires_at, revoked_at, created_at
+	err := s.repo.db.QueryRowContext(ctx, `
+		SELECT id, user_id, token_hash, label, admin_created, debug_timing, expires_at, revoked_at, created_at
 		FROM tokens WHERE token_hash = ?
-	`, tokenHash).Scan(&t.ID, &t.UserID, &t.TokenHash, &t.Label, &t.AdminCreated, &expiresAt, &revokedAt, &t.CreatedAt)
+	`, tokenHash).Scan(&t.ID, &t.UserID, &t.TokenHash, &t.Label, &t.AdminCreated, &t.DebugTiming, &expiresAt, &revokedAt, &t.CreatedAt)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("invalid token")
+		return nil, ErrInvalidToken
 	}
 	if err != nil {
 		return nil, err
@@ -245,70 +303,88 @@ func (s *TokenStore) ValidateToken(rawToken string) (*ValidatedToken, error) {
 
 	// Check if revoked
 	if t.RevokedAt != nil {
-		return nil, fmt.Errorf("token has been revoked")
+		return nil, ErrTokenRevoked
 	}
 
 	// Check if expired
 	if t.ExpiresAt != nil && t.ExpiresAt.Before(time.Now()) {
-		return nil, fmt.Errorf("token has expired")
+		return nil, ErrTokenExpired
 	}
 
 	// Get user
-	user, err := s.repo.GetUserByID(t.UserID)
+	user, err := s.repo.GetUserByID(ctx, t.UserID)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, fmt.Errorf("user not found")
+		return nil, ErrTokenUserNotFound
 	}
 
 	// Check user status
-	if user.Status != StatusActive {
-		return nil, fmt.Errorf("user account is %s", user.Status)
+	if !user.IsActive() {
+		return nil, &InactiveAccountError{User: user}
 	}
 
 	// Get feature IDs
-	featureIDs, err := s.getTokenFeatureIDs(t.ID)
+	featureIDs, versions, err := s.getTokenFeatures(ctx, t.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get allowed IPs
-	allowedIPs, err := s.getTokenAllowedIPs(t.ID)
+	allowedIPs, err := s.getTokenAllowedIPs(ctx, t.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ValidatedToken{
-		Token:      &t,
-		User:       user,
-		FeatureIDs: featureIDs,
-		AllowedIPs: allowedIPs,
+		Token:           &t,
+		User:            user,
+		FeatureIDs:      featureIDs,
+		FeatureVersions: versions,
+		AllowedIPs:      allowedIPs,
 	}, nil
 }
 
-func (s *TokenStore) getTokenFeatureIDs(tokenID int64) ([]int64, error) {
-	rows, err := s.repo.db.Query(`
-		SELECT feature_id FROM token_features WHERE token_id = ?
+// getTokenFeatures returns a token's feature IDs and the versions it pins
+func (s *TokenStore) getTokenFeatures(ctx context.Context, tokenID int64) ([]int64, map[int64]int, error) {
+	rows, err := s.repo.db.QueryContext(ctx, `
+		SELECT feature_id, version FROM token_features WHERE token_id = ?
 	`, tokenID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var ids []int64
+	versions := make(map[int64]int)
 	for rows.Next() {
 		var id int64
-		if err := rows.Scan(&id); err != nil {
-			return nil, err
+		var version sql.NullInt64
+		if err := rows.Scan(&id, &version); err != nil {
+			return nil, nil, err
 		}
 		ids = append(ids, id)
+		if version.Valid {
+			versions[id] = int(version.Int64)
+		}
 	}
-	return ids, rows.Err()
+	return ids, versions, rows.Err()
 }
 
-func (s *TokenStore) getTokenAllowedIPs(tokenID int64) ([]string, error) {
-	rows, err := s.repo.db.Query(`
+// featureVersionsBySlug keys pinned versions by slug for API responses
+func featureVersionsBySlug(features []Feature, versions map[int64]int) map[string]int {
+	bySlug := make(map[string]int)
+	for _, f := range features {
+		if v, ok := versions[f.ID]; ok {
+			bySlug[f.Slug] = v
+		}
+	}
+	return bySlug
+}
+
+func (s *TokenStore) getTokenAllowedIPs(ctx context.Context, tokenID int64) ([]string, error) {
+	rows, err := s.repo.db.QueryContext(ctx, `
 		SELECT ip_address FROM token_allowed_ips WHERE token_id = ?
 	`, tokenID)
 	if err != nil {
@@ -328,39 +404,61 @@ func (s *TokenStore) getTokenAllowedIPs(tokenID int64) ([]string, error) {
 }
 
 // ListUserTokens returns all tokens for a user (without raw values)
-func (s *TokenStore) ListUserTokens(userID int64) ([]Token, error) {
-	rows, err := s.repo.db.Query(`
-		SELECT id, user_id, label, admin_created, expires_at, revoked_at, created_at
+func (s *TokenStore) ListUserTokens(ctx context.Context, userID int64) ([]Token, error) {
+	rows, err := s.repo.db.QueryContext(ctx, `
+		SELECT id, user_id, label, admin_created, debug_timing, expires_at, revoked_at, created_at
 		FROM tokens WHERE user_id = ? ORDER BY created_at DESC
 	`, userID)
 	if err != nil {
 		return nil, err
 	}
+	return s.scanTokens(ctx, rows)
+}
+
+// ListUserTokensPage returns a page of a user's tokens, newest first. The
+// page holds one extra token when another page follows (see pagination.Next).
+func (s *TokenStore) ListUserTokensPage(ctx context.Context, userID int64, page pagination.Params) ([]Token, error) {
+	after, args := page.Where("id")
+	rows, err := s.repo.db.QueryContext(ctx, `
+		SELECT id, user_id, label, admin_created, debug_timing, expires_at, revoked_at, created_at
+		FROM tokens WHERE user_id = ? AND `+after+`
+		ORDER BY id DESC
+		LIMIT ?
+	`, append(append([]interface{}{userID}, args...), page.FetchLimit())...)
+	if err != nil {
+		return nil, err
+	}
+	return s.scanTokens(ctx, rows)
+}
+
+// scanTokens reads token rows along with their features and allowed IPs
+func (s *TokenStore) scanTokens(ctx context.Context, rows *sql.Rows) ([]Token, error) {
 	defer rows.Close()
 
 	var tokens []Token
 	for rows.Next() {
 		var t Token
 		var expiresAt, revokedAt sql.NullTime
-		if err := rows.Scan(&t.ID, &t.UserID, &t.Label, &t.AdminCreated, &expiresAt, &revokedAt, &t.CreatedAt); err != nil {
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Label, &t.AdminCreated, &t.DebugTiming, &expiresAt, &revokedAt, &t.CreatedAt); err != nil {
 			return nil, err
 		}
 		t.ExpiresAt = ScanNullableTime(expiresAt)
 		t.RevokedAt = ScanNullableTime(revokedAt)
 
 		// Get features
-		featureIDs, err := s.getTokenFeatureIDs(t.ID)
+		featureIDs, versions, err := s.getTokenFeatures(ctx, t.ID)
 		if err != nil {
 			return nil, err
 		}
-		features, err := s.features.GetFeaturesByIDs(featureIDs)
+		features, err := s.features.GetFeaturesByIDs(ctx, featureIDs)
 		if err != nil {
 			return nil, err
 		}
 		t.Features = features
+		t.FeatureVersions = featureVersionsBySlug(features, versions)
 
 		// Get allowed IPs
-		t.AllowedIPs, err = s.getTokenAllowedIPs(t.ID)
+		t.AllowedIPs, err = s.getTokenAllowedIPs(ctx, t.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -371,13 +469,13 @@ func (s *TokenStore) ListUserTokens(userID int64) ([]Token, error) {
 }
 
 // GetTokenByID returns a token by ID
-func (s *TokenStore) GetTokenByID(tokenID int64) (*Token, error) {
+func (s *TokenStore) GetTokenByID(ctx context.Context, tokenID int64) (*Token, error) {
 	var t Token
 	var expiresAt, revokedAt sql.NullTime
-	err := s.repo.db.QueryRow(`
-		SELECT id, user_id, label, admin_created, expires_at, revoked_at, created_at
+	err := s.repo.db.QueryRowContext(ctx, `
+		SELECT id, user_id, label, admin_created, debug_timing, expires_at, revoked_at, created_at
 		FROM tokens WHERE id = ?
-	`, tokenID).Scan(&t.ID, &t.UserID, &t.Label, &t.AdminCreated, &expiresAt, &revokedAt, &t.CreatedAt)
+	`, tokenID).Scan(&t.ID, &t.UserID, &t.Label, &t.AdminCreated, &t.DebugTiming, &expiresAt, &revokedAt, &t.CreatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -388,18 +486,19 @@ func (s *TokenStore) GetTokenByID(tokenID int64) (*Token, error) {
 	t.RevokedAt = ScanNullableTime(revokedAt)
 
 	// Get features
-	featureIDs, err := s.getTokenFeatureIDs(t.ID)
+	featureIDs, versions, err := s.getTokenFeatures(ctx, t.ID)
 	if err != nil {
 		return nil, err
 	}
-	features, err := s.features.GetFeaturesByIDs(featureIDs)
+	features, err := s.features.GetFeaturesByIDs(ctx, featureIDs)
 	if err != nil {
 		return nil, err
 	}
 	t.Features = features
+	t.FeatureVersions = featureVersionsBySlug(features, versions)
 
 	// Get allowed IPs
-	t.AllowedIPs, err = s.getTokenAllowedIPs(t.ID)
+	t.AllowedIPs, err = s.getTokenAllowedIPs(ctx, t.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -407,35 +506,171 @@ func (s *TokenStore) GetTokenByID(tokenID int64) (*Token, error) {
 	return &t, nil
 }
 
+// TokenHasFeature reports whether a token may still use a feature, directly or through
+// a parent feature: it must be neither revoked nor expired and its user must be active.
+// Background work done on a token's behalf, e.g. webhook deliveries, checks this the way
+// requests are checked by ValidateToken.
+func (s *TokenStore) TokenHasFeature(ctx context.Context, tokenID int64, featureSlug string) (bool, error) {
+	var userID int64
+	var expiresAt, revokedAt sql.NullTime
+	err := s.repo.db.QueryRowContext(ctx, `
+		SELECT user_id, expires_at, revoked_at FROM tokens WHERE id = ?
+	`, tokenID).Scan(&userID, &expiresAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if revokedAt.Valid || (expiresAt.Valid && expiresAt.Time.Before(time.Now())) {
+		return false, nil
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil || user == nil || !user.IsActive() {
+		return false, err
+	}
+
+	featureIDs, _, err := s.getTokenFeatures(ctx, tokenID)
+	if err != nil {
+		return false, err
+	}
+	return s.features.TokenHasFeatureAccess(ctx, featureIDs, featureSlug)
+}
+
+// ListDeprecatedFeatureTokens returns active tokens that include a deprecated
+// feature, so admins can reach their owners before the feature is retired
+func (s *TokenStore) ListDeprecatedFeatureTokens(ctx context.Context) ([]DeprecatedFeatureToken, error) {
+	rows, err := s.repo.db.QueryContext(ctx, `
+		SELECT t.id, t.label, t.user_id, u.email, t.admin_created, t.expires_at, t.created_at, tf.feature_id
+		FROM tokens t
+		JOIN users u ON u.id = t.user_id
+		JOIN token_features tf ON tf.token_id = t.id
+		JOIN features f ON f.id = tf.feature_id
+		WHERE f.deprecated = 1 AND t.revoked_at IS NULL AND (t.expires_at IS NULL OR t.expires_at > ?)
+		ORDER BY t.id
+	`, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []DeprecatedFeatureToken
+	var featureIDs [][]int64
+	for rows.Next() {
+		var t DeprecatedFeatureToken
+		var expiresAt sql.NullTime
+		var featureID int64
+		if err := rows.Scan(&t.TokenID, &t.Label, &t.UserID, &t.Email, &t.AdminCreated, &expiresAt, &t.CreatedAt, &featureID); err != nil {
+			return nil, err
+		}
+		if n := len(tokens); n > 0 && tokens[n-1].TokenID == t.TokenID {
+			featureIDs[n-1] = append(featureIDs[n-1], featureID)
+			continue
+		}
+		t.ExpiresAt = ScanNullableTime(expiresAt)
+		tokens = append(tokens, t)
+		featureIDs = append(featureIDs, []int64{featureID})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range tokens {
+		tokens[i].Features, err = s.features.GetFeaturesByIDs(ctx, featureIDs[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tokens, nil
+}
+
 // RevokeToken revokes a token (user can only revoke their own tokens)
-func (s *TokenStore) RevokeToken(tokenID int64, userID int64) error {
-	result, err := s.repo.db.Exec(`
-		UPDATE tokens SET revoked_at = ? 
-		WHERE id = ? AND user_id = ? AND revoked_at IS NULL
-	`, time.Now(), tokenID, userID)
+func (s *TokenStore) RevokeToken(ctx context.Context, tokenID int64, userID int64) error {
+	return s.revoke(ctx, tokenID, &userID)
+}
+
+// AdminRevokeToken revokes any token (admin use)
+func (s *TokenStore) AdminRevokeToken(ctx context.Context, tokenID int64) error {
+	return s.revoke(ctx, tokenID, nil)
+}
+
+// RestoreToken clears the revocation of a token that has not expired (admin use)
+func (s *TokenStore) RestoreToken(ctx context.Context, tokenID int64) error {
+	var event events.TokenRestored
+	err := s.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		var userID int64
+		err := tx.QueryRowContext(ctx, `
+			UPDATE tokens SET revoked_at = NULL
+			WHERE id = ? AND revoked_at IS NOT NULL AND (expires_at IS NULL OR expires_at > ?)
+			RETURNING user_id
+		`, tokenID, time.Now()).Scan(&userID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("token not found, not revoked or expired")
+		}
+		if err != nil {
+			return err
+		}
+
+		event = events.TokenRestored{
+			TokenID:    tokenID,
+			UserID:     userID,
+			OccurredAt: time.Now(),
+		}
+		return s.repo.outbox.Enqueue(tx, event)
+	})
 	if err != nil {
 		return err
 	}
 
-	rows, _ := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("token not found or already revoked")
-	}
+	s.events.Publish(context.Background(), event)
 	return nil
 }
 
-// AdminRevokeToken revokes any token (admin use)
-func (s *TokenStore) AdminRevokeToken(tokenID int64) error {
-	result, err := s.repo.db.Exec(`
-		UPDATE tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL
-	`, time.Now(), tokenID)
+// SetDebugTiming turns Server-Timing breakdowns on or off for a token owned by userID
+func (s *TokenStore) SetDebugTiming(ctx context.Context, tokenID int64, userID int64, enabled bool) error {
+	result, err := s.repo.db.ExecContext(ctx, `
+		UPDATE tokens SET debug_timing = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL
+	`, enabled, tokenID, userID)
 	if err != nil {
 		return err
 	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("token not found or revoked")
+	}
+	return nil
+}
+
+// revoke marks a token revoked and records the event. A nil ownerID skips the
+// ownership check and marks the revocation as done by an admin.
+func (s *TokenStore) revoke(ctx context.Context, tokenID int64, ownerID *int64) error {
+	var event events.TokenRevoked
+	err := s.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		var userID int64
+		err := tx.QueryRowContext(ctx, `
+			UPDATE tokens SET revoked_at = ?
+			WHERE id = ? AND (? IS NULL OR user_id = ?) AND revoked_at IS NULL
+			RETURNING user_id
+		`, time.Now(), tokenID, ownerID, ownerID).Scan(&userID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("token not found or already revoked")
+		}
+		if err != nil {
+			return err
+		}
 
-	rows, _ := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("token not found or already revoked")
+		event = events.TokenRevoked{
+			TokenID:    tokenID,
+			UserID:     userID,
+			ByAdmin:    ownerID == nil,
+			OccurredAt: time.Now(),
+		}
+		return s.repo.outbox.Enqueue(tx, event)
+	})
+	if err != nil {
+		return err
 	}
+
+	s.events.Publish(context.Background(), event)
 	return nil
 }
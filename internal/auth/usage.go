@@ -2,8 +2,14 @@ package auth
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"API/internal/health"
 )
 
 const (
@@ -18,6 +24,9 @@ const (
 
 	// UsageRetentionPeriod is how long to keep usage logs (60 seconds for RPM)
 	UsageRetentionPeriod = 60 * time.Second
+
+	// UsageBacklogLimit is how many buffered entries mean the writer is falling behind
+	UsageBacklogLimit = UsageBufferSize / 2
 )
 
 // UsageEntry represents a single API request for buffered logging
@@ -35,16 +44,24 @@ type UsageTracker struct {
 	wg           sync.WaitGroup
 	stateStore   *OAuthStateStore
 	sessionStore *SessionStore
+	idempotency  *IdempotencyStore
+	heartbeat    *health.Heartbeat
+	cleanupBeat  *health.Heartbeat
+	dropped      atomic.Int64
+	lastDrop     atomic.Int64 // Unix nanoseconds of the last dropped entry
 }
 
 // NewUsageTracker creates a new usage tracker
-func NewUsageTracker(repo *Repository, stateStore *OAuthStateStore, sessionStore *SessionStore) *UsageTracker {
+func NewUsageTracker(repo *Repository, stateStore *OAuthStateStore, sessionStore *SessionStore, idempotency *IdempotencyStore) *UsageTracker {
 	return &UsageTracker{
 		repo:         repo,
 		buffer:       make(chan UsageEntry, UsageBufferSize),
 		stopCh:       make(chan struct{}),
 		stateStore:   stateStore,
 		sessionStore: sessionStore,
+		idempotency:  idempotency,
+		heartbeat:    health.NewHeartbeat(UsageFlushInterval),
+		cleanupBeat:  health.NewHeartbeat(UsageCleanupInterval),
 	}
 }
 
@@ -61,16 +78,17 @@ func (t *UsageTracker) RecordRequest(userID int64, featureID int64) {
 	select {
 	case t.buffer <- entry:
 	default:
-		// Buffer full, silently drop
-		// In production, you might want to log this
+		// Buffer full, drop the entry; the backlog check reports it
+		t.dropped.Add(1)
+		t.lastDrop.Store(time.Now().UnixNano())
 	}
 }
 
 // GetFeatureRPM returns the current requests per minute for a user on a feature
-func (t *UsageTracker) GetFeatureRPM(userID int64, featureID int64) (int, error) {
+func (t *UsageTracker) GetFeatureRPM(ctx context.Context, userID int64, featureID int64) (int, error) {
 	cutoff := time.Now().Add(-UsageRetentionPeriod)
 	var count int
-	err := t.repo.db.QueryRow(`
+	err := t.repo.db.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM usage_log
 		WHERE user_id = ? AND feature_id = ? AND timestamp > ?
 	`, userID, featureID, cutoff).Scan(&count)
@@ -78,10 +96,10 @@ func (t *UsageTracker) GetFeatureRPM(userID int64, featureID int64) (int, error)
 }
 
 // GetUserTotalRPM returns the total requests per minute for a user across all features
-func (t *UsageTracker) GetUserTotalRPM(userID int64) (int, error) {
+func (t *UsageTracker) GetUserTotalRPM(ctx context.Context, userID int64) (int, error) {
 	cutoff := time.Now().Add(-UsageRetentionPeriod)
 	var count int
-	err := t.repo.db.QueryRow(`
+	err := t.repo.db.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM usage_log
 		WHERE user_id = ? AND timestamp > ?
 	`, userID, cutoff).Scan(&count)
@@ -111,9 +129,35 @@ func (t *UsageTracker) Stop() {
 	t.wg.Wait()
 }
 
+// Heartbeat reports whether the usage writer is running
+func (t *UsageTracker) Heartbeat() *health.Heartbeat {
+	return t.heartbeat
+}
+
+// CleanupHeartbeat reports whether the cleanup job is running, and when it last ran
+func (t *UsageTracker) CleanupHeartbeat() *health.Heartbeat {
+	return t.cleanupBeat
+}
+
+// Backlog checks that the usage writer keeps up: it fails while the buffer is more than
+// half full, or when an entry was dropped for lack of room in the last minute
+func (t *UsageTracker) Backlog(ctx context.Context) (string, error) {
+	queued := len(t.buffer)
+	detail := fmt.Sprintf("%d of %d entries buffered, %d dropped", queued, UsageBufferSize, t.dropped.Load())
+	if queued >= UsageBacklogLimit {
+		return detail, errors.New("usage writer is falling behind")
+	}
+	if last := t.lastDrop.Load(); last != 0 && time.Since(time.Unix(0, last)) < UsageRetentionPeriod {
+		return detail, errors.New("usage entries were dropped in the last minute")
+	}
+	return detail, nil
+}
+
 func (t *UsageTracker) usageWriter(ctx context.Context) {
 	ticker := time.NewTicker(UsageFlushInterval)
 	defer ticker.Stop()
+	t.heartbeat.Beat()
+	defer t.heartbeat.Stop()
 
 	var batch []UsageEntry
 
@@ -136,6 +180,7 @@ func (t *UsageTracker) usageWriter(ctx context.Context) {
 				batch = nil
 			}
 		case <-ticker.C:
+			t.heartbeat.Beat()
 			// Periodic flush
 			if len(batch) > 0 {
 				t.flushBatch(batch)
@@ -165,30 +210,30 @@ func (t *UsageTracker) flushBatch(batch []UsageEntry) {
 		return
 	}
 
-	tx, err := t.repo.db.Begin()
-	if err != nil {
-		return // Silently fail - in production, log this
-	}
-	defer tx.Rollback()
-
-	stmt, err := tx.Prepare(`
-		INSERT INTO usage_log (user_id, feature_id, timestamp) VALUES (?, ?, ?)
-	`)
-	if err != nil {
-		return
-	}
-	defer stmt.Close()
-
-	for _, entry := range batch {
-		stmt.Exec(entry.UserID, entry.FeatureID, entry.Timestamp)
-	}
+	// Silently fail - in production, log this. Not bound to the writer's
+	// context, which is already cancelled for the final flush.
+	ctx := context.Background()
+	t.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO usage_log (user_id, feature_id, timestamp) VALUES (?, ?, ?)
+		`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
 
-	tx.Commit()
+		for _, entry := range batch {
+			stmt.ExecContext(ctx, entry.UserID, entry.FeatureID, entry.Timestamp)
+		}
+		return nil
+	})
 }
 
 func (t *UsageTracker) cleanupTicker(ctx context.Context) {
 	ticker := time.NewTicker(UsageCleanupInterval)
 	defer ticker.Stop()
+	t.cleanupBeat.Beat()
+	defer t.cleanupBeat.Stop()
 
 	for {
 		select {
@@ -197,32 +242,41 @@ func (t *UsageTracker) cleanupTicker(ctx context.Context) {
 		case <-t.stopCh:
 			return
 		case <-ticker.C:
-			t.cleanup()
+			t.cleanup(ctx)
+			t.cleanupBeat.Beat()
 		}
 	}
 }
 
-func (t *UsageTracker) cleanup() {
+func (t *UsageTracker) cleanup(ctx context.Context) {
 	cutoff := time.Now().Add(-UsageRetentionPeriod)
 
 	// Clean up old usage logs
-	t.repo.db.Exec("DELETE FROM usage_log WHERE timestamp <= ?", cutoff)
+	t.repo.db.ExecContext(ctx, "DELETE FROM usage_log WHERE timestamp <= ?", cutoff)
 
 	// Clean up expired sessions
 	if t.sessionStore != nil {
-		t.sessionStore.CleanupExpiredSessions()
+		t.sessionStore.CleanupExpiredSessions(ctx)
 	}
 
 	// Clean up expired OAuth states
 	if t.stateStore != nil {
-		t.stateStore.CleanupExpiredStates()
+		t.stateStore.CleanupExpiredStates(ctx)
 	}
+
+	// Clean up idempotency keys past their replay window
+	if t.idempotency != nil {
+		t.idempotency.CleanupExpiredKeys(ctx)
+	}
+
+	// Lift suspensions that have reached their suspended_until
+	t.repo.ReactivateExpiredSuspensions(ctx)
 }
 
 // GetUsageStats returns usage statistics for a user
-func (t *UsageTracker) GetUsageStats(userID int64) (map[int64]int, error) {
+func (t *UsageTracker) GetUsageStats(ctx context.Context, userID int64) (map[int64]int, error) {
 	cutoff := time.Now().Add(-UsageRetentionPeriod)
-	rows, err := t.repo.db.Query(`
+	rows, err := t.repo.db.QueryContext(ctx, `
 		SELECT feature_id, COUNT(*) as count
 		FROM usage_log
 		WHERE user_id = ? AND timestamp > ?
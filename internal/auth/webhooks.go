@@ -0,0 +1,344 @@
+package auth
+
+import (
+	"API/internal/events"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// WebhookTimeout bounds a single webhook request
+	WebhookTimeout = 10 * time.Second
+
+	// WebhookPingEvent is the event name of test deliveries
+	WebhookPingEvent = "ping"
+)
+
+// WebhookEventTypes are the auth events admins can subscribe webhooks to
+var WebhookEventTypes = []events.Type{
+	events.TypeUserCreated,
+	events.TypeUserSuspended,
+	events.TypeUserDeleted,
+	events.TypeUserRestored,
+	events.TypeTokenCreated,
+	events.TypeTokenRevoked,
+	events.TypeTokenRestored,
+	events.TypeQuotaExceeded,
+}
+
+// WebhookStore manages admin-registered webhooks. Every webhook is a durable
+// subscriber of the auth outbox, so deliveries are retried with backoff and a
+// failing endpoint never delays or duplicates deliveries to the others.
+type WebhookStore struct {
+	repo   *Repository
+	outbox *events.Outbox
+	client *http.Client
+}
+
+// NewWebhookStore creates a new webhook store
+func NewWebhookStore(repo *Repository, outbox *events.Outbox) *WebhookStore {
+	return &WebhookStore{
+		repo:   repo,
+		outbox: outbox,
+		client: &http.Client{Timeout: WebhookTimeout},
+	}
+}
+
+// Load subscribes every stored webhook to the outbox. Call it before the
+// outbox dispatcher starts so that pending deliveries find their subscriber.
+func (s *WebhookStore) Load(ctx context.Context) error {
+	webhooks, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, w := range webhooks {
+		s.subscribe(&w)
+	}
+	return nil
+}
+
+// List returns all webhooks
+func (s *WebhookStore) List(ctx context.Context) ([]AdminWebhook, error) {
+	rows, err := s.repo.db.QueryContext(ctx, `
+		SELECT id, url, events, format, secret, description, active, created_by, created_at
+		FROM admin_webhooks ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []AdminWebhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *w)
+	}
+	return webhooks, rows.Err()
+}
+
+// Get returns a webhook by ID
+func (s *WebhookStore) Get(ctx context.Context, id int64) (*AdminWebhook, error) {
+	w, err := scanWebhook(s.repo.db.QueryRowContext(ctx, `
+		SELECT id, url, events, format, secret, description, active, created_by, created_at
+		FROM admin_webhooks WHERE id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func scanWebhook(row rowScanner) (*AdminWebhook, error) {
+	var w AdminWebhook
+	var eventList string
+	var desc sql.NullString
+	var createdBy sql.NullInt64
+	if err := row.Scan(&w.ID, &w.URL, &eventList, &w.Format, &w.Secret, &desc, &w.Active, &createdBy, &w.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, t := range strings.Split(eventList, ",") {
+		w.Events = append(w.Events, events.Type(t))
+	}
+	w.Description = ScanNullableString(desc)
+	w.CreatedBy = ScanNullableInt64(createdBy)
+	return &w, nil
+}
+
+// Create registers a webhook and subscribes it. A signing secret is generated
+// when the request does not provide one.
+func (s *WebhookStore) Create(ctx context.Context, req WebhookCreateRequest, createdBy *int64) (*AdminWebhook, error) {
+	if err := validateWebhookEvents(req.Events); err != nil {
+		return nil, err
+	}
+	if req.Format == "" {
+		req.Format = WebhookFormatJSON
+	}
+	if req.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		req.Secret = hex.EncodeToString(secret)
+	}
+
+	var id int64
+	err := s.repo.db.QueryRowContext(ctx, `
+		INSERT INTO admin_webhooks (url, events, format, secret, description, created_by)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`, req.URL, joinEventTypes(req.Events), req.Format, req.Secret, req.Description, createdBy).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.subscribe(w)
+	return w, nil
+}
+
+// Update changes a webhook and re-subscribes it. Returns nil when it does not exist.
+func (s *WebhookStore) Update(ctx context.Context, id int64, req WebhookUpdateRequest) (*AdminWebhook, error) {
+	if req.Events != nil {
+		if err := validateWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+	}
+
+	var eventList *string
+	if req.Events != nil {
+		joined := joinEventTypes(req.Events)
+		eventList = &joined
+	}
+	result, err := s.repo.db.ExecContext(ctx, `
+		UPDATE admin_webhooks
+		SET url = COALESCE(?, url),
+		    events = COALESCE(?, events),
+		    format = COALESCE(?, format),
+		    description = COALESCE(?, description),
+		    active = COALESCE(?, active)
+		WHERE id = ?
+	`, req.URL, eventList, req.Format, req.Description, req.Active, id)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, nil
+	}
+
+	w, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.subscribe(w)
+	return w, nil
+}
+
+// Delete removes a webhook along with its undelivered events
+func (s *WebhookStore) Delete(ctx context.Context, id int64) (bool, error) {
+	result, err := s.repo.db.ExecContext(ctx, "DELETE FROM admin_webhooks WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	if s.outbox != nil {
+		if err := s.outbox.Unsubscribe(webhookSubscriberName(id)); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// Ping sends a test delivery right away, bypassing the outbox, so admins can
+// check the URL and their signature verification when setting a webhook up
+func (s *WebhookStore) Ping(ctx context.Context, w *AdminWebhook) error {
+	return s.send(ctx, w, WebhookPingEvent, map[string]interface{}{"webhookId": w.ID}, "Test event from the OpenSourceDUTH API")
+}
+
+func (s *WebhookStore) subscribe(w *AdminWebhook) {
+	if s.outbox == nil {
+		return
+	}
+	s.outbox.Subscribe(webhookSubscriberName(w.ID), s.deliverer(w.ID), w.Events...)
+}
+
+// deliverer returns the outbox subscriber of a webhook. The webhook is read
+// again on every delivery so that edits and pauses apply to queued events.
+func (s *WebhookStore) deliverer(id int64) events.Subscriber {
+	return func(ctx context.Context, event events.Event) error {
+		w, err := s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if w == nil || !w.Active {
+			return nil
+		}
+		return s.send(ctx, w, string(event.EventType()), event, describeEvent(event))
+	}
+}
+
+// send POSTs an event in the webhook's format. JSON bodies are an envelope of
+// the event name and its data; chat formats carry a one-line summary. Every
+// body is signed with the webhook's secret in X-Webhook-Signature.
+func (s *WebhookStore) send(ctx context.Context, w *AdminWebhook, eventType string, data interface{}, summary string) error {
+	var payload interface{}
+	switch w.Format {
+	case WebhookFormatSlack:
+		payload = map[string]interface{}{"text": summary}
+	case WebhookFormatDiscord:
+		payload = map[string]interface{}{"content": summary}
+	default:
+		payload = map[string]interface{}{"event": eventType, "data": data}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write(body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", eventType)
+	req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %d responded with %s", w.ID, resp.Status)
+	}
+	return nil
+}
+
+func webhookSubscriberName(id int64) string {
+	return fmt.Sprintf("admin-webhook:%d", id)
+}
+
+func validateWebhookEvents(types []events.Type) error {
+	for _, t := range types {
+		known := false
+		for _, supported := range WebhookEventTypes {
+			if t == supported {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("Unsupported webhook event '%s'", t)
+		}
+	}
+	return nil
+}
+
+func joinEventTypes(types []events.Type) string {
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = string(t)
+	}
+	return strings.Join(parts, ",")
+}
+
+// describeEvent summarizes an event in one line for chat webhooks
+func describeEvent(event events.Event) string {
+	switch e := event.(type) {
+	case events.UserCreated:
+		return fmt.Sprintf("New user %s (#%d)", e.Email, e.UserID)
+	case events.UserSuspended:
+		msg := fmt.Sprintf("User #%d was suspended", e.UserID)
+		if e.Reason != nil && *e.Reason != "" {
+			msg += ": " + *e.Reason
+		}
+		if e.SuspendedUntil != nil {
+			msg += " (until " + e.SuspendedUntil.UTC().Format(time.RFC3339) + ")"
+		}
+		return msg
+	case events.UserDeleted:
+		return fmt.Sprintf("User #%d was deleted", e.UserID)
+	case events.UserRestored:
+		return fmt.Sprintf("User #%d was restored", e.UserID)
+	case events.TokenCreated:
+		msg := fmt.Sprintf("Token #%d %q was created for user #%d", e.TokenID, e.Label, e.UserID)
+		if e.AdminCreated {
+			msg += " by an admin"
+		}
+		return msg
+	case events.TokenRevoked:
+		msg := fmt.Sprintf("Token #%d of user #%d was revoked", e.TokenID, e.UserID)
+		if e.ByAdmin {
+			msg += " by an admin"
+		}
+		return msg
+	case events.TokenRestored:
+		return fmt.Sprintf("Token #%d of user #%d was restored", e.TokenID, e.UserID)
+	case events.QuotaExceeded:
+		return fmt.Sprintf("User #%d exceeded the %d RPM limit on %s", e.UserID, e.LimitRPM, e.FeatureSlug)
+	default:
+		return string(event.EventType())
+	}
+}
@@ -0,0 +1,436 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"API/internal/health"
+)
+
+const (
+	// WorkspaceUsageFlushInterval is how often buffered workspace request counts are written
+	WorkspaceUsageFlushInterval = 10 * time.Second
+)
+
+// WorkspaceStore manages course workspaces: an instructor (staff role) issues
+// time-boxed, feature-scoped tokens to a cohort and follows their usage.
+type WorkspaceStore struct {
+	repo       *Repository
+	tokenStore *TokenStore
+	features   *FeatureRegistry
+	usage      *UsageTracker
+
+	// Request counts per token buffered by RecordUsage until the next flush
+	mu        sync.Mutex
+	pending   map[int64]int
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	heartbeat *health.Heartbeat
+}
+
+// NewWorkspaceStore creates a new workspace store
+func NewWorkspaceStore(repo *Repository, tokenStore *TokenStore, features *FeatureRegistry, usage *UsageTracker) *WorkspaceStore {
+	return &WorkspaceStore{
+		repo:       repo,
+		tokenStore: tokenStore,
+		features:   features,
+		usage:      usage,
+		pending:    make(map[int64]int),
+		stopCh:     make(chan struct{}),
+		heartbeat:  health.NewHeartbeat(WorkspaceUsageFlushInterval),
+	}
+}
+
+// CreateWorkspace creates a workspace for an instructor. Admin-only features
+// cannot be granted since instructors do not have admin rights.
+func (s *WorkspaceStore) CreateWorkspace(ctx context.Context, instructorID int64, name string, featureSlugs []string, endsAt time.Time) (*CourseWorkspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("Workspace name is required")
+	}
+	if !endsAt.After(time.Now()) {
+		return nil, fmt.Errorf("Workspace end must be in the future")
+	}
+
+	features, err := s.features.GetFeaturesBySlugs(ctx, featureSlugs)
+	if err != nil {
+		return nil, err
+	}
+	if len(features) == 0 {
+		return nil, fmt.Errorf("At least one valid feature is required")
+	}
+	if len(features) != len(featureSlugs) {
+		return nil, fmt.Errorf("One or more features not found")
+	}
+	for _, f := range features {
+		if f.AdminOnly {
+			return nil, fmt.Errorf("Feature '%s' is admin-only and cannot be granted to students", f.Slug)
+		}
+		if f.ApprovalRequired {
+			return nil, fmt.Errorf("Feature '%s' requires approval and cannot be granted to a whole course", f.Slug)
+		}
+	}
+
+	var id int64
+	err = s.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO course_workspaces (name, instructor_id, ends_at) VALUES (?, ?, ?)
+		`, name, instructorID, endsAt)
+		if err != nil {
+			return err
+		}
+		id, _ = result.LastInsertId()
+
+		for _, f := range features {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO course_workspace_features (workspace_id, feature_id) VALUES (?, ?)
+			`, id, f.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetWorkspace(ctx, id)
+}
+
+// ListWorkspaces returns the workspaces of an instructor, or all of them when instructorID is nil
+func (s *WorkspaceStore) ListWorkspaces(ctx context.Context, instructorID *int64) ([]CourseWorkspace, error) {
+	rows, err := s.repo.db.QueryContext(ctx, `
+		SELECT w.id, w.name, w.instructor_id, w.ends_at, w.created_at,
+		       (SELECT COUNT(*) FROM course_workspace_members m WHERE m.workspace_id = w.id)
+		FROM course_workspaces w
+		WHERE ? IS NULL OR w.instructor_id = ?
+		ORDER BY w.ends_at DESC, w.id DESC
+	`, instructorID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces := []CourseWorkspace{}
+	for rows.Next() {
+		var w CourseWorkspace
+		if err := rows.Scan(&w.ID, &w.Name, &w.InstructorID, &w.EndsAt, &w.CreatedAt, &w.MemberCount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		workspaces = append(workspaces, w)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range workspaces {
+		if workspaces[i].Features, err = s.getWorkspaceFeatures(ctx, workspaces[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return workspaces, nil
+}
+
+// GetWorkspace returns a workspace by ID, or nil if it does not exist
+func (s *WorkspaceStore) GetWorkspace(ctx context.Context, id int64) (*CourseWorkspace, error) {
+	var w CourseWorkspace
+	err := s.repo.db.QueryRowContext(ctx, `
+		SELECT w.id, w.name, w.instructor_id, w.ends_at, w.created_at,
+		       (SELECT COUNT(*) FROM course_workspace_members m WHERE m.workspace_id = w.id)
+		FROM course_workspaces w
+		WHERE w.id = ?
+	`, id).Scan(&w.ID, &w.Name, &w.InstructorID, &w.EndsAt, &w.CreatedAt, &w.MemberCount)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if w.Features, err = s.getWorkspaceFeatures(ctx, id); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (s *WorkspaceStore) getWorkspaceFeatures(ctx context.Context, workspaceID int64) ([]Feature, error) {
+	rows, err := s.repo.db.QueryContext(ctx, `
+		SELECT `+featureColumns+`
+		FROM features f
+		JOIN course_workspace_features wf ON wf.feature_id = f.id
+		WHERE wf.workspace_id = ?
+		ORDER BY f.slug
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	features := []Feature{}
+	for rows.Next() {
+		f, err := scanFeature(rows)
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, *f)
+	}
+	return features, rows.Err()
+}
+
+// EnrollStudents issues a token to each email that belongs to an existing user.
+// Emails without an account are returned as missing (students log in once
+// first), and students who are already enrolled are skipped.
+func (s *WorkspaceStore) EnrollStudents(ctx context.Context, w *CourseWorkspace, emails []string) (issued []IssuedWorkspaceToken, missing, enrolled []string, err error) {
+	issued = []IssuedWorkspaceToken{}
+	missing = []string{}
+	enrolled = []string{}
+
+	if !w.EndsAt.After(time.Now()) {
+		return nil, nil, nil, fmt.Errorf("Workspace has ended")
+	}
+
+	label := "Course: " + w.Name
+	seen := make(map[string]bool)
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		user, err := s.repo.GetUserByEmail(ctx, email)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if user == nil {
+			missing = append(missing, email)
+			continue
+		}
+
+		var exists bool
+		if err := s.repo.db.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM course_workspace_members WHERE workspace_id = ? AND user_id = ?)
+		`, w.ID, user.ID).Scan(&exists); err != nil {
+			return nil, nil, nil, err
+		}
+		if exists {
+			enrolled = append(enrolled, email)
+			continue
+		}
+
+		token, err := s.tokenStore.CreateWorkspaceToken(ctx, user.ID, label, w.Features, w.EndsAt)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if _, err := s.repo.db.ExecContext(ctx, `
+			INSERT INTO course_workspace_members (workspace_id, user_id, token_id) VALUES (?, ?, ?)
+		`, w.ID, user.ID, token.ID); err != nil {
+			return nil, nil, nil, err
+		}
+		issued = append(issued, IssuedWorkspaceToken{UserID: user.ID, Email: email, Token: token.RawToken})
+	}
+	return issued, missing, enrolled, nil
+}
+
+// RemoveStudent revokes a student's workspace token and removes them from the workspace
+func (s *WorkspaceStore) RemoveStudent(ctx context.Context, workspaceID, userID int64) (bool, error) {
+	var tokenID sql.NullInt64
+	err := s.repo.db.QueryRowContext(ctx, `
+		SELECT token_id FROM course_workspace_members WHERE workspace_id = ? AND user_id = ?
+	`, workspaceID, userID).Scan(&tokenID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.revokeMemberToken(ctx, tokenID); err != nil {
+		return false, err
+	}
+	_, err = s.repo.db.ExecContext(ctx, `
+		DELETE FROM course_workspace_members WHERE workspace_id = ? AND user_id = ?
+	`, workspaceID, userID)
+	return err == nil, err
+}
+
+// DeleteWorkspace revokes all student tokens of a workspace and deletes it
+func (s *WorkspaceStore) DeleteWorkspace(ctx context.Context, id int64) error {
+	rows, err := s.repo.db.QueryContext(ctx, `
+		SELECT token_id FROM course_workspace_members WHERE workspace_id = ? AND token_id IS NOT NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+	var tokenIDs []sql.NullInt64
+	for rows.Next() {
+		var tokenID sql.NullInt64
+		if err := rows.Scan(&tokenID); err != nil {
+			rows.Close()
+			return err
+		}
+		tokenIDs = append(tokenIDs, tokenID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, tokenID := range tokenIDs {
+		if err := s.revokeMemberToken(ctx, tokenID); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.repo.db.ExecContext(ctx, "DELETE FROM course_workspaces WHERE id = ?", id)
+	return err
+}
+
+// revokeMemberToken revokes a student token unless it is already revoked
+func (s *WorkspaceStore) revokeMemberToken(ctx context.Context, tokenID sql.NullInt64) error {
+	if !tokenID.Valid {
+		return nil
+	}
+	token, err := s.tokenStore.GetTokenByID(ctx, tokenID.Int64)
+	if err != nil {
+		return err
+	}
+	if token == nil || token.RevokedAt != nil {
+		return nil
+	}
+	return s.tokenStore.AdminRevokeToken(ctx, tokenID.Int64)
+}
+
+// GetMembers returns the students of a workspace with their usage
+func (s *WorkspaceStore) GetMembers(ctx context.Context, workspaceID int64) ([]WorkspaceMember, error) {
+	s.Flush()
+
+	rows, err := s.repo.db.QueryContext(ctx, `
+		SELECT m.id, m.user_id, u.email, u.display_name, m.token_id,
+		       t.id IS NOT NULL AND t.revoked_at IS NULL AND (t.expires_at IS NULL OR t.expires_at > ?),
+		       m.request_count, m.last_used_at, m.created_at
+		FROM course_workspace_members m
+		JOIN users u ON u.id = m.user_id
+		LEFT JOIN tokens t ON t.id = m.token_id
+		WHERE m.workspace_id = ?
+		ORDER BY u.email
+	`, time.Now(), workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []WorkspaceMember{}
+	for rows.Next() {
+		var m WorkspaceMember
+		var tokenID sql.NullInt64
+		var lastUsedAt sql.NullTime
+		if err := rows.Scan(&m.ID, &m.UserID, &m.Email, &m.DisplayName, &tokenID, &m.TokenActive,
+			&m.RequestCount, &lastUsedAt, &m.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		m.TokenID = ScanNullableInt64(tokenID)
+		m.LastUsedAt = ScanNullableTime(lastUsedAt)
+		members = append(members, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Requests in the current window, across the features granted by the workspace
+	features, err := s.getWorkspaceFeatures(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range members {
+		for _, f := range features {
+			rpm, err := s.usage.GetFeatureRPM(ctx, members[i].UserID, f.ID)
+			if err != nil {
+				return nil, err
+			}
+			members[i].CurrentRPM += rpm
+		}
+	}
+	return members, nil
+}
+
+// RecordUsage is a HookPostUsage hook counting requests made with workspace tokens
+func (s *WorkspaceStore) RecordUsage(hc *HookContext) error {
+	if hc.Token == nil {
+		return nil
+	}
+	s.mu.Lock()
+	s.pending[hc.Token.ID]++
+	s.mu.Unlock()
+	return nil
+}
+
+// Start begins the background goroutine flushing buffered request counts
+func (s *WorkspaceStore) Start(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(WorkspaceUsageFlushInterval)
+		defer ticker.Stop()
+		s.heartbeat.Beat()
+		defer s.heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				s.Flush()
+				return
+			case <-s.stopCh:
+				s.Flush()
+				return
+			case <-ticker.C:
+				s.heartbeat.Beat()
+				s.Flush()
+			}
+		}
+	}()
+}
+
+// Heartbeat reports whether the flush loop is running
+func (s *WorkspaceStore) Heartbeat() *health.Heartbeat {
+	return s.heartbeat
+}
+
+// Stop gracefully stops the workspace store
+func (s *WorkspaceStore) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// Flush writes buffered request counts. Tokens outside any workspace match no
+// rows and are simply dropped.
+func (s *WorkspaceStore) Flush() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[int64]int)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	// Errors are dropped, like usage flushing. Not bound to the loop's
+	// context, which is already cancelled for the final flush.
+	ctx := context.Background()
+	now := time.Now()
+	s.repo.WithTx(ctx, func(tx *sql.Tx) error {
+		for tokenID, count := range pending {
+			tx.ExecContext(ctx, `
+				UPDATE course_workspace_members
+				SET request_count = request_count + ?, last_used_at = ?
+				WHERE token_id = ?
+			`, count, now, tokenID)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,280 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"API/internal/health"
+	"API/internal/storage"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	// DefaultInterval is how often snapshots are taken when BACKUP_INTERVAL is unset
+	DefaultInterval = 24 * time.Hour
+
+	// DefaultKeep is how many snapshots of each database are kept when
+	// BACKUP_KEEP is unset
+	DefaultKeep = 7
+
+	// timeFormat sorts lexically in time order, so the newest key sorts last
+	timeFormat = "20060102T150405Z"
+)
+
+// ErrInProgress is returned when a backup is requested while one is running
+var ErrInProgress = errors.New("a backup is already in progress")
+
+// Snapshot is one stored copy of a database
+type Snapshot struct {
+	Database  string    `json:"database"`
+	Key       string    `json:"key"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type database struct {
+	name string
+	db   *sql.DB
+}
+
+// Manager takes consistent snapshots of a tenant's databases with SQLite's
+// online backup API, which copies the pages under a read lock, so requests
+// keep being served while it runs
+type Manager struct {
+	storage   storage.Storage
+	prefix    string // e.g. "duth/", so tenants can share a bucket
+	keep      int
+	interval  time.Duration
+	databases []database
+	running   sync.Mutex
+	heartbeat *health.Heartbeat
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewManager creates a backup manager storing snapshots under prefix and
+// keeping the newest keep of each database
+func NewManager(store storage.Storage, prefix string, keep int, interval time.Duration) *Manager {
+	return &Manager{
+		storage:   store,
+		prefix:    prefix,
+		keep:      keep,
+		interval:  interval,
+		heartbeat: health.NewHeartbeat(interval),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// AddDatabase registers a database to back up under name
+func (m *Manager) AddDatabase(name string, db *sql.DB) {
+	m.databases = append(m.databases, database{name: name, db: db})
+}
+
+// Start begins taking a snapshot every interval
+func (m *Manager) Start(ctx context.Context) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		m.heartbeat.Beat()
+		defer m.heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				m.heartbeat.Beat()
+				if _, err := m.Run(ctx); err != nil {
+					log.Printf("Backup failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Heartbeat reports whether the backup loop is running
+func (m *Manager) Heartbeat() *health.Heartbeat {
+	return m.heartbeat
+}
+
+// Stop gracefully stops the backup loop
+func (m *Manager) Stop() {
+	close(m.stopCh)
+	m.wg.Wait()
+}
+
+// Run snapshots every database, stores the snapshots and prunes the ones
+// past retention. It returns ErrInProgress when a backup is already running.
+func (m *Manager) Run(ctx context.Context) ([]Snapshot, error) {
+	if !m.running.TryLock() {
+		return nil, ErrInProgress
+	}
+	defer m.running.Unlock()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var snapshots []Snapshot
+	for _, d := range m.databases {
+		snapshot, err := m.snapshot(ctx, d, now)
+		if err != nil {
+			return snapshots, fmt.Errorf("%s: %w", d.name, err)
+		}
+		snapshots = append(snapshots, *snapshot)
+		if err := m.prune(ctx, d.name); err != nil {
+			return snapshots, fmt.Errorf("%s: failed to prune: %w", d.name, err)
+		}
+	}
+	return snapshots, nil
+}
+
+// List returns the stored snapshots, newest first
+func (m *Manager) List(ctx context.Context) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	for _, d := range m.databases {
+		objects, err := m.storage.List(ctx, m.keyPrefix(d.name))
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range objects {
+			snapshot, ok := m.parseKey(d.name, o)
+			if ok {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// snapshot copies one database into a temporary file and stores it
+func (m *Manager) snapshot(ctx context.Context, d database, now time.Time) (*Snapshot, error) {
+	tmp, err := os.CreateTemp("", "backup-"+d.name+"-*.db")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := copyDatabase(ctx, d.db, tmp.Name()); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	key := m.keyPrefix(d.name) + now.Format(timeFormat) + ".db"
+	if err := m.storage.Put(ctx, key, file, info.Size()); err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return &Snapshot{Database: d.name, Key: key, Size: info.Size(), CreatedAt: now}, nil
+}
+
+// prune deletes all but the newest keep snapshots of a database
+func (m *Manager) prune(ctx context.Context, name string) error {
+	if m.keep <= 0 {
+		return nil
+	}
+	objects, err := m.storage.List(ctx, m.keyPrefix(name))
+	if err != nil {
+		return err
+	}
+	var keys []string
+	for _, o := range objects {
+		if _, ok := m.parseKey(name, o); ok {
+			keys = append(keys, o.Key)
+		}
+	}
+	sort.Strings(keys)
+	for len(keys) > m.keep {
+		if err := m.storage.Delete(ctx, keys[0]); err != nil {
+			return err
+		}
+		keys = keys[1:]
+	}
+	return nil
+}
+
+func (m *Manager) keyPrefix(name string) string {
+	return m.prefix + name + "-"
+}
+
+// parseKey reads the time a snapshot was taken from its key, skipping
+// objects that were not written by a backup
+func (m *Manager) parseKey(name string, o storage.Object) (Snapshot, bool) {
+	stamp, ok := strings.CutSuffix(strings.TrimPrefix(o.Key, m.keyPrefix(name)), ".db")
+	if !ok {
+		return Snapshot{}, false
+	}
+	created, err := time.Parse(timeFormat, stamp)
+	if err != nil {
+		return Snapshot{}, false
+	}
+	return Snapshot{Database: name, Key: o.Key, Size: o.Size, CreatedAt: created}, true
+}
+
+// sqliteConn returns the SQLite connection under a driver connection, which the
+// databases opened to log slow queries wrap
+func sqliteConn(driverConn any) *sqlite3.SQLiteConn {
+	if wrapped, ok := driverConn.(interface{ Unwrap() driver.Conn }); ok {
+		driverConn = wrapped.Unwrap()
+	}
+	return driverConn.(*sqlite3.SQLiteConn)
+}
+
+// copyDatabase writes a consistent copy of src to the SQLite file at path
+func copyDatabase(ctx context.Context, src *sql.DB, path string) error {
+	dst, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	dstConn, err := dst.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close()
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	return dstConn.Raw(func(dstDriver any) error {
+		return srcConn.Raw(func(srcDriver any) error {
+			backup, err := dstDriver.(*sqlite3.SQLiteConn).Backup("main", sqliteConn(srcDriver), "main")
+			if err != nil {
+				return err
+			}
+			// -1 copies every page in one step, so the copy is a single
+			// point in time
+			if _, err := backup.Step(-1); err != nil {
+				backup.Finish()
+				return err
+			}
+			return backup.Finish()
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package backup
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"API/internal/apierror"
+	"API/internal/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the backup admin endpoints
+type Handler struct {
+	manager *Manager
+}
+
+// NewHandler creates a new backup handler
+func NewHandler(manager *Manager) *Handler {
+	return &Handler{manager: manager}
+}
+
+// ListBackups returns the stored snapshots, newest first
+// GET /admin/backups
+func (h *Handler) ListBackups(c *gin.Context) {
+	snapshots, err := h.manager.List(c.Request.Context())
+	if err != nil {
+		log.Printf("Failed to list backups: %v", err)
+		common.JSON(c, http.StatusBadGateway, common.CreateErrorResponse(apierror.New(apierror.UpstreamFailed, "failed to list backups")))
+		return
+	}
+
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"backups": snapshots,
+	}))
+}
+
+// CreateBackup snapshots every database now, outside the schedule
+// POST /admin/backups
+func (h *Handler) CreateBackup(c *gin.Context) {
+	snapshots, err := h.manager.Run(c.Request.Context())
+	if errors.Is(err, ErrInProgress) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		log.Printf("Backup failed: %v", err)
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "backup failed")))
+		return
+	}
+
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"backups": snapshots,
+	}))
+}
@@ -0,0 +1,24 @@
+package backup
+
+import (
+	"net/http"
+	"time"
+
+	"API/internal/auth"
+	"API/internal/limits"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers the backup admin routes
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	admin := rg.Group("/admin")
+	admin.Use(authMiddleware.RequireSession())
+	admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	admin.Use(authMiddleware.Idempotent())
+	{
+		admin.GET("/backups", h.ListBackups)
+		admin.POST("/backups", h.CreateBackup)
+		limits.SetRoute(http.MethodPost, admin.BasePath()+"/backups", limits.Limits{Timeout: 10 * time.Minute})
+	}
+}
@@ -0,0 +1,142 @@
+// Package calendar holds the calendars the modules publish for calendar apps, and
+// renders them as iCalendar (RFC 5545).
+package calendar
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MIMEType is the media type of iCalendar data
+const MIMEType = "text/calendar; charset=utf-8"
+
+// Entry is an event of a calendar
+type Entry struct {
+	// UID identifies the entry across requests, e.g. "event-12"; the host serving it is
+	// appended to make it globally unique. It only holds letters, digits and dashes, so it
+	// can name the entry in URLs.
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Categories  []string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool      // Start and End are dates in their location; End is exclusive
+	Modified    time.Time // Zero when unknown
+}
+
+// Collection is a calendar a module publishes, e.g. the cafeteria's meals
+type Collection struct {
+	// Name identifies the calendar in URLs, e.g. "meals"
+	Name string
+	// Feature is the feature a token needs to see the calendar
+	Feature string
+	// Color is shown by calendar apps that support it, as #RRGGBB
+	Color string
+	// Title returns the calendar's name in a language
+	Title func(lang string) string
+	// Entries returns the events overlapping from and to in a language. Calendars of a
+	// user's own data, e.g. their bookings, use userID; others ignore it.
+	Entries func(ctx context.Context, userID int64, lang string, from, to time.Time) ([]Entry, error)
+}
+
+// icalTime is the UTC form iCalendar date-times take
+const icalTime = "20060102T150405Z"
+
+// icalDate is the form of all-day dates
+const icalDate = "20060102"
+
+// icalEscaper escapes the characters RFC 5545 reserves in text values
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// Encode renders entries as a calendar that calendar apps can subscribe to. host makes
+// the entry UIDs unique to this API.
+func Encode(name string, entries []Entry, host string, now time.Time) []byte {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//OpenSourceDUTH//API//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+icalEscaper.Replace(name))
+	}
+	for _, e := range entries {
+		writeEvent(&b, e, host, now)
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+func writeEvent(b *strings.Builder, e Entry, host string, now time.Time) {
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, "UID:"+e.UID+"@"+host)
+	writeLine(b, "DTSTAMP:"+now.UTC().Format(icalTime))
+	if !e.Modified.IsZero() {
+		writeLine(b, "LAST-MODIFIED:"+e.Modified.UTC().Format(icalTime))
+	}
+	if e.AllDay {
+		writeLine(b, "DTSTART;VALUE=DATE:"+e.Start.Format(icalDate))
+		writeLine(b, "DTEND;VALUE=DATE:"+e.End.Format(icalDate))
+	} else {
+		writeLine(b, "DTSTART:"+e.Start.UTC().Format(icalTime))
+		writeLine(b, "DTEND:"+e.End.UTC().Format(icalTime))
+	}
+	writeLine(b, "SUMMARY:"+icalEscaper.Replace(e.Summary))
+	if e.Location != "" {
+		writeLine(b, "LOCATION:"+icalEscaper.Replace(e.Location))
+	}
+	if len(e.Categories) > 0 {
+		categories := make([]string, len(e.Categories))
+		for i, category := range e.Categories {
+			categories[i] = icalEscaper.Replace(category)
+		}
+		writeLine(b, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+	if e.Description != "" {
+		writeLine(b, "DESCRIPTION:"+icalEscaper.Replace(e.Description))
+	}
+	if e.URL != "" {
+		writeLine(b, "URL:"+e.URL)
+	}
+	writeLine(b, "END:VEVENT")
+}
+
+// ETag identifies the content of an entry, so calendar apps only fetch the entries that
+// changed. It leaves out the stamp of the response, which changes on every request.
+func (e Entry) ETag() string {
+	var b strings.Builder
+	writeEvent(&b, e, "", time.Time{})
+	sum := sha256.Sum256([]byte(b.String()))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// Overlaps reports whether the entry takes place between from and to; zero bounds are open
+func (e Entry) Overlaps(from, to time.Time) bool {
+	return (from.IsZero() || e.End.After(from)) && (to.IsZero() || e.Start.Before(to))
+}
+
+// writeLine writes a content line, folded at 75 octets without splitting characters as
+// RFC 5545 requires
+func writeLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards their length
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
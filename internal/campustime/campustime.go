@@ -0,0 +1,68 @@
+// Package campustime holds the time zone the university runs on and the weekly opening
+// hours the campus modules share.
+package campustime
+
+import (
+	"fmt"
+	"slices"
+	"time"
+	_ "time/tzdata" // the API may run in an image without a zoneinfo database
+
+	"API/internal/apierror"
+)
+
+// Location is the time zone of the campus, so dates and opening hours follow the
+// students' clock rather than the server's
+var Location = mustLoadLocation("Europe/Athens")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// Today returns the current date in Location, at midnight
+func Today() time.Time {
+	y, m, d := time.Now().In(Location).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, Location)
+}
+
+// Weekdays are the names OpeningHours take, indexed by time.Weekday
+var Weekdays = [7]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// OpeningHours is when a place is open on a weekday, as HH:MM in Location
+type OpeningHours struct {
+	Day    string `json:"day" binding:"required,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	Opens  string `json:"opens" binding:"required,datetime=15:04"`
+	Closes string `json:"closes" binding:"required,datetime=15:04"`
+}
+
+// IsOpen reports whether hours include the time t
+func IsOpen(hours []OpeningHours, t time.Time) bool {
+	t = t.In(Location)
+	day, clock := Weekdays[t.Weekday()], t.Format("15:04")
+	return slices.ContainsFunc(hours, func(h OpeningHours) bool {
+		return h.Day == day && h.Opens <= clock && clock < h.Closes
+	})
+}
+
+// ValidateHours checks that every period closes after it opens and that periods of the
+// same day do not overlap
+func ValidateHours(hours []OpeningHours) []apierror.Error {
+	var errs []apierror.Error
+	for i, h := range hours {
+		if h.Closes <= h.Opens {
+			errs = append(errs, apierror.Invalid(fmt.Sprintf("hours[%d].closes", i), "closes must be after opens"))
+			continue
+		}
+		for j, other := range hours[:i] {
+			if other.Day == h.Day && h.Opens < other.Closes && other.Opens < h.Closes {
+				errs = append(errs, apierror.Invalid(fmt.Sprintf("hours[%d].opens", i), fmt.Sprintf("overlaps hours[%d] on %s", j, h.Day)))
+				break
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,64 @@
+package campustime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsOpen(t *testing.T) {
+	hours := []OpeningHours{
+		{Day: "monday", Opens: "08:00", Closes: "14:00"},
+		{Day: "monday", Opens: "16:00", Closes: "22:00"},
+	}
+	// May 6th 2024 is a Monday, in summer time, and December 2nd 2024 one in winter time
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"at opening", time.Date(2024, time.May, 6, 8, 0, 0, 0, Location), true},
+		{"before opening", time.Date(2024, time.May, 6, 7, 59, 0, 0, Location), false},
+		{"at closing", time.Date(2024, time.May, 6, 14, 0, 0, 0, Location), false},
+		{"during a break", time.Date(2024, time.May, 6, 15, 0, 0, 0, Location), false},
+		{"in the second period", time.Date(2024, time.May, 6, 21, 59, 0, 0, Location), true},
+		{"on another day", time.Date(2024, time.May, 7, 9, 0, 0, 0, Location), false},
+		{"in summer, UTC is 3 hours behind", time.Date(2024, time.May, 6, 5, 0, 0, 0, time.UTC), true},
+		{"in winter, UTC is 2 hours behind", time.Date(2024, time.December, 2, 5, 30, 0, 0, time.UTC), false},
+		{"on Monday on campus but Sunday in UTC", time.Date(2024, time.May, 5, 22, 30, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOpen(hours, tt.t); got != tt.want {
+				t.Errorf("IsOpen(%s) = %t, want %t", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateHours(t *testing.T) {
+	tests := []struct {
+		name       string
+		hours      []OpeningHours
+		wantFields []string
+	}{
+		{"valid", []OpeningHours{{"monday", "08:00", "14:00"}, {"monday", "14:00", "18:00"}, {"tuesday", "08:00", "18:00"}}, nil},
+		{"closes before opening", []OpeningHours{{"monday", "14:00", "08:00"}}, []string{"hours[0].closes"}},
+		{"closes when opening", []OpeningHours{{"monday", "08:00", "08:00"}}, []string{"hours[0].closes"}},
+		{"overlapping periods", []OpeningHours{{"monday", "08:00", "14:00"}, {"monday", "13:00", "18:00"}}, []string{"hours[1].opens"}},
+		{"a period within another", []OpeningHours{{"monday", "08:00", "18:00"}, {"monday", "10:00", "12:00"}}, []string{"hours[1].opens"}},
+		{"the same hours on other days", []OpeningHours{{"monday", "08:00", "14:00"}, {"sunday", "08:00", "14:00"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateHours(tt.hours)
+			if len(errs) != len(tt.wantFields) {
+				t.Fatalf("errors = %v, want ones for %v", errs, tt.wantFields)
+			}
+			for i, err := range errs {
+				if err.Field != tt.wantFields[i] {
+					t.Errorf("error %d is for %s, want %s", i, err.Field, tt.wantFields[i])
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"API/internal/apierror"
 	"net/http"
 	"time"
 
@@ -36,7 +37,13 @@ func Status(c *gin.Context) {
 		Uptime:                uptime().Truncate(time.Second).String(),
 	}
 	response := CreateSuccessResponse(data)
-	c.JSON(http.StatusOK, response)
+	JSON(c, http.StatusOK, response)
+}
+
+// ErrorCatalog lists every error code responses can carry
+// GET /api/errors
+func ErrorCatalog(c *gin.Context) {
+	Render(c, http.StatusOK, CreateSuccessResponse(apierror.Catalog()))
 }
 
 //This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
@@ -3,6 +3,11 @@ package common
 import (
 	"time"
 
+	"API/internal/apierror"
+	"API/internal/logging"
+	"API/internal/negotiate"
+
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
@@ -11,17 +16,39 @@ import (
 type Metadata struct {
 	Timestamp time.Time `json:"timestamp"`
 	RequestID string    `json:"requestId"`
+	// Set on paginated lists that have another page; pass it back as ?cursor=
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 type APIResponse struct {
-	Data     interface{} `json:"data"`
-	Errors   []string    `json:"errors"`
-	Metadata Metadata    `json:"metadata"`
+	Data     interface{}      `json:"data"`
+	Errors   []apierror.Error `json:"errors"`
+	Metadata Metadata         `json:"metadata"`
 }
 
 // Response functions
 
-func CreateAPIResponse(data interface{}, errors []string, requestID string) APIResponse {
+// JSON writes the response as JSON, carrying the request's ID so that the
+// body, the X-Request-ID header and the request's log line agree
+func JSON(c *gin.Context, status int, response APIResponse) {
+	response.Metadata.RequestID = logging.RequestID(c)
+	c.JSON(status, response)
+}
+
+// Abort writes the response like JSON and stops the handler chain
+func Abort(c *gin.Context, status int, response APIResponse) {
+	c.Abort()
+	JSON(c, status, response)
+}
+
+// Render writes the response as JSON, XML, MessagePack or CSV depending on
+// the request's Accept header; JSON when it names none of them
+func Render(c *gin.Context, status int, response APIResponse) {
+	response.Metadata.RequestID = logging.RequestID(c)
+	negotiate.Write(c, status, response, response.Data, response.Errors)
+}
+
+func CreateAPIResponse(data interface{}, errors []apierror.Error, requestID string) APIResponse {
 	// If the requestID is blank and not cascading from other functions generate a new one
 	if requestID == "" {
 		requestID = uuid.New().String()
@@ -39,12 +66,19 @@ func CreateAPIResponse(data interface{}, errors []string, requestID string) APIR
 func CreateSuccessResponse(data interface{}) APIResponse {
 	return CreateAPIResponse(
 		data,
-		[]string{},
+		[]apierror.Error{},
 		"",
 	)
 }
 
-func CreateErrorResponse(errors []string) APIResponse {
+// CreatePaginatedResponse is a success response for one page of a list
+func CreatePaginatedResponse(data interface{}, nextCursor string) APIResponse {
+	response := CreateSuccessResponse(data)
+	response.Metadata.NextCursor = nextCursor
+	return response
+}
+
+func CreateErrorResponse(errors ...apierror.Error) APIResponse {
 	return CreateAPIResponse(
 		nil,
 		errors,
@@ -55,12 +89,12 @@ func CreateErrorResponse(errors []string) APIResponse {
 func CreateSuccessResponseWithRequestID(data interface{}, requestID string) APIResponse {
 	return CreateAPIResponse(
 		data,
-		[]string{},
+		[]apierror.Error{},
 		requestID,
 	)
 }
 
-func CreateErrorResponseWithRequestID(errors []string, requestID string) APIResponse {
+func CreateErrorResponseWithRequestID(errors []apierror.Error, requestID string) APIResponse {
 	return CreateAPIResponse(
 		nil,
 		errors,
@@ -4,6 +4,7 @@ import "github.com/gin-gonic/gin"
 
 func RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("/status", Status)
+	rg.GET("/errors", ErrorCatalog)
 }
 
 //This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
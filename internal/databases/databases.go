@@ -0,0 +1,32 @@
+// Package databases holds what the v0 repositories share about their SQLite databases.
+//
+// Repositories delete dependent rows themselves instead of relying on ON DELETE CASCADE:
+// foreign keys can be turned off with SQLITE_FOREIGN_KEYS, so the cascade is not relied on.
+package databases
+
+import (
+	"context"
+	"database/sql"
+)
+
+// WithTx runs fn in a transaction on db, committing when it returns nil and rolling back
+// otherwise
+func WithTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// NullString stores empty optional text as NULL
+func NullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
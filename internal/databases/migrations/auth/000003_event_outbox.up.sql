@@ -0,0 +1,16 @@
+-- Events waiting to be delivered to durable subscribers (one row per subscriber)
+CREATE TABLE event_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscriber TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    failed_at TIMESTAMP, -- set once attempts are exhausted
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for the dispatcher's pending scan
+CREATE INDEX idx_event_outbox_pending ON event_outbox(delivered_at, failed_at, next_attempt_at);
@@ -0,0 +1,2 @@
+-- Maximum simultaneous sessions per user in the group; 0 means unlimited
+ALTER TABLE groups ADD COLUMN max_sessions INTEGER NOT NULL DEFAULT 0 CHECK (max_sessions >= 0);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_user_tags_tag;
+DROP TABLE IF EXISTS user_tags;
+
+DROP INDEX IF EXISTS idx_user_notes_user;
+DROP TABLE IF EXISTS user_notes;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_users_suspended_until;
+
+ALTER TABLE users DROP COLUMN suspended_until;
+ALTER TABLE users DROP COLUMN suspended_at;
+ALTER TABLE users DROP COLUMN suspended_by;
+ALTER TABLE users DROP COLUMN suspension_reason;
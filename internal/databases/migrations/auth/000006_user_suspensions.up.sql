@@ -0,0 +1,8 @@
+-- Context for suspended accounts; cleared when the account is reactivated
+ALTER TABLE users ADD COLUMN suspension_reason TEXT;
+ALTER TABLE users ADD COLUMN suspended_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN suspended_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN suspended_until TIMESTAMP; -- NULL means until lifted by an admin
+
+-- Index for the background job that lifts expired suspensions
+CREATE INDEX idx_users_suspended_until ON users(suspended_until) WHERE suspended_until IS NOT NULL;
@@ -0,0 +1,33 @@
+DROP TABLE IF EXISTS course_workspace_members;
+DROP TABLE IF EXISTS course_workspace_features;
+DROP INDEX IF EXISTS idx_course_workspaces_instructor;
+DROP TABLE IF EXISTS course_workspaces;
+
+-- Staff members become regular users again
+CREATE TABLE users_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL UNIQUE,
+    display_name TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended')),
+    group_id INTEGER NOT NULL,
+    max_tokens INTEGER NOT NULL DEFAULT 5,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    suspension_reason TEXT,
+    suspended_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    suspended_at TIMESTAMP,
+    suspended_until TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES groups(id)
+);
+
+INSERT INTO users_old (id, email, display_name, role, status, group_id, max_tokens, created_at,
+                       suspension_reason, suspended_by, suspended_at, suspended_until)
+SELECT id, email, display_name, CASE WHEN role = 'staff' THEN 'user' ELSE role END, status, group_id, max_tokens, created_at,
+       suspension_reason, suspended_by, suspended_at, suspended_until
+FROM users;
+
+DROP INDEX IF EXISTS idx_users_suspended_until;
+DROP TABLE users;
+ALTER TABLE users_old RENAME TO users;
+
+CREATE INDEX idx_users_suspended_until ON users(suspended_until) WHERE suspended_until IS NOT NULL;
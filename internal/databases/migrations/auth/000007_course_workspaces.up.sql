@@ -0,0 +1,66 @@
+-- Allow the 'staff' role. SQLite cannot alter a CHECK constraint, so the users
+-- table is rebuilt; foreign keys are off during migrations (the driver default)
+-- so dependent rows are kept.
+CREATE TABLE users_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL UNIQUE,
+    display_name TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'staff', 'admin')),
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended')),
+    group_id INTEGER NOT NULL,
+    max_tokens INTEGER NOT NULL DEFAULT 5,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    suspension_reason TEXT,
+    suspended_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    suspended_at TIMESTAMP,
+    suspended_until TIMESTAMP, -- NULL means until lifted by an admin
+    FOREIGN KEY (group_id) REFERENCES groups(id)
+);
+
+INSERT INTO users_new (id, email, display_name, role, status, group_id, max_tokens, created_at,
+                       suspension_reason, suspended_by, suspended_at, suspended_until)
+SELECT id, email, display_name, role, status, group_id, max_tokens, created_at,
+       suspension_reason, suspended_by, suspended_at, suspended_until
+FROM users;
+
+DROP INDEX IF EXISTS idx_users_suspended_until;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE INDEX idx_users_suspended_until ON users(suspended_until) WHERE suspended_until IS NOT NULL;
+
+-- Course workspaces let staff issue time-boxed tokens to a cohort of students
+CREATE TABLE course_workspaces (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    instructor_id INTEGER NOT NULL,
+    ends_at TIMESTAMP NOT NULL, -- student tokens expire at this time
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (instructor_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_course_workspaces_instructor ON course_workspaces(instructor_id);
+
+-- Features granted to every student token of a workspace
+CREATE TABLE course_workspace_features (
+    workspace_id INTEGER NOT NULL,
+    feature_id INTEGER NOT NULL,
+    PRIMARY KEY (workspace_id, feature_id),
+    FOREIGN KEY (workspace_id) REFERENCES course_workspaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (feature_id) REFERENCES features(id) ON DELETE CASCADE
+);
+
+-- Students enrolled in a workspace and the token issued to each
+CREATE TABLE course_workspace_members (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    workspace_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    token_id INTEGER UNIQUE,
+    request_count INTEGER NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (workspace_id, user_id),
+    FOREIGN KEY (workspace_id) REFERENCES course_workspaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (token_id) REFERENCES tokens(id) ON DELETE SET NULL
+);
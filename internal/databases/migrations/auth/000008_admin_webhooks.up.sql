@@ -0,0 +1,12 @@
+-- Webhook endpoints registered by admins to receive auth events (e.g. for Discord, Slack or monitoring)
+CREATE TABLE admin_webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    events TEXT NOT NULL, -- comma-separated event types
+    format TEXT NOT NULL DEFAULT 'json' CHECK (format IN ('json', 'slack', 'discord')),
+    secret TEXT NOT NULL, -- HMAC key for X-Webhook-Signature
+    description TEXT,
+    active BOOLEAN NOT NULL DEFAULT 1,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- Tokens with debug_timing get a Server-Timing breakdown on every response
+ALTER TABLE tokens ADD COLUMN debug_timing BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE features DROP COLUMN dedup_window_ms;
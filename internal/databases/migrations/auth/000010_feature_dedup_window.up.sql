@@ -0,0 +1,2 @@
+-- Identical GET requests from one token within this window are coalesced (0 disables)
+ALTER TABLE features ADD COLUMN dedup_window_ms INTEGER NOT NULL DEFAULT 0;
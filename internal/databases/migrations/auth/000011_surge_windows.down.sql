@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS surge_window_groups;
+DROP INDEX IF EXISTS idx_surge_windows_ends_at;
+DROP TABLE IF EXISTS surge_windows;
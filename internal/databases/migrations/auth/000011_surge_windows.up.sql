@@ -0,0 +1,22 @@
+-- Predefined high-traffic periods (exam weeks, registration days). While a
+-- window is active its groups get their quotas multiplied and caches shorten
+-- their TTLs.
+CREATE TABLE surge_windows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    rpm_multiplier REAL NOT NULL CHECK (rpm_multiplier > 0),
+    cache_ttl_factor REAL NOT NULL DEFAULT 1 CHECK (cache_ttl_factor > 0 AND cache_ttl_factor <= 1),
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_surge_windows_ends_at ON surge_windows(ends_at);
+
+CREATE TABLE surge_window_groups (
+    surge_window_id INTEGER NOT NULL REFERENCES surge_windows(id) ON DELETE CASCADE,
+    group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    PRIMARY KEY (surge_window_id, group_id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE features DROP COLUMN sunset_at;
+ALTER TABLE features DROP COLUMN deprecated;
@@ -0,0 +1,3 @@
+-- Deprecated features are announced with Deprecation/Sunset response headers
+ALTER TABLE features ADD COLUMN deprecated BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE features ADD COLUMN sunset_at TIMESTAMP; -- NULL when no retirement date is set
@@ -0,0 +1,3 @@
+ALTER TABLE features DROP COLUMN example;
+ALTER TABLE features DROP COLUMN docs_url;
+ALTER TABLE features DROP COLUMN description;
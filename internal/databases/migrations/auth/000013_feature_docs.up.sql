@@ -0,0 +1,4 @@
+-- Documentation shown in the public feature catalog
+ALTER TABLE features ADD COLUMN description TEXT;
+ALTER TABLE features ADD COLUMN docs_url TEXT;
+ALTER TABLE features ADD COLUMN example TEXT; -- e.g. an example request
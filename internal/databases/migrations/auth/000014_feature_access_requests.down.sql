@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_feature_access_requests_status;
+DROP INDEX IF EXISTS idx_feature_access_requests_open;
+DROP TABLE IF EXISTS feature_access_requests;
+ALTER TABLE features DROP COLUMN approval_required;
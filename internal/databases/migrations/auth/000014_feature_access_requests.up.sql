@@ -0,0 +1,19 @@
+-- Features that users may only add to their tokens once an admin approves a request
+ALTER TABLE features ADD COLUMN approval_required BOOLEAN NOT NULL DEFAULT 0;
+
+CREATE TABLE feature_access_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    feature_id INTEGER NOT NULL REFERENCES features(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied', 'revoked')),
+    reason TEXT, -- why the user needs the feature
+    decided_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    decided_at TIMESTAMP,
+    decision_note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- At most one open or granted request per user and feature
+CREATE UNIQUE INDEX idx_feature_access_requests_open
+    ON feature_access_requests(user_id, feature_id) WHERE status IN ('pending', 'approved');
+CREATE INDEX idx_feature_access_requests_status ON feature_access_requests(status);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_feature_usage_hourly_hour;
+DROP INDEX IF EXISTS idx_feature_usage_hourly_feature;
+DROP TABLE IF EXISTS feature_usage_hourly;
@@ -0,0 +1,13 @@
+-- Requests per token and feature bucketed by hour (UTC), kept longer than
+-- usage_log so admins can see who relies on a feature
+CREATE TABLE feature_usage_hourly (
+    token_id INTEGER NOT NULL REFERENCES tokens(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    feature_id INTEGER NOT NULL REFERENCES features(id) ON DELETE CASCADE,
+    hour TEXT NOT NULL, -- 'YYYY-MM-DD HH:00:00'
+    request_count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (token_id, feature_id, hour)
+);
+
+CREATE INDEX idx_feature_usage_hourly_feature ON feature_usage_hourly(feature_id, hour);
+CREATE INDEX idx_feature_usage_hourly_hour ON feature_usage_hourly(hour);
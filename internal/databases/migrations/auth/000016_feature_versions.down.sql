@@ -0,0 +1,2 @@
+ALTER TABLE token_features DROP COLUMN version;
+ALTER TABLE features DROP COLUMN latest_version;
@@ -0,0 +1,3 @@
+-- Features can serve several response formats; tokens pin one with 'slug@vN'
+ALTER TABLE features ADD COLUMN latest_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE token_features ADD COLUMN version INTEGER; -- NULL = v1, the original format
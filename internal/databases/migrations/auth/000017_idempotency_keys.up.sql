@@ -0,0 +1,13 @@
+-- Responses to POST requests sent with an Idempotency-Key, replayed on retries
+CREATE TABLE idempotency_keys (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    fingerprint TEXT NOT NULL, -- SHA-256 of method, URL and body
+    status INTEGER NOT NULL DEFAULT 0, -- 0 while the original request is in flight
+    content_type TEXT,
+    body BLOB,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys(created_at);
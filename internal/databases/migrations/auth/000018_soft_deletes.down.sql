@@ -0,0 +1,2 @@
+ALTER TABLE groups DROP COLUMN deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
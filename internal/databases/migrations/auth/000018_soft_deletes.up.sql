@@ -0,0 +1,4 @@
+-- Deleted users and groups keep their rows, so tokens, quotas, notes and the
+-- event history that reference them stay intact and can be restored
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP; -- NULL while the user exists
+ALTER TABLE groups ADD COLUMN deleted_at TIMESTAMP; -- NULL while the group exists
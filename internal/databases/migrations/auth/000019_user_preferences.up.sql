@@ -0,0 +1,9 @@
+-- Dietary preferences users set for themselves, used to personalize cafeteria menus.
+-- kind 'diet' is a diet the user follows; 'allergen' is one they avoid.
+CREATE TABLE user_preferences (
+    user_id INTEGER NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('diet', 'allergen')),
+    value TEXT NOT NULL,
+    PRIMARY KEY (user_id, kind, value),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
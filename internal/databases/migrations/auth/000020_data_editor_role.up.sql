@@ -0,0 +1,31 @@
+-- Allow the 'data-editor' role. SQLite cannot alter a CHECK constraint, so the
+-- users table is rebuilt; foreign keys are off during migrations (the driver
+-- default) so dependent rows are kept.
+CREATE TABLE users_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL UNIQUE,
+    display_name TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'staff', 'data-editor', 'admin')),
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended')),
+    group_id INTEGER NOT NULL,
+    max_tokens INTEGER NOT NULL DEFAULT 5,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    suspension_reason TEXT,
+    suspended_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    suspended_at TIMESTAMP,
+    suspended_until TIMESTAMP, -- NULL means until lifted by an admin
+    deleted_at TIMESTAMP, -- NULL while the user exists
+    FOREIGN KEY (group_id) REFERENCES groups(id)
+);
+
+INSERT INTO users_new (id, email, display_name, role, status, group_id, max_tokens, created_at,
+                       suspension_reason, suspended_by, suspended_at, suspended_until, deleted_at)
+SELECT id, email, display_name, role, status, group_id, max_tokens, created_at,
+       suspension_reason, suspended_by, suspended_at, suspended_until, deleted_at
+FROM users;
+
+DROP INDEX IF EXISTS idx_users_suspended_until;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE INDEX idx_users_suspended_until ON users(suspended_until) WHERE suspended_until IS NOT NULL;
@@ -0,0 +1,21 @@
+-- Staff and data-editor invitations become regular user invitations again
+CREATE TABLE invitations_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL UNIQUE,
+    group_id INTEGER NOT NULL,
+    role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+    invited_by INTEGER,
+    expires_at TIMESTAMP,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+INSERT INTO invitations_old (id, email, group_id, role, invited_by, expires_at, accepted_at, created_at)
+SELECT id, email, group_id, CASE WHEN role IN ('user', 'admin') THEN role ELSE 'user' END, invited_by,
+       expires_at, accepted_at, created_at
+FROM invitations;
+
+DROP TABLE invitations;
+ALTER TABLE invitations_old RENAME TO invitations;
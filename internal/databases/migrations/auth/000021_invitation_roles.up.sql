@@ -0,0 +1,22 @@
+-- Invitations may pre-assign every role users can have. 000007 and 000020 widened the
+-- users CHECK only, so staff and data-editor invitations failed to insert; the table
+-- is rebuilt the same way.
+CREATE TABLE invitations_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL UNIQUE,
+    group_id INTEGER NOT NULL,
+    role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'staff', 'data-editor', 'admin')),
+    invited_by INTEGER,
+    expires_at TIMESTAMP, -- NULL means non-expiring
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+INSERT INTO invitations_new (id, email, group_id, role, invited_by, expires_at, accepted_at, created_at)
+SELECT id, email, group_id, role, invited_by, expires_at, accepted_at, created_at
+FROM invitations;
+
+DROP TABLE invitations;
+ALTER TABLE invitations_new RENAME TO invitations;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS course_instructors;
+DROP INDEX IF EXISTS idx_courses_semester;
+DROP TABLE IF EXISTS courses;
+DROP TABLE IF EXISTS departments;
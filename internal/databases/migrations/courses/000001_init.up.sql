@@ -0,0 +1,31 @@
+-- Departments publishing a course catalog, identified by a short code (e.g. "CS")
+CREATE TABLE departments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    name TEXT NOT NULL,
+    name_en TEXT
+);
+
+-- Courses of a department's curriculum, by the semester they are taught in
+CREATE TABLE courses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    department_id INTEGER NOT NULL,
+    code TEXT NOT NULL COLLATE NOCASE,
+    title TEXT NOT NULL,
+    title_en TEXT,
+    semester INTEGER NOT NULL CHECK (semester BETWEEN 1 AND 12),
+    ects REAL NOT NULL CHECK (ects >= 0),
+    UNIQUE (department_id, code),
+    FOREIGN KEY (department_id) REFERENCES departments(id)
+);
+
+CREATE INDEX idx_courses_semester ON courses(department_id, semester);
+
+-- Who teaches a course, in the order the catalog lists them
+CREATE TABLE course_instructors (
+    course_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    PRIMARY KEY (course_id, position),
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
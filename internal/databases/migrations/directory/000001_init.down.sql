@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_staff_department;
+DROP TABLE IF EXISTS staff;
+DROP TABLE IF EXISTS departments;
+DROP TABLE IF EXISTS schools;
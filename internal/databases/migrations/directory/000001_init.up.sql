@@ -0,0 +1,34 @@
+-- Schools of the university and their departments, identified by short codes
+-- that imports match on
+CREATE TABLE schools (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    name TEXT NOT NULL,
+    name_en TEXT
+);
+
+CREATE TABLE departments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    school_id INTEGER NOT NULL,
+    code TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    name TEXT NOT NULL,
+    name_en TEXT,
+    FOREIGN KEY (school_id) REFERENCES schools(id)
+);
+
+-- Faculty and administrative staff of a department. office_hours is free text as
+-- the department publishes it, e.g. "Tuesday 10:00-12:00".
+CREATE TABLE staff (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    department_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    name_en TEXT,
+    title TEXT,
+    office TEXT,
+    email TEXT COLLATE NOCASE,
+    phone TEXT,
+    office_hours TEXT,
+    FOREIGN KEY (department_id) REFERENCES departments(id)
+);
+
+CREATE INDEX idx_staff_department ON staff(department_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS eclass_links;
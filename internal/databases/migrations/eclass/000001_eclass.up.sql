@@ -0,0 +1,12 @@
+-- eClass accounts users linked to the API, one per user. token is the Moodle web
+-- service token eClass issued for the account; the password it was exchanged for is
+-- never stored. Users live in the auth database, so no foreign key.
+CREATE TABLE eclass_links (
+    user_id INTEGER PRIMARY KEY,
+    site_user_id INTEGER NOT NULL,
+    username TEXT NOT NULL,
+    full_name TEXT NOT NULL,
+    token TEXT NOT NULL,
+    consented_at DATETIME NOT NULL,
+    last_used_at DATETIME
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_events_submitted_by;
+DROP INDEX IF EXISTS idx_events_status_start;
+DROP TABLE IF EXISTS events;
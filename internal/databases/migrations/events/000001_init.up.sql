@@ -0,0 +1,28 @@
+-- University events (talks, career days, student events). Events submitted by users
+-- wait for an admin as 'pending'; only 'approved' ones are listed publicly.
+CREATE TABLE events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    title_en TEXT,
+    description TEXT,
+    category TEXT NOT NULL CHECK (category IN ('talk', 'career', 'student', 'cultural', 'sports', 'academic', 'other')),
+    venue TEXT NOT NULL,
+    organizer TEXT NOT NULL,
+    url TEXT,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    status TEXT NOT NULL DEFAULT 'approved' CHECK (status IN ('pending', 'approved', 'rejected')),
+    -- The user who submitted the event, NULL for events added by admins. Users live in
+    -- the auth database, so there is no foreign key.
+    submitted_by INTEGER,
+    decided_by INTEGER,
+    decided_at DATETIME,
+    decision_note TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at >= starts_at)
+);
+
+-- Listings filter approved events by date range
+CREATE INDEX idx_events_status_start ON events(status, starts_at);
+CREATE INDEX idx_events_submitted_by ON events(submitted_by);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_jobs_submitted_by;
+DROP INDEX IF EXISTS idx_jobs_status_expires_at;
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,30 @@
+-- Part-time and student jobs. Jobs submitted by users are listed once an admin approves
+-- them, and no longer listed once expires_at has passed. Users live in the auth
+-- database, so submitted_by and decided_by have no foreign keys; submitted_by is NULL
+-- for jobs added by admins.
+CREATE TABLE jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('part-time', 'full-time', 'seasonal', 'freelance')),
+    location TEXT NOT NULL COLLATE NOCASE,
+    remote INTEGER NOT NULL DEFAULT 0,
+    hours_per_week INTEGER CHECK (hours_per_week BETWEEN 1 AND 60),
+    pay TEXT, -- as the employer puts it, e.g. '7 €/hour'
+    employer_name TEXT NOT NULL COLLATE NOCASE,
+    employer_website TEXT,
+    employer_description TEXT,
+    contact_email TEXT NOT NULL,
+    apply_url TEXT,
+    expires_at DATETIME NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    submitted_by INTEGER,
+    decided_by INTEGER,
+    decided_at DATETIME,
+    decision_note TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_jobs_status_expires_at ON jobs(status, expires_at);
+CREATE INDEX idx_jobs_submitted_by ON jobs(submitted_by);
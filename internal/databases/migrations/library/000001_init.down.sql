@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_room_reservations_user;
+DROP INDEX IF EXISTS idx_room_reservations_room;
+DROP TABLE IF EXISTS room_reservations;
+DROP TABLE IF EXISTS study_rooms;
+DROP TABLE IF EXISTS library_hours;
+DROP TABLE IF EXISTS libraries;
@@ -0,0 +1,50 @@
+-- Library branches. occupied is the latest seat count reported by the gate counters
+-- through the ingest endpoint, NULL until the first report.
+CREATE TABLE libraries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    name TEXT NOT NULL,
+    name_en TEXT,
+    seats INTEGER NOT NULL CHECK (seats >= 0),
+    occupied INTEGER CHECK (occupied >= 0),
+    occupancy_updated_at DATETIME
+);
+
+-- When a library is open, as HH:MM in Athens time on a weekday with Sunday as 0
+CREATE TABLE library_hours (
+    library_id INTEGER NOT NULL,
+    weekday INTEGER NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens_at TEXT NOT NULL,
+    closes_at TEXT NOT NULL,
+    PRIMARY KEY (library_id, weekday, opens_at),
+    FOREIGN KEY (library_id) REFERENCES libraries(id) ON DELETE CASCADE,
+    CHECK (closes_at > opens_at)
+);
+
+-- Study rooms users can reserve
+CREATE TABLE study_rooms (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    library_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    name_en TEXT,
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    FOREIGN KEY (library_id) REFERENCES libraries(id) ON DELETE CASCADE,
+    UNIQUE (library_id, name)
+);
+
+-- Reservations of study rooms. Users live in the auth database, so user_id has no
+-- foreign key. Cancelled reservations are kept with cancelled_at set.
+CREATE TABLE room_reservations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    room_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    cancelled_at DATETIME,
+    FOREIGN KEY (room_id) REFERENCES study_rooms(id) ON DELETE CASCADE,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_room_reservations_room ON room_reservations(room_id, starts_at);
+CREATE INDEX idx_room_reservations_user ON room_reservations(user_id, ends_at);
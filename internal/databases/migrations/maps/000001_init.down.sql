@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS poi_hours;
+DROP INDEX IF EXISTS idx_pois_position;
+DROP TABLE IF EXISTS pois;
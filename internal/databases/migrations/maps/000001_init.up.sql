@@ -0,0 +1,26 @@
+-- Points of interest on the campus map (buildings, labs, amenities), at WGS 84
+-- coordinates
+CREATE TABLE pois (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    name_en TEXT,
+    category TEXT NOT NULL CHECK (category IN ('building', 'lab', 'library', 'cafeteria', 'parking', 'amenity')),
+    description TEXT,
+    latitude REAL NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+    longitude REAL NOT NULL CHECK (longitude BETWEEN -180 AND 180)
+);
+
+-- Radius queries narrow down to a bounding box first
+CREATE INDEX idx_pois_position ON pois(latitude, longitude);
+
+-- When a point of interest is open, as HH:MM in Athens time on a weekday with Sunday
+-- as 0. A day may have several periods, e.g. a lunch break.
+CREATE TABLE poi_hours (
+    poi_id INTEGER NOT NULL,
+    weekday INTEGER NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens_at TEXT NOT NULL,
+    closes_at TEXT NOT NULL,
+    PRIMARY KEY (poi_id, weekday, opens_at),
+    FOREIGN KEY (poi_id) REFERENCES pois(id) ON DELETE CASCADE,
+    CHECK (closes_at > opens_at)
+);
@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// Databases lists the migration sets, one per database
+var Databases = []string{"auth", "schedule", "courses", "maps", "directory", "events", "library", "news", "sports", "postings", "jobs", "printing", "webhooks", "eclass"}
+
+// files holds the migrations, so the binary can migrate its databases
+// without the source tree
+//
+//go:embed auth/*.sql schedule/*.sql courses/*.sql maps/*.sql directory/*.sql events/*.sql library/*.sql news/*.sql sports/*.sql postings/*.sql jobs/*.sql printing/*.sql webhooks/*.sql eclass/*.sql
+var files embed.FS
+
+// New returns a migrator applying the named migration set to a SQLite file.
+// Close it when done.
+func New(name, dbFile string) (*migrate.Migrate, error) {
+	source, err := iofs.New(files, name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown migrations %q: %w", name, err)
+	}
+	return migrate.NewWithSourceInstance("iofs", source, "sqlite3://"+dbFile)
+}
+
+// Up applies the pending migrations of the named set to a SQLite file
+func Up(name, dbFile string) error {
+	m, err := New(name, dbFile)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to migrate %s database %s: %w", name, dbFile, err)
+	}
+	return nil
+}
+
+// Latest returns the highest migration version of the named set
+func Latest(name string) (int64, error) {
+	entries, err := fs.Glob(files, path.Join(name, "*.up.sql"))
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("unknown migrations %q", name)
+	}
+	sort.Strings(entries)
+	prefix, _, _ := strings.Cut(path.Base(entries[len(entries)-1]), "_")
+	return strconv.ParseInt(prefix, 10, 64)
+}
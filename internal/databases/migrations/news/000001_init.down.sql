@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_news_items_category;
+DROP TABLE IF EXISTS news_items;
+DROP TABLE IF EXISTS news_sources;
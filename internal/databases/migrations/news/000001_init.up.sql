@@ -0,0 +1,36 @@
+-- RSS and Atom feeds of the departments' sites. etag and last_modified are the
+-- validators of the last response, sent back so unchanged feeds are not downloaded
+-- again. category is given to items whose feed does not set one.
+CREATE TABLE news_sources (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    slug TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    name TEXT NOT NULL,
+    name_en TEXT,
+    feed_url TEXT NOT NULL UNIQUE,
+    site_url TEXT,
+    category TEXT,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    etag TEXT,
+    last_modified TEXT,
+    last_fetched_at DATETIME,
+    last_error TEXT
+);
+
+-- Items of the feeds. guid identifies an item within its feed; url_key is its
+-- normalized link, so the same post syndicated by several sites is stored once.
+CREATE TABLE news_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_id INTEGER NOT NULL,
+    guid TEXT NOT NULL,
+    url TEXT NOT NULL,
+    url_key TEXT NOT NULL UNIQUE,
+    title TEXT NOT NULL,
+    summary TEXT,
+    category TEXT,
+    published_at DATETIME NOT NULL,
+    fetched_at DATETIME NOT NULL,
+    FOREIGN KEY (source_id) REFERENCES news_sources(id) ON DELETE CASCADE,
+    UNIQUE (source_id, guid)
+);
+
+CREATE INDEX idx_news_items_category ON news_items(category);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posting_tags_tag;
+DROP TABLE IF EXISTS posting_tags;
+DROP INDEX IF EXISTS idx_postings_created_by;
+DROP INDEX IF EXISTS idx_postings_expires_at;
+DROP TABLE IF EXISTS postings;
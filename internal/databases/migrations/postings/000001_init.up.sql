@@ -0,0 +1,34 @@
+-- Thesis topics and internship openings published by department staff with the
+-- data-editor role. Postings are no longer listed once expires_at has passed. Users
+-- live in the auth database, so created_by has no foreign key.
+CREATE TABLE postings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL CHECK (kind IN ('thesis', 'internship')),
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    department TEXT NOT NULL COLLATE NOCASE,
+    supervisor TEXT,
+    organization TEXT,
+    location TEXT,
+    contact_email TEXT,
+    url TEXT,
+    positions INTEGER CHECK (positions > 0),
+    deadline TEXT, -- YYYY-MM-DD, the last day to apply
+    expires_at DATETIME NOT NULL,
+    created_by INTEGER NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_postings_expires_at ON postings(expires_at);
+CREATE INDEX idx_postings_created_by ON postings(created_by);
+
+-- Tags of a posting, lowercase, e.g. 'machine-learning' or 'embedded'
+CREATE TABLE posting_tags (
+    posting_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (posting_id, tag),
+    FOREIGN KEY (posting_id) REFERENCES postings(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_posting_tags_tag ON posting_tags(tag);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_postings_status;
+ALTER TABLE postings DROP COLUMN decision_note;
+ALTER TABLE postings DROP COLUMN decided_at;
+ALTER TABLE postings DROP COLUMN decided_by;
+ALTER TABLE postings DROP COLUMN status;
@@ -0,0 +1,10 @@
+-- Postings by data editors wait for an admin's approval before they are listed.
+-- The postings published so far were listed right away, so they are approved.
+ALTER TABLE postings ADD COLUMN status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected'));
+ALTER TABLE postings ADD COLUMN decided_by INTEGER;
+ALTER TABLE postings ADD COLUMN decided_at DATETIME;
+ALTER TABLE postings ADD COLUMN decision_note TEXT;
+
+UPDATE postings SET status = 'approved';
+
+CREATE INDEX idx_postings_status ON postings(status);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_print_alerts_open;
+DROP TABLE IF EXISTS print_alerts;
+DROP TABLE IF EXISTS print_stations;
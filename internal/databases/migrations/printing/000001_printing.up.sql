@@ -0,0 +1,35 @@
+-- Campus print stations. code is the station's queue name on the print server, which
+-- reports the status, queue length and supplies of every station to the ingest
+-- endpoint; they are NULL until the first report.
+CREATE TABLE print_stations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    name TEXT NOT NULL,
+    name_en TEXT,
+    location TEXT NOT NULL,
+    location_en TEXT,
+    color INTEGER NOT NULL DEFAULT 0,
+    status TEXT CHECK (status IN ('ready', 'printing', 'error', 'offline')),
+    status_message TEXT,
+    queue_length INTEGER CHECK (queue_length >= 0),
+    toner_percent INTEGER CHECK (toner_percent BETWEEN 0 AND 100),
+    paper TEXT CHECK (paper IN ('ok', 'low', 'empty')),
+    reported_at DATETIME
+);
+
+-- Low toner and paper alerts for admins. An alert is raised by the report that finds
+-- the supply low and resolved by the first report that finds it refilled; a station
+-- has at most one open alert of each kind.
+CREATE TABLE print_alerts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    station_id INTEGER NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('toner', 'paper')),
+    message TEXT NOT NULL,
+    raised_at DATETIME NOT NULL,
+    resolved_at DATETIME,
+    acknowledged_by INTEGER, -- users live in the auth database, so no foreign key
+    acknowledged_at DATETIME,
+    FOREIGN KEY (station_id) REFERENCES print_stations(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_print_alerts_open ON print_alerts(station_id, kind) WHERE resolved_at IS NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_announcement_receipts_user;
+DROP TABLE IF EXISTS announcement_receipts;
@@ -0,0 +1,13 @@
+-- Per-user (and optionally per-device) delivery and seen state of announcements.
+-- device_id is '' when the client tracks state per user rather than per device.
+CREATE TABLE announcement_receipts (
+    announcement_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    device_id TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP,
+    seen_at TIMESTAMP,
+    PRIMARY KEY (announcement_id, user_id, device_id),
+    FOREIGN KEY (announcement_id) REFERENCES announcements(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_announcement_receipts_user ON announcement_receipts(user_id, device_id);
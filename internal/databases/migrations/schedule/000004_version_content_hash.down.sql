@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_schedule_versions_content_hash;
+
+ALTER TABLE schedule_versions DROP COLUMN content_hash;
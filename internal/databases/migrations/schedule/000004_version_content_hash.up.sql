@@ -0,0 +1,5 @@
+-- Hash of a version's normalized items, set by imports so that re-importing
+-- the same menu file resolves to the existing version instead of a duplicate.
+ALTER TABLE schedule_versions ADD COLUMN content_hash TEXT;
+
+CREATE UNIQUE INDEX idx_schedule_versions_content_hash ON schedule_versions(content_hash) WHERE content_hash IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE schedule_versions DROP COLUMN updated_at;
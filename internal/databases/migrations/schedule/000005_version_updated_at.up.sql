@@ -0,0 +1,5 @@
+-- When a version or its items last changed, so clients can tell that the
+-- menu they cached is stale. Existing versions count as changed now.
+ALTER TABLE schedule_versions ADD COLUMN updated_at TIMESTAMP;
+
+UPDATE schedule_versions SET updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS food_tags;
+DROP TABLE IF EXISTS food_allergens;
+
+ALTER TABLE foods DROP COLUMN fat;
+ALTER TABLE foods DROP COLUMN carbohydrates;
+ALTER TABLE foods DROP COLUMN protein;
+ALTER TABLE foods DROP COLUMN calories;
@@ -0,0 +1,23 @@
+-- Nutrition per serving; NULL when unknown
+ALTER TABLE foods ADD COLUMN calories REAL;      -- kcal
+ALTER TABLE foods ADD COLUMN protein REAL;       -- grams
+ALTER TABLE foods ADD COLUMN carbohydrates REAL; -- grams
+ALTER TABLE foods ADD COLUMN fat REAL;           -- grams
+
+-- Allergens a food contains, from the 14 the EU requires caterers to declare
+CREATE TABLE food_allergens (
+    food_id INTEGER NOT NULL,
+    allergen TEXT NOT NULL,
+    PRIMARY KEY (food_id, allergen),
+    FOREIGN KEY (food_id) REFERENCES foods(id) ON DELETE CASCADE
+);
+
+-- Dietary tags (e.g. "vegan", "fasting") clients filter menus by
+CREATE TABLE food_tags (
+    food_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (food_id, tag),
+    FOREIGN KEY (food_id) REFERENCES foods(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_food_tags_tag ON food_tags(tag);
@@ -0,0 +1 @@
+ALTER TABLE foods DROP COLUMN image_key;
@@ -0,0 +1,2 @@
+-- Key of the food's image in the image storage, without the variant suffix
+ALTER TABLE foods ADD COLUMN image_key TEXT;
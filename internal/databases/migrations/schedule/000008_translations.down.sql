@@ -0,0 +1,2 @@
+ALTER TABLE announcements DROP COLUMN content_en;
+ALTER TABLE foods DROP COLUMN name_en;
@@ -0,0 +1,3 @@
+-- English translations; NULL falls back to the Greek original
+ALTER TABLE foods ADD COLUMN name_en TEXT;
+ALTER TABLE announcements ADD COLUMN content_en TEXT;
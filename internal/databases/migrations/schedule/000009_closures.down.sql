@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS closure_dishes;
+DROP TABLE IF EXISTS closures;
@@ -0,0 +1,22 @@
+-- Dates the rotation does not apply to, e.g. public holidays. ending_date equals
+-- starting_date for single days.
+CREATE TABLE closures (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    starting_date DATE NOT NULL,
+    ending_date DATE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    reason_en TEXT,
+    CHECK (ending_date >= starting_date)
+);
+
+CREATE INDEX idx_closures_dates ON closures(starting_date, ending_date);
+
+-- Replacement menu served on a closure's dates; without one the cafeteria is closed
+CREATE TABLE closure_dishes (
+    closure_id INTEGER NOT NULL,
+    meal_type TEXT NOT NULL CHECK (meal_type IN ('lunch', 'dinner')),
+    food_id INTEGER NOT NULL,
+    PRIMARY KEY (closure_id, meal_type, food_id),
+    FOREIGN KEY (closure_id) REFERENCES closures(id) ON DELETE CASCADE,
+    FOREIGN KEY (food_id) REFERENCES foods(id)
+);
@@ -0,0 +1,16 @@
+-- One rating per user and food, with an optional short review. Hidden ratings were
+-- taken down by a moderator and are left out of listings and averages.
+CREATE TABLE food_ratings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    food_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    review TEXT NOT NULL DEFAULT '',
+    hidden BOOLEAN NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (food_id, user_id),
+    FOREIGN KEY (food_id) REFERENCES foods(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_food_ratings_food ON food_ratings(food_id, hidden);
@@ -0,0 +1,15 @@
+-- Dishes added to or removed from the meal slots of a version by edits after it was
+-- created, so clients can be told what changed in a published menu
+CREATE TABLE schedule_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    version_id INTEGER NOT NULL,
+    week_number INTEGER NOT NULL,
+    day_number INTEGER NOT NULL,
+    meal_type TEXT NOT NULL,
+    food_id INTEGER NOT NULL,
+    change TEXT NOT NULL CHECK (change IN ('added', 'removed')),
+    changed_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (version_id) REFERENCES schedule_versions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_schedule_changes_changed_at ON schedule_changes(changed_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS service_hours;
+DROP TABLE IF EXISTS restaurants;
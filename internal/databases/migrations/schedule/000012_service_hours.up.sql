@@ -0,0 +1,19 @@
+-- Places the cafeteria serves in, each with its own serving hours
+CREATE TABLE restaurants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    name_en TEXT
+);
+
+-- When a restaurant serves a meal on weekdays or at weekends, as HH:MM in the
+-- cafeteria's time zone
+CREATE TABLE service_hours (
+    restaurant_id INTEGER NOT NULL,
+    meal_type TEXT NOT NULL CHECK (meal_type IN ('lunch', 'dinner')),
+    days TEXT NOT NULL CHECK (days IN ('weekdays', 'weekends')),
+    opens_at TEXT NOT NULL,
+    closes_at TEXT NOT NULL,
+    PRIMARY KEY (restaurant_id, meal_type, days),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE,
+    CHECK (closes_at > opens_at)
+);
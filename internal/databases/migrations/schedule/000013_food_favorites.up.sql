@@ -0,0 +1,11 @@
+-- Foods app users marked as favorites, to be told when they are served next.
+-- user_id refers to the auth database, so it is not a foreign key.
+CREATE TABLE food_favorites (
+    user_id INTEGER NOT NULL,
+    food_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, food_id),
+    FOREIGN KEY (food_id) REFERENCES foods(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_food_favorites_food ON food_favorites(food_id);
@@ -0,0 +1,18 @@
+-- Discord webhooks and Telegram chats the day's menu and announcements are posted
+-- to every day at post_at (HH:MM in the cafeteria's time zone). last_posted_on is
+-- claimed before posting, so only one instance posts; a failed post releases it
+-- and is retried from retry_at.
+CREATE TABLE menu_channels (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL CHECK (kind IN ('discord', 'telegram')),
+    target TEXT NOT NULL,
+    bot_token TEXT,
+    language TEXT NOT NULL DEFAULT 'el',
+    post_at TEXT NOT NULL DEFAULT '10:00',
+    template TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT 1,
+    last_posted_on DATE,
+    last_error TEXT,
+    retry_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
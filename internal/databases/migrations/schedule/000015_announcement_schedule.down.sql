@@ -0,0 +1,3 @@
+ALTER TABLE announcements DROP COLUMN repeat_days;
+ALTER TABLE announcements DROP COLUMN ends_at;
+ALTER TABLE announcements DROP COLUMN starts_at;
@@ -0,0 +1,7 @@
+-- Times of day announcements start and end at, as HH:MM in Athens time, and the
+-- weekdays recurring ones run on, as a bitmask with Sunday as bit 0. Announcements
+-- without repeat_days run from starts_at on their starting date to ends_at on
+-- their ending date; recurring ones run between those times on each of their days.
+ALTER TABLE announcements ADD COLUMN starts_at TEXT;
+ALTER TABLE announcements ADD COLUMN ends_at TEXT;
+ALTER TABLE announcements ADD COLUMN repeat_days INTEGER NOT NULL DEFAULT 0;
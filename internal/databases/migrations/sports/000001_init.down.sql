@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_court_bookings_user;
+DROP INDEX IF EXISTS idx_court_bookings_court;
+DROP TABLE IF EXISTS court_bookings;
+DROP TABLE IF EXISTS courts;
+DROP INDEX IF EXISTS idx_sports_classes_facility;
+DROP TABLE IF EXISTS sports_classes;
+DROP TABLE IF EXISTS facility_hours;
+DROP TABLE IF EXISTS sports_facilities;
@@ -0,0 +1,67 @@
+-- Sports facilities of the university, e.g. the gym, the courts and the pool
+CREATE TABLE sports_facilities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    name TEXT NOT NULL,
+    name_en TEXT,
+    kind TEXT NOT NULL CHECK (kind IN ('gym', 'court', 'pool', 'field', 'other')),
+    location TEXT
+);
+
+-- When a facility is open, as HH:MM in Athens time on a weekday with Sunday as 0
+CREATE TABLE facility_hours (
+    facility_id INTEGER NOT NULL,
+    weekday INTEGER NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens_at TEXT NOT NULL,
+    closes_at TEXT NOT NULL,
+    PRIMARY KEY (facility_id, weekday, opens_at),
+    FOREIGN KEY (facility_id) REFERENCES sports_facilities(id) ON DELETE CASCADE,
+    CHECK (closes_at > opens_at)
+);
+
+-- The weekly program of classes, e.g. pilates on Mondays at 18:00
+CREATE TABLE sports_classes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    facility_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    name_en TEXT,
+    instructor TEXT,
+    weekday INTEGER NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    starts_at TEXT NOT NULL,
+    ends_at TEXT NOT NULL,
+    capacity INTEGER CHECK (capacity > 0),
+    FOREIGN KEY (facility_id) REFERENCES sports_facilities(id) ON DELETE CASCADE,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_sports_classes_facility ON sports_classes(facility_id, weekday, starts_at);
+
+-- Courts users can book, in slots of slot_minutes counted from the start of each
+-- opening period of their facility
+CREATE TABLE courts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    facility_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    name_en TEXT,
+    sport TEXT NOT NULL,
+    slot_minutes INTEGER NOT NULL CHECK (slot_minutes BETWEEN 15 AND 240),
+    FOREIGN KEY (facility_id) REFERENCES sports_facilities(id) ON DELETE CASCADE,
+    UNIQUE (facility_id, name)
+);
+
+-- Bookings of court slots. Users live in the auth database, so user_id has no foreign
+-- key. Cancelled bookings are kept with cancelled_at set.
+CREATE TABLE court_bookings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    court_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    cancelled_at DATETIME,
+    FOREIGN KEY (court_id) REFERENCES courts(id) ON DELETE CASCADE,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_court_bookings_court ON court_bookings(court_id, starts_at);
+CREATE INDEX idx_court_bookings_user ON court_bookings(user_id, ends_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_event_outbox_subscriber;
+DROP INDEX IF EXISTS idx_event_outbox_pending;
+DROP TABLE IF EXISTS event_outbox;
+DROP INDEX IF EXISTS idx_webhook_subscriptions_token;
+DROP TABLE IF EXISTS webhook_subscriptions;
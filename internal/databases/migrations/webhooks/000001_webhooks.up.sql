@@ -0,0 +1,38 @@
+-- Webhooks token holders register for the events their token is entitled to. Each
+-- belongs to the token that created it; tokens and users live in the auth database,
+-- so no foreign keys. events is a comma separated list of event types.
+CREATE TABLE webhook_subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    events TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    description TEXT,
+    active BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_subscriptions_token ON webhook_subscriptions(token_id);
+
+-- Deliveries to the subscriptions, one row per subscription and event, retried with
+-- backoff by the outbox dispatcher. They double as the subscriptions' delivery log.
+CREATE TABLE event_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscriber TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    failed_at TIMESTAMP, -- set once attempts are exhausted
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for the dispatcher's pending scan
+CREATE INDEX idx_event_outbox_pending ON event_outbox(delivered_at, failed_at, next_attempt_at);
+
+-- Index for the delivery log of a subscription
+CREATE INDEX idx_event_outbox_subscriber ON event_outbox(subscriber, id);
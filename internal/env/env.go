@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -40,6 +41,21 @@ func GetDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+// GetList returns a comma-separated environment variable as a trimmed list
+func GetList(key string, defaultValue []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // Auth-related environment variable keys
 const (
 	// OAuth Providers
@@ -50,11 +66,154 @@ const (
 
 	// Auth Configuration
 	EnvAuthCallbackBaseURL = "AUTH_CALLBACK_BASE_URL"
-	EnvSessionSecret       = "SESSION_SECRET"
 	EnvSessionDuration     = "SESSION_DURATION"
 	EnvSecureCookies       = "SECURE_COOKIES"
+
+	// "evict" (default) or "reject" when a login exceeds the group's max_sessions
+	EnvSessionLimitPolicy = "SESSION_LIMIT_POLICY"
+
+	// Comma-separated emails promoted to admin on login while no admin exists
+	EnvAdminBootstrapEmails = "ADMIN_BOOTSTRAP_EMAILS"
+
+	// Tenancy
+	EnvTenantsFile = "TENANTS_FILE"
+
+	// HashiCorp Vault secret the secrets fall back on when neither they nor their
+	// _FILE variant are set; disabled unless VAULT_ADDR and VAULT_SECRET_PATH are
+	EnvVaultAddr       = "VAULT_ADDR"
+	EnvVaultToken      = "VAULT_TOKEN"
+	EnvVaultNamespace  = "VAULT_NAMESPACE"
+	EnvVaultSecretPath = "VAULT_SECRET_PATH" // e.g. secret/data/api for a KV version 2 engine
+
+	// Webhooks; comma-separated URLs notified when a schedule version or an
+	// announcement is published
+	EnvScheduleWebhookURLs     = "SCHEDULE_WEBHOOK_URLS"
+	EnvAnnouncementWebhookURLs = "ANNOUNCEMENT_WEBHOOK_URLS"
+	EnvWebhookSecret           = "WEBHOOK_SECRET"
+
+	// Push notifications through Firebase Cloud Messaging; disabled without credentials
+	EnvPushCredentialsFile = "FCM_CREDENTIALS_FILE"
+	EnvPushTopic           = "PUSH_TOPIC"
+
+	// OCR program for photographed menus, reading the scan on stdin and writing
+	// the text to stdout; the scan import is disabled when unset
+	EnvOCRCommand = "OCR_COMMAND"
+
+	// Study room reservations; how many a user may hold at once (default 2), how
+	// long one may last (default 3h) and how far ahead they can start (default 168h)
+	EnvLibraryMaxActiveBookings = "LIBRARY_MAX_ACTIVE_BOOKINGS"
+	EnvLibraryMaxBookingLength  = "LIBRARY_MAX_BOOKING_LENGTH"
+	EnvLibraryBookingWindow     = "LIBRARY_BOOKING_WINDOW"
+
+	// How often the departments' news feeds are fetched (default 30m)
+	EnvNewsFetchInterval = "NEWS_FETCH_INTERVAL"
+
+	// Court bookings; how many a user may hold at once (default 2) and how far ahead
+	// they can start (default 168h)
+	EnvSportsMaxActiveBookings = "SPORTS_MAX_ACTIVE_BOOKINGS"
+	EnvSportsBookingWindow     = "SPORTS_BOOKING_WINDOW"
+
+	// Print stations; the toner percentage at or below which an alert is raised
+	// (default 10) and how long a status report stands before the station is shown
+	// as unknown (default 15m)
+	EnvPrintingLowToner   = "PRINTING_LOW_TONER_PERCENT"
+	EnvPrintingStaleAfter = "PRINTING_STALE_AFTER"
+
+	// Token holders' webhooks; "true" lets them deliver to loopback and private
+	// network addresses, for local development only
+	EnvWebhooksAllowPrivateURLs = "WEBHOOKS_ALLOW_PRIVATE_URLS"
+
+	// How often the live update streams look for changes that no event announced,
+	// e.g. an announcement reaching its start date (default 15s)
+	EnvStreamPollInterval = "STREAM_POLL_INTERVAL"
+
+	// eClass proxy; the Moodle-based eClass site's address, disabled when unset, the web
+	// service tokens are requested for (default moodle_mobile_app) and the secret the
+	// linked accounts' tokens are encrypted with, without which it is disabled too
+	EnvEClassURL      = "ECLASS_URL"
+	EnvEClassService  = "ECLASS_SERVICE"
+	EnvEClassTokenKey = "ECLASS_TOKEN_KEY"
+
+	// Listener; LISTEN_SOCKET takes precedence over HOST/PORT when set
+	EnvHost         = "HOST"
+	EnvPort         = "PORT"
+	EnvListenSocket = "LISTEN_SOCKET"
+
+	// Addresses or CIDRs of the reverse proxies whose X-Forwarded-For and X-Real-IP are
+	// believed; none when unset, so the client IP is the peer's
+	EnvTrustedProxies = "TRUSTED_PROXIES"
+
+	// gRPC listener on HOST; disabled when unset
+	EnvGRPCPort = "GRPC_PORT"
+
+	// "json" (default) or "text" request and application logs
+	EnvLogFormat = "LOG_FORMAT"
+
+	// Bearer token Prometheus must send to scrape GET /metrics; open when unset
+	EnvMetricsToken = "METRICS_TOKEN"
+
+	// How many of the latest server errors are kept for GET /admin/errors (default 200)
+	EnvErrorLogSize = "ERROR_LOG_SIZE"
+
+	// TLS without a reverse proxy: either a certificate and key, or
+	// Let's Encrypt certificates for the tenant hosts and TLS_AUTOCERT_HOSTS
+	EnvTLSCertFile      = "TLS_CERT_FILE"
+	EnvTLSKeyFile       = "TLS_KEY_FILE"
+	EnvTLSAutocert      = "TLS_AUTOCERT"
+	EnvTLSAutocertHosts = "TLS_AUTOCERT_HOSTS"
+	EnvTLSAutocertEmail = "TLS_AUTOCERT_EMAIL"
+	EnvTLSAutocertCache = "TLS_AUTOCERT_CACHE"
+	EnvTLSRedirectPort  = "TLS_REDIRECT_PORT" // Cleartext port for HTTP-01 challenges and redirects
+
+	// Request limits; routes may override them
+	EnvMaxBodyBytes   = "MAX_BODY_BYTES"
+	EnvRequestTimeout = "REQUEST_TIMEOUT"
+
+	EnvAutoMigrate = "AUTO_MIGRATE" // Apply pending migrations on startup (default true)
+
+	// SQLite connection settings, applied to every tenant database
+	EnvSQLiteJournalMode  = "SQLITE_JOURNAL_MODE" // Default WAL
+	EnvSQLiteSynchronous  = "SQLITE_SYNCHRONOUS"  // Default NORMAL, which is durable enough with WAL
+	EnvSQLiteBusyTimeout  = "SQLITE_BUSY_TIMEOUT" // How long a write waits for the lock, e.g. 5s
+	EnvSQLiteForeignKeys  = "SQLITE_FOREIGN_KEYS" // Default true
+	EnvSQLiteMaxOpenConns = "SQLITE_MAX_OPEN_CONNS"
+	EnvSQLiteMaxIdleConns = "SQLITE_MAX_IDLE_CONNS"
+	EnvSQLiteSlowQuery    = "SQLITE_SLOW_QUERY" // Statements taking longer are logged with their caller, e.g. 100ms; 0 disables
+
+	// Read-only replica of the default tenant's schedule database (e.g. a
+	// LiteFS mount), serving the open-data reads; writes go to the primary
+	EnvScheduleReplicaDB = "SCHEDULE_REPLICA_DB"
+
+	// Database snapshots, to BACKUP_DIR or an S3 bucket; disabled when neither is set
+	EnvBackupDir         = "BACKUP_DIR"
+	EnvBackupS3Endpoint  = "BACKUP_S3_ENDPOINT" // Defaults to AWS in BACKUP_S3_REGION
+	EnvBackupS3Region    = "BACKUP_S3_REGION"
+	EnvBackupS3Bucket    = "BACKUP_S3_BUCKET"
+	EnvBackupS3AccessKey = "BACKUP_S3_ACCESS_KEY"
+	EnvBackupS3SecretKey = "BACKUP_S3_SECRET_KEY"
+	EnvBackupInterval    = "BACKUP_INTERVAL" // 0 disables scheduled snapshots
+	EnvBackupKeep        = "BACKUP_KEEP"     // Snapshots kept per database; 0 keeps all
+
+	// Food images; stored under IMAGE_DIR (default ./internal/images) unless
+	// IMAGE_S3_BUCKET is set. IMAGE_BASE_URL is where clients load them from,
+	// e.g. a CDN in front of the bucket; by default the API serves them itself,
+	// or with IMAGE_SIGNED_URL_EXPIRY (e.g. 1h) redirects to signed URLs of a
+	// private bucket, reached at IMAGE_S3_PUBLIC_ENDPOINT when clients cannot
+	// use IMAGE_S3_ENDPOINT
+	EnvImageDir              = "IMAGE_DIR"
+	EnvImageS3Endpoint       = "IMAGE_S3_ENDPOINT"
+	EnvImageS3PublicEndpoint = "IMAGE_S3_PUBLIC_ENDPOINT"
+	EnvImageS3Region         = "IMAGE_S3_REGION"
+	EnvImageS3Bucket         = "IMAGE_S3_BUCKET"
+	EnvImageS3AccessKey      = "IMAGE_S3_ACCESS_KEY"
+	EnvImageS3SecretKey      = "IMAGE_S3_SECRET_KEY"
+	EnvImageBaseURL          = "IMAGE_BASE_URL"
+	EnvImageSignedURLExpiry  = "IMAGE_SIGNED_URL_EXPIRY"
 )
 
+// DefaultPort is the TCP port the API listens on when PORT is unset
+const DefaultPort = 9237
+
 /*
 This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
 API Copyright (C) 2025 OpenSourceDUTH
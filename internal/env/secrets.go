@@ -0,0 +1,134 @@
+package env
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileSuffix names the variable holding the path of a file to read a secret from instead,
+// e.g. GOOGLE_CLIENT_SECRET_FILE for a Docker secret mounted under /run/secrets
+const FileSuffix = "_FILE"
+
+// vaultTimeout bounds the request reading the secrets from Vault at startup
+const vaultTimeout = 10 * time.Second
+
+var (
+	vaultMu      sync.RWMutex
+	vaultSecrets map[string]string
+)
+
+// GetSecret returns a secret from, in order, its environment variable, the file named by
+// the variable with FileSuffix, the Vault secret loaded by LoadVault under the same name,
+// or defaultValue. Secrets are read at startup, so a file that cannot be read stops the
+// process instead of leaving the feature it configures silently disabled.
+func GetSecret(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	if path, exists := os.LookupEnv(key + FileSuffix); exists {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read %s from %s: %v", key, key+FileSuffix, err)
+		}
+		// Files written by editors and echo end with a newline that is not part of the secret
+		return strings.TrimRight(string(data), "\r\n")
+	}
+
+	vaultMu.RLock()
+	defer vaultMu.RUnlock()
+	if value, exists := vaultSecrets[key]; exists {
+		return value
+	}
+	return defaultValue
+}
+
+// LoadVault reads the secret at VAULT_SECRET_PATH from the HashiCorp Vault server at
+// VAULT_ADDR, whose fields GetSecret then falls back on by name, e.g. a
+// GOOGLE_CLIENT_SECRET field. Both KV engine versions are understood; for version 2
+// the path includes data/, e.g. secret/data/api. It does nothing unless both are set.
+func LoadVault(ctx context.Context) error {
+	addr := GetEnv(EnvVaultAddr, "")
+	path := strings.Trim(GetEnv(EnvVaultSecretPath, ""), "/")
+	if addr == "" || path == "" {
+		return nil
+	}
+	token := GetSecret(EnvVaultToken, "")
+	if token == "" {
+		return fmt.Errorf("%s needs %s", EnvVaultAddr, EnvVaultToken)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, vaultTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", EnvVaultAddr, err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := GetEnv(EnvVaultNamespace, ""); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Vault: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("Vault answered %s for %s: %s", resp.Status, path, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return fmt.Errorf("failed to parse the Vault secret: %w", err)
+	}
+	// Version 2 nests the fields under data, next to the version's metadata
+	fields := secret.Data
+	if nested, ok := secret.Data["data"]; ok {
+		if _, ok := secret.Data["metadata"]; ok {
+			fields = nil
+			if err := json.Unmarshal(nested, &fields); err != nil {
+				return fmt.Errorf("failed to parse the Vault secret: %w", err)
+			}
+		}
+	}
+
+	secrets := make(map[string]string, len(fields))
+	for name, raw := range fields {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return fmt.Errorf("Vault secret field %s is not a string", name)
+		}
+		secrets[name] = value
+	}
+	vaultMu.Lock()
+	vaultSecrets = secrets
+	vaultMu.Unlock()
+	return nil
+}
+
+/*
+This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team as well as helper endpoints to integrate with our apps.
+API Copyright (C) 2025 OpenSourceDUTH
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU General Public License for more details.
+
+    You should have received a copy of the GNU General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
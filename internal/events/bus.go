@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Handler receives published events. Handlers run synchronously on the
+// publishing goroutine, so anything slow (network calls, retries) must be
+// queued by the subscriber instead of done inline.
+type Handler func(ctx context.Context, event Event)
+
+// Bus is an in-process publish/subscribe event bus. Subsystems such as
+// webhooks, notifications, cache invalidation and auditing subscribe to it
+// instead of being called directly by the modules that produce the events.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[Type][]Handler
+	all      []Handler
+}
+
+// NewBus creates an empty event bus
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[Type][]Handler)}
+}
+
+// Subscribe registers a handler for a single event type
+func (b *Bus) Subscribe(eventType Type, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// SubscribeAll registers a handler for every event type
+func (b *Bus) SubscribeAll(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.all = append(b.all, handler)
+}
+
+// Publish delivers an event to its subscribers. A nil bus discards events,
+// and a panicking handler does not affect the publisher or other handlers.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[event.EventType()])+len(b.all))
+	handlers = append(handlers, b.handlers[event.EventType()]...)
+	handlers = append(handlers, b.all...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.deliver(ctx, handler, event)
+	}
+}
+
+func (b *Bus) deliver(ctx context.Context, handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event handler for %s panicked: %v", event.EventType(), r)
+		}
+	}()
+	handler(ctx, event)
+}
@@ -0,0 +1,132 @@
+package events
+
+import "time"
+
+// Type identifies an event kind. Values are stable and used in serialized payloads.
+type Type string
+
+const (
+	TypeUserCreated              Type = "user.created"
+	TypeUserSuspended            Type = "user.suspended"
+	TypeUserDeleted              Type = "user.deleted"
+	TypeUserRestored             Type = "user.restored"
+	TypeTokenCreated             Type = "token.created"
+	TypeTokenRevoked             Type = "token.revoked"
+	TypeTokenRestored            Type = "token.restored"
+	TypeQuotaExceeded            Type = "quota.exceeded"
+	TypeAnnouncementPublished    Type = "announcement.published"
+	TypeScheduleVersionPublished Type = "schedule.version.published"
+)
+
+// Event is implemented by every typed event published on the bus
+type Event interface {
+	EventType() Type
+}
+
+// UserCreated is published when a user account is created on first login
+type UserCreated struct {
+	UserID     int64     `json:"userId"`
+	Email      string    `json:"email"`
+	GroupID    int64     `json:"groupId"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+func (UserCreated) EventType() Type { return TypeUserCreated }
+
+// UserSuspended is published when an admin suspends a user
+type UserSuspended struct {
+	UserID         int64      `json:"userId"`
+	Reason         *string    `json:"reason,omitempty"`
+	SuspendedBy    *int64     `json:"suspendedBy,omitempty"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+	OccurredAt     time.Time  `json:"occurredAt"`
+}
+
+func (UserSuspended) EventType() Type { return TypeUserSuspended }
+
+// UserDeleted is published when an admin deletes a user. The account is only
+// marked deleted and can be restored.
+type UserDeleted struct {
+	UserID     int64     `json:"userId"`
+	DeletedBy  *int64    `json:"deletedBy,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+func (UserDeleted) EventType() Type { return TypeUserDeleted }
+
+// UserRestored is published when an admin restores a deleted user
+type UserRestored struct {
+	UserID     int64     `json:"userId"`
+	RestoredBy *int64    `json:"restoredBy,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+func (UserRestored) EventType() Type { return TypeUserRestored }
+
+// TokenCreated is published when a token is issued to a user
+type TokenCreated struct {
+	TokenID      int64      `json:"tokenId"`
+	UserID       int64      `json:"userId"`
+	Label        string     `json:"label"`
+	AdminCreated bool       `json:"adminCreated"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	OccurredAt   time.Time  `json:"occurredAt"`
+}
+
+func (TokenCreated) EventType() Type { return TypeTokenCreated }
+
+// TokenRevoked is published when a token is revoked by its owner or an admin
+type TokenRevoked struct {
+	TokenID    int64     `json:"tokenId"`
+	UserID     int64     `json:"userId"`
+	ByAdmin    bool      `json:"byAdmin"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+func (TokenRevoked) EventType() Type { return TypeTokenRevoked }
+
+// TokenRestored is published when an admin restores a revoked token
+type TokenRestored struct {
+	TokenID    int64     `json:"tokenId"`
+	UserID     int64     `json:"userId"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+func (TokenRestored) EventType() Type { return TypeTokenRestored }
+
+// QuotaExceeded is published when a user is rate limited on a feature. It is
+// throttled per user and feature, so it marks the start of a burst rather than
+// every rejected request.
+type QuotaExceeded struct {
+	UserID      int64     `json:"userId"`
+	FeatureID   int64     `json:"featureId"`
+	FeatureSlug string    `json:"featureSlug"`
+	LimitRPM    int       `json:"limitRpm"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
+
+func (QuotaExceeded) EventType() Type { return TypeQuotaExceeded }
+
+// AnnouncementPublished is published when an announcement is created
+type AnnouncementPublished struct {
+	AnnouncementID int64     `json:"announcementId"`
+	Type           string    `json:"type"`
+	Content        string    `json:"content"`
+	ContentEn      string    `json:"contentEn,omitempty"` // English translation, if any
+	StartingDate   string    `json:"startingDate"`
+	EndingDate     string    `json:"endingDate"`
+	OccurredAt     time.Time `json:"occurredAt"`
+}
+
+func (AnnouncementPublished) EventType() Type { return TypeAnnouncementPublished }
+
+// ScheduleVersionPublished is published when a schedule version is created
+type ScheduleVersionPublished struct {
+	VersionID    int64     `json:"versionId"`
+	StartingDate string    `json:"startingDate"`
+	EndingDate   string    `json:"endingDate"`
+	IsCurrent    bool      `json:"isCurrent"`
+	OccurredAt   time.Time `json:"occurredAt"`
+}
+
+func (ScheduleVersionPublished) EventType() Type { return TypeScheduleVersionPublished }
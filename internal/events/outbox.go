@@ -0,0 +1,381 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"API/internal/health"
+)
+
+const (
+	// OutboxPollInterval is how often the dispatcher scans for due deliveries
+	OutboxPollInterval = 2 * time.Second
+
+	// OutboxBatchSize is the maximum number of deliveries attempted per scan
+	OutboxBatchSize = 100
+
+	// OutboxMaxAttempts is how many times a delivery is tried before it is marked failed
+	OutboxMaxAttempts = 10
+
+	// OutboxBaseBackoff is the delay before the first retry; it doubles per attempt
+	OutboxBaseBackoff = 5 * time.Second
+
+	// OutboxMaxBackoff caps the delay between retries
+	OutboxMaxBackoff = time.Hour
+
+	// OutboxDeliveryTimeout bounds a single subscriber call
+	OutboxDeliveryTimeout = 30 * time.Second
+
+	// OutboxRetentionPeriod is how long delivered rows are kept
+	OutboxRetentionPeriod = 7 * 24 * time.Hour
+
+	// OutboxMaxDelay is how long a due delivery may wait for the dispatcher before the
+	// backlog check fails
+	OutboxMaxDelay = time.Minute
+)
+
+// Subscriber is a durable event consumer. Returning an error schedules a retry.
+type Subscriber func(ctx context.Context, event Event) error
+
+type outboxSubscription struct {
+	types   map[Type]bool // nil means every type
+	handler Subscriber
+}
+
+// Outbox persists events in the same transaction as the write that produced
+// them and delivers them to durable subscribers asynchronously, retrying with
+// exponential backoff. Because pending rows live in the database, deliveries
+// survive process restarts. Each subscriber gets its own row, so a failing
+// subscriber never causes duplicate deliveries to the others.
+type Outbox struct {
+	db            *sql.DB
+	mu            sync.RWMutex
+	subscriptions map[string]outboxSubscription
+	wakeCh        chan struct{}
+	stopCh        chan struct{}
+	wg            sync.WaitGroup
+	heartbeat     *health.Heartbeat
+}
+
+// NewOutbox creates an outbox backed by the event_outbox table of db
+func NewOutbox(db *sql.DB) *Outbox {
+	return &Outbox{
+		db:            db,
+		subscriptions: make(map[string]outboxSubscription),
+		wakeCh:        make(chan struct{}, 1),
+		stopCh:        make(chan struct{}),
+		heartbeat:     health.NewHeartbeat(OutboxDeliveryTimeout), // A slow subscriber may hold up a scan that long
+	}
+}
+
+// Subscribe registers a durable subscriber under a stable name. Pass no types
+// to receive every event. Subscribers must be registered before events are
+// enqueued; events enqueued earlier are not backfilled.
+func (o *Outbox) Subscribe(name string, handler Subscriber, types ...Type) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	sub := outboxSubscription{handler: handler}
+	if len(types) > 0 {
+		sub.types = make(map[Type]bool)
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+	o.subscriptions[name] = sub
+}
+
+// Unsubscribe removes a durable subscriber and discards its undelivered events
+func (o *Outbox) Unsubscribe(name string) error {
+	o.mu.Lock()
+	delete(o.subscriptions, name)
+	o.mu.Unlock()
+
+	_, err := o.db.Exec("DELETE FROM event_outbox WHERE subscriber = ? AND delivered_at IS NULL", name)
+	return err
+}
+
+// Enqueue records an event for every interested subscriber as part of tx.
+// Call Notify after the transaction commits to deliver without waiting for
+// the next poll. A nil outbox discards events.
+func (o *Outbox) Enqueue(tx *sql.Tx, event Event) error {
+	if o == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	now := time.Now()
+	for name, sub := range o.subscriptions {
+		if sub.types != nil && !sub.types[event.EventType()] {
+			continue
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO event_outbox (subscriber, event_type, payload, next_attempt_at)
+			VALUES (?, ?, ?, ?)
+		`, name, event.EventType(), string(payload), now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnqueueTo records an event for a single subscriber as part of tx, whatever types it was
+// subscribed with. Fan-out subscribers use it to pick the recipients of an event themselves,
+// e.g. only the webhooks whose token may see it.
+func (o *Outbox) EnqueueTo(tx *sql.Tx, subscriber string, event Event) error {
+	if o == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO event_outbox (subscriber, event_type, payload, next_attempt_at)
+		VALUES (?, ?, ?, ?)
+	`, subscriber, event.EventType(), string(payload), time.Now())
+	return err
+}
+
+// Notify wakes the dispatcher (non-blocking)
+func (o *Outbox) Notify() {
+	if o == nil {
+		return
+	}
+	select {
+	case o.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// Start begins the background dispatcher goroutine
+func (o *Outbox) Start(ctx context.Context) {
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		o.dispatcher(ctx)
+	}()
+}
+
+// Stop gracefully stops the dispatcher
+func (o *Outbox) Stop() {
+	close(o.stopCh)
+	o.wg.Wait()
+}
+
+// Heartbeat reports whether the dispatcher is running
+func (o *Outbox) Heartbeat() *health.Heartbeat {
+	return o.heartbeat
+}
+
+func (o *Outbox) dispatcher(ctx context.Context) {
+	ticker := time.NewTicker(OutboxPollInterval)
+	defer ticker.Stop()
+	o.heartbeat.Beat()
+	defer o.heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.stopCh:
+			return
+		case <-o.wakeCh:
+			o.dispatch(ctx)
+		case <-ticker.C:
+			o.heartbeat.Beat()
+			o.dispatch(ctx)
+			o.cleanup()
+		}
+	}
+}
+
+type outboxRow struct {
+	id         int64
+	subscriber string
+	eventType  Type
+	payload    string
+	attempts   int
+}
+
+func (o *Outbox) dispatch(ctx context.Context) {
+	rows, err := o.db.QueryContext(ctx, `
+		SELECT id, subscriber, event_type, payload, attempts
+		FROM event_outbox
+		WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?
+		ORDER BY id
+		LIMIT ?
+	`, time.Now(), OutboxBatchSize)
+	if err != nil {
+		log.Printf("Outbox: failed to load pending events: %v", err)
+		return
+	}
+
+	var batch []outboxRow
+	for rows.Next() {
+		var row outboxRow
+		if err := rows.Scan(&row.id, &row.subscriber, &row.eventType, &row.payload, &row.attempts); err != nil {
+			rows.Close()
+			log.Printf("Outbox: failed to scan pending event: %v", err)
+			return
+		}
+		batch = append(batch, row)
+	}
+	rows.Close()
+
+	for _, row := range batch {
+		if ctx.Err() != nil {
+			return
+		}
+		o.deliver(ctx, row)
+		o.heartbeat.Beat()
+	}
+}
+
+func (o *Outbox) deliver(ctx context.Context, row outboxRow) {
+	o.mu.RLock()
+	sub, ok := o.subscriptions[row.subscriber]
+	o.mu.RUnlock()
+	if !ok {
+		// Subscriber not registered in this process (yet); leave the row for later
+		return
+	}
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("subscriber panicked: %v", r)
+			}
+		}()
+		event, err := Decode(row.eventType, []byte(row.payload))
+		if err != nil {
+			return err
+		}
+		deliveryCtx, cancel := context.WithTimeout(ctx, OutboxDeliveryTimeout)
+		defer cancel()
+		return sub.handler(deliveryCtx, event)
+	}()
+
+	if err == nil {
+		if _, err := o.db.Exec("UPDATE event_outbox SET delivered_at = ?, attempts = attempts + 1 WHERE id = ?", time.Now(), row.id); err != nil {
+			log.Printf("Outbox: failed to mark event %d delivered: %v", row.id, err)
+		}
+		return
+	}
+
+	attempts := row.attempts + 1
+	if attempts >= OutboxMaxAttempts {
+		log.Printf("Outbox: giving up on event %d for %s after %d attempts: %v", row.id, row.subscriber, attempts, err)
+		if _, dbErr := o.db.Exec(`
+			UPDATE event_outbox SET attempts = ?, last_error = ?, failed_at = ? WHERE id = ?
+		`, attempts, err.Error(), time.Now(), row.id); dbErr != nil {
+			log.Printf("Outbox: failed to mark event %d failed: %v", row.id, dbErr)
+		}
+		return
+	}
+
+	// Unrecorded, the attempt is retried without backoff on the next poll
+	if _, dbErr := o.db.Exec(`
+		UPDATE event_outbox SET attempts = ?, last_error = ?, next_attempt_at = ? WHERE id = ?
+	`, attempts, err.Error(), time.Now().Add(Backoff(attempts)), row.id); dbErr != nil {
+		log.Printf("Outbox: failed to schedule a retry of event %d: %v", row.id, dbErr)
+	}
+}
+
+func (o *Outbox) cleanup() {
+	cutoff := time.Now().Add(-OutboxRetentionPeriod)
+	if _, err := o.db.Exec("DELETE FROM event_outbox WHERE delivered_at IS NOT NULL AND delivered_at <= ?", cutoff); err != nil {
+		log.Printf("Outbox: failed to delete delivered events: %v", err)
+	}
+}
+
+// PendingCount returns the number of deliveries still waiting to succeed
+func (o *Outbox) PendingCount() (int, error) {
+	var count int
+	err := o.db.QueryRow(`
+		SELECT COUNT(*) FROM event_outbox WHERE delivered_at IS NULL AND failed_at IS NULL
+	`).Scan(&count)
+	return count, err
+}
+
+// Backlog checks that the dispatcher keeps up: it fails when a delivery has been due for
+// longer than OutboxMaxDelay. Deliveries waiting out a retry's backoff are not overdue.
+func (o *Outbox) Backlog(ctx context.Context) (string, error) {
+	var pending, overdue, failed int
+	err := o.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(CASE WHEN failed_at IS NULL THEN 1 END),
+			COUNT(CASE WHEN failed_at IS NULL AND next_attempt_at <= ? THEN 1 END),
+			COUNT(failed_at)
+		FROM event_outbox WHERE delivered_at IS NULL
+	`, time.Now().Add(-OutboxMaxDelay)).Scan(&pending, &overdue, &failed)
+	if err != nil {
+		return "", fmt.Errorf("failed to count pending deliveries: %w", err)
+	}
+	detail := fmt.Sprintf("%d pending, %d overdue, %d failed", pending, overdue, failed)
+	if overdue > 0 {
+		return detail, fmt.Errorf("%d deliveries overdue by more than %s", overdue, OutboxMaxDelay)
+	}
+	return detail, nil
+}
+
+// Backoff returns the retry delay after the given number of failed attempts
+func Backoff(attempts int) time.Duration {
+	delay := OutboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= OutboxMaxBackoff {
+			return OutboxMaxBackoff
+		}
+	}
+	return delay
+}
+
+// Decode rebuilds a typed event from its serialized payload
+func Decode(eventType Type, payload []byte) (Event, error) {
+	switch eventType {
+	case TypeUserCreated:
+		return decodeAs[UserCreated](payload)
+	case TypeUserSuspended:
+		return decodeAs[UserSuspended](payload)
+	case TypeUserDeleted:
+		return decodeAs[UserDeleted](payload)
+	case TypeUserRestored:
+		return decodeAs[UserRestored](payload)
+	case TypeTokenCreated:
+		return decodeAs[TokenCreated](payload)
+	case TypeTokenRevoked:
+		return decodeAs[TokenRevoked](payload)
+	case TypeTokenRestored:
+		return decodeAs[TokenRestored](payload)
+	case TypeQuotaExceeded:
+		return decodeAs[QuotaExceeded](payload)
+	case TypeAnnouncementPublished:
+		return decodeAs[AnnouncementPublished](payload)
+	case TypeScheduleVersionPublished:
+		return decodeAs[ScheduleVersionPublished](payload)
+	default:
+		return nil, fmt.Errorf("unknown event type: %s", eventType)
+	}
+}
+
+func decodeAs[T Event](payload []byte) (Event, error) {
+	var event T
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
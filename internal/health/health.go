@@ -0,0 +1,253 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CheckTimeout bounds each readiness check, so a locked database fails the
+// probe instead of hanging it
+const CheckTimeout = 2 * time.Second
+
+// StaleBeats is how many intervals a loop may miss before it counts as stuck
+const StaleBeats = 3
+
+// Heartbeat tracks a background loop, which calls Beat on every iteration
+type Heartbeat struct {
+	interval time.Duration
+	last     atomic.Int64 // Unix nanoseconds of the last beat; 0 before the loop starts
+	stopped  atomic.Bool
+}
+
+// NewHeartbeat creates a heartbeat for a loop that runs every interval
+func NewHeartbeat(interval time.Duration) *Heartbeat {
+	return &Heartbeat{interval: interval}
+}
+
+// Beat records that the loop is alive
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Last is when the loop last beat, zero before it starts
+func (h *Heartbeat) Last() time.Time {
+	last := h.last.Load()
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
+// Stop records that the loop returned
+func (h *Heartbeat) Stop() {
+	h.stopped.Store(true)
+}
+
+// Check fails when the loop has not started, has returned, or missed
+// StaleBeats intervals
+func (h *Heartbeat) Check() error {
+	if h.stopped.Load() {
+		return errors.New("stopped")
+	}
+	last := h.last.Load()
+	if last == 0 {
+		return errors.New("not started")
+	}
+	if age := time.Since(time.Unix(0, last)); age > StaleBeats*h.interval {
+		return fmt.Errorf("no heartbeat for %s", age.Truncate(time.Second))
+	}
+	return nil
+}
+
+// Check is a readiness check; a nil error means the dependency is usable. The detail,
+// e.g. a migration version or a queue length, is reported either way.
+type Check func(ctx context.Context) (detail string, err error)
+
+// Checker runs the probes of the whole process, across tenants
+type Checker struct {
+	mu         sync.RWMutex
+	checks     map[string]Check
+	deep       map[string]Check
+	heartbeats map[string]*Heartbeat
+	draining   atomic.Bool
+}
+
+// NewChecker creates a new checker
+func NewChecker() *Checker {
+	return &Checker{
+		checks:     make(map[string]Check),
+		deep:       make(map[string]Check),
+		heartbeats: make(map[string]*Heartbeat),
+	}
+}
+
+// AddCheck registers a readiness check
+func (c *Checker) AddCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// AddDeepCheck registers a check only the deep report runs, for subsystems that may fall
+// behind without the pod needing to leave the rotation, e.g. a delivery backlog
+func (c *Checker) AddDeepCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deep[name] = check
+}
+
+// AddHeartbeat registers a background loop; both probes fail when it is stuck
+func (c *Checker) AddHeartbeat(name string, h *Heartbeat) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.heartbeats[name] = h
+}
+
+// Drain makes readiness fail, so the orchestrator stops routing traffic
+// while the server shuts down
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Status  string     `json:"status"`
+	Error   string     `json:"error,omitempty"`
+	Detail  string     `json:"detail,omitempty"`
+	LastRun *time.Time `json:"lastRun,omitempty"` // Last beat of a background loop
+}
+
+// Report is the body of both probes
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Liveness serves GET /healthz. It only fails when a background loop is
+// stuck, which a restart fixes; dependencies are left to readiness.
+func (c *Checker) Liveness(w http.ResponseWriter, r *http.Request) {
+	report := Report{Status: "ok", Checks: make(map[string]CheckResult)}
+	c.mu.RLock()
+	c.addHeartbeats(&report)
+	c.mu.RUnlock()
+	report.write(w)
+}
+
+// Readiness serves GET /readyz: databases, migrations and background loops
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	c.serve(w, r, false)
+}
+
+// Deep serves GET /healthz/deep: everything readiness checks, plus whether the
+// background subsystems keep up, e.g. the usage writer's queue and the delivery
+// backlogs, with the last run of every loop. Meant for dashboards and on-call, not
+// for the orchestrator.
+func (c *Checker) Deep(w http.ResponseWriter, r *http.Request) {
+	c.serve(w, r, true)
+}
+
+func (c *Checker) serve(w http.ResponseWriter, r *http.Request, deep bool) {
+	report := Report{Status: "ok", Checks: make(map[string]CheckResult)}
+	if c.draining.Load() {
+		report.add("shutdown", "", errors.New("draining"))
+	}
+
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	if deep {
+		for name, check := range c.deep {
+			checks[name] = check
+		}
+	}
+	c.addHeartbeats(&report)
+	c.mu.RUnlock()
+
+	// Checks run concurrently so one slow database does not delay the others
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), CheckTimeout)
+			defer cancel()
+			detail, err := check(ctx)
+			mu.Lock()
+			report.add(name, detail, err)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	report.write(w)
+}
+
+// addHeartbeats reports every background loop; the caller holds c.mu
+func (c *Checker) addHeartbeats(report *Report) {
+	for name, h := range c.heartbeats {
+		report.add(name, "", h.Check())
+		if last := h.Last(); !last.IsZero() {
+			last = last.UTC().Truncate(time.Second)
+			result := report.Checks[name]
+			result.LastRun = &last
+			report.Checks[name] = result
+		}
+	}
+}
+
+func (r *Report) add(name, detail string, err error) {
+	if err != nil {
+		r.Status = "fail"
+		r.Checks[name] = CheckResult{Status: "fail", Error: err.Error(), Detail: detail}
+		return
+	}
+	r.Checks[name] = CheckResult{Status: "ok", Detail: detail}
+}
+
+func (r *Report) write(w http.ResponseWriter) {
+	status := http.StatusOK
+	if r.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(r)
+}
+
+// Database checks that a database answers queries
+func Database(db *sql.DB) Check {
+	return func(ctx context.Context) (string, error) {
+		return "", db.PingContext(ctx)
+	}
+}
+
+// Migrations checks that a database was migrated to latest and that no
+// migration failed halfway
+func Migrations(db *sql.DB, latest int64) Check {
+	return func(ctx context.Context) (string, error) {
+		var version int64
+		var dirty bool
+		err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+		if err != nil {
+			return "", fmt.Errorf("failed to read migration version: %w", err)
+		}
+		detail := fmt.Sprintf("version %d of %d", version, latest)
+		if dirty {
+			return detail, fmt.Errorf("migration %d failed halfway", version)
+		}
+		if version < latest {
+			return detail, fmt.Errorf("at version %d, latest is %d", version, latest)
+		}
+		return detail, nil
+	}
+}
@@ -0,0 +1,95 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"API/internal/apierror"
+	"API/internal/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultMaxBodyBytes is the largest request body accepted when
+	// MAX_BODY_BYTES is unset
+	DefaultMaxBodyBytes = 1 << 20
+
+	// DefaultTimeout is the handler deadline when REQUEST_TIMEOUT is unset
+	DefaultTimeout = 15 * time.Second
+)
+
+// Limits bounds a request. Zero fields fall back to the defaults; a negative
+// Timeout disables the deadline (e.g. for streaming responses).
+type Limits struct {
+	MaxBodyBytes int64
+	Timeout      time.Duration
+}
+
+// routes holds the per-route overrides, keyed by method and route path
+var (
+	routesMu sync.RWMutex
+	routes   = map[string]Limits{}
+)
+
+// SetRoute overrides the limits of one route, e.g. a bulk import that takes
+// a larger body and more time. path is the full route path, parameters
+// included (e.g. "/api/v0/admin/imports").
+func SetRoute(method, path string, l Limits) {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+	routes[method+" "+path] = l
+}
+
+func routeLimits(method, path string) (Limits, bool) {
+	routesMu.RLock()
+	defer routesMu.RUnlock()
+	l, ok := routes[method+" "+path]
+	return l, ok
+}
+
+// Middleware enforces the body size limit and handler deadline of each
+// route. Bodies announcing a larger Content-Length are refused outright;
+// others are cut off once they pass the limit. The deadline is set on the
+// request context, so queries and outbound calls made with it are cancelled,
+// and a 503 is written when the handler gave up without responding.
+func Middleware(defaults Limits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := defaults
+		if override, ok := routeLimits(c.Request.Method, c.FullPath()); ok {
+			if override.MaxBodyBytes != 0 {
+				l.MaxBodyBytes = override.MaxBodyBytes
+			}
+			if override.Timeout != 0 {
+				l.Timeout = override.Timeout
+			}
+		}
+
+		if l.MaxBodyBytes > 0 && c.Request.Body != nil {
+			if c.Request.ContentLength > l.MaxBodyBytes {
+				common.Abort(c, http.StatusRequestEntityTooLarge, common.CreateErrorResponse(
+					apierror.Newf(apierror.PayloadTooLarge, "request body exceeds %d bytes", l.MaxBodyBytes)))
+				return
+			}
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, l.MaxBodyBytes)
+		}
+
+		if l.Timeout <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), l.Timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			common.JSON(c, http.StatusServiceUnavailable, common.CreateErrorResponse(
+				apierror.Newf(apierror.Timeout, "request did not complete within %s", l.Timeout)))
+		}
+	}
+}
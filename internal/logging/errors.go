@@ -0,0 +1,202 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultErrorLogSize is how many errors an ErrorLog keeps unless told otherwise
+	DefaultErrorLogSize = 200
+
+	// maxErrorBody caps how much of an error response is kept to find its message
+	maxErrorBody = 4 << 10
+)
+
+// ErrorEntry is a request that failed on the server's side
+type ErrorEntry struct {
+	RequestID string    `json:"requestId"`
+	Timestamp time.Time `json:"timestamp"`
+	Status    int       `json:"status"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Route     string    `json:"route"`
+	ClientIP  string    `json:"clientIp"`
+	UserID    *int64    `json:"userId,omitempty"`
+	TokenID   *int64    `json:"tokenId,omitempty"`
+	Latency   float64   `json:"latencyMs"`
+	Error     string    `json:"error"`
+	Stack     string    `json:"stack,omitempty"` // Only for panics
+}
+
+// ErrorLog keeps the latest requests answered with a 5xx status, or that panicked, in
+// a ring buffer, so admins can triage them without access to the server's logs
+type ErrorLog struct {
+	mu      sync.Mutex
+	entries []ErrorEntry
+	next    int // Where the next entry goes once the buffer is full
+}
+
+// NewErrorLog creates an error log keeping the latest size errors
+func NewErrorLog(size int) *ErrorLog {
+	if size <= 0 {
+		size = DefaultErrorLogSize
+	}
+	return &ErrorLog{entries: make([]ErrorEntry, 0, size)}
+}
+
+// Size is how many errors the log keeps
+func (l *ErrorLog) Size() int {
+	return cap(l.entries)
+}
+
+// Record adds an error, dropping the oldest once the log is full
+func (l *ErrorLog) Record(e ErrorEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) < cap(l.entries) {
+		l.entries = append(l.entries, e)
+		return
+	}
+	l.entries[l.next] = e
+	l.next = (l.next + 1) % len(l.entries)
+}
+
+// Recent returns up to limit errors matching keep (every one when nil), newest first
+func (l *ErrorLog) Recent(limit int, keep func(ErrorEntry) bool) []ErrorEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := []ErrorEntry{}
+	n := len(l.entries)
+	for i := 0; i < n && len(recent) < limit; i++ {
+		// Walk back from the newest entry, just before next
+		e := l.entries[(l.next-1-i+2*n)%n]
+		if keep == nil || keep(e) {
+			recent = append(recent, e)
+		}
+	}
+	return recent
+}
+
+// Middleware records the requests answered with a 5xx status and those that panic,
+// with the message of their error response, the route and the authenticated user. It
+// goes after Middleware, whose annotations it reads, and re-raises panics for the
+// recovery middleware to answer.
+func (l *ErrorLog) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		writer := &errorCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		defer func() {
+			if r := recover(); r != nil {
+				// gin's recovery answers with a 500 once the panic reaches it
+				e := errorEntry(c, start, http.StatusInternalServerError)
+				e.Error = fmt.Sprintf("panic: %v", r)
+				e.Stack = string(debug.Stack())
+				l.Record(e)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if status < http.StatusInternalServerError {
+			return
+		}
+		e := errorEntry(c, start, status)
+		e.Error = errorMessage([]byte(writer.body.String()))
+		if len(c.Errors) > 0 {
+			if e.Error != "" {
+				e.Error += ": "
+			}
+			e.Error += c.Errors.String()
+		}
+		l.Record(e)
+	}
+}
+
+// entry describes a failed request from what the request log knows of it
+func errorEntry(c *gin.Context, start time.Time, status int) ErrorEntry {
+	e := ErrorEntry{
+		RequestID: RequestID(c),
+		Timestamp: start.UTC(),
+		Status:    status,
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		Route:     c.FullPath(),
+		ClientIP:  c.ClientIP(),
+		Latency:   float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if attrs, ok := c.Get(contextKeyAttrs); ok {
+		for _, attr := range attrs.([]slog.Attr) {
+			id, ok := attr.Value.Any().(int64)
+			if !ok {
+				continue
+			}
+			switch attr.Key {
+			case "userId":
+				e.UserID = &id
+			case "tokenId":
+				e.TokenID = &id
+			}
+		}
+	}
+	return e
+}
+
+// errorMessage finds the message of an error response, in the envelope of the API
+// ({"errors": [{"message": ...}]}) or the middleware's ({"error": ...})
+func errorMessage(body []byte) string {
+	var response struct {
+		Error  string `json:"error"`
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(body, &response) != nil {
+		return strings.TrimSpace(string(body))
+	}
+	if response.Error != "" {
+		return response.Error
+	}
+	messages := make([]string, 0, len(response.Errors))
+	for _, e := range response.Errors {
+		messages = append(messages, e.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// errorCaptureWriter keeps the start of 5xx responses, to record their message
+type errorCaptureWriter struct {
+	gin.ResponseWriter
+	body strings.Builder
+}
+
+func (w *errorCaptureWriter) capture(data string) {
+	if w.Status() >= http.StatusInternalServerError && w.body.Len() < maxErrorBody {
+		w.body.WriteString(data[:min(len(data), maxErrorBody-w.body.Len())])
+	}
+}
+
+func (w *errorCaptureWriter) Write(data []byte) (int, error) {
+	w.capture(string(data))
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *errorCaptureWriter) WriteString(s string) (int, error) {
+	w.capture(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// HeaderRequestID carries the request ID, in both directions
+	HeaderRequestID = "X-Request-ID"
+
+	// ContextKeyRequestID is the gin context key of the request ID
+	ContextKeyRequestID = "request_id"
+
+	contextKeyAttrs = "logging_attrs"
+)
+
+// validRequestID matches the request IDs accepted from upstream proxies;
+// anything else is replaced with a fresh ID
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// NewLogger creates the process logger: JSON lines on stdout, or text when
+// format is "text"
+func NewLogger(format string) *slog.Logger {
+	if format == "text" {
+		return slog.New(slog.NewTextHandler(os.Stdout, nil))
+	}
+	return slog.New(slog.NewJSONHandler(os.Stdout, nil))
+}
+
+// RequestID returns the request's ID, assigning a new one (and echoing it in
+// the X-Request-ID response header) on first use
+func RequestID(c *gin.Context) string {
+	if id := c.GetString(ContextKeyRequestID); id != "" {
+		return id
+	}
+	id := uuid.New().String()
+	c.Set(ContextKeyRequestID, id)
+	c.Header(HeaderRequestID, id)
+	return id
+}
+
+// Annotate adds an attribute to the request's log line, e.g. the
+// authenticated user's ID
+func Annotate(c *gin.Context, key string, value any) {
+	attrs, _ := c.Get(contextKeyAttrs)
+	list, _ := attrs.([]slog.Attr)
+	c.Set(contextKeyAttrs, append(list, slog.Any(key, value)))
+}
+
+// Middleware assigns every request its ID, reusing a well-formed
+// X-Request-ID from the client or proxy, and logs one line per request once
+// it is handled. It replaces gin's default logger.
+func Middleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		if id := c.GetHeader(HeaderRequestID); validRequestID.MatchString(id) {
+			c.Set(ContextKeyRequestID, id)
+			c.Header(HeaderRequestID, id)
+		}
+		requestID := RequestID(c)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("requestId", requestID),
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("clientIp", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if extra, ok := c.Get(contextKeyAttrs); ok {
+			attrs = append(attrs, extra.([]slog.Attr)...)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
@@ -0,0 +1,230 @@
+// Package metrics collects the latency and outcome of the requests each feature serves,
+// by tenant and the calling user's group, and the invalid bearer tokens the tenants
+// rejected, and serves them in the Prometheus text format.
+package metrics
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the latency histograms, in seconds
+var LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// NoGroup labels the requests denied before the caller's token was validated
+const NoGroup = "none"
+
+// GroupNames returns the names of a tenant's groups by ID, to label the metrics with
+type GroupNames func(ctx context.Context) (map[int64]string, error)
+
+// Registry holds the metrics of every tenant of the process
+type Registry struct {
+	mu      sync.Mutex
+	tenants []*Tenant
+	token   string
+}
+
+// NewRegistry creates a registry. With a token, scrapes must send it as a bearer token.
+func NewRegistry(token string) *Registry {
+	return &Registry{token: token}
+}
+
+// Tenant adds a tenant, whose group names are looked up on every scrape
+func (r *Registry) Tenant(id string, groupNames GroupNames) *Tenant {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := &Tenant{id: id, groupNames: groupNames, series: make(map[seriesKey]*series)}
+	r.tenants = append(r.tenants, t)
+	return t
+}
+
+// Tenant holds the metrics of a tenant's requests
+type Tenant struct {
+	id         string
+	groupNames GroupNames
+
+	mu     sync.Mutex
+	series map[seriesKey]*series
+	// Invalid bearer tokens, and tries refused because the address was blocked
+	tokenFailures, tokenThrottled uint64
+}
+
+type seriesKey struct {
+	feature string
+	groupID int64 // 0 before the token is validated
+}
+
+// series is the latency histogram of a feature and group, with its requests counted by
+// status class
+type series struct {
+	buckets []uint64 // Observations per bucket, not cumulative; the last is +Inf
+	sum     float64
+	count   uint64
+	classes map[string]uint64 // "2xx", "4xx", "5xx"...
+}
+
+// ObserveRequest records a request to a feature by a user of a group (0 when unknown).
+// It does nothing on a nil tenant.
+func (t *Tenant) ObserveRequest(feature string, groupID int64, status int, latency time.Duration) {
+	if t == nil {
+		return
+	}
+	seconds := latency.Seconds()
+	bucket := sort.SearchFloat64s(LatencyBuckets, seconds)
+	class := strconv.Itoa(status/100) + "xx"
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := seriesKey{feature: feature, groupID: groupID}
+	s := t.series[key]
+	if s == nil {
+		s = &series{buckets: make([]uint64, len(LatencyBuckets)+1), classes: make(map[string]uint64)}
+		t.series[key] = s
+	}
+	s.buckets[bucket]++
+	s.sum += seconds
+	s.count++
+	s.classes[class]++
+}
+
+// ObserveTokenFailure records an invalid bearer token, or with throttled a request
+// refused before its token was checked because the client sent too many invalid ones.
+// It does nothing on a nil tenant.
+func (t *Tenant) ObserveTokenFailure(throttled bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if throttled {
+		t.tokenThrottled++
+	} else {
+		t.tokenFailures++
+	}
+}
+
+// sample is a series copied out of a tenant, with its labels resolved
+type sample struct {
+	tenant, feature, group string
+	series
+}
+
+// snapshot copies the tenant's series, naming their groups
+func (t *Tenant) snapshot(ctx context.Context) []sample {
+	names, err := t.groupNames(ctx)
+	if err != nil {
+		log.Printf("Metrics: failed to get the groups of tenant %s: %v", t.id, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	samples := make([]sample, 0, len(t.series))
+	for key, s := range t.series {
+		group := NoGroup
+		if key.groupID != 0 {
+			group = names[key.groupID]
+			if group == "" {
+				group = strconv.FormatInt(key.groupID, 10)
+			}
+		}
+		copied := *s
+		copied.buckets = append([]uint64(nil), s.buckets...)
+		copied.classes = make(map[string]uint64, len(s.classes))
+		for class, n := range s.classes {
+			copied.classes[class] = n
+		}
+		samples = append(samples, sample{tenant: t.id, feature: key.feature, group: group, series: copied})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].feature != samples[j].feature {
+			return samples[i].feature < samples[j].feature
+		}
+		return samples[i].group < samples[j].group
+	})
+	return samples
+}
+
+// Handler serves GET /metrics in the Prometheus text exposition format
+func (r *Registry) Handler(w http.ResponseWriter, req *http.Request) {
+	if r.token != "" {
+		token, _ := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(r.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "a valid metrics token is required", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	r.mu.Lock()
+	tenants := append([]*Tenant(nil), r.tenants...)
+	r.mu.Unlock()
+	var samples []sample
+	for _, t := range tenants {
+		samples = append(samples, t.snapshot(req.Context())...)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].id < tenants[j].id })
+
+	var b strings.Builder
+	b.WriteString("# HELP api_feature_request_duration_seconds Latency of the requests to a feature, by the caller's group.\n")
+	b.WriteString("# TYPE api_feature_request_duration_seconds histogram\n")
+	for _, s := range samples {
+		labels := s.labels()
+		var cumulative uint64
+		for i, bound := range LatencyBuckets {
+			cumulative += s.buckets[i]
+			fmt.Fprintf(&b, "api_feature_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "api_feature_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, s.count)
+		fmt.Fprintf(&b, "api_feature_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(s.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "api_feature_request_duration_seconds_count{%s} %d\n", labels, s.count)
+	}
+	b.WriteString("# HELP api_feature_requests_total Requests to a feature, by the caller's group and the status class of the response.\n")
+	b.WriteString("# TYPE api_feature_requests_total counter\n")
+	for _, s := range samples {
+		classes := make([]string, 0, len(s.classes))
+		for class := range s.classes {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+		for _, class := range classes {
+			fmt.Fprintf(&b, "api_feature_requests_total{%s,code=\"%s\"} %d\n", s.labels(), class, s.classes[class])
+		}
+	}
+
+	b.WriteString("# HELP api_token_validation_failures_total Bearer tokens rejected as invalid, and requests refused because their address sent too many.\n")
+	b.WriteString("# TYPE api_token_validation_failures_total counter\n")
+	for _, t := range tenants {
+		t.mu.Lock()
+		failures, throttled := t.tokenFailures, t.tokenThrottled
+		t.mu.Unlock()
+		fmt.Fprintf(&b, "api_token_validation_failures_total{tenant=\"%s\",outcome=\"rejected\"} %d\n", escape(t.id), failures)
+		fmt.Fprintf(&b, "api_token_validation_failures_total{tenant=\"%s\",outcome=\"throttled\"} %d\n", escape(t.id), throttled)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// labels renders the labels every series of a sample carries
+func (s sample) labels() string {
+	return fmt.Sprintf(`tenant="%s",feature="%s",group="%s"`, escape(s.tenant), escape(s.feature), escape(s.group))
+}
+
+// labelEscaper escapes label values as the text format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
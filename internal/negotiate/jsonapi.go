@@ -0,0 +1,270 @@
+package negotiate
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"API/internal/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MIMEJSONAPI is the JSON:API content type offered by Write
+const MIMEJSONAPI = "application/vnd.api+json"
+
+// resourceTypes maps JSON keys (and the last segment of route paths) to the
+// JSON:API type of the resources under them. Keys not listed are their own type.
+var resourceTypes = map[string]string{}
+
+// RegisterResourceType declares the JSON:API type of the resources found
+// under a JSON key, e.g. "lunch" holds "foods". Call it from init functions.
+func RegisterResourceType(key, resourceType string) {
+	resourceTypes[key] = resourceType
+}
+
+func resourceType(key string) string {
+	if t, ok := resourceTypes[key]; ok {
+		return t
+	}
+	return key
+}
+
+// wantsJSONAPI reports whether the client asked for JSON:API, either with
+// ?format=jsonapi or through Accept
+func wantsJSONAPI(c *gin.Context, format string) bool {
+	return c.Query("format") == "jsonapi" || format == MIMEJSONAPI
+}
+
+// jsonapiDocument converts a response envelope into a JSON:API document:
+//   - objects with an "id" (or "slug" or "code") become resources, with the
+//     other fields as attributes;
+//   - nested objects and lists of objects that have one become relationships,
+//     and the related objects are added to "included";
+//   - an object holding a single list (e.g. {"users": [...], "total": 3})
+//     becomes that list of resources, its other fields going to "meta";
+//   - the envelope metadata goes to "meta", and nextCursor to links.next.
+func jsonapiDocument(c *gin.Context, status int, response interface{}, errors []apierror.Error) ([]byte, error) {
+	envelope, err := normalize(response)
+	if err != nil {
+		return nil, err
+	}
+	fields, _ := envelope.(map[string]interface{})
+	meta, _ := fields["metadata"].(map[string]interface{})
+	if meta == nil {
+		meta = map[string]interface{}{}
+	}
+	doc := map[string]interface{}{
+		"jsonapi": map[string]interface{}{"version": "1.1"},
+		"links":   map[string]interface{}{"self": c.Request.URL.RequestURI()},
+	}
+
+	if len(errors) > 0 {
+		doc["errors"] = jsonapiErrors(status, errors)
+		doc["meta"] = meta
+		return json.Marshal(doc)
+	}
+
+	if cursor, ok := meta["nextCursor"].(string); ok {
+		next := *c.Request.URL
+		query := next.Query()
+		query.Set("cursor", cursor)
+		next.RawQuery = query.Encode()
+		doc["links"].(map[string]interface{})["next"] = next.RequestURI()
+		delete(meta, "nextCursor")
+	}
+
+	b := &jsonapiBuilder{seen: make(map[string]bool)}
+	typ := resourceType(pathType(c))
+	switch v := fields["data"].(type) {
+	case nil:
+		doc["data"] = nil
+	case []interface{}:
+		doc["data"] = b.resources(typ, v)
+	case map[string]interface{}:
+		if key, list, ok := singleList(v); ok {
+			for k, value := range v {
+				if k != key {
+					meta[k] = value
+				}
+			}
+			doc["data"] = b.resources(resourceType(key), list)
+		} else {
+			doc["data"] = b.resource(typ, v, c.Request.URL.RequestURI())
+		}
+	default:
+		doc["data"] = b.resource(typ, map[string]interface{}{"value": v}, c.Request.URL.RequestURI())
+	}
+	if len(b.included) > 0 {
+		doc["included"] = b.included
+	}
+	doc["meta"] = meta
+	return json.Marshal(doc)
+}
+
+// jsonapiBuilder collects the related resources of a document, once each
+type jsonapiBuilder struct {
+	included []map[string]interface{}
+	seen     map[string]bool
+}
+
+func (b *jsonapiBuilder) resources(typ string, list []interface{}) []map[string]interface{} {
+	resources := make([]map[string]interface{}, 0, len(list))
+	for i, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			obj = map[string]interface{}{"value": item}
+		}
+		resources = append(resources, b.resource(typ, obj, strconv.Itoa(i)))
+	}
+	return resources
+}
+
+// resource converts an object; fallbackID identifies objects without an ID
+func (b *jsonapiBuilder) resource(typ string, obj map[string]interface{}, fallbackID string) map[string]interface{} {
+	id, ok := resourceID(obj)
+	if !ok {
+		id = fallbackID
+	}
+	attributes := map[string]interface{}{}
+	relationships := map[string]interface{}{}
+	resourceMeta := map[string]interface{}{}
+
+	for key, value := range obj {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if _, ok := resourceID(v); ok {
+				relationships[key] = map[string]interface{}{"data": b.include(resourceType(key), v)}
+				continue
+			}
+		case []interface{}:
+			if related, ok := relatedList(v); ok {
+				linkage := make([]map[string]interface{}, 0, len(related))
+				for _, r := range related {
+					linkage = append(linkage, b.include(resourceType(key), r))
+				}
+				relationships[key] = map[string]interface{}{"data": linkage}
+				continue
+			}
+		}
+		switch key {
+		case "id":
+		case "type":
+			// "type" is reserved for the resource type itself
+			resourceMeta[key] = value
+		default:
+			attributes[key] = value
+		}
+	}
+
+	resource := map[string]interface{}{
+		"type":       typ,
+		"id":         id,
+		"attributes": attributes,
+	}
+	if len(relationships) > 0 {
+		resource["relationships"] = relationships
+	}
+	if len(resourceMeta) > 0 {
+		resource["meta"] = resourceMeta
+	}
+	return resource
+}
+
+// include adds a related object to "included" and returns its linkage
+func (b *jsonapiBuilder) include(typ string, obj map[string]interface{}) map[string]interface{} {
+	id, _ := resourceID(obj)
+	if key := typ + "/" + id; !b.seen[key] {
+		b.seen[key] = true
+		b.included = append(b.included, b.resource(typ, obj, id))
+	}
+	return map[string]interface{}{"type": typ, "id": id}
+}
+
+// resourceID returns the identifier of an object, as a string
+func resourceID(obj map[string]interface{}) (string, bool) {
+	for _, key := range []string{"id", "slug", "code"} {
+		if v, ok := obj[key]; ok && v != nil {
+			return csvCell(v), true
+		}
+	}
+	return "", false
+}
+
+// relatedList returns the objects of a non-empty list when all have an ID
+func relatedList(list []interface{}) ([]map[string]interface{}, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+	related := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if _, ok := resourceID(obj); !ok {
+			return nil, false
+		}
+		related = append(related, obj)
+	}
+	return related, true
+}
+
+// singleList finds the only list of an object whose other fields are scalars
+func singleList(obj map[string]interface{}) (string, []interface{}, bool) {
+	if _, ok := resourceID(obj); ok {
+		return "", nil, false
+	}
+	var key string
+	var list []interface{}
+	for k, value := range obj {
+		switch v := value.(type) {
+		case []interface{}:
+			if list != nil {
+				return "", nil, false
+			}
+			key, list = k, v
+		case map[string]interface{}:
+			return "", nil, false
+		}
+	}
+	return key, list, list != nil
+}
+
+// pathType is the last static segment of the route, e.g. "users" for
+// /api/admin/users
+func pathType(c *gin.Context) string {
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if s := segments[i]; s != "" && !strings.HasPrefix(s, ":") && !strings.HasPrefix(s, "*") {
+			return s
+		}
+	}
+	return "resources"
+}
+
+// fieldPointer turns field paths like "items[0].meal_type" into JSON pointers
+var fieldPointer = strings.NewReplacer(".", "/", "[", "/", "]", "")
+
+func jsonapiErrors(status int, errors []apierror.Error) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(errors))
+	for _, e := range errors {
+		obj := map[string]interface{}{
+			"status": strconv.Itoa(status),
+			"code":   e.Code,
+			"title":  http.StatusText(status),
+			"detail": e.Message,
+			"links":  map[string]interface{}{"about": e.DocsURL},
+		}
+		if e.Field != "" {
+			obj["source"] = map[string]interface{}{"pointer": "/data/attributes/" + fieldPointer.Replace(e.Field)}
+		}
+		out = append(out, obj)
+	}
+	return out
+}
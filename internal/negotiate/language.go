@@ -0,0 +1,44 @@
+package negotiate
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Language picks the language of a response: ?lang= when set, otherwise the
+// best match of Accept-Language, otherwise supported[0]. Only the primary
+// subtag is compared, so "en-GB" matches "en". It fails when ?lang= is not
+// one of supported.
+func Language(c *gin.Context, supported []string) (string, error) {
+	c.Writer.Header().Add("Vary", "Accept-Language")
+
+	if lang := strings.ToLower(c.Query("lang")); lang != "" {
+		if !slices.Contains(supported, lang) {
+			return "", fmt.Errorf("lang must be one of %s", strings.Join(supported, ", "))
+		}
+		return lang, nil
+	}
+
+	best, bestQ := supported[0], 0.0
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		// Earlier tags win ties, as clients list them by preference
+		if q > bestQ && slices.Contains(supported, primary) {
+			best, bestQ = primary, q
+		}
+	}
+	return best, nil
+}
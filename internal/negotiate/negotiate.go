@@ -0,0 +1,281 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"unicode"
+
+	"API/internal/apierror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+// MIMECSV is the CSV content type offered by Write
+const MIMECSV = "text/csv"
+
+// offeredFormats are the formats Write can produce, JSON first so that it
+// is picked for */* and missing Accept headers
+var offeredFormats = []string{
+	binding.MIMEJSON,
+	binding.MIMEXML,
+	binding.MIMEXML2,
+	binding.MIMEMSGPACK,
+	binding.MIMEMSGPACK2,
+	MIMECSV,
+	MIMEJSONAPI,
+}
+
+// Write writes a response envelope in the format the client asked for with
+// Accept: JSON (the default), XML, MessagePack, CSV or JSON:API, which can
+// also be asked for with ?format=jsonapi. CSV has no room for the envelope, so
+// only the data (or the errors) is written.
+func Write(c *gin.Context, status int, response, data interface{}, errors []apierror.Error) {
+	c.Writer.Header().Add("Vary", "Accept")
+
+	format := c.NegotiateFormat(offeredFormats...)
+	if wantsJSONAPI(c, format) {
+		body, err := jsonapiDocument(c, status, response, errors)
+		if err != nil {
+			c.String(http.StatusInternalServerError, "failed to render JSON:API")
+			return
+		}
+		c.Data(status, MIMEJSONAPI, body)
+		return
+	}
+
+	switch format {
+	case binding.MIMEXML, binding.MIMEXML2:
+		body, err := responseXML(response)
+		if err != nil {
+			c.String(http.StatusInternalServerError, "failed to render XML")
+			return
+		}
+		c.Data(status, binding.MIMEXML+"; charset=utf-8", body)
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		c.Render(status, render.MsgPack{Data: response})
+	case MIMECSV:
+		var body []byte
+		var err error
+		if len(errors) > 0 {
+			body, err = errorsCSV(errors)
+		} else {
+			body, err = dataCSV(data)
+		}
+		if err != nil {
+			c.String(http.StatusInternalServerError, "failed to render CSV")
+			return
+		}
+		c.Data(status, MIMECSV+"; charset=utf-8", body)
+	default:
+		c.JSON(status, response)
+	}
+}
+
+func errorsCSV(errors []apierror.Error) ([]byte, error) {
+	rows := [][]string{{"code", "message", "field"}}
+	for _, e := range errors {
+		rows = append(rows, []string{string(e.Code), e.Message, e.Field})
+	}
+	return writeCSV(rows)
+}
+
+// dataCSV turns response data into a table. The columns are the JSON field
+// names:
+//   - a list becomes one row per item;
+//   - an object holding lists (e.g. {"users": [...], "total": 3}) becomes the
+//     rows of those lists, with a "section" column when there are several;
+//   - any other object becomes a single row.
+//
+// Nested objects and lists inside a row are written as JSON.
+func dataCSV(data interface{}) ([]byte, error) {
+	if data == nil {
+		return nil, nil
+	}
+	value, err := normalize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []map[string]interface{}
+	var sections []string
+	switch v := value.(type) {
+	case []interface{}:
+		items = csvItems(v, "")
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			if list, ok := v[key].([]interface{}); ok {
+				sections = append(sections, key)
+				items = append(items, csvItems(list, key)...)
+			}
+		}
+		if len(sections) == 0 {
+			items = []map[string]interface{}{v}
+		}
+	default:
+		items = []map[string]interface{}{{"value": v}}
+	}
+
+	// Columns are the union of all item keys
+	columnSet := make(map[string]bool)
+	for _, item := range items {
+		for key := range item {
+			columnSet[key] = true
+		}
+	}
+	delete(columnSet, "section")
+	columns := sortedKeys(columnSet)
+	if len(sections) > 1 {
+		columns = append([]string{"section"}, columns...)
+	}
+
+	rows := [][]string{columns}
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = csvCell(item[column])
+		}
+		rows = append(rows, row)
+	}
+	return writeCSV(rows)
+}
+
+// csvItems converts list items to rows, tagging them with their section
+func csvItems(list []interface{}, section string) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(list))
+	for _, entry := range list {
+		item, ok := entry.(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{"value": entry}
+		}
+		if section != "" {
+			item["section"] = section
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func csvCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalize converts a value to its JSON form (maps, lists, strings, numbers,
+// booleans), so that every format uses the JSON field names
+func normalize(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// responseXML writes the JSON form of a response as XML under <response>.
+// Object keys become elements and list items are wrapped in <item>. Keys
+// that are not valid element names (e.g. week numbers) are written as
+// <item key="...">.
+func responseXML(response interface{}) ([]byte, error) {
+	value, err := normalize(response)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	if err := encodeXML(encoder, "response", value); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXML(encoder *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !isXMLName(name) {
+		start = xml.StartElement{
+			Name: xml.Name{Local: "item"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}},
+		}
+	}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			if err := encodeXML(encoder, key, v[key]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := encodeXML(encoder, "item", item); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := encoder.EncodeToken(xml.CharData(csvCell(v))); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// isXMLName reports whether name can be used as an element name as is
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func writeCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
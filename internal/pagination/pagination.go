@@ -0,0 +1,98 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultLimit is the page size when ?limit= is missing
+	DefaultLimit = 50
+
+	// MaxLimit caps ?limit= so a single request cannot read a whole table
+	MaxLimit = 100
+)
+
+// ErrInvalidCursor is returned for cursors that were not issued by Next
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the last item of a page. Lists are paged by descending ID,
+// newest first, which stays stable while rows are inserted, unlike offsets.
+type Cursor struct {
+	ID int64 `json:"id"`
+}
+
+// Encode returns the opaque form of the cursor sent to clients
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode parses a cursor returned by Encode
+func Decode(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Params is a page request. After is nil for the first page.
+type Params struct {
+	Limit int
+	After *Cursor
+}
+
+// FromQuery reads ?limit= and ?cursor=, falling back to DefaultLimit and
+// capping the limit at MaxLimit
+func FromQuery(c *gin.Context) (Params, error) {
+	params := Params{Limit: DefaultLimit}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return params, errors.New("invalid limit")
+		}
+		params.Limit = min(limit, MaxLimit)
+	}
+	if v := c.Query("cursor"); v != "" {
+		after, err := Decode(v)
+		if err != nil {
+			return params, err
+		}
+		params.After = after
+	}
+	return params, nil
+}
+
+// Where returns the condition selecting the rows after the cursor on the
+// given ID column, or "1 = 1" for the first page
+func (p Params) Where(idColumn string) (string, []interface{}) {
+	if p.After == nil {
+		return "1 = 1", nil
+	}
+	return idColumn + " < ?", []interface{}{p.After.ID}
+}
+
+// FetchLimit is the LIMIT to query with: one more row than the page holds,
+// so Next can tell whether another page follows
+func (p Params) FetchLimit() int {
+	return p.Limit + 1
+}
+
+// Next trims the extra row fetched with FetchLimit and returns the cursor of
+// the following page, or "" on the last page
+func Next[T any](items []T, p Params, id func(T) int64) ([]T, string) {
+	if len(items) <= p.Limit {
+		return items, ""
+	}
+	items = items[:p.Limit]
+	return items, Cursor{ID: id(items[len(items)-1])}.Encode()
+}
@@ -0,0 +1,293 @@
+package realtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"API/internal/health"
+)
+
+const (
+	// DefaultPollInterval is how often the hub looks for changes it was not told about,
+	// e.g. writes made by another instance or announcements starting on their own
+	DefaultPollInterval = 15 * time.Second
+
+	// SubscriberBuffer is how many messages a subscriber may fall behind before it is
+	// dropped, so that one slow client never holds up the others
+	SubscriberBuffer = 32
+
+	// snapshotTimeout bounds the queries of one refresh
+	snapshotTimeout = 10 * time.Second
+)
+
+// Topic is a piece of state clients can follow live, e.g. the announcements running now
+type Topic struct {
+	Name string
+	// Feature is the feature a token needs to follow the topic
+	Feature string
+	// Snapshot returns the topic's current state in a language
+	Snapshot func(ctx context.Context, lang string) (interface{}, error)
+}
+
+// Message is the state of a topic in a language. IDs grow with every change the hub
+// sees; they are per process, so clients must not compare them across reconnects.
+type Message struct {
+	ID    uint64
+	Topic string
+	Lang  string
+	Data  json.RawMessage
+}
+
+type stateKey struct {
+	topic, lang string
+}
+
+// Hub keeps the latest state of every topic in every language and sends the changes to
+// the subscribers following them. States are refreshed every poll interval and whenever
+// Notify is called, and only sent on when they differ from the last one.
+type Hub struct {
+	languages []string
+	interval  time.Duration
+
+	mu          sync.Mutex
+	topics      []Topic
+	state       map[stateKey]Message
+	subscribers map[*Subscriber]bool
+	lastID      uint64
+	stopped     bool
+
+	wakeCh    chan struct{}
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	heartbeat *health.Heartbeat
+}
+
+// NewHub creates a hub serving its topics in languages, refreshed every interval. A zero
+// interval falls back to DefaultPollInterval.
+func NewHub(interval time.Duration, languages ...string) *Hub {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &Hub{
+		languages:   languages,
+		interval:    interval,
+		state:       make(map[stateKey]Message),
+		subscribers: make(map[*Subscriber]bool),
+		wakeCh:      make(chan struct{}, 1),
+		stopCh:      make(chan struct{}),
+		heartbeat:   health.NewHeartbeat(interval + snapshotTimeout),
+	}
+}
+
+// Register adds topics. Call it before Start.
+func (h *Hub) Register(topics ...Topic) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.topics = append(h.topics, topics...)
+}
+
+// Topics returns the registered topics
+func (h *Hub) Topics() []Topic {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Topic(nil), h.topics...)
+}
+
+// Languages returns the languages topics are served in, the default first
+func (h *Hub) Languages() []string {
+	return h.languages
+}
+
+// Notify asks for a refresh without waiting for the next poll (non-blocking), e.g. after
+// a write that changes a topic
+func (h *Hub) Notify() {
+	if h == nil {
+		return
+	}
+	select {
+	case h.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// Start refreshes the topics now and then every poll interval and on Notify. Subscribers
+// are dropped when ctx is done or the hub is stopped, so that open streams end.
+func (h *Hub) Start(ctx context.Context) {
+	h.refresh(ctx)
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		defer h.closeAll()
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		h.heartbeat.Beat()
+		defer h.heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.stopCh:
+				return
+			case <-h.wakeCh:
+				h.refresh(ctx)
+			case <-ticker.C:
+				h.heartbeat.Beat()
+				h.refresh(ctx)
+			}
+		}
+	}()
+}
+
+// Stop ends the refreshes and drops every subscriber
+func (h *Hub) Stop() {
+	close(h.stopCh)
+	h.wg.Wait()
+}
+
+// Heartbeat reports whether the refresh loop is running
+func (h *Hub) Heartbeat() *health.Heartbeat {
+	return h.heartbeat
+}
+
+// refresh takes a snapshot of every topic in every language and sends the changed ones
+func (h *Hub) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, snapshotTimeout)
+	defer cancel()
+
+	for _, topic := range h.Topics() {
+		for _, lang := range h.languages {
+			data, err := topic.Snapshot(ctx, lang)
+			if err != nil {
+				log.Printf("Realtime: failed to refresh %s (%s): %v", topic.Name, lang, err)
+				continue
+			}
+			raw, err := json.Marshal(data)
+			if err != nil {
+				log.Printf("Realtime: failed to encode %s (%s): %v", topic.Name, lang, err)
+				continue
+			}
+			h.publish(topic.Name, lang, raw)
+		}
+	}
+}
+
+// publish stores a state and sends it to the topic's subscribers when it changed
+func (h *Hub) publish(topic, lang string, data json.RawMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := stateKey{topic, lang}
+	if current, ok := h.state[key]; ok && bytes.Equal(current.Data, data) {
+		return
+	}
+	h.lastID++
+	msg := Message{ID: h.lastID, Topic: topic, Lang: lang, Data: data}
+	h.state[key] = msg
+
+	for s := range h.subscribers {
+		if s.lang != lang || !s.topics[topic] {
+			continue
+		}
+		select {
+		case s.ch <- msg:
+		default:
+			// Too far behind; the client reconnects and starts over from the current state
+			delete(h.subscribers, s)
+			close(s.ch)
+		}
+	}
+}
+
+// Subscriber receives the changes of the topics it follows on C, which is closed when it
+// is dropped
+type Subscriber struct {
+	C <-chan Message
+
+	ch     chan Message
+	lang   string
+	topics map[string]bool
+}
+
+// Subscribe follows topics in lang. C starts with their current states, so a client has
+// everything to show before the first change.
+func (h *Hub) Subscribe(lang string, topics []string) *Subscriber {
+	ch := make(chan Message, SubscriberBuffer+len(topics))
+	s := &Subscriber{C: ch, ch: ch, lang: lang, topics: make(map[string]bool)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, topic := range topics {
+		s.topics[topic] = true
+		if msg, ok := h.state[stateKey{topic, lang}]; ok {
+			ch <- msg
+		}
+	}
+	if h.stopped {
+		close(ch)
+		return s
+	}
+	h.subscribers[s] = true
+	return s
+}
+
+// Follow adds topics to a subscriber, sending their current states on C first
+func (h *Hub) Follow(s *Subscriber, topics []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.subscribers[s] {
+		return
+	}
+	for _, topic := range topics {
+		if s.topics[topic] {
+			continue
+		}
+		s.topics[topic] = true
+		msg, ok := h.state[stateKey{topic, s.lang}]
+		if !ok {
+			continue
+		}
+		select {
+		case s.ch <- msg:
+		default:
+			delete(h.subscribers, s)
+			close(s.ch)
+			return
+		}
+	}
+}
+
+// Unfollow removes topics from a subscriber; their changes stop, though states already on
+// C are still delivered
+func (h *Hub) Unfollow(s *Subscriber, topics []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, topic := range topics {
+		delete(s.topics, topic)
+	}
+}
+
+// Unsubscribe stops sending to a subscriber, e.g. once its client went away
+func (h *Hub) Unsubscribe(s *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscribers[s] {
+		delete(h.subscribers, s)
+		close(s.ch)
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopped = true
+	for s := range h.subscribers {
+		delete(h.subscribers, s)
+		close(s.ch)
+	}
+}
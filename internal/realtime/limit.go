@@ -0,0 +1,41 @@
+package realtime
+
+import "sync"
+
+// ConnectionLimit caps the connections each token holds open at once, so that one client
+// cannot tie up the server with idle streams
+type ConnectionLimit struct {
+	max int
+
+	mu   sync.Mutex
+	open map[int64]int
+}
+
+func NewConnectionLimit(max int) *ConnectionLimit {
+	return &ConnectionLimit{max: max, open: make(map[int64]int)}
+}
+
+// Max returns how many connections a token may hold open
+func (l *ConnectionLimit) Max() int {
+	return l.max
+}
+
+// Acquire counts a connection of the token, returning false when it has the maximum open
+func (l *ConnectionLimit) Acquire(tokenID int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.open[tokenID] >= l.max {
+		return false
+	}
+	l.open[tokenID]++
+	return true
+}
+
+// Release uncounts a connection once it closed
+func (l *ConnectionLimit) Release(tokenID int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.open[tokenID]--; l.open[tokenID] <= 0 {
+		delete(l.open, tokenID)
+	}
+}
@@ -0,0 +1,462 @@
+// Schedule lookups served on GRPC_PORT. Calls need a token with the
+// "schedule" feature in the "authorization: Bearer <token>" metadata.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: schedule.proto
+
+package schedulepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DateScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // DDMMYYYY, today when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DateScheduleRequest) Reset() {
+	*x = DateScheduleRequest{}
+	mi := &file_schedule_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DateScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DateScheduleRequest) ProtoMessage() {}
+
+func (x *DateScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schedule_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DateScheduleRequest.ProtoReflect.Descriptor instead.
+func (*DateScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_schedule_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DateScheduleRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type Food struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Allergens     []string               `protobuf:"bytes,3,rep,name=allergens,proto3" json:"allergens,omitempty"` // e.g. "milk", "gluten"
+	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`           // "vegan", "vegetarian", "gluten-free" or "fasting"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Food) Reset() {
+	*x = Food{}
+	mi := &file_schedule_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Food) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Food) ProtoMessage() {}
+
+func (x *Food) ProtoReflect() protoreflect.Message {
+	mi := &file_schedule_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Food.ProtoReflect.Descriptor instead.
+func (*Food) Descriptor() ([]byte, []int) {
+	return file_schedule_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Food) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Food) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Food) GetAllergens() []string {
+	if x != nil {
+		return x.Allergens
+	}
+	return nil
+}
+
+func (x *Food) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type DateSchedule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lunch         []*Food                `protobuf:"bytes,1,rep,name=lunch,proto3" json:"lunch,omitempty"`
+	Dinner        []*Food                `protobuf:"bytes,2,rep,name=dinner,proto3" json:"dinner,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DateSchedule) Reset() {
+	*x = DateSchedule{}
+	mi := &file_schedule_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DateSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DateSchedule) ProtoMessage() {}
+
+func (x *DateSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_schedule_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DateSchedule.ProtoReflect.Descriptor instead.
+func (*DateSchedule) Descriptor() ([]byte, []int) {
+	return file_schedule_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DateSchedule) GetLunch() []*Food {
+	if x != nil {
+		return x.Lunch
+	}
+	return nil
+}
+
+func (x *DateSchedule) GetDinner() []*Food {
+	if x != nil {
+		return x.Dinner
+	}
+	return nil
+}
+
+type UnseenAnnouncementsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnseenAnnouncementsRequest) Reset() {
+	*x = UnseenAnnouncementsRequest{}
+	mi := &file_schedule_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnseenAnnouncementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnseenAnnouncementsRequest) ProtoMessage() {}
+
+func (x *UnseenAnnouncementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schedule_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnseenAnnouncementsRequest.ProtoReflect.Descriptor instead.
+func (*UnseenAnnouncementsRequest) Descriptor() ([]byte, []int) {
+	return file_schedule_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UnseenAnnouncementsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type Announcement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	StartingDate  string                 `protobuf:"bytes,4,opt,name=starting_date,json=startingDate,proto3" json:"starting_date,omitempty"`
+	EndingDate    string                 `protobuf:"bytes,5,opt,name=ending_date,json=endingDate,proto3" json:"ending_date,omitempty"`
+	IsCurrent     bool                   `protobuf:"varint,6,opt,name=is_current,json=isCurrent,proto3" json:"is_current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Announcement) Reset() {
+	*x = Announcement{}
+	mi := &file_schedule_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Announcement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Announcement) ProtoMessage() {}
+
+func (x *Announcement) ProtoReflect() protoreflect.Message {
+	mi := &file_schedule_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Announcement.ProtoReflect.Descriptor instead.
+func (*Announcement) Descriptor() ([]byte, []int) {
+	return file_schedule_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Announcement) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Announcement) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Announcement) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Announcement) GetStartingDate() string {
+	if x != nil {
+		return x.StartingDate
+	}
+	return ""
+}
+
+func (x *Announcement) GetEndingDate() string {
+	if x != nil {
+		return x.EndingDate
+	}
+	return ""
+}
+
+func (x *Announcement) GetIsCurrent() bool {
+	if x != nil {
+		return x.IsCurrent
+	}
+	return false
+}
+
+type UnseenAnnouncements struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Announcements []*Announcement        `protobuf:"bytes,2,rep,name=announcements,proto3" json:"announcements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnseenAnnouncements) Reset() {
+	*x = UnseenAnnouncements{}
+	mi := &file_schedule_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnseenAnnouncements) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnseenAnnouncements) ProtoMessage() {}
+
+func (x *UnseenAnnouncements) ProtoReflect() protoreflect.Message {
+	mi := &file_schedule_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnseenAnnouncements.ProtoReflect.Descriptor instead.
+func (*UnseenAnnouncements) Descriptor() ([]byte, []int) {
+	return file_schedule_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UnseenAnnouncements) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *UnseenAnnouncements) GetAnnouncements() []*Announcement {
+	if x != nil {
+		return x.Announcements
+	}
+	return nil
+}
+
+var File_schedule_proto protoreflect.FileDescriptor
+
+const file_schedule_proto_rawDesc = "" +
+	"\n" +
+	"\x0eschedule.proto\x12\x12osduth.schedule.v0\")\n" +
+	"\x13DateScheduleRequest\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\"\\\n" +
+	"\x04Food\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tallergens\x18\x03 \x03(\tR\tallergens\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\"p\n" +
+	"\fDateSchedule\x12.\n" +
+	"\x05lunch\x18\x01 \x03(\v2\x18.osduth.schedule.v0.FoodR\x05lunch\x120\n" +
+	"\x06dinner\x18\x02 \x03(\v2\x18.osduth.schedule.v0.FoodR\x06dinner\"9\n" +
+	"\x1aUnseenAnnouncementsRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\"\xb1\x01\n" +
+	"\fAnnouncement\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12#\n" +
+	"\rstarting_date\x18\x04 \x01(\tR\fstartingDate\x12\x1f\n" +
+	"\vending_date\x18\x05 \x01(\tR\n" +
+	"endingDate\x12\x1d\n" +
+	"\n" +
+	"is_current\x18\x06 \x01(\bR\tisCurrent\"s\n" +
+	"\x13UnseenAnnouncements\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12F\n" +
+	"\rannouncements\x18\x02 \x03(\v2 .osduth.schedule.v0.AnnouncementR\rannouncements2\xdb\x01\n" +
+	"\bSchedule\x12\\\n" +
+	"\x0fGetDateSchedule\x12'.osduth.schedule.v0.DateScheduleRequest\x1a .osduth.schedule.v0.DateSchedule\x12q\n" +
+	"\x16GetUnseenAnnouncements\x12..osduth.schedule.v0.UnseenAnnouncementsRequest\x1a'.osduth.schedule.v0.UnseenAnnouncementsB\x1dZ\x1bAPI/internal/rpc/schedulepbb\x06proto3"
+
+var (
+	file_schedule_proto_rawDescOnce sync.Once
+	file_schedule_proto_rawDescData []byte
+)
+
+func file_schedule_proto_rawDescGZIP() []byte {
+	file_schedule_proto_rawDescOnce.Do(func() {
+		file_schedule_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_schedule_proto_rawDesc), len(file_schedule_proto_rawDesc)))
+	})
+	return file_schedule_proto_rawDescData
+}
+
+var file_schedule_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_schedule_proto_goTypes = []any{
+	(*DateScheduleRequest)(nil),        // 0: osduth.schedule.v0.DateScheduleRequest
+	(*Food)(nil),                       // 1: osduth.schedule.v0.Food
+	(*DateSchedule)(nil),               // 2: osduth.schedule.v0.DateSchedule
+	(*UnseenAnnouncementsRequest)(nil), // 3: osduth.schedule.v0.UnseenAnnouncementsRequest
+	(*Announcement)(nil),               // 4: osduth.schedule.v0.Announcement
+	(*UnseenAnnouncements)(nil),        // 5: osduth.schedule.v0.UnseenAnnouncements
+}
+var file_schedule_proto_depIdxs = []int32{
+	1, // 0: osduth.schedule.v0.DateSchedule.lunch:type_name -> osduth.schedule.v0.Food
+	1, // 1: osduth.schedule.v0.DateSchedule.dinner:type_name -> osduth.schedule.v0.Food
+	4, // 2: osduth.schedule.v0.UnseenAnnouncements.announcements:type_name -> osduth.schedule.v0.Announcement
+	0, // 3: osduth.schedule.v0.Schedule.GetDateSchedule:input_type -> osduth.schedule.v0.DateScheduleRequest
+	3, // 4: osduth.schedule.v0.Schedule.GetUnseenAnnouncements:input_type -> osduth.schedule.v0.UnseenAnnouncementsRequest
+	2, // 5: osduth.schedule.v0.Schedule.GetDateSchedule:output_type -> osduth.schedule.v0.DateSchedule
+	5, // 6: osduth.schedule.v0.Schedule.GetUnseenAnnouncements:output_type -> osduth.schedule.v0.UnseenAnnouncements
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_schedule_proto_init() }
+func file_schedule_proto_init() {
+	if File_schedule_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_schedule_proto_rawDesc), len(file_schedule_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_schedule_proto_goTypes,
+		DependencyIndexes: file_schedule_proto_depIdxs,
+		MessageInfos:      file_schedule_proto_msgTypes,
+	}.Build()
+	File_schedule_proto = out.File
+	file_schedule_proto_goTypes = nil
+	file_schedule_proto_depIdxs = nil
+}
@@ -0,0 +1,166 @@
+// Schedule lookups served on GRPC_PORT. Calls need a token with the
+// "schedule" feature in the "authorization: Bearer <token>" metadata.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: schedule.proto
+
+package schedulepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Schedule_GetDateSchedule_FullMethodName        = "/osduth.schedule.v0.Schedule/GetDateSchedule"
+	Schedule_GetUnseenAnnouncements_FullMethodName = "/osduth.schedule.v0.Schedule/GetUnseenAnnouncements"
+)
+
+// ScheduleClient is the client API for Schedule service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScheduleClient interface {
+	// Menu of a date; NOT_FOUND when no schedule version covers it
+	GetDateSchedule(ctx context.Context, in *DateScheduleRequest, opts ...grpc.CallOption) (*DateSchedule, error)
+	// Today's announcements the token's user (or device) has not seen
+	GetUnseenAnnouncements(ctx context.Context, in *UnseenAnnouncementsRequest, opts ...grpc.CallOption) (*UnseenAnnouncements, error)
+}
+
+type scheduleClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScheduleClient(cc grpc.ClientConnInterface) ScheduleClient {
+	return &scheduleClient{cc}
+}
+
+func (c *scheduleClient) GetDateSchedule(ctx context.Context, in *DateScheduleRequest, opts ...grpc.CallOption) (*DateSchedule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DateSchedule)
+	err := c.cc.Invoke(ctx, Schedule_GetDateSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleClient) GetUnseenAnnouncements(ctx context.Context, in *UnseenAnnouncementsRequest, opts ...grpc.CallOption) (*UnseenAnnouncements, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnseenAnnouncements)
+	err := c.cc.Invoke(ctx, Schedule_GetUnseenAnnouncements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScheduleServer is the server API for Schedule service.
+// All implementations must embed UnimplementedScheduleServer
+// for forward compatibility.
+type ScheduleServer interface {
+	// Menu of a date; NOT_FOUND when no schedule version covers it
+	GetDateSchedule(context.Context, *DateScheduleRequest) (*DateSchedule, error)
+	// Today's announcements the token's user (or device) has not seen
+	GetUnseenAnnouncements(context.Context, *UnseenAnnouncementsRequest) (*UnseenAnnouncements, error)
+	mustEmbedUnimplementedScheduleServer()
+}
+
+// UnimplementedScheduleServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedScheduleServer struct{}
+
+func (UnimplementedScheduleServer) GetDateSchedule(context.Context, *DateScheduleRequest) (*DateSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDateSchedule not implemented")
+}
+func (UnimplementedScheduleServer) GetUnseenAnnouncements(context.Context, *UnseenAnnouncementsRequest) (*UnseenAnnouncements, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUnseenAnnouncements not implemented")
+}
+func (UnimplementedScheduleServer) mustEmbedUnimplementedScheduleServer() {}
+func (UnimplementedScheduleServer) testEmbeddedByValue()                  {}
+
+// UnsafeScheduleServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScheduleServer will
+// result in compilation errors.
+type UnsafeScheduleServer interface {
+	mustEmbedUnimplementedScheduleServer()
+}
+
+func RegisterScheduleServer(s grpc.ServiceRegistrar, srv ScheduleServer) {
+	// If the following call pancis, it indicates UnimplementedScheduleServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Schedule_ServiceDesc, srv)
+}
+
+func _Schedule_GetDateSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DateScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServer).GetDateSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Schedule_GetDateSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServer).GetDateSchedule(ctx, req.(*DateScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Schedule_GetUnseenAnnouncements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnseenAnnouncementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServer).GetUnseenAnnouncements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Schedule_GetUnseenAnnouncements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServer).GetUnseenAnnouncements(ctx, req.(*UnseenAnnouncementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Schedule_ServiceDesc is the grpc.ServiceDesc for Schedule service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Schedule_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "osduth.schedule.v0.Schedule",
+	HandlerType: (*ScheduleServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDateSchedule",
+			Handler:    _Schedule_GetDateSchedule_Handler,
+		},
+		{
+			MethodName: "GetUnseenAnnouncements",
+			Handler:    _Schedule_GetUnseenAnnouncements_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "schedule.proto",
+}
@@ -0,0 +1,13 @@
+// Package rpc serves the gRPC services declared in api/proto. The authpb and schedulepb
+// stubs are generated from those files; regenerate them after editing one.
+package rpc
+
+//go:generate protoc --proto_path=../../api/proto --go_out=. --go_opt=module=API/internal/rpc --go-grpc_out=. --go-grpc_opt=module=API/internal/rpc auth.proto schedule.proto
+
+import "google.golang.org/grpc"
+
+// NewServer creates a gRPC server for one tenant. It is served through
+// ServeHTTP, so the tenant host router can dispatch calls by :authority.
+func NewServer(interceptor grpc.UnaryServerInterceptor) *grpc.Server {
+	return grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+}
@@ -0,0 +1,253 @@
+// Package slowquery wraps a database driver to log the statements that take longer than
+// a threshold, with the code that ran them, to find the hotspots behind latency spikes.
+package slowquery
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// maxQueryLength caps how much of a statement is logged
+const maxQueryLength = 1000
+
+// Open opens a database through d, logging the statements on it that take longer than
+// threshold, or none when it is 0. name tells the databases apart in the log.
+func Open(d driver.Driver, dsn, name string, threshold time.Duration) *sql.DB {
+	c := &connector{driver: d, dsn: dsn}
+	if threshold > 0 {
+		c.log = &logger{name: name, threshold: threshold}
+	}
+	return sql.OpenDB(c)
+}
+
+// logger reports the slow statements of a database
+type logger struct {
+	name      string
+	threshold time.Duration
+}
+
+// observe logs a statement that took elapsed, if that is over the threshold
+func (l *logger) observe(query string, elapsed time.Duration) {
+	if elapsed < l.threshold {
+		return
+	}
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxQueryLength {
+		query = query[:maxQueryLength] + "..."
+	}
+	log.Printf("Slow query on %s database took %s, from %s: %s", l.name, elapsed.Round(time.Microsecond), caller(), query)
+}
+
+// caller finds the first function up the stack outside database/sql and the wrapper's
+// methods, e.g. the repository method that ran the statement
+func caller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "database/sql.") && !strings.HasPrefix(frame.Function, "API/internal/slowquery.(") {
+			return fmt.Sprintf("%s (%s:%d)", frame.Function, filepath.Base(frame.File), frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// connector opens the wrapped driver's connections for sql.OpenDB
+type connector struct {
+	driver driver.Driver
+	dsn    string
+	log    *logger // nil when slow queries are not logged
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	inner, err := c.driver.Open(c.dsn)
+	if err != nil || c.log == nil {
+		return inner, err
+	}
+	return &conn{inner: inner.(innerConn), log: c.log}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// innerConn is what the wrapped driver's connections must implement, as SQLite's do
+type innerConn interface {
+	driver.Conn
+	driver.ConnPrepareContext
+	driver.ConnBeginTx
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+}
+
+// conn times the statements run on a connection
+type conn struct {
+	inner innerConn
+	log   *logger
+}
+
+// Unwrap returns the driver's own connection, for code that needs it through Conn.Raw,
+// e.g. SQLite's online backup
+func (c *conn) Unwrap() driver.Conn {
+	return c.inner
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	inner, err := c.inner.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{inner: inner, query: query, log: c.log}, nil
+}
+
+func (c *conn) Close() error {
+	return c.inner.Close()
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	// With immediate transactions, beginning waits for the write lock, which is often
+	// where the time goes
+	start := time.Now()
+	tx, err := c.inner.BeginTx(ctx, opts)
+	c.log.observe("BEGIN", time.Since(start))
+	return tx, err
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := c.inner.ExecContext(ctx, query, args)
+	c.log.observe(query, time.Since(start))
+	return result, err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	inner, err := c.inner.QueryContext(ctx, query, args)
+	if err != nil {
+		c.log.observe(query, time.Since(start))
+		return nil, err
+	}
+	return &rows{inner: inner, query: query, log: c.log, elapsed: time.Since(start)}, nil
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	return c.inner.Ping(ctx)
+}
+
+// stmt times the runs of a prepared statement
+type stmt struct {
+	inner driver.Stmt
+	query string
+	log   *logger
+}
+
+func (s *stmt) Close() error {
+	return s.inner.Close()
+}
+
+func (s *stmt) NumInput() int {
+	return s.inner.NumInput()
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	start := time.Now()
+	result, err := s.inner.Exec(args)
+	s.log.observe(s.query, time.Since(start))
+	return result, err
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	start := time.Now()
+	inner, err := s.inner.Query(args)
+	if err != nil {
+		s.log.observe(s.query, time.Since(start))
+		return nil, err
+	}
+	return &rows{inner: inner, query: s.query, log: s.log, elapsed: time.Since(start)}, nil
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := s.inner.(driver.StmtExecContext).ExecContext(ctx, args)
+	s.log.observe(s.query, time.Since(start))
+	return result, err
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	inner, err := s.inner.(driver.StmtQueryContext).QueryContext(ctx, args)
+	if err != nil {
+		s.log.observe(s.query, time.Since(start))
+		return nil, err
+	}
+	return &rows{inner: inner, query: s.query, log: s.log, elapsed: time.Since(start)}, nil
+}
+
+// rows adds up the time spent stepping through a query's results, since SQLite runs
+// queries as their rows are read, and logs the query once they are closed. The time the
+// caller spends between rows is left out.
+type rows struct {
+	inner   driver.Rows
+	query   string
+	log     *logger
+	elapsed time.Duration
+}
+
+func (r *rows) Columns() []string {
+	return r.inner.Columns()
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	start := time.Now()
+	err := r.inner.Next(dest)
+	r.elapsed += time.Since(start)
+	return err
+}
+
+func (r *rows) Close() error {
+	err := r.inner.Close()
+	r.log.observe(r.query, r.elapsed)
+	return err
+}
+
+// The column types of the driver's rows, for sql.Rows.ColumnTypes
+
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	if t, ok := r.inner.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return t.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if t, ok := r.inner.(driver.RowsColumnTypeNullable); ok {
+		return t.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *rows) ColumnTypeScanType(index int) reflect.Type {
+	if t, ok := r.inner.(driver.RowsColumnTypeScanType); ok {
+		return t.ColumnTypeScanType(index)
+	}
+	return reflect.TypeFor[any]()
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrTooLarge is returned for uploads over a Policy's MaxBytes
+	ErrTooLarge = errors.New("file is too large")
+
+	// ErrUnsupportedType is returned for uploads whose content is none of a Policy's Types
+	ErrUnsupportedType = errors.New("file type is not accepted")
+)
+
+// ImageTypes are the image formats uploads may be in
+var ImageTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// Policy is what an upload may be: its size, and its type as sniffed from the content,
+// never as claimed by the client
+type Policy struct {
+	MaxBytes int64
+	Types    []string
+}
+
+// Read reads an upload that follows the policy, returning the content and its type. It
+// reads at most one byte over MaxBytes, and fails with an error wrapping ErrTooLarge or
+// ErrUnsupportedType.
+func (p Policy) Read(upload io.Reader) ([]byte, string, error) {
+	data, err := io.ReadAll(io.LimitReader(upload, p.MaxBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > p.MaxBytes {
+		return nil, "", fmt.Errorf("%w: the limit is %d bytes", ErrTooLarge, p.MaxBytes)
+	}
+	contentType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	if !slices.Contains(p.Types, contentType) {
+		return nil, "", fmt.Errorf("%w: expected %s", ErrUnsupportedType, strings.Join(p.Types, ", "))
+	}
+	return data, contentType, nil
+}
@@ -0,0 +1,112 @@
+// Package storage keeps files (database snapshots, uploaded images) on local disk or in
+// an S3-compatible bucket behind one interface
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Object is a stored file
+type Object struct {
+	Key  string
+	Size int64
+}
+
+// Storage is where files are kept. Keys use "/" as separator.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.ReadSeeker, size int64) error
+	// Get opens a stored object; a missing key fails with fs.ErrNotExist
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	List(ctx context.Context, prefix string) ([]Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// URLSigner is a Storage clients can download from directly, with URLs that expire
+type URLSigner interface {
+	SignedURL(key string, expires time.Duration) (string, error)
+}
+
+// DirStorage keeps files in a local directory, e.g. a mounted volume
+type DirStorage struct {
+	dir string
+}
+
+// NewDirStorage creates a storage writing under dir
+func NewDirStorage(dir string) *DirStorage {
+	return &DirStorage{dir: dir}
+}
+
+// Put writes the file to a temporary file first, so a crash never leaves a
+// truncated file under its final name
+func (s *DirStorage) Put(ctx context.Context, key string, body io.ReadSeeker, size int64) error {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".partial-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens a stored file
+func (s *DirStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.dir, filepath.FromSlash(key)))
+}
+
+// List returns the files whose key starts with prefix
+func (s *DirStorage) List(ctx context.Context, prefix string) ([]Object, error) {
+	dir := filepath.Join(s.dir, filepath.FromSlash(prefix))
+	if !strings.HasSuffix(prefix, "/") {
+		dir = filepath.Dir(dir)
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []Object
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		key, err := filepath.Rel(s.dir, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		key = filepath.ToSlash(key)
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, Object{Key: key, Size: info.Size()})
+	}
+	return objects, nil
+}
+
+// Delete removes a file
+func (s *DirStorage) Delete(ctx context.Context, key string) error {
+	return os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+}
@@ -0,0 +1,65 @@
+package tenant
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"API/internal/apierror"
+	"API/internal/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HostRouter dispatches requests to the tenant that owns the request host
+type HostRouter struct {
+	handlers map[string]http.Handler
+	fallback http.Handler
+}
+
+// NewHostRouter creates an empty host router
+func NewHostRouter() *HostRouter {
+	return &HostRouter{handlers: make(map[string]http.Handler)}
+}
+
+// Add registers a tenant's handler for all of its hosts. A tenant without hosts
+// becomes the fallback, which is how single-tenant deployments are served.
+func (r *HostRouter) Add(t Tenant, handler http.Handler) {
+	if len(t.Hosts) == 0 {
+		r.fallback = handler
+		return
+	}
+	for _, host := range t.Hosts {
+		r.handlers[host] = handler
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (r *HostRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if handler, ok := r.handlers[normalizeHost(req.Host)]; ok {
+		handler.ServeHTTP(w, req)
+		return
+	}
+	if r.fallback != nil {
+		r.fallback.ServeHTTP(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(common.CreateErrorResponse(apierror.New(apierror.UnknownTenant, "unknown tenant host")))
+}
+
+// RegisterRoutes registers the public tenant information routes
+func RegisterRoutes(rg *gin.RouterGroup, t Tenant) {
+	rg.GET("/tenant", func(c *gin.Context) {
+		common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+			"id": t.ID,
+			"branding": gin.H{
+				"name":       t.Branding.Name,
+				"university": t.Branding.University,
+				"website":    t.Branding.Website,
+				"logoUrl":    "/favicon.ico",
+			},
+		}))
+	})
+}
@@ -0,0 +1,333 @@
+package tenant
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"API/internal/env"
+)
+
+const (
+	// DefaultTenantID identifies the tenant built from plain environment variables
+	DefaultTenantID = "duth"
+
+	// DefaultTokenPrefix is the token prefix used when a tenant does not set one
+	DefaultTokenPrefix = "osduth_"
+
+	// DefaultDatabaseDir is where tenant databases live unless configured otherwise
+	DefaultDatabaseDir = "./internal/databases"
+)
+
+// Tenant holds everything that differs between university deployments
+type Tenant struct {
+	ID              string   `json:"id"`
+	Hosts           []string `json:"hosts"`
+	Branding        Branding `json:"branding"`
+	TokenPrefix     string   `json:"tokenPrefix"`
+	AcademicDomains []string `json:"academicDomains"`
+	Datasets        Datasets `json:"datasets"`
+	OAuth           OAuth    `json:"oauth"`
+	Webhooks        Webhooks `json:"webhooks"`
+	Push            Push     `json:"push"`
+	EClass          EClass   `json:"eclass"`
+}
+
+// Branding describes how a tenant presents itself to clients
+type Branding struct {
+	Name       string `json:"name"`
+	University string `json:"university"`
+	Website    string `json:"website"`
+	LogoPath   string `json:"logoPath"`
+}
+
+// Datasets points a tenant at its own database files
+type Datasets struct {
+	AuthDB     string `json:"authDb"`
+	ScheduleDB string `json:"scheduleDb"`
+	// ScheduleReplicaDB is an optional read-only replica of ScheduleDB (e.g. a
+	// LiteFS mount) that serves the open-data reads
+	ScheduleReplicaDB string `json:"scheduleReplicaDb"`
+	CoursesDB         string `json:"coursesDb"`
+	MapsDB            string `json:"mapsDb"`
+	DirectoryDB       string `json:"directoryDb"`
+	EventsDB          string `json:"eventsDb"`
+	LibraryDB         string `json:"libraryDb"`
+	NewsDB            string `json:"newsDb"`
+	SportsDB          string `json:"sportsDb"`
+	PostingsDB        string `json:"postingsDb"`
+	JobsDB            string `json:"jobsDb"`
+	PrintingDB        string `json:"printingDb"`
+	WebhooksDB        string `json:"webhooksDb"`
+	EClassDB          string `json:"eclassDb"`
+}
+
+// OAuth holds a tenant's OAuth application credentials
+type OAuth struct {
+	CallbackBaseURL string      `json:"callbackBaseUrl"`
+	Google          Credentials `json:"google"`
+	GitHub          Credentials `json:"github"`
+}
+
+// Webhooks lists the integration endpoints notified about a tenant's open data
+type Webhooks struct {
+	// SchedulePublished receives a "schedule.published" POST for every new schedule version
+	SchedulePublished []string `json:"schedulePublished"`
+	// AnnouncementPublished receives an "announcement.published" POST for every new announcement
+	AnnouncementPublished []string `json:"announcementPublished"`
+	// Secret signs webhook bodies (X-Webhook-Signature); empty disables signing
+	Secret string `json:"secret"`
+	// SecretRef names the secret to read Secret from instead, see Credentials
+	SecretRef string `json:"secretRef"`
+}
+
+// Push configures notifications sent to the tenant's mobile apps through
+// Firebase Cloud Messaging topics
+type Push struct {
+	// FCMCredentialsFile is a Firebase service account JSON file; empty disables push
+	FCMCredentialsFile string `json:"fcmCredentialsFile"`
+	// Topic is the prefix of the per-language topics, e.g. "duth-menu-el"
+	Topic string `json:"topic"`
+}
+
+// EClass points the eClass proxy at the university's Moodle-based eClass site
+type EClass struct {
+	// URL is the site's address, e.g. "https://eclass.duth.gr"; empty disables the proxy
+	URL string `json:"url"`
+	// Service is the Moodle web service tokens are requested for, by default the mobile app's
+	Service string `json:"service"`
+	// TokenKey encrypts the linked accounts' tokens in eclassDb; the proxy stays disabled
+	// without it. A tenants file names it with TokenKeyRef, see Credentials.
+	TokenKey    string `json:"-"`
+	TokenKeyRef string `json:"tokenKeyRef"`
+}
+
+// Credentials holds the client credentials of a single OAuth application
+type Credentials struct {
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+	// ClientSecretRef names the secret to read ClientSecret from with env.GetSecret, e.g.
+	// "UOA_GITHUB_CLIENT_SECRET", so a tenants file can leave it to a _FILE variable or Vault
+	ClientSecretRef string `json:"clientSecretRef"`
+}
+
+// Load returns the configured tenants. When TENANTS_FILE is set the tenants are
+// read from that JSON file, otherwise a single tenant is built from the environment.
+func Load() ([]Tenant, error) {
+	path := env.GetEnv(env.EnvTenantsFile, "")
+	if path == "" {
+		return []Tenant{Default()}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file: %w", err)
+	}
+
+	var tenants []Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("failed to parse tenants file: %w", err)
+	}
+
+	for i := range tenants {
+		tenants[i].applyDefaults()
+		if err := tenants[i].resolveSecrets(); err != nil {
+			return nil, err
+		}
+	}
+	if err := Validate(tenants); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// Default builds the single-tenant configuration from environment variables
+func Default() Tenant {
+	t := Tenant{
+		ID: DefaultTenantID,
+		Branding: Branding{
+			Name:       "OpenSourceDUTH",
+			University: "Democritus University of Thrace",
+			Website:    "https://opensource.cs.duth.gr",
+			LogoPath:   "./internal/assets/logo.svg",
+		},
+		Datasets: Datasets{
+			AuthDB:            filepath.Join(DefaultDatabaseDir, "auth.db"),
+			ScheduleDB:        filepath.Join(DefaultDatabaseDir, "schedule.db"),
+			ScheduleReplicaDB: env.GetEnv(env.EnvScheduleReplicaDB, ""),
+			CoursesDB:         filepath.Join(DefaultDatabaseDir, "courses.db"),
+			MapsDB:            filepath.Join(DefaultDatabaseDir, "maps.db"),
+			DirectoryDB:       filepath.Join(DefaultDatabaseDir, "directory.db"),
+			EventsDB:          filepath.Join(DefaultDatabaseDir, "events.db"),
+			LibraryDB:         filepath.Join(DefaultDatabaseDir, "library.db"),
+			NewsDB:            filepath.Join(DefaultDatabaseDir, "news.db"),
+			SportsDB:          filepath.Join(DefaultDatabaseDir, "sports.db"),
+			PostingsDB:        filepath.Join(DefaultDatabaseDir, "postings.db"),
+			JobsDB:            filepath.Join(DefaultDatabaseDir, "jobs.db"),
+			PrintingDB:        filepath.Join(DefaultDatabaseDir, "printing.db"),
+			WebhooksDB:        filepath.Join(DefaultDatabaseDir, "webhooks.db"),
+			EClassDB:          filepath.Join(DefaultDatabaseDir, "eclass.db"),
+		},
+		OAuth: OAuth{
+			CallbackBaseURL: env.GetEnv(env.EnvAuthCallbackBaseURL, fmt.Sprintf("http://localhost:%d", env.GetInt(env.EnvPort, env.DefaultPort))),
+			Google: Credentials{
+				ClientID:     env.GetEnv(env.EnvGoogleClientID, ""),
+				ClientSecret: env.GetSecret(env.EnvGoogleClientSecret, ""),
+			},
+			GitHub: Credentials{
+				ClientID:     env.GetEnv(env.EnvGitHubClientID, ""),
+				ClientSecret: env.GetSecret(env.EnvGitHubClientSecret, ""),
+			},
+		},
+		Webhooks: Webhooks{
+			SchedulePublished:     env.GetList(env.EnvScheduleWebhookURLs, nil),
+			AnnouncementPublished: env.GetList(env.EnvAnnouncementWebhookURLs, nil),
+			Secret:                env.GetSecret(env.EnvWebhookSecret, ""),
+		},
+		Push: Push{
+			FCMCredentialsFile: env.GetEnv(env.EnvPushCredentialsFile, ""),
+			Topic:              env.GetEnv(env.EnvPushTopic, ""),
+		},
+		EClass: EClass{
+			URL:      env.GetEnv(env.EnvEClassURL, ""),
+			Service:  env.GetEnv(env.EnvEClassService, ""),
+			TokenKey: env.GetSecret(env.EnvEClassTokenKey, ""),
+		},
+	}
+	t.applyDefaults()
+	return t
+}
+
+func (t *Tenant) applyDefaults() {
+	t.ID = strings.TrimSpace(t.ID)
+	if t.TokenPrefix == "" {
+		t.TokenPrefix = DefaultTokenPrefix
+	}
+	if t.Branding.Name == "" {
+		t.Branding.Name = t.ID
+	}
+	if t.Datasets.AuthDB == "" {
+		t.Datasets.AuthDB = filepath.Join(DefaultDatabaseDir, t.ID, "auth.db")
+	}
+	if t.Datasets.ScheduleDB == "" {
+		t.Datasets.ScheduleDB = filepath.Join(DefaultDatabaseDir, t.ID, "schedule.db")
+	}
+	if t.Datasets.CoursesDB == "" {
+		t.Datasets.CoursesDB = filepath.Join(DefaultDatabaseDir, t.ID, "courses.db")
+	}
+	if t.Datasets.MapsDB == "" {
+		t.Datasets.MapsDB = filepath.Join(DefaultDatabaseDir, t.ID, "maps.db")
+	}
+	if t.Datasets.DirectoryDB == "" {
+		t.Datasets.DirectoryDB = filepath.Join(DefaultDatabaseDir, t.ID, "directory.db")
+	}
+	if t.Datasets.EventsDB == "" {
+		t.Datasets.EventsDB = filepath.Join(DefaultDatabaseDir, t.ID, "events.db")
+	}
+	if t.Datasets.LibraryDB == "" {
+		t.Datasets.LibraryDB = filepath.Join(DefaultDatabaseDir, t.ID, "library.db")
+	}
+	if t.Datasets.NewsDB == "" {
+		t.Datasets.NewsDB = filepath.Join(DefaultDatabaseDir, t.ID, "news.db")
+	}
+	if t.Datasets.SportsDB == "" {
+		t.Datasets.SportsDB = filepath.Join(DefaultDatabaseDir, t.ID, "sports.db")
+	}
+	if t.Datasets.PostingsDB == "" {
+		t.Datasets.PostingsDB = filepath.Join(DefaultDatabaseDir, t.ID, "postings.db")
+	}
+	if t.Datasets.JobsDB == "" {
+		t.Datasets.JobsDB = filepath.Join(DefaultDatabaseDir, t.ID, "jobs.db")
+	}
+	if t.Datasets.PrintingDB == "" {
+		t.Datasets.PrintingDB = filepath.Join(DefaultDatabaseDir, t.ID, "printing.db")
+	}
+	if t.Datasets.WebhooksDB == "" {
+		t.Datasets.WebhooksDB = filepath.Join(DefaultDatabaseDir, t.ID, "webhooks.db")
+	}
+	if t.Datasets.EClassDB == "" {
+		t.Datasets.EClassDB = filepath.Join(DefaultDatabaseDir, t.ID, "eclass.db")
+	}
+	if t.Push.Topic == "" {
+		t.Push.Topic = t.ID + "-menu"
+	}
+	for i, host := range t.Hosts {
+		t.Hosts[i] = normalizeHost(host)
+	}
+	for i, domain := range t.AcademicDomains {
+		t.AcademicDomains[i] = strings.ToLower(strings.TrimSpace(domain))
+	}
+}
+
+// resolveSecrets reads the secrets a tenant names by reference
+func (t *Tenant) resolveSecrets() error {
+	secrets := []struct {
+		field string
+		ref   string
+		value *string
+	}{
+		{"oauth.google.clientSecretRef", t.OAuth.Google.ClientSecretRef, &t.OAuth.Google.ClientSecret},
+		{"oauth.github.clientSecretRef", t.OAuth.GitHub.ClientSecretRef, &t.OAuth.GitHub.ClientSecret},
+		{"webhooks.secretRef", t.Webhooks.SecretRef, &t.Webhooks.Secret},
+		{"eclass.tokenKeyRef", t.EClass.TokenKeyRef, &t.EClass.TokenKey},
+	}
+	for _, secret := range secrets {
+		if secret.ref == "" {
+			continue
+		}
+		if *secret.value != "" {
+			return fmt.Errorf("tenant %s sets both %s and the secret itself", t.ID, secret.field)
+		}
+		if *secret.value = env.GetSecret(secret.ref, ""); *secret.value == "" {
+			return fmt.Errorf("tenant %s: secret %s named by %s is not set", t.ID, secret.ref, secret.field)
+		}
+	}
+	return nil
+}
+
+// Validate checks that a set of tenants can be served from one process
+func Validate(tenants []Tenant) error {
+	if len(tenants) == 0 {
+		return fmt.Errorf("at least one tenant is required")
+	}
+
+	ids := make(map[string]bool)
+	hosts := make(map[string]string)
+	prefixes := make(map[string]string)
+	for _, t := range tenants {
+		if t.ID == "" {
+			return fmt.Errorf("tenant ID is required")
+		}
+		if ids[t.ID] {
+			return fmt.Errorf("duplicate tenant ID: %s", t.ID)
+		}
+		ids[t.ID] = true
+
+		if len(tenants) > 1 && len(t.Hosts) == 0 {
+			return fmt.Errorf("tenant %s must declare at least one host", t.ID)
+		}
+		for _, host := range t.Hosts {
+			if other, exists := hosts[host]; exists {
+				return fmt.Errorf("host %s is claimed by both %s and %s", host, other, t.ID)
+			}
+			hosts[host] = t.ID
+		}
+
+		if other, exists := prefixes[t.TokenPrefix]; exists {
+			return fmt.Errorf("token prefix %s is shared by %s and %s", t.TokenPrefix, other, t.ID)
+		}
+		prefixes[t.TokenPrefix] = t.ID
+	}
+	return nil
+}
+
+// normalizeHost lowercases a host and strips any port
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.LastIndex(host, ":"); i != -1 && !strings.HasSuffix(host, "]") {
+		host = host[:i]
+	}
+	return strings.Trim(host, "[]")
+}
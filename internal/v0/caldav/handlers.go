@@ -0,0 +1,624 @@
+package caldav
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/calendar"
+	"API/internal/negotiate"
+	"API/internal/v0/common"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// PastDays and AheadDays bound the entries served around today; a calendar-query
+	// asking for more is narrowed to them
+	PastDays  = 30
+	AheadDays = 90
+
+	// Realm is named in the Basic challenge calendar apps answer with the token
+	Realm = "OpenSourceDUTH API"
+)
+
+// Tokens tells whether the calling token may see a calendar
+type Tokens interface {
+	TokenHasFeature(ctx context.Context, tokenID int64, featureSlug string) (bool, error)
+}
+
+// Handler serves the calendars the modules publish as a read-only CalDAV account. Its
+// root is both the user's principal and their calendar home, holding one calendar per
+// collection the token has the feature of.
+type Handler struct {
+	tokens      Tokens
+	collections []calendar.Collection
+}
+
+func NewHandler(tokens Tokens) *Handler {
+	return &Handler{tokens: tokens}
+}
+
+// Register adds calendars to the account, in the order calendar apps list them
+func (h *Handler) Register(collections ...calendar.Collection) {
+	h.collections = append(h.collections, collections...)
+}
+
+// request is a CalDAV request being served, with the entries it loaded so far
+type request struct {
+	c           *gin.Context
+	user        *auth.User
+	base        string // Path of the root, without the trailing slash
+	lang        string
+	collections []calendar.Collection // The calendars the token may see
+	from, to    time.Time
+	entries     map[string][]calendar.Entry
+}
+
+// begin prepares a request of the calling token. It renders an error and returns nil
+// when it cannot be served.
+func (h *Handler) begin(c *gin.Context) *request {
+	user := auth.GetUserFromContext(c)
+	token := auth.GetTokenFromContext(c)
+	if user == nil || token == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return nil
+	}
+	lang, err := negotiate.Language(c, Languages)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return nil
+	}
+
+	var collections []calendar.Collection
+	for _, collection := range h.collections {
+		ok, err := h.tokens.TokenHasFeature(c.Request.Context(), token.ID, collection.Feature)
+		if err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to check the token's features")))
+			return nil
+		}
+		if ok {
+			collections = append(collections, collection)
+		}
+	}
+
+	now := time.Now()
+	return &request{
+		c:           c,
+		user:        user,
+		base:        strings.TrimSuffix(c.FullPath(), "/*path"),
+		lang:        lang,
+		collections: collections,
+		from:        now.AddDate(0, 0, -PastDays),
+		to:          now.AddDate(0, 0, AheadDays),
+		entries:     make(map[string][]calendar.Entry),
+	}
+}
+
+// target is the resource a path names: the root, a calendar or one of its entries
+type target struct {
+	collection *calendar.Collection // nil for the root
+	uid        string               // Empty for the root and calendars
+}
+
+// resolve finds the resource of a path below the root, e.g. "/meals/" or
+// "/meals/meal-20261015-lunch.ics"
+func (r *request) resolve(path string) (target, bool) {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return target{}, true
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) > 2 {
+		return target{}, false
+	}
+	var t target
+	for i := range r.collections {
+		if r.collections[i].Name == parts[0] {
+			t.collection = &r.collections[i]
+		}
+	}
+	if t.collection == nil {
+		return target{}, false
+	}
+	if len(parts) == 2 {
+		uid, ok := strings.CutSuffix(parts[1], ".ics")
+		if !ok || uid == "" {
+			return target{}, false
+		}
+		t.uid = uid
+	}
+	return t, true
+}
+
+// resolveHref finds the resource of an href of a multiget, which clients send either as
+// a path or as a full URL
+func (r *request) resolveHref(href string) (target, bool) {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return target{}, false
+	}
+	path, ok := strings.CutPrefix(u.Path, r.base)
+	if !ok {
+		return target{}, false
+	}
+	return r.resolve(path)
+}
+
+// load returns the entries of a calendar in the request's window, loading them once
+func (r *request) load(collection *calendar.Collection) ([]calendar.Entry, error) {
+	if entries, ok := r.entries[collection.Name]; ok {
+		return entries, nil
+	}
+	loaded, err := collection.Entries(r.c.Request.Context(), r.user.ID, r.lang, r.from, r.to)
+	if err != nil {
+		return nil, err
+	}
+	entries := []calendar.Entry{}
+	for _, e := range loaded {
+		if e.Overlaps(r.from, r.to) {
+			entries = append(entries, e)
+		}
+	}
+	r.entries[collection.Name] = entries
+	return entries, nil
+}
+
+// find returns an entry of a calendar by UID, or false when it is not in the window
+func (r *request) find(collection *calendar.Collection, uid string) (calendar.Entry, bool, error) {
+	entries, err := r.load(collection)
+	if err != nil {
+		return calendar.Entry{}, false, err
+	}
+	for _, e := range entries {
+		if e.UID == uid {
+			return e, true, nil
+		}
+	}
+	return calendar.Entry{}, false, nil
+}
+
+// failed renders the error of a calendar that could not be loaded
+func (r *request) failed(collection *calendar.Collection, err error) {
+	log.Printf("CalDAV: failed to load the %s calendar of user %d: %v", collection.Name, r.user.ID, err)
+	common.JSON(r.c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to load the calendar")))
+}
+
+func notFound(c *gin.Context) {
+	common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "no such calendar or entry")))
+}
+
+// rootHref, collectionHref and entryHref are the paths of the resources
+func (r *request) rootHref() string {
+	return r.base + "/"
+}
+
+func (r *request) collectionHref(collection *calendar.Collection) string {
+	return r.base + "/" + collection.Name + "/"
+}
+
+func (r *request) entryHref(collection *calendar.Collection, e calendar.Entry) string {
+	return r.collectionHref(collection) + e.UID + ".ics"
+}
+
+// rootProps are the properties of the root, which calendar apps discover the account by
+func (r *request) rootProps() map[xml.Name]string {
+	name := r.user.DisplayName
+	if name == "" {
+		name = r.user.Email
+	}
+	home := "<d:href>" + escape(r.rootHref()) + "</d:href>"
+	return map[xml.Name]string{
+		propResourceType:          "<d:collection/><d:principal/>",
+		propDisplayName:           escape(name),
+		propCurrentUserPrincipal:  home,
+		propPrincipalURL:          home,
+		propCalendarHome:          home,
+		propCalendarUserAddresses: "<d:href>mailto:" + escape(r.user.Email) + "</d:href>",
+		propPrivileges:            readOnly,
+	}
+}
+
+// collectionProps are the properties of a calendar. Its ctag changes whenever one of its
+// entries does, so calendar apps only look further then.
+func (r *request) collectionProps(collection *calendar.Collection) (map[xml.Name]string, error) {
+	entries, err := r.load(collection)
+	if err != nil {
+		return nil, err
+	}
+	ctag := sha256.New()
+	for _, e := range entries {
+		ctag.Write([]byte(e.UID + e.ETag()))
+	}
+	return map[xml.Name]string{
+		propResourceType:         "<d:collection/><c:calendar/>",
+		propDisplayName:          escape(collection.Title(r.lang)),
+		propCurrentUserPrincipal: "<d:href>" + escape(r.rootHref()) + "</d:href>",
+		propSupportedComponents:  `<c:comp name="VEVENT"/>`,
+		propSupportedReports: "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>",
+		propCTag:       `"` + hex.EncodeToString(ctag.Sum(nil)[:16]) + `"`,
+		propColor:      escape(collection.Color),
+		propPrivileges: readOnly,
+	}, nil
+}
+
+// entryProps are the properties of an entry; its data is only sent when asked for
+func (r *request) entryProps(e calendar.Entry, withData bool) map[xml.Name]string {
+	props := map[xml.Name]string{
+		propResourceType: "",
+		propETag:         escape(e.ETag()),
+		propContentType:  calendar.MIMEType + "; component=VEVENT",
+	}
+	if withData {
+		props[propCalendarData] = escape(string(r.single(e)))
+	}
+	return props
+}
+
+// single renders an entry as a calendar of its own, the form CalDAV serves entries in
+func (r *request) single(e calendar.Entry) []byte {
+	return calendar.Encode("", []calendar.Entry{e}, r.c.Request.Host, time.Now())
+}
+
+// readOnly is the privilege set of every resource
+const readOnly = "<d:privilege><d:read/></d:privilege>"
+
+// Options advertises the CalDAV support calendar apps look for before signing in
+// OPTIONS /caldav/*path
+func (h *Handler) Options(c *gin.Context) {
+	c.Header("DAV", "1, 3, calendar-access")
+	c.Header("Allow", "OPTIONS, GET, HEAD, PROPFIND, REPORT")
+	c.Status(http.StatusOK)
+}
+
+// Propfind returns the properties of a resource and, at Depth 1, of its children: the
+// calendars of the root, the entries of a calendar
+// PROPFIND /caldav/*path
+func (h *Handler) Propfind(c *gin.Context) {
+	r := h.begin(c)
+	if r == nil {
+		return
+	}
+	t, ok := r.resolve(c.Param("path"))
+	if !ok {
+		notFound(c)
+		return
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "failed to read the request body")))
+		return
+	}
+	// An empty body asks for every property, like allprop
+	var want []xml.Name
+	if len(bytes.TrimSpace(body)) > 0 {
+		var req propfindRequest
+		if err := xml.Unmarshal(body, &req); err != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "the body is not a PROPFIND request")))
+			return
+		}
+		if req.Prop != nil {
+			want = req.Prop.names()
+		}
+	}
+	// Depth infinity is not offered (RFC 4918 §9.1); missing, it is served as 1, which
+	// covers the whole account
+	children := c.GetHeader("Depth") != "0"
+
+	var responses []response
+	switch {
+	case t.collection == nil:
+		responses = append(responses, selectProps(r.rootHref(), r.rootProps(), want))
+		if children {
+			for i := range r.collections {
+				collection := &r.collections[i]
+				props, err := r.collectionProps(collection)
+				if err != nil {
+					r.failed(collection, err)
+					return
+				}
+				responses = append(responses, selectProps(r.collectionHref(collection), props, want))
+			}
+		}
+	case t.uid == "":
+		props, err := r.collectionProps(t.collection)
+		if err != nil {
+			r.failed(t.collection, err)
+			return
+		}
+		responses = append(responses, selectProps(r.collectionHref(t.collection), props, want))
+		if children {
+			for _, e := range r.entries[t.collection.Name] {
+				responses = append(responses, selectProps(r.entryHref(t.collection, e), r.entryProps(e, false), want))
+			}
+		}
+	default:
+		e, found, err := r.find(t.collection, t.uid)
+		if err != nil {
+			r.failed(t.collection, err)
+			return
+		}
+		if !found {
+			notFound(c)
+			return
+		}
+		responses = append(responses, selectProps(r.entryHref(t.collection, e), r.entryProps(e, false), want))
+	}
+	writeMultistatus(c, responses)
+}
+
+// Report answers a calendar-query, returning a calendar's entries in a time range, or a
+// calendar-multiget, returning the entries of the hrefs it lists
+// REPORT /caldav/*path
+func (h *Handler) Report(c *gin.Context) {
+	r := h.begin(c)
+	if r == nil {
+		return
+	}
+	t, ok := r.resolve(c.Param("path"))
+	if !ok || t.uid != "" {
+		notFound(c)
+		return
+	}
+	var req reportRequest
+	if err := xml.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "the body is not a REPORT request")))
+		return
+	}
+	var want []xml.Name
+	if req.Prop != nil {
+		want = req.Prop.names()
+	}
+	withData := want == nil
+	for _, name := range want {
+		withData = withData || name == propCalendarData
+	}
+
+	var responses []response
+	switch req.XMLName {
+	case reportQuery:
+		if t.collection == nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "calendar-query is only supported on calendars")))
+			return
+		}
+		from, to, events := req.timeRange()
+		if !events {
+			break
+		}
+		entries, err := r.load(t.collection)
+		if err != nil {
+			r.failed(t.collection, err)
+			return
+		}
+		for _, e := range entries {
+			if e.Overlaps(from, to) {
+				responses = append(responses, selectProps(r.entryHref(t.collection, e), r.entryProps(e, withData), want))
+			}
+		}
+	case reportMultiget:
+		for _, href := range req.Hrefs {
+			found := false
+			ht, ok := r.resolveHref(href)
+			if ok && ht.uid != "" {
+				e, ok, err := r.find(ht.collection, ht.uid)
+				if err != nil {
+					r.failed(ht.collection, err)
+					return
+				}
+				if ok {
+					responses = append(responses, selectProps(r.entryHref(ht.collection, e), r.entryProps(e, withData), want))
+					found = true
+				}
+			}
+			if !found {
+				responses = append(responses, response{href: href, status: http.StatusNotFound})
+			}
+		}
+	default:
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "only calendar-query and calendar-multiget reports are supported")))
+		return
+	}
+	writeMultistatus(c, responses)
+}
+
+// Get returns a calendar as an iCalendar feed, or one of its entries as a calendar of
+// its own
+// GET /caldav/*path
+func (h *Handler) Get(c *gin.Context) {
+	r := h.begin(c)
+	if r == nil {
+		return
+	}
+	t, ok := r.resolve(c.Param("path"))
+	if !ok || t.collection == nil {
+		notFound(c)
+		return
+	}
+	if t.uid == "" {
+		entries, err := r.load(t.collection)
+		if err != nil {
+			r.failed(t.collection, err)
+			return
+		}
+		c.Data(http.StatusOK, calendar.MIMEType, calendar.Encode(t.collection.Title(r.lang), entries, c.Request.Host, time.Now()))
+		return
+	}
+	e, found, err := r.find(t.collection, t.uid)
+	if err != nil {
+		r.failed(t.collection, err)
+		return
+	}
+	if !found {
+		notFound(c)
+		return
+	}
+	c.Header("ETag", e.ETag())
+	c.Data(http.StatusOK, calendar.MIMEType, r.single(e))
+}
+
+// names lists the properties of a <prop>
+func (p *propList) names() []xml.Name {
+	names := make([]xml.Name, 0, len(p.Props))
+	for _, prop := range p.Props {
+		names = append(names, prop.XMLName)
+	}
+	return names
+}
+
+// timeRange returns the range of the VEVENTs a calendar-query asks for; zero bounds are
+// open. events is false when it asks for other components only.
+func (req *reportRequest) timeRange() (from, to time.Time, events bool) {
+	if req.Filter == nil || req.Filter.Comp.Name != "VCALENDAR" {
+		return time.Time{}, time.Time{}, req.Filter == nil
+	}
+	if len(req.Filter.Comp.Comps) == 0 {
+		return time.Time{}, time.Time{}, true
+	}
+	for _, comp := range req.Filter.Comp.Comps {
+		if comp.Name != "VEVENT" {
+			continue
+		}
+		if comp.TimeRange != nil {
+			from, _ = time.Parse(icalTime, comp.TimeRange.Start)
+			to, _ = time.Parse(icalTime, comp.TimeRange.End)
+		}
+		return from, to, true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// icalTime is the form of the bounds of a time-range
+const icalTime = "20060102T150405Z"
+
+// response is a resource of a multistatus: either its properties, or a status when it
+// could not be found
+type response struct {
+	href    string
+	status  int
+	found   []property
+	missing []xml.Name
+}
+
+// property is a property found, with its value as XML
+type property struct {
+	name  xml.Name
+	value string
+}
+
+// selectProps picks the properties asked for from those of a resource, every one when
+// want is nil
+func selectProps(href string, props map[xml.Name]string, want []xml.Name) response {
+	resp := response{href: href}
+	if want == nil {
+		for name, value := range props {
+			if name != propCalendarData {
+				resp.found = append(resp.found, property{name, value})
+			}
+		}
+		sort.Slice(resp.found, func(i, j int) bool {
+			a, b := resp.found[i].name, resp.found[j].name
+			return a.Space < b.Space || a.Space == b.Space && a.Local < b.Local
+		})
+		return resp
+	}
+	for _, name := range want {
+		if value, ok := props[name]; ok {
+			resp.found = append(resp.found, property{name, value})
+		} else {
+			resp.missing = append(resp.missing, name)
+		}
+	}
+	return resp
+}
+
+// writeMultistatus renders a 207 Multi-Status response (RFC 4918 §13)
+func writeMultistatus(c *gin.Context, responses []response) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString("<d:multistatus")
+	spaces := make([]string, 0, len(prefixes))
+	for space := range prefixes {
+		spaces = append(spaces, space)
+	}
+	sort.Strings(spaces)
+	for _, space := range spaces {
+		b.WriteString(" xmlns:" + prefixes[space] + `="` + escape(space) + `"`)
+	}
+	b.WriteString(">")
+	for _, resp := range responses {
+		b.WriteString("<d:response><d:href>" + escape(resp.href) + "</d:href>")
+		if resp.status != 0 {
+			b.WriteString("<d:status>" + statusLine(resp.status) + "</d:status>")
+		}
+		if len(resp.found) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, prop := range resp.found {
+				writeElement(&b, prop.name, prop.value)
+			}
+			b.WriteString("</d:prop><d:status>" + statusLine(http.StatusOK) + "</d:status></d:propstat>")
+		}
+		if len(resp.missing) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, name := range resp.missing {
+				writeElement(&b, name, "")
+			}
+			b.WriteString("</d:prop><d:status>" + statusLine(http.StatusNotFound) + "</d:status></d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>")
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", []byte(b.String()))
+}
+
+// writeElement writes a property; namespaces without a prefix are declared on it
+func writeElement(b *strings.Builder, name xml.Name, value string) {
+	tag, declare := name.Local, ""
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		declare = ` xmlns="` + escape(name.Space) + `"`
+	}
+	if value == "" {
+		b.WriteString("<" + tag + declare + "/>")
+		return
+	}
+	b.WriteString("<" + tag + declare + ">" + value + "</" + tag + ">")
+}
+
+func statusLine(status int) string {
+	return "HTTP/1.1 " + strconv.Itoa(status) + " " + http.StatusText(status)
+}
+
+// escape makes text safe to put in XML
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,102 @@
+package caldav
+
+import "encoding/xml"
+
+// Languages the calendars are served in, the original Greek first
+const (
+	LanguageGreek   = "el"
+	LanguageEnglish = "en"
+)
+
+var Languages = []string{LanguageGreek, LanguageEnglish}
+
+// XML namespaces of WebDAV (RFC 4918), CalDAV (RFC 4791) and the extensions calendar
+// apps look for
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+	nsApple  = "http://apple.com/ns/ical/"
+)
+
+// prefixes are the namespace prefixes of responses, declared on their root element
+var prefixes = map[string]string{nsDAV: "d", nsCalDAV: "c", nsCS: "cs", nsApple: "ic"}
+
+// Properties served
+var (
+	propResourceType          = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName           = xml.Name{Space: nsDAV, Local: "displayname"}
+	propCurrentUserPrincipal  = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL          = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propPrivileges            = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	propSupportedReports      = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	propETag                  = xml.Name{Space: nsDAV, Local: "getetag"}
+	propContentType           = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propCalendarHome          = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propCalendarUserAddresses = xml.Name{Space: nsCalDAV, Local: "calendar-user-address-set"}
+	propSupportedComponents   = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData          = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propCTag                  = xml.Name{Space: nsCS, Local: "getctag"}
+	propColor                 = xml.Name{Space: nsApple, Local: "calendar-color"}
+)
+
+// Reports supported on calendars
+var (
+	reportQuery    = xml.Name{Space: nsCalDAV, Local: "calendar-query"}
+	reportMultiget = xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}
+)
+
+// anyElement is an element whose name is all that matters
+type anyElement struct {
+	XMLName xml.Name
+}
+
+// propList is the <prop> of a request, listing the properties wanted
+type propList struct {
+	Props []anyElement `xml:",any"`
+}
+
+// propfindRequest is the body of a PROPFIND. An empty body asks for every property.
+type propfindRequest struct {
+	XMLName xml.Name    `xml:"DAV: propfind"`
+	AllProp *anyElement `xml:"DAV: allprop"`
+	Prop    *propList   `xml:"DAV: prop"`
+}
+
+// reportRequest is the body of a calendar-query or calendar-multiget REPORT
+type reportRequest struct {
+	XMLName xml.Name
+	Prop    *propList `xml:"DAV: prop"`
+	Hrefs   []string  `xml:"DAV: href"`
+	Filter  *struct {
+		Comp compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// compFilter narrows a calendar-query to components, e.g. the VEVENTs of a time range
+type compFilter struct {
+	Name      string       `xml:"name,attr"`
+	TimeRange *timeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	Comps     []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// timeRange bounds a calendar-query, as UTC date-times; either may be missing
+type timeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
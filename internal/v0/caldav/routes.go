@@ -0,0 +1,78 @@
+package caldav
+
+import (
+	"API/internal/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSlug is the feature tokens need to sign in. Each calendar also needs the feature
+// of the data it holds (see calendar.Collection).
+const FeatureSlug = "caldav"
+
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "CalDAV", Description: "A read-only CalDAV account with the meal schedule, the academic calendar and the token's user's bookings"},
+}
+
+// methods CalDAV is read through, besides OPTIONS
+var methods = []string{"PROPFIND", "REPORT", http.MethodGet, http.MethodHead}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	requireToken := authMiddleware.RequireToken(FeatureSlug)
+	rg.OPTIONS("/caldav/*path", h.Options)
+	rg.Handle("PROPFIND", "/caldav/*path", basicAuth, requireToken, h.Propfind)
+	rg.Handle("REPORT", "/caldav/*path", basicAuth, requireToken, h.Report)
+	rg.GET("/caldav/*path", basicAuth, requireToken, h.Get)
+	rg.HEAD("/caldav/*path", basicAuth, requireToken, h.Get)
+}
+
+// RegisterWellKnown points calendar apps given only the host at the account (RFC 6764)
+func RegisterWellKnown(router *gin.Engine, rg *gin.RouterGroup) {
+	location := rg.BasePath() + "/caldav/"
+	redirect := func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, location)
+	}
+	router.OPTIONS("/.well-known/caldav", redirect)
+	for _, method := range methods {
+		router.Handle(method, "/.well-known/caldav", redirect)
+	}
+}
+
+// basicAuth lets calendar apps, which only speak Basic authentication, sign in with the
+// token as the password; the username is ignored. Bearer tokens work as anywhere else.
+func basicAuth(c *gin.Context) {
+	if _, password, ok := c.Request.BasicAuth(); ok {
+		c.Request.Header.Set("Authorization", "Bearer "+password)
+	}
+	c.Writer = &challengeWriter{ResponseWriter: c.Writer}
+	c.Next()
+}
+
+// challengeWriter asks for Basic credentials when a request is not authenticated, which
+// calendar apps wait for before sending them
+type challengeWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *challengeWriter) WriteHeader(status int) {
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="`+Realm+`", charset="UTF-8"`)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,370 @@
+package courses
+
+import (
+	"API/internal/databases"
+	"API/internal/pagination"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new course catalog repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+var (
+	// ErrDuplicateDepartment is returned when another department already has the code, ignoring case
+	ErrDuplicateDepartment = errors.New("a department with this code already exists")
+
+	// ErrDepartmentInUse is returned when deleting a department that still has courses
+	ErrDepartmentInUse = errors.New("department still has courses")
+
+	// ErrUnknownDepartment is returned when a course names a department that does not exist
+	ErrUnknownDepartment = errors.New("department does not exist")
+
+	// ErrDuplicateCourse is returned when another course of the department already has the code
+	ErrDuplicateCourse = errors.New("the department already has a course with this code")
+)
+
+// ListDepartments returns every department by code. A university has a few dozen of
+// them, so they are not paginated.
+func (r *Repository) ListDepartments(ctx context.Context) ([]Department, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, code, name, COALESCE(name_en, '') FROM departments ORDER BY code")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	departments := []Department{}
+	for rows.Next() {
+		var d Department
+		if err := rows.Scan(&d.ID, &d.Code, &d.Name, &d.NameEn); err != nil {
+			return nil, err
+		}
+		departments = append(departments, d)
+	}
+	return departments, rows.Err()
+}
+
+// checkDuplicateDepartment fails with ErrDuplicateDepartment when a department other than
+// exceptID has the code
+func checkDuplicateDepartment(ctx context.Context, tx *sql.Tx, code string, exceptID int64) error {
+	var id int64
+	err := tx.QueryRowContext(ctx, "SELECT id FROM departments WHERE code = ? AND id != ? LIMIT 1", code, exceptID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return ErrDuplicateDepartment
+}
+
+// CreateDepartment adds a department
+func (r *Repository) CreateDepartment(ctx context.Context, d Department) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkDuplicateDepartment(ctx, tx, d.Code, 0); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO departments (code, name, name_en) VALUES (?, ?, ?)", d.Code, d.Name, databases.NullString(d.NameEn))
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplaceDepartment overwrites a department. It returns false when the department does not exist.
+func (r *Repository) ReplaceDepartment(ctx context.Context, id int64, d Department) (bool, error) {
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkDuplicateDepartment(ctx, tx, d.Code, id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "UPDATE departments SET code = ?, name = ?, name_en = ? WHERE id = ?", d.Code, d.Name, databases.NullString(d.NameEn), id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		replaced = n > 0
+		return err
+	})
+	return replaced, err
+}
+
+// DeleteDepartment deletes a department without courses. It returns false when the
+// department does not exist.
+func (r *Repository) DeleteDepartment(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		var courses int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM courses WHERE department_id = ?", id).Scan(&courses); err != nil {
+			return err
+		}
+		if courses > 0 {
+			return ErrDepartmentInUse
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM departments WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+// courseColumns are the columns scanCourse reads, in order; c is the courses table and d
+// the departments table
+const courseColumns = "c.id, c.department_id, d.code, c.code, c.title, COALESCE(c.title_en, ''), c.semester, c.ects"
+
+func scanCourse(scan func(dest ...interface{}) error) (Course, error) {
+	course := Course{Instructors: []string{}}
+	err := scan(&course.ID, &course.DepartmentID, &course.Department, &course.Code, &course.Title, &course.TitleEn, &course.Semester, &course.ECTS)
+	return course, err
+}
+
+// attachInstructors loads the instructors of courses
+func attachInstructors(ctx context.Context, db *sql.DB, courses []*Course) error {
+	if len(courses) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*Course, len(courses))
+	placeholders := make([]string, 0, len(courses))
+	args := make([]interface{}, 0, len(courses))
+	for _, course := range courses {
+		byID[course.ID] = course
+		placeholders = append(placeholders, "?")
+		args = append(args, course.ID)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT course_id, name FROM course_instructors
+		WHERE course_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY course_id, position`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var courseID int64
+		var name string
+		if err := rows.Scan(&courseID, &name); err != nil {
+			return err
+		}
+		byID[courseID].Instructors = append(byID[courseID].Instructors, name)
+	}
+	return rows.Err()
+}
+
+// GetCourse returns a course with its instructors, or nil when it does not exist
+func (r *Repository) GetCourse(ctx context.Context, id int64) (*Course, error) {
+	course, err := scanCourse(r.db.QueryRowContext(ctx, `
+		SELECT `+courseColumns+` FROM courses c
+		JOIN departments d ON d.id = c.department_id
+		WHERE c.id = ?`, id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &course, attachInstructors(ctx, r.db, []*Course{&course})
+}
+
+// ListCourses returns a page of courses matching the filter, newest first. The page holds
+// one extra course when another page follows (see pagination.Next).
+func (r *Repository) ListCourses(ctx context.Context, filter CourseFilter, page pagination.Params) ([]Course, error) {
+	where, args := courseFilter(filter)
+	after, afterArgs := page.Where("c.id")
+	args = append(append(args, afterArgs...), page.FetchLimit())
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+courseColumns+` FROM courses c
+		JOIN departments d ON d.id = c.department_id
+		WHERE `+where+` AND `+after+`
+		ORDER BY c.id DESC
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	courses := []Course{}
+	for rows.Next() {
+		course, err := scanCourse(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		courses = append(courses, course)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pointers := make([]*Course, len(courses))
+	for i := range courses {
+		pointers[i] = &courses[i]
+	}
+	return courses, attachInstructors(ctx, r.db, pointers)
+}
+
+// CountCourses returns the number of courses matching the filter
+func (r *Repository) CountCourses(ctx context.Context, filter CourseFilter) (int, error) {
+	where, args := courseFilter(filter)
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM courses c JOIN departments d ON d.id = c.department_id WHERE "+where, args...).Scan(&count)
+	return count, err
+}
+
+func courseFilter(filter CourseFilter) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.Department != "" {
+		conditions = append(conditions, "d.code = ?")
+		args = append(args, filter.Department)
+	}
+	if filter.Semester != 0 {
+		conditions = append(conditions, "c.semester = ?")
+		args = append(args, filter.Semester)
+	}
+	if q := strings.TrimSpace(filter.Instructor); q != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM course_instructors ci WHERE ci.course_id = c.id AND LOWER(ci.name) LIKE ?)")
+		args = append(args, "%"+strings.ToLower(q)+"%")
+	}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		conditions = append(conditions, "(LOWER(c.code) LIKE ? OR LOWER(c.title) LIKE ? OR LOWER(COALESCE(c.title_en, '')) LIKE ?)")
+		like := "%" + strings.ToLower(q) + "%"
+		args = append(args, like, like, like)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// checkCourse fails with ErrUnknownDepartment when the course's department does not exist
+// and with ErrDuplicateCourse when a course of the department other than exceptID has its code
+func checkCourse(ctx context.Context, tx *sql.Tx, course Course, exceptID int64) error {
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM departments WHERE id = ?)", course.DepartmentID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUnknownDepartment
+	}
+	err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM courses WHERE department_id = ? AND code = ? AND id != ?)",
+		course.DepartmentID, course.Code, exceptID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrDuplicateCourse
+	}
+	return nil
+}
+
+// writeInstructors replaces the instructors of a course
+func writeInstructors(ctx context.Context, tx *sql.Tx, id int64, instructors []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM course_instructors WHERE course_id = ?", id); err != nil {
+		return err
+	}
+	for position, name := range instructors {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO course_instructors (course_id, position, name) VALUES (?, ?, ?)", id, position, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateCourse adds a course with its instructors
+func (r *Repository) CreateCourse(ctx context.Context, course Course) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkCourse(ctx, tx, course, 0); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO courses (department_id, code, title, title_en, semester, ects) VALUES (?, ?, ?, ?, ?, ?)
+		`, course.DepartmentID, course.Code, course.Title, databases.NullString(course.TitleEn), course.Semester, course.ECTS)
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		return writeInstructors(ctx, tx, id, course.Instructors)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplaceCourse overwrites a course and its instructors. It returns false when the course
+// does not exist.
+func (r *Repository) ReplaceCourse(ctx context.Context, id int64, course Course) (bool, error) {
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkCourse(ctx, tx, course, id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE courses SET department_id = ?, code = ?, title = ?, title_en = ?, semester = ?, ects = ? WHERE id = ?
+		`, course.DepartmentID, course.Code, course.Title, databases.NullString(course.TitleEn), course.Semester, course.ECTS, id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		replaced = true
+		return writeInstructors(ctx, tx, id, course.Instructors)
+	})
+	return replaced, err
+}
+
+// DeleteCourse deletes a course and its instructors. It returns false when the course does
+// not exist.
+func (r *Repository) DeleteCourse(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM course_instructors WHERE course_id = ?", id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM courses WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,341 @@
+package courses
+
+import (
+	"API/internal/apierror"
+	"API/internal/pagination"
+	"API/internal/v0/common"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the course catalogs from the Repository
+type Handler struct {
+	repo *Repository
+}
+
+func NewHandler(repo *Repository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// GetDepartments returns every department with a course catalog
+// GET /courses/departments?lang=
+func (h *Handler) GetDepartments(c *gin.Context) {
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	departments, err := h.repo.ListDepartments(c.Request.Context())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list departments")))
+		return
+	}
+	for i := range departments {
+		departments[i] = departments[i].Localized(lang)
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"departments": departments}))
+}
+
+// ListCourses returns the courses matching the filters, newest first
+// GET /courses?department=&semester=&instructor=&q=&lang=&limit=&cursor=
+func (h *Handler) ListCourses(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+
+	filter := CourseFilter{
+		Department: strings.TrimSpace(c.Query("department")),
+		Instructor: c.Query("instructor"),
+		Query:      c.Query("q"),
+	}
+	if v := c.Query("semester"); v != "" {
+		semester, err := strconv.Atoi(v)
+		if err != nil || semester < 1 || semester > 12 {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "semester must be a number from 1 to 12")))
+			return
+		}
+		filter.Semester = semester
+	}
+
+	courses, err := h.repo.ListCourses(c.Request.Context(), filter, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list courses")))
+		return
+	}
+	total, err := h.repo.CountCourses(c.Request.Context(), filter)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count courses")))
+		return
+	}
+
+	courses, next := pagination.Next(courses, page, func(course Course) int64 { return course.ID })
+	for i := range courses {
+		courses[i] = courses[i].Localized(lang)
+	}
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"courses": courses,
+		"total":   total,
+		"limit":   page.Limit,
+	}, next))
+}
+
+// GetCourse returns one course with its instructors
+// GET /courses/:id?lang=
+func (h *Handler) GetCourse(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid course ID")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	course, err := h.repo.GetCourse(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get course")))
+		return
+	}
+	if course == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "course not found")))
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"course": course.Localized(lang)}))
+}
+
+// ListDepartments returns every department with its translations
+// GET /admin/courses/departments
+func (h *Handler) ListDepartments(c *gin.Context) {
+	departments, err := h.repo.ListDepartments(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list departments")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"departments": departments}))
+}
+
+// PostDepartment adds a department
+// POST /admin/courses/departments
+func (h *Handler) PostDepartment(c *gin.Context) {
+	department, ok := bindDepartment(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateDepartment(c.Request.Context(), department)
+	if errors.Is(err, ErrDuplicateDepartment) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create department")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceDepartment overwrites a department's code and names
+// PUT /admin/courses/departments/:id
+func (h *Handler) ReplaceDepartment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid department ID")))
+		return
+	}
+	department, ok := bindDepartment(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceDepartment(c.Request.Context(), id, department)
+	if errors.Is(err, ErrDuplicateDepartment) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update department")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "department not found")))
+		return
+	}
+	department.ID = id
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"department": department}))
+}
+
+// DeleteDepartment deletes a department that has no courses left
+// DELETE /admin/courses/departments/:id
+func (h *Handler) DeleteDepartment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid department ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteDepartment(c.Request.Context(), id)
+	if errors.Is(err, ErrDepartmentInUse) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete department")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "department not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "department deleted"}))
+}
+
+// bindDepartment binds and validates a department body. It renders an error and returns
+// false when the body is invalid.
+func bindDepartment(c *gin.Context) (Department, bool) {
+	var department Department
+	if err := c.ShouldBindJSON(&department); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Department{}, false
+	}
+	department.Code = strings.ToUpper(strings.TrimSpace(department.Code))
+	department.Name = strings.TrimSpace(department.Name)
+	department.NameEn = strings.TrimSpace(department.NameEn)
+	var errs []apierror.Error
+	if department.Code == "" {
+		errs = append(errs, apierror.Invalid("code", "code is required"))
+	}
+	if department.Name == "" {
+		errs = append(errs, apierror.Invalid("name", "name is required"))
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Department{}, false
+	}
+	return department, true
+}
+
+// PostCourse adds a course to a department's catalog
+// POST /admin/courses
+func (h *Handler) PostCourse(c *gin.Context) {
+	course, ok := bindCourse(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateCourse(c.Request.Context(), course)
+	if !renderCourseError(c, err, "failed to create course") {
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceCourse overwrites a course and its instructors
+// PUT /admin/courses/:id
+func (h *Handler) ReplaceCourse(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid course ID")))
+		return
+	}
+	course, ok := bindCourse(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceCourse(c.Request.Context(), id, course)
+	if !renderCourseError(c, err, "failed to update course") {
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "course not found")))
+		return
+	}
+	updated, _ := h.repo.GetCourse(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"course": updated}))
+}
+
+// DeleteCourse deletes a course and its instructors
+// DELETE /admin/courses/:id
+func (h *Handler) DeleteCourse(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid course ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteCourse(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete course")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "course not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "course deleted"}))
+}
+
+// bindCourse binds and validates a course body. It renders an error and returns false
+// when the body is invalid.
+func bindCourse(c *gin.Context) (Course, bool) {
+	var course Course
+	if err := c.ShouldBindJSON(&course); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Course{}, false
+	}
+	course.Code = strings.ToUpper(strings.TrimSpace(course.Code))
+	course.Title = strings.TrimSpace(course.Title)
+	course.TitleEn = strings.TrimSpace(course.TitleEn)
+	instructors := make([]string, 0, len(course.Instructors))
+	for _, name := range course.Instructors {
+		if name = strings.TrimSpace(name); name != "" {
+			instructors = append(instructors, name)
+		}
+	}
+	course.Instructors = instructors
+	var errs []apierror.Error
+	if course.Code == "" {
+		errs = append(errs, apierror.Invalid("code", "code is required"))
+	}
+	if course.Title == "" {
+		errs = append(errs, apierror.Invalid("title", "title is required"))
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Course{}, false
+	}
+	return course, true
+}
+
+// renderCourseError renders the error of a course write, returning true when there was none
+func renderCourseError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrUnknownDepartment):
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("department_id", err.Error())))
+	case errors.Is(err, ErrDuplicateCourse):
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+	default:
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, message)))
+	}
+	return false
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
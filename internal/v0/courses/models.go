@@ -0,0 +1,74 @@
+package courses
+
+// Languages catalogs are served in, the original Greek first
+const (
+	LanguageGreek   = "el"
+	LanguageEnglish = "en"
+)
+
+var Languages = []string{LanguageGreek, LanguageEnglish}
+
+// Department publishes a course catalog
+type Department struct {
+	ID     int64  `json:"id"`
+	Code   string `json:"code" binding:"required,max=20"`
+	Name   string `json:"name" binding:"required,max=200"`
+	NameEn string `json:"name_en,omitempty" binding:"max=200"`
+}
+
+// Localized returns the department in lang, falling back to the Greek original, with the
+// translation fields left out
+func (d Department) Localized(lang string) Department {
+	if lang == LanguageEnglish && d.NameEn != "" {
+		d.Name = d.NameEn
+	}
+	d.NameEn = ""
+	return d
+}
+
+// Course is a course of a department's curriculum. Instructors are listed in the order
+// the catalog gives them.
+type Course struct {
+	ID           int64    `json:"id"`
+	DepartmentID int64    `json:"department_id" binding:"required,min=1"`
+	Department   string   `json:"department"`
+	Code         string   `json:"code" binding:"required,max=20"`
+	Title        string   `json:"title" binding:"required,max=300"`
+	TitleEn      string   `json:"title_en,omitempty" binding:"max=300"`
+	Semester     int      `json:"semester" binding:"required,min=1,max=12"`
+	ECTS         float64  `json:"ects" binding:"min=0,max=60"`
+	Instructors  []string `json:"instructors" binding:"max=20,dive,required,max=200"`
+}
+
+// Localized returns the course in lang, falling back to the Greek original, with the
+// translation fields left out
+func (c Course) Localized(lang string) Course {
+	if lang == LanguageEnglish && c.TitleEn != "" {
+		c.Title = c.TitleEn
+	}
+	c.TitleEn = ""
+	return c
+}
+
+// CourseFilter narrows a course listing; zero fields match every course
+type CourseFilter struct {
+	Department string // department code, ignoring case
+	Semester   int
+	Instructor string // part of an instructor's name
+	Query      string // part of the code or title, in either language
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,53 @@
+package courses
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSlug is the feature tokens need for the course catalog endpoints
+const FeatureSlug = "courses"
+
+// Features are the features this module serves, registered at startup
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Courses API", Description: "Departments' course catalogs: codes, titles, semesters, ECTS and instructors"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	courses := rg.Group("/courses")
+	{
+		courses.GET("", authMiddleware.RequireToken(FeatureSlug), h.ListCourses)
+		courses.GET("/departments", authMiddleware.RequireToken(FeatureSlug), h.GetDepartments)
+		courses.GET("/:id", authMiddleware.RequireToken(FeatureSlug), h.GetCourse)
+	}
+
+	courses_admin := rg.Group("/admin/courses")
+	courses_admin.Use(authMiddleware.RequireSession())
+	courses_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	courses_admin.Use(authMiddleware.Idempotent())
+	{
+		courses_admin.POST("", h.PostCourse)
+		courses_admin.PUT("/:id", h.ReplaceCourse)
+		courses_admin.DELETE("/:id", h.DeleteCourse)
+		courses_admin.GET("/departments", h.ListDepartments)
+		courses_admin.POST("/departments", h.PostDepartment)
+		courses_admin.PUT("/departments/:id", h.ReplaceDepartment)
+		courses_admin.DELETE("/departments/:id", h.DeleteDepartment)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
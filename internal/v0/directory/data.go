@@ -0,0 +1,340 @@
+package directory
+
+import (
+	"API/internal/databases"
+	"API/internal/pagination"
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new directory repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// ListSchools returns every school with its departments, by code. A university has a
+// handful of schools, so they are not paginated.
+func (r *Repository) ListSchools(ctx context.Context) ([]School, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, code, name, COALESCE(name_en, '') FROM schools ORDER BY code")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schools := []School{}
+	byID := make(map[int64]int)
+	for rows.Next() {
+		school := School{Departments: []Department{}}
+		if err := rows.Scan(&school.ID, &school.Code, &school.Name, &school.NameEn); err != nil {
+			return nil, err
+		}
+		byID[school.ID] = len(schools)
+		schools = append(schools, school)
+	}
+	if err := rows.Err(); err != nil || len(schools) == 0 {
+		return schools, err
+	}
+
+	departments, err := r.db.QueryContext(ctx, "SELECT id, school_id, code, name, COALESCE(name_en, '') FROM departments ORDER BY code")
+	if err != nil {
+		return nil, err
+	}
+	defer departments.Close()
+	for departments.Next() {
+		var schoolID int64
+		var d Department
+		if err := departments.Scan(&d.ID, &schoolID, &d.Code, &d.Name, &d.NameEn); err != nil {
+			return nil, err
+		}
+		if i, ok := byID[schoolID]; ok {
+			schools[i].Departments = append(schools[i].Departments, d)
+		}
+	}
+	return schools, departments.Err()
+}
+
+// DepartmentExists reports whether a department has the code, ignoring case
+func (r *Repository) DepartmentExists(ctx context.Context, code string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM departments WHERE code = ?)", code).Scan(&exists)
+	return exists, err
+}
+
+// staffColumns are the columns scanStaff reads, in order; s is the staff table and d the
+// departments table
+const staffColumns = `s.id, d.code, s.name, COALESCE(s.name_en, ''), COALESCE(s.title, ''), COALESCE(s.office, ''),
+	COALESCE(s.email, ''), COALESCE(s.phone, ''), COALESCE(s.office_hours, '')`
+
+func scanStaff(scan func(dest ...interface{}) error) (Staff, error) {
+	var s Staff
+	err := scan(&s.ID, &s.Department, &s.Name, &s.NameEn, &s.Title, &s.Office, &s.Email, &s.Phone, &s.OfficeHours)
+	return s, err
+}
+
+func queryStaff(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]Staff, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	staff := []Staff{}
+	for rows.Next() {
+		s, err := scanStaff(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		staff = append(staff, s)
+	}
+	return staff, rows.Err()
+}
+
+// GetStaff returns a staff member, or nil when they do not exist
+func (r *Repository) GetStaff(ctx context.Context, id int64) (*Staff, error) {
+	staff, err := queryStaff(ctx, r.db, `
+		SELECT `+staffColumns+` FROM staff s
+		JOIN departments d ON d.id = s.department_id
+		WHERE s.id = ?`, id)
+	if err != nil || len(staff) == 0 {
+		return nil, err
+	}
+	return &staff[0], nil
+}
+
+// ListDepartmentStaff returns the staff of a department by name. Departments have a few
+// dozen staff members, so they are not paginated.
+func (r *Repository) ListDepartmentStaff(ctx context.Context, code string) ([]Staff, error) {
+	return queryStaff(ctx, r.db, `
+		SELECT `+staffColumns+` FROM staff s
+		JOIN departments d ON d.id = s.department_id
+		WHERE d.code = ?
+		ORDER BY s.name, s.id`, code)
+}
+
+// SearchStaff returns a page of staff matching the filter, newest first. The page holds
+// one extra staff member when another page follows (see pagination.Next).
+func (r *Repository) SearchStaff(ctx context.Context, filter StaffFilter, page pagination.Params) ([]Staff, error) {
+	where, args := staffFilter(filter)
+	after, afterArgs := page.Where("s.id")
+	args = append(append(args, afterArgs...), page.FetchLimit())
+	return queryStaff(ctx, r.db, `
+		SELECT `+staffColumns+` FROM staff s
+		JOIN departments d ON d.id = s.department_id
+		WHERE `+where+` AND `+after+`
+		ORDER BY s.id DESC
+		LIMIT ?`, args...)
+}
+
+// CountStaff returns the number of staff matching the filter
+func (r *Repository) CountStaff(ctx context.Context, filter StaffFilter) (int, error) {
+	where, args := staffFilter(filter)
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM staff s JOIN departments d ON d.id = s.department_id WHERE "+where, args...).Scan(&count)
+	return count, err
+}
+
+func staffFilter(filter StaffFilter) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.Department != "" {
+		conditions = append(conditions, "d.code = ?")
+		args = append(args, filter.Department)
+	}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		conditions = append(conditions, `(LOWER(s.name) LIKE ? OR LOWER(COALESCE(s.name_en, '')) LIKE ?
+			OR LOWER(COALESCE(s.email, '')) LIKE ? OR LOWER(COALESCE(s.office, '')) LIKE ?)`)
+		like := "%" + strings.ToLower(q) + "%"
+		args = append(args, like, like, like, like)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// errImportRolledBack rolls back a dry-run import's transaction
+var errImportRolledBack = errors.New("import rolled back")
+
+// ImportDirectory replaces the directory with imp in one transaction. Schools and
+// departments are matched by code and staff by email, or by department and name when
+// they have none; the unmatched ones are removed. When dryRun is set the transaction is
+// rolled back, so nothing changes but the result shows what would.
+func (r *Repository) ImportDirectory(ctx context.Context, imp DirectoryImport, dryRun bool) (*ImportResult, error) {
+	result := &ImportResult{DryRun: dryRun}
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		schoolIDs, err := codeIDs(ctx, tx, "schools")
+		if err != nil {
+			return err
+		}
+		departmentIDs, err := codeIDs(ctx, tx, "departments")
+		if err != nil {
+			return err
+		}
+		staffIDs, err := staffKeys(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		keptSchools, keptDepartments, keptStaff := map[int64]bool{}, map[int64]bool{}, map[int64]bool{}
+		for _, school := range imp.Schools {
+			schoolID, err := upsert(ctx, tx, &result.Schools, schoolIDs[strings.ToLower(school.Code)],
+				"schools", []string{"code", "name", "name_en"},
+				school.Code, school.Name, databases.NullString(school.NameEn))
+			if err != nil {
+				return err
+			}
+			keptSchools[schoolID] = true
+
+			for _, department := range school.Departments {
+				departmentID, err := upsert(ctx, tx, &result.Departments, departmentIDs[strings.ToLower(department.Code)],
+					"departments", []string{"school_id", "code", "name", "name_en"},
+					schoolID, department.Code, department.Name, databases.NullString(department.NameEn))
+				if err != nil {
+					return err
+				}
+				keptDepartments[departmentID] = true
+
+				for _, s := range department.Staff {
+					staffID, err := upsert(ctx, tx, &result.Staff, staffIDs[staffKey(s, departmentIDs[strings.ToLower(department.Code)])],
+						"staff", []string{"department_id", "name", "name_en", "title", "office", "email", "phone", "office_hours"},
+						departmentID, s.Name, databases.NullString(s.NameEn), databases.NullString(s.Title), databases.NullString(s.Office),
+						databases.NullString(s.Email), databases.NullString(s.Phone), databases.NullString(s.OfficeHours))
+					if err != nil {
+						return err
+					}
+					keptStaff[staffID] = true
+				}
+			}
+		}
+
+		// Staff go first, so no department is removed from under them
+		for _, removal := range []struct {
+			table  string
+			ids    map[string]int64
+			kept   map[int64]bool
+			counts *ImportCounts
+		}{
+			{"staff", staffIDs, keptStaff, &result.Staff},
+			{"departments", departmentIDs, keptDepartments, &result.Departments},
+			{"schools", schoolIDs, keptSchools, &result.Schools},
+		} {
+			for _, id := range removal.ids {
+				if removal.kept[id] {
+					continue
+				}
+				if _, err := tx.ExecContext(ctx, "DELETE FROM "+removal.table+" WHERE id = ?", id); err != nil {
+					return err
+				}
+				removal.counts.Removed++
+			}
+		}
+
+		if dryRun {
+			return errImportRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// codeIDs returns the IDs of the rows of a table with a code column by lowercased code
+func codeIDs(ctx context.Context, tx *sql.Tx, table string) (map[string]int64, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT id, code FROM "+table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := make(map[string]int64)
+	for rows.Next() {
+		var id int64
+		var code string
+		if err := rows.Scan(&id, &code); err != nil {
+			return nil, err
+		}
+		ids[strings.ToLower(code)] = id
+	}
+	return ids, rows.Err()
+}
+
+// staffKeys returns the IDs of the staff by the key imports match them on
+func staffKeys(ctx context.Context, tx *sql.Tx) (map[string]int64, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT id, department_id, name, COALESCE(email, '') FROM staff")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := make(map[string]int64)
+	for rows.Next() {
+		var id, departmentID int64
+		var s StaffImport
+		if err := rows.Scan(&id, &departmentID, &s.Name, &s.Email); err != nil {
+			return nil, err
+		}
+		ids[staffKey(s, departmentID)] = id
+	}
+	return ids, rows.Err()
+}
+
+// staffKey identifies a staff member across imports: by email, or by department and name
+// when they have none. departmentID is 0 for departments that are new to the directory.
+func staffKey(s StaffImport, departmentID int64) string {
+	if s.Email != "" {
+		return "email:" + strings.ToLower(s.Email)
+	}
+	return "name:" + strconv.FormatInt(departmentID, 10) + ":" + strings.ToLower(s.Name)
+}
+
+// upsert updates the row id of table to values, counting it as updated when anything
+// changed, or inserts a new row when id is 0. It returns the row's ID.
+func upsert(ctx context.Context, tx *sql.Tx, counts *ImportCounts, id int64, table string, columns []string, values ...interface{}) (int64, error) {
+	if id == 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+		res, err := tx.ExecContext(ctx, "INSERT INTO "+table+" ("+strings.Join(columns, ", ")+") VALUES ("+placeholders+")", values...)
+		if err != nil {
+			return 0, err
+		}
+		counts.Created++
+		return res.LastInsertId()
+	}
+
+	sets := make([]string, len(columns))
+	changes := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = column + " = ?"
+		changes[i] = column + " IS NOT ?"
+	}
+	args := append(append(append([]interface{}{}, values...), id), values...)
+	res, err := tx.ExecContext(ctx, "UPDATE "+table+" SET "+strings.Join(sets, ", ")+
+		" WHERE id = ? AND ("+strings.Join(changes, " OR ")+")", args...)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return 0, err
+	} else if n > 0 {
+		counts.Updated++
+	}
+	return id, nil
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
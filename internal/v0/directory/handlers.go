@@ -0,0 +1,229 @@
+package directory
+
+import (
+	"API/internal/apierror"
+	"API/internal/pagination"
+	"API/internal/v0/common"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the staff directory from the Repository
+type Handler struct {
+	repo *Repository
+}
+
+func NewHandler(repo *Repository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// GetSchools returns every school with its departments
+// GET /directory/schools?lang=
+func (h *Handler) GetSchools(c *gin.Context) {
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	schools, err := h.repo.ListSchools(c.Request.Context())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list schools")))
+		return
+	}
+	for i := range schools {
+		schools[i] = schools[i].Localized(lang)
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"schools": schools}))
+}
+
+// GetDepartmentStaff returns the staff of a department by name
+// GET /directory/departments/:code/staff?lang=
+func (h *Handler) GetDepartmentStaff(c *gin.Context) {
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	code := c.Param("code")
+	exists, err := h.repo.DepartmentExists(c.Request.Context(), code)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get department")))
+		return
+	}
+	if !exists {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "department not found")))
+		return
+	}
+	staff, err := h.repo.ListDepartmentStaff(c.Request.Context(), code)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list staff")))
+		return
+	}
+	for i := range staff {
+		staff[i] = staff[i].Localized(lang)
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"staff": staff}))
+}
+
+// SearchStaff returns the staff matching a search, newest first
+// GET /directory/staff?q=&department=&lang=&limit=&cursor=
+func (h *Handler) SearchStaff(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+
+	filter := StaffFilter{Department: strings.TrimSpace(c.Query("department")), Query: c.Query("q")}
+	staff, err := h.repo.SearchStaff(c.Request.Context(), filter, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to search staff")))
+		return
+	}
+	total, err := h.repo.CountStaff(c.Request.Context(), filter)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count staff")))
+		return
+	}
+
+	staff, next := pagination.Next(staff, page, func(s Staff) int64 { return s.ID })
+	for i := range staff {
+		staff[i] = staff[i].Localized(lang)
+	}
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"staff": staff,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
+}
+
+// GetStaff returns one staff member
+// GET /directory/staff/:id?lang=
+func (h *Handler) GetStaff(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid staff ID")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	staff, err := h.repo.GetStaff(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get staff member")))
+		return
+	}
+	if staff == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "staff member not found")))
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"staff": staff.Localized(lang)}))
+}
+
+// PostImport replaces the directory with the one in the body, reporting what was created,
+// updated and removed. ?dry_run=true reports it without changing anything.
+// POST /admin/directory/import?dry_run=
+func (h *Handler) PostImport(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("dry_run", "dry_run must be true or false")))
+		return
+	}
+	var imp DirectoryImport
+	if err := c.ShouldBindJSON(&imp); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	if errs := normalizeImport(&imp); len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+
+	result, err := h.repo.ImportDirectory(c.Request.Context(), imp, dryRun)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to import directory")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(result))
+}
+
+// normalizeImport trims the fields of an import and checks that it names every school,
+// department and staff member once
+func normalizeImport(imp *DirectoryImport) []apierror.Error {
+	var errs []apierror.Error
+	schools, departments, staff := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for i := range imp.Schools {
+		school := &imp.Schools[i]
+		field := fmt.Sprintf("schools[%d]", i)
+		school.Code = strings.ToUpper(strings.TrimSpace(school.Code))
+		school.Name, school.NameEn = strings.TrimSpace(school.Name), strings.TrimSpace(school.NameEn)
+		errs = append(errs, checkUnique(schools, school.Code, field+".code", "school")...)
+		if school.Name == "" {
+			errs = append(errs, apierror.Invalid(field+".name", "name is required"))
+		}
+
+		for j := range school.Departments {
+			department := &school.Departments[j]
+			field := fmt.Sprintf("%s.departments[%d]", field, j)
+			department.Code = strings.ToUpper(strings.TrimSpace(department.Code))
+			department.Name, department.NameEn = strings.TrimSpace(department.Name), strings.TrimSpace(department.NameEn)
+			errs = append(errs, checkUnique(departments, department.Code, field+".code", "department")...)
+			if department.Name == "" {
+				errs = append(errs, apierror.Invalid(field+".name", "name is required"))
+			}
+
+			for k := range department.Staff {
+				s := &department.Staff[k]
+				field := fmt.Sprintf("%s.staff[%d]", field, k)
+				for _, value := range []*string{&s.Name, &s.NameEn, &s.Title, &s.Office, &s.Email, &s.Phone, &s.OfficeHours} {
+					*value = strings.TrimSpace(*value)
+				}
+				if s.Name == "" {
+					errs = append(errs, apierror.Invalid(field+".name", "name is required"))
+					continue
+				}
+				// Staff without an email are told apart by name within their department
+				key, keyField := strings.ToLower(s.Email), field+".email"
+				if key == "" {
+					key, keyField = department.Code+":"+strings.ToLower(s.Name), field+".name"
+				}
+				errs = append(errs, checkUnique(staff, key, keyField, "staff member")...)
+			}
+		}
+	}
+	return errs
+}
+
+// checkUnique records key in seen, returning an error for field when it was already there
+func checkUnique(seen map[string]bool, key, field, kind string) []apierror.Error {
+	if key == "" {
+		return []apierror.Error{apierror.Invalid(field, "code is required")}
+	}
+	if seen[strings.ToLower(key)] {
+		return []apierror.Error{apierror.Invalid(field, "another "+kind+" has the same "+field[strings.LastIndex(field, ".")+1:])}
+	}
+	seen[strings.ToLower(key)] = true
+	return nil
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
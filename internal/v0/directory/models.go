@@ -0,0 +1,141 @@
+package directory
+
+// Languages the directory is served in, the original Greek first
+const (
+	LanguageGreek   = "el"
+	LanguageEnglish = "en"
+)
+
+var Languages = []string{LanguageGreek, LanguageEnglish}
+
+// School groups departments
+type School struct {
+	ID          int64        `json:"id"`
+	Code        string       `json:"code"`
+	Name        string       `json:"name"`
+	NameEn      string       `json:"name_en,omitempty"`
+	Departments []Department `json:"departments"`
+}
+
+// Localized returns the school and its departments in lang, falling back to the Greek
+// original, with the translation fields left out
+func (s School) Localized(lang string) School {
+	if lang == LanguageEnglish && s.NameEn != "" {
+		s.Name = s.NameEn
+	}
+	s.NameEn = ""
+	departments := make([]Department, len(s.Departments))
+	for i, d := range s.Departments {
+		departments[i] = d.Localized(lang)
+	}
+	s.Departments = departments
+	return s
+}
+
+// Department belongs to a school and has staff
+type Department struct {
+	ID     int64  `json:"id"`
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	NameEn string `json:"name_en,omitempty"`
+}
+
+// Localized returns the department in lang, falling back to the Greek original, with the
+// translation fields left out
+func (d Department) Localized(lang string) Department {
+	if lang == LanguageEnglish && d.NameEn != "" {
+		d.Name = d.NameEn
+	}
+	d.NameEn = ""
+	return d
+}
+
+// Staff is a member of a department's faculty or administration
+type Staff struct {
+	ID          int64  `json:"id"`
+	Department  string `json:"department"` // code
+	Name        string `json:"name"`
+	NameEn      string `json:"name_en,omitempty"`
+	Title       string `json:"title,omitempty"` // e.g. "Professor"
+	Office      string `json:"office,omitempty"`
+	Email       string `json:"email,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+	OfficeHours string `json:"office_hours,omitempty"`
+}
+
+// Localized returns the staff member in lang, falling back to the Greek original, with
+// the translation fields left out
+func (s Staff) Localized(lang string) Staff {
+	if lang == LanguageEnglish && s.NameEn != "" {
+		s.Name = s.NameEn
+	}
+	s.NameEn = ""
+	return s
+}
+
+// StaffFilter narrows a staff listing; zero fields match every staff member
+type StaffFilter struct {
+	Department string // department code, ignoring case
+	Query      string // part of the name (in either language), email or office
+}
+
+// DirectoryImport is the whole directory as the university publishes it. Importing it
+// replaces the stored directory: schools and departments are matched by code and staff
+// by email, or by name when they have none, so their IDs stay the same across imports.
+type DirectoryImport struct {
+	Schools []SchoolImport `json:"schools" binding:"required,min=1,dive"`
+}
+
+type SchoolImport struct {
+	Code        string             `json:"code" binding:"required,max=20"`
+	Name        string             `json:"name" binding:"required,max=200"`
+	NameEn      string             `json:"name_en" binding:"max=200"`
+	Departments []DepartmentImport `json:"departments" binding:"dive"`
+}
+
+type DepartmentImport struct {
+	Code   string        `json:"code" binding:"required,max=20"`
+	Name   string        `json:"name" binding:"required,max=200"`
+	NameEn string        `json:"name_en" binding:"max=200"`
+	Staff  []StaffImport `json:"staff" binding:"dive"`
+}
+
+type StaffImport struct {
+	Name        string `json:"name" binding:"required,max=200"`
+	NameEn      string `json:"name_en" binding:"max=200"`
+	Title       string `json:"title" binding:"max=100"`
+	Office      string `json:"office" binding:"max=100"`
+	Email       string `json:"email" binding:"omitempty,email,max=254"`
+	Phone       string `json:"phone" binding:"max=50"`
+	OfficeHours string `json:"office_hours" binding:"max=500"`
+}
+
+// ImportCounts is how many rows of a kind an import created, updated and removed
+type ImportCounts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Removed int `json:"removed"`
+}
+
+// ImportResult reports what an import changed, or would change on a dry run
+type ImportResult struct {
+	DryRun      bool         `json:"dry_run"`
+	Schools     ImportCounts `json:"schools"`
+	Departments ImportCounts `json:"departments"`
+	Staff       ImportCounts `json:"staff"`
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
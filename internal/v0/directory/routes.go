@@ -0,0 +1,52 @@
+package directory
+
+import (
+	"API/internal/auth"
+	"API/internal/limits"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSlug is the feature tokens need for the directory endpoints
+const FeatureSlug = "directory"
+
+// Features are the features this module serves, registered at startup
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Directory API", Description: "Schools, departments and their staff with offices, contacts and office hours"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	directory := rg.Group("/directory")
+	{
+		directory.GET("/schools", authMiddleware.RequireToken(FeatureSlug), h.GetSchools)
+		directory.GET("/departments/:code/staff", authMiddleware.RequireToken(FeatureSlug), h.GetDepartmentStaff)
+		directory.GET("/staff", authMiddleware.RequireToken(FeatureSlug), h.SearchStaff)
+		directory.GET("/staff/:id", authMiddleware.RequireToken(FeatureSlug), h.GetStaff)
+	}
+
+	directory_admin := rg.Group("/admin/directory")
+	directory_admin.Use(authMiddleware.RequireSession())
+	directory_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	directory_admin.Use(authMiddleware.Idempotent())
+	{
+		directory_admin.POST("/import", h.PostImport)
+		limits.SetRoute(http.MethodPost, directory_admin.BasePath()+"/import", limits.Limits{MaxBodyBytes: 10 << 20, Timeout: time.Minute})
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,75 @@
+package eclass
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// courseCacheTTL bounds how long a user's courses are reused. Enrolments change a few
+	// times a semester, while the documents endpoint needs the courses on every call.
+	courseCacheTTL = 10 * time.Minute
+
+	// courseCacheSize bounds the users kept
+	courseCacheSize = 4096
+)
+
+// courseCache holds the courses of recently active users, so listing their documents
+// costs eClass one call instead of two. Cached lists are shared between callers and
+// must not be modified.
+type courseCache struct {
+	mu      sync.Mutex
+	entries map[int64]courseEntry
+}
+
+type courseEntry struct {
+	courses   []EnrolledCourse
+	expiresAt time.Time
+}
+
+func newCourseCache() *courseCache {
+	return &courseCache{entries: make(map[int64]courseEntry)}
+}
+
+// get returns the cached courses of a user and whether there were any
+func (c *courseCache) get(userID int64) ([]EnrolledCourse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[userID]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(c.entries, userID)
+		ok = false
+	}
+	return entry.courses, ok
+}
+
+func (c *courseCache) put(userID int64, courses []EnrolledCourse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= courseCacheSize {
+		clear(c.entries)
+	}
+	c.entries[userID] = courseEntry{courses: courses, expiresAt: time.Now().Add(courseCacheTTL)}
+}
+
+// forget drops a user's courses, e.g. when they link another account
+func (c *courseCache) forget(userID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
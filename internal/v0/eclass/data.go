@@ -0,0 +1,141 @@
+package eclass
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type Repository struct {
+	db     *sql.DB
+	tokens *tokenCipher // nil without a key
+}
+
+// NewRepository creates a new eClass links repository. Tokens are stored encrypted with
+// tokenKey; without one, links can only be deleted.
+func NewRepository(db *sql.DB, tokenKey string) *Repository {
+	r := &Repository{db: db}
+	if tokenKey != "" {
+		r.tokens = newTokenCipher(tokenKey)
+	}
+	return r
+}
+
+// GetLink returns the eClass account a user linked, or nil when there is none
+func (r *Repository) GetLink(ctx context.Context, userID int64) (*Link, error) {
+	if r.tokens == nil {
+		return nil, ErrNoTokenKey
+	}
+	var l Link
+	var lastUsed sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT user_id, site_user_id, username, full_name, token, consented_at, last_used_at
+		FROM eclass_links WHERE user_id = ?
+	`, userID).Scan(&l.UserID, &l.SiteUserID, &l.Username, &l.FullName, &l.Token, &l.ConsentedAt, &lastUsed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lastUsed.Valid {
+		l.LastUsedAt = &lastUsed.Time
+	}
+	if l.Token, err = r.tokens.open(l.UserID, l.Token); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// SaveLink links an eClass account to a user, replacing the one linked before
+func (r *Repository) SaveLink(ctx context.Context, l *Link) error {
+	if r.tokens == nil {
+		return ErrNoTokenKey
+	}
+	token, err := r.tokens.seal(l.UserID, l.Token)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO eclass_links (user_id, site_user_id, username, full_name, token, consented_at, last_used_at)
+		VALUES (?, ?, ?, ?, ?, ?, NULL)
+		ON CONFLICT(user_id) DO UPDATE SET
+			site_user_id = excluded.site_user_id,
+			username = excluded.username,
+			full_name = excluded.full_name,
+			token = excluded.token,
+			consented_at = excluded.consented_at,
+			last_used_at = NULL
+	`, l.UserID, l.SiteUserID, l.Username, l.FullName, token, l.ConsentedAt)
+	return err
+}
+
+// EncryptStoredTokens encrypts the tokens linked before tokens were stored encrypted,
+// returning how many there were
+func (r *Repository) EncryptStoredTokens(ctx context.Context) (int, error) {
+	if r.tokens == nil {
+		return 0, ErrNoTokenKey
+	}
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, token FROM eclass_links WHERE token NOT LIKE ?", sealedPrefix+"%")
+	if err != nil {
+		return 0, err
+	}
+	plain := make(map[int64]string)
+	for rows.Next() {
+		var userID int64
+		var token string
+		if err := rows.Scan(&userID, &token); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		plain[userID] = token
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for userID, token := range plain {
+		sealed, err := r.tokens.seal(userID, token)
+		if err != nil {
+			return 0, err
+		}
+		// A link saved meanwhile already has its token encrypted
+		if _, err := r.db.ExecContext(ctx, "UPDATE eclass_links SET token = ? WHERE user_id = ? AND token = ?", sealed, userID, token); err != nil {
+			return 0, err
+		}
+	}
+	return len(plain), nil
+}
+
+// TouchLink records that a user's linked account was just used
+func (r *Repository) TouchLink(ctx context.Context, userID int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE eclass_links SET last_used_at = ? WHERE user_id = ?", at, userID)
+	return err
+}
+
+// DeleteLink unlinks a user's eClass account, reporting whether there was one
+func (r *Repository) DeleteLink(ctx context.Context, userID int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM eclass_links WHERE user_id = ?", userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,135 @@
+package eclass
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"API/internal/databases/migrations"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const testToken = "4f1e7c2a9b0d8e6f3a5c7b9d1e2f4a6c"
+
+// testDB returns a freshly migrated eClass database
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "eclass.db")
+	if err := migrations.Up("eclass", file); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// storedToken reads the token column of a user's link as it is stored
+func storedToken(t *testing.T, db *sql.DB, userID int64) string {
+	t.Helper()
+	var token string
+	if err := db.QueryRow("SELECT token FROM eclass_links WHERE user_id = ?", userID).Scan(&token); err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestLinkTokenEncrypted(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	repo := NewRepository(db, "the token key")
+	link := &Link{UserID: 1, SiteUserID: 42, Username: "student", FullName: "A Student", Token: testToken, ConsentedAt: time.Now()}
+	if err := repo.SaveLink(ctx, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored := storedToken(t, db, 1); strings.Contains(stored, testToken) {
+		t.Errorf("stored token %q holds the raw token", stored)
+	}
+	got, err := repo.GetLink(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Token != testToken {
+		t.Errorf("GetLink token = %q, want %q", got.Token, testToken)
+	}
+}
+
+func TestLinkTokenDecryption(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		key     string
+		tamper  func(db *sql.DB) error
+		wantErr error
+	}{
+		{"with the key", "the token key", nil, nil},
+		{"with another key", "another key", nil, errSealedToken},
+		{"without a key", "", nil, ErrNoTokenKey},
+		{"copied to another user", "the token key", func(db *sql.DB) error {
+			_, err := db.Exec("UPDATE eclass_links SET token = (SELECT token FROM eclass_links WHERE user_id = 2) WHERE user_id = 1")
+			return err
+		}, errSealedToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testDB(t)
+			repo := NewRepository(db, "the token key")
+			for _, userID := range []int64{1, 2} {
+				link := &Link{UserID: userID, Username: "student", FullName: "A Student", Token: testToken, ConsentedAt: time.Now()}
+				if err := repo.SaveLink(ctx, link); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.tamper != nil {
+				if err := tt.tamper(db); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := NewRepository(db, tt.key).GetLink(ctx, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetLink error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncryptStoredTokens(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	if _, err := db.Exec(`
+		INSERT INTO eclass_links (user_id, site_user_id, username, full_name, token, consented_at)
+		VALUES (1, 42, 'student', 'A Student', ?, ?)
+	`, testToken, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(db, "the token key")
+
+	for _, want := range []int{1, 0} {
+		n, err := repo.EncryptStoredTokens(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Errorf("encrypted %d tokens, want %d", n, want)
+		}
+	}
+	if stored := storedToken(t, db, 1); strings.Contains(stored, testToken) {
+		t.Errorf("stored token %q holds the raw token", stored)
+	}
+	link, err := repo.GetLink(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.Token != testToken {
+		t.Errorf("GetLink token = %q, want %q", link.Token, testToken)
+	}
+}
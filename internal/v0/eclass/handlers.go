@@ -0,0 +1,289 @@
+package eclass
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/v0/common"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultDocumentDays is how far back GET /eclass/documents looks unless ?days= says
+	// otherwise; MaxDocumentDays caps it
+	DefaultDocumentDays = 14
+	MaxDocumentDays     = 90
+
+	// DefaultDeadlineDays is how far ahead GET /eclass/deadlines looks unless ?days= says
+	// otherwise; MaxDeadlineDays caps it
+	DefaultDeadlineDays = 30
+	MaxDeadlineDays     = 180
+)
+
+// Handler serves the calling user's eClass data through the account they linked. client
+// is nil when the tenant has no eClass site, and every endpoint answers 404.
+type Handler struct {
+	repo    *Repository
+	client  *Client
+	courses *courseCache
+}
+
+func NewHandler(repo *Repository, client *Client) *Handler {
+	return &Handler{repo: repo, client: client, courses: newCourseCache()}
+}
+
+// callingUser returns the user of the request when the tenant has an eClass site. It
+// renders an error and returns nil otherwise.
+func (h *Handler) callingUser(c *gin.Context) *auth.User {
+	if h.client == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "eClass is not enabled")))
+		return nil
+	}
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+	}
+	return user
+}
+
+// link loads the eClass account the user linked. It renders an error and returns nil
+// when there is none.
+func (h *Handler) link(c *gin.Context, user *auth.User) *Link {
+	link, err := h.repo.GetLink(c.Request.Context(), user.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get eClass link")))
+		return nil
+	}
+	if link == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "no eClass account is linked")))
+	}
+	return link
+}
+
+// failed renders an error of eClass. A link eClass no longer accepts is removed, so the
+// user is asked to link the account again.
+func (h *Handler) failed(c *gin.Context, link *Link, err error, message string) {
+	if errors.Is(err, ErrInvalidToken) {
+		if _, err := h.repo.DeleteLink(c.Request.Context(), link.UserID); err != nil {
+			log.Printf("eClass: failed to remove the rejected link of user %d: %v", link.UserID, err)
+		}
+		h.courses.forget(link.UserID)
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, "eClass no longer accepts the linked account; link it again")))
+		return
+	}
+	log.Printf("eClass: %s for user %d: %v", message, link.UserID, err)
+	common.JSON(c, http.StatusBadGateway, common.CreateErrorResponse(apierror.New(apierror.UpstreamFailed, message)))
+}
+
+// used records that the link served a request; failing to is not worth failing it for
+func (h *Handler) used(c *gin.Context, link *Link) {
+	if err := h.repo.TouchLink(c.Request.Context(), link.UserID, time.Now()); err != nil {
+		log.Printf("eClass: failed to record use of the link of user %d: %v", link.UserID, err)
+	}
+}
+
+// courseList returns the courses of the linked account, from the cache when recent
+func (h *Handler) courseList(c *gin.Context, link *Link) ([]EnrolledCourse, error) {
+	if courses, ok := h.courses.get(link.UserID); ok {
+		return courses, nil
+	}
+	courses, err := h.client.Courses(c.Request.Context(), link.Token, link.SiteUserID)
+	if err != nil {
+		return nil, err
+	}
+	h.courses.put(link.UserID, courses)
+	return courses, nil
+}
+
+// parseDays reads ?days=, between 1 and max. It renders an error and returns false when
+// it is invalid.
+func parseDays(c *gin.Context, def, max int) (int, bool) {
+	value := c.Query("days")
+	if value == "" {
+		return def, true
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 || days > max {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("days", fmt.Sprintf("days must be between 1 and %d", max))))
+		return 0, false
+	}
+	return days, true
+}
+
+// GetLink returns the eClass account the current user linked
+// GET /eclass/link
+func (h *Handler) GetLink(c *gin.Context) {
+	user := h.callingUser(c)
+	if user == nil {
+		return
+	}
+	link := h.link(c, user)
+	if link == nil {
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"link": link}))
+}
+
+// PutLink links the current user's eClass account with their consent, replacing the
+// one linked before. A password is only passed on to eClass in exchange for a key.
+// PUT /eclass/link
+func (h *Handler) PutLink(c *gin.Context) {
+	user := h.callingUser(c)
+	if user == nil {
+		return
+	}
+	var req LinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	if !req.Consent {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("consent", "consent must be true to link an eClass account")))
+		return
+	}
+
+	ctx := c.Request.Context()
+	token := strings.TrimSpace(req.Key)
+	if req.Username != "" {
+		var err error
+		token, err = h.client.Login(ctx, strings.TrimSpace(req.Username), req.Password)
+		if errors.Is(err, ErrInvalidLogin) {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("password", err.Error())))
+			return
+		}
+		if err != nil {
+			log.Printf("eClass: failed to sign in user %d: %v", user.ID, err)
+			common.JSON(c, http.StatusBadGateway, common.CreateErrorResponse(apierror.New(apierror.UpstreamFailed, "failed to sign in to eClass")))
+			return
+		}
+	}
+	info, err := h.client.SiteInfo(ctx, token)
+	if errors.Is(err, ErrInvalidToken) {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("key", "eClass does not accept this key")))
+		return
+	}
+	if err != nil {
+		log.Printf("eClass: failed to get the account of user %d: %v", user.ID, err)
+		common.JSON(c, http.StatusBadGateway, common.CreateErrorResponse(apierror.New(apierror.UpstreamFailed, "failed to get the eClass account")))
+		return
+	}
+
+	link := &Link{
+		UserID:      user.ID,
+		SiteUserID:  info.UserID,
+		Username:    info.Username,
+		FullName:    info.FullName,
+		Token:       token,
+		ConsentedAt: time.Now().UTC(),
+	}
+	if err := h.repo.SaveLink(ctx, link); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to link eClass account")))
+		return
+	}
+	h.courses.forget(user.ID)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"link": link}))
+}
+
+// DeleteLink unlinks the current user's eClass account, withdrawing their consent. The
+// key stays valid on eClass until the user resets it from their security keys page.
+// DELETE /eclass/link
+func (h *Handler) DeleteLink(c *gin.Context) {
+	user := h.callingUser(c)
+	if user == nil {
+		return
+	}
+	deleted, err := h.repo.DeleteLink(c.Request.Context(), user.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to unlink eClass account")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "no eClass account is linked")))
+		return
+	}
+	h.courses.forget(user.ID)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "eClass account unlinked"}))
+}
+
+// GetCourses returns the courses the linked eClass account is enrolled in
+// GET /eclass/courses
+func (h *Handler) GetCourses(c *gin.Context) {
+	user := h.callingUser(c)
+	if user == nil {
+		return
+	}
+	link := h.link(c, user)
+	if link == nil {
+		return
+	}
+	courses, err := h.courseList(c, link)
+	if err != nil {
+		h.failed(c, link, err, "failed to get courses from eClass")
+		return
+	}
+	h.used(c, link)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"courses": courses}))
+}
+
+// GetDocuments returns the files posted to the linked account's courses in the last
+// days (default 14, at most 90), newest first
+// GET /eclass/documents?days=
+func (h *Handler) GetDocuments(c *gin.Context) {
+	user := h.callingUser(c)
+	if user == nil {
+		return
+	}
+	days, ok := parseDays(c, DefaultDocumentDays, MaxDocumentDays)
+	if !ok {
+		return
+	}
+	link := h.link(c, user)
+	if link == nil {
+		return
+	}
+	courses, err := h.courseList(c, link)
+	if err != nil {
+		h.failed(c, link, err, "failed to get courses from eClass")
+		return
+	}
+	documents, err := h.client.Documents(c.Request.Context(), link.Token, courses, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		h.failed(c, link, err, "failed to get documents from eClass")
+		return
+	}
+	h.used(c, link)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"documents": documents, "days": days}))
+}
+
+// GetDeadlines returns the linked account's activities due in the next days (default
+// 30, at most 180), soonest first
+// GET /eclass/deadlines?days=
+func (h *Handler) GetDeadlines(c *gin.Context) {
+	user := h.callingUser(c)
+	if user == nil {
+		return
+	}
+	days, ok := parseDays(c, DefaultDeadlineDays, MaxDeadlineDays)
+	if !ok {
+		return
+	}
+	link := h.link(c, user)
+	if link == nil {
+		return
+	}
+	now := time.Now()
+	deadlines, err := h.client.Deadlines(c.Request.Context(), link.Token, now, now.AddDate(0, 0, days))
+	if err != nil {
+		h.failed(c, link, err, "failed to get deadlines from eClass")
+		return
+	}
+	h.used(c, link)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"deadlines": deadlines, "days": days}))
+}
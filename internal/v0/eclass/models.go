@@ -0,0 +1,72 @@
+package eclass
+
+import "time"
+
+// Link is the eClass account a user linked to the API. The web service token eClass
+// issued for it is only used to call eClass on the user's behalf and never returned.
+type Link struct {
+	UserID      int64      `json:"-"`
+	SiteUserID  int64      `json:"site_user_id"`
+	Username    string     `json:"username"`
+	FullName    string     `json:"full_name"`
+	Token       string     `json:"-"`
+	ConsentedAt time.Time  `json:"consented_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+}
+
+// LinkRequest is the body of PUT /eclass/link. Either the account's username and
+// password, which are exchanged for a web service token and not stored, or a key
+// copied from the eClass security keys page, for accounts that sign in through the
+// institution. consent must be true: the user agrees to the API reading their eClass
+// courses, documents and deadlines until they unlink the account.
+type LinkRequest struct {
+	Username string `json:"username" binding:"required_without=Key,max=100"`
+	Password string `json:"password" binding:"required_with=Username,max=200"`
+	Key      string `json:"key" binding:"required_without=Username,max=100"`
+	Consent  bool   `json:"consent"`
+}
+
+// EnrolledCourse is an eClass course the linked account is enrolled in
+type EnrolledCourse struct {
+	ID        int64  `json:"id"`
+	ShortName string `json:"short_name"`
+	FullName  string `json:"full_name"`
+	URL       string `json:"url"`
+}
+
+// Document is a file posted to one of the account's courses
+type Document struct {
+	ID         int64     `json:"id"`
+	CourseID   int64     `json:"course_id"`
+	Course     string    `json:"course"`
+	Name       string    `json:"name"`
+	URL        string    `json:"url"` // The document's page on eClass, where the user signs in to download it
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// Deadline is an upcoming activity the account has to act on, e.g. an assignment to
+// submit or a quiz to take
+type Deadline struct {
+	ID       int64     `json:"id"`
+	Name     string    `json:"name"`
+	Module   string    `json:"module"` // The activity type, e.g. assign or quiz
+	CourseID int64     `json:"course_id"`
+	Course   string    `json:"course"`
+	DueAt    time.Time `json:"due_at"`
+	URL      string    `json:"url"`
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
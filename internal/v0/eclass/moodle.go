@@ -0,0 +1,283 @@
+package eclass
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultService is the Moodle web service tokens are requested for. The mobile app's
+	// service is enabled on most sites and exposes every function the module calls.
+	DefaultService = "moodle_mobile_app"
+
+	// maxResponseBytes caps the size of an eClass response; larger ones are rejected
+	maxResponseBytes = 4 << 20
+
+	// maxDeadlines is the most events Moodle returns from one timeline query
+	maxDeadlines = 50
+)
+
+var (
+	// ErrInvalidLogin is returned when eClass rejects a username and password
+	ErrInvalidLogin = errors.New("eClass rejected the username or password")
+
+	// ErrInvalidToken is returned when eClass no longer accepts a linked account's token,
+	// e.g. because the user reset their security keys
+	ErrInvalidToken = errors.New("eClass no longer accepts the linked account's key")
+)
+
+// Client calls the web services of a Moodle-based eClass site
+type Client struct {
+	baseURL string
+	service string
+	http    *http.Client
+}
+
+// NewClient creates a client for the eClass site at baseURL, requesting tokens for the
+// given web service (DefaultService when empty)
+func NewClient(baseURL, service string) *Client {
+	if service == "" {
+		service = DefaultService
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		service: service,
+		http:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// moodleError is the body of a failed Moodle call. The login endpoint reports errors in
+// error, the web services in exception and message.
+type moodleError struct {
+	Error     string `json:"error"`
+	Exception string `json:"exception"`
+	ErrorCode string `json:"errorcode"`
+	Message   string `json:"message"`
+}
+
+// post sends a form to a Moodle endpoint and decodes the answer into out. Tokens and
+// passwords travel in the body, so they stay out of proxy and server logs.
+func (c *Client) post(ctx context.Context, path string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("eClass returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxResponseBytes {
+		return fmt.Errorf("eClass response is larger than %d bytes", maxResponseBytes)
+	}
+
+	// Errors come back as objects with a 200 status, even from functions returning lists
+	var failure moodleError
+	if json.Unmarshal(body, &failure) == nil && (failure.Exception != "" || failure.Error != "") {
+		switch failure.ErrorCode {
+		case "invalidlogin":
+			return ErrInvalidLogin
+		case "invalidtoken":
+			return ErrInvalidToken
+		}
+		message := failure.Message
+		if message == "" {
+			message = failure.Error
+		}
+		return fmt.Errorf("eClass error %s: %s", failure.ErrorCode, message)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("unexpected eClass response: %w", err)
+	}
+	return nil
+}
+
+// call runs a web service function with the token of an account
+func (c *Client) call(ctx context.Context, token, function string, params url.Values, out interface{}) error {
+	form := url.Values{}
+	for key, values := range params {
+		form[key] = values
+	}
+	form.Set("wstoken", token)
+	form.Set("wsfunction", function)
+	form.Set("moodlewsrestformat", "json")
+	return c.post(ctx, "/webservice/rest/server.php", form, out)
+}
+
+// Login exchanges an account's username and password for a web service token
+func (c *Client) Login(ctx context.Context, username, password string) (string, error) {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	form := url.Values{"username": {username}, "password": {password}, "service": {c.service}}
+	if err := c.post(ctx, "/login/token.php", form, &resp); err != nil {
+		return "", err
+	}
+	if resp.Token == "" {
+		return "", errors.New("eClass issued no token")
+	}
+	return resp.Token, nil
+}
+
+// SiteInfo is the account a token belongs to
+type SiteInfo struct {
+	UserID   int64  `json:"userid"`
+	Username string `json:"username"`
+	FullName string `json:"fullname"`
+}
+
+// SiteInfo returns the account of a token, which also checks that eClass accepts it
+func (c *Client) SiteInfo(ctx context.Context, token string) (*SiteInfo, error) {
+	var info SiteInfo
+	if err := c.call(ctx, token, "core_webservice_get_site_info", nil, &info); err != nil {
+		return nil, err
+	}
+	if info.UserID == 0 {
+		return nil, errors.New("eClass returned no account")
+	}
+	return &info, nil
+}
+
+// Courses returns the visible courses an account is enrolled in
+func (c *Client) Courses(ctx context.Context, token string, siteUserID int64) ([]EnrolledCourse, error) {
+	var resp []struct {
+		ID        int64  `json:"id"`
+		ShortName string `json:"shortname"`
+		FullName  string `json:"fullname"`
+		Visible   *int   `json:"visible"`
+	}
+	params := url.Values{"userid": {strconv.FormatInt(siteUserID, 10)}}
+	if err := c.call(ctx, token, "core_enrol_get_users_courses", params, &resp); err != nil {
+		return nil, err
+	}
+	courses := []EnrolledCourse{}
+	for _, course := range resp {
+		if course.Visible != nil && *course.Visible == 0 {
+			continue
+		}
+		courses = append(courses, EnrolledCourse{
+			ID:        course.ID,
+			ShortName: course.ShortName,
+			FullName:  course.FullName,
+			URL:       c.baseURL + "/course/view.php?id=" + strconv.FormatInt(course.ID, 10),
+		})
+	}
+	return courses, nil
+}
+
+// Documents returns the files of the given courses modified since a time, newest first
+func (c *Client) Documents(ctx context.Context, token string, courses []EnrolledCourse, since time.Time) ([]Document, error) {
+	documents := []Document{}
+	if len(courses) == 0 {
+		return documents, nil
+	}
+	names := make(map[int64]string, len(courses))
+	params := url.Values{}
+	for i, course := range courses {
+		names[course.ID] = course.FullName
+		params.Set(fmt.Sprintf("courseids[%d]", i), strconv.FormatInt(course.ID, 10))
+	}
+
+	var resp struct {
+		Resources []struct {
+			CourseModule int64  `json:"coursemodule"`
+			Course       int64  `json:"course"`
+			Name         string `json:"name"`
+			TimeModified int64  `json:"timemodified"`
+		} `json:"resources"`
+	}
+	if err := c.call(ctx, token, "mod_resource_get_resources_by_courses", params, &resp); err != nil {
+		return nil, err
+	}
+	for _, r := range resp.Resources {
+		modified := time.Unix(r.TimeModified, 0).UTC()
+		if modified.Before(since) {
+			continue
+		}
+		documents = append(documents, Document{
+			ID:         r.CourseModule,
+			CourseID:   r.Course,
+			Course:     names[r.Course],
+			Name:       r.Name,
+			URL:        c.baseURL + "/mod/resource/view.php?id=" + strconv.FormatInt(r.CourseModule, 10),
+			ModifiedAt: modified,
+		})
+	}
+	sort.SliceStable(documents, func(i, j int) bool {
+		return documents[i].ModifiedAt.After(documents[j].ModifiedAt)
+	})
+	return documents, nil
+}
+
+// Deadlines returns the account's activities due between from and to, soonest first
+func (c *Client) Deadlines(ctx context.Context, token string, from, to time.Time) ([]Deadline, error) {
+	var resp struct {
+		Events []struct {
+			ID         int64  `json:"id"`
+			Name       string `json:"name"`
+			ModuleName string `json:"modulename"`
+			TimeSort   int64  `json:"timesort"`
+			URL        string `json:"url"`
+			Course     struct {
+				ID       int64  `json:"id"`
+				FullName string `json:"fullname"`
+			} `json:"course"`
+		} `json:"events"`
+	}
+	params := url.Values{
+		"timesortfrom": {strconv.FormatInt(from.Unix(), 10)},
+		"timesortto":   {strconv.FormatInt(to.Unix(), 10)},
+		"limitnum":     {strconv.Itoa(maxDeadlines)},
+	}
+	if err := c.call(ctx, token, "core_calendar_get_action_events_by_timesort", params, &resp); err != nil {
+		return nil, err
+	}
+	deadlines := []Deadline{}
+	for _, e := range resp.Events {
+		deadlines = append(deadlines, Deadline{
+			ID:       e.ID,
+			Name:     e.Name,
+			Module:   e.ModuleName,
+			CourseID: e.Course.ID,
+			Course:   e.Course.FullName,
+			DueAt:    time.Unix(e.TimeSort, 0).UTC(),
+			URL:      e.URL,
+		})
+	}
+	return deadlines, nil
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
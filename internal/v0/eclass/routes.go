@@ -0,0 +1,47 @@
+package eclass
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// FeatureSlug is the feature tokens need for the eClass endpoints
+	FeatureSlug = "eclass"
+
+	// FeatureRPM is the groups' quota on the feature. Each request costs eClass one or
+	// two calls on the user's behalf, so it is far below the usual quotas.
+	FeatureRPM = 10
+)
+
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "eClass", RPM: FeatureRPM, Description: "The courses, new documents and deadlines of the eClass account the token's user linked"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	eclass := rg.Group("/eclass")
+	{
+		eclass.GET("/link", authMiddleware.RequireToken(FeatureSlug), h.GetLink)
+		eclass.PUT("/link", authMiddleware.RequireToken(FeatureSlug), h.PutLink)
+		eclass.DELETE("/link", authMiddleware.RequireToken(FeatureSlug), h.DeleteLink)
+		eclass.GET("/courses", authMiddleware.RequireToken(FeatureSlug), h.GetCourses)
+		eclass.GET("/documents", authMiddleware.RequireToken(FeatureSlug), h.GetDocuments)
+		eclass.GET("/deadlines", authMiddleware.RequireToken(FeatureSlug), h.GetDeadlines)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
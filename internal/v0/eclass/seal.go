@@ -0,0 +1,83 @@
+package eclass
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// sealedPrefix marks a stored token encrypted by tokenCipher. Tokens linked before
+// tokens were encrypted lack it until EncryptStoredTokens runs.
+const sealedPrefix = "v1:"
+
+var (
+	// ErrNoTokenKey is returned for links read or saved without ECLASS_TOKEN_KEY
+	ErrNoTokenKey = errors.New("no eClass token key is configured")
+
+	// errSealedToken is returned for a stored token that cannot be decrypted, e.g. with
+	// another key or from another user's row
+	errSealedToken = errors.New("stored eClass token cannot be decrypted")
+)
+
+// tokenCipher encrypts the stored web service tokens, which let anyone holding one act
+// as the student on eClass. It uses AES-256-GCM under the SHA-256 of the configured key,
+// with the user ID as additional data, so a token copied to another user's row does
+// not decrypt.
+type tokenCipher struct {
+	aead cipher.AEAD
+}
+
+func newTokenCipher(key string) *tokenCipher {
+	sum := sha256.Sum256([]byte(key))
+	block, _ := aes.NewCipher(sum[:]) // a 32-byte key cannot fail
+	aead, _ := cipher.NewGCM(block)
+	return &tokenCipher{aead: aead}
+}
+
+// seal encrypts the token of a user's link
+func (tc *tokenCipher) seal(userID int64, token string) (string, error) {
+	nonce := make([]byte, tc.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := tc.aead.Seal(nonce, nonce, []byte(token), []byte(strconv.FormatInt(userID, 10)))
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts the stored token of a user's link
+func (tc *tokenCipher) open(userID int64, stored string) (string, error) {
+	encoded, ok := strings.CutPrefix(stored, sealedPrefix)
+	if !ok {
+		return "", errSealedToken
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < tc.aead.NonceSize() {
+		return "", errSealedToken
+	}
+	nonce, ciphertext := sealed[:tc.aead.NonceSize()], sealed[tc.aead.NonceSize():]
+	token, err := tc.aead.Open(nil, nonce, ciphertext, []byte(strconv.FormatInt(userID, 10)))
+	if err != nil {
+		return "", errSealedToken
+	}
+	return string(token), nil
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"API/internal/calendar"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CalendarAcademic is the name of the academic calendar (see calendar.Collection)
+const CalendarAcademic = "academic"
+
+// entry turns an event into a calendar entry
+func (e Event) entry() calendar.Entry {
+	// Organizers are free text rather than addresses, so they go in the description
+	description := e.Organizer
+	if e.Description != "" {
+		description += "\n\n" + e.Description
+	}
+	return calendar.Entry{
+		UID:         fmt.Sprintf("event-%d", e.ID),
+		Summary:     e.Title,
+		Description: description,
+		Location:    e.Venue,
+		URL:         e.URL,
+		Categories:  []string{strings.ToUpper(e.Category)},
+		Start:       e.StartsAt,
+		End:         e.EndsAt,
+		Modified:    e.UpdatedAt,
+	}
+}
+
+// iCalendar renders events as an RFC 5545 calendar that calendar apps can subscribe to.
+// host makes the event UIDs unique to this API.
+func iCalendar(events []Event, name, host string, now time.Time) []byte {
+	entries := make([]calendar.Entry, len(events))
+	for i, e := range events {
+		entries[i] = e.entry()
+	}
+	return calendar.Encode(name, entries, host, now)
+}
+
+// Calendars returns what the events calendar publishes for calendar apps: the approved
+// events of the academic category, e.g. exam periods and holidays
+func Calendars(repo *Repository) []calendar.Collection {
+	return []calendar.Collection{
+		{
+			Name:    CalendarAcademic,
+			Feature: FeatureSlug,
+			Color:   "#1E5AA8",
+			Title: func(lang string) string {
+				if lang == LanguageEnglish {
+					return "DUTH academic calendar"
+				}
+				return "Ακαδημαϊκό ημερολόγιο ΔΠΘ"
+			},
+			Entries: func(ctx context.Context, userID int64, lang string, from, to time.Time) ([]calendar.Entry, error) {
+				events, err := repo.CalendarEvents(ctx, EventFilter{Category: "academic", Status: StatusApproved, From: from, To: to}, calendarLimit)
+				if err != nil {
+					return nil, err
+				}
+				entries := make([]calendar.Entry, len(events))
+				for i, e := range events {
+					entries[i] = e.Localized(lang).entry()
+				}
+				return entries, nil
+			},
+		},
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
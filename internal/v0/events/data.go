@@ -0,0 +1,229 @@
+package events
+
+import (
+	"API/internal/databases"
+	"API/internal/pagination"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrEventNotFound is returned when deciding on an event that does not exist
+	ErrEventNotFound = errors.New("event not found")
+
+	// ErrEventState is returned when a decision is made on an event that is not pending
+	ErrEventState = errors.New("event is not pending")
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new events repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// eventColumns are the columns scanEvent reads, in order
+const eventColumns = `id, title, COALESCE(title_en, ''), COALESCE(description, ''), category, venue, organizer,
+	COALESCE(url, ''), starts_at, ends_at, status, submitted_by, decided_by, decided_at, COALESCE(decision_note, ''),
+	created_at, updated_at`
+
+func scanEvent(scan func(dest ...interface{}) error) (Event, error) {
+	var e Event
+	var submittedBy, decidedBy sql.NullInt64
+	var decidedAt sql.NullTime
+	err := scan(&e.ID, &e.Title, &e.TitleEn, &e.Description, &e.Category, &e.Venue, &e.Organizer,
+		&e.URL, &e.StartsAt, &e.EndsAt, &e.Status, &submittedBy, &decidedBy, &decidedAt, &e.DecisionNote,
+		&e.CreatedAt, &e.UpdatedAt)
+	if submittedBy.Valid {
+		e.SubmittedBy = &submittedBy.Int64
+	}
+	if decidedBy.Valid {
+		e.DecidedBy = &decidedBy.Int64
+	}
+	if decidedAt.Valid {
+		e.DecidedAt = &decidedAt.Time
+	}
+	return e, err
+}
+
+func (r *Repository) queryEvents(ctx context.Context, query string, args ...interface{}) ([]Event, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		e, err := scanEvent(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// GetEvent returns an event whatever its status, or nil when it does not exist
+func (r *Repository) GetEvent(ctx context.Context, id int64) (*Event, error) {
+	e, err := scanEvent(r.db.QueryRowContext(ctx, "SELECT "+eventColumns+" FROM events WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ListEvents returns a page of events matching the filter, newest first. The page holds
+// one extra event when another page follows (see pagination.Next).
+func (r *Repository) ListEvents(ctx context.Context, filter EventFilter, page pagination.Params) ([]Event, error) {
+	where, args := eventFilter(filter)
+	after, afterArgs := page.Where("id")
+	args = append(append(args, afterArgs...), page.FetchLimit())
+	return r.queryEvents(ctx, `
+		SELECT `+eventColumns+` FROM events
+		WHERE `+where+` AND `+after+`
+		ORDER BY id DESC
+		LIMIT ?`, args...)
+}
+
+// CountEvents returns the number of events matching the filter
+func (r *Repository) CountEvents(ctx context.Context, filter EventFilter) (int, error) {
+	where, args := eventFilter(filter)
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM events WHERE "+where, args...).Scan(&count)
+	return count, err
+}
+
+// CalendarEvents returns up to limit events matching the filter in the order they start,
+// for calendar exports
+func (r *Repository) CalendarEvents(ctx context.Context, filter EventFilter, limit int) ([]Event, error) {
+	where, args := eventFilter(filter)
+	args = append(args, limit)
+	return r.queryEvents(ctx, `
+		SELECT `+eventColumns+` FROM events
+		WHERE `+where+`
+		ORDER BY starts_at, id
+		LIMIT ?`, args...)
+}
+
+func eventFilter(filter EventFilter) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.SubmittedBy != 0 {
+		conditions = append(conditions, "submitted_by = ?")
+		args = append(args, filter.SubmittedBy)
+	}
+	if filter.Category != "" {
+		conditions = append(conditions, "category = ?")
+		args = append(args, filter.Category)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "ends_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "starts_at < ?")
+		args = append(args, filter.To.UTC())
+	}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		conditions = append(conditions, `(LOWER(title) LIKE ? OR LOWER(COALESCE(title_en, '')) LIKE ?
+			OR LOWER(venue) LIKE ? OR LOWER(organizer) LIKE ?)`)
+		like := "%" + strings.ToLower(q) + "%"
+		args = append(args, like, like, like, like)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// CreateEvent adds an event with the given status. submittedBy is nil for events added
+// by admins.
+func (r *Repository) CreateEvent(ctx context.Context, req EventRequest, status Status, submittedBy *int64) (int64, error) {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO events (title, title_en, description, category, venue, organizer, url, starts_at, ends_at,
+			status, submitted_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, req.Title, databases.NullString(req.TitleEn), databases.NullString(req.Description), req.Category, req.Venue, req.Organizer,
+		databases.NullString(req.URL), req.StartsAt.UTC(), req.EndsAt.UTC(), status, submittedBy, now, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// ReplaceEvent overwrites the details of an event, keeping its status. It returns false
+// when the event does not exist.
+func (r *Repository) ReplaceEvent(ctx context.Context, id int64, req EventRequest) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE events SET title = ?, title_en = ?, description = ?, category = ?, venue = ?, organizer = ?, url = ?,
+			starts_at = ?, ends_at = ?, updated_at = ?
+		WHERE id = ?
+	`, req.Title, databases.NullString(req.TitleEn), databases.NullString(req.Description), req.Category, req.Venue, req.Organizer,
+		databases.NullString(req.URL), req.StartsAt.UTC(), req.EndsAt.UTC(), time.Now().UTC(), id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DeleteEvent deletes an event. It returns false when the event does not exist.
+func (r *Repository) DeleteEvent(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM events WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DecideEvent approves or rejects a pending event
+func (r *Repository) DecideEvent(ctx context.Context, id int64, status Status, adminID *int64, note string) (*Event, error) {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE events SET status = ?, decided_by = ?, decided_at = ?, decision_note = ?, updated_at = ?
+		WHERE id = ? AND status = 'pending'
+	`, status, adminID, now, databases.NullString(note), now, id)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		event, err := r.GetEvent(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if event == nil {
+			return nil, ErrEventNotFound
+		}
+		return nil, ErrEventState
+	}
+	return r.GetEvent(ctx, id)
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
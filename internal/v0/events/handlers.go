@@ -0,0 +1,417 @@
+package events
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/calendar"
+	"API/internal/campustime"
+	"API/internal/pagination"
+	"API/internal/v0/common"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// calendarDays is how far ahead calendar exports reach when ?to= is missing
+	calendarDays = 180
+
+	// calendarLimit caps the events of one calendar export
+	calendarLimit = 1000
+)
+
+// Handler serves the events calendar from the Repository
+type Handler struct {
+	repo *Repository
+}
+
+func NewHandler(repo *Repository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// parseFilter reads ?category=, ?q=, ?from= and ?to=. It renders an error with render and
+// returns false when one of them is invalid.
+func parseFilter(c *gin.Context, render func(*gin.Context, int, common.APIResponse)) (EventFilter, bool) {
+	filter := EventFilter{Category: c.Query("category"), Query: c.Query("q")}
+	var errs []apierror.Error
+	if filter.Category != "" && !slices.Contains(Categories, filter.Category) {
+		errs = append(errs, apierror.Invalid("category", "category must be one of "+strings.Join(Categories, ", ")))
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := parseBound(v, false)
+		if err != nil {
+			errs = append(errs, apierror.Invalid("from", "from must be an RFC 3339 time or a date"))
+		}
+		filter.From = from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := parseBound(v, true)
+		if err != nil {
+			errs = append(errs, apierror.Invalid("to", "to must be an RFC 3339 time or a date"))
+		}
+		filter.To = to
+	}
+	if len(errs) == 0 && !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		errs = append(errs, apierror.Invalid("to", "to must be after from"))
+	}
+	if len(errs) > 0 {
+		render(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return EventFilter{}, false
+	}
+	return filter, true
+}
+
+// ListEvents returns the approved events matching the filters, newest first
+// GET /events?category=&q=&from=&to=&lang=&limit=&cursor=
+func (h *Handler) ListEvents(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	filter, ok := parseFilter(c, common.Render)
+	if !ok {
+		return
+	}
+	filter.Status = StatusApproved
+
+	events, err := h.repo.ListEvents(c.Request.Context(), filter, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list events")))
+		return
+	}
+	total, err := h.repo.CountEvents(c.Request.Context(), filter)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count events")))
+		return
+	}
+
+	events, next := pagination.Next(events, page, func(e Event) int64 { return e.ID })
+	for i := range events {
+		events[i] = events[i].Localized(lang).Public()
+	}
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"events": events,
+		"total":  total,
+		"limit":  page.Limit,
+	}, next))
+}
+
+// GetEvent returns one approved event
+// GET /events/:id?lang=
+func (h *Handler) GetEvent(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid event ID")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	event, err := h.repo.GetEvent(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get event")))
+		return
+	}
+	if event == nil || event.Status != StatusApproved {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "event not found")))
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"event": event.Localized(lang).Public()}))
+}
+
+// ExportCalendar returns the approved events matching the filters as an iCalendar feed,
+// from today to calendarDays ahead unless ?from= and ?to= say otherwise
+// GET /events/calendar.ics?category=&q=&from=&to=&lang=
+func (h *Handler) ExportCalendar(c *gin.Context) {
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	filter, ok := parseFilter(c, common.Render)
+	if !ok {
+		return
+	}
+	filter.Status = StatusApproved
+	if filter.From.IsZero() {
+		filter.From = campustime.Today()
+	}
+	if filter.To.IsZero() {
+		filter.To = filter.From.AddDate(0, 0, calendarDays)
+	}
+
+	events, err := h.repo.CalendarEvents(c.Request.Context(), filter, calendarLimit)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to export events")))
+		return
+	}
+	for i := range events {
+		events[i] = events[i].Localized(lang)
+	}
+	name := "Εκδηλώσεις ΔΠΘ"
+	if lang == LanguageEnglish {
+		name = "DUTH Events"
+	}
+	c.Data(http.StatusOK, calendar.MIMEType, iCalendar(events, name, c.Request.Host, time.Now()))
+}
+
+// ListSubmissions returns the events the current user submitted, newest first, with
+// where each stands in the approval flow
+// GET /events/submissions?limit=&cursor=
+func (h *Handler) ListSubmissions(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	filter := EventFilter{SubmittedBy: user.ID}
+	events, err := h.repo.ListEvents(c.Request.Context(), filter, page)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list submissions")))
+		return
+	}
+	total, err := h.repo.CountEvents(c.Request.Context(), filter)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count submissions")))
+		return
+	}
+
+	events, next := pagination.Next(events, page, func(e Event) int64 { return e.ID })
+	common.JSON(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"events": events,
+		"total":  total,
+		"limit":  page.Limit,
+	}, next))
+}
+
+// PostSubmission submits an event, which is listed once an admin approves it
+// POST /events/submissions
+func (h *Handler) PostSubmission(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	req, ok := bindEvent(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateEvent(c.Request.Context(), req, StatusPending, &user.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to submit event")))
+		return
+	}
+	event, _ := h.repo.GetEvent(c.Request.Context(), id)
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"event": event}))
+}
+
+// AdminListEvents returns the events matching the filters whatever their status, newest
+// first. ?status=pending lists the submissions waiting for a decision.
+// GET /admin/events?status=&category=&q=&from=&to=&limit=&cursor=
+func (h *Handler) AdminListEvents(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	filter, ok := parseFilter(c, common.JSON)
+	if !ok {
+		return
+	}
+	switch status := Status(c.Query("status")); status {
+	case "", StatusPending, StatusApproved, StatusRejected:
+		filter.Status = status
+	default:
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("status", "status must be pending, approved or rejected")))
+		return
+	}
+
+	events, err := h.repo.ListEvents(c.Request.Context(), filter, page)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list events")))
+		return
+	}
+	total, err := h.repo.CountEvents(c.Request.Context(), filter)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count events")))
+		return
+	}
+
+	events, next := pagination.Next(events, page, func(e Event) int64 { return e.ID })
+	common.JSON(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"events": events,
+		"total":  total,
+		"limit":  page.Limit,
+	}, next))
+}
+
+// PostEvent adds an approved event
+// POST /admin/events
+func (h *Handler) PostEvent(c *gin.Context) {
+	req, ok := bindEvent(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateEvent(c.Request.Context(), req, StatusApproved, nil)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create event")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceEvent overwrites the details of an event, e.g. to fix a submission before
+// approving it
+// PUT /admin/events/:id
+func (h *Handler) ReplaceEvent(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid event ID")))
+		return
+	}
+	req, ok := bindEvent(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceEvent(c.Request.Context(), id, req)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update event")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "event not found")))
+		return
+	}
+	event, _ := h.repo.GetEvent(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"event": event}))
+}
+
+// DeleteEvent deletes an event
+// DELETE /admin/events/:id
+func (h *Handler) DeleteEvent(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid event ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteEvent(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete event")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "event not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "event deleted"}))
+}
+
+// ApproveEvent lists a pending submission
+// POST /admin/events/:id/approve
+func (h *Handler) ApproveEvent(c *gin.Context) {
+	h.decideEvent(c, StatusApproved)
+}
+
+// RejectEvent turns down a pending submission, with an optional note for the submitter
+// POST /admin/events/:id/reject
+func (h *Handler) RejectEvent(c *gin.Context) {
+	h.decideEvent(c, StatusRejected)
+}
+
+func (h *Handler) decideEvent(c *gin.Context, status Status) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid event ID")))
+		return
+	}
+
+	var req DecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+			return
+		}
+	}
+
+	var adminID *int64
+	if admin := auth.GetUserFromContext(c); admin != nil {
+		adminID = &admin.ID
+	}
+	event, err := h.repo.DecideEvent(c.Request.Context(), id, status, adminID, strings.TrimSpace(req.Note))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEventNotFound):
+			common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, err.Error())))
+		case errors.Is(err, ErrEventState):
+			common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		default:
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update event")))
+		}
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"event": event}))
+}
+
+// bindEvent binds and validates an event body. It renders an error and returns false
+// when the body is invalid.
+func bindEvent(c *gin.Context) (EventRequest, bool) {
+	var req EventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return EventRequest{}, false
+	}
+	req.Title, req.TitleEn = strings.TrimSpace(req.Title), strings.TrimSpace(req.TitleEn)
+	req.Description = strings.TrimSpace(req.Description)
+	req.Venue, req.Organizer = strings.TrimSpace(req.Venue), strings.TrimSpace(req.Organizer)
+	req.URL = strings.TrimSpace(req.URL)
+	req.StartsAt, req.EndsAt = req.StartsAt.Truncate(time.Second), req.EndsAt.Truncate(time.Second)
+	var errs []apierror.Error
+	if req.Title == "" {
+		errs = append(errs, apierror.Invalid("title", "title is required"))
+	}
+	if req.Venue == "" {
+		errs = append(errs, apierror.Invalid("venue", "venue is required"))
+	}
+	if req.Organizer == "" {
+		errs = append(errs, apierror.Invalid("organizer", "organizer is required"))
+	}
+	if req.EndsAt.Before(req.StartsAt) {
+		errs = append(errs, apierror.Invalid("ends_at", "ends_at must not be before starts_at"))
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return EventRequest{}, false
+	}
+	return req, true
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,109 @@
+package events
+
+import "time"
+
+// Languages events are served in, the original Greek first
+const (
+	LanguageGreek   = "el"
+	LanguageEnglish = "en"
+)
+
+var Languages = []string{LanguageGreek, LanguageEnglish}
+
+// Categories are the kinds of events the calendar lists
+var Categories = []string{"talk", "career", "student", "cultural", "sports", "academic", "other"}
+
+// Status is where an event stands in the approval flow
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+// Event is a talk, career day, student event or the like. Submitted events are pending
+// until an admin approves them; events added by admins are approved right away.
+type Event struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	TitleEn     string    `json:"title_en,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Category    string    `json:"category"`
+	Venue       string    `json:"venue"`
+	Organizer   string    `json:"organizer"`
+	URL         string    `json:"url,omitempty"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+
+	// Approval details, left out of public listings
+	Status       Status     `json:"status,omitempty"`
+	SubmittedBy  *int64     `json:"submitted_by,omitempty"`
+	DecidedBy    *int64     `json:"decided_by,omitempty"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	DecisionNote string     `json:"decision_note,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Localized returns the event in lang, falling back to the Greek original, with the
+// translation fields left out
+func (e Event) Localized(lang string) Event {
+	if lang == LanguageEnglish && e.TitleEn != "" {
+		e.Title = e.TitleEn
+	}
+	e.TitleEn = ""
+	return e
+}
+
+// Public returns the event without its approval details
+func (e Event) Public() Event {
+	e.Status = ""
+	e.SubmittedBy, e.DecidedBy, e.DecidedAt = nil, nil, nil
+	e.DecisionNote = ""
+	return e
+}
+
+// EventRequest is the body admins and submitters send to add or replace an event
+type EventRequest struct {
+	Title       string    `json:"title" binding:"required,max=200"`
+	TitleEn     string    `json:"title_en" binding:"max=200"`
+	Description string    `json:"description" binding:"max=5000"`
+	Category    string    `json:"category" binding:"required,oneof=talk career student cultural sports academic other"`
+	Venue       string    `json:"venue" binding:"required,max=200"`
+	Organizer   string    `json:"organizer" binding:"required,max=200"`
+	URL         string    `json:"url" binding:"omitempty,url,max=500"`
+	StartsAt    time.Time `json:"starts_at" binding:"required"`
+	EndsAt      time.Time `json:"ends_at" binding:"required"`
+}
+
+// DecisionRequest is the optional body of an approval or rejection
+type DecisionRequest struct {
+	Note string `json:"note" binding:"max=1000"`
+}
+
+// EventFilter narrows an event listing; zero fields match every event
+type EventFilter struct {
+	Category string
+	Query    string    // part of the title (in either language), venue or organizer
+	From     time.Time // events that end at or after From
+	To       time.Time // events that start before To
+	Status   Status
+	// SubmittedBy narrows the listing to one user's submissions when not zero
+	SubmittedBy int64
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
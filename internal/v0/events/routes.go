@@ -0,0 +1,60 @@
+package events
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSlug is the feature tokens need for the events endpoints
+const FeatureSlug = "events"
+
+// Features are the features this module serves, registered at startup
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Events API", Description: "University events (talks, career days, student events) with an iCalendar feed"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	events := rg.Group("/events")
+	{
+		events.GET("", authMiddleware.RequireToken(FeatureSlug), h.ListEvents)
+		events.GET("/calendar.ics", authMiddleware.RequireToken(FeatureSlug), h.ExportCalendar)
+		events.GET("/:id", authMiddleware.RequireToken(FeatureSlug), h.GetEvent)
+	}
+
+	// Signed-in users submit events for approval and follow their submissions
+	submissions := rg.Group("/events/submissions")
+	submissions.Use(authMiddleware.RequireSession())
+	{
+		submissions.GET("", h.ListSubmissions)
+		submissions.POST("", authMiddleware.Idempotent(), h.PostSubmission)
+	}
+
+	events_admin := rg.Group("/admin/events")
+	events_admin.Use(authMiddleware.RequireSession())
+	events_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	events_admin.Use(authMiddleware.Idempotent())
+	{
+		events_admin.GET("", h.AdminListEvents)
+		events_admin.POST("", h.PostEvent)
+		events_admin.PUT("/:id", h.ReplaceEvent)
+		events_admin.DELETE("/:id", h.DeleteEvent)
+		events_admin.POST("/:id/approve", h.ApproveEvent)
+		events_admin.POST("/:id/reject", h.RejectEvent)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
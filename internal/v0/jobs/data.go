@@ -0,0 +1,204 @@
+package jobs
+
+import (
+	"API/internal/databases"
+	"API/internal/pagination"
+	"API/internal/v0/moderation"
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new jobs repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// jobColumns are the columns scanJob reads, in order
+const jobColumns = `id, title, description, type, location, remote, hours_per_week, COALESCE(pay, ''),
+	employer_name, COALESCE(employer_website, ''), COALESCE(employer_description, ''), contact_email,
+	COALESCE(apply_url, ''), expires_at, submitted_by, ` + moderation.Columns + `, created_at, updated_at`
+
+func scanJob(scan func(dest ...interface{}) error) (Job, error) {
+	var j Job
+	var hours sql.NullInt64
+	dest := []interface{}{&j.ID, &j.Title, &j.Description, &j.Type, &j.Location, &j.Remote, &hours, &j.Pay,
+		&j.Employer.Name, &j.Employer.Website, &j.Employer.Description, &j.ContactEmail,
+		&j.ApplyURL, &j.ExpiresAt, &j.SubmittedBy}
+	dest = append(append(dest, j.Review.Scan()...), &j.CreatedAt, &j.UpdatedAt)
+	err := scan(dest...)
+	if hours.Valid {
+		n := int(hours.Int64)
+		j.HoursPerWeek = &n
+	}
+	return j, err
+}
+
+func (r *Repository) queryJobs(ctx context.Context, query string, args ...interface{}) ([]Job, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []Job{}
+	for rows.Next() {
+		j, err := scanJob(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// GetJob returns a job whatever its status and expiry, or nil when it does not exist
+func (r *Repository) GetJob(ctx context.Context, id int64) (*Job, error) {
+	j, err := scanJob(r.db.QueryRowContext(ctx, "SELECT "+jobColumns+" FROM jobs WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// ListJobs returns a page of jobs matching the filter, newest first. The page holds one
+// extra job when another page follows (see pagination.Next).
+func (r *Repository) ListJobs(ctx context.Context, filter JobFilter, page pagination.Params) ([]Job, error) {
+	where, args := jobFilter(filter)
+	after, afterArgs := page.Where("id")
+	args = append(append(args, afterArgs...), page.FetchLimit())
+	return r.queryJobs(ctx, `
+		SELECT `+jobColumns+` FROM jobs
+		WHERE `+where+` AND `+after+`
+		ORDER BY id DESC
+		LIMIT ?`, args...)
+}
+
+// CountJobs returns the number of jobs matching the filter
+func (r *Repository) CountJobs(ctx context.Context, filter JobFilter) (int, error) {
+	where, args := jobFilter(filter)
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM jobs WHERE "+where, args...).Scan(&count)
+	return count, err
+}
+
+func jobFilter(filter JobFilter) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if !filter.Now.IsZero() {
+		conditions = append(conditions, "expires_at > ?")
+		args = append(args, filter.Now.UTC())
+	}
+	if filter.SubmittedBy != 0 {
+		conditions = append(conditions, "submitted_by = ?")
+		args = append(args, filter.SubmittedBy)
+	}
+	if filter.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, filter.Type)
+	}
+	if filter.Location != "" {
+		conditions = append(conditions, "location = ?")
+		args = append(args, filter.Location)
+	}
+	if filter.Remote {
+		conditions = append(conditions, "remote = 1")
+	}
+	if filter.Employer != "" {
+		conditions = append(conditions, "employer_name = ?")
+		args = append(args, filter.Employer)
+	}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		conditions = append(conditions, "(LOWER(title) LIKE ? OR LOWER(description) LIKE ? OR LOWER(employer_name) LIKE ?)")
+		like := "%" + strings.ToLower(q) + "%"
+		args = append(args, like, like, like)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// CreateJob adds a job with the given status. submittedBy is nil for jobs added by
+// admins.
+func (r *Repository) CreateJob(ctx context.Context, j Job, status moderation.Status, submittedBy *int64) (int64, error) {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO jobs (title, description, type, location, remote, hours_per_week, pay, employer_name,
+			employer_website, employer_description, contact_email, apply_url, expires_at, status, submitted_by,
+			created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, j.Title, j.Description, j.Type, j.Location, j.Remote, j.HoursPerWeek, databases.NullString(j.Pay), j.Employer.Name,
+		databases.NullString(j.Employer.Website), databases.NullString(j.Employer.Description), j.ContactEmail, databases.NullString(j.ApplyURL),
+		j.ExpiresAt.UTC(), status, submittedBy, now, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// ReplaceJob overwrites the details of a job. A submitterID other than 0 limits the
+// change to that user's submissions and sends the job back for approval; admins pass 0
+// and keep its status. It returns false when there is no such job.
+func (r *Repository) ReplaceJob(ctx context.Context, id int64, j Job, submitterID int64) (bool, error) {
+	resubmit := ""
+	if submitterID != 0 {
+		resubmit = ", " + moderation.Resubmit
+	}
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET title = ?, description = ?, type = ?, location = ?, remote = ?, hours_per_week = ?, pay = ?,
+			employer_name = ?, employer_website = ?, employer_description = ?, contact_email = ?, apply_url = ?,
+			expires_at = ?, updated_at = ?`+resubmit+`
+		WHERE id = ? AND (submitted_by = ? OR ? = 0)
+	`, j.Title, j.Description, j.Type, j.Location, j.Remote, j.HoursPerWeek, databases.NullString(j.Pay), j.Employer.Name,
+		databases.NullString(j.Employer.Website), databases.NullString(j.Employer.Description), j.ContactEmail, databases.NullString(j.ApplyURL),
+		j.ExpiresAt.UTC(), time.Now().UTC(), id, submitterID, submitterID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DeleteJob deletes a job. A submitterID other than 0 limits the deletion to that user's
+// submissions. It returns false when there is no such job.
+func (r *Repository) DeleteJob(ctx context.Context, id, submitterID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM jobs WHERE id = ? AND (submitted_by = ? OR ? = 0)", id, submitterID, submitterID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DecideJob approves or rejects a pending job
+func (r *Repository) DecideJob(ctx context.Context, id int64, status moderation.Status, adminID *int64, note string) (*Job, error) {
+	if err := moderation.Decide(ctx, r.db, "jobs", id, status, adminID, note); err != nil {
+		return nil, err
+	}
+	return r.GetJob(ctx, id)
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
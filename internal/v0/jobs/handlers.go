@@ -0,0 +1,396 @@
+package jobs
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/pagination"
+	"API/internal/v0/common"
+	"API/internal/v0/moderation"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the job board from the Repository
+type Handler struct {
+	repo *Repository
+}
+
+func NewHandler(repo *Repository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// parseFilter reads ?type=, ?q=, ?location=, ?remote= and ?employer=. It renders an error
+// with render and returns false when one of them is invalid.
+func parseFilter(c *gin.Context, render func(*gin.Context, int, common.APIResponse)) (JobFilter, bool) {
+	filter := JobFilter{
+		Type:     c.Query("type"),
+		Query:    c.Query("q"),
+		Location: strings.TrimSpace(c.Query("location")),
+		Employer: strings.TrimSpace(c.Query("employer")),
+	}
+	var errs []apierror.Error
+	if filter.Type != "" && !slices.Contains(Types, filter.Type) {
+		errs = append(errs, apierror.Invalid("type", "type must be one of "+strings.Join(Types, ", ")))
+	}
+	if v := c.Query("remote"); v != "" {
+		remote, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, apierror.Invalid("remote", "remote must be true or false"))
+		}
+		filter.Remote = remote
+	}
+	if len(errs) > 0 {
+		render(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return JobFilter{}, false
+	}
+	return filter, true
+}
+
+// ListJobs returns the approved jobs that have not expired matching the filters, newest
+// first
+// GET /jobs?type=&q=&location=&remote=&employer=&limit=&cursor=
+func (h *Handler) ListJobs(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	filter, ok := parseFilter(c, common.Render)
+	if !ok {
+		return
+	}
+	filter.Status = moderation.StatusApproved
+	filter.Now = time.Now()
+
+	jobs, err := h.repo.ListJobs(c.Request.Context(), filter, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list jobs")))
+		return
+	}
+	total, err := h.repo.CountJobs(c.Request.Context(), filter)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count jobs")))
+		return
+	}
+
+	jobs, next := pagination.Next(jobs, page, func(j Job) int64 { return j.ID })
+	for i := range jobs {
+		jobs[i] = jobs[i].Public()
+	}
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"jobs":  jobs,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
+}
+
+// GetJob returns one approved job that has not expired
+// GET /jobs/:id
+func (h *Handler) GetJob(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid job ID")))
+		return
+	}
+	job, err := h.repo.GetJob(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get job")))
+		return
+	}
+	if job == nil || job.Status != moderation.StatusApproved || !job.ExpiresAt.After(time.Now()) {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "job not found")))
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"job": job.Public()}))
+}
+
+// ListSubmissions returns the jobs the current user submitted, expired ones included,
+// newest first, with where each stands in the approval flow
+// GET /jobs/submissions?limit=&cursor=
+func (h *Handler) ListSubmissions(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	filter := JobFilter{SubmittedBy: user.ID}
+	jobs, err := h.repo.ListJobs(c.Request.Context(), filter, page)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list submissions")))
+		return
+	}
+	total, err := h.repo.CountJobs(c.Request.Context(), filter)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count submissions")))
+		return
+	}
+
+	jobs, next := pagination.Next(jobs, page, func(j Job) int64 { return j.ID })
+	common.JSON(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"jobs":  jobs,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
+}
+
+// PostSubmission submits a job, which is listed once an admin approves it
+// POST /jobs/submissions
+func (h *Handler) PostSubmission(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	job, ok := bindJob(c, time.Now())
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateJob(c.Request.Context(), job, moderation.StatusPending, &user.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to submit job")))
+		return
+	}
+	created, _ := h.repo.GetJob(c.Request.Context(), id)
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"job": created}))
+}
+
+// ReplaceSubmission overwrites one of the current user's submissions, e.g. to answer a
+// rejection note, and sends it back for approval
+// PUT /jobs/submissions/:id
+func (h *Handler) ReplaceSubmission(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	h.replaceJob(c, user.ID)
+}
+
+// DeleteSubmission withdraws one of the current user's submissions
+// DELETE /jobs/submissions/:id
+func (h *Handler) DeleteSubmission(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	h.deleteJob(c, user.ID)
+}
+
+// AdminListJobs returns the jobs matching the filters whatever their status and expiry,
+// newest first. ?status=pending lists the submissions waiting for a decision.
+// GET /admin/jobs?status=&type=&q=&location=&remote=&employer=&limit=&cursor=
+func (h *Handler) AdminListJobs(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	filter, ok := parseFilter(c, common.JSON)
+	if !ok {
+		return
+	}
+	if filter.Status, ok = moderation.StatusQuery(c); !ok {
+		return
+	}
+
+	jobs, err := h.repo.ListJobs(c.Request.Context(), filter, page)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list jobs")))
+		return
+	}
+	total, err := h.repo.CountJobs(c.Request.Context(), filter)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count jobs")))
+		return
+	}
+
+	jobs, next := pagination.Next(jobs, page, func(j Job) int64 { return j.ID })
+	common.JSON(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"jobs":  jobs,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
+}
+
+// PostJob adds an approved job
+// POST /admin/jobs
+func (h *Handler) PostJob(c *gin.Context) {
+	job, ok := bindJob(c, time.Now())
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateJob(c.Request.Context(), job, moderation.StatusApproved, nil)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create job")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceJob overwrites the details of a job, keeping its status, e.g. to fix a
+// submission before approving it
+// PUT /admin/jobs/:id
+func (h *Handler) ReplaceJob(c *gin.Context) {
+	h.replaceJob(c, 0)
+}
+
+// DeleteJob deletes a job
+// DELETE /admin/jobs/:id
+func (h *Handler) DeleteJob(c *gin.Context) {
+	h.deleteJob(c, 0)
+}
+
+// ApproveJob lists a pending submission
+// POST /admin/jobs/:id/approve
+func (h *Handler) ApproveJob(c *gin.Context) {
+	h.decideJob(c, moderation.StatusApproved)
+}
+
+// RejectJob turns down a pending submission, with an optional note for the submitter
+// POST /admin/jobs/:id/reject
+func (h *Handler) RejectJob(c *gin.Context) {
+	h.decideJob(c, moderation.StatusRejected)
+}
+
+// replaceJob overwrites a job, limited to the submissions of submitterID unless it is 0
+func (h *Handler) replaceJob(c *gin.Context, submitterID int64) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid job ID")))
+		return
+	}
+	job, ok := bindJob(c, time.Now())
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceJob(c.Request.Context(), id, job, submitterID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update job")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "job not found")))
+		return
+	}
+	updated, _ := h.repo.GetJob(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"job": updated}))
+}
+
+// deleteJob deletes a job, limited to the submissions of submitterID unless it is 0
+func (h *Handler) deleteJob(c *gin.Context, submitterID int64) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid job ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteJob(c.Request.Context(), id, submitterID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete job")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "job not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "job deleted"}))
+}
+
+func (h *Handler) decideJob(c *gin.Context, status moderation.Status) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid job ID")))
+		return
+	}
+	note, adminID, ok := moderation.BindDecision(c)
+	if !ok {
+		return
+	}
+	job, err := h.repo.DecideJob(c.Request.Context(), id, status, adminID, note)
+	if err != nil {
+		moderation.RenderError(c, err, "job")
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"job": job}))
+}
+
+// bindJob binds and validates a job body, working out when the job expires. It renders
+// an error and returns false when the body is invalid.
+func bindJob(c *gin.Context, now time.Time) (Job, bool) {
+	var req JobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Job{}, false
+	}
+	j := Job{
+		Title:        strings.TrimSpace(req.Title),
+		Description:  strings.TrimSpace(req.Description),
+		Type:         req.Type,
+		Location:     strings.TrimSpace(req.Location),
+		Remote:       req.Remote,
+		HoursPerWeek: req.HoursPerWeek,
+		Pay:          strings.TrimSpace(req.Pay),
+		Employer: Employer{
+			Name:        strings.TrimSpace(req.Employer.Name),
+			Website:     strings.TrimSpace(req.Employer.Website),
+			Description: strings.TrimSpace(req.Employer.Description),
+		},
+		ContactEmail: strings.TrimSpace(req.ContactEmail),
+		ApplyURL:     strings.TrimSpace(req.ApplyURL),
+	}
+
+	var errs []apierror.Error
+	if j.Title == "" {
+		errs = append(errs, apierror.Invalid("title", "title is required"))
+	}
+	if j.Description == "" {
+		errs = append(errs, apierror.Invalid("description", "description is required"))
+	}
+	if j.Location == "" {
+		errs = append(errs, apierror.Invalid("location", "location is required"))
+	}
+	if j.Employer.Name == "" {
+		errs = append(errs, apierror.Invalid("employer.name", "employer name is required"))
+	}
+	if req.ExpiresAt != nil {
+		j.ExpiresAt = req.ExpiresAt.Truncate(time.Second)
+		if !j.ExpiresAt.After(now) {
+			errs = append(errs, apierror.Invalid("expires_at", "expires_at must be in the future"))
+		} else if j.ExpiresAt.After(now.Add(MaxLifetime)) {
+			errs = append(errs, apierror.Invalid("expires_at", "expires_at must be within 90 days"))
+		}
+	} else {
+		j.ExpiresAt = now.Add(DefaultLifetime).Truncate(time.Second)
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Job{}, false
+	}
+	return j, true
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"time"
+
+	"API/internal/v0/moderation"
+)
+
+// Types are the kinds of employment a job offers
+var Types = []string{"part-time", "full-time", "seasonal", "freelance"}
+
+const (
+	// DefaultLifetime is how long a job without an expiry stays listed
+	DefaultLifetime = 30 * 24 * time.Hour
+
+	// MaxLifetime caps expires_at, so the board does not fill with stale openings
+	MaxLifetime = 90 * 24 * time.Hour
+)
+
+// Employer is who offers a job
+type Employer struct {
+	Name        string `json:"name"`
+	Website     string `json:"website,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Job is a part-time or student position. Jobs submitted by users are listed once an
+// admin approves them; jobs added by admins are approved right away.
+type Job struct {
+	ID           int64     `json:"id"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Type         string    `json:"type"`
+	Location     string    `json:"location"`
+	Remote       bool      `json:"remote"`
+	HoursPerWeek *int      `json:"hours_per_week,omitempty"`
+	Pay          string    `json:"pay,omitempty"`
+	Employer     Employer  `json:"employer"`
+	ContactEmail string    `json:"contact_email"`
+	ApplyURL     string    `json:"apply_url,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+
+	// Approval details, left out of public listings
+	SubmittedBy *int64 `json:"submitted_by,omitempty"`
+	moderation.Review
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Public returns the job without its approval details
+func (j Job) Public() Job {
+	j.SubmittedBy = nil
+	j.Review = moderation.Review{}
+	return j
+}
+
+// EmployerRequest is the employer of a JobRequest
+type EmployerRequest struct {
+	Name        string `json:"name" binding:"required,max=200"`
+	Website     string `json:"website" binding:"omitempty,url,max=500"`
+	Description string `json:"description" binding:"max=2000"`
+}
+
+// JobRequest is the body of a job submission, or of a job added or replaced by an
+// admin. Without expires_at, a job expires DefaultLifetime after it is sent.
+type JobRequest struct {
+	Title        string          `json:"title" binding:"required,max=200"`
+	Description  string          `json:"description" binding:"required,max=10000"`
+	Type         string          `json:"type" binding:"required,oneof=part-time full-time seasonal freelance"`
+	Location     string          `json:"location" binding:"required,max=200"`
+	Remote       bool            `json:"remote"`
+	HoursPerWeek *int            `json:"hours_per_week" binding:"omitempty,min=1,max=60"`
+	Pay          string          `json:"pay" binding:"max=100"`
+	Employer     EmployerRequest `json:"employer" binding:"required"`
+	ContactEmail string          `json:"contact_email" binding:"required,email,max=200"`
+	ApplyURL     string          `json:"apply_url" binding:"omitempty,url,max=500"`
+	ExpiresAt    *time.Time      `json:"expires_at"`
+}
+
+// JobFilter narrows a job listing; zero fields match every job
+type JobFilter struct {
+	Type     string
+	Query    string // part of the title, description or employer name
+	Location string
+	Remote   bool   // remote jobs only
+	Employer string // the employer's name
+	Status   moderation.Status
+	Now      time.Time // jobs that have not expired by Now, when not zero
+	// SubmittedBy narrows the listing to one user's submissions when not zero
+	SubmittedBy int64
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+const FeatureSlug = "jobs"
+
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Jobs API", Description: "Part-time and student job openings, approved by admins"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	jobs := rg.Group("/jobs")
+	{
+		jobs.GET("", authMiddleware.RequireToken(FeatureSlug), h.ListJobs)
+		jobs.GET("/:id", authMiddleware.RequireToken(FeatureSlug), h.GetJob)
+	}
+
+	// Signed-in users (e.g. employers) submit jobs for approval and follow their submissions
+	submissions := rg.Group("/jobs/submissions")
+	submissions.Use(authMiddleware.RequireSession())
+	submissions.Use(authMiddleware.Idempotent())
+	{
+		submissions.GET("", h.ListSubmissions)
+		submissions.POST("", h.PostSubmission)
+		submissions.PUT("/:id", h.ReplaceSubmission)
+		submissions.DELETE("/:id", h.DeleteSubmission)
+	}
+
+	jobs_admin := rg.Group("/admin/jobs")
+	jobs_admin.Use(authMiddleware.RequireSession())
+	jobs_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	jobs_admin.Use(authMiddleware.Idempotent())
+	{
+		jobs_admin.GET("", h.AdminListJobs)
+		jobs_admin.POST("", h.PostJob)
+		jobs_admin.PUT("/:id", h.ReplaceJob)
+		jobs_admin.DELETE("/:id", h.DeleteJob)
+		jobs_admin.POST("/:id/approve", h.ApproveJob)
+		jobs_admin.POST("/:id/reject", h.RejectJob)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
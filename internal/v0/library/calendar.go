@@ -0,0 +1,79 @@
+package library
+
+import (
+	"API/internal/calendar"
+	"context"
+	"fmt"
+	"time"
+)
+
+// CalendarReservations is the name of the calendar of a user's study room reservations
+// (see calendar.Collection)
+const CalendarReservations = "library-reservations"
+
+// Calendars returns what the library publishes for calendar apps: the study room
+// reservations of the token's user
+func Calendars(repo *Repository) []calendar.Collection {
+	return []calendar.Collection{
+		{
+			Name:    CalendarReservations,
+			Feature: ReservationsFeatureSlug,
+			Color:   "#2E7D32",
+			Title: func(lang string) string {
+				if lang == LanguageEnglish {
+					return "Study room reservations"
+				}
+				return "Κρατήσεις αιθουσών μελέτης"
+			},
+			Entries: func(ctx context.Context, userID int64, lang string, from, to time.Time) ([]calendar.Entry, error) {
+				reservations, err := repo.ListUserReservations(ctx, userID, from)
+				if err != nil {
+					return nil, err
+				}
+				libraries, err := repo.ListLibraries(ctx)
+				if err != nil {
+					return nil, err
+				}
+				names := make(map[int64]string, len(libraries))
+				for _, l := range libraries {
+					names[l.ID] = l.Localized(lang).Name
+				}
+				summary := "Αίθουσα μελέτης %s"
+				if lang == LanguageEnglish {
+					summary = "Study room %s"
+				}
+
+				entries := []calendar.Entry{}
+				for _, r := range reservations {
+					if !r.StartsAt.Before(to) {
+						break
+					}
+					entries = append(entries, calendar.Entry{
+						UID:      fmt.Sprintf("reservation-%d", r.ID),
+						Summary:  fmt.Sprintf(summary, r.Room),
+						Location: names[r.LibraryID],
+						Start:    r.StartsAt,
+						End:      r.EndsAt,
+						Modified: r.CreatedAt,
+					})
+				}
+				return entries, nil
+			},
+		},
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
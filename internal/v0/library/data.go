@@ -0,0 +1,519 @@
+package library
+
+import (
+	"API/internal/campustime"
+	"API/internal/databases"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrDuplicateLibrary is returned when another library has the same code
+	ErrDuplicateLibrary = errors.New("another library has the same code")
+
+	// ErrDuplicateRoom is returned when the library has another room with the same name
+	ErrDuplicateRoom = errors.New("the library has another room with the same name")
+
+	// ErrUnknownLibrary is returned for rooms of a library that does not exist
+	ErrUnknownLibrary = errors.New("library not found")
+
+	// ErrRoomTaken is returned when a reservation overlaps another one of the same room
+	ErrRoomTaken = errors.New("the room is already reserved at that time")
+
+	// ErrBookingLimit is returned when a user already holds as many reservations as allowed
+	ErrBookingLimit = errors.New("too many active reservations")
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new library repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// libraryColumns are the columns scanLibrary reads, in order
+const libraryColumns = "id, code, name, COALESCE(name_en, ''), seats, occupied, occupancy_updated_at"
+
+func scanLibrary(scan func(dest ...interface{}) error) (Library, error) {
+	l := Library{Hours: []campustime.OpeningHours{}}
+	var occupied sql.NullInt64
+	var updatedAt sql.NullTime
+	if err := scan(&l.ID, &l.Code, &l.Name, &l.NameEn, &l.Seats, &occupied, &updatedAt); err != nil {
+		return l, err
+	}
+	if occupied.Valid && updatedAt.Valid {
+		o := Occupancy{Occupied: int(occupied.Int64), UpdatedAt: updatedAt.Time}
+		o.Free = max(l.Seats-o.Occupied, 0)
+		if l.Seats > 0 {
+			o.Percent = min(o.Occupied*100/l.Seats, 100)
+		}
+		l.Occupancy = &o
+	}
+	return l, nil
+}
+
+// ListLibraries returns every library with its hours and occupancy, by name
+func (r *Repository) ListLibraries(ctx context.Context) ([]Library, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+libraryColumns+" FROM libraries ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	libraries := []Library{}
+	for rows.Next() {
+		l, err := scanLibrary(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		libraries = append(libraries, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return libraries, r.attachHours(ctx, libraries)
+}
+
+// GetLibrary returns a library with its hours and occupancy, or nil when it does not exist
+func (r *Repository) GetLibrary(ctx context.Context, id int64) (*Library, error) {
+	l, err := scanLibrary(r.db.QueryRowContext(ctx, "SELECT "+libraryColumns+" FROM libraries WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	libraries := []Library{l}
+	if err := r.attachHours(ctx, libraries); err != nil {
+		return nil, err
+	}
+	return &libraries[0], nil
+}
+
+// attachHours loads the opening hours of libraries, Monday first
+func (r *Repository) attachHours(ctx context.Context, libraries []Library) error {
+	if len(libraries) == 0 {
+		return nil
+	}
+	byID := make(map[int64]int, len(libraries))
+	placeholders := make([]string, 0, len(libraries))
+	args := make([]interface{}, 0, len(libraries))
+	for i, l := range libraries {
+		byID[l.ID] = i
+		placeholders = append(placeholders, "?")
+		args = append(args, l.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT library_id, weekday, opens_at, closes_at FROM library_hours
+		WHERE library_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY library_id, (weekday + 6) % 7, opens_at`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var libraryID int64
+		var weekday int
+		var h campustime.OpeningHours
+		if err := rows.Scan(&libraryID, &weekday, &h.Opens, &h.Closes); err != nil {
+			return err
+		}
+		h.Day = campustime.Weekdays[weekday]
+		libraries[byID[libraryID]].Hours = append(libraries[byID[libraryID]].Hours, h)
+	}
+	return rows.Err()
+}
+
+// libraryCodeTaken reports whether a library other than id has the code
+func libraryCodeTaken(ctx context.Context, tx *sql.Tx, code string, id int64) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM libraries WHERE code = ? AND id != ?)", code, id).Scan(&exists)
+	return exists, err
+}
+
+// writeHours replaces the opening hours of a library
+func writeHours(ctx context.Context, tx *sql.Tx, id int64, hours []campustime.OpeningHours) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM library_hours WHERE library_id = ?", id); err != nil {
+		return err
+	}
+	for _, h := range hours {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO library_hours (library_id, weekday, opens_at, closes_at) VALUES (?, ?, ?, ?)
+		`, id, slices.Index(campustime.Weekdays[:], h.Day), h.Opens, h.Closes)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateLibrary adds a library with its opening hours
+func (r *Repository) CreateLibrary(ctx context.Context, l Library) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if taken, err := libraryCodeTaken(ctx, tx, l.Code, 0); err != nil || taken {
+			if taken {
+				return ErrDuplicateLibrary
+			}
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO libraries (code, name, name_en, seats) VALUES (?, ?, ?, ?)
+		`, l.Code, l.Name, databases.NullString(l.NameEn), l.Seats)
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		return writeHours(ctx, tx, id, l.Hours)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplaceLibrary overwrites a library and its opening hours, keeping its occupancy. It
+// returns false when the library does not exist.
+func (r *Repository) ReplaceLibrary(ctx context.Context, id int64, l Library) (bool, error) {
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if taken, err := libraryCodeTaken(ctx, tx, l.Code, id); err != nil || taken {
+			if taken {
+				return ErrDuplicateLibrary
+			}
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE libraries SET code = ?, name = ?, name_en = ?, seats = ? WHERE id = ?
+		`, l.Code, l.Name, databases.NullString(l.NameEn), l.Seats, id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		replaced = true
+		return writeHours(ctx, tx, id, l.Hours)
+	})
+	return replaced, err
+}
+
+// DeleteLibrary deletes a library with its hours, rooms and their reservations. It
+// returns false when the library does not exist.
+func (r *Repository) DeleteLibrary(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, query := range []string{
+			"DELETE FROM room_reservations WHERE room_id IN (SELECT id FROM study_rooms WHERE library_id = ?)",
+			"DELETE FROM study_rooms WHERE library_id = ?",
+			"DELETE FROM library_hours WHERE library_id = ?",
+		} {
+			if _, err := tx.ExecContext(ctx, query, id); err != nil {
+				return err
+			}
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM libraries WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+// SetOccupancy records the number of taken seats of a library as of now. It returns
+// false when the library does not exist.
+func (r *Repository) SetOccupancy(ctx context.Context, id int64, occupied int, now time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE libraries SET occupied = ?, occupancy_updated_at = ? WHERE id = ?
+	`, occupied, now.UTC(), id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListRooms returns the study rooms of a library by name, each with the reservations that
+// overlap [from, to)
+func (r *Repository) ListRooms(ctx context.Context, libraryID int64, from, to time.Time) ([]Room, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, library_id, name, COALESCE(name_en, ''), capacity FROM study_rooms
+		WHERE library_id = ?
+		ORDER BY name
+	`, libraryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := []Room{}
+	byID := map[int64]int{}
+	for rows.Next() {
+		room := Room{Booked: []Period{}}
+		if err := rows.Scan(&room.ID, &room.LibraryID, &room.Name, &room.NameEn, &room.Capacity); err != nil {
+			return nil, err
+		}
+		byID[room.ID] = len(rooms)
+		rooms = append(rooms, room)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	booked, err := r.db.QueryContext(ctx, `
+		SELECT rr.room_id, rr.starts_at, rr.ends_at FROM room_reservations rr
+		JOIN study_rooms sr ON sr.id = rr.room_id
+		WHERE sr.library_id = ? AND rr.cancelled_at IS NULL AND rr.starts_at < ? AND rr.ends_at > ?
+		ORDER BY rr.starts_at
+	`, libraryID, to.UTC(), from.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer booked.Close()
+	for booked.Next() {
+		var roomID int64
+		var p Period
+		if err := booked.Scan(&roomID, &p.StartsAt, &p.EndsAt); err != nil {
+			return nil, err
+		}
+		if i, ok := byID[roomID]; ok {
+			rooms[i].Booked = append(rooms[i].Booked, p)
+		}
+	}
+	return rooms, booked.Err()
+}
+
+// GetRoom returns a study room, or nil when it does not exist
+func (r *Repository) GetRoom(ctx context.Context, id int64) (*Room, error) {
+	var room Room
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, library_id, name, COALESCE(name_en, ''), capacity FROM study_rooms WHERE id = ?
+	`, id).Scan(&room.ID, &room.LibraryID, &room.Name, &room.NameEn, &room.Capacity)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// checkRoom returns ErrUnknownLibrary or ErrDuplicateRoom when room cannot be stored
+// under id
+func checkRoom(ctx context.Context, tx *sql.Tx, room Room, id int64) error {
+	var libraryExists, nameTaken bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM libraries WHERE id = ?),
+			EXISTS (SELECT 1 FROM study_rooms WHERE library_id = ? AND name = ? AND id != ?)
+	`, room.LibraryID, room.LibraryID, room.Name, id).Scan(&libraryExists, &nameTaken)
+	switch {
+	case err != nil:
+		return err
+	case !libraryExists:
+		return ErrUnknownLibrary
+	case nameTaken:
+		return ErrDuplicateRoom
+	}
+	return nil
+}
+
+// CreateRoom adds a study room to a library
+func (r *Repository) CreateRoom(ctx context.Context, room Room) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkRoom(ctx, tx, room, 0); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO study_rooms (library_id, name, name_en, capacity) VALUES (?, ?, ?, ?)
+		`, room.LibraryID, room.Name, databases.NullString(room.NameEn), room.Capacity)
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplaceRoom overwrites a study room, keeping its reservations. It returns false when
+// the room does not exist.
+func (r *Repository) ReplaceRoom(ctx context.Context, id int64, room Room) (bool, error) {
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkRoom(ctx, tx, room, id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE study_rooms SET library_id = ?, name = ?, name_en = ?, capacity = ? WHERE id = ?
+		`, room.LibraryID, room.Name, databases.NullString(room.NameEn), room.Capacity, id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		replaced = n > 0
+		return err
+	})
+	return replaced, err
+}
+
+// DeleteRoom deletes a study room and its reservations. It returns false when the room
+// does not exist.
+func (r *Repository) DeleteRoom(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM room_reservations WHERE room_id = ?", id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM study_rooms WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+// reservationColumns are the columns scanReservation reads, in order
+const reservationColumns = `rr.id, rr.room_id, sr.name, sr.library_id, rr.user_id, rr.starts_at, rr.ends_at,
+	rr.created_at, rr.cancelled_at`
+
+func scanReservation(scan func(dest ...interface{}) error) (Reservation, error) {
+	var res Reservation
+	var cancelledAt sql.NullTime
+	err := scan(&res.ID, &res.RoomID, &res.Room, &res.LibraryID, &res.UserID, &res.StartsAt, &res.EndsAt,
+		&res.CreatedAt, &cancelledAt)
+	if cancelledAt.Valid {
+		res.CancelledAt = &cancelledAt.Time
+	}
+	return res, err
+}
+
+// getReservation returns a reservation, or nil when it does not exist
+func (r *Repository) getReservation(ctx context.Context, id int64) (*Reservation, error) {
+	res, err := scanReservation(r.db.QueryRowContext(ctx, `
+		SELECT `+reservationColumns+` FROM room_reservations rr
+		JOIN study_rooms sr ON sr.id = rr.room_id
+		WHERE rr.id = ?
+	`, id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListUserReservations returns the reservations of a user that have not ended by now, in
+// the order they start
+func (r *Repository) ListUserReservations(ctx context.Context, userID int64, now time.Time) ([]Reservation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reservationColumns+` FROM room_reservations rr
+		JOIN study_rooms sr ON sr.id = rr.room_id
+		WHERE rr.user_id = ? AND rr.cancelled_at IS NULL AND rr.ends_at > ?
+		ORDER BY rr.starts_at
+	`, userID, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservations := []Reservation{}
+	for rows.Next() {
+		res, err := scanReservation(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, res)
+	}
+	return reservations, rows.Err()
+}
+
+// Reserve books a study room for a user, unless the room is taken at that time or the
+// user already holds maxActive reservations that have not ended by now
+func (r *Repository) Reserve(ctx context.Context, userID int64, req ReservationRequest, maxActive int, now time.Time) (*Reservation, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		var active int
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM room_reservations WHERE user_id = ? AND cancelled_at IS NULL AND ends_at > ?
+		`, userID, now.UTC()).Scan(&active); err != nil {
+			return err
+		}
+		if active >= maxActive {
+			return ErrBookingLimit
+		}
+
+		// The overlap check is part of the insert, so concurrent bookings cannot both win
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO room_reservations (room_id, user_id, starts_at, ends_at, created_at)
+			SELECT ?, ?, ?, ?, ?
+			WHERE NOT EXISTS (
+				SELECT 1 FROM room_reservations
+				WHERE room_id = ? AND cancelled_at IS NULL AND starts_at < ? AND ends_at > ?
+			)
+		`, req.RoomID, userID, req.StartsAt.UTC(), req.EndsAt.UTC(), now.UTC(), req.RoomID, req.EndsAt.UTC(), req.StartsAt.UTC())
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return ErrRoomTaken
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.getReservation(ctx, id)
+}
+
+// CancelReservation cancels a reservation of a user that has not ended by now. It
+// returns false when the user has no such reservation.
+func (r *Repository) CancelReservation(ctx context.Context, id, userID int64, now time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE room_reservations SET cancelled_at = ?
+		WHERE id = ? AND user_id = ? AND cancelled_at IS NULL AND ends_at > ?
+	`, now.UTC(), id, userID, now.UTC())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
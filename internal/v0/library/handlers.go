@@ -0,0 +1,491 @@
+package library
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/campustime"
+	"API/internal/realtime"
+	"API/internal/v0/common"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the library from the Repository
+type Handler struct {
+	repo   *Repository
+	limits BookingLimits
+	hub    *realtime.Hub // Told about occupancy and library changes; nil without live updates
+}
+
+func NewHandler(repo *Repository) *Handler {
+	return &Handler{repo: repo, limits: DefaultBookingLimits}
+}
+
+// SetBookingLimits replaces DefaultBookingLimits
+func (h *Handler) SetBookingLimits(limits BookingLimits) {
+	h.limits = limits
+}
+
+// SetHub sends occupancy reports and library changes to the live updates at once, instead
+// of at the hub's next poll
+func (h *Handler) SetHub(hub *realtime.Hub) {
+	h.hub = hub
+}
+
+// present localizes libraries and marks which of them are open now
+func present(libraries []Library, lang string, now time.Time) {
+	for i := range libraries {
+		libraries[i] = libraries[i].Localized(lang)
+		if len(libraries[i].Hours) > 0 {
+			open := campustime.IsOpen(libraries[i].Hours, now)
+			libraries[i].OpenNow = &open
+		}
+	}
+}
+
+// GetLibraries returns every library with its opening hours and current occupancy
+// GET /library/libraries?lang=
+func (h *Handler) GetLibraries(c *gin.Context) {
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	libraries, err := h.repo.ListLibraries(c.Request.Context())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list libraries")))
+		return
+	}
+	present(libraries, lang, time.Now())
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"libraries": libraries}))
+}
+
+// GetLibrary returns one library with its opening hours and current occupancy
+// GET /library/libraries/:id?lang=
+func (h *Handler) GetLibrary(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid library ID")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	library, err := h.repo.GetLibrary(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get library")))
+		return
+	}
+	if library == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "library not found")))
+		return
+	}
+	libraries := []Library{*library}
+	present(libraries, lang, time.Now())
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"library": libraries[0]}))
+}
+
+// GetRooms returns the study rooms of a library with the periods they are booked on a
+// day, today by default
+// GET /library/libraries/:id/rooms?date=&lang=
+func (h *Handler) GetRooms(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid library ID")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	day := campustime.Today()
+	if v := c.Query("date"); v != "" {
+		if day, err = time.ParseInLocation("2006-01-02", v, campustime.Location); err != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("date", "date must be YYYY-MM-DD")))
+			return
+		}
+	}
+
+	library, err := h.repo.GetLibrary(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get library")))
+		return
+	}
+	if library == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "library not found")))
+		return
+	}
+	rooms, err := h.repo.ListRooms(c.Request.Context(), id, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list rooms")))
+		return
+	}
+	for i := range rooms {
+		rooms[i] = rooms[i].Localized(lang)
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"date":  day.Format("2006-01-02"),
+		"hours": library.Hours,
+		"rooms": rooms,
+	}))
+}
+
+// PutOccupancy records the number of taken seats reported by a library's gate counters
+// PUT /library/libraries/:id/occupancy
+func (h *Handler) PutOccupancy(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid library ID")))
+		return
+	}
+	var report OccupancyReport
+	if err := c.ShouldBindJSON(&report); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	updated, err := h.repo.SetOccupancy(c.Request.Context(), id, *report.Occupied, time.Now())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to record occupancy")))
+		return
+	}
+	if !updated {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "library not found")))
+		return
+	}
+	h.hub.Notify()
+	library, _ := h.repo.GetLibrary(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"occupancy": library.Occupancy}))
+}
+
+// ListReservations returns the calling user's reservations that have not ended yet
+// GET /library/reservations
+func (h *Handler) ListReservations(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+	reservations, err := h.repo.ListUserReservations(c.Request.Context(), user.ID, time.Now())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list reservations")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"reservations": reservations,
+		"max_active":   h.limits.MaxActive,
+	}))
+}
+
+// PostReservation books a study room for the calling user, within the library's opening
+// hours and the BookingLimits
+// POST /library/reservations
+func (h *Handler) PostReservation(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+	var req ReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	req.StartsAt, req.EndsAt = req.StartsAt.Truncate(time.Minute), req.EndsAt.Truncate(time.Minute)
+	now := time.Now()
+	var errs []apierror.Error
+	switch {
+	case !req.EndsAt.After(req.StartsAt):
+		errs = append(errs, apierror.Invalid("ends_at", "ends_at must be after starts_at"))
+	case req.EndsAt.Sub(req.StartsAt) > h.limits.MaxDuration:
+		errs = append(errs, apierror.Invalid("ends_at", fmt.Sprintf("reservations last at most %s", h.limits.MaxDuration)))
+	}
+	switch {
+	case req.StartsAt.Before(now.Truncate(time.Minute)):
+		errs = append(errs, apierror.Invalid("starts_at", "starts_at must not be in the past"))
+	case req.StartsAt.After(now.Add(h.limits.Window)):
+		errs = append(errs, apierror.Invalid("starts_at", fmt.Sprintf("reservations start at most %s ahead", h.limits.Window)))
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+
+	room, err := h.repo.GetRoom(c.Request.Context(), req.RoomID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get room")))
+		return
+	}
+	if room == nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("room_id", "room not found")))
+		return
+	}
+	library, err := h.repo.GetLibrary(c.Request.Context(), room.LibraryID)
+	if err != nil || library == nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get library")))
+		return
+	}
+	if !covers(library.Hours, req.StartsAt, req.EndsAt) {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("starts_at", "the library is not open for the whole reservation")))
+		return
+	}
+
+	reservation, err := h.repo.Reserve(c.Request.Context(), user.ID, req, h.limits.MaxActive, now)
+	switch {
+	case errors.Is(err, ErrRoomTaken):
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+	case errors.Is(err, ErrBookingLimit):
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, fmt.Sprintf("%s: at most %d at a time", err, h.limits.MaxActive))))
+	case err != nil:
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to reserve room")))
+	default:
+		common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"reservation": reservation}))
+	}
+}
+
+// DeleteReservation cancels one of the calling user's reservations that has not ended
+// DELETE /library/reservations/:id
+func (h *Handler) DeleteReservation(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid reservation ID")))
+		return
+	}
+	cancelled, err := h.repo.CancelReservation(c.Request.Context(), id, user.ID, time.Now())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to cancel reservation")))
+		return
+	}
+	if !cancelled {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "reservation not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "reservation cancelled"}))
+}
+
+// ListLibraries returns every library with its translations and occupancy
+// GET /admin/library/libraries
+func (h *Handler) ListLibraries(c *gin.Context) {
+	libraries, err := h.repo.ListLibraries(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list libraries")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"libraries": libraries}))
+}
+
+// PostLibrary adds a library with its opening hours
+// POST /admin/library/libraries
+func (h *Handler) PostLibrary(c *gin.Context) {
+	library, ok := bindLibrary(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateLibrary(c.Request.Context(), library)
+	if errors.Is(err, ErrDuplicateLibrary) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create library")))
+		return
+	}
+	h.hub.Notify()
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceLibrary overwrites a library and its opening hours
+// PUT /admin/library/libraries/:id
+func (h *Handler) ReplaceLibrary(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid library ID")))
+		return
+	}
+	library, ok := bindLibrary(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceLibrary(c.Request.Context(), id, library)
+	if errors.Is(err, ErrDuplicateLibrary) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update library")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "library not found")))
+		return
+	}
+	h.hub.Notify()
+	updated, _ := h.repo.GetLibrary(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"library": updated}))
+}
+
+// DeleteLibrary deletes a library with its rooms and their reservations
+// DELETE /admin/library/libraries/:id
+func (h *Handler) DeleteLibrary(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid library ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteLibrary(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete library")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "library not found")))
+		return
+	}
+	h.hub.Notify()
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "library deleted"}))
+}
+
+// bindLibrary binds and validates a library body. It renders an error and returns false
+// when the body is invalid.
+func bindLibrary(c *gin.Context) (Library, bool) {
+	var library Library
+	if err := c.ShouldBindJSON(&library); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Library{}, false
+	}
+	library.Code = strings.ToUpper(strings.TrimSpace(library.Code))
+	library.Name = strings.TrimSpace(library.Name)
+	library.NameEn = strings.TrimSpace(library.NameEn)
+	library.Occupancy, library.OpenNow = nil, nil
+	if library.Hours == nil {
+		library.Hours = []campustime.OpeningHours{}
+	}
+	var errs []apierror.Error
+	if library.Code == "" {
+		errs = append(errs, apierror.Invalid("code", "code is required"))
+	}
+	if library.Name == "" {
+		errs = append(errs, apierror.Invalid("name", "name is required"))
+	}
+	errs = append(errs, campustime.ValidateHours(library.Hours)...)
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Library{}, false
+	}
+	return library, true
+}
+
+// PostRoom adds a study room to a library
+// POST /admin/library/rooms
+func (h *Handler) PostRoom(c *gin.Context) {
+	room, ok := bindRoom(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateRoom(c.Request.Context(), room)
+	if !renderRoomError(c, err, "failed to create room") {
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceRoom overwrites a study room
+// PUT /admin/library/rooms/:id
+func (h *Handler) ReplaceRoom(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid room ID")))
+		return
+	}
+	room, ok := bindRoom(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceRoom(c.Request.Context(), id, room)
+	if !renderRoomError(c, err, "failed to update room") {
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "room not found")))
+		return
+	}
+	room.ID = id
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"room": room}))
+}
+
+// DeleteRoom deletes a study room and its reservations
+// DELETE /admin/library/rooms/:id
+func (h *Handler) DeleteRoom(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid room ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteRoom(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete room")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "room not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "room deleted"}))
+}
+
+// bindRoom binds and validates a study room body. It renders an error and returns false
+// when the body is invalid.
+func bindRoom(c *gin.Context) (Room, bool) {
+	var room Room
+	if err := c.ShouldBindJSON(&room); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Room{}, false
+	}
+	room.Name, room.NameEn = strings.TrimSpace(room.Name), strings.TrimSpace(room.NameEn)
+	room.Booked = nil
+	if room.Name == "" {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("name", "name is required")))
+		return Room{}, false
+	}
+	return room, true
+}
+
+// renderRoomError renders the error of a room write, returning true when there was none
+func renderRoomError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrUnknownLibrary):
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("library_id", err.Error())))
+	case errors.Is(err, ErrDuplicateRoom):
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+	default:
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, message)))
+	}
+	return false
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
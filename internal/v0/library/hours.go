@@ -0,0 +1,38 @@
+package library
+
+import (
+	"slices"
+	"time"
+
+	"API/internal/campustime"
+)
+
+// covers reports whether one period of hours spans all of [start, end), so that a
+// reservation does not run past closing time
+func covers(hours []campustime.OpeningHours, start, end time.Time) bool {
+	start, end = start.In(campustime.Location), end.In(campustime.Location)
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+	if y1 != y2 || m1 != m2 || d1 != d2 {
+		return false
+	}
+	day, from, to := campustime.Weekdays[start.Weekday()], start.Format("15:04"), end.Format("15:04")
+	return slices.ContainsFunc(hours, func(h campustime.OpeningHours) bool {
+		return h.Day == day && h.Opens <= from && to <= h.Closes
+	})
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
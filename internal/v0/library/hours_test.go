@@ -0,0 +1,42 @@
+package library
+
+import (
+	"testing"
+	"time"
+
+	"API/internal/campustime"
+)
+
+func TestCovers(t *testing.T) {
+	hours := []campustime.OpeningHours{
+		{Day: "monday", Opens: "08:00", Closes: "14:00"},
+		{Day: "monday", Opens: "16:00", Closes: "22:00"},
+		{Day: "tuesday", Opens: "08:00", Closes: "22:00"},
+	}
+	// May 6th 2024 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.May, day, hour, minute, 0, 0, campustime.Location)
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{"within a period", at(6, 9, 0), at(6, 11, 0), true},
+		{"the whole period", at(6, 8, 0), at(6, 14, 0), true},
+		{"before opening", at(6, 7, 30), at(6, 9, 0), false},
+		{"past closing", at(6, 13, 0), at(6, 15, 0), false},
+		{"across a break", at(6, 13, 0), at(6, 17, 0), false},
+		{"on a closed day", at(8, 9, 0), at(8, 10, 0), false},
+		{"across midnight", at(7, 21, 0), at(8, 1, 0), false},
+		{"in campus time", at(6, 9, 0).UTC(), at(6, 10, 0).UTC(), true},
+		{"9:00 UTC is noon on campus", time.Date(2024, time.May, 6, 9, 0, 0, 0, time.UTC), time.Date(2024, time.May, 6, 11, 30, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := covers(hours, tt.start, tt.end); got != tt.want {
+				t.Errorf("covers(%s, %s) = %t, want %t", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
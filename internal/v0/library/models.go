@@ -0,0 +1,120 @@
+package library
+
+import (
+	"time"
+
+	"API/internal/campustime"
+	"API/internal/v0/common"
+)
+
+// Languages the library is served in, the original Greek first
+const (
+	LanguageGreek   = "el"
+	LanguageEnglish = "en"
+)
+
+var Languages = []string{LanguageGreek, LanguageEnglish}
+
+// Library is a library branch with its opening hours and seat occupancy
+type Library struct {
+	ID     int64                     `json:"id"`
+	Code   string                    `json:"code" binding:"required,max=20"`
+	Name   string                    `json:"name" binding:"required,max=200"`
+	NameEn string                    `json:"name_en,omitempty" binding:"max=200"`
+	Seats  int                       `json:"seats" binding:"min=0"`
+	Hours  []campustime.OpeningHours `json:"hours" binding:"max=50,dive"`
+
+	// Occupancy and OpenNow are computed for responses
+	Occupancy *Occupancy `json:"occupancy,omitempty"`
+	OpenNow   *bool      `json:"open_now,omitempty"`
+}
+
+// Occupancy is how many seats of a library were taken at the last report
+type Occupancy struct {
+	Occupied  int       `json:"occupied"`
+	Free      int       `json:"free"`
+	Percent   int       `json:"percent"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Localized returns the library in lang, falling back to the Greek original, with the
+// translation fields left out
+func (l Library) Localized(lang string) Library {
+	common.Localize(lang, &l.Name, &l.NameEn)
+	return l
+}
+
+// OccupancyReport is what the gate counters send to the ingest endpoint
+type OccupancyReport struct {
+	Occupied *int `json:"occupied" binding:"required,min=0"`
+}
+
+// Room is a study room users can reserve
+type Room struct {
+	ID        int64  `json:"id"`
+	LibraryID int64  `json:"library_id" binding:"required"`
+	Name      string `json:"name" binding:"required,max=100"`
+	NameEn    string `json:"name_en,omitempty" binding:"max=100"`
+	Capacity  int    `json:"capacity" binding:"required,min=1,max=100"`
+
+	// Booked lists the reserved periods of the day asked for, in availability listings
+	Booked []Period `json:"booked,omitempty"`
+}
+
+// Localized returns the room in lang, falling back to the Greek original, with the
+// translation fields left out
+func (r Room) Localized(lang string) Room {
+	common.Localize(lang, &r.Name, &r.NameEn)
+	return r
+}
+
+// Period is a span of time a room is reserved for
+type Period struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// Reservation is a user's booking of a study room
+type Reservation struct {
+	ID          int64      `json:"id"`
+	RoomID      int64      `json:"room_id"`
+	Room        string     `json:"room"`
+	LibraryID   int64      `json:"library_id"`
+	UserID      int64      `json:"-"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+// ReservationRequest is the body of a new reservation
+type ReservationRequest struct {
+	RoomID   int64     `json:"room_id" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+}
+
+// BookingLimits bound what a single user can reserve
+type BookingLimits struct {
+	MaxActive   int           // reservations a user may hold that have not ended yet
+	MaxDuration time.Duration // length of one reservation
+	Window      time.Duration // how far ahead reservations can start
+}
+
+// DefaultBookingLimits apply unless LIBRARY_* variables say otherwise
+var DefaultBookingLimits = BookingLimits{MaxActive: 2, MaxDuration: 3 * time.Hour, Window: 7 * 24 * time.Hour}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
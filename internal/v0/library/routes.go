@@ -0,0 +1,68 @@
+package library
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// FeatureSlug is the feature tokens need for the library endpoints
+	FeatureSlug = "library"
+
+	// ReservationsFeatureSlug is the feature tokens need to reserve study rooms
+	ReservationsFeatureSlug = "library.reservations"
+
+	// OccupancyFeatureSlug is the feature the gate counters' tokens need to report
+	// occupancy. It is admin-only, so only tokens issued by admins can hold it.
+	OccupancyFeatureSlug = "library-occupancy"
+)
+
+// Features are the features this module serves, registered at startup
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Library API", Description: "Library opening hours, seat occupancy and study rooms"},
+	{Slug: ReservationsFeatureSlug, Name: "Study room reservations", Parent: FeatureSlug, Description: "Reserving the libraries' study rooms for the token's user"},
+	{Slug: OccupancyFeatureSlug, Name: "Library occupancy ingest", AdminOnly: true, Description: "Lets the libraries' gate counters report how many seats are taken"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	library := rg.Group("/library")
+	{
+		library.GET("/libraries", authMiddleware.RequireToken(FeatureSlug), h.GetLibraries)
+		library.GET("/libraries/:id", authMiddleware.RequireToken(FeatureSlug), h.GetLibrary)
+		library.GET("/libraries/:id/rooms", authMiddleware.RequireToken(FeatureSlug), h.GetRooms)
+		library.PUT("/libraries/:id/occupancy", authMiddleware.RequireToken(OccupancyFeatureSlug), h.PutOccupancy)
+		library.GET("/reservations", authMiddleware.RequireToken(ReservationsFeatureSlug), h.ListReservations)
+		library.POST("/reservations", authMiddleware.RequireToken(ReservationsFeatureSlug), h.PostReservation)
+		library.DELETE("/reservations/:id", authMiddleware.RequireToken(ReservationsFeatureSlug), h.DeleteReservation)
+	}
+
+	library_admin := rg.Group("/admin/library")
+	library_admin.Use(authMiddleware.RequireSession())
+	library_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	library_admin.Use(authMiddleware.Idempotent())
+	{
+		library_admin.GET("/libraries", h.ListLibraries)
+		library_admin.POST("/libraries", h.PostLibrary)
+		library_admin.PUT("/libraries/:id", h.ReplaceLibrary)
+		library_admin.DELETE("/libraries/:id", h.DeleteLibrary)
+		library_admin.POST("/rooms", h.PostRoom)
+		library_admin.PUT("/rooms/:id", h.ReplaceRoom)
+		library_admin.DELETE("/rooms/:id", h.DeleteRoom)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,44 @@
+package library
+
+import (
+	"API/internal/realtime"
+	"context"
+	"time"
+)
+
+// TopicOccupancy is the topic name of the libraries' live occupancy (see realtime.Hub)
+const TopicOccupancy = "library.occupancy"
+
+// Topics returns what the library streams live: every library with its occupancy and
+// whether it is open now, for the info screens at the entrances
+func Topics(repo *Repository) []realtime.Topic {
+	return []realtime.Topic{
+		{
+			Name:    TopicOccupancy,
+			Feature: FeatureSlug,
+			Snapshot: func(ctx context.Context, lang string) (interface{}, error) {
+				libraries, err := repo.ListLibraries(ctx)
+				if err != nil {
+					return nil, err
+				}
+				present(libraries, lang, time.Now())
+				return map[string]interface{}{"libraries": libraries}, nil
+			},
+		},
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
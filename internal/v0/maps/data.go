@@ -0,0 +1,233 @@
+package maps
+
+import (
+	"API/internal/campustime"
+	"API/internal/databases"
+	"API/internal/pagination"
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new campus map repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// poiColumns are the columns scanPOI reads, in order
+const poiColumns = "id, name, COALESCE(name_en, ''), category, COALESCE(description, ''), latitude, longitude"
+
+func scanPOI(scan func(dest ...interface{}) error) (POI, error) {
+	poi := POI{Hours: []campustime.OpeningHours{}}
+	err := scan(&poi.ID, &poi.Name, &poi.NameEn, &poi.Category, &poi.Description, &poi.Latitude, &poi.Longitude)
+	return poi, err
+}
+
+// queryPOIs returns the points of interest a query over poiColumns selects, with their hours
+func queryPOIs(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]POI, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pois := []POI{}
+	for rows.Next() {
+		poi, err := scanPOI(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		pois = append(pois, poi)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pois, attachHours(ctx, db, pois)
+}
+
+// attachHours loads the opening hours of pois, Monday first
+func attachHours(ctx context.Context, db *sql.DB, pois []POI) error {
+	if len(pois) == 0 {
+		return nil
+	}
+	byID := make(map[int64]int, len(pois))
+	placeholders := make([]string, 0, len(pois))
+	args := make([]interface{}, 0, len(pois))
+	for i, poi := range pois {
+		byID[poi.ID] = i
+		placeholders = append(placeholders, "?")
+		args = append(args, poi.ID)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT poi_id, weekday, opens_at, closes_at FROM poi_hours
+		WHERE poi_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY poi_id, (weekday + 6) % 7, opens_at`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var poiID int64
+		var weekday int
+		var h campustime.OpeningHours
+		if err := rows.Scan(&poiID, &weekday, &h.Opens, &h.Closes); err != nil {
+			return err
+		}
+		h.Day = campustime.Weekdays[weekday]
+		pois[byID[poiID]].Hours = append(pois[byID[poiID]].Hours, h)
+	}
+	return rows.Err()
+}
+
+// GetPOI returns a point of interest with its hours, or nil when it does not exist
+func (r *Repository) GetPOI(ctx context.Context, id int64) (*POI, error) {
+	pois, err := queryPOIs(ctx, r.db, "SELECT "+poiColumns+" FROM pois WHERE id = ?", id)
+	if err != nil || len(pois) == 0 {
+		return nil, err
+	}
+	return &pois[0], nil
+}
+
+// ListPOIs returns a page of points of interest matching the filter, newest first. The
+// page holds one extra point when another page follows (see pagination.Next).
+func (r *Repository) ListPOIs(ctx context.Context, filter POIFilter, page pagination.Params) ([]POI, error) {
+	where, args := poiFilter(filter)
+	after, afterArgs := page.Where("id")
+	args = append(append(args, afterArgs...), page.FetchLimit())
+	return queryPOIs(ctx, r.db, `
+		SELECT `+poiColumns+` FROM pois
+		WHERE `+where+` AND `+after+`
+		ORDER BY id DESC
+		LIMIT ?`, args...)
+}
+
+// CountPOIs returns the number of points of interest matching the filter
+func (r *Repository) CountPOIs(ctx context.Context, filter POIFilter) (int, error) {
+	where, args := poiFilter(filter)
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pois WHERE "+where, args...).Scan(&count)
+	return count, err
+}
+
+// POIsIn returns up to limit points of interest matching the filter inside a box
+func (r *Repository) POIsIn(ctx context.Context, box Box, filter POIFilter, limit int) ([]POI, error) {
+	where, args := poiFilter(filter)
+	args = append(args, box.South, box.North, box.West, box.East, limit)
+	return queryPOIs(ctx, r.db, `
+		SELECT `+poiColumns+` FROM pois
+		WHERE `+where+` AND latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?
+		ORDER BY id
+		LIMIT ?`, args...)
+}
+
+func poiFilter(filter POIFilter) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.Category != "" {
+		conditions = append(conditions, "category = ?")
+		args = append(args, filter.Category)
+	}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		conditions = append(conditions, "(LOWER(name) LIKE ? OR LOWER(COALESCE(name_en, '')) LIKE ?)")
+		like := "%" + strings.ToLower(q) + "%"
+		args = append(args, like, like)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// writeHours replaces the opening hours of a point of interest
+func writeHours(ctx context.Context, tx *sql.Tx, id int64, hours []campustime.OpeningHours) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM poi_hours WHERE poi_id = ?", id); err != nil {
+		return err
+	}
+	for _, h := range hours {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO poi_hours (poi_id, weekday, opens_at, closes_at) VALUES (?, ?, ?, ?)
+		`, id, slices.Index(campustime.Weekdays[:], h.Day), h.Opens, h.Closes)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreatePOI adds a point of interest with its opening hours
+func (r *Repository) CreatePOI(ctx context.Context, poi POI) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO pois (name, name_en, category, description, latitude, longitude) VALUES (?, ?, ?, ?, ?, ?)
+		`, poi.Name, databases.NullString(poi.NameEn), poi.Category, databases.NullString(poi.Description), poi.Latitude, poi.Longitude)
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		return writeHours(ctx, tx, id, poi.Hours)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplacePOI overwrites a point of interest and its opening hours. It returns false when
+// the point does not exist.
+func (r *Repository) ReplacePOI(ctx context.Context, id int64, poi POI) (bool, error) {
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE pois SET name = ?, name_en = ?, category = ?, description = ?, latitude = ?, longitude = ? WHERE id = ?
+		`, poi.Name, databases.NullString(poi.NameEn), poi.Category, databases.NullString(poi.Description), poi.Latitude, poi.Longitude, id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		replaced = true
+		return writeHours(ctx, tx, id, poi.Hours)
+	})
+	return replaced, err
+}
+
+// DeletePOI deletes a point of interest and its opening hours. It returns false when the
+// point does not exist.
+func (r *Repository) DeletePOI(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM poi_hours WHERE poi_id = ?", id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM pois WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,94 @@
+package maps
+
+import "math"
+
+// earthRadius is the mean radius of the Earth in meters
+const earthRadius = 6371000
+
+// distance returns the great-circle distance between two points in meters
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dPhi, dLambda := (lat2-lat1)*math.Pi/180, (lon2-lon1)*math.Pi/180
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// Box is an area between two latitudes and two longitudes
+type Box struct {
+	South, West, North, East float64
+}
+
+// around returns a box holding every point within radius meters of a point, so that a
+// query can narrow down to it before measuring distances. It is wider than needed away
+// from the equator, never narrower.
+func around(lat, lon, radius float64) Box {
+	dLat := radius / earthRadius * 180 / math.Pi
+	dLon := 180.0
+	if cos := math.Cos(lat * math.Pi / 180); cos > 1e-9 {
+		dLon = min(dLat/cos, 180)
+	}
+	return Box{South: lat - dLat, West: lon - dLon, North: lat + dLat, East: lon + dLon}
+}
+
+// MaxZoom is the deepest zoom level tiles are served at
+const MaxZoom = 22
+
+// tileBox returns the area of a Web Mercator (slippy map) tile
+func tileBox(z, x, y int) Box {
+	n := math.Exp2(float64(z))
+	lon := func(x int) float64 { return float64(x)/n*360 - 180 }
+	lat := func(y int) float64 { return math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/n))) * 180 / math.Pi }
+	return Box{South: lat(y + 1), West: lon(x), North: lat(y), East: lon(x + 1)}
+}
+
+// MIMEGeoJSON is the content type of GeoJSON responses
+const MIMEGeoJSON = "application/geo+json"
+
+// FeatureCollection is a GeoJSON (RFC 7946) list of points of interest
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a point of interest as a GeoJSON point, with its other fields as properties
+type Feature struct {
+	Type       string   `json:"type"`
+	ID         int64    `json:"id"`
+	Geometry   Geometry `json:"geometry"`
+	Properties POI      `json:"properties"`
+}
+
+// Geometry is a GeoJSON point. Its coordinates are longitude first.
+type Geometry struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// geoJSON returns points of interest as a feature collection
+func geoJSON(pois []POI) FeatureCollection {
+	collection := FeatureCollection{Type: "FeatureCollection", Features: make([]Feature, len(pois))}
+	for i, poi := range pois {
+		collection.Features[i] = Feature{
+			Type:       "Feature",
+			ID:         poi.ID,
+			Geometry:   Geometry{Type: "Point", Coordinates: [2]float64{poi.Longitude, poi.Latitude}},
+			Properties: poi,
+		}
+	}
+	return collection
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,357 @@
+package maps
+
+import (
+	"API/internal/apierror"
+	"API/internal/campustime"
+	"API/internal/pagination"
+	"API/internal/v0/common"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	// DefaultRadius is the radius of "near me" queries when ?radius= is missing, in meters
+	DefaultRadius = 500
+
+	// MaxRadius caps ?radius=; a campus fits well within it
+	MaxRadius = 5000
+
+	// MaxTilePOIs caps the points of interest of a tile, so zooming out cannot read the
+	// whole table
+	MaxTilePOIs = 1000
+)
+
+// Handler serves the campus map from the Repository
+type Handler struct {
+	repo *Repository
+}
+
+func NewHandler(repo *Repository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// parseFilter reads ?category= and ?q=. It renders an error and returns false for
+// unknown categories.
+func parseFilter(c *gin.Context) (POIFilter, bool) {
+	filter := POIFilter{Category: c.Query("category"), Query: c.Query("q")}
+	if filter.Category != "" && !slices.Contains(Categories, filter.Category) {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "category must be one of "+strings.Join(Categories, ", "))))
+		return POIFilter{}, false
+	}
+	return filter, true
+}
+
+// wantsGeoJSON reports whether the client asked for GeoJSON, either with ?format=geojson
+// or through Accept
+func wantsGeoJSON(c *gin.Context) bool {
+	return c.Query("format") == "geojson" || c.NegotiateFormat(binding.MIMEJSON, MIMEGeoJSON) == MIMEGeoJSON
+}
+
+// renderGeoJSON writes points of interest as a GeoJSON feature collection
+func renderGeoJSON(c *gin.Context, pois []POI) {
+	c.Writer.Header().Add("Vary", "Accept")
+	body, err := json.Marshal(geoJSON(pois))
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to render GeoJSON")))
+		return
+	}
+	c.Data(http.StatusOK, MIMEGeoJSON, body)
+}
+
+// present localizes points of interest and marks which of them are open now
+func present(pois []POI, lang string, now time.Time) {
+	for i := range pois {
+		pois[i] = pois[i].Localized(lang)
+		if len(pois[i].Hours) > 0 {
+			open := campustime.IsOpen(pois[i].Hours, now)
+			pois[i].OpenNow = &open
+		}
+	}
+}
+
+// ListPOIs returns the points of interest matching the filters, newest first, or all of
+// them as GeoJSON
+// GET /maps/pois?category=&q=&lang=&limit=&cursor=&format=geojson
+func (h *Handler) ListPOIs(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	filter, ok := parseFilter(c)
+	if !ok {
+		return
+	}
+
+	// A map layer needs every point at once rather than page by page
+	if wantsGeoJSON(c) {
+		pois, err := h.repo.POIsIn(c.Request.Context(), Box{South: -90, West: -180, North: 90, East: 180}, filter, MaxTilePOIs)
+		if err != nil {
+			common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list points of interest")))
+			return
+		}
+		present(pois, lang, time.Now())
+		renderGeoJSON(c, pois)
+		return
+	}
+
+	pois, err := h.repo.ListPOIs(c.Request.Context(), filter, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list points of interest")))
+		return
+	}
+	total, err := h.repo.CountPOIs(c.Request.Context(), filter)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count points of interest")))
+		return
+	}
+
+	pois, next := pagination.Next(pois, page, func(poi POI) int64 { return poi.ID })
+	present(pois, lang, time.Now())
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"pois":  pois,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
+}
+
+// GetNearbyPOIs returns the points of interest within a radius of a position, nearest first
+// GET /maps/pois/near?lat=&lon=&radius=&category=&q=&lang=&limit=&format=geojson
+func (h *Handler) GetNearbyPOIs(c *gin.Context) {
+	lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(c.Query("lon"), 64)
+	if latErr != nil || lonErr != nil || math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "lat and lon must be a position in degrees")))
+		return
+	}
+	radius := float64(DefaultRadius)
+	if v := c.Query("radius"); v != "" {
+		r, err := strconv.ParseFloat(v, 64)
+		if err != nil || r <= 0 || r > MaxRadius {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, fmt.Sprintf("radius must be meters, at most %d", MaxRadius))))
+			return
+		}
+		radius = r
+	}
+	limit := pagination.DefaultLimit
+	if v := c.Query("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l < 1 {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "invalid limit")))
+			return
+		}
+		limit = min(l, pagination.MaxLimit)
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	filter, ok := parseFilter(c)
+	if !ok {
+		return
+	}
+
+	candidates, err := h.repo.POIsIn(c.Request.Context(), around(lat, lon, radius), filter, MaxTilePOIs)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to find points of interest")))
+		return
+	}
+	// The box holds its corners too; keep the points within the radius
+	pois := []POI{}
+	for _, poi := range candidates {
+		if d := distance(lat, lon, poi.Latitude, poi.Longitude); d <= radius {
+			d = math.Round(d)
+			poi.Distance = &d
+			pois = append(pois, poi)
+		}
+	}
+	sort.SliceStable(pois, func(i, j int) bool { return *pois[i].Distance < *pois[j].Distance })
+	if len(pois) > limit {
+		pois = pois[:limit]
+	}
+
+	present(pois, lang, time.Now())
+	if wantsGeoJSON(c) {
+		renderGeoJSON(c, pois)
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"pois": pois}))
+}
+
+// GetPOI returns one point of interest with its opening hours
+// GET /maps/pois/:id?lang=&format=geojson
+func (h *Handler) GetPOI(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid point of interest ID")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	poi, err := h.repo.GetPOI(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get point of interest")))
+		return
+	}
+	if poi == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "point of interest not found")))
+		return
+	}
+
+	pois := []POI{*poi}
+	present(pois, lang, time.Now())
+	if wantsGeoJSON(c) {
+		renderGeoJSON(c, pois)
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"poi": pois[0]}))
+}
+
+// GetTile returns the points of interest within a Web Mercator tile as GeoJSON, for map
+// clients that load the map tile by tile
+// GET /maps/tiles/:z/:x/:y?category=&q=&lang=
+func (h *Handler) GetTile(c *gin.Context) {
+	z, zErr := strconv.Atoi(c.Param("z"))
+	x, xErr := strconv.Atoi(c.Param("x"))
+	y, yErr := strconv.Atoi(strings.TrimSuffix(c.Param("y"), ".geojson"))
+	if zErr != nil || xErr != nil || yErr != nil || z < 0 || z > MaxZoom || x < 0 || y < 0 || x >= 1<<z || y >= 1<<z {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, fmt.Sprintf("tile must be z/x/y with z from 0 to %d and x and y below 2^z", MaxZoom))))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	filter, ok := parseFilter(c)
+	if !ok {
+		return
+	}
+
+	pois, err := h.repo.POIsIn(c.Request.Context(), tileBox(z, x, y), filter, MaxTilePOIs)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list points of interest")))
+		return
+	}
+	present(pois, lang, time.Now())
+	renderGeoJSON(c, pois)
+}
+
+// PostPOI adds a point of interest with its opening hours
+// POST /admin/maps/pois
+func (h *Handler) PostPOI(c *gin.Context) {
+	poi, ok := bindPOI(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreatePOI(c.Request.Context(), poi)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create point of interest")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplacePOI overwrites a point of interest and its opening hours
+// PUT /admin/maps/pois/:id
+func (h *Handler) ReplacePOI(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid point of interest ID")))
+		return
+	}
+	poi, ok := bindPOI(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplacePOI(c.Request.Context(), id, poi)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update point of interest")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "point of interest not found")))
+		return
+	}
+	updated, _ := h.repo.GetPOI(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"poi": updated}))
+}
+
+// DeletePOI deletes a point of interest and its opening hours
+// DELETE /admin/maps/pois/:id
+func (h *Handler) DeletePOI(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid point of interest ID")))
+		return
+	}
+	deleted, err := h.repo.DeletePOI(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete point of interest")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "point of interest not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "point of interest deleted"}))
+}
+
+// bindPOI binds and validates a point of interest body. It renders an error and returns
+// false when the body is invalid.
+func bindPOI(c *gin.Context) (POI, bool) {
+	var poi POI
+	if err := c.ShouldBindJSON(&poi); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return POI{}, false
+	}
+	poi.Name = strings.TrimSpace(poi.Name)
+	poi.NameEn = strings.TrimSpace(poi.NameEn)
+	poi.Description = strings.TrimSpace(poi.Description)
+	poi.OpenNow, poi.Distance = nil, nil
+	// 9:00 binds too; zero-padded times compare correctly as text
+	for i, h := range poi.Hours {
+		opens, _ := time.Parse("15:04", h.Opens)
+		closes, _ := time.Parse("15:04", h.Closes)
+		poi.Hours[i].Opens, poi.Hours[i].Closes = opens.Format("15:04"), closes.Format("15:04")
+	}
+	if poi.Name == "" {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("name", "name is required")))
+		return POI{}, false
+	}
+	if errs := campustime.ValidateHours(poi.Hours); len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return POI{}, false
+	}
+	return poi, true
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,61 @@
+package maps
+
+import "API/internal/campustime"
+
+// Languages the map is served in, the original Greek first
+const (
+	LanguageGreek   = "el"
+	LanguageEnglish = "en"
+)
+
+var Languages = []string{LanguageGreek, LanguageEnglish}
+
+// Categories are the kinds of points of interest the map shows
+var Categories = []string{"building", "lab", "library", "cafeteria", "parking", "amenity"}
+
+// POI is a point of interest on the campus map. Hours are empty when they are not known.
+type POI struct {
+	ID          int64                     `json:"id"`
+	Name        string                    `json:"name" binding:"required,max=200"`
+	NameEn      string                    `json:"name_en,omitempty" binding:"max=200"`
+	Category    string                    `json:"category" binding:"required,oneof=building lab library cafeteria parking amenity"`
+	Description string                    `json:"description,omitempty" binding:"max=2000"`
+	Latitude    float64                   `json:"latitude" binding:"min=-90,max=90"`
+	Longitude   float64                   `json:"longitude" binding:"min=-180,max=180"`
+	Hours       []campustime.OpeningHours `json:"hours" binding:"max=50,dive"`
+
+	// OpenNow and Distance are computed for responses
+	OpenNow  *bool    `json:"open_now,omitempty"`
+	Distance *float64 `json:"distance,omitempty"` // meters, in "near me" results
+}
+
+// Localized returns the point of interest in lang, falling back to the Greek original,
+// with the translation fields left out
+func (p POI) Localized(lang string) POI {
+	if lang == LanguageEnglish && p.NameEn != "" {
+		p.Name = p.NameEn
+	}
+	p.NameEn = ""
+	return p
+}
+
+// POIFilter narrows a listing of points of interest; zero fields match every one
+type POIFilter struct {
+	Category string
+	Query    string // part of the name, in either language
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,55 @@
+package maps
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSlug is the feature tokens need for the campus map endpoints
+const FeatureSlug = "maps"
+
+// TilesFeatureSlug is the feature tokens need to load the map tile by tile. Tokens with
+// the maps feature have it too.
+const TilesFeatureSlug = "maps.tiles"
+
+// Features are the features this module serves, registered at startup
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Maps API", Description: "Campus buildings, labs and amenities with their positions and opening hours"},
+	{Slug: TilesFeatureSlug, Name: "Map tiles", Parent: FeatureSlug, Description: "Points of interest by Web Mercator tile, as GeoJSON"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	maps := rg.Group("/maps")
+	{
+		maps.GET("/pois", authMiddleware.RequireToken(FeatureSlug), h.ListPOIs)
+		maps.GET("/pois/near", authMiddleware.RequireToken(FeatureSlug), h.GetNearbyPOIs)
+		maps.GET("/pois/:id", authMiddleware.RequireToken(FeatureSlug), h.GetPOI)
+		maps.GET("/tiles/:z/:x/:y", authMiddleware.RequireToken(TilesFeatureSlug), h.GetTile)
+	}
+
+	maps_admin := rg.Group("/admin/maps")
+	maps_admin.Use(authMiddleware.RequireSession())
+	maps_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	maps_admin.Use(authMiddleware.Idempotent())
+	{
+		maps_admin.POST("/pois", h.PostPOI)
+		maps_admin.PUT("/pois/:id", h.ReplacePOI)
+		maps_admin.DELETE("/pois/:id", h.DeletePOI)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
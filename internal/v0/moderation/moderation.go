@@ -0,0 +1,144 @@
+package moderation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/v0/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Status is where a submission stands in the approval flow. Only approved
+// submissions are listed publicly.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+var (
+	// ErrNotFound is returned when deciding on a row that does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrNotPending is returned when a decision is made on a row that is not pending
+	ErrNotPending = errors.New("not pending")
+)
+
+// Review is the approval state of a moderated row, embedded in the module's
+// model. The table keeps it in the status, decided_by, decided_at and
+// decision_note columns.
+type Review struct {
+	Status       Status     `json:"status,omitempty"`
+	DecidedBy    *int64     `json:"decided_by,omitempty"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	DecisionNote string     `json:"decision_note,omitempty"`
+}
+
+// Columns are the columns Scan reads, in order, to be added to a module's
+// column list
+const Columns = "status, decided_by, decided_at, COALESCE(decision_note, '')"
+
+// Scan returns the scan destinations of Columns
+func (r *Review) Scan() []interface{} {
+	return []interface{}{&r.Status, &r.DecidedBy, &r.DecidedAt, &r.DecisionNote}
+}
+
+// Decide approves or rejects a pending row of table, recording the admin and
+// the note. It returns ErrNotFound or ErrNotPending when the row cannot be
+// decided on.
+func Decide(ctx context.Context, db *sql.DB, table string, id int64, status Status, adminID *int64, note string) error {
+	now := time.Now().UTC()
+	res, err := db.ExecContext(ctx, `
+		UPDATE `+table+` SET status = ?, decided_by = ?, decided_at = ?, decision_note = ?, updated_at = ?
+		WHERE id = ? AND status = 'pending'
+	`, status, adminID, now, sql.NullString{String: note, Valid: note != ""}, now, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	var current Status
+	err = db.QueryRowContext(ctx, "SELECT status FROM "+table+" WHERE id = ?", id).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return ErrNotPending
+}
+
+// Resubmit is the SQL assignment that sends a row back to review, for
+// submitters editing it after a decision
+const Resubmit = "status = 'pending', decided_by = NULL, decided_at = NULL, decision_note = NULL"
+
+// StatusQuery reads ?status= of admin listings, where "" lists every status.
+// It renders an error and returns false when the status is unknown.
+func StatusQuery(c *gin.Context) (Status, bool) {
+	switch status := Status(c.Query("status")); status {
+	case "", StatusPending, StatusApproved, StatusRejected:
+		return status, true
+	default:
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("status", "status must be pending, approved or rejected")))
+		return "", false
+	}
+}
+
+// DecisionRequest is the optional body of an approval or rejection
+type DecisionRequest struct {
+	Note string `json:"note" binding:"max=1000"`
+}
+
+// BindDecision reads the optional note of a decision and the admin making it.
+// It renders an error and returns false when the body is invalid.
+func BindDecision(c *gin.Context) (note string, adminID *int64, ok bool) {
+	var req DecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+			return "", nil, false
+		}
+	}
+	if admin := auth.GetUserFromContext(c); admin != nil {
+		adminID = &admin.ID
+	}
+	return strings.TrimSpace(req.Note), adminID, true
+}
+
+// RenderError renders an error returned by Decide, naming the row by noun
+// (e.g. "job")
+func RenderError(c *gin.Context, err error, noun string) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, noun+" not found")))
+	case errors.Is(err, ErrNotPending):
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, noun+" is not pending")))
+	default:
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update "+noun)))
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
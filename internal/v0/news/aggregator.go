@@ -0,0 +1,192 @@
+package news
+
+import (
+	"API/internal/health"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFetchInterval is how often the sources are fetched unless NEWS_FETCH_INTERVAL
+	// sets otherwise
+	DefaultFetchInterval = 30 * time.Minute
+
+	// maxFeedBytes caps the size of a feed; larger ones are rejected
+	maxFeedBytes = 5 << 20
+)
+
+// Aggregator fetches the enabled sources' feeds every interval and stores their new items
+type Aggregator struct {
+	repo      *Repository
+	client    *http.Client
+	interval  time.Duration
+	heartbeat *health.Heartbeat
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewAggregator creates an aggregator for the repository's sources
+func NewAggregator(repo *Repository, interval time.Duration) *Aggregator {
+	return &Aggregator{
+		repo:      repo,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		interval:  interval,
+		heartbeat: health.NewHeartbeat(interval),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start fetches the sources now and then every interval
+func (a *Aggregator) Start(ctx context.Context) {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+		a.heartbeat.Beat()
+		defer a.heartbeat.Stop()
+		a.fetchAll(ctx)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-a.stopCh:
+				return
+			case <-ticker.C:
+				a.heartbeat.Beat()
+				a.fetchAll(ctx)
+			}
+		}
+	}()
+}
+
+// Heartbeat reports whether the fetching loop is running
+func (a *Aggregator) Heartbeat() *health.Heartbeat {
+	return a.heartbeat
+}
+
+// Stop gracefully stops the aggregator
+func (a *Aggregator) Stop() {
+	close(a.stopCh)
+	a.wg.Wait()
+}
+
+func (a *Aggregator) fetchAll(ctx context.Context) {
+	sources, err := a.repo.ListSources(ctx, true)
+	if err != nil {
+		log.Printf("Failed to list news sources: %v", err)
+		return
+	}
+	for _, source := range sources {
+		if result := a.Fetch(ctx, source); result.Error != "" {
+			log.Printf("Failed to fetch news source %s: %s", source.Slug, result.Error)
+		}
+	}
+}
+
+// Fetch fetches a source's feed right away and stores its new and changed items. The
+// feed is requested conditionally, so an unchanged feed is not downloaded again.
+func (a *Aggregator) Fetch(ctx context.Context, source Source) FetchResult {
+	result := FetchResult{Source: source.Slug}
+	fetchedAt := time.Now()
+
+	items, etag, lastModified, err := a.download(ctx, source)
+	if err == nil && items == nil {
+		result.NotModified = true
+		etag, lastModified = source.etag, source.lastModified
+	}
+	if err == nil && items != nil {
+		result.Items = len(items)
+		result.Added, result.Updated, err = a.repo.SaveItems(ctx, source.ID, items, fetchedAt)
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if recordErr := a.repo.RecordFetch(ctx, source.ID, etag, lastModified, err, fetchedAt); recordErr != nil {
+		log.Printf("Failed to record the fetch of news source %s: %v", source.Slug, recordErr)
+	}
+	return result
+}
+
+// download requests a source's feed and normalizes its items, oldest first so that the
+// feed's ids follow publication. It returns nil items when the feed has not changed
+// since the last fetch.
+func (a *Aggregator) download(ctx context.Context, source Source) ([]feedItem, string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.FeedURL, nil)
+	if err != nil {
+		return nil, "", "", err
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8")
+	if source.etag != "" {
+		req.Header.Set("If-None-Match", source.etag)
+	}
+	if source.lastModified != "" {
+		req.Header.Set("If-Modified-Since", source.lastModified)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, "", "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", "", fmt.Errorf("feed returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes+1))
+	if err != nil {
+		return nil, "", "", err
+	}
+	if len(body) > maxFeedBytes {
+		return nil, "", "", fmt.Errorf("feed is larger than %d bytes", maxFeedBytes)
+	}
+
+	entries, err := parseFeed(body)
+	if err != nil {
+		return nil, "", "", err
+	}
+	base := resp.Request.URL
+	if source.SiteURL != "" {
+		if site, err := url.Parse(source.SiteURL); err == nil {
+			base = site
+		}
+	}
+	items := []feedItem{}
+	for _, e := range entries {
+		item, guid, ok := normalize(e, base, source.Category, time.Now())
+		if !ok {
+			continue
+		}
+		items = append(items, feedItem{Item: item, GUID: guid, URLKey: urlKey(item.URL)})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].PublishedAt.Before(items[j].PublishedAt)
+	})
+	return items, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), nil
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,337 @@
+package news
+
+import (
+	"API/internal/databases"
+	"API/internal/pagination"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrDuplicateSource is returned when another source has the same slug or feed URL
+var ErrDuplicateSource = errors.New("another source has the same slug or feed URL")
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new news repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// sourceColumns are the columns scanSource reads, in order
+const sourceColumns = `id, slug, name, COALESCE(name_en, ''), feed_url, COALESCE(site_url, ''), COALESCE(category, ''),
+	enabled, COALESCE(etag, ''), COALESCE(last_modified, ''), last_fetched_at, COALESCE(last_error, '')`
+
+func scanSource(scan func(dest ...interface{}) error) (Source, error) {
+	var s Source
+	var enabled bool
+	var fetchedAt sql.NullTime
+	err := scan(&s.ID, &s.Slug, &s.Name, &s.NameEn, &s.FeedURL, &s.SiteURL, &s.Category,
+		&enabled, &s.etag, &s.lastModified, &fetchedAt, &s.LastError)
+	s.Enabled = &enabled
+	if fetchedAt.Valid {
+		s.LastFetchedAt = &fetchedAt.Time
+	}
+	return s, err
+}
+
+// ListSources returns every source by name; enabledOnly leaves out the disabled ones
+func (r *Repository) ListSources(ctx context.Context, enabledOnly bool) ([]Source, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+sourceColumns+" FROM news_sources WHERE enabled OR NOT ? ORDER BY name", enabledOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sources := []Source{}
+	for rows.Next() {
+		s, err := scanSource(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+// GetSource returns a source, or nil when it does not exist
+func (r *Repository) GetSource(ctx context.Context, id int64) (*Source, error) {
+	s, err := scanSource(r.db.QueryRowContext(ctx, "SELECT "+sourceColumns+" FROM news_sources WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// sourceTaken reports whether a source other than id has the slug or feed URL
+func sourceTaken(ctx context.Context, tx *sql.Tx, s Source, id int64) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM news_sources WHERE (slug = ? OR feed_url = ?) AND id != ?)
+	`, s.Slug, s.FeedURL, id).Scan(&exists)
+	return exists, err
+}
+
+// CreateSource adds a feed, fetched from the next round on
+func (r *Repository) CreateSource(ctx context.Context, s Source) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if taken, err := sourceTaken(ctx, tx, s, 0); err != nil || taken {
+			if taken {
+				return ErrDuplicateSource
+			}
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO news_sources (slug, name, name_en, feed_url, site_url, category, enabled) VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, s.Slug, s.Name, databases.NullString(s.NameEn), s.FeedURL, databases.NullString(s.SiteURL), databases.NullString(s.Category), *s.Enabled)
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplaceSource overwrites a source, keeping its items. A new feed URL is fetched in full
+// on the next round. It returns false when the source does not exist.
+func (r *Repository) ReplaceSource(ctx context.Context, id int64, s Source) (bool, error) {
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if taken, err := sourceTaken(ctx, tx, s, id); err != nil || taken {
+			if taken {
+				return ErrDuplicateSource
+			}
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE news_sources SET slug = ?, name = ?, name_en = ?, site_url = ?, category = ?, enabled = ?,
+				etag = CASE WHEN feed_url = ? THEN etag END,
+				last_modified = CASE WHEN feed_url = ? THEN last_modified END,
+				feed_url = ?
+			WHERE id = ?
+		`, s.Slug, s.Name, databases.NullString(s.NameEn), databases.NullString(s.SiteURL), databases.NullString(s.Category), *s.Enabled,
+			s.FeedURL, s.FeedURL, s.FeedURL, id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		replaced = n > 0
+		return err
+	})
+	return replaced, err
+}
+
+// DeleteSource deletes a source and its items. It returns false when the source does not
+// exist.
+func (r *Repository) DeleteSource(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM news_items WHERE source_id = ?", id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM news_sources WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+// RecordFetch stores the outcome of fetching a source: the validators of the response
+// when it succeeded, or the error when it did not
+func (r *Repository) RecordFetch(ctx context.Context, id int64, etag, lastModified string, fetchErr error, at time.Time) error {
+	if fetchErr != nil {
+		_, err := r.db.ExecContext(ctx, `
+			UPDATE news_sources SET last_fetched_at = ?, last_error = ? WHERE id = ?
+		`, at.UTC(), fetchErr.Error(), id)
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE news_sources SET last_fetched_at = ?, last_error = NULL, etag = ?, last_modified = ? WHERE id = ?
+	`, at.UTC(), databases.NullString(etag), databases.NullString(lastModified), id)
+	return err
+}
+
+// feedItem is a normalized item with the keys it is deduplicated by
+type feedItem struct {
+	Item
+	GUID   string
+	URLKey string
+}
+
+// SaveItems stores the items of a source's feed. Items the source already has are
+// updated when their title, summary or link changed; items whose link another item
+// already has are skipped, so a post published by two departments is listed once.
+func (r *Repository) SaveItems(ctx context.Context, sourceID int64, items []feedItem, fetchedAt time.Time) (added, updated int, err error) {
+	err = databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, item := range items {
+			var id int64
+			err := tx.QueryRowContext(ctx, "SELECT id FROM news_items WHERE source_id = ? AND guid = ?", sourceID, item.GUID).Scan(&id)
+			if err != nil && err != sql.ErrNoRows {
+				return err
+			}
+
+			var duplicate bool
+			if err := tx.QueryRowContext(ctx, `
+				SELECT EXISTS (SELECT 1 FROM news_items WHERE url_key = ? AND id != ?)
+			`, item.URLKey, id).Scan(&duplicate); err != nil {
+				return err
+			}
+			if duplicate {
+				continue
+			}
+
+			if id == 0 {
+				_, err := tx.ExecContext(ctx, `
+					INSERT INTO news_items (source_id, guid, url, url_key, title, summary, category, published_at, fetched_at)
+					VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+				`, sourceID, item.GUID, item.URL, item.URLKey, item.Title, databases.NullString(item.Summary), databases.NullString(item.Category),
+					item.PublishedAt.UTC(), fetchedAt.UTC())
+				if err != nil {
+					return err
+				}
+				added++
+				continue
+			}
+
+			res, err := tx.ExecContext(ctx, `
+				UPDATE news_items SET url = ?, url_key = ?, title = ?, summary = ?, category = ?
+				WHERE id = ? AND (url != ? OR title != ? OR summary IS NOT ? OR category IS NOT ?)
+			`, item.URL, item.URLKey, item.Title, databases.NullString(item.Summary), databases.NullString(item.Category),
+				id, item.URL, item.Title, databases.NullString(item.Summary), databases.NullString(item.Category))
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err != nil {
+				return err
+			} else if n > 0 {
+				updated++
+			}
+		}
+		return nil
+	})
+	return added, updated, err
+}
+
+// itemColumns are the columns scanItem reads, in order, over news_items i joined with
+// news_sources s
+const itemColumns = "i.id, s.slug, i.title, COALESCE(i.summary, ''), i.url, COALESCE(i.category, ''), i.published_at"
+
+func scanItem(scan func(dest ...interface{}) error) (Item, error) {
+	var item Item
+	err := scan(&item.ID, &item.Source, &item.Title, &item.Summary, &item.URL, &item.Category, &item.PublishedAt)
+	return item, err
+}
+
+// ListItems returns a page of the items matching the filter, newest first. The page holds
+// one extra item when another page follows (see pagination.Next).
+func (r *Repository) ListItems(ctx context.Context, filter ItemFilter, page pagination.Params) ([]Item, error) {
+	where, args := itemFilter(filter)
+	after, afterArgs := page.Where("i.id")
+	args = append(append(args, afterArgs...), page.FetchLimit())
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+itemColumns+` FROM news_items i
+		JOIN news_sources s ON s.id = i.source_id
+		WHERE `+where+` AND `+after+`
+		ORDER BY i.id DESC
+		LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Item{}
+	for rows.Next() {
+		item, err := scanItem(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// CountItems returns the number of items matching the filter
+func (r *Repository) CountItems(ctx context.Context, filter ItemFilter) (int, error) {
+	where, args := itemFilter(filter)
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM news_items i JOIN news_sources s ON s.id = i.source_id WHERE `+where, args...).Scan(&count)
+	return count, err
+}
+
+// ListCategories returns the categories of the enabled sources' items, most used first
+func (r *Repository) ListCategories(ctx context.Context) ([]Category, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT i.category, COUNT(*) FROM news_items i
+		JOIN news_sources s ON s.id = i.source_id
+		WHERE s.enabled AND i.category IS NOT NULL
+		GROUP BY i.category
+		ORDER BY COUNT(*) DESC, i.category`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []Category{}
+	for rows.Next() {
+		var c Category
+		if err := rows.Scan(&c.Name, &c.Items); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+// itemFilter matches items of enabled sources only, so disabling a source hides its items
+func itemFilter(filter ItemFilter) (string, []interface{}) {
+	conditions := []string{"s.enabled"}
+	var args []interface{}
+	if len(filter.Sources) > 0 {
+		conditions = append(conditions, "s.slug IN (?"+strings.Repeat(", ?", len(filter.Sources)-1)+")")
+		for _, slug := range filter.Sources {
+			args = append(args, slug)
+		}
+	}
+	if filter.Category != "" {
+		conditions = append(conditions, "i.category = ?")
+		args = append(args, strings.ToLower(filter.Category))
+	}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		conditions = append(conditions, "(LOWER(i.title) LIKE ? OR LOWER(COALESCE(i.summary, '')) LIKE ?)")
+		like := "%" + strings.ToLower(q) + "%"
+		args = append(args, like, like)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
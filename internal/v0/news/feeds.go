@@ -0,0 +1,270 @@
+package news
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	// maxTitleLength and maxSummaryLength cap the text kept of an item, in characters
+	maxTitleLength   = 300
+	maxSummaryLength = 500
+)
+
+// errUnknownFormat is returned for documents that are neither RSS nor Atom
+var errUnknownFormat = errors.New("not an RSS or Atom feed")
+
+// entry is an item as its feed describes it, before normalization
+type entry struct {
+	GUID      string
+	Link      string
+	Title     string
+	Summary   string
+	Category  string
+	Published string
+}
+
+// rssDocument covers RSS 2.0 (<rss><channel><item>) and RSS 1.0 (<rdf:RDF><item>)
+type rssDocument struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	GUID        string   `xml:"guid"`
+	Link        string   `xml:"link"`
+	Title       string   `xml:"title"`
+	Description string   `xml:"description"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Subject     string   `xml:"http://purl.org/dc/elements/1.1/ subject"`
+}
+
+type atomDocument struct {
+	Entries []struct {
+		ID    string `xml:"id"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Title      string `xml:"title"`
+		Summary    string `xml:"summary"`
+		Content    string `xml:"content"`
+		Categories []struct {
+			Term  string `xml:"term,attr"`
+			Label string `xml:"label,attr"`
+		} `xml:"category"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// parseFeed reads the entries of an RSS or Atom document in any charset it declares
+func parseFeed(body []byte) ([]entry, error) {
+	root, err := rootElement(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	switch root {
+	case "rss", "RDF":
+		var doc rssDocument
+		if err := decode(body, &doc); err != nil {
+			return nil, err
+		}
+		for _, item := range append(doc.Channel.Items, doc.Items...) {
+			e := entry{GUID: item.GUID, Link: item.Link, Title: item.Title, Summary: item.Description, Published: item.PubDate}
+			if e.Published == "" {
+				e.Published = item.Date
+			}
+			if len(item.Categories) > 0 {
+				e.Category = item.Categories[0]
+			} else {
+				e.Category = item.Subject
+			}
+			entries = append(entries, e)
+		}
+	case "feed":
+		var doc atomDocument
+		if err := decode(body, &doc); err != nil {
+			return nil, err
+		}
+		for _, item := range doc.Entries {
+			e := entry{GUID: item.ID, Title: item.Title, Summary: item.Summary, Published: item.Published}
+			for _, link := range item.Links {
+				if link.Rel == "" || link.Rel == "alternate" {
+					e.Link = link.Href
+					break
+				}
+			}
+			if e.Summary == "" {
+				e.Summary = item.Content
+			}
+			if e.Published == "" {
+				e.Published = item.Updated
+			}
+			if len(item.Categories) > 0 {
+				e.Category = item.Categories[0].Label
+				if e.Category == "" {
+					e.Category = item.Categories[0].Term
+				}
+			}
+			entries = append(entries, e)
+		}
+	default:
+		return nil, errUnknownFormat
+	}
+	return entries, nil
+}
+
+func newDecoder(body []byte) *xml.Decoder {
+	d := xml.NewDecoder(bytes.NewReader(body))
+	// Department sites still publish in ISO-8859-7 and windows-1253
+	d.CharsetReader = charset.NewReaderLabel
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+	return d
+}
+
+func decode(body []byte, v interface{}) error {
+	return newDecoder(body).Decode(v)
+}
+
+// rootElement returns the local name of the document's first element
+func rootElement(body []byte) (string, error) {
+	d := newDecoder(body)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", errUnknownFormat
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// feedTimeLayouts are the date formats found in feeds, RFC 822 variants first
+var feedTimeLayouts = []string{
+	time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700", "Mon, 02 Jan 2006 15:04 -0700", time.RFC3339, "2006-01-02T15:04:05", "2006-01-02",
+}
+
+// parseFeedTime reads an item's date, returning false when it has none or it cannot be read
+func parseFeedTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// normalize turns an entry into an item: text without markup, an absolute link and a
+// publication time no later than fetchedAt. It returns false for entries without a
+// title or link.
+func normalize(e entry, base *url.URL, defaultCategory string, fetchedAt time.Time) (Item, string, bool) {
+	item := Item{
+		Title:    truncate(plainText(e.Title), maxTitleLength),
+		Summary:  truncate(plainText(e.Summary), maxSummaryLength),
+		Category: strings.ToLower(strings.TrimSpace(plainText(e.Category))),
+	}
+	if item.Category == "" {
+		item.Category = defaultCategory
+	}
+	link, err := base.Parse(strings.TrimSpace(e.Link))
+	if item.Title == "" || err != nil || (link.Scheme != "http" && link.Scheme != "https") {
+		return Item{}, "", false
+	}
+	item.URL = link.String()
+
+	item.PublishedAt = fetchedAt
+	if t, ok := parseFeedTime(e.Published); ok && t.Before(fetchedAt) {
+		item.PublishedAt = t
+	}
+
+	// Items without a guid are told apart by their link
+	guid := strings.TrimSpace(e.GUID)
+	if guid == "" {
+		guid = item.URL
+	}
+	return item, guid, true
+}
+
+// urlKey normalizes a link so that the same post reached through http or https, with or
+// without www., a trailing slash or tracking parameters, has one key
+func urlKey(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	query := u.Query()
+	for name := range query {
+		if strings.HasPrefix(name, "utm_") || name == "fbclid" || name == "gclid" {
+			query.Del(name)
+		}
+	}
+	key := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if len(query) > 0 {
+		key += "?" + query.Encode()
+	}
+	return key
+}
+
+// plainText returns the text of an HTML fragment with whitespace collapsed
+func plainText(fragment string) string {
+	if !strings.ContainsAny(fragment, "<&") {
+		return strings.Join(strings.Fields(fragment), " ")
+	}
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.TextToken:
+			b.Write(z.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			// Tags such as <p> and <br> separate words
+			b.WriteByte(' ')
+		}
+	}
+}
+
+// truncate shortens s to at most n characters, ending it with an ellipsis when cut
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)[:n-1]
+	return strings.TrimRight(string(runes), " ,.;:") + "…"
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,239 @@
+package news
+
+import (
+	"API/internal/apierror"
+	"API/internal/pagination"
+	"API/internal/v0/common"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the news feed from the Repository
+type Handler struct {
+	repo       *Repository
+	aggregator *Aggregator
+}
+
+// NewHandler creates a handler whose admins can fetch sources through the aggregator
+func NewHandler(repo *Repository, aggregator *Aggregator) *Handler {
+	return &Handler{repo: repo, aggregator: aggregator}
+}
+
+// GetFeed returns the items of every enabled source, newest first
+// GET /news?source=&category=&q=&limit=&cursor=
+func (h *Handler) GetFeed(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	filter := ItemFilter{Category: c.Query("category"), Query: c.Query("q")}
+	// ?source=a,b and ?source=a&source=b both select several sources
+	for _, value := range c.QueryArray("source") {
+		for _, slug := range strings.Split(value, ",") {
+			if slug = strings.ToLower(strings.TrimSpace(slug)); slug != "" {
+				filter.Sources = append(filter.Sources, slug)
+			}
+		}
+	}
+
+	items, err := h.repo.ListItems(c.Request.Context(), filter, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list news")))
+		return
+	}
+	total, err := h.repo.CountItems(c.Request.Context(), filter)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count news")))
+		return
+	}
+
+	items, next := pagination.Next(items, page, func(item Item) int64 { return item.ID })
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"items": items,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
+}
+
+// GetSources returns the enabled sources, whose slugs filter the feed
+// GET /news/sources?lang=
+func (h *Handler) GetSources(c *gin.Context) {
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	sources, err := h.repo.ListSources(c.Request.Context(), true)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list news sources")))
+		return
+	}
+	for i := range sources {
+		sources[i] = sources[i].Localized(lang).Public()
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"sources": sources}))
+}
+
+// GetCategories returns the categories of the feed's items
+// GET /news/categories
+func (h *Handler) GetCategories(c *gin.Context) {
+	categories, err := h.repo.ListCategories(c.Request.Context())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list news categories")))
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"categories": categories}))
+}
+
+// ListSources returns every source with its feed and the outcome of its last fetch
+// GET /admin/news/sources
+func (h *Handler) ListSources(c *gin.Context) {
+	sources, err := h.repo.ListSources(c.Request.Context(), false)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list news sources")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"sources": sources}))
+}
+
+// PostSource adds a source, fetched on the next round or through FetchSource
+// POST /admin/news/sources
+func (h *Handler) PostSource(c *gin.Context) {
+	source, ok := bindSource(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateSource(c.Request.Context(), source)
+	if err != nil {
+		if errors.Is(err, ErrDuplicateSource) {
+			common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+			return
+		}
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create news source")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceSource overwrites a source; its items are kept
+// PUT /admin/news/sources/:id
+func (h *Handler) ReplaceSource(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid source ID")))
+		return
+	}
+	source, ok := bindSource(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceSource(c.Request.Context(), id, source)
+	if err != nil {
+		if errors.Is(err, ErrDuplicateSource) {
+			common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+			return
+		}
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update news source")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "news source not found")))
+		return
+	}
+	updated, _ := h.repo.GetSource(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"source": updated}))
+}
+
+// DeleteSource deletes a source and its items
+// DELETE /admin/news/sources/:id
+func (h *Handler) DeleteSource(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid source ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteSource(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete news source")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "news source not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "news source deleted"}))
+}
+
+// FetchSource fetches a source right away, e.g. to check a new feed, and reports what it
+// found. Disabled sources can be fetched too.
+// POST /admin/news/sources/:id/fetch
+func (h *Handler) FetchSource(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid source ID")))
+		return
+	}
+	source, err := h.repo.GetSource(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get news source")))
+		return
+	}
+	if source == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "news source not found")))
+		return
+	}
+	result := h.aggregator.Fetch(c.Request.Context(), *source)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"result": result}))
+}
+
+// bindSource binds and validates a source body, enabling the source unless the body
+// disables it. It renders an error and returns false when the body is invalid.
+func bindSource(c *gin.Context) (Source, bool) {
+	var source Source
+	if err := c.ShouldBindJSON(&source); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Source{}, false
+	}
+	source.Slug = strings.ToLower(strings.TrimSpace(source.Slug))
+	source.Name, source.NameEn = strings.TrimSpace(source.Name), strings.TrimSpace(source.NameEn)
+	source.Category = strings.ToLower(strings.TrimSpace(source.Category))
+	source.ID, source.LastFetchedAt, source.LastError = 0, nil, ""
+	if source.Enabled == nil {
+		enabled := true
+		source.Enabled = &enabled
+	}
+	var errs []apierror.Error
+	if source.Slug == "" || strings.ContainsAny(source.Slug, ", /") {
+		errs = append(errs, apierror.Invalid("slug", "slug must be non-empty without commas, spaces or slashes"))
+	}
+	if source.Name == "" {
+		errs = append(errs, apierror.Invalid("name", "name is required"))
+	}
+	if !strings.HasPrefix(source.FeedURL, "http://") && !strings.HasPrefix(source.FeedURL, "https://") {
+		errs = append(errs, apierror.Invalid("feed_url", "feed_url must be an http or https URL"))
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Source{}, false
+	}
+	return source, true
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
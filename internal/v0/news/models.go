@@ -0,0 +1,95 @@
+package news
+
+import "time"
+
+// Languages source names are served in, the original Greek first. Items are served as
+// their sites publish them.
+const (
+	LanguageGreek   = "el"
+	LanguageEnglish = "en"
+)
+
+var Languages = []string{LanguageGreek, LanguageEnglish}
+
+// Source is an RSS or Atom feed of a department's site
+type Source struct {
+	ID       int64  `json:"id"`
+	Slug     string `json:"slug" binding:"required,max=50"`
+	Name     string `json:"name" binding:"required,max=200"`
+	NameEn   string `json:"name_en,omitempty" binding:"max=200"`
+	FeedURL  string `json:"feed_url" binding:"required,url,max=500"`
+	SiteURL  string `json:"site_url,omitempty" binding:"omitempty,url,max=500"`
+	Category string `json:"category,omitempty" binding:"max=50"` // for items whose feed sets none
+	Enabled  *bool  `json:"enabled,omitempty"`
+
+	// Fetch state, shown to admins
+	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	etag          string
+	lastModified  string
+}
+
+// Localized returns the source in lang, falling back to the Greek original, with the
+// translation fields left out
+func (s Source) Localized(lang string) Source {
+	if lang == LanguageEnglish && s.NameEn != "" {
+		s.Name = s.NameEn
+	}
+	s.NameEn = ""
+	return s
+}
+
+// Public returns the source without its feed and fetch state
+func (s Source) Public() Source {
+	s.FeedURL, s.Enabled, s.LastFetchedAt, s.LastError = "", nil, nil, ""
+	return s
+}
+
+// Item is a news post of one of the sources
+type Item struct {
+	ID          int64     `json:"id"`
+	Source      string    `json:"source"` // slug
+	Title       string    `json:"title"`
+	Summary     string    `json:"summary,omitempty"`
+	URL         string    `json:"url"`
+	Category    string    `json:"category,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// ItemFilter narrows the feed; zero fields match every item
+type ItemFilter struct {
+	Sources  []string // slugs, ignoring case
+	Category string
+	Query    string // part of the title or summary
+}
+
+// Category is a category of the feed with the number of its items
+type Category struct {
+	Name  string `json:"name"`
+	Items int    `json:"items"`
+}
+
+// FetchResult reports what fetching a source found
+type FetchResult struct {
+	Source      string `json:"source"`
+	NotModified bool   `json:"not_modified,omitempty"`
+	Items       int    `json:"items"`   // in the feed
+	Added       int    `json:"added"`   // new items
+	Updated     int    `json:"updated"` // items whose title, summary or link changed
+	Error       string `json:"error,omitempty"`
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
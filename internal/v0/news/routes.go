@@ -0,0 +1,51 @@
+package news
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSlug is the feature tokens need for the news endpoints
+const FeatureSlug = "news"
+
+// Features are the features this module serves, registered at startup
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "News API", Description: "News aggregated from the departments' sites"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	news := rg.Group("/news")
+	{
+		news.GET("", authMiddleware.RequireToken(FeatureSlug), h.GetFeed)
+		news.GET("/sources", authMiddleware.RequireToken(FeatureSlug), h.GetSources)
+		news.GET("/categories", authMiddleware.RequireToken(FeatureSlug), h.GetCategories)
+	}
+
+	news_admin := rg.Group("/admin/news")
+	news_admin.Use(authMiddleware.RequireSession())
+	news_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	news_admin.Use(authMiddleware.Idempotent())
+	{
+		news_admin.GET("/sources", h.ListSources)
+		news_admin.POST("/sources", h.PostSource)
+		news_admin.PUT("/sources/:id", h.ReplaceSource)
+		news_admin.DELETE("/sources/:id", h.DeleteSource)
+		news_admin.POST("/sources/:id/fetch", h.FetchSource)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
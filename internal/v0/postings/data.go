@@ -0,0 +1,300 @@
+package postings
+
+import (
+	"API/internal/campustime"
+	"API/internal/databases"
+	"API/internal/pagination"
+	"API/internal/v0/moderation"
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new postings repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// postingColumns are the columns scanPosting reads, in order
+const postingColumns = `id, kind, title, description, department, COALESCE(supervisor, ''), COALESCE(organization, ''),
+	COALESCE(location, ''), COALESCE(contact_email, ''), COALESCE(url, ''), positions, COALESCE(deadline, ''),
+	expires_at, created_by, ` + moderation.Columns + `, created_at, updated_at`
+
+func scanPosting(scan func(dest ...interface{}) error) (Posting, error) {
+	p := Posting{Tags: []string{}}
+	var positions sql.NullInt64
+	dest := []interface{}{&p.ID, &p.Kind, &p.Title, &p.Description, &p.Department, &p.Supervisor, &p.Organization,
+		&p.Location, &p.ContactEmail, &p.URL, &positions, &p.Deadline, &p.ExpiresAt, &p.CreatedBy}
+	dest = append(append(dest, p.Review.Scan()...), &p.CreatedAt, &p.UpdatedAt)
+	err := scan(dest...)
+	if positions.Valid {
+		n := int(positions.Int64)
+		p.Positions = &n
+	}
+	return p, err
+}
+
+func (r *Repository) queryPostings(ctx context.Context, query string, args ...interface{}) ([]Posting, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	postings := []Posting{}
+	for rows.Next() {
+		p, err := scanPosting(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		postings = append(postings, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	return postings, r.attachTags(ctx, postings)
+}
+
+// attachTags loads the tags of postings, alphabetically
+func (r *Repository) attachTags(ctx context.Context, postings []Posting) error {
+	if len(postings) == 0 {
+		return nil
+	}
+	byID := make(map[int64]int, len(postings))
+	placeholders := make([]string, 0, len(postings))
+	args := make([]interface{}, 0, len(postings))
+	for i, p := range postings {
+		byID[p.ID] = i
+		placeholders = append(placeholders, "?")
+		args = append(args, p.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT posting_id, tag FROM posting_tags
+		WHERE posting_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY posting_id, tag`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var postingID int64
+		var tag string
+		if err := rows.Scan(&postingID, &tag); err != nil {
+			return err
+		}
+		postings[byID[postingID]].Tags = append(postings[byID[postingID]].Tags, tag)
+	}
+	return rows.Err()
+}
+
+// GetPosting returns a posting whether or not it has expired, or nil when it does not
+// exist
+func (r *Repository) GetPosting(ctx context.Context, id int64) (*Posting, error) {
+	postings, err := r.queryPostings(ctx, "SELECT "+postingColumns+" FROM postings WHERE id = ?", id)
+	if err != nil || len(postings) == 0 {
+		return nil, err
+	}
+	return &postings[0], nil
+}
+
+// ListPostings returns a page of postings matching the filter, newest first. The page
+// holds one extra posting when another page follows (see pagination.Next).
+func (r *Repository) ListPostings(ctx context.Context, filter PostingFilter, page pagination.Params) ([]Posting, error) {
+	where, args := postingFilter(filter)
+	after, afterArgs := page.Where("id")
+	args = append(append(args, afterArgs...), page.FetchLimit())
+	return r.queryPostings(ctx, `
+		SELECT `+postingColumns+` FROM postings
+		WHERE `+where+` AND `+after+`
+		ORDER BY id DESC
+		LIMIT ?`, args...)
+}
+
+// CountPostings returns the number of postings matching the filter
+func (r *Repository) CountPostings(ctx context.Context, filter PostingFilter) (int, error) {
+	where, args := postingFilter(filter)
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM postings WHERE "+where, args...).Scan(&count)
+	return count, err
+}
+
+// ListTags returns the tags of the postings matching the filter, most used first
+func (r *Repository) ListTags(ctx context.Context, filter PostingFilter) ([]Tag, error) {
+	where, args := postingFilter(filter)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT tag, COUNT(*) FROM posting_tags
+		WHERE posting_id IN (SELECT id FROM postings WHERE `+where+`)
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []Tag{}
+	for rows.Next() {
+		var t Tag
+		if err := rows.Scan(&t.Tag, &t.Postings); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// writeTags replaces the tags of a posting
+func writeTags(ctx context.Context, tx *sql.Tx, id int64, tags []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM posting_tags WHERE posting_id = ?", id); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO posting_tags (posting_id, tag) VALUES (?, ?)", id, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreatePosting adds an editor's posting, pending approval or approved
+func (r *Repository) CreatePosting(ctx context.Context, p Posting, status moderation.Status, createdBy int64) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO postings (kind, title, description, department, supervisor, organization, location,
+				contact_email, url, positions, deadline, expires_at, created_by, status, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, p.Kind, p.Title, p.Description, p.Department, databases.NullString(p.Supervisor), databases.NullString(p.Organization),
+			databases.NullString(p.Location), databases.NullString(p.ContactEmail), databases.NullString(p.URL), p.Positions, databases.NullString(p.Deadline),
+			p.ExpiresAt.UTC(), createdBy, status, now, now)
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		return writeTags(ctx, tx, id, p.Tags)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplacePosting overwrites a posting and its tags. An editorID other than 0 limits the
+// change to that editor's postings and sends the posting back for approval. It returns
+// false when there is no such posting.
+func (r *Repository) ReplacePosting(ctx context.Context, id int64, p Posting, editorID int64) (bool, error) {
+	resubmit := ""
+	if editorID != 0 {
+		resubmit = ", " + moderation.Resubmit
+	}
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE postings SET kind = ?, title = ?, description = ?, department = ?, supervisor = ?, organization = ?,
+				location = ?, contact_email = ?, url = ?, positions = ?, deadline = ?, expires_at = ?, updated_at = ?`+resubmit+`
+			WHERE id = ? AND (created_by = ? OR ? = 0)
+		`, p.Kind, p.Title, p.Description, p.Department, databases.NullString(p.Supervisor), databases.NullString(p.Organization),
+			databases.NullString(p.Location), databases.NullString(p.ContactEmail), databases.NullString(p.URL), p.Positions, databases.NullString(p.Deadline),
+			p.ExpiresAt.UTC(), time.Now().UTC(), id, editorID, editorID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		replaced = true
+		return writeTags(ctx, tx, id, p.Tags)
+	})
+	return replaced, err
+}
+
+// DeletePosting deletes a posting and its tags. An editorID other than 0 limits the
+// deletion to that editor's postings. It returns false when there is no such posting.
+func (r *Repository) DeletePosting(ctx context.Context, id, editorID int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "DELETE FROM postings WHERE id = ? AND (created_by = ? OR ? = 0)", id, editorID, editorID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		deleted = true
+		_, err = tx.ExecContext(ctx, "DELETE FROM posting_tags WHERE posting_id = ?", id)
+		return err
+	})
+	return deleted, err
+}
+
+// DecidePosting approves or rejects a pending posting
+func (r *Repository) DecidePosting(ctx context.Context, id int64, status moderation.Status, adminID *int64, note string) (*Posting, error) {
+	if err := moderation.Decide(ctx, r.db, "postings", id, status, adminID, note); err != nil {
+		return nil, err
+	}
+	return r.GetPosting(ctx, id)
+}
+
+func postingFilter(filter PostingFilter) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if !filter.Now.IsZero() {
+		conditions = append(conditions, "expires_at > ?")
+		args = append(args, filter.Now.UTC())
+	}
+	if filter.Open {
+		conditions = append(conditions, "(deadline IS NULL OR deadline >= ?)")
+		args = append(args, filter.Now.In(campustime.Location).Format("2006-01-02"))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.CreatedBy != 0 {
+		conditions = append(conditions, "created_by = ?")
+		args = append(args, filter.CreatedBy)
+	}
+	if filter.Kind != "" {
+		conditions = append(conditions, "kind = ?")
+		args = append(args, filter.Kind)
+	}
+	if filter.Department != "" {
+		conditions = append(conditions, "department = ?")
+		args = append(args, filter.Department)
+	}
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM posting_tags pt WHERE pt.posting_id = postings.id AND pt.tag = ?)")
+		args = append(args, tag)
+	}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		conditions = append(conditions, `(LOWER(title) LIKE ? OR LOWER(description) LIKE ?
+			OR LOWER(COALESCE(supervisor, '')) LIKE ? OR LOWER(COALESCE(organization, '')) LIKE ?)`)
+		like := "%" + strings.ToLower(q) + "%"
+		args = append(args, like, like, like, like)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,387 @@
+package postings
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/pagination"
+	"API/internal/v0/common"
+	"API/internal/v0/moderation"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the postings from the Repository
+type Handler struct {
+	repo *Repository
+}
+
+func NewHandler(repo *Repository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// parseFilter reads ?kind=, ?tag=, ?department=, ?q= and ?open=. It renders an error
+// with render and returns false when one of them is invalid.
+func parseFilter(c *gin.Context, render func(*gin.Context, int, common.APIResponse)) (PostingFilter, bool) {
+	filter := PostingFilter{
+		Kind:       c.Query("kind"),
+		Department: strings.TrimSpace(c.Query("department")),
+		Query:      c.Query("q"),
+	}
+	// ?tag=a,b and ?tag=a&tag=b both ask for postings with every tag
+	for _, value := range c.QueryArray("tag") {
+		filter.Tags = append(filter.Tags, normalizeTags(strings.Split(value, ","))...)
+	}
+	var errs []apierror.Error
+	if filter.Kind != "" && !slices.Contains(Kinds, filter.Kind) {
+		errs = append(errs, apierror.Invalid("kind", "kind must be one of "+strings.Join(Kinds, ", ")))
+	}
+	if v := c.Query("open"); v != "" {
+		open, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, apierror.Invalid("open", "open must be true or false"))
+		}
+		filter.Open = open
+	}
+	if len(errs) > 0 {
+		render(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return PostingFilter{}, false
+	}
+	return filter, true
+}
+
+// normalizeTags lowercases tags, dropping empty and repeated ones
+func normalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// GetPostings returns the postings that have not expired matching the filters, newest
+// first. ?open=true leaves out those whose deadline has passed.
+// GET /postings?kind=&tag=&department=&q=&open=&limit=&cursor=
+func (h *Handler) GetPostings(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	filter, ok := parseFilter(c, common.Render)
+	if !ok {
+		return
+	}
+	filter.Now = time.Now()
+	filter.Status = moderation.StatusApproved
+
+	postings, err := h.repo.ListPostings(c.Request.Context(), filter, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list postings")))
+		return
+	}
+	total, err := h.repo.CountPostings(c.Request.Context(), filter)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count postings")))
+		return
+	}
+
+	postings, next := pagination.Next(postings, page, func(p Posting) int64 { return p.ID })
+	for i := range postings {
+		postings[i] = postings[i].Public()
+	}
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"postings": postings,
+		"total":    total,
+		"limit":    page.Limit,
+	}, next))
+}
+
+// GetPosting returns one approved posting that has not expired
+// GET /postings/:id
+func (h *Handler) GetPosting(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid posting ID")))
+		return
+	}
+	posting, err := h.repo.GetPosting(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get posting")))
+		return
+	}
+	if posting == nil || posting.Status != moderation.StatusApproved || !posting.ExpiresAt.After(time.Now()) {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "posting not found")))
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"posting": posting.Public()}))
+}
+
+// GetTags returns the tags of the postings that have not expired, most used first,
+// optionally of one kind or department
+// GET /postings/tags?kind=&department=&open=
+func (h *Handler) GetTags(c *gin.Context) {
+	filter, ok := parseFilter(c, common.Render)
+	if !ok {
+		return
+	}
+	filter.Now = time.Now()
+	filter.Status = moderation.StatusApproved
+	tags, err := h.repo.ListTags(c.Request.Context(), filter)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list tags")))
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"tags": tags}))
+}
+
+// editorScope returns the editor whose postings the user may change, or 0 for admins,
+// who may change every posting
+func editorScope(user *auth.User) int64 {
+	if user.Role == auth.RoleAdmin {
+		return 0
+	}
+	return user.ID
+}
+
+// ListEditorPostings returns the calling editor's postings, expired ones included, newest
+// first, with where each stands in the approval flow. Admins get every editor's postings,
+// and ?status=pending lists those waiting for a decision.
+// GET /editor/postings?status=&kind=&tag=&department=&q=&open=&limit=&cursor=
+func (h *Handler) ListEditorPostings(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	filter, ok := parseFilter(c, common.JSON)
+	if !ok {
+		return
+	}
+	if filter.Status, ok = moderation.StatusQuery(c); !ok {
+		return
+	}
+	filter.CreatedBy = editorScope(user)
+	if filter.Open {
+		filter.Now = time.Now()
+	}
+
+	postings, err := h.repo.ListPostings(c.Request.Context(), filter, page)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list postings")))
+		return
+	}
+	total, err := h.repo.CountPostings(c.Request.Context(), filter)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count postings")))
+		return
+	}
+
+	postings, next := pagination.Next(postings, page, func(p Posting) int64 { return p.ID })
+	common.JSON(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"postings": postings,
+		"total":    total,
+		"limit":    page.Limit,
+	}, next))
+}
+
+// PostPosting publishes a posting, which is listed once an admin approves it. Postings
+// by admins are approved right away.
+// POST /editor/postings
+func (h *Handler) PostPosting(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	posting, ok := bindPosting(c, time.Now())
+	if !ok {
+		return
+	}
+	status := moderation.StatusPending
+	if user.Role == auth.RoleAdmin {
+		status = moderation.StatusApproved
+	}
+	id, err := h.repo.CreatePosting(c.Request.Context(), posting, status, user.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create posting")))
+		return
+	}
+	created, _ := h.repo.GetPosting(c.Request.Context(), id)
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"posting": created}))
+}
+
+// ReplacePosting overwrites one of the calling editor's postings, e.g. to extend its
+// deadline, and sends it back for approval. Admins can replace any posting, keeping
+// its status.
+// PUT /editor/postings/:id
+func (h *Handler) ReplacePosting(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid posting ID")))
+		return
+	}
+	posting, ok := bindPosting(c, time.Now())
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplacePosting(c.Request.Context(), id, posting, editorScope(user))
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update posting")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "posting not found")))
+		return
+	}
+	updated, _ := h.repo.GetPosting(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"posting": updated}))
+}
+
+// DeletePosting deletes one of the calling editor's postings. Admins can delete any
+// posting.
+// DELETE /editor/postings/:id
+func (h *Handler) DeletePosting(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid posting ID")))
+		return
+	}
+	deleted, err := h.repo.DeletePosting(c.Request.Context(), id, editorScope(user))
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete posting")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "posting not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "posting deleted"}))
+}
+
+// ApprovePosting lists a pending posting
+// POST /admin/postings/:id/approve
+func (h *Handler) ApprovePosting(c *gin.Context) {
+	h.decidePosting(c, moderation.StatusApproved)
+}
+
+// RejectPosting turns down a pending posting, with an optional note for its editor
+// POST /admin/postings/:id/reject
+func (h *Handler) RejectPosting(c *gin.Context) {
+	h.decidePosting(c, moderation.StatusRejected)
+}
+
+func (h *Handler) decidePosting(c *gin.Context, status moderation.Status) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid posting ID")))
+		return
+	}
+	note, adminID, ok := moderation.BindDecision(c)
+	if !ok {
+		return
+	}
+	posting, err := h.repo.DecidePosting(c.Request.Context(), id, status, adminID, note)
+	if err != nil {
+		moderation.RenderError(c, err, "posting")
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"posting": posting}))
+}
+
+// bindPosting binds and validates a posting body, working out when the posting expires.
+// It renders an error and returns false when the body is invalid.
+func bindPosting(c *gin.Context, now time.Time) (Posting, bool) {
+	var req PostingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Posting{}, false
+	}
+	p := Posting{
+		Kind:         req.Kind,
+		Title:        strings.TrimSpace(req.Title),
+		Description:  strings.TrimSpace(req.Description),
+		Department:   strings.ToUpper(strings.TrimSpace(req.Department)),
+		Supervisor:   strings.TrimSpace(req.Supervisor),
+		Organization: strings.TrimSpace(req.Organization),
+		Location:     strings.TrimSpace(req.Location),
+		ContactEmail: strings.TrimSpace(req.ContactEmail),
+		URL:          strings.TrimSpace(req.URL),
+		Positions:    req.Positions,
+		Tags:         normalizeTags(req.Tags),
+		Deadline:     req.Deadline,
+	}
+
+	var errs []apierror.Error
+	if p.Title == "" {
+		errs = append(errs, apierror.Invalid("title", "title is required"))
+	}
+	if p.Description == "" {
+		errs = append(errs, apierror.Invalid("description", "description is required"))
+	}
+	if p.Department == "" {
+		errs = append(errs, apierror.Invalid("department", "department is required"))
+	}
+	var closes time.Time
+	if p.Deadline != "" {
+		closes, _ = deadlineEnd(p.Deadline)
+		if !closes.After(now) {
+			errs = append(errs, apierror.Invalid("deadline", "deadline must not be in the past"))
+		}
+	}
+	switch {
+	case req.ExpiresAt != nil:
+		p.ExpiresAt = req.ExpiresAt.Truncate(time.Second)
+		if !p.ExpiresAt.After(now) {
+			errs = append(errs, apierror.Invalid("expires_at", "expires_at must be in the future"))
+		} else if p.ExpiresAt.Before(closes) {
+			errs = append(errs, apierror.Invalid("expires_at", "expires_at must not be before the deadline ends"))
+		}
+	case !closes.IsZero():
+		p.ExpiresAt = closes
+	default:
+		p.ExpiresAt = now.Add(DefaultLifetime).Truncate(time.Second)
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Posting{}, false
+	}
+	return p, true
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
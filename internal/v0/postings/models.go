@@ -0,0 +1,106 @@
+package postings
+
+import (
+	"time"
+
+	"API/internal/v0/moderation"
+)
+
+// Kinds are the kinds of postings
+var Kinds = []string{KindThesis, KindInternship}
+
+const (
+	KindThesis     = "thesis"
+	KindInternship = "internship"
+)
+
+// DefaultLifetime is how long a posting without a deadline or expiry stays listed
+const DefaultLifetime = 180 * 24 * time.Hour
+
+// Posting is a thesis topic or an internship opening. Postings are written in the
+// language their department publishes them in, so they have no translations. A data
+// editor's postings are listed once an admin approves them; admins' own postings are
+// approved right away.
+type Posting struct {
+	ID           int64     `json:"id"`
+	Kind         string    `json:"kind"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Department   string    `json:"department"`
+	Supervisor   string    `json:"supervisor,omitempty"`
+	Organization string    `json:"organization,omitempty"` // the host of an internship
+	Location     string    `json:"location,omitempty"`
+	ContactEmail string    `json:"contact_email,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	Positions    *int      `json:"positions,omitempty"`
+	Tags         []string  `json:"tags"`
+	Deadline     string    `json:"deadline,omitempty"` // YYYY-MM-DD, the last day to apply
+	ExpiresAt    time.Time `json:"expires_at"`
+
+	// CreatedBy and the approval details are shown to editors only
+	CreatedBy int64 `json:"created_by,omitempty"`
+	moderation.Review
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Public returns the posting without the editor who published it and its approval
+// details
+func (p Posting) Public() Posting {
+	p.CreatedBy = 0
+	p.Review = moderation.Review{}
+	return p
+}
+
+// PostingRequest is the body editors send to publish or replace a posting. Without
+// expires_at, a posting expires after its deadline, or DefaultLifetime after it is
+// published when it has none.
+type PostingRequest struct {
+	Kind         string     `json:"kind" binding:"required,oneof=thesis internship"`
+	Title        string     `json:"title" binding:"required,max=200"`
+	Description  string     `json:"description" binding:"required,max=10000"`
+	Department   string     `json:"department" binding:"required,max=20"`
+	Supervisor   string     `json:"supervisor" binding:"max=200"`
+	Organization string     `json:"organization" binding:"max=200"`
+	Location     string     `json:"location" binding:"max=200"`
+	ContactEmail string     `json:"contact_email" binding:"omitempty,email,max=200"`
+	URL          string     `json:"url" binding:"omitempty,url,max=500"`
+	Positions    *int       `json:"positions" binding:"omitempty,min=1,max=1000"`
+	Tags         []string   `json:"tags" binding:"max=20,dive,max=40"`
+	Deadline     string     `json:"deadline" binding:"omitempty,datetime=2006-01-02"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+}
+
+// PostingFilter narrows a posting listing; zero fields match every posting
+type PostingFilter struct {
+	Kind       string
+	Tags       []string // postings with every one of the tags
+	Department string
+	Query      string    // part of the title, description, supervisor or organization
+	Open       bool      // postings whose deadline has not passed by Now
+	Now        time.Time // postings that have not expired by Now, when not zero
+	Status     moderation.Status
+	// CreatedBy narrows the listing to one editor's postings when not zero
+	CreatedBy int64
+}
+
+// Tag is a tag of the listed postings with the number of postings it is on
+type Tag struct {
+	Tag      string `json:"tag"`
+	Postings int    `json:"postings"`
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
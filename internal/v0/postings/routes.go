@@ -0,0 +1,60 @@
+package postings
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSlug is the feature tokens need for the postings endpoints
+const FeatureSlug = "postings"
+
+// Features are the features this module serves, registered at startup
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Postings API", Description: "Thesis topics and internship openings published by the departments"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	postings := rg.Group("/postings")
+	{
+		postings.GET("", authMiddleware.RequireToken(FeatureSlug), h.GetPostings)
+		postings.GET("/tags", authMiddleware.RequireToken(FeatureSlug), h.GetTags)
+		postings.GET("/:id", authMiddleware.RequireToken(FeatureSlug), h.GetPosting)
+	}
+
+	// Data editors publish postings for approval; admins pass RequireRole too
+	postings_editor := rg.Group("/editor/postings")
+	postings_editor.Use(authMiddleware.RequireSession())
+	postings_editor.Use(authMiddleware.RequireRole(auth.RoleDataEditor))
+	postings_editor.Use(authMiddleware.Idempotent())
+	{
+		postings_editor.GET("", h.ListEditorPostings)
+		postings_editor.POST("", h.PostPosting)
+		postings_editor.PUT("/:id", h.ReplacePosting)
+		postings_editor.DELETE("/:id", h.DeletePosting)
+	}
+
+	postings_admin := rg.Group("/admin/postings")
+	postings_admin.Use(authMiddleware.RequireSession())
+	postings_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	postings_admin.Use(authMiddleware.Idempotent())
+	{
+		postings_admin.POST("/:id/approve", h.ApprovePosting)
+		postings_admin.POST("/:id/reject", h.RejectPosting)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,315 @@
+package printing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"API/internal/databases"
+)
+
+var (
+	// ErrDuplicateStation is returned when another station has the same code
+	ErrDuplicateStation = errors.New("another station has the same code")
+
+	// ErrAlertResolved is returned when acknowledging an alert that was already resolved
+	ErrAlertResolved = errors.New("the alert was already resolved")
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new printing repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// stationColumns are the columns scanStation reads, in order
+const stationColumns = `id, code, name, COALESCE(name_en, ''), location, COALESCE(location_en, ''), color,
+	status, COALESCE(status_message, ''), queue_length, toner_percent, COALESCE(paper, ''), reported_at`
+
+func scanStation(scan func(dest ...interface{}) error) (Station, error) {
+	var s Station
+	var state sql.NullString
+	var queue, toner sql.NullInt64
+	var reportedAt sql.NullTime
+	status := Status{}
+	if err := scan(&s.ID, &s.Code, &s.Name, &s.NameEn, &s.Location, &s.LocationEn, &s.Color,
+		&state, &status.Message, &queue, &toner, &status.Paper, &reportedAt); err != nil {
+		return s, err
+	}
+	if state.Valid && reportedAt.Valid {
+		status.State, status.QueueLength, status.UpdatedAt = state.String, int(queue.Int64), reportedAt.Time
+		if toner.Valid {
+			percent := int(toner.Int64)
+			status.TonerPercent = &percent
+		}
+		s.Status = &status
+	}
+	return s, nil
+}
+
+// ListStations returns every station by name
+func (r *Repository) ListStations(ctx context.Context) ([]Station, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+stationColumns+" FROM print_stations ORDER BY name, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stations := []Station{}
+	for rows.Next() {
+		s, err := scanStation(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		stations = append(stations, s)
+	}
+	return stations, rows.Err()
+}
+
+// GetStation returns a station, or nil when it does not exist
+func (r *Repository) GetStation(ctx context.Context, id int64) (*Station, error) {
+	s, err := scanStation(r.db.QueryRowContext(ctx, "SELECT "+stationColumns+" FROM print_stations WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func stationCodeTaken(ctx context.Context, tx *sql.Tx, code string, id int64) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM print_stations WHERE code = ? AND id != ?)", code, id).Scan(&exists)
+	return exists, err
+}
+
+// CreateStation adds a station, without a status until the print server reports it
+func (r *Repository) CreateStation(ctx context.Context, s Station) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if taken, err := stationCodeTaken(ctx, tx, s.Code, 0); err != nil || taken {
+			if taken {
+				return ErrDuplicateStation
+			}
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO print_stations (code, name, name_en, location, location_en, color) VALUES (?, ?, ?, ?, ?, ?)
+		`, s.Code, s.Name, databases.NullString(s.NameEn), s.Location, databases.NullString(s.LocationEn), s.Color)
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplaceStation overwrites a station, keeping its status and alerts. It returns false
+// when the station does not exist.
+func (r *Repository) ReplaceStation(ctx context.Context, id int64, s Station) (bool, error) {
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if taken, err := stationCodeTaken(ctx, tx, s.Code, id); err != nil || taken {
+			if taken {
+				return ErrDuplicateStation
+			}
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE print_stations SET code = ?, name = ?, name_en = ?, location = ?, location_en = ?, color = ?
+			WHERE id = ?
+		`, s.Code, s.Name, databases.NullString(s.NameEn), s.Location, databases.NullString(s.LocationEn), s.Color, id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		replaced = n > 0
+		return err
+	})
+	return replaced, err
+}
+
+// DeleteStation deletes a station with its alerts. It returns false when the station does
+// not exist.
+func (r *Repository) DeleteStation(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM print_alerts WHERE station_id = ?", id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM print_stations WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+// RecordReports stores the print server's reports as of now, raising and resolving the
+// stations' supply alerts, with toner at or below lowToner percent counting as low. It
+// returns the codes of the reports that match no station, which are skipped.
+func (r *Repository) RecordReports(ctx context.Context, reports []StationReport, lowToner int, now time.Time) ([]string, error) {
+	unknown := []string{}
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, report := range reports {
+			var id int64
+			err := tx.QueryRowContext(ctx, "SELECT id FROM print_stations WHERE code = ?", report.Code).Scan(&id)
+			if errors.Is(err, sql.ErrNoRows) {
+				unknown = append(unknown, report.Code)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, `
+				UPDATE print_stations SET status = ?, status_message = ?, queue_length = ?, toner_percent = ?, paper = ?,
+					reported_at = ?
+				WHERE id = ?
+			`, report.State, databases.NullString(report.Message), *report.QueueLength, report.TonerPercent,
+				databases.NullString(report.Paper), now.UTC(), id)
+			if err != nil {
+				return err
+			}
+
+			// Supplies left out of a report are not known to be low, so their alerts stay as they are
+			if report.TonerPercent != nil {
+				message := ""
+				if *report.TonerPercent <= lowToner {
+					message = fmt.Sprintf("toner at %d%%", *report.TonerPercent)
+				}
+				if err := setAlert(ctx, tx, id, AlertToner, message, now); err != nil {
+					return err
+				}
+			}
+			if report.Paper != "" {
+				message := ""
+				if report.Paper != "ok" {
+					message = "paper " + report.Paper
+				}
+				if err := setAlert(ctx, tx, id, AlertPaper, message, now); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	return unknown, err
+}
+
+// setAlert raises the station's alert of kind with message, or updates the message of the
+// open one. An empty message resolves the open alert instead.
+func setAlert(ctx context.Context, tx *sql.Tx, stationID int64, kind, message string, now time.Time) error {
+	if message == "" {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE print_alerts SET resolved_at = ? WHERE station_id = ? AND kind = ? AND resolved_at IS NULL
+		`, now.UTC(), stationID, kind)
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `
+		UPDATE print_alerts SET message = ? WHERE station_id = ? AND kind = ? AND resolved_at IS NULL
+	`, message, stationID, kind)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO print_alerts (station_id, kind, message, raised_at) VALUES (?, ?, ?, ?)
+	`, stationID, kind, message, now.UTC())
+	return err
+}
+
+// alertColumns are the columns scanAlert reads, in order
+const alertColumns = `a.id, a.station_id, s.code, s.name, a.kind, a.message, a.raised_at, a.resolved_at,
+	a.acknowledged_by, a.acknowledged_at`
+
+func scanAlert(scan func(dest ...interface{}) error) (Alert, error) {
+	var a Alert
+	err := scan(&a.ID, &a.StationID, &a.StationCode, &a.StationName, &a.Kind, &a.Message, &a.RaisedAt, &a.ResolvedAt,
+		&a.AcknowledgedBy, &a.AcknowledgedAt)
+	return a, err
+}
+
+// ListAlerts returns the open alerts, oldest first. When since is not zero the resolved
+// alerts raised since then are listed too.
+func (r *Repository) ListAlerts(ctx context.Context, since time.Time) ([]Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM print_alerts a JOIN print_stations s ON s.id = a.station_id
+		WHERE a.resolved_at IS NULL`
+	args := []interface{}{}
+	if !since.IsZero() {
+		query += " OR a.raised_at >= ?"
+		args = append(args, since.UTC())
+	}
+	rows, err := r.db.QueryContext(ctx, query+" ORDER BY a.raised_at, a.id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []Alert{}
+	for rows.Next() {
+		a, err := scanAlert(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
+
+// AcknowledgeAlert records that an admin has seen an open alert, e.g. while the toner is
+// on its way. It returns nil when the alert does not exist and ErrAlertResolved when it
+// is no longer open.
+func (r *Repository) AcknowledgeAlert(ctx context.Context, id, adminID int64, now time.Time) (*Alert, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE print_alerts SET acknowledged_by = ?, acknowledged_at = ? WHERE id = ? AND resolved_at IS NULL
+	`, adminID, now.UTC(), id)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	a, err := scanAlert(r.db.QueryRowContext(ctx, `SELECT `+alertColumns+` FROM print_alerts a
+		JOIN print_stations s ON s.id = a.station_id WHERE a.id = ?`, id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrAlertResolved
+	}
+	return &a, nil
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
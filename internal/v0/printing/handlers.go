@@ -0,0 +1,283 @@
+package printing
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/v0/common"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the print stations from the Repository
+type Handler struct {
+	repo       *Repository
+	thresholds Thresholds
+}
+
+func NewHandler(repo *Repository) *Handler {
+	return &Handler{repo: repo, thresholds: DefaultThresholds}
+}
+
+// SetThresholds replaces DefaultThresholds
+func (h *Handler) SetThresholds(thresholds Thresholds) {
+	h.thresholds = thresholds
+}
+
+// present localizes stations and shows those the print server has not reported on
+// lately as unknown
+func (h *Handler) present(stations []Station, lang string, now time.Time) {
+	for i := range stations {
+		stations[i] = stations[i].Localized(lang)
+		if status := stations[i].Status; status != nil && now.Sub(status.UpdatedAt) > h.thresholds.StaleAfter {
+			stale := *status
+			stale.State, stale.Message = StateUnknown, ""
+			stations[i].Status = &stale
+		}
+	}
+}
+
+// GetStations returns every print station with its state and queue length
+// GET /printing/stations?lang=
+func (h *Handler) GetStations(c *gin.Context) {
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	stations, err := h.repo.ListStations(c.Request.Context())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list stations")))
+		return
+	}
+	h.present(stations, lang, time.Now())
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"stations": stations}))
+}
+
+// GetStation returns one print station with its state and queue length
+// GET /printing/stations/:id?lang=
+func (h *Handler) GetStation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid station ID")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	station, err := h.repo.GetStation(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get station")))
+		return
+	}
+	if station == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "station not found")))
+		return
+	}
+	stations := []Station{*station}
+	h.present(stations, lang, time.Now())
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"station": stations[0]}))
+}
+
+// PutStatus records the print server's report on its stations, raising and resolving
+// their toner and paper alerts. Stations are matched by code; reports on unknown codes
+// are skipped and listed in the response.
+// PUT /printing/status
+func (h *Handler) PutStatus(c *gin.Context) {
+	var report StatusReport
+	if err := c.ShouldBindJSON(&report); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	for i := range report.Stations {
+		report.Stations[i].Code = strings.TrimSpace(report.Stations[i].Code)
+		report.Stations[i].Message = strings.TrimSpace(report.Stations[i].Message)
+	}
+	unknown, err := h.repo.RecordReports(c.Request.Context(), report.Stations, h.thresholds.LowToner, time.Now())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to record status")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"updated": len(report.Stations) - len(unknown),
+		"unknown": unknown,
+	}))
+}
+
+// ListStations returns every station with its translations and last reported status,
+// however old
+// GET /admin/printing/stations
+func (h *Handler) ListStations(c *gin.Context) {
+	stations, err := h.repo.ListStations(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list stations")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"stations": stations}))
+}
+
+// PostStation adds a print station
+// POST /admin/printing/stations
+func (h *Handler) PostStation(c *gin.Context) {
+	station, ok := bindStation(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateStation(c.Request.Context(), station)
+	if errors.Is(err, ErrDuplicateStation) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create station")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceStation overwrites a print station
+// PUT /admin/printing/stations/:id
+func (h *Handler) ReplaceStation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid station ID")))
+		return
+	}
+	station, ok := bindStation(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceStation(c.Request.Context(), id, station)
+	if errors.Is(err, ErrDuplicateStation) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update station")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "station not found")))
+		return
+	}
+	updated, _ := h.repo.GetStation(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"station": updated}))
+}
+
+// DeleteStation deletes a print station with its alerts
+// DELETE /admin/printing/stations/:id
+func (h *Handler) DeleteStation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid station ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteStation(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete station")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "station not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "station deleted"}))
+}
+
+// bindStation binds and validates a station body. It renders an error and returns false
+// when the body is invalid.
+func bindStation(c *gin.Context) (Station, bool) {
+	var station Station
+	if err := c.ShouldBindJSON(&station); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Station{}, false
+	}
+	station.Code = strings.TrimSpace(station.Code)
+	station.Name, station.NameEn = strings.TrimSpace(station.Name), strings.TrimSpace(station.NameEn)
+	station.Location, station.LocationEn = strings.TrimSpace(station.Location), strings.TrimSpace(station.LocationEn)
+	station.Status = nil
+	var errs []apierror.Error
+	if station.Code == "" {
+		errs = append(errs, apierror.Invalid("code", "code is required"))
+	}
+	if station.Name == "" {
+		errs = append(errs, apierror.Invalid("name", "name is required"))
+	}
+	if station.Location == "" {
+		errs = append(errs, apierror.Invalid("location", "location is required"))
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Station{}, false
+	}
+	return station, true
+}
+
+// ListAlerts returns the open toner and paper alerts, oldest first. ?since= (RFC 3339)
+// adds the resolved alerts raised since then.
+// GET /admin/printing/alerts?since=
+func (h *Handler) ListAlerts(c *gin.Context) {
+	var since time.Time
+	if v := c.Query("since"); v != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, v); err != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("since", "since must be an RFC 3339 time")))
+			return
+		}
+	}
+	alerts, err := h.repo.ListAlerts(c.Request.Context(), since)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list alerts")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"alerts": alerts}))
+}
+
+// AcknowledgeAlert marks an open alert as seen by the calling admin, so others know it
+// is being seen to. The alert stays open until a report finds the supply refilled.
+// POST /admin/printing/alerts/:id/acknowledge
+func (h *Handler) AcknowledgeAlert(c *gin.Context) {
+	admin := auth.GetUserFromContext(c)
+	if admin == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "not authenticated")))
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid alert ID")))
+		return
+	}
+	alert, err := h.repo.AcknowledgeAlert(c.Request.Context(), id, admin.ID, time.Now())
+	if errors.Is(err, ErrAlertResolved) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to acknowledge alert")))
+		return
+	}
+	if alert == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "alert not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"alert": alert}))
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,116 @@
+package printing
+
+import "time"
+
+// Languages the print stations are served in, the original Greek first
+const (
+	LanguageGreek   = "el"
+	LanguageEnglish = "en"
+)
+
+var Languages = []string{LanguageGreek, LanguageEnglish}
+
+// StateUnknown is the state of a station the print server has not reported on within
+// Thresholds.StaleAfter
+const StateUnknown = "unknown"
+
+// Alert kinds
+const (
+	AlertToner = "toner"
+	AlertPaper = "paper"
+)
+
+// Station is a campus print station
+type Station struct {
+	ID         int64  `json:"id"`
+	Code       string `json:"code" binding:"required,max=100"` // the station's queue on the print server
+	Name       string `json:"name" binding:"required,max=200"`
+	NameEn     string `json:"name_en,omitempty" binding:"max=200"`
+	Location   string `json:"location" binding:"required,max=200"`
+	LocationEn string `json:"location_en,omitempty" binding:"max=200"`
+	Color      bool   `json:"color"`
+
+	// Status is the print server's last report, nil until the first one
+	Status *Status `json:"status,omitempty"`
+}
+
+// Status is how a station was doing at the print server's last report
+type Status struct {
+	State        string    `json:"state"` // ready, printing, error, offline or unknown
+	Message      string    `json:"message,omitempty"`
+	QueueLength  int       `json:"queue_length"`
+	TonerPercent *int      `json:"toner_percent,omitempty"`
+	Paper        string    `json:"paper,omitempty"` // ok, low or empty
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Localized returns the station in lang, falling back to the Greek original, with the
+// translation fields left out
+func (s Station) Localized(lang string) Station {
+	if lang == LanguageEnglish {
+		if s.NameEn != "" {
+			s.Name = s.NameEn
+		}
+		if s.LocationEn != "" {
+			s.Location = s.LocationEn
+		}
+	}
+	s.NameEn, s.LocationEn = "", ""
+	return s
+}
+
+// StationReport is the print server's report on one station, matched by code
+type StationReport struct {
+	Code         string `json:"code" binding:"required,max=100"`
+	State        string `json:"state" binding:"required,oneof=ready printing error offline"`
+	Message      string `json:"message" binding:"max=500"`
+	QueueLength  *int   `json:"queue_length" binding:"required,min=0"`
+	TonerPercent *int   `json:"toner_percent" binding:"omitempty,min=0,max=100"`
+	Paper        string `json:"paper" binding:"omitempty,oneof=ok low empty"`
+}
+
+// StatusReport is what the print server sends to the ingest endpoint
+type StatusReport struct {
+	Stations []StationReport `json:"stations" binding:"required,min=1,max=1000,dive"`
+}
+
+// Alert is a low toner or paper alert of a station
+type Alert struct {
+	ID             int64      `json:"id"`
+	StationID      int64      `json:"station_id"`
+	StationCode    string     `json:"station_code"`
+	StationName    string     `json:"station_name"`
+	Kind           string     `json:"kind"` // toner or paper
+	Message        string     `json:"message"`
+	RaisedAt       time.Time  `json:"raised_at"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	AcknowledgedBy *int64     `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+// Thresholds decide when supplies raise alerts and when a station's status is too old
+// to show
+type Thresholds struct {
+	// LowToner raises a toner alert at or below this percentage
+	LowToner int
+	// StaleAfter is how long a report stands; older ones show the station as unknown
+	StaleAfter time.Duration
+}
+
+// DefaultThresholds apply unless the handler is given others with SetThresholds
+var DefaultThresholds = Thresholds{LowToner: 10, StaleAfter: 15 * time.Minute}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
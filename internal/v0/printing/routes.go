@@ -0,0 +1,58 @@
+package printing
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// FeatureSlug is the feature tokens need for the printing endpoints
+	FeatureSlug = "printing"
+
+	// IngestFeatureSlug is the feature the print server's token needs to report the
+	// stations' status. It is admin-only, so only tokens issued by admins can hold it.
+	IngestFeatureSlug = "printing-ingest"
+)
+
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Printing API", Description: "Status and queue lengths of the campus print stations"},
+	{Slug: IngestFeatureSlug, Name: "Printing status ingest", AdminOnly: true, Description: "Lets the print server report the stations' status, queues and supplies"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	printing := rg.Group("/printing")
+	{
+		printing.GET("/stations", authMiddleware.RequireToken(FeatureSlug), h.GetStations)
+		printing.GET("/stations/:id", authMiddleware.RequireToken(FeatureSlug), h.GetStation)
+		printing.PUT("/status", authMiddleware.RequireToken(IngestFeatureSlug), h.PutStatus)
+	}
+
+	printing_admin := rg.Group("/admin/printing")
+	printing_admin.Use(authMiddleware.RequireSession())
+	printing_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	printing_admin.Use(authMiddleware.Idempotent())
+	{
+		printing_admin.GET("/stations", h.ListStations)
+		printing_admin.POST("/stations", h.PostStation)
+		printing_admin.PUT("/stations/:id", h.ReplaceStation)
+		printing_admin.DELETE("/stations/:id", h.DeleteStation)
+		printing_admin.GET("/alerts", h.ListAlerts)
+		printing_admin.POST("/alerts/:id/acknowledge", h.AcknowledgeAlert)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
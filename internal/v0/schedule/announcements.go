@@ -0,0 +1,121 @@
+package schedule
+
+import (
+	"API/internal/health"
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+// AnnouncementRefreshInterval is how often is_current is brought in line with the dates
+// and times; announcements are scheduled to the minute
+const AnnouncementRefreshInterval = time.Minute
+
+// repeatWeekdays are the names repeat_on takes, indexed by time.Weekday, which is also
+// the bit of the weekday in the repeat_days column
+var repeatWeekdays = [7]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// repeatDays returns the repeat_days bitmask of weekday names
+func repeatDays(names []string) int {
+	var days int
+	for weekday, name := range repeatWeekdays {
+		if slices.Contains(names, name) {
+			days |= 1 << weekday
+		}
+	}
+	return days
+}
+
+// repeatOn returns the weekday names of a repeat_days bitmask, Monday first
+func repeatOn(days int) []string {
+	var names []string
+	for i := range repeatWeekdays {
+		if weekday := (i + 1) % 7; days&(1<<weekday) != 0 {
+			names = append(names, repeatWeekdays[weekday])
+		}
+	}
+	return names
+}
+
+// AnnouncementMaintainer keeps the is_current flag of announcements in line with their
+// dates, times and weekdays as time passes
+type AnnouncementMaintainer struct {
+	repo      *Repository
+	heartbeat *health.Heartbeat
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewAnnouncementMaintainer creates a maintainer for the repository's announcements
+func NewAnnouncementMaintainer(repo *Repository) *AnnouncementMaintainer {
+	return &AnnouncementMaintainer{
+		repo:      repo,
+		heartbeat: health.NewHeartbeat(AnnouncementRefreshInterval),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start refreshes the flags now and then every AnnouncementRefreshInterval
+func (m *AnnouncementMaintainer) Start(ctx context.Context) {
+	m.refresh(ctx)
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(AnnouncementRefreshInterval)
+		defer ticker.Stop()
+		m.heartbeat.Beat()
+		defer m.heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				m.heartbeat.Beat()
+				m.refresh(ctx)
+			}
+		}
+	}()
+}
+
+// Heartbeat reports whether the refresh loop is running
+func (m *AnnouncementMaintainer) Heartbeat() *health.Heartbeat {
+	return m.heartbeat
+}
+
+// Stop gracefully stops the maintainer
+func (m *AnnouncementMaintainer) Stop() {
+	close(m.stopCh)
+	m.wg.Wait()
+}
+
+func (m *AnnouncementMaintainer) refresh(ctx context.Context) {
+	changed, err := m.repo.RefreshCurrentAnnouncements(ctx, time.Now())
+	if err != nil {
+		log.Printf("Failed to refresh current announcements: %v", err)
+		return
+	}
+	if changed > 0 {
+		log.Printf("Updated is_current of %d announcements", changed)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
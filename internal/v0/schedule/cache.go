@@ -0,0 +1,89 @@
+package schedule
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// menuCacheTTL bounds how long a resolved menu is served from memory. Writes
+	// through the repository clear the cache at once; the TTL catches up with
+	// writes made by another instance and with a replica that was behind.
+	menuCacheTTL = 5 * time.Minute
+
+	// menuCacheSize bounds the dates kept, so clients walking through arbitrary
+	// dates cannot grow the cache without limit
+	menuCacheSize = 1024
+)
+
+// menuCache holds the resolved menus of recently requested dates, so the
+// lunch-hour traffic for today's menu does not redo the version lookup, the
+// rotation math and the food joins on every request. Cached menus are shared
+// between callers and must not be modified.
+type menuCache struct {
+	mu      sync.Mutex
+	entries map[string]menuEntry
+
+	// generation changes on every invalidation, so a menu resolved while a
+	// write committed is not stored over the invalidation
+	generation uint64
+}
+
+type menuEntry struct {
+	menu      *DateSchedule // nil when no schedule covers the date
+	expiresAt time.Time
+}
+
+func newMenuCache() *menuCache {
+	return &menuCache{entries: make(map[string]menuEntry)}
+}
+
+// get returns the cached menu of date and whether there was one. It also
+// returns the generation to pass to put once a missing menu is resolved.
+func (c *menuCache) get(date string) (*DateSchedule, bool, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[date]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(c.entries, date)
+		ok = false
+	}
+	return entry.menu, ok, c.generation
+}
+
+// put stores the menu of date resolved during generation; it is dropped when
+// the cache was invalidated since
+func (c *menuCache) put(date string, menu *DateSchedule, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	if len(c.entries) >= menuCacheSize {
+		clear(c.entries)
+	}
+	c.entries[date] = menuEntry{menu: menu, expiresAt: time.Now().Add(menuCacheTTL)}
+}
+
+// invalidate drops every cached menu
+func (c *menuCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	clear(c.entries)
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,153 @@
+package schedule
+
+import (
+	"API/internal/calendar"
+	"API/internal/campustime"
+	"context"
+	"strings"
+	"time"
+)
+
+// CalendarMeals is the name of the cafeteria's calendar (see calendar.Collection)
+const CalendarMeals = "meals"
+
+// mealTitles name the meals' calendar entries by language
+var mealTitles = map[string]map[string]string{
+	"lunch":  {LanguageGreek: "Μεσημεριανό", LanguageEnglish: "Lunch"},
+	"dinner": {LanguageGreek: "Βραδινό", LanguageEnglish: "Dinner"},
+}
+
+// Calendars returns what the cafeteria publishes for calendar apps: every lunch and
+// dinner with its dishes, at the hours the restaurants serve it, and the days it is closed
+func Calendars(repo *Repository) []calendar.Collection {
+	return []calendar.Collection{
+		{
+			Name:    CalendarMeals,
+			Feature: FeatureSlug,
+			Color:   "#E07B00",
+			Title: func(lang string) string {
+				if lang == LanguageEnglish {
+					return "DUTH cafeteria"
+				}
+				return "Λέσχη ΔΠΘ"
+			},
+			Entries: func(ctx context.Context, userID int64, lang string, from, to time.Time) ([]calendar.Entry, error) {
+				return mealEntries(ctx, repo, lang, from, to)
+			},
+		},
+	}
+}
+
+// mealEntries returns the meals served between from and to
+func mealEntries(ctx context.Context, repo *Repository, lang string, from, to time.Time) ([]calendar.Entry, error) {
+	y, m, d := from.In(campustime.Location).Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, campustime.Location)
+	days, err := repo.GetRangeSchedule(ctx, start, to.In(campustime.Location))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []calendar.Entry{}
+	for _, day := range days {
+		date, err := time.ParseInLocation("2006-01-02", day.Date, campustime.Location)
+		if err != nil {
+			return nil, err
+		}
+		menu := day.DateSchedule.Localized(lang)
+		if menu.Closure != nil && len(menu.Lunch) == 0 && len(menu.Dinner) == 0 {
+			summary := "Η λέσχη είναι κλειστή"
+			if lang == LanguageEnglish {
+				summary = "The cafeteria is closed"
+			}
+			entries = append(entries, calendar.Entry{
+				UID:         "closed-" + day.Date,
+				Summary:     summary,
+				Description: menu.Closure.Reason,
+				Start:       date,
+				End:         date.AddDate(0, 0, 1),
+				AllDay:      true,
+			})
+			continue
+		}
+
+		for _, meal := range []struct {
+			typ   string
+			foods []Food
+		}{{"lunch", menu.Lunch}, {"dinner", menu.Dinner}} {
+			if len(meal.foods) == 0 {
+				continue
+			}
+			dishes := make([]string, len(meal.foods))
+			for i, food := range meal.foods {
+				dishes[i] = food.Name
+			}
+			description := strings.Join(dishes, "\n")
+			if menu.Closure != nil && menu.Closure.Reason != "" {
+				description = menu.Closure.Reason + "\n\n" + description
+			}
+			entry := calendar.Entry{
+				UID:         "meal-" + day.Date + "-" + meal.typ,
+				Summary:     mealTitles[meal.typ][lang],
+				Description: description,
+			}
+			entry.Start, entry.End, entry.Location = servingTime(date, menu.Hours, meal.typ)
+			entry.AllDay = entry.Start.IsZero()
+			if entry.AllDay {
+				entry.Start, entry.End = date, date.AddDate(0, 0, 1)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	overlapping := entries[:0]
+	for _, e := range entries {
+		if e.Overlaps(from, to) {
+			overlapping = append(overlapping, e)
+		}
+	}
+	return overlapping, nil
+}
+
+// servingTime returns when a meal is served on date, from the first restaurant opening to
+// the last one closing, and where. The times are zero when no restaurant lists the meal.
+func servingTime(date time.Time, hours []MealHours, mealType string) (time.Time, time.Time, string) {
+	var opens, closes time.Time
+	var restaurants []string
+	for _, h := range hours {
+		if h.MealType != mealType {
+			continue
+		}
+		o, err1 := time.Parse("15:04", h.Opens)
+		c, err2 := time.Parse("15:04", h.Closes)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		// Set the clock rather than add to midnight, which is off on the days DST changes
+		y, m, d := date.Date()
+		o = time.Date(y, m, d, o.Hour(), o.Minute(), 0, 0, campustime.Location)
+		c = time.Date(y, m, d, c.Hour(), c.Minute(), 0, 0, campustime.Location)
+		if opens.IsZero() || o.Before(opens) {
+			opens = o
+		}
+		if c.After(closes) {
+			closes = c
+		}
+		restaurants = append(restaurants, h.Restaurant)
+	}
+	return opens, closes, strings.Join(restaurants, ", ")
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,272 @@
+package schedule
+
+import (
+	"API/internal/campustime"
+	"API/internal/health"
+	"API/internal/pagination"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const (
+	// MenuPostInterval is how often channels are checked for a due post
+	MenuPostInterval = time.Minute
+
+	// menuPostRetry is how long a channel whose post failed waits before the next try
+	menuPostRetry = 15 * time.Minute
+
+	// Message length limits of the chat services
+	discordMaxLength  = 2000
+	telegramMaxLength = 4096
+)
+
+// telegramAPI is where Telegram bot requests are sent
+const telegramAPI = "https://api.telegram.org"
+
+// defaultMenuTemplates are used by channels without a template of their own
+var defaultMenuTemplates = map[string]string{
+	LanguageGreek: `{{if .Closed}}Το εστιατόριο είναι κλειστό σήμερα {{.Date}}{{with .Closure}}: {{.}}{{end}}
+{{else}}Μενού {{.Date}}{{with .Closure}} ({{.}}){{end}}
+{{if .Lunch}}Μεσημεριανό: {{join .Lunch ", "}}
+{{end}}{{if .Dinner}}Βραδινό: {{join .Dinner ", "}}
+{{end}}{{end}}{{range .Announcements}}
+📢 {{.}}{{end}}`,
+	LanguageEnglish: `{{if .Closed}}The restaurant is closed today {{.Date}}{{with .Closure}}: {{.}}{{end}}
+{{else}}Menu for {{.Date}}{{with .Closure}} ({{.}}){{end}}
+{{if .Lunch}}Lunch: {{join .Lunch ", "}}
+{{end}}{{if .Dinner}}Dinner: {{join .Dinner ", "}}
+{{end}}{{end}}{{range .Announcements}}
+📢 {{.}}{{end}}`,
+}
+
+var templateFuncs = template.FuncMap{"join": strings.Join}
+
+// MenuPost is what channel templates are executed with
+type MenuPost struct {
+	Date          string // DD/MM/YYYY
+	Lunch         []string
+	Dinner        []string
+	Closed        bool   // a closure without a replacement menu covers the date
+	Closure       string // the reason of the closure covering the date, if any
+	Hours         []MealHours
+	Announcements []string
+}
+
+// parseMenuTemplate parses a channel template, the default one of lang when empty
+func parseMenuTemplate(text, lang string) (*template.Template, error) {
+	if text == "" {
+		text = defaultMenuTemplates[lang]
+	}
+	return template.New("menu").Funcs(templateFuncs).Parse(text)
+}
+
+// MenuPoster posts the day's menu and announcements to the configured chat channels at
+// their post time
+type MenuPoster struct {
+	repo      *Repository
+	client    *http.Client
+	heartbeat *health.Heartbeat
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewMenuPoster creates a poster for the repository's channels
+func NewMenuPoster(repo *Repository) *MenuPoster {
+	return &MenuPoster{
+		repo:      repo,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		heartbeat: health.NewHeartbeat(MenuPostInterval),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start posts to the due channels now and then every MenuPostInterval
+func (p *MenuPoster) Start(ctx context.Context) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(MenuPostInterval)
+		defer ticker.Stop()
+		p.heartbeat.Beat()
+		defer p.heartbeat.Stop()
+		p.postDue(ctx)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.stopCh:
+				return
+			case <-ticker.C:
+				p.heartbeat.Beat()
+				p.postDue(ctx)
+			}
+		}
+	}()
+}
+
+// Heartbeat reports whether the posting loop is running
+func (p *MenuPoster) Heartbeat() *health.Heartbeat {
+	return p.heartbeat
+}
+
+// Stop gracefully stops the poster
+func (p *MenuPoster) Stop() {
+	close(p.stopCh)
+	p.wg.Wait()
+}
+
+func (p *MenuPoster) postDue(ctx context.Context) {
+	day := campustime.Today()
+	date := day.Format("2006-01-02")
+	channels, err := p.repo.DueChannels(ctx, date, time.Now().In(campustime.Location).Format("15:04"))
+	if err != nil {
+		log.Printf("Failed to list due menu channels: %v", err)
+		return
+	}
+	for _, ch := range channels {
+		claimed, err := p.repo.ClaimChannel(ctx, ch.ID, date)
+		if err != nil {
+			log.Printf("Failed to claim menu channel %d: %v", ch.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		postErr := p.Post(ctx, &ch, day)
+		if postErr != nil {
+			log.Printf("Failed to post the menu to channel %d: %v", ch.ID, postErr)
+		}
+		if err := p.repo.RecordChannelPost(ctx, ch.ID, ch.LastPostedOn, postErr, menuPostRetry); err != nil {
+			log.Printf("Failed to record the post to menu channel %d: %v", ch.ID, err)
+		}
+	}
+}
+
+// Post sends the menu and announcements of date to a channel right away
+func (p *MenuPoster) Post(ctx context.Context, ch *MenuChannel, date time.Time) error {
+	text, err := p.render(ctx, ch, date)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil // e.g. a template that only reports announcements, on a day without any
+	}
+	switch ch.Kind {
+	case ChannelDiscord:
+		return p.send(ctx, ch.Target, map[string]string{"content": truncate(text, discordMaxLength)})
+	case ChannelTelegram:
+		return p.send(ctx, telegramAPI+"/bot"+ch.BotToken+"/sendMessage", map[string]string{"chat_id": ch.Target, "text": truncate(text, telegramMaxLength)})
+	}
+	return fmt.Errorf("unknown channel kind %q", ch.Kind)
+}
+
+// render executes a channel's template with the menu and announcements of date
+func (p *MenuPoster) render(ctx context.Context, ch *MenuChannel, date time.Time) (string, error) {
+	day := date.Format("2006-01-02")
+	post := MenuPost{Date: date.Format("02/01/2006")}
+
+	menu, err := p.repo.GetDateSchedule(ctx, day)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	if menu != nil {
+		localized := menu.Localized(ch.Language)
+		for _, food := range localized.Lunch {
+			post.Lunch = append(post.Lunch, food.Name)
+		}
+		for _, food := range localized.Dinner {
+			post.Dinner = append(post.Dinner, food.Name)
+		}
+		post.Hours = localized.Hours
+		if localized.Closure != nil {
+			post.Closure = localized.Closure.Reason
+			post.Closed = len(post.Lunch)+len(post.Dinner) == 0
+		}
+	}
+
+	announcements, err := p.repo.ListAnnouncements(ctx, AnnouncementFilter{ActiveOn: day}, pagination.Params{Limit: pagination.MaxLimit})
+	if err != nil {
+		return "", err
+	}
+	for _, a := range announcements {
+		post.Announcements = append(post.Announcements, a.Localized(ch.Language).Content)
+	}
+	if len(post.Lunch)+len(post.Dinner)+len(post.Announcements) == 0 && !post.Closed {
+		return "", nil // nothing served or announced, e.g. during the summer break
+	}
+
+	tmpl, err := parseMenuTemplate(ch.Template, ch.Language)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, post); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (p *MenuPoster) send(ctx context.Context, endpoint string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// The URL holds the Telegram bot token, which must not end up in last_error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("responded with %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// truncate shortens text to at most max characters
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -1,165 +1,2590 @@
 package schedule
 
 import (
+	"API/internal/campustime"
+	"API/internal/databases"
+	"API/internal/events"
+	"API/internal/pagination"
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Repository struct {
-	db *sql.DB
+	db     *sql.DB
+	read   *sql.DB // serves the open-data reads; db unless a replica is set
+	outbox *events.Outbox
+	images *ImageStore // nil when images are not stored
+	menus  *menuCache  // resolved menus by date, cleared on every write
 }
 
-// NewRepository creates a new schedule repository
-func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+// NewRepository creates a new schedule repository. Publications are recorded in the outbox, which may be nil.
+func NewRepository(db *sql.DB, outbox *events.Outbox) *Repository {
+	return &Repository{db: db, read: db, outbox: outbox, menus: newMenuCache()}
+}
+
+// SetReplica routes the open-data reads (the schedule itself) to a read-only
+// replica. Writes, and reads that must see them (e.g. announcement receipts
+// and import deduplication), stay on the primary.
+func (r *Repository) SetReplica(replica *sql.DB) {
+	r.read = replica
+}
+
+// SetImages stores food images in images and adds their URLs to foods
+func (r *Repository) SetImages(images *ImageStore) {
+	r.images = images
+}
+
+// WithTx runs fn in a transaction like databases.WithTx. Events fn records in the outbox
+// are dispatched once it commits.
+func (r *Repository) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if err := databases.WithTx(ctx, r.db, fn); err != nil {
+		return err
+	}
+	r.menus.invalidate()
+	r.outbox.Notify()
+	return nil
+}
+
+var (
+	// ErrDuplicateFood is returned when another food already has the name, ignoring case
+	ErrDuplicateFood = errors.New("a food with this name already exists")
+
+	// ErrFoodInUse is returned when deleting a food that schedule items or closures still serve
+	ErrFoodInUse = errors.New("food is still served by schedule items or closures")
+)
+
+// foodColumns are scanned with foodRow.dest; f is the foods table
+const foodColumns = "f.id, f.name, COALESCE(f.name_en, ''), f.calories, f.protein, f.carbohydrates, f.fat, f.image_key"
+
+// foodRow scans foodColumns, whose nutrition values and image may be NULL
+type foodRow struct {
+	food                                  Food
+	calories, protein, carbohydrates, fat sql.NullFloat64
+	imageKey                              sql.NullString
+}
+
+func (row *foodRow) dest() []interface{} {
+	return []interface{}{&row.food.ID, &row.food.Name, &row.food.NameEn, &row.calories, &row.protein, &row.carbohydrates, &row.fat, &row.imageKey}
+}
+
+// result returns the scanned food, with Nutrition left nil when no value is known
+func (row *foodRow) result() Food {
+	f := row.food
+	if row.calories.Valid || row.protein.Valid || row.carbohydrates.Valid || row.fat.Valid {
+		f.Nutrition = &Nutrition{
+			Calories:      nullFloat(row.calories),
+			Protein:       nullFloat(row.protein),
+			Carbohydrates: nullFloat(row.carbohydrates),
+			Fat:           nullFloat(row.fat),
+		}
+	}
+	f.imageKey = row.imageKey.String
+	return f
+}
+
+func nullFloat(n sql.NullFloat64) *float64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Float64
+}
+
+// attachFoodDetails loads the allergens, tags and ratings of foods from db and adds their
+// image URLs
+func (r *Repository) attachFoodDetails(ctx context.Context, db *sql.DB, foods []*Food) error {
+	if r.images != nil {
+		for _, f := range foods {
+			if f.imageKey != "" {
+				f.Image = r.images.URLs(f.imageKey)
+			}
+		}
+	}
+	if err := attachFoodLabels(ctx, db, foods); err != nil {
+		return err
+	}
+	return attachFoodRatings(ctx, db, foods)
+}
+
+// attachFoodRatings loads the average rating of foods from db, leaving out hidden ratings
+func attachFoodRatings(ctx context.Context, db *sql.DB, foods []*Food) error {
+	if len(foods) == 0 {
+		return nil
+	}
+	byID := make(map[int][]*Food, len(foods))
+	args := make([]interface{}, 0, len(foods))
+	for _, f := range foods {
+		if _, ok := byID[f.ID]; !ok {
+			args = append(args, f.ID)
+		}
+		byID[f.ID] = append(byID[f.ID], f)
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT food_id, AVG(rating), COUNT(*) FROM food_ratings
+		WHERE hidden = 0 AND food_id IN (`+strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")+`)
+		GROUP BY food_id
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var foodID int
+		var rating Rating
+		if err := rows.Scan(&foodID, &rating.Average, &rating.Count); err != nil {
+			return err
+		}
+		rating.Average = math.Round(rating.Average*10) / 10
+		for _, f := range byID[foodID] {
+			f.Rating = &Rating{Average: rating.Average, Count: rating.Count}
+		}
+	}
+	return rows.Err()
+}
+
+// attachFoodLabels loads the allergens and tags of foods from db in two queries
+func attachFoodLabels(ctx context.Context, db *sql.DB, foods []*Food) error {
+	if len(foods) == 0 {
+		return nil
+	}
+	byID := make(map[int][]*Food, len(foods))
+	args := make([]interface{}, 0, len(foods))
+	for _, f := range foods {
+		f.Allergens, f.Tags = []string{}, []string{}
+		if _, ok := byID[f.ID]; !ok {
+			args = append(args, f.ID)
+		}
+		byID[f.ID] = append(byID[f.ID], f)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+
+	for _, label := range []struct {
+		query string
+		add   func(f *Food, value string)
+	}{
+		{"SELECT food_id, allergen FROM food_allergens WHERE food_id IN (" + placeholders + ") ORDER BY allergen", func(f *Food, v string) { f.Allergens = append(f.Allergens, v) }},
+		{"SELECT food_id, tag FROM food_tags WHERE food_id IN (" + placeholders + ") ORDER BY tag", func(f *Food, v string) { f.Tags = append(f.Tags, v) }},
+	} {
+		rows, err := db.QueryContext(ctx, label.query, args...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var foodID int
+			var value string
+			if err := rows.Scan(&foodID, &value); err != nil {
+				rows.Close()
+				return err
+			}
+			for _, f := range byID[foodID] {
+				label.add(f, value)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFoodLabels replaces the allergens and tags of a food; nil lists are left unchanged
+func setFoodLabels(ctx context.Context, tx *sql.Tx, foodID int64, allergens, tags *[]string) error {
+	for _, label := range []struct {
+		table, column string
+		values        *[]string
+	}{
+		{"food_allergens", "allergen", allergens},
+		{"food_tags", "tag", tags},
+	} {
+		if label.values == nil {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+label.table+" WHERE food_id = ?", foodID); err != nil {
+			return err
+		}
+		for _, value := range *label.values {
+			if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO "+label.table+" (food_id, "+label.column+") VALUES (?, ?)", foodID, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nutritionArgs returns the nutrition column values, NULL for unknown ones
+func nutritionArgs(n *Nutrition) []interface{} {
+	if n == nil {
+		return []interface{}{nil, nil, nil, nil}
+	}
+	args := make([]interface{}, 0, 4)
+	for _, v := range []*float64{n.Calories, n.Protein, n.Carbohydrates, n.Fat} {
+		if v == nil {
+			args = append(args, nil)
+		} else {
+			args = append(args, *v)
+		}
+	}
+	return args
 }
 
 // CreateFood adds a new food item to the database
-func (r *Repository) CreateFood(name string) error {
-	_, err := r.db.Exec("INSERT INTO foods (name) VALUES (?)", name)
+func (r *Repository) CreateFood(ctx context.Context, f Food) (int64, error) {
+	var id int64
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		if err := checkDuplicateFood(ctx, tx, f.Name, 0); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO foods (name, name_en, calories, protein, carbohydrates, fat) VALUES (?, ?, ?, ?, ?, ?)
+		`, append([]interface{}{f.Name, databases.NullString(f.NameEn)}, nutritionArgs(f.Nutrition)...)...)
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		return setFoodLabels(ctx, tx, id, &f.Allergens, &f.Tags)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// checkDuplicateFood fails with ErrDuplicateFood when a food other than exceptID is named name
+func checkDuplicateFood(ctx context.Context, tx *sql.Tx, name string, exceptID int64) error {
+	var id int64
+	err := tx.QueryRowContext(ctx, "SELECT id FROM foods WHERE name = ? COLLATE NOCASE AND id != ? LIMIT 1", name, exceptID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return ErrDuplicateFood
+}
+
+// GetFood returns a food by ID, or nil when it does not exist
+func (r *Repository) GetFood(ctx context.Context, id int64) (*Food, error) {
+	var row foodRow
+	err := r.db.QueryRowContext(ctx, "SELECT "+foodColumns+" FROM foods f WHERE f.id = ?", id).Scan(row.dest()...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	f := row.result()
+	if err := r.attachFoodDetails(ctx, r.db, []*Food{&f}); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// MissingFoods returns the IDs among ids that no food has
+func (r *Repository) MissingFoods(ctx context.Context, ids []int) ([]int, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	rows, err := r.db.QueryContext(ctx, "SELECT id FROM foods WHERE id IN ("+placeholders+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make(map[int]bool, len(ids))
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		found[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var missing []int
+	for _, id := range ids {
+		if !found[id] && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+// VersionExists reports whether a schedule version exists
+func (r *Repository) VersionExists(ctx context.Context, id int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM schedule_versions WHERE id = ?)", id).Scan(&exists)
+	return exists, err
+}
+
+// ListFoods returns a page of foods whose name contains query, newest first. The page holds
+// one extra food when another page follows (see pagination.Next).
+func (r *Repository) ListFoods(ctx context.Context, query string, page pagination.Params) ([]Food, error) {
+	where, args := foodFilter(query)
+	after, afterArgs := page.Where("f.id")
+	args = append(append(args, afterArgs...), page.FetchLimit())
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+foodColumns+` FROM foods f
+		WHERE `+where+` AND `+after+`
+		ORDER BY f.id DESC
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	foods := []Food{}
+	for rows.Next() {
+		var row foodRow
+		if err := rows.Scan(row.dest()...); err != nil {
+			return nil, err
+		}
+		foods = append(foods, row.result())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return foods, r.attachFoodDetails(ctx, r.db, foodPointers(foods))
+}
+
+func foodPointers(foods []Food) []*Food {
+	pointers := make([]*Food, len(foods))
+	for i := range foods {
+		pointers[i] = &foods[i]
+	}
+	return pointers
+}
+
+// CountFoods returns the number of foods whose name contains query
+func (r *Repository) CountFoods(ctx context.Context, query string) (int, error) {
+	where, args := foodFilter(query)
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM foods f WHERE "+where, args...).Scan(&count)
+	return count, err
+}
+
+func foodFilter(query string) (string, []interface{}) {
+	if q := strings.TrimSpace(query); q != "" {
+		return "LOWER(f.name) LIKE ?", []interface{}{"%" + strings.ToLower(q) + "%"}
+	}
+	return "1 = 1", nil
+}
+
+// UpdateFood applies the set fields of update. It returns false when the food does not exist.
+func (r *Repository) UpdateFood(ctx context.Context, id int64, update FoodUpdate) (bool, error) {
+	var sets []string
+	var args []interface{}
+	if update.Name != nil {
+		sets = append(sets, "name = ?")
+		args = append(args, *update.Name)
+	}
+	if update.NameEn != nil {
+		sets = append(sets, "name_en = ?")
+		args = append(args, databases.NullString(*update.NameEn))
+	}
+	if update.Nutrition != nil {
+		sets = append(sets, "calories = ?", "protein = ?", "carbohydrates = ?", "fat = ?")
+		args = append(args, nutritionArgs(update.Nutrition)...)
+	}
+
+	var updated bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM foods WHERE id = ?)", id).Scan(&exists); err != nil || !exists {
+			return err
+		}
+		updated = true
+
+		if update.Name != nil {
+			if err := checkDuplicateFood(ctx, tx, *update.Name, id); err != nil {
+				return err
+			}
+		}
+		if len(sets) > 0 {
+			if _, err := tx.ExecContext(ctx, "UPDATE foods SET "+strings.Join(sets, ", ")+" WHERE id = ?", append(args, id)...); err != nil {
+				return err
+			}
+		}
+		return setFoodLabels(ctx, tx, id, update.Allergens, update.Tags)
+	})
+	return updated, err
+}
+
+// DeleteFood deletes a food no schedule item or closure serves, since deleting it would silently
+// change published menus. It returns false when the food does not exist.
+func (r *Repository) DeleteFood(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	var imageKey sql.NullString
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		var references int
+		err := tx.QueryRowContext(ctx, `
+			SELECT (SELECT COUNT(*) FROM schedule_dishes WHERE food_id = ?) + (SELECT COUNT(*) FROM closure_dishes WHERE food_id = ?)
+		`, id, id).Scan(&references)
+		if err != nil {
+			return err
+		}
+		if references > 0 {
+			return ErrFoodInUse
+		}
+		if err := tx.QueryRowContext(ctx, "SELECT image_key FROM foods WHERE id = ?", id).Scan(&imageKey); err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		for _, table := range []string{"food_allergens", "food_tags", "food_ratings", "food_favorites"} {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE food_id = ?", id); err != nil {
+				return err
+			}
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM foods WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	r.deleteImage(ctx, imageKey.String)
+	return deleted, nil
+}
+
+// SetFoodImage sets the image of a food, "" removing it, and deletes the variants of the
+// image it replaces. It returns false when the food does not exist.
+func (r *Repository) SetFoodImage(ctx context.Context, id int64, key string) (bool, error) {
+	var previous sql.NullString
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, "SELECT image_key FROM foods WHERE id = ?", id).Scan(&previous); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE foods SET image_key = ? WHERE id = ?", databases.NullString(key), id)
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	r.deleteImage(ctx, previous.String)
+	return true, nil
+}
+
+// deleteImage removes the variants of an image no food uses anymore. Failures are only
+// logged, since they leave unused files behind rather than broken menus.
+func (r *Repository) deleteImage(ctx context.Context, key string) {
+	if key == "" || r.images == nil {
+		return
+	}
+	if err := r.images.Delete(ctx, key); err != nil {
+		log.Printf("Failed to delete food image %s: %v", key, err)
+	}
+}
+
+// CreateVersion adds a new schedule version to the database
+// TODO: Add validation for date formats
+func (r *Repository) CreateVersion(ctx context.Context, start, end string, active bool) (int64, error) {
+	var id int64
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "INSERT INTO schedule_versions (starting_date, ending_date, is_current, updated_at) VALUES (?, ?, ?, ?)", start, end, active, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+
+		return r.outbox.Enqueue(tx, events.ScheduleVersionPublished{
+			VersionID:    id,
+			StartingDate: start,
+			EndingDate:   end,
+			IsCurrent:    active,
+			OccurredAt:   time.Now(),
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// CreateScheduleItem adds a new schedule item to the database with associated dishes. What day, week and meal type is this dish []int for.
+func (r *Repository) CreateScheduleItem(ctx context.Context, versionID int, week, day int, mealType string, dishIDs []int) error {
+	return r.WithTx(ctx, func(tx *sql.Tx) error {
+		if err := insertScheduleItem(ctx, tx, int64(versionID), week, day, mealType, dishIDs); err != nil {
+			return err
+		}
+		if err := recordChanges(ctx, tx, int64(versionID), nil, &slotDishes{week, day, mealType, dishIDs}); err != nil {
+			return err
+		}
+		return markVersionEdited(ctx, tx, int64(versionID))
+	})
+}
+
+func insertScheduleItem(ctx context.Context, tx *sql.Tx, versionID int64, week, day int, mealType string, dishIDs []int) error {
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO schedule (version_id, week_number, day_number, meal_type) 
+		VALUES (?, ?, ?, ?)`,
+		versionID, week, day, mealType,
+	)
+	if err != nil {
+		return err
+	}
+
+	scheduleID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO schedule_dishes (schedule_id, food_id) VALUES (?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, foodID := range dishIDs {
+		if _, err := stmt.ExecContext(ctx, scheduleID, foodID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrUnknownFood is returned when a dish list references a food that does not exist
+var ErrUnknownFood = errors.New("dish_ids references a food that does not exist")
+
+// GetScheduleItem returns a schedule item with its dishes, or nil when it does not exist
+func (r *Repository) GetScheduleItem(ctx context.Context, id int64) (*ScheduleItem, error) {
+	var item ScheduleItem
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, version_id, week_number, day_number, meal_type FROM schedule WHERE id = ?
+	`, id).Scan(&item.ID, &item.VersionID, &item.WeekNumber, &item.DayNumber, &item.MealType)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT food_id FROM schedule_dishes WHERE schedule_id = ? ORDER BY rowid", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	item.DishIDs = []int{}
+	for rows.Next() {
+		var foodID int
+		if err := rows.Scan(&foodID); err != nil {
+			return nil, err
+		}
+		item.DishIDs = append(item.DishIDs, foodID)
+	}
+	return &item, rows.Err()
+}
+
+// UpdateScheduleItem applies the set fields of update, replacing the dish list in the same
+// transaction. It returns false when the item does not exist.
+func (r *Repository) UpdateScheduleItem(ctx context.Context, id int64, update ScheduleItemUpdate) (bool, error) {
+	var sets []string
+	var args []interface{}
+	if update.WeekNumber != nil {
+		sets = append(sets, "week_number = ?")
+		args = append(args, *update.WeekNumber)
+	}
+	if update.DayNumber != nil {
+		sets = append(sets, "day_number = ?")
+		args = append(args, *update.DayNumber)
+	}
+	if update.MealType != nil {
+		sets = append(sets, "meal_type = ?")
+		args = append(args, *update.MealType)
+	}
+
+	var updated bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		var versionID int64
+		err := tx.QueryRowContext(ctx, "SELECT version_id FROM schedule WHERE id = ?", id).Scan(&versionID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		updated = true
+		before, err := getSlotDishes(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if len(sets) > 0 {
+			if _, err := tx.ExecContext(ctx, "UPDATE schedule SET "+strings.Join(sets, ", ")+" WHERE id = ?", append(args, id)...); err != nil {
+				return err
+			}
+		}
+		if update.DishIDs != nil {
+			if err := replaceDishes(ctx, tx, id, update.DishIDs); err != nil {
+				return err
+			}
+		}
+		after, err := getSlotDishes(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := recordChanges(ctx, tx, versionID, before, after); err != nil {
+			return err
+		}
+		return markVersionEdited(ctx, tx, versionID)
+	})
+	return updated, err
+}
+
+// DeleteScheduleItem deletes a schedule item and its dish list. It returns false when the
+// item does not exist.
+func (r *Repository) DeleteScheduleItem(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		var versionID int64
+		err := tx.QueryRowContext(ctx, "SELECT version_id FROM schedule WHERE id = ?", id).Scan(&versionID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		deleted = true
+		before, err := getSlotDishes(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schedule_dishes WHERE schedule_id = ?", id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schedule WHERE id = ?", id); err != nil {
+			return err
+		}
+		if err := recordChanges(ctx, tx, versionID, before, nil); err != nil {
+			return err
+		}
+		return markVersionEdited(ctx, tx, versionID)
+	})
+	return deleted, err
+}
+
+// replaceDishes swaps the dish list of a schedule item, failing with ErrUnknownFood when a
+// food does not exist
+func replaceDishes(ctx context.Context, tx *sql.Tx, scheduleID int64, dishIDs []int) error {
+	unique := make(map[int]bool, len(dishIDs))
+	args := make([]interface{}, 0, len(dishIDs))
+	for _, id := range dishIDs {
+		if !unique[id] {
+			unique[id] = true
+			args = append(args, id)
+		}
+	}
+	var found int
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM foods WHERE id IN ("+placeholders+")", args...).Scan(&found); err != nil {
+		return err
+	}
+	if found != len(args) {
+		return ErrUnknownFood
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schedule_dishes WHERE schedule_id = ?", scheduleID); err != nil {
+		return err
+	}
+	for _, foodID := range args {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schedule_dishes (schedule_id, food_id) VALUES (?, ?)", scheduleID, foodID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// slotDishes is the meal slot of a schedule item and the dishes it serves
+type slotDishes struct {
+	week, day int
+	meal      string
+	dishes    []int
+}
+
+// getSlotDishes returns the slot and dishes of a schedule item
+func getSlotDishes(ctx context.Context, tx *sql.Tx, id int64) (*slotDishes, error) {
+	var slot slotDishes
+	err := tx.QueryRowContext(ctx, "SELECT week_number, day_number, meal_type FROM schedule WHERE id = ?", id).Scan(&slot.week, &slot.day, &slot.meal)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT food_id FROM schedule_dishes WHERE schedule_id = ? ORDER BY rowid", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var foodID int
+		if err := rows.Scan(&foodID); err != nil {
+			return nil, err
+		}
+		slot.dishes = append(slot.dishes, foodID)
+	}
+	return &slot, rows.Err()
+}
+
+// recordChanges records the dishes an edit of a schedule item added and removed, given the
+// item before and after it; nil stands for an item that does not exist
+func recordChanges(ctx context.Context, tx *sql.Tx, versionID int64, before, after *slotDishes) error {
+	type dish struct {
+		week, day int
+		meal      string
+		food      int
+	}
+	var order []dish
+	net := make(map[dish]int)
+	for _, side := range []struct {
+		slot  *slotDishes
+		delta int
+	}{{before, -1}, {after, 1}} {
+		if side.slot == nil {
+			continue
+		}
+		seen := make(map[int]bool)
+		for _, food := range side.slot.dishes {
+			if seen[food] {
+				continue
+			}
+			seen[food] = true
+			d := dish{side.slot.week, side.slot.day, side.slot.meal, food}
+			if _, ok := net[d]; !ok {
+				order = append(order, d)
+			}
+			net[d] += side.delta
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, d := range order {
+		change := "added"
+		switch net[d] {
+		case 0:
+			continue
+		case -1:
+			change = "removed"
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO schedule_changes (version_id, week_number, day_number, meal_type, food_id, change, changed_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, versionID, d.week, d.day, d.meal, d.food, change, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markVersionEdited records that a version's items changed. Its import hash is cleared, as
+// the items no longer match the imported file, so importing that file again creates a
+// fresh version.
+func markVersionEdited(ctx context.Context, tx *sql.Tx, versionID int64) error {
+	_, err := tx.ExecContext(ctx, "UPDATE schedule_versions SET content_hash = NULL, updated_at = ? WHERE id = ?", time.Now().UTC(), versionID)
+	return err
+}
+
+// ImportSchedule creates a version with all of its items in one go. When a version with the
+// same normalized items was imported before, nothing is created and that version is returned
+// with Duplicate set, so a menu file that arrives twice is never published twice.
+func (r *Repository) ImportSchedule(ctx context.Context, imp ScheduleImport) (*ImportResult, error) {
+	items := normalizeImportItems(imp.Items)
+	hash := hashImportItems(items)
+
+	existing, err := r.getVersionByContentHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != 0 {
+		return &ImportResult{VersionID: existing, Duplicate: true, ContentHash: hash}, nil
+	}
+
+	var id int64
+	err = r.WithTx(ctx, func(tx *sql.Tx) error {
+		id, err = r.insertImportedVersion(ctx, tx, imp, items, hash)
+		return err
+	})
+	if err != nil {
+		// A concurrent import of the same file won the race on the unique index
+		if id, lookupErr := r.getVersionByContentHash(ctx, hash); lookupErr == nil && id != 0 {
+			return &ImportResult{VersionID: id, Duplicate: true, ContentHash: hash}, nil
+		}
+		return nil, err
+	}
+	return &ImportResult{VersionID: id, ContentHash: hash}, nil
+}
+
+// insertImportedVersion creates a version holding normalized items, tagged with their hash
+func (r *Repository) insertImportedVersion(ctx context.Context, tx *sql.Tx, imp ScheduleImport, items []ScheduleImportItem, hash string) (int64, error) {
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO schedule_versions (starting_date, ending_date, is_current, content_hash, updated_at) VALUES (?, ?, ?, ?, ?)
+	`, imp.StartingDate, imp.EndingDate, imp.IsCurrent, hash, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, item := range items {
+		if err := insertScheduleItem(ctx, tx, id, item.WeekNumber, item.DayNumber, item.MealType, item.DishIDs); err != nil {
+			return 0, fmt.Errorf("week %d day %d %s: %w", item.WeekNumber, item.DayNumber, item.MealType, err)
+		}
+	}
+
+	return id, r.outbox.Enqueue(tx, events.ScheduleVersionPublished{
+		VersionID:    id,
+		StartingDate: imp.StartingDate,
+		EndingDate:   imp.EndingDate,
+		IsCurrent:    imp.IsCurrent,
+		OccurredAt:   time.Now(),
+	})
+}
+
+// errImportRolledBack ends a spreadsheet import transaction that must not be committed,
+// i.e. a dry run or a duplicate
+var errImportRolledBack = errors.New("import rolled back")
+
+// ImportSpreadsheet imports a menu whose foods are named rather than referenced. Foods are
+// matched by name ignoring case, and the missing ones are created with the version and its
+// items in one transaction. When dryRun is set, or the menu matches an imported version,
+// the transaction is rolled back, so nothing is created but the result shows what would be.
+func (r *Repository) ImportSpreadsheet(ctx context.Context, imp ScheduleImport, menu SpreadsheetMenu, dryRun bool) (*SpreadsheetImportResult, error) {
+	result := &SpreadsheetImportResult{DryRun: dryRun, CreatedFoods: []string{}}
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		foodIDs := make(map[string]int)
+		var items []ScheduleImportItem
+		for _, slot := range menu.Items {
+			item := ScheduleImportItem{WeekNumber: slot.WeekNumber, DayNumber: slot.DayNumber, MealType: slot.MealType}
+			for _, name := range slot.Foods {
+				key := strings.ToLower(name)
+				id, ok := foodIDs[key]
+				if !ok {
+					created, err := resolveFood(ctx, tx, name, menu.NamesEn[key], &id)
+					if err != nil {
+						return fmt.Errorf("food %q: %w", name, err)
+					}
+					if created {
+						result.CreatedFoods = append(result.CreatedFoods, name)
+					}
+					foodIDs[key] = id
+				}
+				item.DishIDs = append(item.DishIDs, id)
+			}
+			items = append(items, item)
+			result.Dishes += len(item.DishIDs)
+		}
+		items = normalizeImportItems(items)
+		result.Items = len(items)
+		result.ContentHash = hashImportItems(items)
+
+		err := tx.QueryRowContext(ctx, "SELECT id FROM schedule_versions WHERE content_hash = ?", result.ContentHash).Scan(&result.VersionID)
+		if err == nil {
+			result.Duplicate = true
+			return errImportRolledBack
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+		if dryRun {
+			return errImportRolledBack
+		}
+		result.VersionID, err = r.insertImportedVersion(ctx, tx, imp, items, result.ContentHash)
+		return err
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// resolveFood sets id to the food named name, ignoring case, creating it when there is none
+func resolveFood(ctx context.Context, tx *sql.Tx, name, nameEn string, id *int) (bool, error) {
+	err := tx.QueryRowContext(ctx, "SELECT id FROM foods WHERE name = ? COLLATE NOCASE ORDER BY id LIMIT 1", name).Scan(id)
+	if err != sql.ErrNoRows {
+		return false, err
+	}
+	res, err := tx.ExecContext(ctx, "INSERT INTO foods (name, name_en) VALUES (?, ?)", name, databases.NullString(nameEn))
+	if err != nil {
+		return false, err
+	}
+	created, err := res.LastInsertId()
+	*id = int(created)
+	return true, err
+}
+
+// getVersionByContentHash returns the ID of the imported version with the given hash, or 0
+func (r *Repository) getVersionByContentHash(ctx context.Context, hash string) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, "SELECT id FROM schedule_versions WHERE content_hash = ?", hash).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+// normalizeImportItems merges items for the same meal slot and orders slots and dishes, so
+// that files listing the same menu in a different order or layout compare equal
+func normalizeImportItems(items []ScheduleImportItem) []ScheduleImportItem {
+	type slot struct {
+		week, day int
+		meal      string
+	}
+	dishes := make(map[slot]map[int]bool)
+	for _, item := range items {
+		key := slot{item.WeekNumber, item.DayNumber, strings.ToLower(strings.TrimSpace(item.MealType))}
+		if dishes[key] == nil {
+			dishes[key] = make(map[int]bool)
+		}
+		for _, id := range item.DishIDs {
+			dishes[key][id] = true
+		}
+	}
+
+	normalized := make([]ScheduleImportItem, 0, len(dishes))
+	for key, set := range dishes {
+		ids := make([]int, 0, len(set))
+		for id := range set {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		normalized = append(normalized, ScheduleImportItem{
+			WeekNumber: key.week,
+			DayNumber:  key.day,
+			MealType:   key.meal,
+			DishIDs:    ids,
+		})
+	}
+	sort.Slice(normalized, func(i, j int) bool {
+		a, b := normalized[i], normalized[j]
+		if a.WeekNumber != b.WeekNumber {
+			return a.WeekNumber < b.WeekNumber
+		}
+		if a.DayNumber != b.DayNumber {
+			return a.DayNumber < b.DayNumber
+		}
+		return a.MealType < b.MealType
+	})
+	return normalized
+}
+
+// hashImportItems returns the hex SHA-256 of normalized items, one "week:day:meal:dish,dish" line per slot
+func hashImportItems(items []ScheduleImportItem) string {
+	h := sha256.New()
+	for _, item := range items {
+		ids := make([]string, len(item.DishIDs))
+		for i, id := range item.DishIDs {
+			ids[i] = strconv.Itoa(id)
+		}
+		fmt.Fprintf(h, "%d:%d:%s:%s\n", item.WeekNumber, item.DayNumber, item.MealType, strings.Join(ids, ","))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CreateAnnouncement adds a new announcement to the database. is_current follows its dates
+// and times.
+func (r *Repository) CreateAnnouncement(ctx context.Context, a Announcement) (int64, error) {
+	var id int64
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO announcements (type, content, content_en, starting_date, ending_date, starts_at, ends_at, repeat_days)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, a.Type, a.Content, databases.NullString(a.ContentEn), a.StartingDate, a.EndingDate,
+			databases.NullString(a.StartsAt), databases.NullString(a.EndsAt), repeatDays(a.RepeatOn))
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE announcements SET is_current = "+announcementRunningAt+" WHERE id = ?",
+			append(announcementMoment(time.Now()), id)...); err != nil {
+			return err
+		}
+
+		return r.outbox.Enqueue(tx, events.AnnouncementPublished{
+			AnnouncementID: id,
+			Type:           a.Type,
+			Content:        a.Content,
+			ContentEn:      a.ContentEn,
+			StartingDate:   a.StartingDate,
+			EndingDate:     a.EndingDate,
+			OccurredAt:     time.Now(),
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateAnnouncement applies the set fields of update and recomputes is_current from the
+// resulting dates and times. It returns false when the announcement does not exist.
+func (r *Repository) UpdateAnnouncement(ctx context.Context, id int64, update AnnouncementUpdate) (bool, error) {
+	var sets []string
+	var args []interface{}
+	if update.Type != nil {
+		sets = append(sets, "type = ?")
+		args = append(args, *update.Type)
+	}
+	if update.Content != nil {
+		sets = append(sets, "content = ?")
+		args = append(args, *update.Content)
+	}
+	if update.ContentEn != nil {
+		sets = append(sets, "content_en = ?")
+		args = append(args, databases.NullString(*update.ContentEn))
+	}
+	if update.StartingDate != nil {
+		sets = append(sets, "starting_date = ?")
+		args = append(args, *update.StartingDate)
+	}
+	if update.EndingDate != nil {
+		sets = append(sets, "ending_date = ?")
+		args = append(args, *update.EndingDate)
+	}
+	if update.StartsAt != nil {
+		sets = append(sets, "starts_at = ?")
+		args = append(args, databases.NullString(*update.StartsAt))
+	}
+	if update.EndsAt != nil {
+		sets = append(sets, "ends_at = ?")
+		args = append(args, databases.NullString(*update.EndsAt))
+	}
+	if update.RepeatOn != nil {
+		sets = append(sets, "repeat_days = ?")
+		args = append(args, repeatDays(*update.RepeatOn))
+	}
+
+	var updated bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		if len(sets) > 0 {
+			res, err := tx.ExecContext(ctx, "UPDATE announcements SET "+strings.Join(sets, ", ")+" WHERE id = ?", append(args, id)...)
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err != nil || n == 0 {
+				return err
+			}
+		}
+		res, err := tx.ExecContext(ctx, "UPDATE announcements SET is_current = "+announcementRunningAt+" WHERE id = ?",
+			append(announcementMoment(time.Now()), id)...)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		updated = n > 0
+		return err
+	})
+	return updated, err
+}
+
+// DeleteAnnouncement deletes an announcement with its receipts. It returns false when the
+// announcement does not exist.
+func (r *Repository) DeleteAnnouncement(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM announcement_receipts WHERE announcement_id = ?", id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM announcements WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+// announcementRunningOn is true for announcements running at some time of the day bound
+// by announcementDay after it
+const announcementRunningOn = "(substr(starting_date, 1, 10) <= ? AND (ending_date IS NULL OR ending_date = '' OR substr(ending_date, 1, 10) >= ?)" +
+	" AND (repeat_days = 0 OR repeat_days & ? != 0))"
+
+// announcementRunningAt is true for announcements running at the time bound by
+// announcementMoment after it. One-offs run from starts_at on their starting date to
+// ends_at on their ending date, recurring ones between those times on each of their days.
+const announcementRunningAt = "(CASE WHEN repeat_days = 0" +
+	" THEN substr(starting_date, 1, 10) || ' ' || COALESCE(starts_at, '00:00') <= ?" +
+	" AND (ending_date IS NULL OR ending_date = '' OR substr(ending_date, 1, 10) || ' ' || COALESCE(ends_at, '24:00') > ?)" +
+	" ELSE " + announcementRunningOn + " AND COALESCE(starts_at, '00:00') <= ? AND COALESCE(ends_at, '24:00') > ? END)"
+
+// announcementDay returns the arguments of announcementRunningOn for a YYYY-MM-DD date
+func announcementDay(date string) []interface{} {
+	day, _ := time.Parse("2006-01-02", date)
+	return []interface{}{date, date, 1 << day.Weekday()}
+}
+
+// announcementMoment returns the arguments of announcementRunningAt for a time, which is
+// compared in Athens time as "YYYY-MM-DD HH:MM" text
+func announcementMoment(t time.Time) []interface{} {
+	t = t.In(campustime.Location)
+	date, clock := t.Format("2006-01-02"), t.Format("15:04")
+	args := []interface{}{date + " " + clock, date + " " + clock}
+	return append(append(args, announcementDay(date)...), clock, clock)
+}
+
+// RefreshCurrentAnnouncements sets is_current on the announcements running at now and
+// clears it on the others. It returns how many announcements changed.
+func (r *Repository) RefreshCurrentAnnouncements(ctx context.Context, now time.Time) (int64, error) {
+	moment := announcementMoment(now)
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE announcements SET is_current = `+announcementRunningAt+`
+		WHERE is_current IS NOT `+announcementRunningAt,
+		append(moment, moment...)...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// MarkAnnouncements records that announcements were delivered to or seen by a user's device.
+// Timestamps are only set the first time so they reflect the original delivery and view.
+func (r *Repository) MarkAnnouncements(ctx context.Context, userID int64, deviceID string, ids []int, seen bool) error {
+	now := time.Now().UTC()
+	var seenAt interface{}
+	if seen {
+		seenAt = now
+	}
+
+	return r.WithTx(ctx, func(tx *sql.Tx) error {
+		for _, id := range ids {
+			// Unknown announcement IDs are skipped rather than failing the whole batch
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO announcement_receipts (announcement_id, user_id, device_id, delivered_at, seen_at)
+				SELECT id, ?, ?, ?, ? FROM announcements WHERE id = ?
+				ON CONFLICT (announcement_id, user_id, device_id) DO UPDATE SET
+					delivered_at = COALESCE(delivered_at, excluded.delivered_at),
+					seen_at = COALESCE(seen_at, excluded.seen_at)`,
+				userID, deviceID, now, seenAt, id,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetUnseenAnnouncements returns the announcements running at now that a user's device has not seen yet
+func (r *Repository) GetUnseenAnnouncements(ctx context.Context, userID int64, deviceID string, now time.Time) ([]Announcement, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+announcementColumns+`
+		FROM announcements
+		WHERE `+announcementRunningAt+`
+		  AND NOT EXISTS (
+			SELECT 1 FROM announcement_receipts ar
+			WHERE ar.announcement_id = announcements.id AND ar.user_id = ? AND ar.device_id = ? AND ar.seen_at IS NOT NULL
+		  )
+		ORDER BY starting_date DESC, id DESC`,
+		append(announcementMoment(now), userID, deviceID)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	announcements := []Announcement{}
+	for rows.Next() {
+		a, err := scanAnnouncement(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, a)
+	}
+	return announcements, rows.Err()
+}
+
+// CurrentAnnouncements returns the announcements running at now, newest first
+func (r *Repository) CurrentAnnouncements(ctx context.Context, now time.Time) ([]Announcement, error) {
+	rows, err := r.read.QueryContext(ctx, `
+		SELECT `+announcementColumns+`
+		FROM announcements
+		WHERE `+announcementRunningAt+`
+		ORDER BY starting_date DESC, id DESC`,
+		announcementMoment(now)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	announcements := []Announcement{}
+	for rows.Next() {
+		a, err := scanAnnouncement(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		// is_current catches up within AnnouncementRefreshInterval; these are running now
+		a.IsCurrent = true
+		announcements = append(announcements, a)
+	}
+	return announcements, rows.Err()
+}
+
+// GetAnnouncement returns an announcement by ID, or nil when it does not exist
+func (r *Repository) GetAnnouncement(ctx context.Context, id int64) (*Announcement, error) {
+	a, err := scanAnnouncement(r.db.QueryRowContext(ctx, "SELECT "+announcementColumns+" FROM announcements WHERE id = ?", id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListAnnouncements returns a page of announcements matching the filter, newest first. The
+// page holds one extra announcement when another page follows (see pagination.Next).
+func (r *Repository) ListAnnouncements(ctx context.Context, filter AnnouncementFilter, page pagination.Params) ([]Announcement, error) {
+	where, args := announcementFilter(filter)
+	after, afterArgs := page.Where("id")
+	args = append(append(args, afterArgs...), page.FetchLimit())
+
+	rows, err := r.read.QueryContext(ctx, `
+		SELECT `+announcementColumns+`
+		FROM announcements
+		WHERE `+where+` AND `+after+`
+		ORDER BY id DESC
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	announcements := []Announcement{}
+	for rows.Next() {
+		a, err := scanAnnouncement(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, a)
+	}
+	return announcements, rows.Err()
+}
+
+// announcementColumns are the columns scanAnnouncement reads, in order
+const announcementColumns = `id, COALESCE(type, ''), content, COALESCE(content_en, ''), starting_date, COALESCE(ending_date, ''), is_current,
+	COALESCE(starts_at, ''), COALESCE(ends_at, ''), repeat_days`
+
+func scanAnnouncement(scan func(dest ...interface{}) error) (Announcement, error) {
+	var a Announcement
+	var repeatDays int
+	err := scan(&a.ID, &a.Type, &a.Content, &a.ContentEn, &a.StartingDate, &a.EndingDate, &a.IsCurrent,
+		&a.StartsAt, &a.EndsAt, &repeatDays)
+	a.StartingDate, a.EndingDate = trimDate(a.StartingDate), trimDate(a.EndingDate)
+	a.RepeatOn = repeatOn(repeatDays)
+	return a, err
+}
+
+// CountAnnouncements returns the number of announcements matching the filter
+func (r *Repository) CountAnnouncements(ctx context.Context, filter AnnouncementFilter) (int, error) {
+	where, args := announcementFilter(filter)
+	var count int
+	err := r.read.QueryRowContext(ctx, "SELECT COUNT(*) FROM announcements WHERE "+where, args...).Scan(&count)
+	return count, err
+}
+
+func announcementFilter(filter AnnouncementFilter) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, filter.Type)
+	}
+	if filter.ActiveOn != "" {
+		conditions = append(conditions, announcementRunningOn)
+		args = append(args, announcementDay(filter.ActiveOn)...)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// GetDateSchedule returns the menu of a date, or that of the closure covering it; ctx bounds
+// its queries. Menus are cached until the next write, and the one returned must not be
+// modified.
+func (r *Repository) GetDateSchedule(ctx context.Context, date string) (*DateSchedule, error) {
+	menu, ok, generation := r.menus.get(date)
+	if !ok {
+		var err error
+		menu, err = r.resolveDateSchedule(ctx, date)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Dates no version covers are asked for too, e.g. during the summer break
+			r.menus.put(date, nil, generation)
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.menus.put(date, menu, generation)
+	}
+	if menu == nil {
+		return nil, sql.ErrNoRows
+	}
+	return menu, nil
+}
+
+// resolveDateSchedule computes the menu of a date from the closures and the rotation
+func (r *Repository) resolveDateSchedule(ctx context.Context, date string) (*DateSchedule, error) {
+	var result DateSchedule
+
+	// Avoid nil slices in JSON response
+	result.Lunch = []Food{}
+	result.Dinner = []Food{}
+
+	// Closures replace the rotation, with or without a menu of their own
+	closed, err := r.closureMenu(ctx, date, &result)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		if err := r.rotationMenu(ctx, date, &result); err != nil {
+			return nil, err
+		}
+	}
+
+	// A closure without a replacement menu serves nothing, at any hour
+	if !closed || len(result.Lunch)+len(result.Dinner) > 0 {
+		if result.Hours, err = r.mealHours(ctx, date); err != nil {
+			return nil, err
+		}
+	}
+
+	foods := append(foodPointers(result.Lunch), foodPointers(result.Dinner)...)
+	if err := r.attachFoodDetails(ctx, r.read, foods); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// rotationMenu puts the menu the rotating 4-week schedule serves on date in result. It
+// fails with sql.ErrNoRows when no version covers date.
+func (r *Repository) rotationMenu(ctx context.Context, date string, result *DateSchedule) error {
+	var startingDateStr string
+	var versionID int
+	query := `SELECT id, starting_date FROM schedule_versions 
+              WHERE ? >= starting_date AND (? <= ending_date OR ending_date IS NULL OR ending_date = '') 
+              LIMIT 1`
+
+	err := r.read.QueryRowContext(ctx, query, date, date).Scan(&versionID, &startingDateStr)
+	if err != nil {
+		return err
+	}
+	// Trim time part if exists
+	if len(startingDateStr) > 10 {
+		startingDateStr = startingDateStr[:10]
+	}
+
+	start, err := time.Parse("2006-01-02", startingDateStr)
+	if err != nil {
+		return err
+	}
+	target, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+
+	daysDiff := int(target.Sub(start).Hours() / 24)
+	if daysDiff < 0 {
+		return fmt.Errorf("We do not have a schedule for the requested date")
+	}
+
+	weekNum := ((daysDiff / 7) % 4) + 1
+	dayNum := (daysDiff % 7) + 1
+
+	rows, err := r.read.QueryContext(ctx, `
+        SELECT `+foodColumns+`, s.meal_type 
+        FROM foods f
+        JOIN schedule_dishes sd ON f.id = sd.food_id
+        JOIN schedule s ON s.id = sd.schedule_id
+        WHERE s.version_id = ? AND s.week_number = ? AND s.day_number = ?`, versionID, weekNum, dayNum)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row foodRow
+		var mealType string
+		rows.Scan(append(row.dest(), &mealType)...)
+
+		if mealType == "lunch" {
+			result.Lunch = append(result.Lunch, row.result())
+		} else {
+			result.Dinner = append(result.Dinner, row.result())
+		}
+	}
+	return rows.Err()
+}
+
+// GetRangeSchedule returns the menu of every date from start to end inclusive. Dates no
+// schedule version covers are included with empty meals.
+func (r *Repository) GetRangeSchedule(ctx context.Context, start, end time.Time) ([]DaySchedule, error) {
+	var days []DaySchedule
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		day := DaySchedule{Date: date.Format("2006-01-02")}
+		schedule, err := r.GetDateSchedule(ctx, day.Date)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			day.DateSchedule = DateSchedule{Lunch: []Food{}, Dinner: []Food{}}
+		case err != nil:
+			return nil, fmt.Errorf("%s: %w", day.Date, err)
+		default:
+			day.DateSchedule = *schedule
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// GetCurrentVersion returns the current schedule version, the newest one when several are
+// marked current, or nil when none is
+func (r *Repository) GetCurrentVersion(ctx context.Context) (*ScheduleVersion, error) {
+	var v ScheduleVersion
+	var updatedAt sql.NullTime
+	err := r.read.QueryRowContext(ctx, `
+		SELECT id, starting_date, COALESCE(ending_date, ''), is_current, updated_at
+		FROM schedule_versions WHERE is_current = 1
+		ORDER BY starting_date DESC, id DESC LIMIT 1`).Scan(&v.ID, &v.StartingDate, &v.EndingDate, &v.IsCurrent, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Trim time part if exists
+	if len(v.StartingDate) > 10 {
+		v.StartingDate = v.StartingDate[:10]
+	}
+	if len(v.EndingDate) > 10 {
+		v.EndingDate = v.EndingDate[:10]
+	}
+	if updatedAt.Valid {
+		v.UpdatedAt = &updatedAt.Time
+	}
+	return &v, nil
+}
+
+// GetCurrentSchedule returns the whole rotating menu of the current version, keyed by week
+// and day. Every week and day is present, with empty meals where nothing is served.
+func (r *Repository) GetCurrentSchedule(ctx context.Context) (SemesterSchedule, error) {
+	version, err := r.GetCurrentVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil {
+		return nil, sql.ErrNoRows
+	}
+	versionID := version.ID
+
+	result := make(SemesterSchedule, 4)
+	for week := 1; week <= 4; week++ {
+		result[week] = make(map[int]DateSchedule, 7)
+		for day := 1; day <= 7; day++ {
+			result[week][day] = DateSchedule{Lunch: []Food{}, Dinner: []Food{}}
+		}
+	}
+
+	rows, err := r.read.QueryContext(ctx, `
+		SELECT s.week_number, s.day_number, s.meal_type, `+foodColumns+`
+		FROM schedule s
+		JOIN schedule_dishes sd ON sd.schedule_id = s.id
+		JOIN foods f ON f.id = sd.food_id
+		WHERE s.version_id = ?
+		ORDER BY s.week_number, s.day_number, s.id, sd.rowid`, versionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var week, day int
+		var mealType string
+		var row foodRow
+		if err := rows.Scan(append([]interface{}{&week, &day, &mealType}, row.dest()...)...); err != nil {
+			return nil, err
+		}
+		f := row.result()
+		meals, ok := result[week][day]
+		if !ok {
+			// Out of range slots cannot be reached by date, so they are not served either
+			continue
+		}
+		if mealType == "lunch" {
+			meals.Lunch = append(meals.Lunch, f)
+		} else {
+			meals.Dinner = append(meals.Dinner, f)
+		}
+		result[week][day] = meals
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var foods []*Food
+	for _, days := range result {
+		for _, meals := range days {
+			// The slices share their arrays with the map's copies
+			foods = append(foods, foodPointers(meals.Lunch)...)
+			foods = append(foods, foodPointers(meals.Dinner)...)
+		}
+	}
+	return result, r.attachFoodDetails(ctx, r.read, foods)
+}
+
+// ErrClosureOverlap is returned when a closure would cover dates another closure covers
+var ErrClosureOverlap = errors.New("another closure already covers some of these dates")
+
+// closureColumns are scanned with scanClosure
+const closureColumns = "id, starting_date, ending_date, reason, COALESCE(reason_en, '')"
+
+func scanClosure(scan func(dest ...interface{}) error) (Closure, error) {
+	var c Closure
+	err := scan(&c.ID, &c.StartingDate, &c.EndingDate, &c.Reason, &c.ReasonEn)
+	// Trim time part if exists
+	if len(c.StartingDate) > 10 {
+		c.StartingDate = c.StartingDate[:10]
+	}
+	if len(c.EndingDate) > 10 {
+		c.EndingDate = c.EndingDate[:10]
+	}
+	return c, err
+}
+
+// attachClosureDishes loads the replacement menus of closures from db
+func attachClosureDishes(ctx context.Context, db *sql.DB, closures []*Closure) error {
+	if len(closures) == 0 {
+		return nil
+	}
+	byID := make(map[int]*Closure, len(closures))
+	args := make([]interface{}, 0, len(closures))
+	for _, c := range closures {
+		byID[c.ID] = c
+		args = append(args, c.ID)
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT closure_id, meal_type, food_id FROM closure_dishes
+		WHERE closure_id IN (`+strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")+`)
+		ORDER BY rowid`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var closureID, foodID int
+		var mealType string
+		if err := rows.Scan(&closureID, &mealType, &foodID); err != nil {
+			return err
+		}
+		c := byID[closureID]
+		if mealType == "lunch" {
+			c.LunchDishIDs = append(c.LunchDishIDs, foodID)
+		} else {
+			c.DinnerDishIDs = append(c.DinnerDishIDs, foodID)
+		}
+	}
+	return rows.Err()
+}
+
+// writeClosure checks that c overlaps no other closure and replaces its replacement menu
+func writeClosure(ctx context.Context, tx *sql.Tx, id int64, c Closure) error {
+	var other int64
+	err := tx.QueryRowContext(ctx, `
+		SELECT id FROM closures WHERE starting_date <= ? AND ending_date >= ? AND id != ? LIMIT 1`,
+		c.EndingDate, c.StartingDate, id).Scan(&other)
+	if err == nil {
+		return ErrClosureOverlap
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM closure_dishes WHERE closure_id = ?", id); err != nil {
+		return err
+	}
+	for mealType, dishIDs := range map[string][]int{"lunch": c.LunchDishIDs, "dinner": c.DinnerDishIDs} {
+		for _, foodID := range dishIDs {
+			_, err := tx.ExecContext(ctx, `
+				INSERT OR IGNORE INTO closure_dishes (closure_id, meal_type, food_id)
+				SELECT ?, ?, id FROM foods WHERE id = ?`, id, mealType, foodID, foodID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return touchCurrentVersion(ctx, tx)
+}
+
+// touchCurrentVersion moves the current version's updated_at forward, so clients polling
+// /schedule/version refetch menus that changed without the version changing
+func touchCurrentVersion(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "UPDATE schedule_versions SET updated_at = ? WHERE is_current = 1", time.Now().UTC())
 	return err
 }
 
-// CreateVersion adds a new schedule version to the database
-// TODO: Add validation for date formats
-func (r *Repository) CreateVersion(start, end string, active bool) (int64, error) {
-	res, err := r.db.Exec("INSERT INTO schedule_versions (starting_date, ending_date, is_current) VALUES (?, ?, ?)", start, end, active)
-	if err != nil {
-		return 0, err
+// CreateClosure adds a closure, failing with ErrClosureOverlap when another one covers some
+// of its dates. An empty EndingDate closes StartingDate only.
+func (r *Repository) CreateClosure(ctx context.Context, c Closure) (int64, error) {
+	if c.EndingDate == "" {
+		c.EndingDate = c.StartingDate
+	}
+	var id int64
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO closures (starting_date, ending_date, reason, reason_en) VALUES (?, ?, ?, ?)
+		`, c.StartingDate, c.EndingDate, c.Reason, databases.NullString(c.ReasonEn))
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		return writeClosure(ctx, tx, id, c)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetClosure returns a closure with its replacement menu, or nil when it does not exist
+func (r *Repository) GetClosure(ctx context.Context, id int64) (*Closure, error) {
+	c, err := scanClosure(r.db.QueryRowContext(ctx, "SELECT "+closureColumns+" FROM closures WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := attachClosureDishes(ctx, r.db, []*Closure{&c}); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListClosures returns a page of closures ending on or after from (every closure when
+// from is empty), newest first. The page holds one extra closure when another page
+// follows (see pagination.Next).
+func (r *Repository) ListClosures(ctx context.Context, from string, page pagination.Params) ([]Closure, error) {
+	after, args := page.Where("id")
+	rows, err := r.read.QueryContext(ctx, `
+		SELECT `+closureColumns+` FROM closures
+		WHERE (? = '' OR ending_date >= ?) AND `+after+`
+		ORDER BY id DESC
+		LIMIT ?
+	`, append(append([]interface{}{from, from}, args...), page.FetchLimit())...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	closures := []Closure{}
+	for rows.Next() {
+		c, err := scanClosure(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		closures = append(closures, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	pointers := make([]*Closure, len(closures))
+	for i := range closures {
+		pointers[i] = &closures[i]
+	}
+	return closures, attachClosureDishes(ctx, r.read, pointers)
+}
+
+// CountClosures returns the number of closures ending on or after from
+func (r *Repository) CountClosures(ctx context.Context, from string) (int, error) {
+	var count int
+	err := r.read.QueryRowContext(ctx, "SELECT COUNT(*) FROM closures WHERE ? = '' OR ending_date >= ?", from, from).Scan(&count)
+	return count, err
+}
+
+// ReplaceClosure overwrites a closure and its replacement menu. It returns false when the
+// closure does not exist.
+func (r *Repository) ReplaceClosure(ctx context.Context, id int64, c Closure) (bool, error) {
+	if c.EndingDate == "" {
+		c.EndingDate = c.StartingDate
+	}
+	var replaced bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE closures SET starting_date = ?, ending_date = ?, reason = ?, reason_en = ? WHERE id = ?
+		`, c.StartingDate, c.EndingDate, c.Reason, databases.NullString(c.ReasonEn), id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		replaced = true
+		return writeClosure(ctx, tx, id, c)
+	})
+	return replaced, err
+}
+
+// DeleteClosure deletes a closure, so the rotation applies to its dates again. It returns
+// false when the closure does not exist.
+func (r *Repository) DeleteClosure(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM closure_dishes WHERE closure_id = ?", id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM closures WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if deleted = n > 0; !deleted || err != nil {
+			return err
+		}
+		return touchCurrentVersion(ctx, tx)
+	})
+	return deleted, err
+}
+
+// closureMenu returns the closure covering date with its replacement menu in result, or
+// false when none does
+func (r *Repository) closureMenu(ctx context.Context, date string, result *DateSchedule) (bool, error) {
+	c, err := scanClosure(r.read.QueryRowContext(ctx, `
+		SELECT `+closureColumns+` FROM closures
+		WHERE starting_date <= ? AND ending_date >= ?
+		ORDER BY id DESC LIMIT 1`, date, date).Scan)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	result.Closure = &c
+
+	rows, err := r.read.QueryContext(ctx, `
+		SELECT `+foodColumns+`, cd.meal_type
+		FROM closure_dishes cd
+		JOIN foods f ON f.id = cd.food_id
+		WHERE cd.closure_id = ?
+		ORDER BY cd.rowid`, c.ID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row foodRow
+		var mealType string
+		if err := rows.Scan(append(row.dest(), &mealType)...); err != nil {
+			return false, err
+		}
+		if mealType == "lunch" {
+			result.Lunch = append(result.Lunch, row.result())
+		} else {
+			result.Dinner = append(result.Dinner, row.result())
+		}
+	}
+	return true, rows.Err()
+}
+
+// ListRestaurants returns every restaurant with its serving hours. A university has a
+// handful of them, so they are not paginated.
+func (r *Repository) ListRestaurants(ctx context.Context) ([]Restaurant, error) {
+	return listRestaurants(ctx, r.read, "")
+}
+
+// GetRestaurant returns a restaurant with its serving hours, or nil when it does not exist
+func (r *Repository) GetRestaurant(ctx context.Context, id int64) (*Restaurant, error) {
+	restaurants, err := listRestaurants(ctx, r.db, "WHERE id = ?", id)
+	if err != nil || len(restaurants) == 0 {
+		return nil, err
 	}
-	return res.LastInsertId()
+	return &restaurants[0], nil
 }
 
-// CreateScheduleItem adds a new schedule item to the database with associated dishes. What day, week and meal type is this dish []int for.
-func (r *Repository) CreateScheduleItem(versionID int, week, day int, mealType string, dishIDs []int) error {
-	tx, err := r.db.Begin()
+func listRestaurants(ctx context.Context, db *sql.DB, where string, args ...interface{}) ([]Restaurant, error) {
+	rows, err := db.QueryContext(ctx, "SELECT id, name, COALESCE(name_en, '') FROM restaurants "+where+" ORDER BY id", args...)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer rows.Close()
+
+	restaurants := []Restaurant{}
+	byID := make(map[int64]int)
+	for rows.Next() {
+		restaurant := Restaurant{Hours: []ServiceHours{}}
+		if err := rows.Scan(&restaurant.ID, &restaurant.Name, &restaurant.NameEn); err != nil {
+			return nil, err
+		}
+		byID[restaurant.ID] = len(restaurants)
+		restaurants = append(restaurants, restaurant)
+	}
+	if err := rows.Err(); err != nil || len(restaurants) == 0 {
+		return restaurants, err
 	}
-	// Defer a rollback in case anything fails.
-	defer func() {
-		_ = tx.Rollback()
-	}()
 
-	res, err := tx.Exec(`
-		INSERT INTO schedule (version_id, week_number, day_number, meal_type) 
-		VALUES (?, ?, ?, ?)`,
-		versionID, week, day, mealType,
-	)
+	hours, err := db.QueryContext(ctx, `
+		SELECT restaurant_id, meal_type, days, opens_at, closes_at FROM service_hours
+		ORDER BY days, opens_at`)
 	if err != nil {
+		return nil, err
+	}
+	defer hours.Close()
+	for hours.Next() {
+		var restaurantID int64
+		var h ServiceHours
+		if err := hours.Scan(&restaurantID, &h.MealType, &h.Days, &h.Opens, &h.Closes); err != nil {
+			return nil, err
+		}
+		if i, ok := byID[restaurantID]; ok {
+			restaurants[i].Hours = append(restaurants[i].Hours, h)
+		}
+	}
+	return restaurants, hours.Err()
+}
+
+// writeServiceHours replaces the serving hours of a restaurant
+func writeServiceHours(ctx context.Context, tx *sql.Tx, id int64, hours []ServiceHours) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_hours WHERE restaurant_id = ?", id); err != nil {
 		return err
 	}
+	for _, h := range hours {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO service_hours (restaurant_id, meal_type, days, opens_at, closes_at) VALUES (?, ?, ?, ?, ?)
+		`, id, h.MealType, h.Days, h.Opens, h.Closes)
+		if err != nil {
+			return err
+		}
+	}
+	return touchCurrentVersion(ctx, tx)
+}
 
-	scheduleID, err := res.LastInsertId()
+// CreateRestaurant adds a restaurant with its serving hours
+func (r *Repository) CreateRestaurant(ctx context.Context, restaurant Restaurant) (int64, error) {
+	var id int64
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "INSERT INTO restaurants (name, name_en) VALUES (?, ?)", restaurant.Name, databases.NullString(restaurant.NameEn))
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		return writeServiceHours(ctx, tx, id, restaurant.Hours)
+	})
 	if err != nil {
-		return err
+		return 0, err
 	}
+	return id, nil
+}
+
+// ReplaceRestaurant overwrites a restaurant and its serving hours. It returns false when
+// the restaurant does not exist.
+func (r *Repository) ReplaceRestaurant(ctx context.Context, id int64, restaurant Restaurant) (bool, error) {
+	var replaced bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE restaurants SET name = ?, name_en = ? WHERE id = ?", restaurant.Name, databases.NullString(restaurant.NameEn), id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		replaced = true
+		return writeServiceHours(ctx, tx, id, restaurant.Hours)
+	})
+	return replaced, err
+}
+
+// DeleteRestaurant deletes a restaurant and its serving hours. It returns false when the
+// restaurant does not exist.
+func (r *Repository) DeleteRestaurant(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM service_hours WHERE restaurant_id = ?", id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM restaurants WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if deleted = n > 0; !deleted || err != nil {
+			return err
+		}
+		return touchCurrentVersion(ctx, tx)
+	})
+	return deleted, err
+}
 
-	stmt, err := tx.Prepare("INSERT INTO schedule_dishes (schedule_id, food_id) VALUES (?, ?)")
+// mealHours returns when the restaurants serve the meals on date, in serving order
+func (r *Repository) mealHours(ctx context.Context, date string) ([]MealHours, error) {
+	day, err := time.Parse("2006-01-02", date)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer stmt.Close()
+	rows, err := r.read.QueryContext(ctx, `
+		SELECT r.id, r.name, COALESCE(r.name_en, ''), h.meal_type, h.opens_at, h.closes_at
+		FROM service_hours h
+		JOIN restaurants r ON r.id = h.restaurant_id
+		WHERE h.days = ?
+		ORDER BY h.opens_at, r.id`, serviceDays(day))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	for _, foodID := range dishIDs {
-		if _, err := stmt.Exec(scheduleID, foodID); err != nil {
+	var hours []MealHours
+	for rows.Next() {
+		var h MealHours
+		if err := rows.Scan(&h.RestaurantID, &h.Restaurant, &h.RestaurantEn, &h.MealType, &h.Opens, &h.Closes); err != nil {
+			return nil, err
+		}
+		hours = append(hours, h)
+	}
+	return hours, rows.Err()
+}
+
+// func (r *Repository) GetAnnouncements(annType string) {
+
+// }
+
+// ErrReviewHidden is returned when a user edits a review a moderator hid
+var ErrReviewHidden = errors.New("the review was hidden by a moderator")
+
+// ReviewFilter narrows a review listing; zero values match every review
+type ReviewFilter struct {
+	FoodID   int64
+	Hidden   *bool
+	WithText bool // only reviews with text, not bare ratings
+}
+
+func (f ReviewFilter) where() (string, []interface{}) {
+	var hidden interface{}
+	if f.Hidden != nil {
+		hidden = *f.Hidden
+	}
+	return "(? = 0 OR food_id = ?) AND (? IS NULL OR hidden = ?) AND (? = 0 OR review != '')",
+		[]interface{}{f.FoodID, f.FoodID, hidden, hidden, f.WithText}
+}
+
+const reviewColumns = "id, food_id, user_id, rating, review, hidden, created_at, updated_at"
+
+func scanReview(scan func(dest ...interface{}) error) (Review, error) {
+	var rv Review
+	err := scan(&rv.ID, &rv.FoodID, &rv.UserID, &rv.Rating, &rv.Review, &rv.Hidden, &rv.CreatedAt, &rv.UpdatedAt)
+	return rv, err
+}
+
+// RateFood sets a user's rating and review of a food, replacing any earlier one. It returns
+// nil when the food does not exist, and ErrReviewHidden when the user's review was hidden,
+// so a moderated review cannot be brought back by editing it.
+func (r *Repository) RateFood(ctx context.Context, foodID, userID int64, rating int, review string) (*Review, error) {
+	var rv *Review
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM foods WHERE id = ?)", foodID).Scan(&exists); err != nil || !exists {
+			return err
+		}
+		var hidden bool
+		err := tx.QueryRowContext(ctx, "SELECT hidden FROM food_ratings WHERE food_id = ? AND user_id = ?", foodID, userID).Scan(&hidden)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if hidden {
+			return ErrReviewHidden
+		}
+
+		now := time.Now().UTC()
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO food_ratings (food_id, user_id, rating, review, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT (food_id, user_id) DO UPDATE SET rating = excluded.rating, review = excluded.review, updated_at = excluded.updated_at
+		`, foodID, userID, rating, review, now, now)
+		if err != nil {
 			return err
 		}
+		saved, err := scanReview(tx.QueryRowContext(ctx, "SELECT "+reviewColumns+" FROM food_ratings WHERE food_id = ? AND user_id = ?", foodID, userID).Scan)
+		rv = &saved
+		return err
+	})
+	return rv, err
+}
+
+// GetUserReview returns a user's rating of a food, or nil when they have not rated it
+func (r *Repository) GetUserReview(ctx context.Context, foodID, userID int64) (*Review, error) {
+	rv, err := scanReview(r.db.QueryRowContext(ctx, "SELECT "+reviewColumns+" FROM food_ratings WHERE food_id = ? AND user_id = ?", foodID, userID).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// DeleteUserReview deletes a user's rating of a food. It returns false when they had none.
+func (r *Repository) DeleteUserReview(ctx context.Context, foodID, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM food_ratings WHERE food_id = ? AND user_id = ? AND hidden = 0", foodID, userID)
+	if err != nil {
+		return false, err
+	}
+	r.menus.invalidate() // menus show the average ratings
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListReviews returns a page of the reviews matching filter, newest first
+func (r *Repository) ListReviews(ctx context.Context, filter ReviewFilter, page pagination.Params) ([]Review, error) {
+	where, args := filter.where()
+	after, afterArgs := page.Where("id")
+	rows, err := r.read.QueryContext(ctx, `
+		SELECT `+reviewColumns+` FROM food_ratings
+		WHERE `+where+` AND `+after+`
+		ORDER BY id DESC
+		LIMIT ?
+	`, append(append(args, afterArgs...), page.FetchLimit())...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []Review{}
+	for rows.Next() {
+		rv, err := scanReview(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rv)
 	}
+	return reviews, rows.Err()
+}
 
-	return tx.Commit()
+// CountReviews returns the number of reviews matching filter
+func (r *Repository) CountReviews(ctx context.Context, filter ReviewFilter) (int, error) {
+	where, args := filter.where()
+	var count int
+	err := r.read.QueryRowContext(ctx, "SELECT COUNT(*) FROM food_ratings WHERE "+where, args...).Scan(&count)
+	return count, err
 }
 
-// CreateAnnouncement adds a new announcement to the database
-func (r *Repository) CreateAnnouncement(annType, content, start, end string, isCurrent bool) (int64, error) {
-	res, err := r.db.Exec("INSERT INTO announcements (type, content, starting_date, ending_date, is_current) VALUES (?, ?, ?, ?, ?)", annType, content, start, end, isCurrent)
+// ModerateReview hides or shows a review. It returns false when the review does not exist.
+func (r *Repository) ModerateReview(ctx context.Context, id int64, hidden bool) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "UPDATE food_ratings SET hidden = ? WHERE id = ?", hidden, id)
 	if err != nil {
-		return 0, err
+		return false, err
 	}
-	return res.LastInsertId()
+	r.menus.invalidate() // menus show the average ratings
+	n, err := res.RowsAffected()
+	return n > 0, err
 }
 
-func (r *Repository) GetDateSchedule(date string) (*DateSchedule, error) {
-	var result DateSchedule
+// DeleteReview deletes a review, so its user can rate the food again. It returns false
+// when the review does not exist.
+func (r *Repository) DeleteReview(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM food_ratings WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	r.menus.invalidate() // menus show the average ratings
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
 
-	// Avoid nil slices in JSON response
-	result.Lunch = []Food{}
-	result.Dinner = []Food{}
+// Favorites only concern their user, so unlike menu writes they do not go through WithTx
+// and leave the menu cache alone
 
-	var startingDateStr string
-	var versionID int
-	query := `SELECT id, starting_date FROM schedule_versions 
-              WHERE ? >= starting_date AND (? <= ending_date OR ending_date IS NULL OR ending_date = '') 
-              LIMIT 1`
+// AddFavorite marks a food as a favorite of a user; marking it again changes nothing. It
+// returns false when the food does not exist.
+func (r *Repository) AddFavorite(ctx context.Context, userID, foodID int64) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM foods WHERE id = ?)", foodID).Scan(&exists); err != nil || !exists {
+		return false, err
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO food_favorites (user_id, food_id, created_at) VALUES (?, ?, ?)
+	`, userID, foodID, time.Now().UTC())
+	return err == nil, err
+}
+
+// RemoveFavorite unmarks a favorite food. It returns false when it was not one.
+func (r *Repository) RemoveFavorite(ctx context.Context, userID, foodID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM food_favorites WHERE user_id = ? AND food_id = ?", userID, foodID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
 
-	err := r.db.QueryRow(query, date, date).Scan(&versionID, &startingDateStr)
+// ListFavorites returns a user's favorite foods in the order they were marked
+func (r *Repository) ListFavorites(ctx context.Context, userID int64) ([]Food, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+foodColumns+` FROM food_favorites ff
+		JOIN foods f ON f.id = ff.food_id
+		WHERE ff.user_id = ?
+		ORDER BY ff.created_at, f.id`, userID)
 	if err != nil {
 		return nil, err
 	}
-	// Trim time part if exists
-	if len(startingDateStr) > 10 {
-		startingDateStr = startingDateStr[:10]
+	defer rows.Close()
+
+	foods := []Food{}
+	for rows.Next() {
+		var row foodRow
+		if err := rows.Scan(row.dest()...); err != nil {
+			return nil, err
+		}
+		foods = append(foods, row.result())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return foods, r.attachFoodDetails(ctx, r.db, foodPointers(foods))
+}
+
+// NextServings returns the first date from from on, within days, that each of foods is
+// served, lunch before dinner. Foods not served in that time are left out.
+//
+// The dates a food can be served on are found from the rotation slots and closure menus
+// listing it, and each is checked against the menu actually served then, since closures
+// and overlapping versions override the rotation.
+func (r *Repository) NextServings(ctx context.Context, foodIDs []int, from time.Time, days int) (map[int]Serving, error) {
+	servings := make(map[int]Serving)
+	if len(foodIDs) == 0 {
+		return servings, nil
+	}
+	first, last := from.Format("2006-01-02"), from.AddDate(0, 0, days-1).Format("2006-01-02")
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(foodIDs)), ", ")
+	args := make([]interface{}, 0, len(foodIDs)+1)
+	for _, id := range foodIDs {
+		args = append(args, id)
 	}
 
-	start, err := time.Parse("2006-01-02", startingDateStr)
+	candidates := make(map[string]bool)
+	addDates := func(start, end string, step int) error {
+		startDate, err := time.Parse("2006-01-02", trimDate(start))
+		if err != nil {
+			return err
+		}
+		for date := startDate; ; date = date.AddDate(0, 0, step) {
+			day := date.Format("2006-01-02")
+			if day > last || (end != "" && day > trimDate(end)) {
+				return nil
+			}
+			if day >= first {
+				candidates[day] = true
+			}
+		}
+	}
+
+	// A rotation slot recurs every 28 days from its first date in the version
+	rows, err := r.read.QueryContext(ctx, `
+		SELECT DISTINCT v.starting_date, COALESCE(v.ending_date, ''), s.week_number, s.day_number
+		FROM schedule_dishes sd
+		JOIN schedule s ON s.id = sd.schedule_id
+		JOIN schedule_versions v ON v.id = s.version_id
+		WHERE sd.food_id IN (`+placeholders+`)
+		  AND (v.ending_date IS NULL OR v.ending_date = '' OR v.ending_date >= ?)`,
+		append(args, first)...)
 	if err != nil {
 		return nil, err
 	}
-	target, err := time.Parse("2006-01-02", date)
+	defer rows.Close()
+	type slot struct {
+		start, end string
+		offset     int
+	}
+	var slots []slot
+	for rows.Next() {
+		var s slot
+		var week, day int
+		if err := rows.Scan(&s.start, &s.end, &week, &day); err != nil {
+			return nil, err
+		}
+		s.offset = (week-1)*7 + day - 1
+		slots = append(slots, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, s := range slots {
+		start, err := time.Parse("2006-01-02", trimDate(s.start))
+		if err != nil {
+			return nil, err
+		}
+		if err := addDates(start.AddDate(0, 0, s.offset).Format("2006-01-02"), s.end, 28); err != nil {
+			return nil, err
+		}
+	}
+
+	// Replacement menus are served on every date of their closure
+	closures, err := r.read.QueryContext(ctx, `
+		SELECT DISTINCT c.starting_date, c.ending_date
+		FROM closure_dishes cd
+		JOIN closures c ON c.id = cd.closure_id
+		WHERE cd.food_id IN (`+placeholders+`) AND c.ending_date >= ?`,
+		append(args, first)...)
 	if err != nil {
 		return nil, err
 	}
+	defer closures.Close()
+	for closures.Next() {
+		var start, end string
+		if err := closures.Scan(&start, &end); err != nil {
+			return nil, err
+		}
+		if err := addDates(start, end, 1); err != nil {
+			return nil, err
+		}
+	}
+	if err := closures.Err(); err != nil {
+		return nil, err
+	}
 
-	daysDiff := int(target.Sub(start).Hours() / 24)
-	if daysDiff < 0 {
-		return nil, fmt.Errorf("We do not have a schedule for the requested date")
+	dates := make([]string, 0, len(candidates))
+	for date := range candidates {
+		dates = append(dates, date)
 	}
+	slices.Sort(dates)
+	for _, date := range dates {
+		menu, err := r.GetDateSchedule(ctx, date)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for mealType, foods := range map[string][]Food{"lunch": menu.Lunch, "dinner": menu.Dinner} {
+			for _, food := range foods {
+				if !slices.Contains(foodIDs, food.ID) {
+					continue
+				}
+				if s, found := servings[food.ID]; !found || (s.Date == date && mealType == "lunch") {
+					servings[food.ID] = Serving{Date: date, MealType: mealType}
+				}
+			}
+		}
+		if len(servings) == len(foodIDs) {
+			break
+		}
+	}
+	return servings, nil
+}
 
-	weekNum := ((daysDiff / 7) % 4) + 1
-	dayNum := (daysDiff % 7) + 1
+// channelColumns are scanned with scanChannel
+const channelColumns = `id, kind, target, COALESCE(bot_token, ''), language, post_at, template, active,
+	COALESCE(last_posted_on, ''), COALESCE(last_error, ''), created_at`
 
-	rows, err := r.db.Query(`
-        SELECT f.id, f.name, s.meal_type 
-        FROM foods f
-        JOIN schedule_dishes sd ON f.id = sd.food_id
-        JOIN schedule s ON s.id = sd.schedule_id
-        WHERE s.version_id = ? AND s.week_number = ? AND s.day_number = ?`, versionID, weekNum, dayNum)
+func scanChannel(scan func(dest ...interface{}) error) (MenuChannel, error) {
+	var ch MenuChannel
+	err := scan(&ch.ID, &ch.Kind, &ch.Target, &ch.BotToken, &ch.Language, &ch.PostAt, &ch.Template, &ch.Active,
+		&ch.LastPostedOn, &ch.LastError, &ch.CreatedAt)
+	ch.LastPostedOn = trimDate(ch.LastPostedOn)
+	return ch, err
+}
+
+// CreateChannel adds a channel the daily menu is posted to
+func (r *Repository) CreateChannel(ctx context.Context, ch MenuChannel) (int64, error) {
+	var id int64
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO menu_channels (kind, target, bot_token, language, post_at, template, active, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, ch.Kind, ch.Target, databases.NullString(ch.BotToken), ch.Language, ch.PostAt, ch.Template, ch.Active, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	return id, err
+}
+
+// GetChannel returns a channel, bot token included, or nil when it does not exist
+func (r *Repository) GetChannel(ctx context.Context, id int64) (*MenuChannel, error) {
+	ch, err := scanChannel(r.db.QueryRowContext(ctx, "SELECT "+channelColumns+" FROM menu_channels WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ch, nil
+}
+
+// ListChannels returns every channel, bot tokens included. There are only a few, so they
+// are not paginated.
+func (r *Repository) ListChannels(ctx context.Context) ([]MenuChannel, error) {
+	return r.queryChannels(ctx, "SELECT "+channelColumns+" FROM menu_channels ORDER BY id")
+}
+
+// DueChannels returns the active channels whose post time on date has come by now (HH:MM)
+// and that were not posted to on date yet
+func (r *Repository) DueChannels(ctx context.Context, date, now string) ([]MenuChannel, error) {
+	return r.queryChannels(ctx, `
+		SELECT `+channelColumns+` FROM menu_channels
+		WHERE active = 1 AND post_at <= ?
+		  AND (last_posted_on IS NULL OR last_posted_on < ?)
+		  AND (retry_at IS NULL OR retry_at <= ?)
+		ORDER BY id`, now, date, time.Now().UTC())
+}
+
+func (r *Repository) queryChannels(ctx context.Context, query string, args ...interface{}) ([]MenuChannel, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	channels := []MenuChannel{}
 	for rows.Next() {
-		var f Food
-		var mealType string
-		rows.Scan(&f.ID, &f.Name, &mealType)
-
-		if mealType == "lunch" {
-			result.Lunch = append(result.Lunch, f)
-		} else {
-			result.Dinner = append(result.Dinner, f)
+		ch, err := scanChannel(rows.Scan)
+		if err != nil {
+			return nil, err
 		}
+		channels = append(channels, ch)
 	}
+	return channels, rows.Err()
+}
 
-	return &result, nil
+// UpdateChannel changes the fields of a channel that are set. It returns false when the
+// channel does not exist.
+func (r *Repository) UpdateChannel(ctx context.Context, id int64, update MenuChannelUpdate) (bool, error) {
+	var sets []string
+	var args []interface{}
+	if update.Target != nil {
+		sets, args = append(sets, "target = ?"), append(args, *update.Target)
+	}
+	if update.BotToken != nil && *update.BotToken != "" {
+		sets, args = append(sets, "bot_token = ?"), append(args, *update.BotToken)
+	}
+	if update.Language != nil {
+		sets, args = append(sets, "language = ?"), append(args, *update.Language)
+	}
+	if update.PostAt != nil {
+		sets, args = append(sets, "post_at = ?"), append(args, *update.PostAt)
+	}
+	if update.Template != nil {
+		sets, args = append(sets, "template = ?"), append(args, *update.Template)
+	}
+	if update.Active != nil {
+		// Re-enabling a channel gives a failing one a fresh start
+		sets, args = append(sets, "active = ?", "retry_at = NULL"), append(args, *update.Active)
+	}
+	if len(sets) == 0 {
+		exists, err := r.GetChannel(ctx, id)
+		return exists != nil, err
+	}
+
+	var updated bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE menu_channels SET "+strings.Join(sets, ", ")+" WHERE id = ?", append(args, id)...)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		updated = n > 0
+		return err
+	})
+	return updated, err
 }
 
-// func (r *Repository) GetCurrentSchedule() {
-// 	var result []CurrentSchedule
-// 	var scheduleVersion ScheduleVersion
+// DeleteChannel deletes a channel. It returns false when the channel does not exist.
+func (r *Repository) DeleteChannel(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := r.WithTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "DELETE FROM menu_channels WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
 
-// 	err := r.db.QueryRow("SELECT id, starting_date, ending_date, is_current FROM schedule_versions WHERE is_current = 1 LIMIT 1").
-// 		Scan(&scheduleVersion.ID, &scheduleVersion.StartingDate, &scheduleVersion.EndingDate, &scheduleVersion.IsCurrent)
-// 	if err != nil {
-// 		return
-// 	}
+// ClaimChannel marks a channel as posted to on date, unless it already was, e.g. by
+// another instance. It returns false when the claim was lost.
+func (r *Repository) ClaimChannel(ctx context.Context, id int64, date string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE menu_channels SET last_posted_on = ?
+		WHERE id = ? AND (last_posted_on IS NULL OR last_posted_on < ?)
+	`, date, id, date)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
 
-// }
+// RecordChannelPost records how posting to a claimed channel went. A failed post
+// releases the claim back to previous, so it is retried after retryAfter.
+func (r *Repository) RecordChannelPost(ctx context.Context, id int64, previous string, postErr error, retryAfter time.Duration) error {
+	if postErr == nil {
+		_, err := r.db.ExecContext(ctx, "UPDATE menu_channels SET last_error = NULL, retry_at = NULL WHERE id = ?", id)
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE menu_channels SET last_posted_on = ?, last_error = ?, retry_at = ? WHERE id = ?
+	`, databases.NullString(previous), postErr.Error(), time.Now().UTC().Add(retryAfter), id)
+	return err
+}
 
-// func (r *Repository) GetAnnouncements(annType string) {
+// ListChanges returns the dishes added to and removed from menus by edits made after since,
+// per date from the first to the last date given. Each slot of a version appears on the
+// dates of the range it is served on; a dish added and removed again is left out.
+func (r *Repository) ListChanges(ctx context.Context, since time.Time, first, last time.Time) ([]MenuChange, error) {
+	rows, err := r.read.QueryContext(ctx, `
+		SELECT c.version_id, v.starting_date, COALESCE(v.ending_date, ''), c.week_number, c.day_number, c.meal_type, c.change, c.changed_at, `+foodColumns+`
+		FROM schedule_changes c
+		JOIN schedule_versions v ON v.id = c.version_id
+		JOIN foods f ON f.id = c.food_id
+		WHERE c.changed_at > ?
+		ORDER BY c.id
+	`, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-// }
+	type dish struct {
+		version   int64
+		week, day int
+		meal      string
+		food      int
+	}
+	type netChange struct {
+		start, end string
+		food       Food
+		net        int
+		changedAt  time.Time
+	}
+	var order []dish
+	changes := make(map[dish]*netChange)
+	for rows.Next() {
+		var d dish
+		var start, end, change string
+		var changedAt time.Time
+		var row foodRow
+		if err := rows.Scan(append([]interface{}{&d.version, &start, &end, &d.week, &d.day, &d.meal, &change, &changedAt}, row.dest()...)...); err != nil {
+			return nil, err
+		}
+		d.food = row.food.ID
+		c, ok := changes[d]
+		if !ok {
+			c = &netChange{start: start, end: end, food: row.result()}
+			changes[d] = c
+			order = append(order, d)
+		}
+		if change == "added" {
+			c.net++
+		} else {
+			c.net--
+		}
+		c.changedAt = changedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	type slot struct {
+		date, meal string
+	}
+	var result []MenuChange
+	index := make(map[slot]int)
+	var foods []*Food
+	for _, d := range order {
+		c := changes[d]
+		if c.net == 0 {
+			continue
+		}
+		start, err := time.Parse("2006-01-02", trimDate(c.start))
+		if err != nil {
+			return nil, err
+		}
+		for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+			day := date.Format("2006-01-02")
+			if day < trimDate(c.start) || (c.end != "" && day > trimDate(c.end)) {
+				continue
+			}
+			target, _ := time.Parse("2006-01-02", day)
+			daysDiff := int(target.Sub(start).Hours() / 24)
+			if (daysDiff/7)%4+1 != d.week || daysDiff%7+1 != d.day {
+				continue
+			}
+			key := slot{day, d.meal}
+			i, ok := index[key]
+			if !ok {
+				i = len(result)
+				index[key] = i
+				result = append(result, MenuChange{Date: day, MealType: d.meal, Added: []Food{}, Removed: []Food{}})
+			}
+			if c.net > 0 {
+				result[i].Added = append(result[i].Added, c.food)
+			} else {
+				result[i].Removed = append(result[i].Removed, c.food)
+			}
+			if c.changedAt.After(result[i].ChangedAt) {
+				result[i].ChangedAt = c.changedAt
+			}
+		}
+	}
+	for i := range result {
+		for j := range result[i].Added {
+			foods = append(foods, &result[i].Added[j])
+		}
+		for j := range result[i].Removed {
+			foods = append(foods, &result[i].Removed[j])
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Date != result[j].Date {
+			return result[i].Date < result[j].Date
+		}
+		return result[i].MealType < result[j].MealType
+	})
+	return result, r.attachFoodDetails(ctx, r.read, foods)
+}
+
+// trimDate drops the time part SQLite may add to a stored date
+func trimDate(date string) string {
+	if len(date) > 10 {
+		return date[:10]
+	}
+	return date
+}
 
 //   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
 //   API Copyright (C) 2025 OpenSourceDUTH
@@ -0,0 +1,50 @@
+package schedule
+
+import "testing"
+
+func TestHashImportItems(t *testing.T) {
+	menu := []ScheduleImportItem{
+		{WeekNumber: 1, DayNumber: 1, MealType: "lunch", DishIDs: []int{1, 2}},
+		{WeekNumber: 1, DayNumber: 1, MealType: "dinner", DishIDs: []int{3}},
+	}
+	tests := []struct {
+		name     string
+		items    []ScheduleImportItem
+		wantSame bool
+	}{
+		{"the same menu", menu, true},
+		{"slots in another order", []ScheduleImportItem{menu[1], menu[0]}, true},
+		{"dishes in another order", []ScheduleImportItem{
+			{WeekNumber: 1, DayNumber: 1, MealType: "lunch", DishIDs: []int{2, 1}},
+			menu[1],
+		}, true},
+		{"a slot split in two", []ScheduleImportItem{
+			{WeekNumber: 1, DayNumber: 1, MealType: "lunch", DishIDs: []int{1}},
+			{WeekNumber: 1, DayNumber: 1, MealType: "lunch", DishIDs: []int{2, 1}},
+			menu[1],
+		}, true},
+		{"meal types in another case", []ScheduleImportItem{
+			{WeekNumber: 1, DayNumber: 1, MealType: " Lunch", DishIDs: []int{1, 2}},
+			menu[1],
+		}, true},
+		{"another dish", []ScheduleImportItem{
+			{WeekNumber: 1, DayNumber: 1, MealType: "lunch", DishIDs: []int{1, 4}},
+			menu[1],
+		}, false},
+		{"a dish moved to another day", []ScheduleImportItem{
+			{WeekNumber: 1, DayNumber: 1, MealType: "lunch", DishIDs: []int{1}},
+			{WeekNumber: 1, DayNumber: 2, MealType: "lunch", DishIDs: []int{2}},
+			menu[1],
+		}, false},
+		{"a missing slot", menu[:1], false},
+	}
+	want := hashImportItems(normalizeImportItems(menu))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hashImportItems(normalizeImportItems(tt.items))
+			if (got == want) != tt.wantSame {
+				t.Errorf("hash %s, want same as %s: %t", got, want, tt.wantSame)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package schedule
+
+import (
+	"API/internal/campustime"
+	"testing"
+	"time"
+)
+
+func TestWeekOf(t *testing.T) {
+	date := func(value string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02", value, campustime.Location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	tests := []struct {
+		name       string
+		date       string
+		wantMonday string
+		wantSunday string
+	}{
+		{"a Monday", "2024-05-06", "2024-05-06", "2024-05-12"},
+		{"a Wednesday", "2024-05-08", "2024-05-06", "2024-05-12"},
+		{"a Sunday ends its week", "2024-05-12", "2024-05-06", "2024-05-12"},
+		{"across months", "2024-05-01", "2024-04-29", "2024-05-05"},
+		{"across years", "2025-01-01", "2024-12-30", "2025-01-05"},
+		{"the week clocks go forward", "2024-03-31", "2024-03-25", "2024-03-31"},
+		{"the week clocks go back", "2024-10-27", "2024-10-21", "2024-10-27"},
+		{"the week after clocks go back", "2024-10-28", "2024-10-28", "2024-11-03"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monday, sunday := weekOf(date(tt.date))
+			if !monday.Equal(date(tt.wantMonday)) || !sunday.Equal(date(tt.wantSunday)) {
+				t.Errorf("weekOf(%s) = %s, %s, want the midnights of %s, %s", tt.date, monday, sunday, tt.wantMonday, tt.wantSunday)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2024-05-06T10:00:00Z", time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC), false},
+		{"2024-05-06T13:00:00+03:00", time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC), false},
+		{"2024-05-06", time.Date(2024, 5, 5, 21, 0, 0, 0, time.UTC), false},  // summer time
+		{"2024-12-06", time.Date(2024, 12, 5, 22, 0, 0, 0, time.UTC), false}, // winter time
+		{"06/05/2024", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSince(%q) error = %v, want error %t", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"API/internal/auth"
+	"API/internal/campustime"
+	"API/internal/rpc/schedulepb"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Service serves schedule lookups over gRPC from the same repository as the HTTP handlers
+type Service struct {
+	schedulepb.UnimplementedScheduleServer
+	repo *Repository
+}
+
+// NewService creates a new schedule gRPC service
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// GetDateSchedule returns the menu of a date
+func (s *Service) GetDateSchedule(ctx context.Context, req *schedulepb.DateScheduleRequest) (*schedulepb.DateSchedule, error) {
+	date := campustime.Today()
+	if req.GetDate() != "" {
+		parsed, err := time.Parse("02012006", req.GetDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid date format. Please use DDMMYYYY")
+		}
+		date = parsed
+	}
+
+	schedule, err := s.repo.GetDateSchedule(ctx, date.Format("2006-01-02"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "We do not have a schedule for the requested date")
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &schedulepb.DateSchedule{Lunch: foodMessages(schedule.Lunch), Dinner: foodMessages(schedule.Dinner)}, nil
+}
+
+// GetUnseenAnnouncements returns the running announcements the caller has not seen
+func (s *Service) GetUnseenAnnouncements(ctx context.Context, req *schedulepb.UnseenAnnouncementsRequest) (*schedulepb.UnseenAnnouncements, error) {
+	user := auth.GetUserFromRPCContext(ctx)
+	if user == nil {
+		return nil, status.Error(codes.Unauthenticated, "Not authenticated")
+	}
+	if len(req.GetDeviceId()) > 128 {
+		return nil, status.Error(codes.InvalidArgument, "device_id is too long")
+	}
+
+	announcements, err := s.repo.GetUnseenAnnouncements(ctx, user.ID, req.GetDeviceId(), time.Now())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &schedulepb.UnseenAnnouncements{Count: int64(len(announcements))}
+	for _, a := range announcements {
+		resp.Announcements = append(resp.Announcements, &schedulepb.Announcement{
+			Id:           int64(a.ID),
+			Type:         a.Type,
+			Content:      a.Content,
+			StartingDate: a.StartingDate,
+			EndingDate:   a.EndingDate,
+			IsCurrent:    a.IsCurrent,
+		})
+	}
+	return resp, nil
+}
+
+// foodMessages converts foods to their messages in api/proto/schedule.proto
+func foodMessages(foods []Food) []*schedulepb.Food {
+	messages := make([]*schedulepb.Food, len(foods))
+	for i, f := range foods {
+		messages[i] = &schedulepb.Food{Id: int64(f.ID), Name: f.Name, Allergens: f.Allergens, Tags: f.Tags}
+	}
+	return messages
+}
+
+// RegisterGRPC registers the schedule service, guarded by the same feature as the routes
+func RegisterGRPC(s *grpc.Server, svc *Service, authMiddleware *auth.Middleware) {
+	schedulepb.RegisterScheduleServer(s, svc)
+
+	authMiddleware.RequireTokenRPC(schedulepb.Schedule_GetDateSchedule_FullMethodName, FeatureSlug)
+	authMiddleware.RequireTokenRPC(schedulepb.Schedule_GetUnseenAnnouncements_FullMethodName, FeatureSlug)
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
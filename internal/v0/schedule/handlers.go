@@ -1,8 +1,24 @@
 package schedule
 
 import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/campustime"
+	"API/internal/events"
+	"API/internal/pagination"
+	"API/internal/storage"
 	"API/internal/v0/common"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,90 +26,1968 @@ import (
 
 // Handler initialization that holds the Repository database connection so we can save the data
 type Handler struct {
-	repo *Repository
+	repo        *Repository
+	events      *events.Bus
+	preferences PreferenceStore
+	poster      *MenuPoster
+	ocr         OCR
 }
 
-func NewHandler(repo *Repository) *Handler {
-	return &Handler{repo: repo}
+func NewHandler(repo *Repository, bus *events.Bus) *Handler {
+	return &Handler{repo: repo, events: bus}
+}
+
+// PreferenceStore looks up users' dietary preferences; the auth Repository is one
+type PreferenceStore interface {
+	GetPreferences(ctx context.Context, userID int64) (*auth.Preferences, error)
+}
+
+// SetPreferences enables personalized menus, reading preferences from store
+func (h *Handler) SetPreferences(store PreferenceStore) {
+	h.preferences = store
+}
+
+// SetOCR enables importing photographed or scanned menus, recognized with ocr
+func (h *Handler) SetOCR(ocr OCR) {
+	h.ocr = ocr
+}
+
+// SetMenuPoster lets admins test channels with the poster that posts the daily menus
+func (h *Handler) SetMenuPoster(poster *MenuPoster) {
+	h.poster = poster
 }
 
 func (h *Handler) PostFood(c *gin.Context) {
 	var f Food
 	if err := c.ShouldBindJSON(&f); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	f.Name = strings.TrimSpace(f.Name)
+	f.NameEn = strings.TrimSpace(f.NameEn)
+	if f.Name == "" {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("name", "name is required")))
+		return
+	}
+	id, err := h.repo.CreateFood(c.Request.Context(), f)
+	if errors.Is(err, ErrDuplicateFood) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ListFoods returns foods with name search and pagination
+// GET /admin/foods?q=&limit=&cursor=
+func (h *Handler) ListFoods(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	query := c.Query("q")
+	foods, err := h.repo.ListFoods(c.Request.Context(), query, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list foods")))
+		return
+	}
+	total, err := h.repo.CountFoods(c.Request.Context(), query)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count foods")))
+		return
+	}
+
+	foods, next := pagination.Next(foods, page, func(f Food) int64 { return int64(f.ID) })
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"foods": foods,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
+}
+
+// GetFood returns one food
+// GET /admin/foods/:id
+func (h *Handler) GetFood(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid food ID")))
+		return
+	}
+	food, err := h.repo.GetFood(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get food")))
+		return
+	}
+	if food == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "food not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"food": food}))
+}
+
+// UpdateFood changes a food's names, nutrition, allergens or tags
+// PATCH /admin/foods/:id
+func (h *Handler) UpdateFood(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid food ID")))
+		return
+	}
+	var update FoodUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("name", "name cannot be empty")))
+			return
+		}
+		update.Name = &name
+	}
+	if update.NameEn != nil {
+		nameEn := strings.TrimSpace(*update.NameEn)
+		update.NameEn = &nameEn
+	}
+
+	updated, err := h.repo.UpdateFood(c.Request.Context(), id, update)
+	if errors.Is(err, ErrDuplicateFood) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update food")))
+		return
+	}
+	if !updated {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "food not found")))
+		return
+	}
+	food, _ := h.repo.GetFood(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"food": food}))
+}
+
+// DeleteFood deletes a food that no schedule item or closure serves
+// DELETE /admin/foods/:id
+func (h *Handler) DeleteFood(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid food ID")))
+		return
+	}
+
+	deleted, err := h.repo.DeleteFood(c.Request.Context(), id)
+	if errors.Is(err, ErrFoodInUse) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete food")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "food not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "food deleted"}))
+}
+
+// PutFoodImage replaces a food's image with a multipart upload in the "image" field,
+// stored as resized variants
+// PUT /admin/foods/:id/image
+func (h *Handler) PutFoodImage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid food ID")))
+		return
+	}
+	if h.repo.images == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "food images are not enabled")))
+		return
+	}
+	header, err := c.FormFile("image")
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("image", "a multipart image file is required")))
+		return
+	}
+	food, err := h.repo.GetFood(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get food")))
+		return
+	}
+	if food == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "food not found")))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("image", "failed to read the uploaded file")))
+		return
+	}
+	defer file.Close()
+	key, err := h.repo.images.Save(c.Request.Context(), id, file)
+	switch {
+	case errors.Is(err, storage.ErrUnsupportedType):
+		common.JSON(c, http.StatusUnsupportedMediaType, common.CreateErrorResponse(apierror.New(apierror.UnsupportedMedia, err.Error())))
+		return
+	case errors.Is(err, storage.ErrTooLarge):
+		common.JSON(c, http.StatusRequestEntityTooLarge, common.CreateErrorResponse(apierror.New(apierror.PayloadTooLarge, err.Error())))
+		return
+	case err != nil:
+		log.Printf("Failed to store image of food %d: %v", id, err)
+		common.JSON(c, http.StatusBadGateway, common.CreateErrorResponse(apierror.New(apierror.UpstreamFailed, "failed to store image")))
+		return
+	}
+
+	found, err := h.repo.SetFoodImage(c.Request.Context(), id, key)
+	if err != nil || !found {
+		// The food was deleted meanwhile, or the key was not saved; either way nothing uses the variants
+		_ = h.repo.images.Delete(c.Request.Context(), key)
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update food")))
+		return
+	}
+	if !found {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "food not found")))
+		return
+	}
+	food, _ = h.repo.GetFood(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"food": food}))
+}
+
+// DeleteFoodImage removes a food's image
+// DELETE /admin/foods/:id/image
+func (h *Handler) DeleteFoodImage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid food ID")))
+		return
+	}
+	found, err := h.repo.SetFoodImage(c.Request.Context(), id, "")
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update food")))
+		return
+	}
+	if !found {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "food not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "food image deleted"}))
+}
+
+// GetImage serves a stored image variant, or redirects to a signed URL of it when
+// the images are in a private bucket. Keys are unique per upload, so clients may
+// cache them indefinitely.
+// GET /images/*key
+func (h *Handler) GetImage(c *gin.Context) {
+	if h.repo.images == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "image not found")))
+		return
+	}
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	signed, err := h.repo.images.SignedURL(key)
+	if errors.Is(err, fs.ErrNotExist) {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "image not found")))
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to sign image URL %s: %v", key, err)
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to load image")))
+		return
+	}
+	if signed != "" {
+		// Cached redirects must not outlive the URL they point to
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.repo.images.SignedFor()/2/time.Second)))
+		c.Redirect(http.StatusFound, signed)
+		return
+	}
+
+	body, err := h.repo.images.Open(c.Request.Context(), key)
+	if errors.Is(err, fs.ErrNotExist) {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "image not found")))
 		return
 	}
-	if err := h.repo.CreateFood(f.Name); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{err.Error()}))
+	if err != nil {
+		log.Printf("Failed to load image %s: %v", c.Param("key"), err)
+		common.JSON(c, http.StatusBadGateway, common.CreateErrorResponse(apierror.New(apierror.UpstreamFailed, "failed to load image")))
 		return
 	}
-	c.JSON(http.StatusCreated, common.CreateSuccessResponse(nil))
+	defer body.Close()
+
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.DataFromReader(http.StatusOK, -1, "image/jpeg", body, nil)
 }
 
 func (h *Handler) PostVersion(c *gin.Context) {
 	var v ScheduleVersion
 	if err := c.ShouldBindJSON(&v); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	if errs := validateDateRange(v.StartingDate, v.EndingDate); len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
 		return
 	}
-	id, err := h.repo.CreateVersion(v.StartingDate, v.EndingDate, v.IsCurrent)
+	id, err := h.repo.CreateVersion(c.Request.Context(), v.StartingDate, v.EndingDate, v.IsCurrent)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
 		return
 	}
-	c.JSON(http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+	h.events.Publish(c.Request.Context(), events.ScheduleVersionPublished{
+		VersionID:    id,
+		StartingDate: v.StartingDate,
+		EndingDate:   v.EndingDate,
+		IsCurrent:    v.IsCurrent,
+		OccurredAt:   time.Now(),
+	})
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
 }
 
 func (h *Handler) PostSchedule(c *gin.Context) {
 	var s ScheduleItem
 	if err := c.ShouldBindJSON(&s); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
 		return
 	}
-	if err := h.repo.CreateScheduleItem(s.VersionID, s.WeekNumber, s.DayNumber, s.MealType, s.DishIDs); err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{err.Error()}))
+	exists, err := h.repo.VersionExists(c.Request.Context(), s.VersionID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	errs, err := h.validateDishes(c.Request.Context(), "dish_ids", s.DishIDs)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	if !exists {
+		errs = append(errs, apierror.Invalid("version_id", "schedule version does not exist"))
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+	if err := h.repo.CreateScheduleItem(c.Request.Context(), s.VersionID, s.WeekNumber, s.DayNumber, s.MealType, s.DishIDs); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
 		return
 	}
-	c.JSON(http.StatusCreated, common.CreateSuccessResponse(nil))
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(nil))
 }
 
-func (h *Handler) PostAnnouncement(c *gin.Context) {
-	var a Announcement
-	if err := c.ShouldBindJSON(&a); err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+// GetScheduleItem returns a schedule item with its dish list
+// GET /admin/items/:id
+func (h *Handler) GetScheduleItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid schedule item ID")))
 		return
 	}
-	id, err := h.repo.CreateAnnouncement(a.Type, a.Content, a.StartingDate, a.EndingDate, a.IsCurrent)
+	item, err := h.repo.GetScheduleItem(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse([]string{err.Error()}))
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get schedule item")))
+		return
+	}
+	if item == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "schedule item not found")))
 		return
 	}
-	c.JSON(http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"item": item}))
 }
 
-func (h *Handler) GetSchedule(c *gin.Context) {
-	allParameter := c.Query("all")
-	dateParameter := c.Query("date")
+// ReplaceScheduleItem sets every field of a schedule item
+// PUT /admin/items/:id
+func (h *Handler) ReplaceScheduleItem(c *gin.Context) {
+	var item ScheduleImportItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	h.updateScheduleItem(c, ScheduleItemUpdate{
+		WeekNumber: &item.WeekNumber,
+		DayNumber:  &item.DayNumber,
+		MealType:   &item.MealType,
+		DishIDs:    item.DishIDs,
+	})
+}
 
-	// Check
-	if dateParameter != "" {
-		parsedTime, err := time.Parse("02012006", dateParameter)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{"Invalid date format. Please use DDMMYYYY"}))
-			return
-		}
+// UpdateScheduleItem changes the fields of a schedule item that are set
+// PATCH /admin/items/:id
+func (h *Handler) UpdateScheduleItem(c *gin.Context) {
+	var update ScheduleItemUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	h.updateScheduleItem(c, update)
+}
 
-		formatedDate := parsedTime.Format("2006-01-02")
-		schedule, err := h.repo.GetDateSchedule(formatedDate)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, common.CreateErrorResponse([]string{err.Error()}))
+func (h *Handler) updateScheduleItem(c *gin.Context, update ScheduleItemUpdate) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid schedule item ID")))
+		return
+	}
+	if update.MealType != nil {
+		mealType, ok := normalizeMealType(*update.MealType)
+		if !ok {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("meal_type", "meal_type must be lunch or dinner")))
 			return
 		}
-		c.JSON(http.StatusOK, common.CreateSuccessResponse(schedule))
+		update.MealType = &mealType
+	}
+
+	updated, err := h.repo.UpdateScheduleItem(c.Request.Context(), id, update)
+	if errors.Is(err, ErrUnknownFood) {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("dish_ids", err.Error())))
 		return
-	} else if allParameter == "true" {
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update schedule item")))
+		return
+	}
+	if !updated {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "schedule item not found")))
+		return
+	}
+
+	item, _ := h.repo.GetScheduleItem(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"item": item}))
+}
+
+// DeleteScheduleItem deletes a schedule item and its dish list
+// DELETE /admin/items/:id
+func (h *Handler) DeleteScheduleItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid schedule item ID")))
+		return
+	}
+
+	deleted, err := h.repo.DeleteScheduleItem(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete schedule item")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "schedule item not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "schedule item deleted"}))
+}
+
+// PostImport imports a complete menu as a new version. Importing a menu whose items match an
+// existing version returns that version with 200 instead of publishing a duplicate.
+func (h *Handler) PostImport(c *gin.Context) {
+	var imp ScheduleImport
+	if err := c.ShouldBindJSON(&imp); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	errs, err := h.validateImport(c.Request.Context(), &imp)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+	result, err := h.repo.ImportSchedule(c.Request.Context(), imp)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	if result.Duplicate {
+		common.JSON(c, http.StatusOK, common.CreateSuccessResponse(result))
+		return
+	}
+	h.events.Publish(c.Request.Context(), events.ScheduleVersionPublished{
+		VersionID:    result.VersionID,
+		StartingDate: imp.StartingDate,
+		EndingDate:   imp.EndingDate,
+		IsCurrent:    imp.IsCurrent,
+		OccurredAt:   time.Now(),
+	})
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(result))
+}
+
+// PostSpreadsheetImport imports a menu spreadsheet, as sent by the catering office, as a new
+// version. With ?dry_run=true the file is only validated and nothing is created.
+func (h *Handler) PostSpreadsheetImport(c *gin.Context) {
+	var form SpreadsheetImport
+	if err := c.ShouldBind(&form); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("dry_run", "dry_run must be true or false")))
+		return
+	}
+	start, errs := parseDateRange(form.StartingDate, form.EndingDate)
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("file", "a multipart CSV or XLSX file is required")))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("file", "failed to read the file")))
+		return
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("file", "failed to read the file")))
+		return
+	}
+
+	rows, err := readSpreadsheet(data)
+	if errors.Is(err, ErrUnsupportedSpreadsheet) {
+		common.JSON(c, http.StatusUnsupportedMediaType, common.CreateErrorResponse(apierror.New(apierror.UnsupportedMedia, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("file", err.Error())))
+		return
+	}
+	menu, errs := parseSpreadsheet(rows, start)
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+
+	imp := ScheduleImport{StartingDate: form.StartingDate, EndingDate: form.EndingDate, IsCurrent: form.IsCurrent}
+	h.importMenu(c, imp, menu, dryRun)
+}
+
+// PostMenuScan recognizes the text of a photographed or scanned menu and returns it as a
+// draft of meal slots, which the admin corrects and confirms with PostMenuDraft. Nothing
+// is stored.
+func (h *Handler) PostMenuScan(c *gin.Context) {
+	if h.ocr == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "OCR is not configured")))
+		return
+	}
+	var form SpreadsheetImport
+	if err := c.ShouldBind(&form); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	start, errs := parseDateRange(form.StartingDate, form.EndingDate)
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("file", "a multipart image or PDF file is required")))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("file", "failed to read the file")))
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, MaxScanBytes+1))
+	file.Close()
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("file", "failed to read the file")))
+		return
+	}
+	if len(data) > MaxScanBytes {
+		common.JSON(c, http.StatusRequestEntityTooLarge, common.CreateErrorResponse(apierror.New(apierror.PayloadTooLarge, fmt.Sprintf("file exceeds %d bytes", MaxScanBytes))))
+		return
+	}
+	if err := checkScan(data); err != nil {
+		common.JSON(c, http.StatusUnsupportedMediaType, common.CreateErrorResponse(apierror.New(apierror.UnsupportedMedia, err.Error())))
+		return
+	}
+
+	text, err := h.ocr.Recognize(c.Request.Context(), data)
+	if err != nil {
+		common.JSON(c, http.StatusBadGateway, common.CreateErrorResponse(apierror.New(apierror.UpstreamFailed, "OCR failed: "+err.Error())))
+		return
+	}
+	draft := parseMenuText(text, start)
+	draft.StartingDate, draft.EndingDate, draft.IsCurrent = form.StartingDate, form.EndingDate, form.IsCurrent
+	draft.Text = text
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(draft))
+}
 
+// PostMenuDraft imports a menu draft returned by PostMenuScan, once the admin has
+// corrected it, as a new version. It takes ?dry_run=true like PostSpreadsheetImport.
+func (h *Handler) PostMenuDraft(c *gin.Context) {
+	var draft MenuDraft
+	if err := c.ShouldBindJSON(&draft); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("dry_run", "dry_run must be true or false")))
+		return
+	}
+	if errs := validateDateRange(draft.StartingDate, draft.EndingDate); len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
 	}
+	imp := ScheduleImport{StartingDate: draft.StartingDate, EndingDate: draft.EndingDate, IsCurrent: draft.IsCurrent}
+	h.importMenu(c, imp, draft.menu(), dryRun)
+}
+
+// importMenu creates a version from a parsed menu and responds with what was created
+func (h *Handler) importMenu(c *gin.Context, imp ScheduleImport, menu SpreadsheetMenu, dryRun bool) {
+	result, err := h.repo.ImportSpreadsheet(c.Request.Context(), imp, menu, dryRun)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	if result.DryRun || result.Duplicate {
+		common.JSON(c, http.StatusOK, common.CreateSuccessResponse(result))
+		return
+	}
+	h.events.Publish(c.Request.Context(), events.ScheduleVersionPublished{
+		VersionID:    result.VersionID,
+		StartingDate: imp.StartingDate,
+		EndingDate:   imp.EndingDate,
+		IsCurrent:    imp.IsCurrent,
+		OccurredAt:   time.Now(),
+	})
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(result))
+}
+
+func (h *Handler) PostAnnouncement(c *gin.Context) {
+	var a Announcement
+	if err := c.ShouldBindJSON(&a); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	if errs := normalizeAnnouncement(&a); len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+	id, err := h.repo.CreateAnnouncement(c.Request.Context(), a)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	h.events.Publish(c.Request.Context(), events.AnnouncementPublished{
+		AnnouncementID: id,
+		Type:           a.Type,
+		Content:        a.Content,
+		ContentEn:      a.ContentEn,
+		StartingDate:   a.StartingDate,
+		EndingDate:     a.EndingDate,
+		OccurredAt:     time.Now(),
+	})
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// UpdateAnnouncement changes an announcement, e.g. to fix a typo or reschedule it
+// PATCH /admin/announcements/:id
+func (h *Handler) UpdateAnnouncement(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid announcement ID")))
+		return
+	}
+	var update AnnouncementUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	if update.EndingDate != nil && *update.EndingDate != "" {
+		if _, err := time.Parse("2006-01-02", *update.EndingDate); err != nil {
+			common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "Invalid ending_date. Please use YYYY-MM-DD, or an empty string for no end")))
+			return
+		}
+	}
+
+	announcement, err := h.repo.GetAnnouncement(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update announcement")))
+		return
+	}
+	if announcement == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "announcement not found")))
+		return
+	}
+
+	// The schedule is validated as a whole, e.g. new times against the dates
+	if update.StartingDate != nil {
+		announcement.StartingDate = *update.StartingDate
+	}
+	if update.EndingDate != nil {
+		announcement.EndingDate = *update.EndingDate
+	}
+	if update.StartsAt != nil {
+		announcement.StartsAt = *update.StartsAt
+	}
+	if update.EndsAt != nil {
+		announcement.EndsAt = *update.EndsAt
+	}
+	if update.RepeatOn != nil {
+		announcement.RepeatOn = *update.RepeatOn
+	}
+	if errs := normalizeAnnouncement(announcement); len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+	if update.StartsAt != nil {
+		update.StartsAt = &announcement.StartsAt
+	}
+	if update.EndsAt != nil {
+		update.EndsAt = &announcement.EndsAt
+	}
+
+	updated, err := h.repo.UpdateAnnouncement(c.Request.Context(), id, update)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update announcement")))
+		return
+	}
+	if !updated {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "announcement not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "announcement updated"}))
+}
+
+// DeleteAnnouncement deletes an announcement and its receipts
+// DELETE /admin/announcements/:id
+func (h *Handler) DeleteAnnouncement(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid announcement ID")))
+		return
+	}
+
+	deleted, err := h.repo.DeleteAnnouncement(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete announcement")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "announcement not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "announcement deleted"}))
+}
+
+// PostClosure closes the cafeteria on a date or range, optionally with a replacement menu
+// POST /admin/closures
+func (h *Handler) PostClosure(c *gin.Context) {
+	closure, ok := h.bindClosure(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateClosure(c.Request.Context(), closure)
+	if errors.Is(err, ErrClosureOverlap) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create closure")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ListClosures returns closures with pagination, only those not over yet with ?upcoming=true
+// GET /admin/closures?upcoming=&limit=&cursor=
+func (h *Handler) ListClosures(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	var from string
+	if v := c.Query("upcoming"); v != "" {
+		upcoming, err := strconv.ParseBool(v)
+		if err != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "upcoming must be true or false")))
+			return
+		}
+		if upcoming {
+			from = campustime.Today().Format("2006-01-02")
+		}
+	}
+	h.renderClosures(c, from, page, "")
+}
+
+// ListUpcomingClosures returns the closures that are not over yet, so apps can warn ahead
+// GET /schedule/closures?lang=&limit=&cursor=
+func (h *Handler) ListUpcomingClosures(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	h.renderClosures(c, campustime.Today().Format("2006-01-02"), page, lang)
+}
+
+// renderClosures renders a page of the closures ending on or after from, localized to
+// lang unless it is empty
+func (h *Handler) renderClosures(c *gin.Context, from string, page pagination.Params, lang string) {
+	closures, err := h.repo.ListClosures(c.Request.Context(), from, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list closures")))
+		return
+	}
+	total, err := h.repo.CountClosures(c.Request.Context(), from)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count closures")))
+		return
+	}
+
+	closures, next := pagination.Next(closures, page, func(closure Closure) int64 { return int64(closure.ID) })
+	if lang != "" {
+		for i := range closures {
+			closures[i] = closures[i].Localized(lang)
+		}
+	}
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"closures": closures,
+		"total":    total,
+		"limit":    page.Limit,
+	}, next))
+}
+
+// GetClosure returns a closure with its replacement menu
+// GET /admin/closures/:id
+func (h *Handler) GetClosure(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid closure ID")))
+		return
+	}
+	closure, err := h.repo.GetClosure(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get closure")))
+		return
+	}
+	if closure == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "closure not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"closure": closure}))
+}
+
+// ReplaceClosure overwrites a closure's dates, reason and replacement menu
+// PUT /admin/closures/:id
+func (h *Handler) ReplaceClosure(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid closure ID")))
+		return
+	}
+	closure, ok := h.bindClosure(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceClosure(c.Request.Context(), id, closure)
+	if errors.Is(err, ErrClosureOverlap) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update closure")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "closure not found")))
+		return
+	}
+	updated, _ := h.repo.GetClosure(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"closure": updated}))
+}
+
+// DeleteClosure deletes a closure, so the rotation serves its dates again
+// DELETE /admin/closures/:id
+func (h *Handler) DeleteClosure(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid closure ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteClosure(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete closure")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "closure not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "closure deleted"}))
+}
+
+// bindClosure binds and validates a closure body. It renders an error and returns false
+// when the body is invalid.
+func (h *Handler) bindClosure(c *gin.Context) (Closure, bool) {
+	var closure Closure
+	if err := c.ShouldBindJSON(&closure); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Closure{}, false
+	}
+	closure.Reason = strings.TrimSpace(closure.Reason)
+	closure.ReasonEn = strings.TrimSpace(closure.ReasonEn)
+	errs, err := h.validateClosure(c.Request.Context(), closure)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to validate closure")))
+		return Closure{}, false
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Closure{}, false
+	}
+	return closure, true
+}
+
+// GetHours returns every restaurant with the hours it serves each meal, so apps can show
+// e.g. "dinner starts at 18:30"
+// GET /schedule/hours?lang=
+func (h *Handler) GetHours(c *gin.Context) {
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	restaurants, err := h.repo.ListRestaurants(c.Request.Context())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list restaurants")))
+		return
+	}
+	for i := range restaurants {
+		restaurants[i] = restaurants[i].Localized(lang)
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"restaurants": restaurants}))
+}
+
+// ListRestaurants returns every restaurant with its serving hours and translations
+// GET /admin/restaurants
+func (h *Handler) ListRestaurants(c *gin.Context) {
+	restaurants, err := h.repo.ListRestaurants(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list restaurants")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"restaurants": restaurants}))
+}
+
+// PostRestaurant adds a restaurant with its serving hours
+// POST /admin/restaurants
+func (h *Handler) PostRestaurant(c *gin.Context) {
+	restaurant, ok := bindRestaurant(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateRestaurant(c.Request.Context(), restaurant)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create restaurant")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// GetRestaurant returns a restaurant with its serving hours
+// GET /admin/restaurants/:id
+func (h *Handler) GetRestaurant(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid restaurant ID")))
+		return
+	}
+	restaurant, err := h.repo.GetRestaurant(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get restaurant")))
+		return
+	}
+	if restaurant == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "restaurant not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"restaurant": restaurant}))
+}
+
+// ReplaceRestaurant overwrites a restaurant's names and serving hours
+// PUT /admin/restaurants/:id
+func (h *Handler) ReplaceRestaurant(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid restaurant ID")))
+		return
+	}
+	restaurant, ok := bindRestaurant(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceRestaurant(c.Request.Context(), id, restaurant)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update restaurant")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "restaurant not found")))
+		return
+	}
+	updated, _ := h.repo.GetRestaurant(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"restaurant": updated}))
+}
+
+// DeleteRestaurant deletes a restaurant and its serving hours
+// DELETE /admin/restaurants/:id
+func (h *Handler) DeleteRestaurant(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid restaurant ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteRestaurant(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete restaurant")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "restaurant not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "restaurant deleted"}))
+}
+
+// bindRestaurant binds and validates a restaurant body. It renders an error and returns
+// false when the body is invalid.
+func bindRestaurant(c *gin.Context) (Restaurant, bool) {
+	var restaurant Restaurant
+	if err := c.ShouldBindJSON(&restaurant); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Restaurant{}, false
+	}
+	restaurant.Name = strings.TrimSpace(restaurant.Name)
+	restaurant.NameEn = strings.TrimSpace(restaurant.NameEn)
+	// 9:00 binds too; zero-padded times compare correctly as text
+	for i, h := range restaurant.Hours {
+		opens, _ := time.Parse("15:04", h.Opens)
+		closes, _ := time.Parse("15:04", h.Closes)
+		restaurant.Hours[i].Opens, restaurant.Hours[i].Closes = opens.Format("15:04"), closes.Format("15:04")
+	}
+	if restaurant.Name == "" {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("name", "name is required")))
+		return Restaurant{}, false
+	}
+	if errs := validateRestaurant(restaurant); len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Restaurant{}, false
+	}
+	return restaurant, true
+}
+
+// ListChannels returns the chat channels the daily menu is posted to
+// GET /admin/channels
+func (h *Handler) ListChannels(c *gin.Context) {
+	channels, err := h.repo.ListChannels(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list channels")))
+		return
+	}
+	for i := range channels {
+		channels[i] = channels[i].Redacted()
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"channels": channels}))
+}
+
+// PostChannel adds a Discord webhook or Telegram chat to post the daily menu to. New
+// channels are active unless the body says otherwise.
+// POST /admin/channels
+func (h *Handler) PostChannel(c *gin.Context) {
+	channel := MenuChannel{Active: true}
+	if err := c.ShouldBindJSON(&channel); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	if channel.Language == "" {
+		channel.Language = LanguageGreek
+	}
+	if channel.PostAt == "" {
+		channel.PostAt = "10:00"
+	}
+	if errs := normalizeChannel(&channel); len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+	id, err := h.repo.CreateChannel(c.Request.Context(), channel)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create channel")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// GetChannel returns a channel with the outcome of its last post
+// GET /admin/channels/:id
+func (h *Handler) GetChannel(c *gin.Context) {
+	channel, ok := h.channelTarget(c)
+	if !ok {
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"channel": channel.Redacted()}))
+}
+
+// UpdateChannel changes a channel's target, language, post time or template, or enables
+// or disables it
+// PATCH /admin/channels/:id
+func (h *Handler) UpdateChannel(c *gin.Context) {
+	channel, ok := h.channelTarget(c)
+	if !ok {
+		return
+	}
+	var update MenuChannelUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	// The changed channel is validated as a whole, e.g. a new target against its kind
+	if update.Target != nil {
+		channel.Target = *update.Target
+	}
+	if update.BotToken != nil && *update.BotToken != "" {
+		channel.BotToken = *update.BotToken
+	}
+	if update.Language != nil {
+		channel.Language = *update.Language
+	}
+	if update.PostAt != nil {
+		channel.PostAt = *update.PostAt
+	}
+	if update.Template != nil {
+		channel.Template = *update.Template
+	}
+	if errs := normalizeChannel(channel); len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return
+	}
+	if update.Target != nil {
+		update.Target = &channel.Target
+	}
+	if update.PostAt != nil {
+		update.PostAt = &channel.PostAt
+	}
+	if update.Template != nil {
+		update.Template = &channel.Template
+	}
+
+	updated, err := h.repo.UpdateChannel(c.Request.Context(), channel.ID, update)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update channel")))
+		return
+	}
+	if !updated {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "channel not found")))
+		return
+	}
+	saved, _ := h.repo.GetChannel(c.Request.Context(), channel.ID)
+	if saved == nil {
+		saved = channel
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"channel": saved.Redacted()}))
+}
+
+// DeleteChannel stops posting to a channel and forgets it
+// DELETE /admin/channels/:id
+func (h *Handler) DeleteChannel(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid channel ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteChannel(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete channel")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "channel not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "channel deleted"}))
+}
+
+// TestChannel posts today's menu to a channel right away, so admins can check the target
+// and template. It does not count as the day's post.
+// POST /admin/channels/:id/test
+func (h *Handler) TestChannel(c *gin.Context) {
+	if h.poster == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "menu posting is not enabled")))
+		return
+	}
+	channel, ok := h.channelTarget(c)
+	if !ok {
+		return
+	}
+	if err := h.poster.Post(c.Request.Context(), channel, campustime.Today()); err != nil {
+		common.JSON(c, http.StatusBadGateway, common.CreateErrorResponse(apierror.New(apierror.UpstreamFailed, "failed to post: "+err.Error())))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "menu posted"}))
+}
+
+// channelTarget returns the channel named by the :id parameter. It renders an error and
+// returns false when it is invalid or does not exist.
+func (h *Handler) channelTarget(c *gin.Context) (*MenuChannel, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid channel ID")))
+		return nil, false
+	}
+	channel, err := h.repo.GetChannel(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get channel")))
+		return nil, false
+	}
+	if channel == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "channel not found")))
+		return nil, false
+	}
+	return channel, true
+}
+
+// PostAnnouncementReceipts marks announcements as delivered or seen for the token's user
+func (h *Handler) PostAnnouncementReceipts(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+
+	var r AnnouncementReceipt
+	if err := c.ShouldBindJSON(&r); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	if err := h.repo.MarkAnnouncements(c.Request.Context(), user.ID, r.DeviceID, r.AnnouncementIDs, r.Status == "seen"); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(nil))
+}
+
+// GetUnseenAnnouncements returns the running announcements the token's user (or device, via ?device_id=) has not seen
+func (h *Handler) GetUnseenAnnouncements(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.Render(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+
+	deviceID := c.Query("device_id")
+	if len(deviceID) > 128 {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "device_id is too long")))
+		return
+	}
+
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+
+	announcements, err := h.repo.GetUnseenAnnouncements(c.Request.Context(), user.ID, deviceID, time.Now())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	for i := range announcements {
+		announcements[i] = announcements[i].Localized(lang)
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(UnseenAnnouncements{
+		Count:         len(announcements),
+		Announcements: announcements,
+	}))
+}
+
+// ListAnnouncements returns announcements with filters and pagination
+// GET /announcements?type=&active=&lang=&limit=&cursor=
+func (h *Handler) ListAnnouncements(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+
+	var filter AnnouncementFilter
+	if v := c.Query("type"); v != "" {
+		if !slices.Contains(AnnouncementTypes, v) {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "type must be one of "+strings.Join(AnnouncementTypes, ", "))))
+			return
+		}
+		filter.Type = v
+	}
+	if v := c.Query("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "active must be true or false")))
+			return
+		}
+		if active {
+			filter.ActiveOn = campustime.Today().Format("2006-01-02")
+		}
+	}
+
+	announcements, err := h.repo.ListAnnouncements(c.Request.Context(), filter, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list announcements")))
+		return
+	}
+	total, err := h.repo.CountAnnouncements(c.Request.Context(), filter)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count announcements")))
+		return
+	}
+
+	announcements, next := pagination.Next(announcements, page, func(a Announcement) int64 { return int64(a.ID) })
+	for i := range announcements {
+		announcements[i] = announcements[i].Localized(lang)
+	}
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"announcements": announcements,
+		"total":         total,
+		"limit":         page.Limit,
+	}, next))
+}
+
+// MaxRangeDays caps how many dates one ?from=&to= request resolves
+const MaxRangeDays = 31
+
+func (h *Handler) GetSchedule(c *gin.Context) {
+	allParameter := c.Query("all")
+	dateParameter := c.Query("date")
+	fromParameter, toParameter := c.Query("from"), c.Query("to")
+	view, ok := parseMenuView(c)
+	if !ok {
+		return
+	}
+
+	// Check
+	if fromParameter != "" || toParameter != "" {
+		from, fromErr := time.Parse("02012006", fromParameter)
+		to, toErr := time.Parse("02012006", toParameter)
+		if fromErr != nil || toErr != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "Invalid from or to. Please use DDMMYYYY for both")))
+			return
+		}
+		if to.Before(from) {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "to must not be before from")))
+			return
+		}
+		if days := int(to.Sub(from).Hours()/24) + 1; days > MaxRangeDays {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, fmt.Sprintf("The range may span at most %d days", MaxRangeDays))))
+			return
+		}
+
+		schedule, err := h.repo.GetRangeSchedule(c.Request.Context(), from, to)
+		if err != nil {
+			common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+			return
+		}
+		common.Render(c, http.StatusOK, common.CreateSuccessResponse(view.days(schedule)))
+		return
+	} else if dateParameter != "" {
+		parsedTime, err := time.Parse("02012006", dateParameter)
+		if err != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "Invalid date format. Please use DDMMYYYY")))
+			return
+		}
+
+		formatedDate := parsedTime.Format("2006-01-02")
+		schedule, err := h.repo.GetDateSchedule(c.Request.Context(), formatedDate)
+		if err != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+			return
+		}
+		common.Render(c, http.StatusOK, common.CreateSuccessResponse(view.meals(*schedule)))
+		return
+	} else if allParameter == "true" {
+		schedule, err := h.repo.GetCurrentSchedule(c.Request.Context())
+		if errors.Is(err, sql.ErrNoRows) {
+			common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "There is no current schedule")))
+			return
+		}
+		if err != nil {
+			common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+			return
+		}
+		if view.diet != "" {
+			schedule = schedule.WithTag(view.diet)
+		}
+		common.Render(c, http.StatusOK, common.CreateSuccessResponse(schedule.Localized(view.lang)))
+		return
+	}
+
+	common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "One of date, from and to, or all=true is required")))
+}
+
+// GetVersion returns the current version's date range and when it last changed, so clients
+// can tell when to refetch the menu. It answers If-Modified-Since with 304.
+func (h *Handler) GetVersion(c *gin.Context) {
+	version, err := h.repo.GetCurrentVersion(c.Request.Context())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	if version == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "There is no current schedule")))
+		return
+	}
+
+	if version.UpdatedAt != nil {
+		// HTTP dates have second precision
+		modified := version.UpdatedAt.UTC().Truncate(time.Second)
+		c.Header("Last-Modified", modified.Format(http.TimeFormat))
+		if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !modified.After(since) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(version))
+}
+
+// GetToday returns today's menu in the cafeteria's time zone
+func (h *Handler) GetToday(c *gin.Context) {
+	h.renderDay(c, campustime.Today())
+}
+
+// GetTomorrow returns tomorrow's menu in the cafeteria's time zone
+func (h *Handler) GetTomorrow(c *gin.Context) {
+	h.renderDay(c, campustime.Today().AddDate(0, 0, 1))
+}
+
+// GetWeek returns the menus of the current week, Monday to Sunday
+func (h *Handler) GetWeek(c *gin.Context) {
+	view, ok := parseMenuView(c)
+	if !ok {
+		return
+	}
+	monday, sunday := weekOf(campustime.Today())
+	schedule, err := h.repo.GetRangeSchedule(c.Request.Context(), monday, sunday)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(view.days(schedule)))
+}
+
+// GetStats returns how often dishes were served over the last days, up to today: the
+// most frequent ones, or with ?sort=absent those not served for the longest
+// GET /schedule/stats?days=&limit=&sort=&lang=
+func (h *Handler) GetStats(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(DefaultStatsDays)))
+	if err != nil || days < 1 || days > MaxStatsDays {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("days", fmt.Sprintf("days must be between 1 and %d", MaxStatsDays))))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(pagination.DefaultLimit)))
+	if err != nil || limit < 1 || limit > pagination.MaxLimit {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("limit", fmt.Sprintf("limit must be between 1 and %d", pagination.MaxLimit))))
+		return
+	}
+	sort := c.DefaultQuery("sort", StatsSortFrequent)
+	if sort != StatsSortFrequent && sort != StatsSortAbsent {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("sort", "sort must be frequent or absent")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+
+	end := campustime.Today()
+	schedule, err := h.repo.GetRangeSchedule(c.Request.Context(), end.AddDate(0, 0, 1-days), end)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(menuStats(schedule, sort, limit).Localized(lang)))
+}
+
+func (h *Handler) renderDay(c *gin.Context, date time.Time) {
+	view, ok := parseMenuView(c)
+	if !ok {
+		return
+	}
+	day := DaySchedule{Date: date.Format("2006-01-02")}
+	schedule, err := h.repo.GetDateSchedule(c.Request.Context(), day.Date)
+	if errors.Is(err, sql.ErrNoRows) {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "We do not have a schedule for the requested date")))
+		return
+	}
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+	day.DateSchedule = view.meals(*schedule)
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(day))
+}
+
+// menuView is how a client asked to see menus: in a language, and optionally only the
+// foods with a dietary tag
+type menuView struct {
+	lang string
+	diet string // one of FoodTags, or empty for every food
+}
+
+// parseMenuView reads ?diet= and the language (see common.ParseLanguage). It renders an error
+// and returns false for unknown tags and languages.
+func parseMenuView(c *gin.Context) (menuView, bool) {
+	diet := c.Query("diet")
+	if diet != "" && !slices.Contains(FoodTags, diet) {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "diet must be one of "+strings.Join(FoodTags, ", "))))
+		return menuView{}, false
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	return menuView{lang: lang, diet: diet}, ok
+}
+
+// meals applies the view to one menu
+func (v menuView) meals(d DateSchedule) DateSchedule {
+	if v.diet != "" {
+		d = d.WithTag(v.diet)
+	}
+	return d.Localized(v.lang)
+}
+
+// days applies the view to the menus of a range
+func (v menuView) days(days []DaySchedule) []DaySchedule {
+	for i := range days {
+		days[i].DateSchedule = v.meals(days[i].DateSchedule)
+	}
+	return days
+}
+
+// GetFoodReviews returns the visible reviews of a food that have text, newest first
+// GET /schedule/foods/:id/reviews?limit=&cursor=
+func (h *Handler) GetFoodReviews(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid food ID")))
+		return
+	}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	visible := false
+	h.renderReviews(c, ReviewFilter{FoodID: id, Hidden: &visible, WithText: true}, page, false)
+}
+
+// ListReviews returns reviews for moderation, optionally of one food or by hidden state
+// GET /admin/reviews?food_id=&hidden=&limit=&cursor=
+func (h *Handler) ListReviews(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	var filter ReviewFilter
+	if v := c.Query("food_id"); v != "" {
+		if filter.FoodID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid food ID")))
+			return
+		}
+	}
+	if v := c.Query("hidden"); v != "" {
+		hidden, err := strconv.ParseBool(v)
+		if err != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "hidden must be true or false")))
+			return
+		}
+		filter.Hidden = &hidden
+	}
+	h.renderReviews(c, filter, page, true)
+}
+
+// renderReviews renders a page of the reviews matching filter. Reviewers are only shown
+// to admins.
+func (h *Handler) renderReviews(c *gin.Context, filter ReviewFilter, page pagination.Params, admin bool) {
+	reviews, err := h.repo.ListReviews(c.Request.Context(), filter, page)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list reviews")))
+		return
+	}
+	total, err := h.repo.CountReviews(c.Request.Context(), filter)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to count reviews")))
+		return
+	}
+
+	reviews, next := pagination.Next(reviews, page, func(rv Review) int64 { return rv.ID })
+	if !admin {
+		for i := range reviews {
+			reviews[i].UserID = 0
+		}
+	}
+	common.Render(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"reviews": reviews,
+		"total":   total,
+		"limit":   page.Limit,
+	}, next))
+}
+
+// GetMyReview returns the calling user's rating of a food
+// GET /schedule/foods/:id/rating
+func (h *Handler) GetMyReview(c *gin.Context) {
+	user, id, ok := reviewTarget(c)
+	if !ok {
+		return
+	}
+	review, err := h.repo.GetUserReview(c.Request.Context(), id, user.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get rating")))
+		return
+	}
+	if review == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "you have not rated this food")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(review))
+}
+
+// PutMyReview sets the calling user's rating of a food from 1 to 5, with an optional review
+// PUT /schedule/foods/:id/rating
+func (h *Handler) PutMyReview(c *gin.Context) {
+	user, id, ok := reviewTarget(c)
+	if !ok {
+		return
+	}
+	var body Review
+	if err := c.ShouldBindJSON(&body); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	review, err := h.repo.RateFood(c.Request.Context(), id, user.ID, body.Rating, strings.TrimSpace(body.Review))
+	if errors.Is(err, ErrReviewHidden) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to save rating")))
+		return
+	}
+	if review == nil {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "food not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(review))
+}
+
+// DeleteMyReview withdraws the calling user's rating of a food
+// DELETE /schedule/foods/:id/rating
+func (h *Handler) DeleteMyReview(c *gin.Context) {
+	user, id, ok := reviewTarget(c)
+	if !ok {
+		return
+	}
+	deleted, err := h.repo.DeleteUserReview(c.Request.Context(), id, user.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete rating")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "you have not rated this food")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "rating deleted"}))
+}
+
+// FavoritesLookahead is how many days ahead, from today, favorites are looked up in the
+// menus; the rotation repeats every 28 days, so a slot is found unless closures skip it
+const FavoritesLookahead = 90
+
+// ListFavorites returns the foods the token's user marked as favorites
+// GET /schedule/favorites?lang=
+func (h *Handler) ListFavorites(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.Render(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	foods, err := h.repo.ListFavorites(c.Request.Context(), user.ID)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list favorites")))
+		return
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"favorites": localizedFoods(foods, lang)}))
+}
+
+// GetNextFavorites returns the token's user's favorite foods with the next date and meal
+// each is served at, soonest first
+// GET /schedule/favorites/next?lang=
+func (h *Handler) GetNextFavorites(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.Render(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	foods, err := h.repo.ListFavorites(c.Request.Context(), user.ID)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list favorites")))
+		return
+	}
+	ids := make([]int, len(foods))
+	for i, food := range foods {
+		ids[i] = food.ID
+	}
+	servings, err := h.repo.NextServings(c.Request.Context(), ids, campustime.Today(), FavoritesLookahead)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+
+	favorites := make([]Favorite, len(foods))
+	for i, food := range foods {
+		favorites[i] = Favorite{Food: food.Localized(lang)}
+		if serving, ok := servings[food.ID]; ok {
+			favorites[i].Next = &serving
+		}
+	}
+	// Soonest first, lunch before dinner, and those not served at the end
+	slices.SortStableFunc(favorites, func(a, b Favorite) int {
+		switch {
+		case a.Next == nil && b.Next == nil:
+			return 0
+		case a.Next == nil:
+			return 1
+		case b.Next == nil:
+			return -1
+		case a.Next.Date != b.Next.Date:
+			return strings.Compare(a.Next.Date, b.Next.Date)
+		}
+		// "lunch" sorts after "dinner" as text
+		return strings.Compare(b.Next.MealType, a.Next.MealType)
+	})
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"favorites": favorites, "lookahead_days": FavoritesLookahead}))
+}
+
+// PutFavorite marks a food as a favorite of the token's user
+// PUT /schedule/foods/:id/favorite
+func (h *Handler) PutFavorite(c *gin.Context) {
+	user, id, ok := reviewTarget(c)
+	if !ok {
+		return
+	}
+	added, err := h.repo.AddFavorite(c.Request.Context(), user.ID, id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to add favorite")))
+		return
+	}
+	if !added {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "food not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "favorite added"}))
+}
+
+// DeleteFavorite unmarks a favorite food of the token's user
+// DELETE /schedule/foods/:id/favorite
+func (h *Handler) DeleteFavorite(c *gin.Context) {
+	user, id, ok := reviewTarget(c)
+	if !ok {
+		return
+	}
+	removed, err := h.repo.RemoveFavorite(c.Request.Context(), user.ID, id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to remove favorite")))
+		return
+	}
+	if !removed {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "food is not a favorite")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "favorite removed"}))
+}
+
+// reviewTarget returns the calling user and the food ID of a rating request. It renders an
+// error and returns false when either is missing.
+func reviewTarget(c *gin.Context) (*auth.User, int64, bool) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return nil, 0, false
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid food ID")))
+		return nil, 0, false
+	}
+	return user, id, true
+}
+
+// ModerateReview hides a review from listings and averages, or shows it again
+// PATCH /admin/reviews/:id
+func (h *Handler) ModerateReview(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid review ID")))
+		return
+	}
+	var body ReviewModeration
+	if err := c.ShouldBindJSON(&body); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	found, err := h.repo.ModerateReview(c.Request.Context(), id, *body.Hidden)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to moderate review")))
+		return
+	}
+	if !found {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "review not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "review updated"}))
+}
+
+// DeleteReview deletes a review
+// DELETE /admin/reviews/:id
+func (h *Handler) DeleteReview(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid review ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteReview(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete review")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "review not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "review deleted"}))
+}
+
+// changesDays is how far ahead GetChanges looks: one rotation, so every slot of a version
+// falls on exactly one of the dates
+const changesDays = 28
+
+// GetChanges lists the dishes added to and removed from the menus of the coming four weeks
+// by edits made after ?since=, so clients can tell users what changed
+// GET /schedule/changes?since=2026-10-01T08:00:00Z&lang=
+func (h *Handler) GetChanges(c *gin.Context) {
+	value := c.Query("since")
+	if value == "" {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("since", "since is required")))
+		return
+	}
+	since, err := parseSince(value)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("since", "since must be an RFC 3339 time or a YYYY-MM-DD date")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+
+	first := campustime.Today()
+	changes, err := h.repo.ListChanges(c.Request.Context(), since, first, first.AddDate(0, 0, changesDays-1))
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list changes")))
+		return
+	}
+	for i := range changes {
+		changes[i] = changes[i].Localized(lang)
+	}
+	if changes == nil {
+		changes = []MenuChange{}
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"since":   since.UTC(),
+		"changes": changes,
+	}))
+}
+
+// GetPersonalized returns a day's menu checked against the calling user's dietary
+// preferences (see PUT /auth/me/preferences), today's unless ?date= is given. With
+// ?suitable_only=true the foods that do not suit them are left out.
+// GET /schedule/personalized?date=DDMMYYYY&suitable_only=&lang=
+func (h *Handler) GetPersonalized(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.Render(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+	if h.preferences == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "personalized menus are not enabled")))
+		return
+	}
+	date := campustime.Today()
+	if v := c.Query("date"); v != "" {
+		parsed, err := time.Parse("02012006", v)
+		if err != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "Invalid date format. Please use DDMMYYYY")))
+			return
+		}
+		date = parsed
+	}
+	suitableOnly, err := strconv.ParseBool(c.DefaultQuery("suitable_only", "false"))
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("suitable_only", "suitable_only must be true or false")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.preferences.GetPreferences(c.Request.Context(), user.ID)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get preferences")))
+		return
+	}
+	day := PersonalizedDay{Date: date.Format("2006-01-02"), Preferences: prefs}
+	schedule, err := h.repo.GetDateSchedule(c.Request.Context(), day.Date)
+	if errors.Is(err, sql.ErrNoRows) {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "We do not have a schedule for the requested date")))
+		return
+	}
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, err.Error())))
+		return
+	}
+
+	localized := schedule.Localized(lang)
+	day.Lunch = personalize(localized.Lunch, prefs, suitableOnly)
+	day.Dinner = personalize(localized.Dinner, prefs, suitableOnly)
+	day.Closure = localized.Closure
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(day))
 }
 
 //   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
@@ -0,0 +1,211 @@
+package schedule
+
+import (
+	"API/internal/storage"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxImageBytes is the largest image upload accepted
+	MaxImageBytes = 10 << 20
+
+	// maxImagePixels bounds the decoded size, so a small file that decodes
+	// to a huge bitmap is refused before it is decoded
+	maxImagePixels = 40_000_000
+
+	imageQuality = 85
+)
+
+// ErrUnsupportedImage is returned for uploads that are not a JPEG, PNG, GIF or WebP image
+var ErrUnsupportedImage = fmt.Errorf("%w: image must be a JPEG, PNG, GIF or WebP file", storage.ErrUnsupportedType)
+
+// imagePolicy is what food image uploads may be
+var imagePolicy = storage.Policy{MaxBytes: MaxImageBytes, Types: storage.ImageTypes}
+
+// imageVariant is a resized copy of every uploaded image
+type imageVariant struct {
+	name string
+	size int // longest side in pixels; smaller images are not upscaled
+}
+
+var imageVariants = []imageVariant{
+	{"thumb", 160},
+	{"medium", 480},
+	{"large", 1200},
+}
+
+// variantKey matches the keys served at /images, e.g. foods/12/3f9a...-thumb.jpg
+var variantKey = regexp.MustCompile(`^foods/\d+/[0-9a-f]{16}-(thumb|medium|large)\.jpg$`)
+
+// FoodImage holds the URLs of a food image's variants
+type FoodImage struct {
+	Thumb  string `json:"thumb"`
+	Medium string `json:"medium"`
+	Large  string `json:"large"`
+}
+
+// ImageStore keeps the resized variants of uploaded images in a storage
+type ImageStore struct {
+	storage storage.Storage
+	prefix  string // e.g. "duth/", so tenants can share a bucket
+	baseURL string // where clients load variants from; keys are appended
+
+	// signedFor is how long the signed URLs the API redirects to are valid; zero when
+	// the API serves the images itself
+	signedFor time.Duration
+}
+
+// NewImageStore creates a store writing under prefix. Variant URLs are
+// baseURL followed by the key, prefix included when external is set (e.g. a
+// CDN in front of the bucket) and left out when the API serves the images.
+func NewImageStore(store storage.Storage, prefix, baseURL string, external bool) *ImageStore {
+	s := &ImageStore{storage: store, prefix: prefix, baseURL: strings.TrimSuffix(baseURL, "/")}
+	if external {
+		s.baseURL += "/" + strings.TrimSuffix(prefix, "/")
+	}
+	return s
+}
+
+// SignURLs has the API redirect image requests to signed URLs valid for expires, so that
+// clients download straight from a private bucket. It returns false when the storage
+// cannot sign URLs.
+func (s *ImageStore) SignURLs(expires time.Duration) bool {
+	if _, ok := s.storage.(storage.URLSigner); !ok {
+		return false
+	}
+	s.signedFor = expires
+	return true
+}
+
+// SignedFor returns how long signed URLs are valid, zero when they are not used
+func (s *ImageStore) SignedFor() time.Duration {
+	return s.signedFor
+}
+
+// Save resizes an uploaded image of food into every variant and stores them.
+// It returns the key stored with the food, or an error wrapping
+// storage.ErrTooLarge or storage.ErrUnsupportedType for uploads it refuses.
+func (s *ImageStore) Save(ctx context.Context, foodID int64, upload io.Reader) (string, error) {
+	data, _, err := imagePolicy.Read(upload)
+	if err != nil {
+		return "", err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width*config.Height > maxImagePixels {
+		return "", ErrUnsupportedImage
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupportedImage
+	}
+
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	key := "foods/" + strconv.FormatInt(foodID, 10) + "/" + hex.EncodeToString(random)
+	for _, v := range imageVariants {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resize(src, v.size), &jpeg.Options{Quality: imageQuality}); err != nil {
+			return "", err
+		}
+		if err := s.storage.Put(ctx, s.prefix+variantName(key, v.name), bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil {
+			_ = s.Delete(ctx, key)
+			return "", fmt.Errorf("failed to store %s image: %w", v.name, err)
+		}
+	}
+	return key, nil
+}
+
+// Open returns a stored variant by the key clients request, which must be one Save wrote
+func (s *ImageStore) Open(ctx context.Context, variant string) (io.ReadCloser, error) {
+	if !variantKey.MatchString(variant) {
+		return nil, fs.ErrNotExist
+	}
+	return s.storage.Get(ctx, s.prefix+variant)
+}
+
+// SignedURL returns a signed URL of a stored variant, or "" when the API serves the
+// images itself
+func (s *ImageStore) SignedURL(variant string) (string, error) {
+	if !variantKey.MatchString(variant) {
+		return "", fs.ErrNotExist
+	}
+	if s.signedFor == 0 {
+		return "", nil
+	}
+	return s.storage.(storage.URLSigner).SignedURL(s.prefix+variant, s.signedFor)
+}
+
+// Delete removes every variant of an image; missing ones are skipped
+func (s *ImageStore) Delete(ctx context.Context, key string) error {
+	var errs []error
+	for _, v := range imageVariants {
+		if err := s.storage.Delete(ctx, s.prefix+variantName(key, v.name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// URLs returns the variant URLs of an image
+func (s *ImageStore) URLs(key string) *FoodImage {
+	return &FoodImage{
+		Thumb:  s.baseURL + "/" + variantName(key, "thumb"),
+		Medium: s.baseURL + "/" + variantName(key, "medium"),
+		Large:  s.baseURL + "/" + variantName(key, "large"),
+	}
+}
+
+func variantName(key, variant string) string {
+	return key + "-" + variant + ".jpg"
+}
+
+// resize scales src so its longest side is at most size, onto a white
+// background since JPEG has no transparency
+func resize(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if longest := max(width, height); longest > size {
+		width = max(1, width*size/longest)
+		height = max(1, height*size/longest)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	return dst
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
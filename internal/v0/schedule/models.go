@@ -1,41 +1,474 @@
 package schedule
 
+import (
+	"API/internal/auth"
+	"slices"
+	"time"
+)
+
 type Food struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID        int        `json:"id"`
+	Name      string     `json:"name" binding:"required,max=200"`
+	NameEn    string     `json:"name_en,omitempty" binding:"max=200"`
+	Nutrition *Nutrition `json:"nutrition,omitempty"`
+	Allergens []string   `json:"allergens" binding:"dive,oneof=gluten crustaceans eggs fish peanuts soybeans milk nuts celery mustard sesame sulphites lupin molluscs"`
+	Tags      []string   `json:"tags" binding:"dive,oneof=vegan vegetarian gluten-free fasting"`
+	Image     *FoodImage `json:"image,omitempty"`
+	Rating    *Rating    `json:"rating,omitempty"`
+
+	imageKey string // set from image_key; Image is built from it
+}
+
+// Nutrition is per serving; unknown values are null
+type Nutrition struct {
+	Calories      *float64 `json:"calories" binding:"omitempty,min=0"`      // kcal
+	Protein       *float64 `json:"protein" binding:"omitempty,min=0"`       // grams
+	Carbohydrates *float64 `json:"carbohydrates" binding:"omitempty,min=0"` // grams
+	Fat           *float64 `json:"fat" binding:"omitempty,min=0"`           // grams
+}
+
+// Languages menus and announcements are served in, the original Greek first
+const (
+	LanguageGreek   = "el"
+	LanguageEnglish = "en"
+)
+
+var Languages = []string{LanguageGreek, LanguageEnglish}
+
+// Localized returns the food in lang, falling back to the Greek original, with the
+// translation fields left out
+func (f Food) Localized(lang string) Food {
+	if lang == LanguageEnglish && f.NameEn != "" {
+		f.Name = f.NameEn
+	}
+	f.NameEn = ""
+	return f
+}
+
+// FoodAllergens are the 14 allergens the EU requires caterers to declare
+var FoodAllergens = []string{"gluten", "crustaceans", "eggs", "fish", "peanuts", "soybeans", "milk", "nuts", "celery", "mustard", "sesame", "sulphites", "lupin", "molluscs"}
+
+// FoodTags are the dietary tags menus can be filtered by with ?diet=
+var FoodTags = []string{"vegan", "vegetarian", "gluten-free", "fasting"}
+
+// FoodUpdate changes the fields that are set. Nutrition, Allergens and Tags replace the
+// whole previous value; an empty NameEn removes the translation.
+type FoodUpdate struct {
+	Name      *string    `json:"name" binding:"omitempty,max=200"`
+	NameEn    *string    `json:"name_en" binding:"omitempty,max=200"`
+	Nutrition *Nutrition `json:"nutrition"`
+	Allergens *[]string  `json:"allergens" binding:"omitempty,dive,oneof=gluten crustaceans eggs fish peanuts soybeans milk nuts celery mustard sesame sulphites lupin molluscs"`
+	Tags      *[]string  `json:"tags" binding:"omitempty,dive,oneof=vegan vegetarian gluten-free fasting"`
 }
 
 type ScheduleVersion struct {
 	ID           int    `json:"id"`
-	StartingDate string `json:"starting_date"`
-	EndingDate   string `json:"ending_date"`
+	StartingDate string `json:"starting_date" binding:"required,datetime=2006-01-02"`
+	EndingDate   string `json:"ending_date" binding:"omitempty,datetime=2006-01-02"`
 	IsCurrent    bool   `json:"is_current"`
+
+	// UpdatedAt is when the version or its items last changed; ignored on create
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
 }
 
 type ScheduleItem struct {
 	ID         int    `json:"id"`
-	VersionID  int    `json:"version_id"`
-	WeekNumber int    `json:"week_number"`
-	DayNumber  int    `json:"day_number"`
-	MealType   string `json:"meal_type"`
-	DishIDs    []int  `json:"dish_ids"`
+	VersionID  int    `json:"version_id" binding:"required,min=1"`
+	WeekNumber int    `json:"week_number" binding:"required,min=1,max=4"`
+	DayNumber  int    `json:"day_number" binding:"required,min=1,max=7"`
+	MealType   string `json:"meal_type" binding:"required,oneof=lunch dinner"`
+	DishIDs    []int  `json:"dish_ids" binding:"required,min=1"`
 }
+
+// ScheduleItemUpdate changes the fields that are set. DishIDs, when set, replaces the whole
+// dish list.
+type ScheduleItemUpdate struct {
+	WeekNumber *int    `json:"week_number" binding:"omitempty,min=1,max=4"`
+	DayNumber  *int    `json:"day_number" binding:"omitempty,min=1,max=7"`
+	MealType   *string `json:"meal_type"`
+	DishIDs    []int   `json:"dish_ids" binding:"omitempty,min=1"`
+}
+
+// ScheduleImport is a complete menu (e.g. one parsed from an emailed file) imported as a new version
+type ScheduleImport struct {
+	StartingDate string               `json:"starting_date" binding:"required,datetime=2006-01-02"`
+	EndingDate   string               `json:"ending_date" binding:"omitempty,datetime=2006-01-02"`
+	IsCurrent    bool                 `json:"is_current"`
+	Items        []ScheduleImportItem `json:"items" binding:"required,min=1,dive"`
+}
+
+type ScheduleImportItem struct {
+	WeekNumber int    `json:"week_number" binding:"min=1,max=4"`
+	DayNumber  int    `json:"day_number" binding:"min=1,max=7"`
+	MealType   string `json:"meal_type" binding:"required"`
+	DishIDs    []int  `json:"dish_ids" binding:"required,min=1"`
+}
+
+// ImportResult references the version an import resolved to. Duplicate is set when the
+// items matched an already imported version and nothing was created.
+type ImportResult struct {
+	VersionID   int64  `json:"version_id"`
+	Duplicate   bool   `json:"duplicate"`
+	ContentHash string `json:"content_hash"`
+}
+
 type Announcement struct {
 	ID           int    `json:"id"`
-	Type         string `json:"type"`
-	Content      string `json:"content"`
-	StartingDate string `json:"starting_date"`
-	EndingDate   string `json:"ending_date"`
+	Type         string `json:"type" binding:"required,oneof=info menu_change holiday emergency"`
+	Content      string `json:"content" binding:"required"`
+	ContentEn    string `json:"content_en,omitempty"`
+	StartingDate string `json:"starting_date" binding:"required,datetime=2006-01-02"`
+	EndingDate   string `json:"ending_date" binding:"omitempty,datetime=2006-01-02"`
 	IsCurrent    bool   `json:"is_current"`
+
+	// StartsAt and EndsAt are HH:MM times of day. An announcement runs from StartsAt on
+	// its starting date to EndsAt on its ending date, or, when RepeatOn lists weekdays,
+	// between those times on each of them, e.g. a "no dinner" notice every Sunday.
+	StartsAt string   `json:"starts_at,omitempty" binding:"omitempty,datetime=15:04"`
+	EndsAt   string   `json:"ends_at,omitempty" binding:"omitempty,datetime=15:04"`
+	RepeatOn []string `json:"repeat_on,omitempty" binding:"omitempty,dive,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+}
+
+// Localized returns the announcement in lang, falling back to the Greek original, with
+// the translation fields left out
+func (a Announcement) Localized(lang string) Announcement {
+	if lang == LanguageEnglish && a.ContentEn != "" {
+		a.Content = a.ContentEn
+	}
+	a.ContentEn = ""
+	return a
+}
+
+// AnnouncementUpdate changes the fields that are set. An empty EndingDate makes the
+// announcement open-ended, an empty ContentEn removes the translation, empty times
+// remove them and an empty RepeatOn makes it a one-off. is_current follows the dates
+// and times and cannot be set.
+type AnnouncementUpdate struct {
+	Type         *string   `json:"type" binding:"omitempty,oneof=info menu_change holiday emergency"`
+	Content      *string   `json:"content" binding:"omitempty,min=1"`
+	ContentEn    *string   `json:"content_en"`
+	StartingDate *string   `json:"starting_date" binding:"omitempty,datetime=2006-01-02"`
+	EndingDate   *string   `json:"ending_date"`
+	StartsAt     *string   `json:"starts_at"`
+	EndsAt       *string   `json:"ends_at"`
+	RepeatOn     *[]string `json:"repeat_on"`
+}
+
+// AnnouncementTypes are the types the announcements table accepts
+var AnnouncementTypes = []string{"info", "menu_change", "holiday", "emergency"}
+
+// AnnouncementFilter narrows the announcements list. ActiveOn is a YYYY-MM-DD date the
+// announcements must run on; empty means any date.
+type AnnouncementFilter struct {
+	Type     string
+	ActiveOn string
+}
+
+// AnnouncementReceipt marks announcements as delivered to or seen by the calling user.
+// DeviceID is optional and lets each of a user's devices keep its own state.
+type AnnouncementReceipt struct {
+	AnnouncementIDs []int  `json:"announcement_ids" binding:"required,min=1"`
+	DeviceID        string `json:"device_id" binding:"max=128"`
+	Status          string `json:"status" binding:"required,oneof=delivered seen"`
+}
+
+type UnseenAnnouncements struct {
+	Count         int            `json:"count"`
+	Announcements []Announcement `json:"announcements"`
 }
 
 type DateSchedule struct {
 	Lunch  []Food `json:"lunch"`
 	Dinner []Food `json:"dinner"`
+
+	// Closure is set when one covers the date; Lunch and Dinner then hold its
+	// replacement menu, empty when the cafeteria is closed
+	Closure *Closure `json:"closure,omitempty"`
+
+	// Hours are when the restaurants serve the meals on the date; none when closed
+	Hours []MealHours `json:"hours,omitempty"`
+}
+
+// WithTag returns the menu with only the foods tagged tag
+func (d DateSchedule) WithTag(tag string) DateSchedule {
+	return DateSchedule{Lunch: foodsWithTag(d.Lunch, tag), Dinner: foodsWithTag(d.Dinner, tag), Closure: d.Closure, Hours: d.Hours}
+}
+
+// Localized returns the menu in lang
+func (d DateSchedule) Localized(lang string) DateSchedule {
+	localized := DateSchedule{Lunch: localizedFoods(d.Lunch, lang), Dinner: localizedFoods(d.Dinner, lang)}
+	if d.Closure != nil {
+		closure := d.Closure.Localized(lang)
+		localized.Closure = &closure
+	}
+	for _, h := range d.Hours {
+		localized.Hours = append(localized.Hours, h.Localized(lang))
+	}
+	return localized
+}
+
+func localizedFoods(foods []Food, lang string) []Food {
+	localized := make([]Food, len(foods))
+	for i, f := range foods {
+		localized[i] = f.Localized(lang)
+	}
+	return localized
+}
+
+func foodsWithTag(foods []Food, tag string) []Food {
+	filtered := []Food{}
+	for _, f := range foods {
+		if slices.Contains(f.Tags, tag) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// Closure is a date or range of dates the rotation does not apply to, e.g. a public
+// holiday. The cafeteria is closed unless a replacement menu is set.
+type Closure struct {
+	ID           int    `json:"id"`
+	StartingDate string `json:"starting_date" binding:"required,datetime=2006-01-02"`
+	EndingDate   string `json:"ending_date" binding:"omitempty,datetime=2006-01-02"` // starting_date when empty
+	Reason       string `json:"reason" binding:"max=500"`
+	ReasonEn     string `json:"reason_en,omitempty" binding:"max=500"`
+
+	// The replacement menu as food IDs; none means closed
+	LunchDishIDs  []int `json:"lunch_dish_ids,omitempty"`
+	DinnerDishIDs []int `json:"dinner_dish_ids,omitempty"`
+}
+
+// Localized returns the closure in lang, falling back to the Greek original, with the
+// translation fields left out
+func (c Closure) Localized(lang string) Closure {
+	if lang == LanguageEnglish && c.ReasonEn != "" {
+		c.Reason = c.ReasonEn
+	}
+	c.ReasonEn = ""
+	return c
+}
+
+// Serving is a date and meal a food is served at
+type Serving struct {
+	Date     string `json:"date"`
+	MealType string `json:"meal_type"`
+}
+
+// Favorite is a user's favorite food with when it is served next, nil when it is not
+// within the look-ahead
+type Favorite struct {
+	Food Food     `json:"food"`
+	Next *Serving `json:"next"`
+}
+
+// Kinds of MenuChannel
+const (
+	ChannelDiscord  = "discord"
+	ChannelTelegram = "telegram"
+)
+
+// MenuChannel is a chat channel the day's menu and announcements are posted to every
+// day. BotToken is never returned.
+type MenuChannel struct {
+	ID           int64     `json:"id"`
+	Kind         string    `json:"kind" binding:"required,oneof=discord telegram"`
+	Target       string    `json:"target" binding:"required,max=2000"` // Discord webhook URL, or Telegram chat ID or @channel
+	BotToken     string    `json:"bot_token,omitempty" binding:"max=200"`
+	Language     string    `json:"language" binding:"omitempty,oneof=el en"`
+	PostAt       string    `json:"post_at" binding:"omitempty,datetime=15:04"`
+	Template     string    `json:"template" binding:"max=4000"` // text/template; empty uses the default
+	Active       bool      `json:"active"`
+	LastPostedOn string    `json:"last_posted_on,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Redacted returns the channel without its bot token
+func (ch MenuChannel) Redacted() MenuChannel {
+	ch.BotToken = ""
+	return ch
+}
+
+// MenuChannelUpdate changes the fields that are set. An empty Template restores the
+// default one and an empty BotToken keeps the current one.
+type MenuChannelUpdate struct {
+	Target   *string `json:"target" binding:"omitempty,min=1,max=2000"`
+	BotToken *string `json:"bot_token" binding:"omitempty,max=200"`
+	Language *string `json:"language" binding:"omitempty,oneof=el en"`
+	PostAt   *string `json:"post_at" binding:"omitempty,datetime=15:04"`
+	Template *string `json:"template" binding:"omitempty,max=4000"`
+	Active   *bool   `json:"active"`
+}
+
+// Restaurant is a place the cafeteria serves in, with its serving hours
+type Restaurant struct {
+	ID     int64          `json:"id"`
+	Name   string         `json:"name" binding:"required,max=200"`
+	NameEn string         `json:"name_en,omitempty" binding:"max=200"`
+	Hours  []ServiceHours `json:"hours" binding:"dive"`
+}
+
+// ServiceHours is when a restaurant serves a meal on weekdays or at weekends, as HH:MM
+// campus time
+type ServiceHours struct {
+	MealType string `json:"meal_type" binding:"required,oneof=lunch dinner"`
+	Days     string `json:"days" binding:"required,oneof=weekdays weekends"`
+	Opens    string `json:"opens" binding:"required,datetime=15:04"`
+	Closes   string `json:"closes" binding:"required,datetime=15:04"`
+}
+
+// Localized returns the restaurant in lang, falling back to the Greek original, with the
+// translation fields left out
+func (r Restaurant) Localized(lang string) Restaurant {
+	if lang == LanguageEnglish && r.NameEn != "" {
+		r.Name = r.NameEn
+	}
+	r.NameEn = ""
+	return r
+}
+
+// MealHours is when a restaurant serves a meal on a particular date
+type MealHours struct {
+	RestaurantID int64  `json:"restaurant_id"`
+	Restaurant   string `json:"restaurant"`
+	RestaurantEn string `json:"restaurant_en,omitempty"`
+	MealType     string `json:"meal_type"`
+	Opens        string `json:"opens"`
+	Closes       string `json:"closes"`
+}
+
+// Localized returns the hours in lang, falling back to the Greek original, with the
+// translation fields left out
+func (h MealHours) Localized(lang string) MealHours {
+	if lang == LanguageEnglish && h.RestaurantEn != "" {
+		h.Restaurant = h.RestaurantEn
+	}
+	h.RestaurantEn = ""
+	return h
+}
+
+// serviceDays are the days of ServiceHours that date falls on
+func serviceDays(date time.Time) string {
+	if weekday := date.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		return "weekends"
+	}
+	return "weekdays"
+}
+
+// DaySchedule is the menu of one calendar date in a range
+type DaySchedule struct {
+	Date string `json:"date"`
+	DateSchedule
 }
 
 type SemesterSchedule map[int]map[int]DateSchedule
 
+// WithTag returns the menus with only the foods tagged tag
+func (s SemesterSchedule) WithTag(tag string) SemesterSchedule {
+	return s.mapMeals(func(meals DateSchedule) DateSchedule { return meals.WithTag(tag) })
+}
+
+// Localized returns the menus in lang
+func (s SemesterSchedule) Localized(lang string) SemesterSchedule {
+	return s.mapMeals(func(meals DateSchedule) DateSchedule { return meals.Localized(lang) })
+}
+
+func (s SemesterSchedule) mapMeals(fn func(DateSchedule) DateSchedule) SemesterSchedule {
+	mapped := make(SemesterSchedule, len(s))
+	for week, days := range s {
+		mapped[week] = make(map[int]DateSchedule, len(days))
+		for day, meals := range days {
+			mapped[week][day] = fn(meals)
+		}
+	}
+	return mapped
+}
+
+// Rating is the average of a food's ratings; foods nobody rated have none
+type Rating struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// Review is a user's rating of a food, with an optional short text. UserID and Hidden are
+// only shown to admins.
+type Review struct {
+	ID        int64     `json:"id"`
+	FoodID    int       `json:"food_id"`
+	UserID    int64     `json:"user_id,omitempty"`
+	Rating    int       `json:"rating" binding:"required,min=1,max=5"`
+	Review    string    `json:"review" binding:"max=500"`
+	Hidden    bool      `json:"hidden,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReviewModeration hides or shows a review
+type ReviewModeration struct {
+	Hidden *bool `json:"hidden" binding:"required"`
+}
+
+// MenuChange lists the dishes edits added to and removed from a meal of a date.
+// ChangedAt is when the latest of those edits was made.
+type MenuChange struct {
+	Date      string    `json:"date"`
+	MealType  string    `json:"meal_type"`
+	Added     []Food    `json:"added"`
+	Removed   []Food    `json:"removed"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Localized returns the change with its foods in lang
+func (c MenuChange) Localized(lang string) MenuChange {
+	c.Added = localizedFoods(c.Added, lang)
+	c.Removed = localizedFoods(c.Removed, lang)
+	return c
+}
+
+// PersonalizedFood is a food checked against a user's dietary preferences. Suitable is set
+// when it has none of the allergens they avoid and fits every diet they follow.
+type PersonalizedFood struct {
+	Food
+	Suitable         bool     `json:"suitable"`
+	AvoidedAllergens []string `json:"avoided_allergens,omitempty"`
+	UnmetDiets       []string `json:"unmet_diets,omitempty"`
+}
+
+// PersonalizedDay is a date's menu checked against the preferences it was personalized for
+type PersonalizedDay struct {
+	Date        string             `json:"date"`
+	Lunch       []PersonalizedFood `json:"lunch"`
+	Dinner      []PersonalizedFood `json:"dinner"`
+	Closure     *Closure           `json:"closure,omitempty"`
+	Preferences *auth.Preferences  `json:"preferences"`
+}
+
+// personalize checks foods against prefs, leaving out the unsuitable ones when suitableOnly
+// is set
+func personalize(foods []Food, prefs *auth.Preferences, suitableOnly bool) []PersonalizedFood {
+	result := []PersonalizedFood{}
+	for _, f := range foods {
+		p := PersonalizedFood{Food: f}
+		for _, allergen := range prefs.Allergens {
+			if slices.Contains(f.Allergens, allergen) {
+				p.AvoidedAllergens = append(p.AvoidedAllergens, allergen)
+			}
+		}
+		for _, diet := range prefs.Diets {
+			// Vegan food is vegetarian too, whether or not it is tagged so
+			if !slices.Contains(f.Tags, diet) && !(diet == "vegetarian" && slices.Contains(f.Tags, "vegan")) {
+				p.UnmetDiets = append(p.UnmetDiets, diet)
+			}
+		}
+		p.Suitable = len(p.AvoidedAllergens) == 0 && len(p.UnmetDiets) == 0
+		if p.Suitable || !suitableOnly {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
 //   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
 //   API Copyright (C) 2025 OpenSourceDUTH
 //       This program is free software: you can redistribute it and/or modify
@@ -0,0 +1,247 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxScanBytes is the largest menu photo or PDF accepted for recognition
+const MaxScanBytes = 20 << 20
+
+// ErrUnsupportedScan is returned for uploads that are neither an image nor a PDF
+var ErrUnsupportedScan = errors.New("file must be an image or a PDF")
+
+// OCR recognizes the text of a photographed or scanned menu
+type OCR interface {
+	Recognize(ctx context.Context, scan []byte) (string, error)
+}
+
+// CommandOCR runs an OCR program that reads the scan on stdin and writes its text to
+// stdout, e.g. "tesseract stdin stdout -l ell+eng", or a script that also converts PDFs
+type CommandOCR struct {
+	args []string
+}
+
+// NewCommandOCR creates a backend running command, split on spaces
+func NewCommandOCR(command string) *CommandOCR {
+	return &CommandOCR{args: strings.Fields(command)}
+}
+
+func (o *CommandOCR) Recognize(ctx context.Context, scan []byte) (string, error) {
+	if len(o.args) == 0 {
+		return "", errors.New("no OCR command is configured")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, o.args[0], o.args[1:]...)
+	cmd.Stdin = bytes.NewReader(scan)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %v: %s", o.args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// checkScan returns ErrUnsupportedScan unless scan looks like an image or a PDF
+func checkScan(scan []byte) error {
+	contentType := http.DetectContentType(scan)
+	if strings.HasPrefix(contentType, "image/") || contentType == "application/pdf" {
+		return nil
+	}
+	return ErrUnsupportedScan
+}
+
+// MenuDraft is a menu recognized from a scan, for an admin to correct and confirm with
+// POST /admin/schedule/draft. Lines that could not be placed in a meal slot are listed
+// in Unassigned.
+type MenuDraft struct {
+	StartingDate string            `json:"starting_date" binding:"required,datetime=2006-01-02"`
+	EndingDate   string            `json:"ending_date" binding:"omitempty,datetime=2006-01-02"`
+	IsCurrent    bool              `json:"is_current"`
+	Items        []SpreadsheetItem `json:"items" binding:"required,min=1,dive"`
+	NamesEn      map[string]string `json:"names_en,omitempty"` // English names, keyed by the Greek ones
+	Unassigned   []string          `json:"unassigned,omitempty"`
+	Text         string            `json:"text,omitempty"` // what the OCR recognized
+}
+
+// menu returns the draft as a parsed spreadsheet, merging items an admin split into
+// several entries for the same meal slot
+func (d MenuDraft) menu() SpreadsheetMenu {
+	menu := SpreadsheetMenu{NamesEn: make(map[string]string)}
+	type slot struct {
+		week, day int
+		meal      string
+	}
+	index := make(map[slot]int)
+	for _, draft := range d.Items {
+		key := slot{draft.WeekNumber, draft.DayNumber, draft.MealType}
+		if _, ok := index[key]; !ok {
+			index[key] = len(menu.Items)
+			menu.Items = append(menu.Items, SpreadsheetItem{WeekNumber: key.week, DayNumber: key.day, MealType: key.meal})
+		}
+		item := &menu.Items[index[key]]
+		for _, name := range draft.Foods {
+			if name = strings.TrimSpace(name); name != "" && !containsFold(item.Foods, name) {
+				item.Foods = append(item.Foods, name)
+			}
+		}
+	}
+	for name, nameEn := range d.NamesEn {
+		if nameEn = strings.TrimSpace(nameEn); nameEn != "" {
+			menu.NamesEn[strings.ToLower(strings.TrimSpace(name))] = nameEn
+		}
+	}
+	return menu
+}
+
+// weekHeading matches week headings such as "Εβδομάδα 2", "2η εβδομάδα" or "Week 2"
+var weekHeading = regexp.MustCompile(`(?i)^(?:(?:εβδομαδα|week)\s*(\d)|(\d)\s*(?:η|st|nd|rd|th)?\s*(?:εβδομαδα|week))`)
+
+// foodSeparators split the foods listed on one line
+var foodSeparators = regexp.MustCompile(`\s*[,;·•|]\s*`)
+
+// parseMenuText places the lines of a recognized menu in meal slots of a version
+// starting on start. Week headings, weekday names and meal names (see weekdays and
+// mealNames) set the slot the following foods go to, and may share a line with them,
+// e.g. "ΔΕΥΤΕΡΑ - Γεύμα: Φακές, Ψωμί". A weekday listed again without a week heading
+// in between starts the next week.
+func parseMenuText(text string, start time.Time) MenuDraft {
+	var draft MenuDraft
+	type slot struct {
+		week, day int
+		meal      string
+	}
+	index := make(map[slot]int)
+	current := slot{week: 1}
+	seenDays := make(map[int]bool)
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		for line != "" {
+			folded := foldGreek(line)
+			if m := weekHeading.FindStringSubmatch(folded); m != nil {
+				week, _ := strconv.Atoi(m[1] + m[2])
+				if week >= 1 && week <= 4 {
+					current = slot{week: week}
+					clear(seenDays)
+				}
+				line = trimMarker(line, len([]rune(m[0])))
+				continue
+			}
+			if name, weekday, ok := prefixWord(folded, foldedWeekdays); ok {
+				day := (int(weekday)-int(start.Weekday())+7)%7 + 1
+				if seenDays[day] && current.week < 4 {
+					current.week++
+					clear(seenDays)
+				}
+				seenDays[day] = true
+				current.day, current.meal = day, ""
+				line = trimMarker(line, len([]rune(name)))
+				continue
+			}
+			if name, meal, ok := prefixWord(folded, foldedMeals); ok {
+				current.meal = meal
+				line = trimMarker(line, len([]rune(name)))
+				continue
+			}
+			break
+		}
+		if line == "" {
+			continue
+		}
+
+		var foods []string
+		for _, name := range foodSeparators.Split(line, -1) {
+			if name = strings.Trim(name, " -–—*.:"); name != "" {
+				foods = append(foods, name)
+			}
+		}
+		if len(foods) == 0 {
+			continue
+		}
+		if current.day == 0 || current.meal == "" {
+			draft.Unassigned = append(draft.Unassigned, line)
+			continue
+		}
+		if _, ok := index[current]; !ok {
+			index[current] = len(draft.Items)
+			draft.Items = append(draft.Items, SpreadsheetItem{WeekNumber: current.week, DayNumber: current.day, MealType: current.meal})
+		}
+		item := &draft.Items[index[current]]
+		for _, name := range foods {
+			if !containsFold(item.Foods, name) {
+				item.Foods = append(item.Foods, name)
+			}
+		}
+	}
+	return draft
+}
+
+// foldedWeekdays and foldedMeals are weekdays and mealNames keyed by foldGreek, since
+// printed menus are often in capitals, which drop the accents
+var foldedWeekdays, foldedMeals = foldKeys(weekdays), foldKeys(mealNames)
+
+func init() {
+	foldedMeals["lunch"], foldedMeals["dinner"] = "lunch", "dinner"
+}
+
+func foldKeys[V any](m map[string]V) map[string]V {
+	folded := make(map[string]V, len(m))
+	for k, v := range m {
+		folded[foldGreek(k)] = v
+	}
+	return folded
+}
+
+// greekAccents maps accented Greek vowels to plain ones
+var greekAccents = strings.NewReplacer("ά", "α", "έ", "ε", "ή", "η", "ί", "ι", "ϊ", "ι", "ΐ", "ι", "ό", "ο", "ύ", "υ", "ϋ", "υ", "ΰ", "υ", "ώ", "ω")
+
+// foldGreek lower-cases text and strips Greek accents. Every replaced letter is one rune,
+// so rune offsets in the result match those in text.
+func foldGreek(text string) string {
+	return greekAccents.Replace(strings.ToLower(text))
+}
+
+// prefixWord returns the longest key of words text starts with as a whole word
+func prefixWord[V any](text string, words map[string]V) (string, V, bool) {
+	var best string
+	var value V
+	for word, v := range words {
+		if len(word) > len(best) && strings.HasPrefix(text, word) {
+			rest := []rune(text[len(word):])
+			if len(rest) == 0 || !unicode.IsLetter(rest[0]) {
+				best, value = word, v
+			}
+		}
+	}
+	return best, value, best != ""
+}
+
+// trimMarker drops the first n runes of line and the separators after them
+func trimMarker(line string, n int) string {
+	return strings.TrimLeft(string([]rune(line)[n:]), " \t-–—:.,;")
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,203 @@
+package schedule
+
+import (
+	"API/internal/campustime"
+	"API/internal/events"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/google"
+)
+
+// pushMaxAge is how old an event can be and still be pushed; a notification
+// about this morning's menu is noise by the evening
+const pushMaxAge = 12 * time.Hour
+
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// Notification is a push message shown on the devices subscribed to a topic.
+// Data is handed to the app, e.g. to open the announcement it names.
+type Notification struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Pusher sends notifications to the devices subscribed to a topic
+type Pusher interface {
+	Push(ctx context.Context, topic string, n Notification) error
+}
+
+// FCMPusher sends notifications through the Firebase Cloud Messaging HTTP v1 API
+type FCMPusher struct {
+	client *http.Client
+	url    string
+}
+
+// NewFCMPusher creates a pusher authenticated with a Firebase service account
+// JSON file
+func NewFCMPusher(credentialsFile string) (*FCMPusher, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+	}
+	var account struct {
+		ProjectID string `json:"project_id"`
+	}
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+	}
+	if account.ProjectID == "" {
+		return nil, fmt.Errorf("FCM credentials have no project_id")
+	}
+	config, err := google.JWTConfigFromJSON(data, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+	}
+
+	client := config.Client(context.Background())
+	client.Timeout = 10 * time.Second
+	return &FCMPusher{
+		client: client,
+		url:    "https://fcm.googleapis.com/v1/projects/" + account.ProjectID + "/messages:send",
+	}, nil
+}
+
+func (p *FCMPusher) Push(ctx context.Context, topic string, n Notification) error {
+	var message fcmRequest
+	message.Message.Topic = topic
+	message.Message.Notification.Title = n.Title
+	message.Message.Notification.Body = n.Body
+	message.Message.Data = n.Data
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("FCM responded with %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// fcmRequest is the body of a messages:send call
+type fcmRequest struct {
+	Message struct {
+		Topic        string `json:"topic"`
+		Notification struct {
+			Title string `json:"title"`
+			Body  string `json:"body"`
+		} `json:"notification"`
+		Data map[string]string `json:"data,omitempty"`
+	} `json:"message"`
+}
+
+// SubscribePush registers durable outbox subscribers that notify the apps
+// when a schedule version or an announcement is published, so they refresh
+// instead of polling. Every language has its own topic, "<topic>-el" and
+// "<topic>-en", and its own subscriber, so a failed send is retried without
+// notifying the other language twice.
+func SubscribePush(outbox *events.Outbox, repo *Repository, pusher Pusher, topic string) {
+	for _, lang := range Languages {
+		langTopic := topic + "-" + lang
+		outbox.Subscribe("push:"+langTopic, func(ctx context.Context, event events.Event) error {
+			n, ok, err := pushNotification(ctx, repo, event, lang)
+			if err != nil || !ok {
+				return err
+			}
+			return pusher.Push(ctx, langTopic, n)
+		}, events.TypeScheduleVersionPublished, events.TypeAnnouncementPublished)
+	}
+}
+
+// pushNotification builds the notification for event in lang; ok is false for
+// events that are too old to be worth pushing
+func pushNotification(ctx context.Context, repo *Repository, event events.Event, lang string) (Notification, bool, error) {
+	switch e := event.(type) {
+	case events.ScheduleVersionPublished:
+		if time.Since(e.OccurredAt) > pushMaxAge {
+			return Notification{}, false, nil
+		}
+		n := Notification{
+			Title: localizedText(lang, "Νέο μενού", "New menu"),
+			Body:  localizedText(lang, "Νέο μενού από ", "New menu from ") + e.StartingDate,
+			Data:  map[string]string{"event": WebhookEventSchedulePublished, "version_id": fmt.Sprint(e.VersionID)},
+		}
+
+		// A menu that is already being served leads with today's lunch
+		date := campustime.Today().Format("2006-01-02")
+		if e.StartingDate <= date && (e.EndingDate == "" || date <= e.EndingDate) {
+			// A replica that has not caught up yet has no version for today
+			menu, err := repo.GetDateSchedule(ctx, date)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return Notification{}, false, err
+			}
+			if menu != nil && menu.Closure == nil && len(menu.Lunch) > 0 {
+				names := make([]string, len(menu.Lunch))
+				for i, food := range localizedFoods(menu.Lunch, lang) {
+					names[i] = food.Name
+				}
+				n.Body = localizedText(lang, "Σημερινό μεσημεριανό: ", "Today's lunch: ") + strings.Join(names, ", ")
+			}
+		}
+		return n, true, nil
+
+	case events.AnnouncementPublished:
+		if time.Since(e.OccurredAt) > pushMaxAge {
+			return Notification{}, false, nil
+		}
+		body := e.Content
+		if lang == LanguageEnglish && e.ContentEn != "" {
+			body = e.ContentEn
+		}
+		return Notification{
+			Title: localizedText(lang, "Ανακοίνωση", "Announcement"),
+			Body:  body,
+			Data:  map[string]string{"event": WebhookEventAnnouncementPublished, "announcement_id": fmt.Sprint(e.AnnouncementID), "type": e.Type},
+		}, true, nil
+	}
+	return Notification{}, false, nil
+}
+
+func localizedText(lang, greek, english string) string {
+	if lang == LanguageEnglish {
+		return english
+	}
+	return greek
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
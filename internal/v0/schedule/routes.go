@@ -2,24 +2,110 @@ package schedule
 
 import (
 	"API/internal/auth"
+	"API/internal/limits"
+	"API/internal/negotiate"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// FeatureSlug is the feature tokens need for the schedule endpoints
+const FeatureSlug = "schedule"
+
+// AnnouncementsFeatureSlug is the feature tokens need to read announcements
+const AnnouncementsFeatureSlug = "announcements"
+
+// Features are the features this module serves, registered at startup
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Schedule API", Description: "Cafeteria menus and announcements"},
+	{Slug: AnnouncementsFeatureSlug, Name: "Announcements API", Description: "Cafeteria announcements, e.g. closures and menu changes"},
+}
+
+// The menu lists foods; JSON:API clients see them as "foods" resources
+func init() {
+	negotiate.RegisterResourceType("lunch", "foods")
+	negotiate.RegisterResourceType("dinner", "foods")
+}
+
 func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
 	schedule := rg.Group("/schedule")
 	{
-		schedule.GET("", authMiddleware.RequireToken("schedule"), h.GetSchedule)
+		schedule.GET("", authMiddleware.RequireToken(FeatureSlug), h.GetSchedule)
+		schedule.GET("/version", authMiddleware.RequireToken(FeatureSlug), h.GetVersion)
+		schedule.GET("/today", authMiddleware.RequireToken(FeatureSlug), h.GetToday)
+		schedule.GET("/tomorrow", authMiddleware.RequireToken(FeatureSlug), h.GetTomorrow)
+		schedule.GET("/week", authMiddleware.RequireToken(FeatureSlug), h.GetWeek)
+		schedule.GET("/closures", authMiddleware.RequireToken(FeatureSlug), h.ListUpcomingClosures)
+		schedule.GET("/hours", authMiddleware.RequireToken(FeatureSlug), h.GetHours)
+		schedule.GET("/changes", authMiddleware.RequireToken(FeatureSlug), h.GetChanges)
+		schedule.GET("/stats", authMiddleware.RequireToken(FeatureSlug), h.GetStats)
+		schedule.GET("/personalized", authMiddleware.RequireToken(FeatureSlug), h.GetPersonalized)
+		schedule.GET("/foods/:id/reviews", authMiddleware.RequireToken(FeatureSlug), h.GetFoodReviews)
+		schedule.GET("/foods/:id/rating", authMiddleware.RequireToken(FeatureSlug), h.GetMyReview)
+		schedule.PUT("/foods/:id/rating", authMiddleware.RequireToken(FeatureSlug), h.PutMyReview)
+		schedule.DELETE("/foods/:id/rating", authMiddleware.RequireToken(FeatureSlug), h.DeleteMyReview)
+		schedule.PUT("/foods/:id/favorite", authMiddleware.RequireToken(FeatureSlug), h.PutFavorite)
+		schedule.DELETE("/foods/:id/favorite", authMiddleware.RequireToken(FeatureSlug), h.DeleteFavorite)
+		schedule.GET("/favorites", authMiddleware.RequireToken(FeatureSlug), h.ListFavorites)
+		schedule.GET("/favorites/next", authMiddleware.RequireToken(FeatureSlug), h.GetNextFavorites)
+		schedule.GET("/announcements/unseen", authMiddleware.RequireToken(FeatureSlug), h.GetUnseenAnnouncements)
+		schedule.POST("/announcements/receipts", authMiddleware.RequireToken(FeatureSlug), h.PostAnnouncementReceipts)
 	}
 
+	rg.GET("/announcements", authMiddleware.RequireToken(AnnouncementsFeatureSlug), h.ListAnnouncements)
+
+	// Image URLs end up in <img> tags, which cannot send a token
+	rg.GET("/images/*key", h.GetImage)
+
 	schedule_admin := rg.Group("/admin")
 	schedule_admin.Use(authMiddleware.RequireSession())
 	schedule_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	schedule_admin.Use(authMiddleware.Idempotent())
 	{
+		schedule_admin.GET("/foods", h.ListFoods)
 		schedule_admin.POST("/foods", h.PostFood)
+		schedule_admin.GET("/foods/:id", h.GetFood)
+		schedule_admin.PATCH("/foods/:id", h.UpdateFood)
+		schedule_admin.DELETE("/foods/:id", h.DeleteFood)
+		schedule_admin.PUT("/foods/:id/image", h.PutFoodImage)
+		limits.SetRoute(http.MethodPut, schedule_admin.BasePath()+"/foods/:id/image", limits.Limits{MaxBodyBytes: MaxImageBytes + 1<<20, Timeout: time.Minute})
+		schedule_admin.DELETE("/foods/:id/image", h.DeleteFoodImage)
 		schedule_admin.POST("/versions", h.PostVersion)
 		schedule_admin.POST("/items", h.PostSchedule)
+		schedule_admin.GET("/items/:id", h.GetScheduleItem)
+		schedule_admin.PUT("/items/:id", h.ReplaceScheduleItem)
+		schedule_admin.PATCH("/items/:id", h.UpdateScheduleItem)
+		schedule_admin.DELETE("/items/:id", h.DeleteScheduleItem)
+		schedule_admin.POST("/imports", h.PostImport)
+		limits.SetRoute(http.MethodPost, schedule_admin.BasePath()+"/imports", limits.Limits{MaxBodyBytes: 10 << 20, Timeout: time.Minute})
+		schedule_admin.POST("/schedule/import", h.PostSpreadsheetImport)
+		limits.SetRoute(http.MethodPost, schedule_admin.BasePath()+"/schedule/import", limits.Limits{MaxBodyBytes: 10 << 20, Timeout: time.Minute})
+		schedule_admin.POST("/schedule/ocr", h.PostMenuScan)
+		limits.SetRoute(http.MethodPost, schedule_admin.BasePath()+"/schedule/ocr", limits.Limits{MaxBodyBytes: MaxScanBytes + 1<<20, Timeout: 2 * time.Minute})
+		schedule_admin.POST("/schedule/draft", h.PostMenuDraft)
 		schedule_admin.POST("/announcements", h.PostAnnouncement)
+		schedule_admin.PATCH("/announcements/:id", h.UpdateAnnouncement)
+		schedule_admin.DELETE("/announcements/:id", h.DeleteAnnouncement)
+		schedule_admin.GET("/closures", h.ListClosures)
+		schedule_admin.POST("/closures", h.PostClosure)
+		schedule_admin.GET("/closures/:id", h.GetClosure)
+		schedule_admin.PUT("/closures/:id", h.ReplaceClosure)
+		schedule_admin.DELETE("/closures/:id", h.DeleteClosure)
+		schedule_admin.GET("/channels", h.ListChannels)
+		schedule_admin.POST("/channels", h.PostChannel)
+		schedule_admin.GET("/channels/:id", h.GetChannel)
+		schedule_admin.PATCH("/channels/:id", h.UpdateChannel)
+		schedule_admin.DELETE("/channels/:id", h.DeleteChannel)
+		schedule_admin.POST("/channels/:id/test", h.TestChannel)
+		schedule_admin.GET("/restaurants", h.ListRestaurants)
+		schedule_admin.POST("/restaurants", h.PostRestaurant)
+		schedule_admin.GET("/restaurants/:id", h.GetRestaurant)
+		schedule_admin.PUT("/restaurants/:id", h.ReplaceRestaurant)
+		schedule_admin.DELETE("/restaurants/:id", h.DeleteRestaurant)
+		schedule_admin.GET("/reviews", h.ListReviews)
+		schedule_admin.PATCH("/reviews/:id", h.ModerateReview)
+		schedule_admin.DELETE("/reviews/:id", h.DeleteReview)
 	}
 }
 
@@ -0,0 +1,365 @@
+package schedule
+
+import (
+	"API/internal/apierror"
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxSpreadsheetRows bounds the rows of an imported spreadsheet, header included
+const MaxSpreadsheetRows = 10000
+
+// ErrUnsupportedSpreadsheet is returned for files that are neither CSV nor XLSX
+var ErrUnsupportedSpreadsheet = errors.New("file must be a UTF-8 CSV or an XLSX spreadsheet")
+
+// SpreadsheetImport is the form sent with an imported spreadsheet
+type SpreadsheetImport struct {
+	StartingDate string `form:"starting_date" binding:"required,datetime=2006-01-02"`
+	EndingDate   string `form:"ending_date" binding:"omitempty,datetime=2006-01-02"`
+	IsCurrent    bool   `form:"is_current"`
+}
+
+// SpreadsheetItem is one meal slot of an imported spreadsheet or menu draft, with its
+// foods by name
+type SpreadsheetItem struct {
+	WeekNumber int      `json:"week_number" binding:"min=1,max=4"`
+	DayNumber  int      `json:"day_number" binding:"min=1,max=7"`
+	MealType   string   `json:"meal_type" binding:"required,oneof=lunch dinner"`
+	Foods      []string `json:"foods" binding:"required,min=1,dive,required,max=200"`
+}
+
+// SpreadsheetMenu is a parsed spreadsheet. NamesEn holds the English names given in the
+// optional food_en column, keyed by the lowercased Greek name.
+type SpreadsheetMenu struct {
+	Items   []SpreadsheetItem
+	NamesEn map[string]string
+}
+
+// SpreadsheetImportResult reports what a spreadsheet import created, or would create on a
+// dry run, in which case VersionID is 0
+type SpreadsheetImportResult struct {
+	ImportResult
+	DryRun       bool     `json:"dry_run"`
+	Items        int      `json:"items"`
+	Dishes       int      `json:"dishes"`
+	CreatedFoods []string `json:"created_foods"`
+}
+
+// spreadsheetColumns are the header names of the columns a spreadsheet must have. Each
+// row is one meal slot; the food cell may list several foods separated by ";" or new lines,
+// which a food_en column may translate in the same order.
+var spreadsheetColumns = []string{"week", "day", "meal", "food"}
+
+// weekdays are the day names accepted next to rotation day numbers
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday, "thursday": time.Thursday,
+	"friday": time.Friday, "saturday": time.Saturday, "sunday": time.Sunday,
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+	"δευτέρα": time.Monday, "τρίτη": time.Tuesday, "τετάρτη": time.Wednesday, "πέμπτη": time.Thursday,
+	"παρασκευή": time.Friday, "σάββατο": time.Saturday, "κυριακή": time.Sunday,
+}
+
+// mealNames are the Greek meal names accepted next to lunch and dinner
+var mealNames = map[string]string{
+	"γεύμα": "lunch", "μεσημεριανό": "lunch",
+	"δείπνο": "dinner", "βραδινό": "dinner",
+}
+
+// readSpreadsheet returns the rows of a CSV file or of the first sheet of an XLSX workbook
+func readSpreadsheet(data []byte) ([][]string, error) {
+	var rows [][]string
+	var err error
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		rows, err = readXLSX(data)
+	} else if !utf8.Valid(data) {
+		return nil, ErrUnsupportedSpreadsheet
+	} else {
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		rows, err = reader.ReadAll()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedSpreadsheet, err)
+	}
+	if len(rows) > MaxSpreadsheetRows {
+		return nil, fmt.Errorf("spreadsheet has more than %d rows", MaxSpreadsheetRows)
+	}
+	return rows, nil
+}
+
+// parseSpreadsheet turns spreadsheet rows into meal slots of a version starting on start.
+// Rotation days count from the starting date, so weekday names are mapped relative to it.
+// Errors name the row as it is numbered in the spreadsheet, e.g. rows[3].day.
+func parseSpreadsheet(rows [][]string, start time.Time) (SpreadsheetMenu, []apierror.Error) {
+	menu := SpreadsheetMenu{NamesEn: make(map[string]string)}
+	if len(rows) == 0 {
+		return menu, []apierror.Error{apierror.Invalid("file", "the spreadsheet is empty")}
+	}
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	var errs []apierror.Error
+	for _, name := range spreadsheetColumns {
+		if _, ok := columns[name]; !ok {
+			errs = append(errs, apierror.Invalid("file", "the header row has no "+name+" column"))
+		}
+	}
+	if len(errs) > 0 {
+		return menu, errs
+	}
+	_, translated := columns["food_en"]
+
+	type slot struct {
+		week, day int
+		meal      string
+	}
+	index := make(map[slot]int)
+	for i, row := range rows[1:] {
+		cell := func(name string) string {
+			if c := columns[name]; c < len(row) {
+				return strings.TrimSpace(row[c])
+			}
+			return ""
+		}
+		field := func(name string) string {
+			return fmt.Sprintf("rows[%d].%s", i+2, name)
+		}
+		if strings.Join(row, "") == "" {
+			continue
+		}
+
+		week, err := strconv.Atoi(cell("week"))
+		if err != nil || week < 1 || week > 4 {
+			errs = append(errs, apierror.Invalid(field("week"), "week must be 1 to 4"))
+		}
+		var day int
+		if weekday, ok := weekdays[strings.ToLower(cell("day"))]; ok {
+			day = (int(weekday)-int(start.Weekday())+7)%7 + 1
+		} else {
+			day, err = strconv.Atoi(cell("day"))
+			if err != nil || day < 1 || day > 7 {
+				errs = append(errs, apierror.Invalid(field("day"), "day must be 1 to 7 or a weekday name"))
+			}
+		}
+		meal, ok := mealNames[strings.ToLower(cell("meal"))]
+		if !ok {
+			if meal, ok = normalizeMealType(cell("meal")); !ok {
+				errs = append(errs, apierror.Invalid(field("meal"), "meal must be lunch or dinner"))
+			}
+		}
+		foods := splitFoods(cell("food"))
+		if len(foods) == 0 {
+			errs = append(errs, apierror.Invalid(field("food"), "food is required"))
+		}
+		var foodsEn []string
+		if translated {
+			foodsEn = splitFoods(cell("food_en"))
+			if len(foodsEn) > 0 && len(foodsEn) != len(foods) {
+				errs = append(errs, apierror.Invalid(field("food_en"), "food_en must list as many foods as food"))
+			}
+		}
+		if len(errs) > 0 {
+			continue
+		}
+
+		key := slot{week, day, meal}
+		if _, ok := index[key]; !ok {
+			index[key] = len(menu.Items)
+			menu.Items = append(menu.Items, SpreadsheetItem{WeekNumber: week, DayNumber: day, MealType: meal})
+		}
+		item := &menu.Items[index[key]]
+		for j, name := range foods {
+			if !containsFold(item.Foods, name) {
+				item.Foods = append(item.Foods, name)
+			}
+			if j < len(foodsEn) {
+				menu.NamesEn[strings.ToLower(name)] = foodsEn[j]
+			}
+		}
+	}
+	if len(errs) == 0 && len(menu.Items) == 0 {
+		errs = append(errs, apierror.Invalid("file", "the spreadsheet has no menu rows"))
+	}
+	return menu, errs
+}
+
+// splitFoods returns the food names listed in a cell
+func splitFoods(cell string) []string {
+	var foods []string
+	for _, name := range strings.FieldsFunc(cell, func(r rune) bool { return r == ';' || r == '\n' }) {
+		if name = strings.TrimSpace(name); name != "" {
+			foods = append(foods, name)
+		}
+	}
+	return foods
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// readXLSX returns the rows of the first sheet of an XLSX workbook. Only the cell values
+// are read; formulas are read as their cached results.
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var sharedStrings []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst struct {
+			Items []xlsxText `xml:"si"`
+		}
+		if err := decodeZipXML(f, &sst); err != nil {
+			return nil, err
+		}
+		for _, si := range sst.Items {
+			sharedStrings = append(sharedStrings, si.String())
+		}
+	}
+
+	f, ok := files[firstSheetPath(files)]
+	if !ok {
+		return nil, errors.New("the workbook has no sheets")
+	}
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeZipXML(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, r := range sheet.Rows {
+		var row []string
+		for _, c := range r.Cells {
+			value := c.Value
+			switch c.Type {
+			case "s":
+				i, err := strconv.Atoi(c.Value)
+				if err != nil || i < 0 || i >= len(sharedStrings) {
+					return nil, fmt.Errorf("cell %s references a missing string", c.Ref)
+				}
+				value = sharedStrings[i]
+			case "inlineStr":
+				value = c.Inline.String()
+			}
+			column := len(row)
+			if c.Ref != "" {
+				column = columnIndex(c.Ref)
+			}
+			for len(row) < column {
+				row = append(row, "")
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// xlsxText is a string item, either plain or split into formatted runs
+type xlsxText struct {
+	Text string   `xml:"t"`
+	Runs []string `xml:"r>t"`
+}
+
+func (t xlsxText) String() string {
+	return t.Text + strings.Join(t.Runs, "")
+}
+
+// firstSheetPath finds the first sheet of the workbook through its relationships, falling
+// back to the usual name
+func firstSheetPath(files map[string]*zip.File) string {
+	const fallback = "xl/worksheets/sheet1.xml"
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	wb, ok1 := files["xl/workbook.xml"]
+	rf, ok2 := files["xl/_rels/workbook.xml.rels"]
+	if !ok1 || !ok2 || decodeZipXML(wb, &workbook) != nil || decodeZipXML(rf, &rels) != nil || len(workbook.Sheets) == 0 {
+		return fallback
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == workbook.Sheets[0].ID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/")
+			}
+			return path.Join("xl", rel.Target)
+		}
+	}
+	return fallback
+}
+
+func decodeZipXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(io.LimitReader(rc, 64<<20)).Decode(v)
+}
+
+// columnIndex returns the zero-based column of a cell reference, e.g. 2 for "C7"
+func columnIndex(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+	}
+	return column - 1
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,49 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSpreadsheetDay(t *testing.T) {
+	wednesday := time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		day     string
+		start   time.Time
+		want    int
+		wantErr bool
+	}{
+		{"the starting weekday is day 1", "wednesday", wednesday, 1, false},
+		{"later weekdays count from it", "Friday", wednesday, 3, false},
+		{"earlier weekdays wrap to the end", "monday", wednesday, 6, false},
+		{"the day before the start is day 7", "tue", wednesday, 7, false},
+		{"Greek names", "Δευτέρα", wednesday, 6, false},
+		{"Greek names in any case", "ΚΥΡΙΑΚΉ", wednesday.AddDate(0, 0, 4), 1, false},
+		{"a Monday start keeps the usual order", "sun", wednesday.AddDate(0, 0, -2), 7, false},
+		{"rotation day numbers are kept", "3", wednesday, 3, false},
+		{"numbers past the week", "8", wednesday, 0, true},
+		{"unknown names", "someday", wednesday, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := [][]string{
+				{"week", "day", "meal", "food"},
+				{"1", tt.day, "lunch", "Φακές"},
+			}
+			menu, errs := parseSpreadsheet(rows, tt.start)
+			if tt.wantErr {
+				if len(errs) != 1 || errs[0].Field != "rows[2].day" {
+					t.Errorf("errors = %v, want one for rows[2].day", errs)
+				}
+				return
+			}
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors %v", errs)
+			}
+			if got := menu.Items[0].DayNumber; got != tt.want {
+				t.Errorf("day %q from a %s = %d, want %d", tt.day, tt.start.Weekday(), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package schedule
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultStatsDays is how many days back the menu statistics look by default
+	DefaultStatsDays = 90
+
+	// MaxStatsDays bounds the look-back, about two semesters
+	MaxStatsDays = 365
+)
+
+// Orders the menu statistics list dishes in
+const (
+	StatsSortFrequent = "frequent" // most appearances first
+	StatsSortAbsent   = "absent"   // longest since last served first
+)
+
+// MenuStats aggregates the menus served from From to To inclusive
+type MenuStats struct {
+	From       string      `json:"from"`
+	To         string      `json:"to"`
+	DaysServed int         `json:"days_served"` // dates with at least one dish
+	Dishes     []DishStats `json:"dishes"`
+}
+
+// DishStats is how often a dish was served in a period
+type DishStats struct {
+	Food        Food    `json:"food"`
+	Appearances int     `json:"appearances"` // meals it was served at
+	PerWeek     float64 `json:"per_week"`    // average appearances per week of the period
+	LastServed  string  `json:"last_served"`
+	DaysSince   int     `json:"days_since"` // days from last_served to the end of the period
+}
+
+// Localized returns the statistics with the dishes in lang
+func (s MenuStats) Localized(lang string) MenuStats {
+	dishes := make([]DishStats, len(s.Dishes))
+	for i, d := range s.Dishes {
+		d.Food = d.Food.Localized(lang)
+		dishes[i] = d
+	}
+	s.Dishes = dishes
+	return s
+}
+
+// menuStats aggregates the menus of consecutive days, keeping the limit dishes first in
+// the sort order
+func menuStats(days []DaySchedule, sort string, limit int) MenuStats {
+	stats := MenuStats{Dishes: []DishStats{}}
+	if len(days) == 0 {
+		return stats
+	}
+	stats.From, stats.To = days[0].Date, days[len(days)-1].Date
+	end, _ := time.Parse("2006-01-02", stats.To)
+
+	byFood := make(map[int]*DishStats)
+	for _, day := range days {
+		if len(day.Lunch)+len(day.Dinner) > 0 {
+			stats.DaysServed++
+		}
+		for _, food := range append(slices.Clip(day.Lunch), day.Dinner...) {
+			dish, ok := byFood[food.ID]
+			if !ok {
+				dish = &DishStats{Food: food}
+				byFood[food.ID] = dish
+			}
+			dish.Appearances++
+			dish.LastServed = day.Date // days are in order
+		}
+	}
+
+	weeks := float64(len(days)) / 7
+	for _, dish := range byFood {
+		dish.PerWeek = math.Round(float64(dish.Appearances)/weeks*100) / 100
+		last, _ := time.Parse("2006-01-02", dish.LastServed)
+		dish.DaysSince = int(end.Sub(last).Hours() / 24)
+		stats.Dishes = append(stats.Dishes, *dish)
+	}
+
+	slices.SortFunc(stats.Dishes, func(a, b DishStats) int {
+		if sort == StatsSortAbsent && a.LastServed != b.LastServed {
+			return strings.Compare(a.LastServed, b.LastServed)
+		}
+		if a.Appearances != b.Appearances {
+			return b.Appearances - a.Appearances
+		}
+		return a.Food.ID - b.Food.ID
+	})
+	if len(stats.Dishes) > limit {
+		stats.Dishes = stats.Dishes[:limit]
+	}
+	return stats
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"API/internal/campustime"
+	"API/internal/realtime"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Topic names of the live updates (see realtime.Hub)
+const (
+	TopicAnnouncements = "announcements"
+	TopicSchedule      = "schedule"
+)
+
+// Topics returns what the cafeteria streams live: the announcements running now and
+// today's menu, which a kiosk shows without polling
+func Topics(repo *Repository) []realtime.Topic {
+	return []realtime.Topic{
+		{
+			Name:    TopicAnnouncements,
+			Feature: AnnouncementsFeatureSlug,
+			Snapshot: func(ctx context.Context, lang string) (interface{}, error) {
+				announcements, err := repo.CurrentAnnouncements(ctx, time.Now())
+				if err != nil {
+					return nil, err
+				}
+				for i := range announcements {
+					announcements[i] = announcements[i].Localized(lang)
+				}
+				return map[string]interface{}{"announcements": announcements}, nil
+			},
+		},
+		{
+			Name:    TopicSchedule,
+			Feature: FeatureSlug,
+			Snapshot: func(ctx context.Context, lang string) (interface{}, error) {
+				date := campustime.Today().Format("2006-01-02")
+				menu, err := repo.GetDateSchedule(ctx, date)
+				if errors.Is(err, sql.ErrNoRows) {
+					// No schedule covers the date; the menu is null until one does
+					return map[string]interface{}{"date": date, "menu": nil}, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				return map[string]interface{}{"date": date, "menu": menu.Localized(lang)}, nil
+			},
+		},
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
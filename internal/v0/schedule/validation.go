@@ -0,0 +1,202 @@
+package schedule
+
+import (
+	"API/internal/apierror"
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// mealTypes are the meals the schedule table accepts
+var mealTypes = []string{"lunch", "dinner"}
+
+// normalizeMealType lower-cases a meal type and reports whether it is one of mealTypes
+func normalizeMealType(mealType string) (string, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(mealType))
+	return normalized, slices.Contains(mealTypes, normalized)
+}
+
+// validateDateRange checks that an ending date, when set, is not before the starting date.
+// Both are YYYY-MM-DD, which compares correctly as text.
+func validateDateRange(starting, ending string) []apierror.Error {
+	if ending != "" && ending < starting {
+		return []apierror.Error{apierror.Invalid("ending_date", "ending_date must not be before starting_date")}
+	}
+	return nil
+}
+
+// parseDateRange checks a date range like validateDateRange and returns its starting
+// date, which the menus of spreadsheets and scans are laid out from
+func parseDateRange(starting, ending string) (time.Time, []apierror.Error) {
+	start, err := time.Parse("2006-01-02", starting)
+	if err != nil {
+		return time.Time{}, []apierror.Error{apierror.Invalid("starting_date", "starting_date must be YYYY-MM-DD")}
+	}
+	return start, validateDateRange(starting, ending)
+}
+
+// validateDishes checks that every food a dish list references exists
+func (h *Handler) validateDishes(ctx context.Context, field string, dishIDs []int) ([]apierror.Error, error) {
+	missing, err := h.repo.MissingFoods(ctx, dishIDs)
+	if err != nil || len(missing) == 0 {
+		return nil, err
+	}
+	return []apierror.Error{apierror.Invalid(field, fmt.Sprintf("foods %v do not exist", missing))}, nil
+}
+
+// validateImport normalizes the meal types of an import and checks its dates, meals and
+// dishes, naming the offending item of each error
+func (h *Handler) validateImport(ctx context.Context, imp *ScheduleImport) ([]apierror.Error, error) {
+	errs := validateDateRange(imp.StartingDate, imp.EndingDate)
+
+	var dishIDs []int
+	for i := range imp.Items {
+		mealType, ok := normalizeMealType(imp.Items[i].MealType)
+		if !ok {
+			errs = append(errs, apierror.Invalid(fmt.Sprintf("items[%d].meal_type", i), "meal_type must be lunch or dinner"))
+		}
+		imp.Items[i].MealType = mealType
+		dishIDs = append(dishIDs, imp.Items[i].DishIDs...)
+	}
+
+	missing, err := h.repo.MissingFoods(ctx, dishIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range imp.Items {
+		var itemMissing []int
+		for _, id := range item.DishIDs {
+			if slices.Contains(missing, id) && !slices.Contains(itemMissing, id) {
+				itemMissing = append(itemMissing, id)
+			}
+		}
+		if len(itemMissing) > 0 {
+			errs = append(errs, apierror.Invalid(fmt.Sprintf("items[%d].dish_ids", i), fmt.Sprintf("foods %v do not exist", itemMissing)))
+		}
+	}
+	return errs, nil
+}
+
+// validateClosure checks a closure's dates and that its replacement menu only holds existing foods
+func (h *Handler) validateClosure(ctx context.Context, closure Closure) ([]apierror.Error, error) {
+	errs := validateDateRange(closure.StartingDate, closure.EndingDate)
+	for field, dishIDs := range map[string][]int{"lunch_dish_ids": closure.LunchDishIDs, "dinner_dish_ids": closure.DinnerDishIDs} {
+		dishErrs, err := h.validateDishes(ctx, field, dishIDs)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, dishErrs...)
+	}
+	return errs, nil
+}
+
+// validateRestaurant checks that every serving period of a restaurant closes after it
+// opens and that no meal has two periods on the same days
+func validateRestaurant(restaurant Restaurant) []apierror.Error {
+	var errs []apierror.Error
+	seen := make(map[ServiceHours]bool)
+	for i, h := range restaurant.Hours {
+		if h.Closes <= h.Opens {
+			errs = append(errs, apierror.Invalid(fmt.Sprintf("hours[%d].closes", i), "closes must be after opens"))
+		}
+		key := ServiceHours{MealType: h.MealType, Days: h.Days}
+		if seen[key] {
+			errs = append(errs, apierror.Invalid(fmt.Sprintf("hours[%d].days", i), fmt.Sprintf("%s already has hours on %s", h.MealType, h.Days)))
+		}
+		seen[key] = true
+	}
+	return errs
+}
+
+// telegramChat matches Telegram chat IDs and public @channel names
+var telegramChat = regexp.MustCompile(`^(-?\d+|@[A-Za-z][A-Za-z0-9_]{3,})$`)
+
+// normalizeChannel trims a channel's fields, zero-pads its post time and checks its
+// target against its kind and that its template parses
+func normalizeChannel(channel *MenuChannel) []apierror.Error {
+	var errs []apierror.Error
+	channel.Target = strings.TrimSpace(channel.Target)
+	channel.BotToken = strings.TrimSpace(channel.BotToken)
+	if postAt, err := time.Parse("15:04", channel.PostAt); err == nil {
+		channel.PostAt = postAt.Format("15:04")
+	}
+
+	switch channel.Kind {
+	case ChannelDiscord:
+		target, err := url.Parse(channel.Target)
+		if err != nil || target.Scheme != "https" || target.Host == "" {
+			errs = append(errs, apierror.Invalid("target", "target must be a Discord webhook URL"))
+		}
+	case ChannelTelegram:
+		if !telegramChat.MatchString(channel.Target) {
+			errs = append(errs, apierror.Invalid("target", "target must be a Telegram chat ID or @channel"))
+		}
+		if channel.BotToken == "" {
+			errs = append(errs, apierror.Invalid("bot_token", "bot_token is required for Telegram channels"))
+		}
+	}
+	if _, err := parseMenuTemplate(channel.Template, channel.Language); err != nil {
+		errs = append(errs, apierror.Invalid("template", err.Error()))
+	}
+	return errs
+}
+
+// normalizeAnnouncement zero-pads an announcement's times, orders its weekdays and checks
+// its dates and times against each other
+func normalizeAnnouncement(a *Announcement) []apierror.Error {
+	errs := validateDateRange(a.StartingDate, a.EndingDate)
+	for _, clock := range []struct {
+		field string
+		value *string
+	}{{"starts_at", &a.StartsAt}, {"ends_at", &a.EndsAt}} {
+		if *clock.value == "" {
+			continue
+		}
+		t, err := time.Parse("15:04", *clock.value)
+		if err != nil {
+			errs = append(errs, apierror.Invalid(clock.field, clock.field+" must be HH:MM"))
+			continue
+		}
+		*clock.value = t.Format("15:04")
+	}
+	for _, day := range a.RepeatOn {
+		if !slices.Contains(repeatWeekdays[:], day) {
+			errs = append(errs, apierror.Invalid("repeat_on", "repeat_on must list weekday names, e.g. sunday"))
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	a.RepeatOn = repeatOn(repeatDays(a.RepeatOn))
+
+	// Recurring announcements run between the times on each day, one-offs from the
+	// starting date and time to the ending ones
+	bounded := len(a.RepeatOn) > 0 || a.StartingDate == a.EndingDate
+	if bounded && a.StartsAt != "" && a.EndsAt != "" && a.EndsAt <= a.StartsAt {
+		errs = append(errs, apierror.Invalid("ends_at", "ends_at must be after starts_at"))
+	}
+	if len(a.RepeatOn) == 0 && a.EndsAt != "" && a.EndingDate == "" {
+		errs = append(errs, apierror.Invalid("ends_at", "ends_at needs an ending_date unless the announcement repeats"))
+	}
+	return errs
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,134 @@
+package schedule
+
+import (
+	"API/internal/events"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// WebhookEventSchedulePublished is the event name sent to schedule publication webhooks
+	WebhookEventSchedulePublished = "schedule.published"
+
+	// WebhookEventAnnouncementPublished is the event name sent to announcement webhooks
+	WebhookEventAnnouncementPublished = "announcement.published"
+)
+
+// SchedulePublishedPayload is the body POSTed to schedule publication webhooks
+type SchedulePublishedPayload struct {
+	Event        string    `json:"event"`
+	VersionID    int64     `json:"version_id"`
+	StartingDate string    `json:"starting_date"`
+	EndingDate   string    `json:"ending_date"`
+	IsCurrent    bool      `json:"is_current"`
+	PublishedAt  time.Time `json:"published_at"`
+}
+
+// AnnouncementPublishedPayload is the body POSTed to announcement webhooks
+type AnnouncementPublishedPayload struct {
+	Event          string    `json:"event"`
+	AnnouncementID int64     `json:"announcement_id"`
+	Type           string    `json:"type"`
+	Content        string    `json:"content"`
+	ContentEn      string    `json:"content_en,omitempty"`
+	StartingDate   string    `json:"starting_date"`
+	EndingDate     string    `json:"ending_date"`
+	PublishedAt    time.Time `json:"published_at"`
+}
+
+// SubscribeWebhooks registers one durable outbox subscriber per URL, so that
+// integrations such as the cafeteria signage are told about new schedule
+// versions and announcements as soon as they are published. A failing endpoint
+// is retried on its own without re-notifying the others. Bodies are signed with
+// secret when set.
+func SubscribeWebhooks(outbox *events.Outbox, scheduleURLs, announcementURLs []string, secret string) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, url := range scheduleURLs {
+		outbox.Subscribe("schedule-webhook:"+url, newWebhookSubscriber(client, url, secret), events.TypeScheduleVersionPublished)
+	}
+	for _, url := range announcementURLs {
+		outbox.Subscribe("announcement-webhook:"+url, newWebhookSubscriber(client, url, secret), events.TypeAnnouncementPublished)
+	}
+}
+
+func newWebhookSubscriber(client *http.Client, url, secret string) events.Subscriber {
+	return func(ctx context.Context, event events.Event) error {
+		var name string
+		var payload interface{}
+		switch e := event.(type) {
+		case events.ScheduleVersionPublished:
+			name = WebhookEventSchedulePublished
+			payload = SchedulePublishedPayload{
+				Event:        name,
+				VersionID:    e.VersionID,
+				StartingDate: e.StartingDate,
+				EndingDate:   e.EndingDate,
+				IsCurrent:    e.IsCurrent,
+				PublishedAt:  e.OccurredAt,
+			}
+		case events.AnnouncementPublished:
+			name = WebhookEventAnnouncementPublished
+			payload = AnnouncementPublishedPayload{
+				Event:          name,
+				AnnouncementID: e.AnnouncementID,
+				Type:           e.Type,
+				Content:        e.Content,
+				ContentEn:      e.ContentEn,
+				StartingDate:   e.StartingDate,
+				EndingDate:     e.EndingDate,
+				PublishedAt:    e.OccurredAt,
+			}
+		default:
+			return nil
+		}
+
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Webhook-Event", name)
+		if secret != "" {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook %s responded with %s", url, resp.Status)
+		}
+		return nil
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
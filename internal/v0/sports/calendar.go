@@ -0,0 +1,79 @@
+package sports
+
+import (
+	"API/internal/calendar"
+	"context"
+	"fmt"
+	"time"
+)
+
+// CalendarBookings is the name of the calendar of a user's court bookings (see
+// calendar.Collection)
+const CalendarBookings = "court-bookings"
+
+// Calendars returns what the sports centre publishes for calendar apps: the court
+// bookings of the token's user
+func Calendars(repo *Repository) []calendar.Collection {
+	return []calendar.Collection{
+		{
+			Name:    CalendarBookings,
+			Feature: BookingsFeatureSlug,
+			Color:   "#C62828",
+			Title: func(lang string) string {
+				if lang == LanguageEnglish {
+					return "Court bookings"
+				}
+				return "Κρατήσεις γηπέδων"
+			},
+			Entries: func(ctx context.Context, userID int64, lang string, from, to time.Time) ([]calendar.Entry, error) {
+				bookings, err := repo.ListUserBookings(ctx, userID, from)
+				if err != nil {
+					return nil, err
+				}
+				facilities, err := repo.ListFacilities(ctx)
+				if err != nil {
+					return nil, err
+				}
+				places := make(map[int64]string, len(facilities))
+				for _, f := range facilities {
+					f = f.Localized(lang)
+					places[f.ID] = f.Name
+					if f.Location != "" {
+						places[f.ID] += ", " + f.Location
+					}
+				}
+
+				entries := []calendar.Entry{}
+				for _, b := range bookings {
+					if !b.StartsAt.Before(to) {
+						break
+					}
+					entries = append(entries, calendar.Entry{
+						UID:      fmt.Sprintf("booking-%d", b.ID),
+						Summary:  b.Court,
+						Location: places[b.FacilityID],
+						Start:    b.StartsAt,
+						End:      b.EndsAt,
+						Modified: b.CreatedAt,
+					})
+				}
+				return entries, nil
+			},
+		},
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
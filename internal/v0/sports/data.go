@@ -0,0 +1,602 @@
+package sports
+
+import (
+	"API/internal/campustime"
+	"API/internal/databases"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrDuplicateFacility is returned when another facility has the same code
+	ErrDuplicateFacility = errors.New("another facility has the same code")
+
+	// ErrDuplicateCourt is returned when the facility has another court with the same name
+	ErrDuplicateCourt = errors.New("the facility has another court with the same name")
+
+	// ErrUnknownFacility is returned for classes and courts of a facility that does not exist
+	ErrUnknownFacility = errors.New("facility not found")
+
+	// ErrSlotTaken is returned when the slot of a court is already booked
+	ErrSlotTaken = errors.New("the court is already booked at that time")
+
+	// ErrBookingLimit is returned when a user already holds as many bookings as allowed
+	ErrBookingLimit = errors.New("too many active bookings")
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new sports repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// facilityColumns are the columns scanFacility reads, in order
+const facilityColumns = "id, code, name, COALESCE(name_en, ''), kind, COALESCE(location, '')"
+
+func scanFacility(scan func(dest ...interface{}) error) (Facility, error) {
+	f := Facility{Hours: []campustime.OpeningHours{}}
+	err := scan(&f.ID, &f.Code, &f.Name, &f.NameEn, &f.Kind, &f.Location)
+	return f, err
+}
+
+// ListFacilities returns every facility with its hours, by name
+func (r *Repository) ListFacilities(ctx context.Context) ([]Facility, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+facilityColumns+" FROM sports_facilities ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facilities := []Facility{}
+	for rows.Next() {
+		f, err := scanFacility(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		facilities = append(facilities, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return facilities, r.attachHours(ctx, facilities)
+}
+
+// GetFacility returns a facility with its hours, or nil when it does not exist
+func (r *Repository) GetFacility(ctx context.Context, id int64) (*Facility, error) {
+	f, err := scanFacility(r.db.QueryRowContext(ctx, "SELECT "+facilityColumns+" FROM sports_facilities WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	facilities := []Facility{f}
+	if err := r.attachHours(ctx, facilities); err != nil {
+		return nil, err
+	}
+	return &facilities[0], nil
+}
+
+// attachHours loads the opening hours of facilities, Monday first
+func (r *Repository) attachHours(ctx context.Context, facilities []Facility) error {
+	if len(facilities) == 0 {
+		return nil
+	}
+	byID := make(map[int64]int, len(facilities))
+	placeholders := make([]string, 0, len(facilities))
+	args := make([]interface{}, 0, len(facilities))
+	for i, f := range facilities {
+		byID[f.ID] = i
+		placeholders = append(placeholders, "?")
+		args = append(args, f.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT facility_id, weekday, opens_at, closes_at FROM facility_hours
+		WHERE facility_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY facility_id, (weekday + 6) % 7, opens_at`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var facilityID int64
+		var weekday int
+		var h campustime.OpeningHours
+		if err := rows.Scan(&facilityID, &weekday, &h.Opens, &h.Closes); err != nil {
+			return err
+		}
+		h.Day = campustime.Weekdays[weekday]
+		facilities[byID[facilityID]].Hours = append(facilities[byID[facilityID]].Hours, h)
+	}
+	return rows.Err()
+}
+
+// facilityCodeTaken reports whether a facility other than id has the code
+func facilityCodeTaken(ctx context.Context, tx *sql.Tx, code string, id int64) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM sports_facilities WHERE code = ? AND id != ?)", code, id).Scan(&exists)
+	return exists, err
+}
+
+// writeHours replaces the opening hours of a facility
+func writeHours(ctx context.Context, tx *sql.Tx, id int64, hours []campustime.OpeningHours) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM facility_hours WHERE facility_id = ?", id); err != nil {
+		return err
+	}
+	for _, h := range hours {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO facility_hours (facility_id, weekday, opens_at, closes_at) VALUES (?, ?, ?, ?)
+		`, id, slices.Index(campustime.Weekdays[:], h.Day), h.Opens, h.Closes)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateFacility adds a facility with its opening hours
+func (r *Repository) CreateFacility(ctx context.Context, f Facility) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if taken, err := facilityCodeTaken(ctx, tx, f.Code, 0); err != nil || taken {
+			if taken {
+				return ErrDuplicateFacility
+			}
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO sports_facilities (code, name, name_en, kind, location) VALUES (?, ?, ?, ?, ?)
+		`, f.Code, f.Name, databases.NullString(f.NameEn), f.Kind, databases.NullString(f.Location))
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		return writeHours(ctx, tx, id, f.Hours)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplaceFacility overwrites a facility and its opening hours. Existing bookings are
+// kept even when they no longer fall within the hours. It returns false when the
+// facility does not exist.
+func (r *Repository) ReplaceFacility(ctx context.Context, id int64, f Facility) (bool, error) {
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if taken, err := facilityCodeTaken(ctx, tx, f.Code, id); err != nil || taken {
+			if taken {
+				return ErrDuplicateFacility
+			}
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE sports_facilities SET code = ?, name = ?, name_en = ?, kind = ?, location = ? WHERE id = ?
+		`, f.Code, f.Name, databases.NullString(f.NameEn), f.Kind, databases.NullString(f.Location), id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		replaced = true
+		return writeHours(ctx, tx, id, f.Hours)
+	})
+	return replaced, err
+}
+
+// DeleteFacility deletes a facility with its hours, classes, courts and their bookings.
+// It returns false when the facility does not exist.
+func (r *Repository) DeleteFacility(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, query := range []string{
+			"DELETE FROM court_bookings WHERE court_id IN (SELECT id FROM courts WHERE facility_id = ?)",
+			"DELETE FROM courts WHERE facility_id = ?",
+			"DELETE FROM sports_classes WHERE facility_id = ?",
+			"DELETE FROM facility_hours WHERE facility_id = ?",
+		} {
+			if _, err := tx.ExecContext(ctx, query, id); err != nil {
+				return err
+			}
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM sports_facilities WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+// facilityExists returns ErrUnknownFacility when the facility does not exist
+func facilityExists(ctx context.Context, tx *sql.Tx, id int64) error {
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM sports_facilities WHERE id = ?)", id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUnknownFacility
+	}
+	return nil
+}
+
+// ListClasses returns the weekly program, Monday first and by start time. A facilityID
+// of 0 lists the classes of every facility, and a weekday of -1 those of every day.
+func (r *Repository) ListClasses(ctx context.Context, facilityID int64, weekday int) ([]Class, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, facility_id, name, COALESCE(name_en, ''), COALESCE(instructor, ''), weekday, starts_at, ends_at, capacity
+		FROM sports_classes
+		WHERE (facility_id = ? OR ? = 0) AND (weekday = ? OR ? = -1)
+		ORDER BY (weekday + 6) % 7, starts_at, name
+	`, facilityID, facilityID, weekday, weekday)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	classes := []Class{}
+	for rows.Next() {
+		var class Class
+		var day int
+		var capacity sql.NullInt64
+		if err := rows.Scan(&class.ID, &class.FacilityID, &class.Name, &class.NameEn, &class.Instructor, &day,
+			&class.Starts, &class.Ends, &capacity); err != nil {
+			return nil, err
+		}
+		class.Day = campustime.Weekdays[day]
+		if capacity.Valid {
+			n := int(capacity.Int64)
+			class.Capacity = &n
+		}
+		classes = append(classes, class)
+	}
+	return classes, rows.Err()
+}
+
+// CreateClass adds a class to the weekly program
+func (r *Repository) CreateClass(ctx context.Context, class Class) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := facilityExists(ctx, tx, class.FacilityID); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO sports_classes (facility_id, name, name_en, instructor, weekday, starts_at, ends_at, capacity)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, class.FacilityID, class.Name, databases.NullString(class.NameEn), databases.NullString(class.Instructor),
+			slices.Index(campustime.Weekdays[:], class.Day), class.Starts, class.Ends, class.Capacity)
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplaceClass overwrites a class of the program. It returns false when the class does
+// not exist.
+func (r *Repository) ReplaceClass(ctx context.Context, id int64, class Class) (bool, error) {
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := facilityExists(ctx, tx, class.FacilityID); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE sports_classes SET facility_id = ?, name = ?, name_en = ?, instructor = ?, weekday = ?,
+				starts_at = ?, ends_at = ?, capacity = ?
+			WHERE id = ?
+		`, class.FacilityID, class.Name, databases.NullString(class.NameEn), databases.NullString(class.Instructor),
+			slices.Index(campustime.Weekdays[:], class.Day), class.Starts, class.Ends, class.Capacity, id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		replaced = n > 0
+		return err
+	})
+	return replaced, err
+}
+
+// DeleteClass removes a class from the program. It returns false when the class does not
+// exist.
+func (r *Repository) DeleteClass(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM sports_classes WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// courtColumns are the columns scanCourt reads, in order
+const courtColumns = "id, facility_id, name, COALESCE(name_en, ''), sport, slot_minutes"
+
+func scanCourt(scan func(dest ...interface{}) error) (Court, error) {
+	var court Court
+	err := scan(&court.ID, &court.FacilityID, &court.Name, &court.NameEn, &court.Sport, &court.SlotMinutes)
+	return court, err
+}
+
+// ListCourts returns the courts of a facility by name
+func (r *Repository) ListCourts(ctx context.Context, facilityID int64) ([]Court, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+courtColumns+" FROM courts WHERE facility_id = ? ORDER BY name", facilityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	courts := []Court{}
+	for rows.Next() {
+		court, err := scanCourt(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		courts = append(courts, court)
+	}
+	return courts, rows.Err()
+}
+
+// GetCourt returns a court, or nil when it does not exist
+func (r *Repository) GetCourt(ctx context.Context, id int64) (*Court, error) {
+	court, err := scanCourt(r.db.QueryRowContext(ctx, "SELECT "+courtColumns+" FROM courts WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &court, nil
+}
+
+// checkCourt returns ErrUnknownFacility or ErrDuplicateCourt when court cannot be stored
+// under id
+func checkCourt(ctx context.Context, tx *sql.Tx, court Court, id int64) error {
+	var facilityExists, nameTaken bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM sports_facilities WHERE id = ?),
+			EXISTS (SELECT 1 FROM courts WHERE facility_id = ? AND name = ? AND id != ?)
+	`, court.FacilityID, court.FacilityID, court.Name, id).Scan(&facilityExists, &nameTaken)
+	switch {
+	case err != nil:
+		return err
+	case !facilityExists:
+		return ErrUnknownFacility
+	case nameTaken:
+		return ErrDuplicateCourt
+	}
+	return nil
+}
+
+// CreateCourt adds a court to a facility
+func (r *Repository) CreateCourt(ctx context.Context, court Court) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkCourt(ctx, tx, court, 0); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO courts (facility_id, name, name_en, sport, slot_minutes) VALUES (?, ?, ?, ?, ?)
+		`, court.FacilityID, court.Name, databases.NullString(court.NameEn), court.Sport, court.SlotMinutes)
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplaceCourt overwrites a court, keeping its bookings. It returns false when the court
+// does not exist.
+func (r *Repository) ReplaceCourt(ctx context.Context, id int64, court Court) (bool, error) {
+	var replaced bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkCourt(ctx, tx, court, id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE courts SET facility_id = ?, name = ?, name_en = ?, sport = ?, slot_minutes = ? WHERE id = ?
+		`, court.FacilityID, court.Name, databases.NullString(court.NameEn), court.Sport, court.SlotMinutes, id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		replaced = n > 0
+		return err
+	})
+	return replaced, err
+}
+
+// DeleteCourt deletes a court and its bookings. It returns false when the court does not
+// exist.
+func (r *Repository) DeleteCourt(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM court_bookings WHERE court_id = ?", id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM courts WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+// BookedPeriods returns, by court, the bookings of a facility's courts that overlap
+// [from, to), as unavailable slots
+func (r *Repository) BookedPeriods(ctx context.Context, facilityID int64, from, to time.Time) (map[int64][]Slot, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT cb.court_id, cb.starts_at, cb.ends_at FROM court_bookings cb
+		JOIN courts c ON c.id = cb.court_id
+		WHERE c.facility_id = ? AND cb.cancelled_at IS NULL AND cb.starts_at < ? AND cb.ends_at > ?
+		ORDER BY cb.starts_at
+	`, facilityID, to.UTC(), from.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	booked := map[int64][]Slot{}
+	for rows.Next() {
+		var courtID int64
+		var s Slot
+		if err := rows.Scan(&courtID, &s.StartsAt, &s.EndsAt); err != nil {
+			return nil, err
+		}
+		booked[courtID] = append(booked[courtID], s)
+	}
+	return booked, rows.Err()
+}
+
+// bookingColumns are the columns scanBooking reads, in order
+const bookingColumns = `cb.id, cb.court_id, c.name, c.facility_id, cb.user_id, cb.starts_at, cb.ends_at,
+	cb.created_at, cb.cancelled_at`
+
+func scanBooking(scan func(dest ...interface{}) error) (Booking, error) {
+	var b Booking
+	var cancelledAt sql.NullTime
+	err := scan(&b.ID, &b.CourtID, &b.Court, &b.FacilityID, &b.UserID, &b.StartsAt, &b.EndsAt,
+		&b.CreatedAt, &cancelledAt)
+	if cancelledAt.Valid {
+		b.CancelledAt = &cancelledAt.Time
+	}
+	return b, err
+}
+
+// getBooking returns a booking, or nil when it does not exist
+func (r *Repository) getBooking(ctx context.Context, id int64) (*Booking, error) {
+	b, err := scanBooking(r.db.QueryRowContext(ctx, `
+		SELECT `+bookingColumns+` FROM court_bookings cb
+		JOIN courts c ON c.id = cb.court_id
+		WHERE cb.id = ?
+	`, id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ListUserBookings returns the bookings of a user that have not ended by now, in the
+// order they start
+func (r *Repository) ListUserBookings(ctx context.Context, userID int64, now time.Time) ([]Booking, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+bookingColumns+` FROM court_bookings cb
+		JOIN courts c ON c.id = cb.court_id
+		WHERE cb.user_id = ? AND cb.cancelled_at IS NULL AND cb.ends_at > ?
+		ORDER BY cb.starts_at
+	`, userID, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookings := []Booking{}
+	for rows.Next() {
+		b, err := scanBooking(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, b)
+	}
+	return bookings, rows.Err()
+}
+
+// Book books a slot of a court for a user, unless the court is booked at that time or
+// the user already holds maxActive bookings that have not ended by now
+func (r *Repository) Book(ctx context.Context, userID, courtID int64, slot Slot, maxActive int, now time.Time) (*Booking, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		var active int
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM court_bookings WHERE user_id = ? AND cancelled_at IS NULL AND ends_at > ?
+		`, userID, now.UTC()).Scan(&active); err != nil {
+			return err
+		}
+		if active >= maxActive {
+			return ErrBookingLimit
+		}
+
+		// The overlap check is part of the insert, so concurrent bookings cannot both win.
+		// Overlap rather than equality is checked, as the court's slot length may have
+		// changed since earlier bookings.
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO court_bookings (court_id, user_id, starts_at, ends_at, created_at)
+			SELECT ?, ?, ?, ?, ?
+			WHERE NOT EXISTS (
+				SELECT 1 FROM court_bookings
+				WHERE court_id = ? AND cancelled_at IS NULL AND starts_at < ? AND ends_at > ?
+			)
+		`, courtID, userID, slot.StartsAt.UTC(), slot.EndsAt.UTC(), now.UTC(), courtID, slot.EndsAt.UTC(), slot.StartsAt.UTC())
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return ErrSlotTaken
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.getBooking(ctx, id)
+}
+
+// CancelBooking cancels a booking of a user that has not ended by now. It returns false
+// when the user has no such booking.
+func (r *Repository) CancelBooking(ctx context.Context, id, userID int64, now time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE court_bookings SET cancelled_at = ?
+		WHERE id = ? AND user_id = ? AND cancelled_at IS NULL AND ends_at > ?
+	`, now.UTC(), id, userID, now.UTC())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
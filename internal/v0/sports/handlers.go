@@ -0,0 +1,586 @@
+package sports
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/campustime"
+	"API/internal/v0/common"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the sports facilities from the Repository
+type Handler struct {
+	repo   *Repository
+	limits BookingLimits
+}
+
+func NewHandler(repo *Repository) *Handler {
+	return &Handler{repo: repo, limits: DefaultBookingLimits}
+}
+
+// SetBookingLimits replaces DefaultBookingLimits
+func (h *Handler) SetBookingLimits(limits BookingLimits) {
+	h.limits = limits
+}
+
+// present localizes facilities and marks which of them are open now
+func present(facilities []Facility, lang string, now time.Time) {
+	for i := range facilities {
+		facilities[i] = facilities[i].Localized(lang)
+		if len(facilities[i].Hours) > 0 {
+			open := campustime.IsOpen(facilities[i].Hours, now)
+			facilities[i].OpenNow = &open
+		}
+	}
+}
+
+// GetFacilities returns every facility with its opening hours
+// GET /sports/facilities?lang=
+func (h *Handler) GetFacilities(c *gin.Context) {
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	facilities, err := h.repo.ListFacilities(c.Request.Context())
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list facilities")))
+		return
+	}
+	present(facilities, lang, time.Now())
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"facilities": facilities}))
+}
+
+// GetFacility returns one facility with its opening hours
+// GET /sports/facilities/:id?lang=
+func (h *Handler) GetFacility(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid facility ID")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	facility, err := h.repo.GetFacility(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get facility")))
+		return
+	}
+	if facility == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "facility not found")))
+		return
+	}
+	facilities := []Facility{*facility}
+	present(facilities, lang, time.Now())
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"facility": facilities[0]}))
+}
+
+// GetProgram returns the weekly program of classes, Monday first, optionally of one
+// facility or day
+// GET /sports/program?facility=&day=&lang=
+func (h *Handler) GetProgram(c *gin.Context) {
+	var facilityID int64
+	if v := c.Query("facility"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("facility", "facility must be a facility ID")))
+			return
+		}
+		facilityID = id
+	}
+	weekday := -1
+	if v := c.Query("day"); v != "" {
+		if weekday = slices.Index(campustime.Weekdays[:], strings.ToLower(v)); weekday < 0 {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("day", "day must be a weekday name, e.g. monday")))
+			return
+		}
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+
+	classes, err := h.repo.ListClasses(c.Request.Context(), facilityID, weekday)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list classes")))
+		return
+	}
+	for i := range classes {
+		classes[i] = classes[i].Localized(lang)
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"classes": classes}))
+}
+
+// GetCourts returns the courts of a facility with their slots on a day, today by
+// default. Booked slots and those that have started are not available.
+// GET /sports/facilities/:id/courts?date=&lang=
+func (h *Handler) GetCourts(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid facility ID")))
+		return
+	}
+	lang, ok := common.ParseLanguage(c, Languages)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	day := campustime.Today()
+	if v := c.Query("date"); v != "" {
+		if day, err = time.ParseInLocation("2006-01-02", v, campustime.Location); err != nil {
+			common.Render(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("date", "date must be YYYY-MM-DD")))
+			return
+		}
+	}
+
+	facility, err := h.repo.GetFacility(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get facility")))
+		return
+	}
+	if facility == nil {
+		common.Render(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "facility not found")))
+		return
+	}
+	courts, err := h.repo.ListCourts(c.Request.Context(), id)
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list courts")))
+		return
+	}
+	booked, err := h.repo.BookedPeriods(c.Request.Context(), id, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		common.Render(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list bookings")))
+		return
+	}
+	for i := range courts {
+		courts[i] = courts[i].Localized(lang)
+		courts[i].Slots = slots(facility.Hours, day, courts[i].SlotMinutes)
+		for j, s := range courts[i].Slots {
+			taken := slices.ContainsFunc(booked[courts[i].ID], func(b Slot) bool {
+				return b.StartsAt.Before(s.EndsAt) && s.StartsAt.Before(b.EndsAt)
+			})
+			courts[i].Slots[j].Available = !taken && s.StartsAt.After(now)
+		}
+	}
+	common.Render(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"date":   day.Format("2006-01-02"),
+		"hours":  facility.Hours,
+		"courts": courts,
+	}))
+}
+
+// ListBookings returns the calling user's bookings that have not ended yet
+// GET /sports/bookings
+func (h *Handler) ListBookings(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+	bookings, err := h.repo.ListUserBookings(c.Request.Context(), user.ID, time.Now())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list bookings")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"bookings":   bookings,
+		"max_active": h.limits.MaxActive,
+	}))
+}
+
+// PostBooking books the court slot starting at starts_at for the calling user, within
+// the BookingLimits
+// POST /sports/bookings
+func (h *Handler) PostBooking(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+	var req BookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+	now := time.Now()
+	switch {
+	case !req.StartsAt.After(now):
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("starts_at", "starts_at must be in the future")))
+		return
+	case req.StartsAt.After(now.Add(h.limits.Window)):
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("starts_at", fmt.Sprintf("bookings start at most %s ahead", h.limits.Window))))
+		return
+	}
+
+	court, err := h.repo.GetCourt(c.Request.Context(), req.CourtID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get court")))
+		return
+	}
+	if court == nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("court_id", "court not found")))
+		return
+	}
+	facility, err := h.repo.GetFacility(c.Request.Context(), court.FacilityID)
+	if err != nil || facility == nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get facility")))
+		return
+	}
+	slot, ok := slotAt(facility.Hours, req.StartsAt, court.SlotMinutes)
+	if !ok {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("starts_at", "no slot of the court starts at that time")))
+		return
+	}
+
+	booking, err := h.repo.Book(c.Request.Context(), user.ID, court.ID, slot, h.limits.MaxActive, now)
+	switch {
+	case errors.Is(err, ErrSlotTaken):
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+	case errors.Is(err, ErrBookingLimit):
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, fmt.Sprintf("%s: at most %d at a time", err, h.limits.MaxActive))))
+	case err != nil:
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to book court")))
+	default:
+		common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"booking": booking}))
+	}
+}
+
+// DeleteBooking cancels one of the calling user's bookings that has not ended
+// DELETE /sports/bookings/:id
+func (h *Handler) DeleteBooking(c *gin.Context) {
+	user := auth.GetUserFromContext(c)
+	if user == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.NotAuthenticated, "Not authenticated")))
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid booking ID")))
+		return
+	}
+	cancelled, err := h.repo.CancelBooking(c.Request.Context(), id, user.ID, time.Now())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to cancel booking")))
+		return
+	}
+	if !cancelled {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "booking not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "booking cancelled"}))
+}
+
+// ListFacilities returns every facility with its translations
+// GET /admin/sports/facilities
+func (h *Handler) ListFacilities(c *gin.Context) {
+	facilities, err := h.repo.ListFacilities(c.Request.Context())
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list facilities")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"facilities": facilities}))
+}
+
+// PostFacility adds a facility with its opening hours
+// POST /admin/sports/facilities
+func (h *Handler) PostFacility(c *gin.Context) {
+	facility, ok := bindFacility(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateFacility(c.Request.Context(), facility)
+	if errors.Is(err, ErrDuplicateFacility) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create facility")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceFacility overwrites a facility and its opening hours
+// PUT /admin/sports/facilities/:id
+func (h *Handler) ReplaceFacility(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid facility ID")))
+		return
+	}
+	facility, ok := bindFacility(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceFacility(c.Request.Context(), id, facility)
+	if errors.Is(err, ErrDuplicateFacility) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update facility")))
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "facility not found")))
+		return
+	}
+	updated, _ := h.repo.GetFacility(c.Request.Context(), id)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"facility": updated}))
+}
+
+// DeleteFacility deletes a facility with its classes, courts and their bookings
+// DELETE /admin/sports/facilities/:id
+func (h *Handler) DeleteFacility(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid facility ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteFacility(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete facility")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "facility not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "facility deleted"}))
+}
+
+// bindFacility binds and validates a facility body. It renders an error and returns
+// false when the body is invalid.
+func bindFacility(c *gin.Context) (Facility, bool) {
+	var facility Facility
+	if err := c.ShouldBindJSON(&facility); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Facility{}, false
+	}
+	facility.Code = strings.ToUpper(strings.TrimSpace(facility.Code))
+	facility.Name = strings.TrimSpace(facility.Name)
+	facility.NameEn = strings.TrimSpace(facility.NameEn)
+	facility.Location = strings.TrimSpace(facility.Location)
+	facility.OpenNow = nil
+	if facility.Hours == nil {
+		facility.Hours = []campustime.OpeningHours{}
+	}
+	var errs []apierror.Error
+	if facility.Code == "" {
+		errs = append(errs, apierror.Invalid("code", "code is required"))
+	}
+	if facility.Name == "" {
+		errs = append(errs, apierror.Invalid("name", "name is required"))
+	}
+	errs = append(errs, campustime.ValidateHours(facility.Hours)...)
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Facility{}, false
+	}
+	return facility, true
+}
+
+// PostClass adds a class to the weekly program
+// POST /admin/sports/classes
+func (h *Handler) PostClass(c *gin.Context) {
+	class, ok := bindClass(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateClass(c.Request.Context(), class)
+	if !renderFacilityChildError(c, err, "failed to create class") {
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceClass overwrites a class of the weekly program
+// PUT /admin/sports/classes/:id
+func (h *Handler) ReplaceClass(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid class ID")))
+		return
+	}
+	class, ok := bindClass(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceClass(c.Request.Context(), id, class)
+	if !renderFacilityChildError(c, err, "failed to update class") {
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "class not found")))
+		return
+	}
+	class.ID = id
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"class": class}))
+}
+
+// DeleteClass removes a class from the weekly program
+// DELETE /admin/sports/classes/:id
+func (h *Handler) DeleteClass(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid class ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteClass(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete class")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "class not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "class deleted"}))
+}
+
+// bindClass binds and validates a class body. It renders an error and returns false when
+// the body is invalid.
+func bindClass(c *gin.Context) (Class, bool) {
+	var class Class
+	if err := c.ShouldBindJSON(&class); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Class{}, false
+	}
+	class.Name, class.NameEn = strings.TrimSpace(class.Name), strings.TrimSpace(class.NameEn)
+	class.Instructor = strings.TrimSpace(class.Instructor)
+	var errs []apierror.Error
+	if class.Name == "" {
+		errs = append(errs, apierror.Invalid("name", "name is required"))
+	}
+	if class.Ends <= class.Starts {
+		errs = append(errs, apierror.Invalid("ends", "ends must be after starts"))
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Class{}, false
+	}
+	return class, true
+}
+
+// PostCourt adds a court to a facility
+// POST /admin/sports/courts
+func (h *Handler) PostCourt(c *gin.Context) {
+	court, ok := bindCourt(c)
+	if !ok {
+		return
+	}
+	id, err := h.repo.CreateCourt(c.Request.Context(), court)
+	if !renderFacilityChildError(c, err, "failed to create court") {
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{"id": id}))
+}
+
+// ReplaceCourt overwrites a court. Bookings made with another slot length are kept.
+// PUT /admin/sports/courts/:id
+func (h *Handler) ReplaceCourt(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid court ID")))
+		return
+	}
+	court, ok := bindCourt(c)
+	if !ok {
+		return
+	}
+	replaced, err := h.repo.ReplaceCourt(c.Request.Context(), id, court)
+	if !renderFacilityChildError(c, err, "failed to update court") {
+		return
+	}
+	if !replaced {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "court not found")))
+		return
+	}
+	court.ID = id
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"court": court}))
+}
+
+// DeleteCourt deletes a court and its bookings
+// DELETE /admin/sports/courts/:id
+func (h *Handler) DeleteCourt(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid court ID")))
+		return
+	}
+	deleted, err := h.repo.DeleteCourt(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete court")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "court not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "court deleted"}))
+}
+
+// bindCourt binds and validates a court body. It renders an error and returns false when
+// the body is invalid.
+func bindCourt(c *gin.Context) (Court, bool) {
+	var court Court
+	if err := c.ShouldBindJSON(&court); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return Court{}, false
+	}
+	court.Name, court.NameEn = strings.TrimSpace(court.Name), strings.TrimSpace(court.NameEn)
+	court.Sport = strings.ToLower(strings.TrimSpace(court.Sport))
+	court.Slots = nil
+	var errs []apierror.Error
+	if court.Name == "" {
+		errs = append(errs, apierror.Invalid("name", "name is required"))
+	}
+	if court.Sport == "" {
+		errs = append(errs, apierror.Invalid("sport", "sport is required"))
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return Court{}, false
+	}
+	return court, true
+}
+
+// renderFacilityChildError renders the error of a class or court write, returning true
+// when there was none
+func renderFacilityChildError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrUnknownFacility):
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("facility_id", err.Error())))
+	case errors.Is(err, ErrDuplicateCourt):
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+	default:
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, message)))
+	}
+	return false
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
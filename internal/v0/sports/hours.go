@@ -0,0 +1,61 @@
+package sports
+
+import (
+	"slices"
+	"time"
+
+	"API/internal/campustime"
+)
+
+// slots returns the slots of length minutes of day, counted from the start of each
+// opening period and ending by its close, in the order they start
+func slots(hours []campustime.OpeningHours, day time.Time, minutes int) []Slot {
+	y, m, d := day.In(campustime.Location).Date()
+	weekday := campustime.Weekdays[day.In(campustime.Location).Weekday()]
+	length := time.Duration(minutes) * time.Minute
+	at := func(clock string) time.Time {
+		t, _ := time.Parse("15:04", clock)
+		return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, campustime.Location)
+	}
+
+	result := []Slot{}
+	for _, h := range hours {
+		if h.Day != weekday {
+			continue
+		}
+		closes := at(h.Closes)
+		for start := at(h.Opens); !start.Add(length).After(closes); start = start.Add(length) {
+			result = append(result, Slot{StartsAt: start, EndsAt: start.Add(length), Available: true})
+		}
+	}
+	slices.SortFunc(result, func(a, b Slot) int {
+		return a.StartsAt.Compare(b.StartsAt)
+	})
+	return result
+}
+
+// slotAt returns the slot of length minutes that starts at start, and false when no slot
+// starts then
+func slotAt(hours []campustime.OpeningHours, start time.Time, minutes int) (Slot, bool) {
+	for _, s := range slots(hours, start, minutes) {
+		if s.StartsAt.Equal(start) {
+			return s, true
+		}
+	}
+	return Slot{}, false
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,83 @@
+package sports
+
+import (
+	"testing"
+	"time"
+
+	"API/internal/campustime"
+)
+
+func TestSlots(t *testing.T) {
+	hours := []campustime.OpeningHours{
+		{Day: "monday", Opens: "17:00", Closes: "21:00"},
+		{Day: "monday", Opens: "08:00", Closes: "11:30"},
+		{Day: "sunday", Opens: "10:00", Closes: "12:00"},
+	}
+	// May 6th 2024 is a Monday, and clocks went back on Sunday, October 27th
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, campustime.Location)
+	}
+	tests := []struct {
+		name    string
+		day     time.Time
+		minutes int
+		want    []string // starts, as HH:MM
+	}{
+		{"periods in the order they start", at(time.May, 6, 0, 0), 60, []string{"08:00", "09:00", "10:00", "17:00", "18:00", "19:00", "20:00"}},
+		{"slots end by closing time", at(time.May, 6, 0, 0), 90, []string{"08:00", "09:30", "17:00", "18:30"}},
+		{"a slot longer than a period", at(time.May, 6, 0, 0), 240, []string{"17:00"}},
+		{"a closed day", at(time.May, 7, 0, 0), 60, []string{}},
+		{"the day on campus", at(time.May, 6, 23, 30).UTC(), 60, []string{"08:00", "09:00", "10:00", "17:00", "18:00", "19:00", "20:00"}},
+		{"the day clocks go back", at(time.October, 27, 0, 0), 60, []string{"10:00", "11:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slots(hours, tt.day, tt.minutes)
+			starts := make([]string, len(got))
+			for i, s := range got {
+				starts[i] = s.StartsAt.In(campustime.Location).Format("15:04")
+				if length := s.EndsAt.Sub(s.StartsAt); length != time.Duration(tt.minutes)*time.Minute {
+					t.Errorf("slot at %s lasts %s, want %d minutes", starts[i], length, tt.minutes)
+				}
+			}
+			if len(starts) != len(tt.want) {
+				t.Fatalf("slots start at %v, want %v", starts, tt.want)
+			}
+			for i := range starts {
+				if starts[i] != tt.want[i] {
+					t.Fatalf("slots start at %v, want %v", starts, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSlotAt(t *testing.T) {
+	hours := []campustime.OpeningHours{{Day: "monday", Opens: "08:00", Closes: "11:30"}}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.May, 6, hour, minute, 0, 0, campustime.Location)
+	}
+	tests := []struct {
+		name   string
+		start  time.Time
+		wantOK bool
+	}{
+		{"the first slot", at(8, 0), true},
+		{"a later slot", at(9, 30), true},
+		{"the same time in UTC", at(9, 30).UTC(), true},
+		{"between slots", at(9, 0), false},
+		{"a slot that would end past closing", at(11, 0), false},
+		{"outside the hours", at(12, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slot, ok := slotAt(hours, tt.start, 90)
+			if ok != tt.wantOK {
+				t.Fatalf("slotAt(%s) ok = %t, want %t", tt.start, ok, tt.wantOK)
+			}
+			if ok && (!slot.StartsAt.Equal(tt.start) || !slot.EndsAt.Equal(tt.start.Add(90*time.Minute))) {
+				t.Errorf("slotAt(%s) = %s to %s", tt.start, slot.StartsAt, slot.EndsAt)
+			}
+		})
+	}
+}
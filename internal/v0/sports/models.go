@@ -0,0 +1,127 @@
+package sports
+
+import (
+	"time"
+
+	"API/internal/campustime"
+	"API/internal/v0/common"
+)
+
+// Languages the sports facilities are served in, the original Greek first
+const (
+	LanguageGreek   = "el"
+	LanguageEnglish = "en"
+)
+
+var Languages = []string{LanguageGreek, LanguageEnglish}
+
+// Facility is a sports facility with its opening hours
+type Facility struct {
+	ID       int64                     `json:"id"`
+	Code     string                    `json:"code" binding:"required,max=20"`
+	Name     string                    `json:"name" binding:"required,max=200"`
+	NameEn   string                    `json:"name_en,omitempty" binding:"max=200"`
+	Kind     string                    `json:"kind" binding:"required,oneof=gym court pool field other"`
+	Location string                    `json:"location,omitempty" binding:"max=200"`
+	Hours    []campustime.OpeningHours `json:"hours" binding:"max=50,dive"`
+
+	// OpenNow is computed for responses
+	OpenNow *bool `json:"open_now,omitempty"`
+}
+
+// Localized returns the facility in lang, falling back to the Greek original, with the
+// translation fields left out
+func (f Facility) Localized(lang string) Facility {
+	common.Localize(lang, &f.Name, &f.NameEn)
+	return f
+}
+
+// Class is a class of the weekly program, held at a facility on a weekday
+type Class struct {
+	ID         int64  `json:"id"`
+	FacilityID int64  `json:"facility_id" binding:"required"`
+	Name       string `json:"name" binding:"required,max=200"`
+	NameEn     string `json:"name_en,omitempty" binding:"max=200"`
+	Instructor string `json:"instructor,omitempty" binding:"max=200"`
+	Day        string `json:"day" binding:"required,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	Starts     string `json:"starts" binding:"required,datetime=15:04"`
+	Ends       string `json:"ends" binding:"required,datetime=15:04"`
+	Capacity   *int   `json:"capacity,omitempty" binding:"omitempty,min=1"`
+}
+
+// Localized returns the class in lang, falling back to the Greek original, with the
+// translation fields left out
+func (c Class) Localized(lang string) Class {
+	common.Localize(lang, &c.Name, &c.NameEn)
+	return c
+}
+
+// Court is a court users can book in slots of SlotMinutes
+type Court struct {
+	ID          int64  `json:"id"`
+	FacilityID  int64  `json:"facility_id" binding:"required"`
+	Name        string `json:"name" binding:"required,max=100"`
+	NameEn      string `json:"name_en,omitempty" binding:"max=100"`
+	Sport       string `json:"sport" binding:"required,max=50"`
+	SlotMinutes int    `json:"slot_minutes" binding:"required,min=15,max=240"`
+
+	// Slots lists the slots of the day asked for, in availability listings
+	Slots []Slot `json:"slots,omitempty"`
+}
+
+// Localized returns the court in lang, falling back to the Greek original, with the
+// translation fields left out
+func (c Court) Localized(lang string) Court {
+	common.Localize(lang, &c.Name, &c.NameEn)
+	return c
+}
+
+// Slot is a span of time a court can be booked for
+type Slot struct {
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Available bool      `json:"available"`
+}
+
+// Booking is a user's booking of a court slot
+type Booking struct {
+	ID          int64      `json:"id"`
+	CourtID     int64      `json:"court_id"`
+	Court       string     `json:"court"`
+	FacilityID  int64      `json:"facility_id"`
+	UserID      int64      `json:"-"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+// BookingRequest is the body of a new booking; the slot's length follows from the court
+type BookingRequest struct {
+	CourtID  int64     `json:"court_id" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+}
+
+// BookingLimits bound what a single user can book
+type BookingLimits struct {
+	MaxActive int           // bookings a user may hold that have not ended yet
+	Window    time.Duration // how far ahead bookings can start
+}
+
+// DefaultBookingLimits apply unless SPORTS_* variables say otherwise
+var DefaultBookingLimits = BookingLimits{MaxActive: 2, Window: 7 * 24 * time.Hour}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
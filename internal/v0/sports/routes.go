@@ -0,0 +1,66 @@
+package sports
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// FeatureSlug is the feature tokens need for the sports endpoints
+	FeatureSlug = "sports"
+
+	// BookingsFeatureSlug is the feature tokens need to book courts
+	BookingsFeatureSlug = "sports.bookings"
+)
+
+// Features are the features this module serves, registered at startup
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Sports API", Description: "Opening hours of the gym and courts, the weekly class program and court availability"},
+	{Slug: BookingsFeatureSlug, Name: "Court bookings", Parent: FeatureSlug, Description: "Booking court slots for the token's user"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	sports := rg.Group("/sports")
+	{
+		sports.GET("/facilities", authMiddleware.RequireToken(FeatureSlug), h.GetFacilities)
+		sports.GET("/facilities/:id", authMiddleware.RequireToken(FeatureSlug), h.GetFacility)
+		sports.GET("/facilities/:id/courts", authMiddleware.RequireToken(FeatureSlug), h.GetCourts)
+		sports.GET("/program", authMiddleware.RequireToken(FeatureSlug), h.GetProgram)
+		sports.GET("/bookings", authMiddleware.RequireToken(BookingsFeatureSlug), h.ListBookings)
+		sports.POST("/bookings", authMiddleware.RequireToken(BookingsFeatureSlug), h.PostBooking)
+		sports.DELETE("/bookings/:id", authMiddleware.RequireToken(BookingsFeatureSlug), h.DeleteBooking)
+	}
+
+	sports_admin := rg.Group("/admin/sports")
+	sports_admin.Use(authMiddleware.RequireSession())
+	sports_admin.Use(authMiddleware.RequireRole(auth.RoleAdmin))
+	sports_admin.Use(authMiddleware.Idempotent())
+	{
+		sports_admin.GET("/facilities", h.ListFacilities)
+		sports_admin.POST("/facilities", h.PostFacility)
+		sports_admin.PUT("/facilities/:id", h.ReplaceFacility)
+		sports_admin.DELETE("/facilities/:id", h.DeleteFacility)
+		sports_admin.POST("/classes", h.PostClass)
+		sports_admin.PUT("/classes/:id", h.ReplaceClass)
+		sports_admin.DELETE("/classes/:id", h.DeleteClass)
+		sports_admin.POST("/courts", h.PostCourt)
+		sports_admin.PUT("/courts/:id", h.ReplaceCourt)
+		sports_admin.DELETE("/courts/:id", h.DeleteCourt)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,166 @@
+package stream
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/negotiate"
+	"API/internal/realtime"
+	"API/internal/v0/common"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// MaxStreamsPerToken caps the streams a token may hold open at once; a stream is
+	// charged to the quota once, when it opens
+	MaxStreamsPerToken = 5
+
+	// KeepAliveInterval is how often an idle stream gets a comment line, so that proxies
+	// do not close it
+	KeepAliveInterval = 25 * time.Second
+
+	// RetryMillis is how long EventSource clients wait before reconnecting
+	RetryMillis = 5000
+)
+
+// Tokens tells whether the calling token may follow a topic
+type Tokens interface {
+	TokenHasFeature(ctx context.Context, tokenID int64, featureSlug string) (bool, error)
+}
+
+// Handler streams the topics of a realtime.Hub as server-sent events
+type Handler struct {
+	hub    *realtime.Hub
+	tokens Tokens
+	open   *realtime.ConnectionLimit
+}
+
+func NewHandler(hub *realtime.Hub, tokens Tokens) *Handler {
+	return &Handler{hub: hub, tokens: tokens, open: realtime.NewConnectionLimit(MaxStreamsPerToken)}
+}
+
+// topics picks the topics of ?topics= (comma separated), or every topic the token may
+// follow when it is missing. It renders an error and returns nil when a topic is unknown
+// or needs a feature the token does not have.
+func (h *Handler) topics(c *gin.Context, token *auth.Token) []string {
+	requested := c.Query("topics")
+	var names []string
+	for _, name := range strings.Split(requested, ",") {
+		if name = strings.TrimSpace(name); name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	var topics []string
+	for _, topic := range h.hub.Topics() {
+		if requested != "" && !slices.Contains(names, topic.Name) {
+			continue
+		}
+		allowed, err := h.tokens.TokenHasFeature(c.Request.Context(), token.ID, topic.Feature)
+		if err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to check the token's features")))
+			return nil
+		}
+		if !allowed {
+			if requested != "" {
+				common.JSON(c, http.StatusForbidden, common.CreateErrorResponse(apierror.New(apierror.FeatureNotAllowed,
+					fmt.Sprintf("the token needs the '%s' feature to follow %s", topic.Feature, topic.Name))))
+				return nil
+			}
+			continue
+		}
+		topics = append(topics, topic.Name)
+	}
+
+	if requested != "" && len(topics) < len(names) {
+		for _, name := range names {
+			if !slices.Contains(topics, name) {
+				common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.Invalid("topics", fmt.Sprintf("unknown topic '%s'", name))))
+				return nil
+			}
+		}
+	}
+	if len(topics) == 0 {
+		common.JSON(c, http.StatusForbidden, common.CreateErrorResponse(apierror.New(apierror.FeatureNotAllowed, "the token may not follow any topic")))
+		return nil
+	}
+	return topics
+}
+
+// Stream sends server-sent events as the followed topics change: the event is the topic
+// (announcements, schedule) and the data its whole current state as JSON, in the language
+// of ?lang= or Accept-Language. The current states are sent first, so a client has
+// everything to show at once and after every reconnect.
+// GET /stream?topics=&lang=
+func (h *Handler) Stream(c *gin.Context) {
+	token := auth.GetTokenFromContext(c)
+	if token == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.InvalidToken, "a token is required")))
+		return
+	}
+	lang, err := negotiate.Language(c, h.hub.Languages())
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	topics := h.topics(c, token)
+	if topics == nil {
+		return
+	}
+	if !h.open.Acquire(token.ID) {
+		common.JSON(c, http.StatusTooManyRequests, common.CreateErrorResponse(apierror.New(apierror.RateLimited,
+			fmt.Sprintf("the token already has %d streams open", MaxStreamsPerToken))))
+		return
+	}
+	defer h.open.Release(token.ID)
+
+	sub := h.hub.Subscribe(lang, topics)
+	defer h.hub.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Content-Language", lang)
+	c.Header("X-Accel-Buffering", "no") // nginx would hold the events back otherwise
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", RetryMillis)
+
+	keepAlive := time.NewTicker(KeepAliveInterval)
+	defer keepAlive.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case msg, ok := <-sub.C:
+			if !ok {
+				return false
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", msg.ID, msg.Topic, msg.Data)
+			return true
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
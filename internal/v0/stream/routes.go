@@ -0,0 +1,38 @@
+package stream
+
+import (
+	"API/internal/auth"
+	"API/internal/limits"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSlug is the feature tokens need to open a stream. Each topic also needs the
+// feature of the data it carries (see realtime.Topic).
+const FeatureSlug = "stream"
+
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Live updates stream", Description: "Server-sent events when the running announcements or today's menu change"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	rg.GET("/stream", authMiddleware.RequireToken(FeatureSlug), h.Stream)
+	// A stream stays open for as long as its client listens
+	limits.SetRoute(http.MethodGet, rg.BasePath()+"/stream", limits.Limits{Timeout: -1})
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,224 @@
+package webhooks
+
+import (
+	"API/internal/databases"
+	"API/internal/events"
+	"API/internal/pagination"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrTooManySubscriptions is returned when a token already has MaxSubscriptionsPerToken
+// webhooks
+var ErrTooManySubscriptions = errors.New("the token has as many webhooks as it may")
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new webhooks repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// subscriptionColumns are the columns scanSubscription reads, in order
+const subscriptionColumns = `id, token_id, user_id, url, events, secret, description, active, created_at, updated_at`
+
+func scanSubscription(scan func(dest ...interface{}) error) (Subscription, error) {
+	var s Subscription
+	var eventList string
+	var description sql.NullString
+	if err := scan(&s.ID, &s.TokenID, &s.UserID, &s.URL, &eventList, &s.Secret, &description, &s.Active,
+		&s.CreatedAt, &s.UpdatedAt); err != nil {
+		return s, err
+	}
+	for _, t := range strings.Split(eventList, ",") {
+		s.Events = append(s.Events, events.Type(t))
+	}
+	if description.Valid {
+		s.Description = &description.String
+	}
+	return s, nil
+}
+
+func (r *Repository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []Subscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, rows.Err()
+}
+
+// ListSubscriptions returns every subscription, for subscribing them to the outbox
+func (r *Repository) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	return r.querySubscriptions(ctx, "SELECT "+subscriptionColumns+" FROM webhook_subscriptions ORDER BY id")
+}
+
+// ListTokenSubscriptions returns a token's subscriptions, oldest first
+func (r *Repository) ListTokenSubscriptions(ctx context.Context, tokenID int64) ([]Subscription, error) {
+	return r.querySubscriptions(ctx, "SELECT "+subscriptionColumns+" FROM webhook_subscriptions WHERE token_id = ? ORDER BY id", tokenID)
+}
+
+// ActiveSubscriptions returns the active subscriptions to an event type
+func (r *Repository) ActiveSubscriptions(ctx context.Context, eventType events.Type) ([]Subscription, error) {
+	return r.querySubscriptions(ctx, `SELECT `+subscriptionColumns+` FROM webhook_subscriptions
+		WHERE active = 1 AND ',' || events || ',' LIKE '%,' || ? || ',%' ORDER BY id`, string(eventType))
+}
+
+// GetSubscription returns a subscription, or nil when it does not exist
+func (r *Repository) GetSubscription(ctx context.Context, id int64) (*Subscription, error) {
+	s, err := scanSubscription(r.db.QueryRowContext(ctx, "SELECT "+subscriptionColumns+" FROM webhook_subscriptions WHERE id = ?", id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// CreateSubscription adds a subscription for its token, which may hold at most max
+func (r *Repository) CreateSubscription(ctx context.Context, s Subscription, max int) (int64, error) {
+	var id int64
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		var count int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM webhook_subscriptions WHERE token_id = ?", s.TokenID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= max {
+			return ErrTooManySubscriptions
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_subscriptions (token_id, user_id, url, events, secret, description) VALUES (?, ?, ?, ?, ?, ?)
+		`, s.TokenID, s.UserID, s.URL, joinEventTypes(s.Events), s.Secret, s.Description)
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateSubscription changes the fields the request sets. It returns false when the
+// subscription does not exist.
+func (r *Repository) UpdateSubscription(ctx context.Context, id int64, req SubscriptionUpdateRequest) (bool, error) {
+	var eventList *string
+	if req.Events != nil {
+		joined := joinEventTypes(req.Events)
+		eventList = &joined
+	}
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_subscriptions
+		SET url = COALESCE(?, url),
+		    events = COALESCE(?, events),
+		    description = COALESCE(?, description),
+		    active = COALESCE(?, active),
+		    updated_at = ?
+		WHERE id = ?
+	`, req.URL, eventList, req.Description, req.Active, time.Now().UTC(), id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DeleteSubscription deletes a subscription with its delivery log. It returns false when
+// the subscription does not exist.
+func (r *Repository) DeleteSubscription(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := databases.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM event_outbox WHERE subscriber = ?", subscriberName(id)); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		deleted = n > 0
+		return err
+	})
+	return deleted, err
+}
+
+// ListDeliveries returns a page of a subscription's deliveries, newest first. Delivered
+// ones are kept for events.OutboxRetentionPeriod. The page holds one extra delivery when
+// another page follows (see pagination.Next).
+func (r *Repository) ListDeliveries(ctx context.Context, id int64, page pagination.Params) ([]Delivery, error) {
+	after, args := page.Where("id")
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, event_type, payload, attempts, COALESCE(last_error, ''), next_attempt_at, delivered_at, failed_at, created_at
+		FROM event_outbox WHERE subscriber = ? AND `+after+`
+		ORDER BY id DESC
+		LIMIT ?
+	`, append(append([]interface{}{subscriberName(id)}, args...), page.FetchLimit())...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		var d Delivery
+		var payload string
+		var nextAttemptAt time.Time
+		var deliveredAt, failedAt sql.NullTime
+		if err := rows.Scan(&d.ID, &d.Event, &payload, &d.Attempts, &d.LastError, &nextAttemptAt, &deliveredAt,
+			&failedAt, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.Data = json.RawMessage(payload)
+		switch {
+		case deliveredAt.Valid:
+			d.Status, d.DeliveredAt = StatusDelivered, &deliveredAt.Time
+		case failedAt.Valid:
+			d.Status, d.FailedAt = StatusFailed, &failedAt.Time
+		default:
+			d.Status, d.NextAttemptAt = StatusPending, &nextAttemptAt
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func joinEventTypes(types []events.Type) string {
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = string(t)
+	}
+	return strings.Join(parts, ",")
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
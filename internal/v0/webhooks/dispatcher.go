@@ -0,0 +1,281 @@
+package webhooks
+
+import (
+	"API/internal/databases"
+	"API/internal/events"
+	"API/internal/v0/schedule"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const (
+	// MaxSubscriptionsPerToken caps the webhooks a single token may register
+	MaxSubscriptionsPerToken = 10
+
+	// DeliveryTimeout bounds a single webhook request
+	DeliveryTimeout = 10 * time.Second
+
+	// PingEvent is the event name of test deliveries
+	PingEvent = "ping"
+
+	// fanOutSubscriber is the name the dispatcher subscribes to the source outboxes under
+	fanOutSubscriber = "token-webhooks"
+)
+
+// EventTypes are the events token holders can subscribe to. Quota events go only to
+// the webhooks of the user who was rate limited.
+var EventTypes = []EventType{
+	{Type: events.TypeScheduleVersionPublished, Feature: schedule.FeatureSlug, Description: "A new version of the menu was published"},
+	{Type: events.TypeAnnouncementPublished, Feature: schedule.AnnouncementsFeatureSlug, Description: "An announcement was published"},
+	{Type: events.TypeQuotaExceeded, Feature: FeatureSlug, Description: "One of your tokens used up its per-minute quota for a feature"},
+}
+
+// errPrivateAddress refuses deliveries to the server's own networks, which token holders
+// must not reach through their webhooks
+var errPrivateAddress = errors.New("webhook URL resolves to a loopback or private network address")
+
+// Tokens tells whether a subscription's token may still receive an event
+type Tokens interface {
+	TokenHasFeature(ctx context.Context, tokenID int64, featureSlug string) (bool, error)
+}
+
+// Dispatcher delivers events to the token holders' webhooks. It subscribes to the outboxes
+// events are published on and copies each event into its own outbox once per entitled
+// subscription, so every webhook is retried with backoff on its own and its deliveries
+// make up its log.
+type Dispatcher struct {
+	repo   *Repository
+	outbox *events.Outbox
+	tokens Tokens
+	client *http.Client
+}
+
+// NewDispatcher creates a dispatcher delivering through outbox, which must be backed by
+// the webhooks database. Deliveries to loopback and private addresses are refused unless
+// AllowPrivateNetworks is called.
+func NewDispatcher(repo *Repository, outbox *events.Outbox, tokens Tokens) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		outbox: outbox,
+		tokens: tokens,
+		client: newClient(false),
+	}
+}
+
+// AllowPrivateNetworks lets webhooks deliver to loopback and private addresses, e.g. a
+// receiver on localhost during development
+func (d *Dispatcher) AllowPrivateNetworks() {
+	d.client = newClient(true)
+}
+
+// Listen subscribes the dispatcher to the outboxes the subscribable events are enqueued
+// on. Call it before they start, like Load.
+func (d *Dispatcher) Listen(sources ...*events.Outbox) {
+	types := make([]events.Type, len(EventTypes))
+	for i, t := range EventTypes {
+		types[i] = t.Type
+	}
+	for _, source := range sources {
+		source.Subscribe(fanOutSubscriber, d.fanOut, types...)
+	}
+}
+
+// Load subscribes every stored webhook to the dispatcher's outbox. Call it before the
+// outbox starts so that pending deliveries find their subscriber.
+func (d *Dispatcher) Load(ctx context.Context) error {
+	subscriptions, err := d.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range subscriptions {
+		d.subscribe(s.ID)
+	}
+	return nil
+}
+
+func (d *Dispatcher) subscribe(id int64) {
+	d.outbox.Subscribe(subscriberName(id), d.deliverer(id))
+}
+
+func (d *Dispatcher) unsubscribe(id int64) error {
+	return d.outbox.Unsubscribe(subscriberName(id))
+}
+
+// fanOut queues an event for every active subscription whose token may receive it
+func (d *Dispatcher) fanOut(ctx context.Context, event events.Event) error {
+	subscriptions, err := d.repo.ActiveSubscriptions(ctx, event.EventType())
+	if err != nil {
+		return err
+	}
+	var recipients []int64
+	for i := range subscriptions {
+		entitled, err := d.entitled(ctx, &subscriptions[i], event)
+		if err != nil {
+			return err
+		}
+		if entitled {
+			recipients = append(recipients, subscriptions[i].ID)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	err = databases.WithTx(ctx, d.repo.db, func(tx *sql.Tx) error {
+		for _, id := range recipients {
+			if err := d.outbox.EnqueueTo(tx, subscriberName(id), event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	d.outbox.Notify()
+	return nil
+}
+
+// entitled reports whether a subscription's token may receive an event: it needs the
+// webhooks feature and the event's, and quota events must concern its own user
+func (d *Dispatcher) entitled(ctx context.Context, s *Subscription, event events.Event) (bool, error) {
+	if e, ok := event.(events.QuotaExceeded); ok && e.UserID != s.UserID {
+		return false, nil
+	}
+	feature := ""
+	for _, t := range EventTypes {
+		if t.Type == event.EventType() {
+			feature = t.Feature
+		}
+	}
+	if feature == "" {
+		return false, nil
+	}
+	for _, slug := range []string{FeatureSlug, feature} {
+		ok, err := d.tokens.TokenHasFeature(ctx, s.TokenID, slug)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// deliverer returns the outbox subscriber of a webhook. The webhook and its token are
+// checked again on every delivery so that edits, pauses and revoked tokens apply to
+// queued events.
+func (d *Dispatcher) deliverer(id int64) events.Subscriber {
+	return func(ctx context.Context, event events.Event) error {
+		s, err := d.repo.GetSubscription(ctx, id)
+		if err != nil {
+			return err
+		}
+		if s == nil || !s.Active {
+			return nil
+		}
+		entitled, err := d.entitled(ctx, s, event)
+		if err != nil || !entitled {
+			return err
+		}
+		return d.send(ctx, s, string(event.EventType()), event)
+	}
+}
+
+// Ping sends a test delivery right away, bypassing the outbox, so token holders can check
+// the URL and their signature verification when setting a webhook up
+func (d *Dispatcher) Ping(ctx context.Context, s *Subscription) error {
+	return d.send(ctx, s, PingEvent, map[string]interface{}{"webhook_id": s.ID})
+}
+
+// send POSTs {"event", "data"} to the webhook, signed with its secret in
+// X-Webhook-Signature like the admin webhooks
+func (d *Dispatcher) send(ctx context.Context, s *Subscription, eventType string, data interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"event": eventType, "data": data})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write(body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", eventType)
+	req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %d responded with %s", s.ID, resp.Status)
+	}
+	return nil
+}
+
+// newClient returns the client webhooks are delivered with. Unless allowPrivate is set,
+// its dialer refuses loopback, private and link-local addresses, which also covers DNS
+// names and redirects that resolve to them. Proxies are not used, as they would dial for it.
+func newClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: DeliveryTimeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+				ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout:   DeliveryTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: DeliveryTimeout},
+	}
+}
+
+// newSecret returns a random signing secret
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func subscriberName(id int64) string {
+	return fmt.Sprintf("token-webhook:%d", id)
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
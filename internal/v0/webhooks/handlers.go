@@ -0,0 +1,311 @@
+package webhooks
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/pagination"
+	"API/internal/v0/common"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the webhooks of the calling token
+type Handler struct {
+	repo       *Repository
+	dispatcher *Dispatcher
+}
+
+func NewHandler(repo *Repository, dispatcher *Dispatcher) *Handler {
+	return &Handler{repo: repo, dispatcher: dispatcher}
+}
+
+// callingToken returns the token of the request. It renders an error and returns nil when
+// there is none.
+func callingToken(c *gin.Context) *auth.Token {
+	token := auth.GetTokenFromContext(c)
+	if token == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.InvalidToken, "a token is required")))
+	}
+	return token
+}
+
+// subscription loads the webhook of the :id parameter. Webhooks of other tokens are not
+// found. It renders an error and returns nil when there is no such webhook.
+func (h *Handler) subscription(c *gin.Context, token *auth.Token) *Subscription {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidID, "invalid webhook ID")))
+		return nil
+	}
+	s, err := h.repo.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get webhook")))
+		return nil
+	}
+	if s == nil || s.TokenID != token.ID {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "webhook not found")))
+		return nil
+	}
+	return s
+}
+
+// validate checks a webhook's URL and that its token may receive each of its events. It
+// renders the problems found and returns false when there are any.
+func (h *Handler) validate(c *gin.Context, s *Subscription) bool {
+	var errs []apierror.Error
+	if u, err := url.Parse(s.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		errs = append(errs, apierror.Invalid("url", "url must be an http or https address"))
+	}
+	for _, t := range s.Events {
+		var known *EventType
+		for i := range EventTypes {
+			if EventTypes[i].Type == t {
+				known = &EventTypes[i]
+			}
+		}
+		if known == nil {
+			errs = append(errs, apierror.Invalid("events", fmt.Sprintf("unsupported event '%s'", t)))
+			continue
+		}
+		ok, err := h.dispatcher.tokens.TokenHasFeature(c.Request.Context(), s.TokenID, known.Feature)
+		if err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to check the token's features")))
+			return false
+		}
+		if !ok {
+			errs = append(errs, apierror.Invalid("events", fmt.Sprintf("the token needs the '%s' feature for '%s'", known.Feature, t)))
+		}
+	}
+	if len(errs) > 0 {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(errs...))
+		return false
+	}
+	return true
+}
+
+// ListWebhooks returns the calling token's webhooks and the events they can subscribe to
+// GET /webhooks
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	token := callingToken(c)
+	if token == nil {
+		return
+	}
+	subscriptions, err := h.repo.ListTokenSubscriptions(c.Request.Context(), token.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list webhooks")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{
+		"webhooks": subscriptions,
+		"events":   EventTypes,
+	}))
+}
+
+// PostWebhook registers a webhook for the calling token. The signing secret is only
+// returned here.
+// POST /webhooks
+func (h *Handler) PostWebhook(c *gin.Context) {
+	token := callingToken(c)
+	if token == nil {
+		return
+	}
+	var req SubscriptionCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	s := Subscription{
+		TokenID:     token.ID,
+		UserID:      token.UserID,
+		URL:         strings.TrimSpace(req.URL),
+		Events:      req.Events,
+		Secret:      req.Secret,
+		Description: req.Description,
+	}
+	if !h.validate(c, &s) {
+		return
+	}
+	if s.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to generate secret")))
+			return
+		}
+		s.Secret = secret
+	}
+
+	id, err := h.repo.CreateSubscription(c.Request.Context(), s, MaxSubscriptionsPerToken)
+	if errors.Is(err, ErrTooManySubscriptions) {
+		common.JSON(c, http.StatusConflict, common.CreateErrorResponse(apierror.New(apierror.Conflict, err.Error())))
+		return
+	}
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to create webhook")))
+		return
+	}
+	h.dispatcher.subscribe(id)
+
+	created, err := h.repo.GetSubscription(c.Request.Context(), id)
+	if err != nil || created == nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to get webhook")))
+		return
+	}
+	common.JSON(c, http.StatusCreated, common.CreateSuccessResponse(gin.H{
+		"webhook": created,
+		"secret":  created.Secret,
+	}))
+}
+
+// GetWebhook returns one of the calling token's webhooks
+// GET /webhooks/:id
+func (h *Handler) GetWebhook(c *gin.Context) {
+	token := callingToken(c)
+	if token == nil {
+		return
+	}
+	s := h.subscription(c, token)
+	if s == nil {
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"webhook": s}))
+}
+
+// PatchWebhook changes one of the calling token's webhooks; active=false pauses it, and
+// events queued meanwhile are dropped
+// PATCH /webhooks/:id
+func (h *Handler) PatchWebhook(c *gin.Context) {
+	token := callingToken(c)
+	if token == nil {
+		return
+	}
+	s := h.subscription(c, token)
+	if s == nil {
+		return
+	}
+	var req SubscriptionUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.FromBinding(err)...))
+		return
+	}
+
+	if req.URL != nil {
+		trimmed := strings.TrimSpace(*req.URL)
+		req.URL = &trimmed
+		s.URL = trimmed
+	}
+	if req.Events != nil {
+		s.Events = req.Events
+	}
+	if !h.validate(c, s) {
+		return
+	}
+
+	updated, err := h.repo.UpdateSubscription(c.Request.Context(), s.ID, req)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to update webhook")))
+		return
+	}
+	if !updated {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "webhook not found")))
+		return
+	}
+	s, _ = h.repo.GetSubscription(c.Request.Context(), s.ID)
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"webhook": s}))
+}
+
+// DeleteWebhook deletes one of the calling token's webhooks with its delivery log
+// DELETE /webhooks/:id
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	token := callingToken(c)
+	if token == nil {
+		return
+	}
+	s := h.subscription(c, token)
+	if s == nil {
+		return
+	}
+	if err := h.dispatcher.unsubscribe(s.ID); err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete webhook")))
+		return
+	}
+	deleted, err := h.repo.DeleteSubscription(c.Request.Context(), s.ID)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to delete webhook")))
+		return
+	}
+	if !deleted {
+		common.JSON(c, http.StatusNotFound, common.CreateErrorResponse(apierror.New(apierror.NotFound, "webhook not found")))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "webhook deleted"}))
+}
+
+// TestWebhook sends a signed ping event to one of the calling token's webhooks right away
+// POST /webhooks/:id/test
+func (h *Handler) TestWebhook(c *gin.Context) {
+	token := callingToken(c)
+	if token == nil {
+		return
+	}
+	s := h.subscription(c, token)
+	if s == nil {
+		return
+	}
+	if err := h.dispatcher.Ping(c.Request.Context(), s); err != nil {
+		common.JSON(c, http.StatusBadGateway, common.CreateErrorResponse(apierror.New(apierror.UpstreamFailed, err.Error())))
+		return
+	}
+	common.JSON(c, http.StatusOK, common.CreateSuccessResponse(gin.H{"message": "webhook accepted the test event"}))
+}
+
+// ListDeliveries returns the deliveries of one of the calling token's webhooks, newest
+// first, with their status, attempts and last error. Failed deliveries are retried with
+// backoff up to events.OutboxMaxAttempts times.
+// GET /webhooks/:id/deliveries?limit=&cursor=
+func (h *Handler) ListDeliveries(c *gin.Context) {
+	token := callingToken(c)
+	if token == nil {
+		return
+	}
+	s := h.subscription(c, token)
+	if s == nil {
+		return
+	}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+	deliveries, err := h.repo.ListDeliveries(c.Request.Context(), s.ID, page)
+	if err != nil {
+		common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to list deliveries")))
+		return
+	}
+	deliveries, next := pagination.Next(deliveries, page, func(d Delivery) int64 { return d.ID })
+	common.JSON(c, http.StatusOK, common.CreatePaginatedResponse(gin.H{
+		"deliveries": deliveries,
+		"limit":      page.Limit,
+	}, next))
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
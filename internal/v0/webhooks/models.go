@@ -0,0 +1,83 @@
+package webhooks
+
+import (
+	"API/internal/events"
+	"encoding/json"
+	"time"
+)
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Subscription is a webhook a token holder registered for some events. It belongs to
+// the token that created it and only receives the events that token is entitled to.
+type Subscription struct {
+	ID          int64         `json:"id"`
+	TokenID     int64         `json:"token_id"`
+	UserID      int64         `json:"-"`
+	URL         string        `json:"url"`
+	Events      []events.Type `json:"events"`
+	Secret      string        `json:"-"` // Only returned on creation
+	Description *string       `json:"description,omitempty"`
+	Active      bool          `json:"active"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// SubscriptionCreateRequest is the body of POST /webhooks. A signing secret is generated
+// when none is given.
+type SubscriptionCreateRequest struct {
+	URL         string        `json:"url" binding:"required,url,max=2000"`
+	Events      []events.Type `json:"events" binding:"required,min=1"`
+	Secret      string        `json:"secret" binding:"omitempty,min=16,max=256"`
+	Description *string       `json:"description" binding:"omitempty,max=500"`
+}
+
+// SubscriptionUpdateRequest is the body of PATCH /webhooks/:id; missing fields are kept
+type SubscriptionUpdateRequest struct {
+	URL         *string       `json:"url" binding:"omitempty,url,max=2000"`
+	Events      []events.Type `json:"events" binding:"omitempty,min=1"`
+	Description *string       `json:"description" binding:"omitempty,max=500"`
+	Active      *bool         `json:"active"`
+}
+
+// Delivery is an event sent, or still to be sent, to a subscription
+type Delivery struct {
+	ID            int64           `json:"id"`
+	Event         events.Type     `json:"event"`
+	Data          json.RawMessage `json:"data"`
+	Status        string          `json:"status"` // pending, delivered or failed
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"` // while pending
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	FailedAt      *time.Time      `json:"failed_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// EventType is an event token holders can subscribe to and the feature their token
+// needs to receive it
+type EventType struct {
+	Type        events.Type `json:"type"`
+	Feature     string      `json:"feature"`
+	Description string      `json:"description"`
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
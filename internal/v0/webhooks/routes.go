@@ -0,0 +1,43 @@
+package webhooks
+
+import (
+	"API/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSlug is the feature tokens need to register webhooks. Each event also needs the
+// feature of the data it carries (see EventTypes).
+const FeatureSlug = "webhooks"
+
+var Features = []auth.FeatureDefinition{
+	{Slug: FeatureSlug, Name: "Webhooks API", Description: "Signed HTTP callbacks for the menu, announcement and quota events the token is entitled to"},
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, h *Handler, authMiddleware *auth.Middleware) {
+	webhooks := rg.Group("/webhooks")
+	{
+		webhooks.GET("", authMiddleware.RequireToken(FeatureSlug), h.ListWebhooks)
+		webhooks.POST("", authMiddleware.RequireToken(FeatureSlug), h.PostWebhook)
+		webhooks.GET("/:id", authMiddleware.RequireToken(FeatureSlug), h.GetWebhook)
+		webhooks.PATCH("/:id", authMiddleware.RequireToken(FeatureSlug), h.PatchWebhook)
+		webhooks.DELETE("/:id", authMiddleware.RequireToken(FeatureSlug), h.DeleteWebhook)
+		webhooks.POST("/:id/test", authMiddleware.RequireToken(FeatureSlug), h.TestWebhook)
+		webhooks.GET("/:id/deliveries", authMiddleware.RequireToken(FeatureSlug), h.ListDeliveries)
+	}
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
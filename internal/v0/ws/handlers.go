@@ -0,0 +1,277 @@
+package ws
+
+import (
+	"API/internal/apierror"
+	"API/internal/auth"
+	"API/internal/negotiate"
+	"API/internal/realtime"
+	"API/internal/v0/common"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// MaxConnectionsPerToken caps the connections a token may hold open at once; a
+	// connection is charged to the quota once, at the handshake
+	MaxConnectionsPerToken = 5
+
+	// MaxMessageBytes caps a client message; a larger one closes the connection
+	MaxMessageBytes = 4 << 10
+
+	// MaxMessagesPerMinute caps the messages a client sends per connection; going over
+	// closes the connection
+	MaxMessagesPerMinute = 60
+
+	// PingInterval is how often an idle connection gets a ping message, so that proxies
+	// do not close it
+	PingInterval = 30 * time.Second
+
+	// WriteTimeout drops clients that stop reading
+	WriteTimeout = 10 * time.Second
+)
+
+// Tokens tells whether the calling token may subscribe to a channel
+type Tokens interface {
+	TokenHasFeature(ctx context.Context, tokenID int64, featureSlug string) (bool, error)
+}
+
+// Handler serves the topics of a realtime.Hub as WebSocket channels
+type Handler struct {
+	hub    *realtime.Hub
+	tokens Tokens
+	open   *realtime.ConnectionLimit
+}
+
+func NewHandler(hub *realtime.Hub, tokens Tokens) *Handler {
+	return &Handler{hub: hub, tokens: tokens, open: realtime.NewConnectionLimit(MaxConnectionsPerToken)}
+}
+
+// Connect upgrades to a WebSocket whose clients subscribe to channels (announcements,
+// schedule, library.occupancy) and get their whole state as JSON, in the language of
+// ?lang= or Accept-Language, on subscribing and whenever it changes. The handshake is
+// authenticated like any request, with the token in the Authorization header.
+// GET /ws?lang=
+func (h *Handler) Connect(c *gin.Context) {
+	token := auth.GetTokenFromContext(c)
+	if token == nil {
+		common.JSON(c, http.StatusUnauthorized, common.CreateErrorResponse(apierror.New(apierror.InvalidToken, "a token is required")))
+		return
+	}
+	if !c.IsWebsocket() {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, "expected a WebSocket upgrade")))
+		return
+	}
+	lang, err := negotiate.Language(c, h.hub.Languages())
+	if err != nil {
+		common.JSON(c, http.StatusBadRequest, common.CreateErrorResponse(apierror.New(apierror.InvalidRequest, err.Error())))
+		return
+	}
+
+	// Entitlements are checked once, at the handshake, like the token itself
+	channels := make(map[string]bool)
+	var allowed []string
+	for _, topic := range h.hub.Topics() {
+		ok, err := h.tokens.TokenHasFeature(c.Request.Context(), token.ID, topic.Feature)
+		if err != nil {
+			common.JSON(c, http.StatusInternalServerError, common.CreateErrorResponse(apierror.New(apierror.Internal, "failed to check the token's features")))
+			return
+		}
+		channels[topic.Name] = ok
+		if ok {
+			allowed = append(allowed, topic.Name)
+		}
+	}
+	if len(allowed) == 0 {
+		common.JSON(c, http.StatusForbidden, common.CreateErrorResponse(apierror.New(apierror.FeatureNotAllowed, "the token may not subscribe to any channel")))
+		return
+	}
+
+	if !h.open.Acquire(token.ID) {
+		common.JSON(c, http.StatusTooManyRequests, common.CreateErrorResponse(apierror.New(apierror.RateLimited,
+			fmt.Sprintf("the token already has %d connections open", MaxConnectionsPerToken))))
+		return
+	}
+	defer h.open.Release(token.ID)
+
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		s := &session{hub: h.hub, conn: conn, channels: channels, following: make(map[string]bool)}
+		s.serve(lang, allowed)
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// received is a message read from the client, or why reading stopped
+type received struct {
+	data []byte
+	err  error
+}
+
+// session is one connection. Everything is sent from serve's goroutine; another one
+// only reads.
+type session struct {
+	hub       *realtime.Hub
+	conn      *websocket.Conn
+	sub       *realtime.Subscriber
+	channels  map[string]bool // every channel, and whether the token may subscribe to it
+	following map[string]bool
+
+	windowStart time.Time
+	messages    int
+}
+
+func (s *session) serve(lang string, allowed []string) {
+	defer s.conn.Close()
+	s.conn.MaxPayloadBytes = MaxMessageBytes
+
+	s.sub = s.hub.Subscribe(lang, nil)
+	defer s.hub.Unsubscribe(s.sub)
+
+	done := make(chan struct{})
+	defer close(done)
+	incoming := make(chan received)
+	go func() {
+		for {
+			var r received
+			r.err = websocket.Message.Receive(s.conn, &r.data)
+			select {
+			case incoming <- r:
+			case <-done:
+				return
+			}
+			if r.err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(PingInterval)
+	defer ping.Stop()
+
+	err := s.send(gin.H{"type": TypeWelcome, "channels": allowed, "limits": Limits{
+		MaxMessageBytes:        MaxMessageBytes,
+		MaxMessagesPerMinute:   MaxMessagesPerMinute,
+		MaxConnectionsPerToken: MaxConnectionsPerToken,
+		PingIntervalSeconds:    int(PingInterval / time.Second),
+	}})
+	for err == nil {
+		select {
+		case msg, ok := <-s.sub.C:
+			if !ok {
+				// Too far behind, or the server is shutting down; the client reconnects
+				return
+			}
+			if s.following[msg.Topic] { // Not a state queued before unsubscribing
+				err = s.send(Update{Type: TypeUpdate, Channel: msg.Topic, ID: msg.ID, Data: msg.Data})
+			}
+		case r := <-incoming:
+			if errors.Is(r.err, websocket.ErrFrameTooLarge) {
+				s.sendError(apierror.Newf(apierror.PayloadTooLarge, "messages may be at most %d bytes", MaxMessageBytes))
+				return
+			}
+			if r.err != nil {
+				return
+			}
+			if !s.allow(time.Now()) {
+				s.sendError(apierror.Newf(apierror.RateLimited, "at most %d messages may be sent per minute", MaxMessagesPerMinute))
+				return
+			}
+			err = s.handle(r.data)
+		case <-ping.C:
+			err = s.send(gin.H{"type": TypePing})
+		}
+	}
+}
+
+// allow counts a client message, returning false when the connection is over
+// MaxMessagesPerMinute
+func (s *session) allow(now time.Time) bool {
+	if now.Sub(s.windowStart) >= time.Minute {
+		s.windowStart, s.messages = now, 0
+	}
+	s.messages++
+	return s.messages <= MaxMessagesPerMinute
+}
+
+// handle answers a client message. Invalid messages are answered with an error message
+// and leave the connection open.
+func (s *session) handle(data []byte) error {
+	var msg ClientMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return s.sendError(apierror.New(apierror.InvalidRequest, "messages must be JSON objects"))
+	}
+
+	switch msg.Type {
+	case TypePing:
+		return s.send(gin.H{"type": TypePong})
+	case TypeSubscribe, TypeUnsubscribe:
+		if len(msg.Channels) == 0 {
+			return s.sendError(apierror.Invalid("channels", "at least one channel is required"))
+		}
+		for _, name := range msg.Channels {
+			allowed, known := s.channels[name]
+			if !known {
+				return s.sendError(apierror.Invalid("channels", fmt.Sprintf("unknown channel '%s'", name)))
+			}
+			if !allowed && msg.Type == TypeSubscribe {
+				return s.sendError(apierror.New(apierror.FeatureNotAllowed, fmt.Sprintf("the token may not subscribe to %s", name)))
+			}
+		}
+		if msg.Type == TypeUnsubscribe {
+			s.hub.Unfollow(s.sub, msg.Channels)
+			for _, name := range msg.Channels {
+				delete(s.following, name)
+			}
+			return s.send(gin.H{"type": TypeUnsubscribed, "channels": s.followed()})
+		}
+		s.hub.Follow(s.sub, msg.Channels)
+		for _, name := range msg.Channels {
+			s.following[name] = true
+		}
+		// The states of the new channels follow the acknowledgement
+		return s.send(gin.H{"type": TypeSubscribed, "channels": s.followed()})
+	default:
+		return s.sendError(apierror.Invalid("type", fmt.Sprintf("unknown message type '%s'", msg.Type)))
+	}
+}
+
+// followed returns the channels the connection is subscribed to, by name
+func (s *session) followed() []string {
+	names := make([]string, 0, len(s.following))
+	for name := range s.following {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *session) send(v interface{}) error {
+	s.conn.SetWriteDeadline(time.Now().Add(WriteTimeout))
+	return websocket.JSON.Send(s.conn, v)
+}
+
+func (s *session) sendError(err apierror.Error) error {
+	return s.send(gin.H{"type": TypeError, "error": err})
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.
@@ -0,0 +1,58 @@
+package ws
+
+import "encoding/json"
+
+// Types of the messages clients send
+const (
+	TypeSubscribe   = "subscribe"
+	TypeUnsubscribe = "unsubscribe"
+	TypePing        = "ping"
+)
+
+// Types of the messages the server sends, besides ping
+const (
+	TypeWelcome      = "welcome"
+	TypeSubscribed   = "subscribed"
+	TypeUnsubscribed = "unsubscribed"
+	TypeUpdate       = "update"
+	TypePong         = "pong"
+	TypeError        = "error"
+)
+
+// ClientMessage is a JSON text message from the client: subscribe or unsubscribe with the
+// channels to add or drop, or ping
+type ClientMessage struct {
+	Type     string   `json:"type"`
+	Channels []string `json:"channels"`
+}
+
+// Update is the state of a channel, sent after subscribing and whenever it changes
+type Update struct {
+	Type    string          `json:"type"`
+	Channel string          `json:"channel"`
+	ID      uint64          `json:"id"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Limits are the per-connection limits, sent in the welcome message
+type Limits struct {
+	MaxMessageBytes        int `json:"max_message_bytes"`
+	MaxMessagesPerMinute   int `json:"max_messages_per_minute"`
+	MaxConnectionsPerToken int `json:"max_connections_per_token"`
+	PingIntervalSeconds    int `json:"ping_interval_seconds"`
+}
+
+//   This project is the monolithic backend API for the OpenSourceDUTH team. Access to open data compiled and provided by the OpenSourceDUTH University Team.
+//   API Copyright (C) 2025 OpenSourceDUTH
+//       This program is free software: you can redistribute it and/or modify
+//       it under the terms of the GNU General Public License as published by
+//       the Free Software Foundation, either version 3 of the License, or
+//       (at your option) any later version.
+
+//       This program is distributed in the hope that it will be useful,
+//       but WITHOUT ANY WARRANTY; without even the implied warranty of
+//       MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//       GNU General Public License for more details.
+
+//       You should have received a copy of the GNU General Public License
+//       along with this program.  If not, see <https://www.gnu.org/licenses/>.